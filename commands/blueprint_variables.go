@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/deploy-cli-sdk/config"
+	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/shared"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// resolveMissingVariables checks the blueprint for required variables that
+// have not been provided in the operation config before changes are staged.
+//
+// In headless mode, a *shared.MissingVariablesError listing all the missing
+// variables is returned. In interactive mode, the user is prompted for the
+// missing values which are added to the operation config and optionally
+// saved to the deploy config file. The names of the variables that were
+// entered but not saved to the deploy config file are returned, ordered by name.
+func resolveMissingVariables(
+	cmd *cobra.Command,
+	blueprintFile string,
+	operationConfig *types.BlueprintOperationConfig,
+	confProvider *config.Provider,
+	styles *stylespkg.Styles,
+	headlessMode bool,
+	logger *zap.Logger,
+) (*types.BlueprintOperationConfig, []string, error) {
+	missing, err := shared.FindMissingVariables(blueprintFile, operationConfig)
+	if err != nil {
+		// The deploy engine reports a more useful diagnostic for blueprints
+		// that can not be loaded, so this is not treated as a failure.
+		logger.Debug("skipping check for missing blueprint variables", zap.Error(err))
+		return operationConfig, nil, nil
+	}
+	if len(missing) == 0 {
+		return operationConfig, nil, nil
+	}
+
+	if headlessMode {
+		return nil, nil, &shared.MissingVariablesError{Variables: missing}
+	}
+
+	deployConfigFile, _ := confProvider.GetString("deployConfigFile")
+	form := shared.NewVariablesForm(styles, missing, deployConfigFile)
+	if err := form.Form.RunWithContext(cmd.Context()); err != nil {
+		return nil, nil, err
+	}
+
+	values, err := form.Values()
+	if err != nil {
+		return nil, nil, err
+	}
+	operationConfig = withBlueprintVariables(operationConfig, values)
+
+	savable, err := form.SavableValues()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(savable) > 0 {
+		if err := config.SaveBlueprintVariables(deployConfigFile, savable); err != nil {
+			return nil, nil, err
+		}
+		fmt.Fprintf(os.Stdout, "Saved blueprint variables to %s\n", deployConfigFile)
+	}
+
+	unsaved := []string{}
+	for _, variable := range missing {
+		if _, saved := savable[variable.Name]; !saved {
+			unsaved = append(unsaved, variable.Name)
+		}
+	}
+	return operationConfig, unsaved, nil
+}
+
+// unsavedPlanVariablesError is returned when a plan file is requested for
+// a staging run where blueprint variables were entered at the prompt without
+// being saved, deploying the plan would not have the values of these variables
+// and the plan could not be verified against the deploy config.
+func unsavedPlanVariablesError(unsaved []string) error {
+	return fmt.Errorf(
+		"a plan file can not be written as the following blueprint variables were entered "+
+			"without being saved to the deploy config file: %s, "+
+			"provide them with --var, --var-file or the blueprintVariables section of the "+
+			"deploy config file when staging and deploying the plan",
+		strings.Join(unsaved, ", "),
+	)
+}
+
+func withBlueprintVariables(
+	operationConfig *types.BlueprintOperationConfig,
+	values map[string]*core.ScalarValue,
+) *types.BlueprintOperationConfig {
+	if operationConfig == nil {
+		operationConfig = &types.BlueprintOperationConfig{}
+	}
+	if operationConfig.BlueprintVariables == nil {
+		operationConfig.BlueprintVariables = map[string]*core.ScalarValue{}
+	}
+	for name, value := range values {
+		operationConfig.BlueprintVariables[name] = value
+	}
+	return operationConfig
+}
@@ -0,0 +1,193 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/newstack-cloud/deploy-cli-sdk/config"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
+	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/stageui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var errShowChangesetFailed = errors.New("show change set failed")
+
+// SetupChangesetsCommand registers a changesets command with a show
+// subcommand on the root command, parameterized by CLIConfig for branding.
+func SetupChangesetsCommand(rootCmd *cobra.Command, confProvider *config.Provider, cfg *CLIConfig) {
+	changesetsCmd := &cobra.Command{
+		Use:   "changesets",
+		Short: "View staged change sets",
+		Long: `Commands for viewing change sets that have been staged with the stage command
+before they are applied with the deploy or destroy commands.`,
+	}
+
+	setupChangesetsShowCommand(changesetsCmd, confProvider, cfg)
+
+	registerRecordSessionFlag(rootCmd, confProvider, cfg)
+	rootCmd.AddCommand(changesetsCmd)
+}
+
+func setupChangesetsShowCommand(changesetsCmd *cobra.Command, confProvider *config.Provider, cfg *CLIConfig) {
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show a previously staged change set",
+		Long: fmt.Sprintf(`Displays the changes of a change set that has already been staged,
+without staging the changes again.
+
+This allows a change set staged in a CI/CD pipeline to be reviewed
+before it is applied with the deploy command.
+
+Examples:
+  # Review a change set in the interactive view
+  %[1]s changesets show --change-set-id abc123
+
+  # Print the change set (useful for CI/CD logs)
+  %[1]s changesets show --change-set-id abc123 > changes.txt
+
+  # Output as JSON, in the same shape as stage --json
+  %[1]s changesets show --change-set-id abc123 --json
+
+  # Apply the change set once it has been reviewed
+  %[1]s deploy --instance-name my-app --change-set-id abc123`, cfg.CLIName),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runShowChangeset(cmd, confProvider, cfg)
+		},
+	}
+
+	prefix := cfg.EnvVarPrefix
+
+	showCmd.PersistentFlags().String(
+		flagChangeSetID, "",
+		"The ID of the change set to show.",
+	)
+	confProvider.BindPFlag("changesetsShowChangeSetID", showCmd.PersistentFlags().Lookup(flagChangeSetID))
+	confProvider.BindEnvVar("changesetsShowChangeSetID", prefix+"_CHANGESETS_SHOW_CHANGE_SET_ID")
+
+	showCmd.PersistentFlags().String(
+		flagInstanceName, "",
+		"The user-defined unique name of the blueprint instance the change set is for. "+
+			"This is optional and is only used in suggested commands and output.",
+	)
+	confProvider.BindPFlag("changesetsShowInstanceName", showCmd.PersistentFlags().Lookup(flagInstanceName))
+	confProvider.BindEnvVar("changesetsShowInstanceName", prefix+"_CHANGESETS_SHOW_INSTANCE_NAME")
+
+	showCmd.PersistentFlags().Bool("json", false,
+		"Output the change set as JSON. "+
+			"Implies non-interactive mode (no TUI).",
+	)
+	confProvider.BindPFlag("changesetsShowJson", showCmd.PersistentFlags().Lookup("json"))
+	confProvider.BindEnvVar("changesetsShowJson", prefix+"_CHANGESETS_SHOW_JSON")
+
+	changesetsCmd.AddCommand(showCmd)
+}
+
+type showChangesetFlags struct {
+	changesetID          string
+	changesetIDIsDefault bool
+	instanceName         string
+	jsonMode             bool
+}
+
+func readShowChangesetFlags(confProvider *config.Provider) (showChangesetFlags, error) {
+	values := newConfigValues(confProvider)
+	changesetID, changesetIDIsDefault := confProvider.GetString("changesetsShowChangeSetID")
+	instanceName, _ := confProvider.GetString("changesetsShowInstanceName")
+	jsonMode := values.bool("changesetsShowJson")
+
+	return showChangesetFlags{
+		changesetID:          changesetID,
+		changesetIDIsDefault: changesetIDIsDefault,
+		instanceName:         instanceName,
+		jsonMode:             jsonMode,
+	}, values.err
+}
+
+// validateShowChangesetFlags makes sure a change set ID has been provided,
+// unlike other commands, there is no interactive fallback for selecting a change set.
+func validateShowChangesetFlags(flags showChangesetFlags) error {
+	if flags.changesetIDIsDefault || flags.changesetID == "" {
+		return fmt.Errorf("required flag --%s must be provided", flagChangeSetID)
+	}
+	return nil
+}
+
+func runShowChangeset(cmd *cobra.Command, confProvider *config.Provider, cfg *CLIConfig) error {
+	logger, handle, err := SetupLogger(cfg.CLIName)
+	if err != nil {
+		return err
+	}
+	defer handle.Close()
+
+	deployEngine, err := engine.Create(confProvider, logger)
+	if err != nil {
+		return err
+	}
+
+	flags, err := readShowChangesetFlags(confProvider)
+	if err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+
+	if flags.jsonMode {
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+	}
+
+	if err := validateShowChangesetFlags(flags); err != nil {
+		if flags.jsonMode {
+			jsonout.WriteJSON(os.Stdout, jsonout.NewErrorOutput(err))
+			return errShowChangesetFailed
+		}
+		return err
+	}
+
+	if _, err := tea.LogToFile(fmt.Sprintf("%s-output.log", cfg.CLIName), "simple"); err != nil {
+		log.Fatal(err)
+	}
+
+	cmd.SilenceUsage = true
+
+	styles := stylespkg.NewStyles(
+		lipgloss.NewRenderer(os.Stdout),
+		cfg.Palette,
+	)
+	inTerminal := term.IsTerminal(int(os.Stdout.Fd()))
+	headlessMode := !inTerminal || flags.jsonMode
+
+	app, err := stageui.NewShowChangesetApp(stageui.ShowChangesetAppConfig{
+		Context:        cmd.Context(),
+		DeployEngine:   deployEngine,
+		Logger:         logger,
+		ChangesetID:    flags.changesetID,
+		InstanceName:   flags.instanceName,
+		Styles:         styles,
+		Headless:       headlessMode,
+		HeadlessWriter: os.Stdout,
+		JSONMode:       flags.jsonMode,
+	})
+	if err != nil {
+		return err
+	}
+
+	finalModel, err := tea.NewProgram(app, newTUIProgramOptions(cmd.Context(), headlessMode)...).Run()
+	if err != nil {
+		return err
+	}
+	finalApp := finalModel.(stageui.ShowChangesetModel)
+
+	if finalApp.Error != nil {
+		cmd.SilenceErrors = true
+		return errShowChangesetFailed
+	}
+
+	return nil
+}
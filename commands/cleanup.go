@@ -25,18 +25,19 @@ type cleanupFlags struct {
 	events                bool
 }
 
-func readCleanupFlags(confProvider *config.Provider) cleanupFlags {
-	validations, _ := confProvider.GetBool("cleanupValidations")
-	changesets, _ := confProvider.GetBool("cleanupChangesets")
-	reconciliationResults, _ := confProvider.GetBool("cleanupReconciliationResults")
-	events, _ := confProvider.GetBool("cleanupEvents")
+func readCleanupFlags(confProvider *config.Provider) (cleanupFlags, error) {
+	values := newConfigValues(confProvider)
+	validations := values.bool("cleanupValidations")
+	changesets := values.bool("cleanupChangesets")
+	reconciliationResults := values.bool("cleanupReconciliationResults")
+	events := values.bool("cleanupEvents")
 
 	return cleanupFlags{
 		validations:           validations,
 		changesets:            changesets,
 		reconciliationResults: reconciliationResults,
 		events:                events,
-	}
+	}, values.err
 }
 
 func (f cleanupFlags) noFlagsProvided() bool {
@@ -91,7 +92,12 @@ Examples:
 			inTerminal := term.IsTerminal(int(os.Stdout.Fd()))
 			headlessMode := !inTerminal
 
-			flags := readCleanupFlags(confProvider).resolveForHeadless(headlessMode)
+			flags, err := readCleanupFlags(confProvider)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+			flags = flags.resolveForHeadless(headlessMode)
 
 			if _, err := tea.LogToFile(fmt.Sprintf("%s-output.log", cfg.CLIName), "simple"); err != nil {
 				log.Fatal(err)
@@ -164,5 +170,6 @@ Examples:
 	confProvider.BindPFlag("cleanupEvents", cleanupCmd.PersistentFlags().Lookup("events"))
 	confProvider.BindEnvVar("cleanupEvents", prefix+"_CLEANUP_EVENTS")
 
+	registerRecordSessionFlag(rootCmd, confProvider, cfg)
 	rootCmd.AddCommand(cleanupCmd)
 }
@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/newstack-cloud/deploy-cli-sdk/completion"
+	"github.com/newstack-cloud/deploy-cli-sdk/config"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// registerFlagCompletions registers dynamic shell completion for the
+// instance, change set and blueprint file flags that are defined on the
+// provided command.
+// This must be called after the flags have been defined.
+func registerFlagCompletions(cmd *cobra.Command, confProvider *config.Provider, cfg *CLIConfig) {
+	if cmd.Flag(flagInstanceName) != nil {
+		cmd.RegisterFlagCompletionFunc(
+			flagInstanceName,
+			instanceCompletionFunc(confProvider, cfg, completion.InstanceFieldName),
+		)
+	}
+
+	if cmd.Flag(flagInstanceID) != nil {
+		cmd.RegisterFlagCompletionFunc(
+			flagInstanceID,
+			instanceCompletionFunc(confProvider, cfg, completion.InstanceFieldID),
+		)
+	}
+
+	if cmd.Flag(flagChangeSetID) != nil {
+		// The deploy engine does not provide a way to list change sets,
+		// so only file completion is disabled for change set IDs.
+		cmd.RegisterFlagCompletionFunc(flagChangeSetID, cobra.NoFileCompletions)
+	}
+
+	if cmd.Flag("blueprint-file") != nil {
+		patterns := completion.BlueprintFilePatterns(cfg.DefaultBlueprintFile)
+		cmd.RegisterFlagCompletionFunc(
+			"blueprint-file",
+			func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+				return completion.CompleteBlueprintFile(toComplete, patterns)
+			},
+		)
+	}
+}
+
+func instanceCompletionFunc(
+	confProvider *config.Provider,
+	cfg *CLIConfig,
+	field completion.InstanceField,
+) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		// Cobra does not run the root command's hooks for completion requests,
+		// config files must be loaded before the engine settings are read.
+		if err := confProvider.LoadDiscoveredConfigFiles(); err != nil {
+			cobra.CompDebugln("failed to load config files for completion: "+err.Error(), false)
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		completer := completion.NewInstanceCompleter(completion.InstanceCompleterConfig{
+			CreateEngine: func() (engine.DeployEngine, error) {
+				return engine.Create(confProvider, zap.NewNop())
+			},
+			Cache:    createCompletionCache(cfg),
+			CacheKey: completionCacheKey(confProvider),
+		})
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		return completer.Complete(ctx, field, toComplete)
+	}
+}
+
+// createCompletionCache creates the cache for instances fetched for
+// completion, completion will query the deploy engine on every request
+// when the user's cache directory can not be determined.
+func createCompletionCache(cfg *CLIConfig) *completion.Cache {
+	cachePath, err := completion.DefaultCachePath(cfg.CLIName)
+	if err != nil {
+		return nil
+	}
+	return completion.NewCache(cachePath, completion.DefaultCacheTTL)
+}
+
+func completionCacheKey(confProvider *config.Provider) string {
+	connectProtocol, _ := confProvider.GetString("connectProtocol")
+	engineEndpoint, _ := confProvider.GetString("engineEndpoint")
+	return connectProtocol + "|" + engineEndpoint
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/newstack-cloud/deploy-cli-sdk/config"
 	"github.com/newstack-cloud/deploy-cli-sdk/styles"
 	"github.com/newstack-cloud/deploy-cli-sdk/tui/precommand"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/shared"
 )
 
 // Flag name constants shared across command files to avoid duplicate string literals.
@@ -36,7 +37,8 @@ func newTUIProgramOptions(ctx context.Context, headlessMode bool) []tea.ProgramO
 // configured and the user has not opted out via --skip-plugin-check. The
 // context is passed to the factory so preflight operations (e.g. plugin
 // dependency resolution and installation) can be cancelled when the command
-// context is cancelled.
+// context is cancelled. A *config.TypeError is returned when the
+// skipPluginCheck config value is not a valid boolean.
 func createPreflight(
 	ctx context.Context,
 	cfg *CLIConfig,
@@ -45,17 +47,17 @@ func createPreflight(
 	s *styles.Styles,
 	headless bool,
 	jsonMode bool,
-) tea.Model {
+) (tea.Model, error) {
 	if cfg.PreflightFactory == nil {
-		return nil
+		return nil, nil
 	}
-	skipCheck, _ := confProvider.GetBool("skipPluginCheck")
-	if skipCheck {
-		return nil
+	skipCheck, _, err := confProvider.GetBoolE("skipPluginCheck")
+	if err != nil || skipCheck {
+		return nil, err
 	}
 	return cfg.PreflightFactory.CreatePreflight(
 		ctx, confProvider, commandName, s, headless, os.Stdout, jsonMode,
-	)
+	), nil
 }
 
 // CLIConfig holds the configuration that differentiates one CLI from another
@@ -116,3 +118,40 @@ type PreflightFactory interface {
 		jsonMode bool,
 	) tea.Model
 }
+
+// CheckOnlyPreflightFactory can be implemented by a PreflightFactory to create
+// preflight check models that report missing plugin dependencies with a
+// preflight.MissingMsg instead of installing them. The doctor command uses
+// it so that diagnosing problems does not change the system, preflight checks
+// are skipped by doctor for factories that do not implement it unless --fix is set.
+type CheckOnlyPreflightFactory interface {
+	CreateCheckOnlyPreflight(
+		ctx context.Context,
+		confProvider *config.Provider,
+		commandName string,
+		styles *styles.Styles,
+		headless bool,
+		writer io.Writer,
+		jsonMode bool,
+	) tea.Model
+}
+
+// loadStreamReconnectPolicy reads how event streams are resumed after the
+// connection to the deploy engine drops from the engineStreamMaxReconnects
+// and engineStreamReconnectMaxWait config values, the defaults of
+// shared.StreamReconnectPolicy are used for values that are not set.
+func loadStreamReconnectPolicy(confProvider *config.Provider) (shared.StreamReconnectPolicy, error) {
+	maxAttempts, _, err := confProvider.GetInt32E("engineStreamMaxReconnects")
+	if err != nil {
+		return shared.StreamReconnectPolicy{}, err
+	}
+	maxWait, _, err := confProvider.GetDuration("engineStreamReconnectMaxWait")
+	if err != nil {
+		return shared.StreamReconnectPolicy{}, err
+	}
+
+	return shared.StreamReconnectPolicy{
+		MaxAttempts: int(maxAttempts),
+		MaxWait:     maxWait,
+	}, nil
+}
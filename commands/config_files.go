@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"github.com/newstack-cloud/deploy-cli-sdk/config"
+	"github.com/spf13/cobra"
+)
+
+// SetupConfigFiles registers a global --config flag on the root command and
+// loads the CLI config files before any command runs, parameterized by
+// CLIConfig for branding.
+//
+// Config files named CLIConfig.DefaultConfigFile are discovered and merged
+// in the following order, where values in later files take precedence:
+//  1. The user config file (e.g. ~/.config/bluelink/bluelink.config.toml)
+//  2. The repository config file, found by walking up from the working
+//     directory to the root of the repository
+//  3. The file provided with --config or the <PREFIX>_CONFIG environment variable
+//
+// Config files are loaded in the PersistentPreRunE hook of the root command,
+// any existing hook is called after the config files have been loaded.
+// Subcommands that define their own PersistentPreRun or PersistentPreRunE
+// hook replace the root hook, as cobra only runs the closest hook.
+// Code paths that run without the hook, such as shell completion, load
+// the config files with the provider's LoadDiscoveredConfigFiles method,
+// config files are only loaded once.
+func SetupConfigFiles(rootCmd *cobra.Command, confProvider *config.Provider, cfg *CLIConfig) {
+	rootCmd.PersistentFlags().String(
+		"config", "",
+		"Path to a CLI config file that takes precedence over the user and repository config files.",
+	)
+	confProvider.BindPFlag("configFile", rootCmd.PersistentFlags().Lookup("config"))
+	confProvider.BindEnvVar("configFile", cfg.EnvVarPrefix+"_CONFIG")
+
+	confProvider.SetConfigFileDiscovery(func() config.DiscoveryOptions {
+		explicitFile, _ := confProvider.GetString("configFile")
+		return config.DiscoveryOptions{
+			FileName:     cfg.DefaultConfigFile,
+			AppName:      cfg.CLIName,
+			ExplicitFile: explicitFile,
+		}
+	})
+
+	existingPreRunE := rootCmd.PersistentPreRunE
+	existingPreRun := rootCmd.PersistentPreRun
+	rootCmd.PersistentPreRun = nil
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := confProvider.LoadDiscoveredConfigFiles(); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		if existingPreRunE != nil {
+			return existingPreRunE(cmd, args)
+		}
+		if existingPreRun != nil {
+			existingPreRun(cmd, args)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,170 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/newstack-cloud/deploy-cli-sdk/config"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
+	"github.com/spf13/cobra"
+)
+
+var errConfigShowFailed = errors.New("showing config failed")
+
+// SetupConfigCommand registers a config command with a show subcommand
+// on the root command, parameterized by CLIConfig for branding.
+func SetupConfigCommand(rootCmd *cobra.Command, confProvider *config.Provider, cfg *CLIConfig) {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the CLI configuration",
+		Long:  `Commands for inspecting the configuration used by the CLI.`,
+	}
+
+	setupConfigShowCommand(configCmd, confProvider, cfg)
+
+	rootCmd.AddCommand(configCmd)
+}
+
+func setupConfigShowCommand(configCmd *cobra.Command, confProvider *config.Provider, cfg *CLIConfig) {
+	showCmd := &cobra.Command{
+		Use:   "show [key...]",
+		Short: "Show the effective configuration and where each value comes from",
+		Long: fmt.Sprintf(`Shows the effective value of each configuration key along with the source
+the value was resolved from.
+
+Values are resolved in the following order of precedence:
+  1. Flags
+  2. Environment variables
+  3. Active engine context (engineEndpoint, connectProtocol and engineAuthConfigFile)
+  4. Config files, where the --config file takes precedence over the
+     repository config file and the repository config file takes
+     precedence over the user config file
+  5. Flag defaults
+  6. Defaults
+
+Flags only apply to the command they are passed to, so flags of other
+commands are shown with their default values.
+Values of keys that hold secrets (e.g. API keys and passwords) are redacted.
+
+Examples:
+  # Show all configuration keys
+  %[1]s config show
+
+  # Show specific configuration keys
+  %[1]s config show engineEndpoint deployInstanceName
+
+  # Show the configuration as JSON
+  %[1]s config show --json`, cfg.CLIName),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			jsonMode, _, err := confProvider.GetBoolE("configShowJson")
+			if err != nil {
+				return err
+			}
+
+			keys := args
+			if len(keys) == 0 {
+				keys = confProvider.Keys()
+			}
+			values := resolveConfigValues(confProvider, keys)
+
+			if jsonMode {
+				cmd.SilenceErrors = true
+				jsonout.WriteJSON(os.Stdout, buildConfigShowOutput(confProvider, values))
+				return nil
+			}
+
+			if err := writeConfigValues(os.Stdout, confProvider, values); err != nil {
+				return errors.Join(errConfigShowFailed, err)
+			}
+			return nil
+		},
+	}
+
+	prefix := cfg.EnvVarPrefix
+
+	showCmd.Flags().Bool("json", false,
+		"Output the configuration as a single JSON object.",
+	)
+	confProvider.BindPFlag("configShowJson", showCmd.Flags().Lookup("json"))
+	confProvider.BindEnvVar("configShowJson", prefix+"_CONFIG_SHOW_JSON")
+
+	configCmd.AddCommand(showCmd)
+}
+
+func resolveConfigValues(confProvider *config.Provider, keys []string) []config.ResolvedValue {
+	values := make([]config.ResolvedValue, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, confProvider.Resolve(key).Redacted())
+	}
+	return values
+}
+
+func buildConfigShowOutput(
+	confProvider *config.Provider,
+	values []config.ResolvedValue,
+) jsonout.ConfigShowOutput {
+	output := jsonout.ConfigShowOutput{
+		Success:     true,
+		ConfigFile:  confProvider.ConfigFile(),
+		ConfigFiles: confProvider.ConfigFiles(),
+		Context:     confProvider.ActiveContextName(),
+		Values:      make([]jsonout.ConfigValue, 0, len(values)),
+	}
+	for _, value := range values {
+		output.Values = append(output.Values, jsonout.ConfigValue{
+			Key:        value.Key,
+			Value:      value.Value,
+			Source:     string(value.Source),
+			SourceName: value.SourceName,
+			EnvVar:     confProvider.EnvVarName(value.Key),
+			Redacted:   value.Value == config.RedactedValue,
+		})
+	}
+	return output
+}
+
+func writeConfigValues(
+	w io.Writer,
+	confProvider *config.Provider,
+	values []config.ResolvedValue,
+) error {
+	configFiles := confProvider.ConfigFiles()
+	switch len(configFiles) {
+	case 0:
+		fmt.Fprintln(w, "Config file: (none loaded)")
+	case 1:
+		fmt.Fprintf(w, "Config file: %s\n", configFiles[0])
+	default:
+		// Config files are listed from lowest to highest precedence.
+		fmt.Fprintln(w, "Config files:")
+		for _, configFile := range configFiles {
+			fmt.Fprintf(w, "  %s\n", configFile)
+		}
+	}
+	if contextName := confProvider.ActiveContextName(); contextName != "" {
+		fmt.Fprintf(w, "Context: %s\n", contextName)
+	}
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE")
+	for _, value := range values {
+		displayValue := value.Value
+		if value.Source == config.SourceUnset {
+			displayValue = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", value.Key, displayValue, describeValueSource(value))
+	}
+	return tw.Flush()
+}
+
+func describeValueSource(value config.ResolvedValue) string {
+	if value.SourceName == "" {
+		return string(value.Source)
+	}
+	return fmt.Sprintf("%s (%s)", value.Source, value.SourceName)
+}
@@ -0,0 +1,40 @@
+package commands
+
+import "github.com/newstack-cloud/deploy-cli-sdk/config"
+
+// configValues reads typed config values for a command, keeping the first
+// *config.TypeError so that invalid values (e.g. "yess" for a boolean set
+// with an environment variable) are reported instead of being read as
+// the zero value of the type.
+type configValues struct {
+	confProvider *config.Provider
+	err          error
+}
+
+func newConfigValues(confProvider *config.Provider) *configValues {
+	return &configValues{confProvider: confProvider}
+}
+
+func (v *configValues) bool(configName string) bool {
+	value, _, err := v.confProvider.GetBoolE(configName)
+	v.record(err)
+	return value
+}
+
+// optionalBool returns nil when the boolean config value has not been set
+// by the user, so the TUI can fall back to its own defaults (or prompt
+// for the value in interactive mode).
+func (v *configValues) optionalBool(configName string) *bool {
+	value, isDefault, err := v.confProvider.GetBoolE(configName)
+	v.record(err)
+	if isDefault {
+		return nil
+	}
+	return &value
+}
+
+func (v *configValues) record(err error) {
+	if err != nil && v.err == nil {
+		v.err = err
+	}
+}
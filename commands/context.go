@@ -0,0 +1,217 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/newstack-cloud/deploy-cli-sdk/config"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
+	"github.com/spf13/cobra"
+)
+
+var errNoContextSelected = errors.New("no engine context is selected")
+
+// SetupContextCommand registers a global --context flag and a context
+// command with list, use and current subcommands on the root command,
+// parameterized by CLIConfig for branding.
+//
+// Contexts are named sets of deploy engine connection settings defined in the
+// "contexts" section of the CLI config file, see config.EngineContext.
+func SetupContextCommand(rootCmd *cobra.Command, confProvider *config.Provider, cfg *CLIConfig) {
+	rootCmd.PersistentFlags().String(
+		"context", "",
+		"The engine context from the CLI config file to use for connecting to the deploy engine. "+
+			"Overrides the context selected with the \"context use\" command.",
+	)
+	confProvider.BindPFlag(config.ContextConfigKey, rootCmd.PersistentFlags().Lookup("context"))
+	confProvider.BindEnvVar(config.ContextConfigKey, cfg.EnvVarPrefix+"_CONTEXT")
+	confProvider.SetCurrentContextFile(config.DefaultCurrentContextFile(cfg.CLIName))
+
+	contextCmd := &cobra.Command{
+		Use:   "context",
+		Short: "Manage deploy engine contexts",
+		Long: fmt.Sprintf(`Commands for managing the engine contexts defined in the CLI config file.
+
+A context is a named set of deploy engine connection settings
+(engineEndpoint, connectProtocol and engineAuthConfigFile) defined in the
+"contexts" section of the CLI config file.
+
+The context is selected with, in order of precedence:
+  1. The --context flag or the %[2]s_CONTEXT environment variable
+  2. The "%[1]s context use" command
+  3. The "context" key of the CLI config file
+
+Settings of the selected context can be overridden with flags
+and environment variables.
+
+Example config file (%[3]s):
+  context = "staging"
+
+  [contexts.staging]
+  engineEndpoint = "https://staging.engine.example.com"
+  engineAuthConfigFile = "~/.bluelink/staging.auth.json"
+
+  [contexts.prod]
+  engineEndpoint = "https://engine.example.com"
+  engineAuthConfigFile = "~/.bluelink/prod.auth.json"`, cfg.CLIName, cfg.EnvVarPrefix, cfg.DefaultConfigFile),
+	}
+
+	setupContextListCommand(contextCmd, confProvider, cfg)
+	setupContextUseCommand(contextCmd, confProvider, cfg)
+	setupContextCurrentCommand(contextCmd, confProvider)
+
+	rootCmd.AddCommand(contextCmd)
+}
+
+func setupContextListCommand(contextCmd *cobra.Command, confProvider *config.Provider, cfg *CLIConfig) {
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the engine contexts defined in the CLI config file",
+		Long: fmt.Sprintf(`Lists the engine contexts defined in the CLI config file,
+the selected context is marked with "*".
+
+Examples:
+  # List contexts
+  %[1]s context list
+
+  # List contexts as JSON
+  %[1]s context list --json`, cfg.CLIName),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			jsonMode, _, err := confProvider.GetBoolE("contextListJson")
+			if err != nil {
+				return err
+			}
+
+			output := buildContextListOutput(confProvider)
+			if jsonMode {
+				cmd.SilenceErrors = true
+				jsonout.WriteJSON(os.Stdout, output)
+				return nil
+			}
+
+			return writeContexts(os.Stdout, output)
+		},
+	}
+
+	listCmd.Flags().Bool("json", false,
+		"Output the contexts as a single JSON object.",
+	)
+	confProvider.BindPFlag("contextListJson", listCmd.Flags().Lookup("json"))
+	confProvider.BindEnvVar("contextListJson", cfg.EnvVarPrefix+"_CONTEXT_LIST_JSON")
+
+	contextCmd.AddCommand(listCmd)
+}
+
+func setupContextUseCommand(contextCmd *cobra.Command, confProvider *config.Provider, cfg *CLIConfig) {
+	useCmd := &cobra.Command{
+		Use:   "use <context>",
+		Short: "Select the engine context used by subsequent commands",
+		Long: fmt.Sprintf(`Selects the engine context used by subsequent commands.
+The selection is stored in %[2]s
+and can be overridden for a single command with the --context flag.
+
+Examples:
+  # Use the staging context
+  %[1]s context use staging`, cfg.CLIName, confProvider.CurrentContextFile()),
+		Args: cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return confProvider.ContextNames(), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			if err := confProvider.UseContext(args[0]); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(os.Stdout, "Switched to context %q.\n", args[0])
+			return nil
+		},
+	}
+
+	contextCmd.AddCommand(useCmd)
+}
+
+func setupContextCurrentCommand(contextCmd *cobra.Command, confProvider *config.Provider) {
+	currentCmd := &cobra.Command{
+		Use:   "current",
+		Short: "Show the selected engine context",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			name, _, err := confProvider.ActiveContext()
+			if err != nil {
+				return err
+			}
+
+			if name == "" {
+				return errNoContextSelected
+			}
+
+			fmt.Fprintln(os.Stdout, name)
+			return nil
+		},
+	}
+
+	contextCmd.AddCommand(currentCmd)
+}
+
+func buildContextListOutput(confProvider *config.Provider) jsonout.ContextListOutput {
+	current := confProvider.ActiveContextName()
+	contexts := confProvider.Contexts()
+	output := jsonout.ContextListOutput{
+		Success:  true,
+		Current:  current,
+		Contexts: make([]jsonout.EngineContext, 0, len(contexts)),
+	}
+	for _, name := range confProvider.ContextNames() {
+		engineContext := contexts[name]
+		output.Contexts = append(output.Contexts, jsonout.EngineContext{
+			Name:                 name,
+			Current:              name == current,
+			EngineEndpoint:       engineContext.EngineEndpoint,
+			ConnectProtocol:      engineContext.ConnectProtocol,
+			EngineAuthConfigFile: engineContext.EngineAuthConfigFile,
+		})
+	}
+	return output
+}
+
+func writeContexts(w io.Writer, output jsonout.ContextListOutput) error {
+	if len(output.Contexts) == 0 {
+		fmt.Fprintln(w, "No contexts are defined in the CLI config file.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CURRENT\tNAME\tENDPOINT\tPROTOCOL")
+	for _, engineContext := range output.Contexts {
+		marker := ""
+		if engineContext.Current {
+			marker = "*"
+		}
+		fmt.Fprintf(
+			tw,
+			"%s\t%s\t%s\t%s\n",
+			marker,
+			engineContext.Name,
+			valueOrDash(engineContext.EngineEndpoint),
+			valueOrDash(engineContext.ConnectProtocol),
+		)
+	}
+	return tw.Flush()
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -11,8 +11,9 @@ import (
 	"github.com/newstack-cloud/deploy-cli-sdk/config"
 	"github.com/newstack-cloud/deploy-cli-sdk/engine"
 	"github.com/newstack-cloud/deploy-cli-sdk/headless"
-	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
+	"github.com/newstack-cloud/deploy-cli-sdk/planfile"
 	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/batchui"
 	"github.com/newstack-cloud/deploy-cli-sdk/tui/deployui"
 	"github.com/newstack-cloud/deploy-cli-sdk/tui/driftui"
 	"github.com/spf13/cobra"
@@ -45,25 +46,39 @@ type deployFlags struct {
 	autoRollback           bool
 	force                  bool
 	jsonMode               bool
+	ndjsonMode             bool
+	outputFormat           string
+	planFile               string
+	manifestFile           string
+	parallelism            int
+	// parallelismErr is set when the parallelism is not a valid number,
+	// it is only reported when deploying a manifest.
+	parallelismErr error
 }
 
-func readDeployFlags(confProvider *config.Provider, cfg *CLIConfig) deployFlags {
+func readDeployFlags(confProvider *config.Provider, cfg *CLIConfig) (deployFlags, error) {
+	values := newConfigValues(confProvider)
 	changesetID, changesetIDIsDefault := confProvider.GetString("deployChangeSetID")
 	instanceID, instanceIDIsDefault := confProvider.GetString("deployInstanceID")
 	instanceName, instanceNameIsDefault := confProvider.GetString("deployInstanceName")
 	blueprintFile, isDefault := confProvider.GetString("deployBlueprintFile")
-	stageFirst, _ := confProvider.GetBool("deployStage")
-	autoApprove, _ := confProvider.GetBool("deployAutoApprove")
-	skipPrompts, _ := confProvider.GetBool("deploySkipPrompts")
-	autoRollback, _ := confProvider.GetBool("deployAutoRollback")
-	force, _ := confProvider.GetBool("deployForce")
-	jsonMode, _ := confProvider.GetBool("deployJson")
+	stageFirst := values.bool("deployStage")
+	autoApprove := values.bool("deployAutoApprove")
+	skipPrompts := values.bool("deploySkipPrompts")
+	autoRollback := values.bool("deployAutoRollback")
+	force := values.bool("deployForce")
+	jsonMode := values.bool("deployJson")
+	outputFormat, _ := confProvider.GetString("deployOutputFormat")
+	planFile, _ := confProvider.GetString("deployPlan")
+	manifestFile, _ := confProvider.GetString("deployManifest")
+	parallelism, _, parallelismErr := confProvider.GetInt32E("deployParallelism")
 
 	var autoApproveCodeOnly bool
 	if cfg.EnableCodeOnlyApproval {
-		autoApproveCodeOnly, _ = confProvider.GetBool("deployAutoApproveCodeOnly")
+		autoApproveCodeOnly = values.bool("deployAutoApproveCodeOnly")
 	}
 
+	jsonMode = jsonMode || isJSONOutputFormat(outputFormat)
 	if jsonMode {
 		autoApprove = true
 	}
@@ -84,10 +99,66 @@ func readDeployFlags(confProvider *config.Provider, cfg *CLIConfig) deployFlags
 		autoRollback:           autoRollback,
 		force:                  force,
 		jsonMode:               jsonMode,
+		ndjsonMode:             outputFormat == outputFormatNDJSON,
+		outputFormat:           outputFormat,
+		planFile:               planFile,
+		manifestFile:           manifestFile,
+		parallelism:            int(parallelism),
+		parallelismErr:         parallelismErr,
+	}, values.err
+}
+
+// applyDeployPlan fills in the deploy flags from a plan file,
+// ensuring that any values provided explicitly match the plan.
+func applyDeployPlan(flags *deployFlags, plan *planfile.Plan) error {
+	if flags.stageFirst {
+		return errors.New("--plan can not be used with --stage, the plan already contains staged changes")
+	}
+	if !flags.changesetIDIsDefault && flags.changesetID != "" && flags.changesetID != plan.ChangesetID {
+		return fmt.Errorf(
+			"--%s %q does not match change set %q in the plan",
+			flagChangeSetID,
+			flags.changesetID,
+			plan.ChangesetID,
+		)
+	}
+	if !flags.instanceIDIsDefault && flags.instanceID != "" && flags.instanceID != plan.InstanceID {
+		return fmt.Errorf(
+			"--%s %q does not match instance %q in the plan",
+			flagInstanceID,
+			flags.instanceID,
+			plan.InstanceID,
+		)
+	}
+	if !flags.instanceNameIsDefault && flags.instanceName != "" && flags.instanceName != plan.InstanceName {
+		return fmt.Errorf(
+			"--%s %q does not match instance %q in the plan",
+			flagInstanceName,
+			flags.instanceName,
+			plan.InstanceName,
+		)
 	}
+
+	flags.changesetID = plan.ChangesetID
+	flags.changesetIDIsDefault = false
+	flags.instanceID = plan.InstanceID
+	flags.instanceIDIsDefault = plan.InstanceID == ""
+	flags.instanceName = plan.InstanceName
+	flags.instanceNameIsDefault = plan.InstanceName == ""
+	if flags.isDefaultBlueprintFile {
+		flags.blueprintFile = plan.BlueprintFile
+		flags.isDefaultBlueprintFile = false
+	}
+	// Everything needed for the deployment comes from the plan so the
+	// config form is skipped to prevent the reviewed values from being changed.
+	flags.skipPrompts = true
+	return nil
 }
 
 func validateDeployFlags(flags deployFlags) error {
+	if err := validateOutputFormat(flags.outputFormat); err != nil {
+		return err
+	}
 	return headless.Validate(
 		headless.OneOf(
 			headless.Flag{
@@ -121,9 +192,26 @@ func validateDeployFlags(flags deployFlags) error {
 	)
 }
 
+func loadDeployPlan(flags *deployFlags) (*planfile.Plan, error) {
+	if flags.planFile == "" {
+		return nil, nil
+	}
+
+	plan, err := planfile.Read(flags.planFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyDeployPlan(flags, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
 func runDeployTUI(
 	cmd *cobra.Command,
 	flags deployFlags,
+	plan *planfile.Plan,
 	cfg *CLIConfig,
 	confProvider *config.Provider,
 	deployEngine engine.DeployEngine,
@@ -148,11 +236,48 @@ func runDeployTUI(
 		}
 	}
 
-	preflightModel := createPreflight(cmd.Context(), cfg, confProvider, "deploy", styles, headlessMode, flags.jsonMode)
+	preflightModel, err := createPreflight(cmd.Context(), cfg, confProvider, "deploy", styles, headlessMode, flags.jsonMode)
+	if err != nil {
+		return err
+	}
 
 	// Loaded after the pre-command step so it picks up the deploy configuration
 	// artifacts written by the pre-command step, if any.
-	operationConfig, err := config.LoadOperationConfig(confProvider)
+	operationConfig, variableOverrides, err := loadOperationConfigWithOverrides(confProvider, "deploy")
+	if err != nil {
+		return err
+	}
+
+	// Variables are only needed when changes are staged as part of
+	// the deployment.
+	if plan == nil && (flags.stageFirst || flags.changesetID == "") {
+		operationConfig, _, err = resolveMissingVariables(
+			cmd, flags.blueprintFile, operationConfig, confProvider, styles, headlessMode, logger,
+		)
+		if err != nil {
+			if flags.jsonMode {
+				writeJSONError(os.Stdout, err, flags.ndjsonMode)
+				return errDeploymentFailed
+			}
+			return err
+		}
+	}
+
+	if plan != nil {
+		verifyErr := plan.Verify(cmd.Context(), deployEngine, planfile.VerifyInput{
+			BlueprintFile:   flags.blueprintFile,
+			OperationConfig: operationConfig,
+		})
+		if verifyErr != nil {
+			if flags.jsonMode {
+				writeJSONError(os.Stdout, verifyErr, flags.ndjsonMode)
+				return errDeploymentFailed
+			}
+			return verifyErr
+		}
+	}
+
+	streamReconnect, err := loadStreamReconnectPolicy(confProvider)
 	if err != nil {
 		return err
 	}
@@ -176,8 +301,12 @@ func runDeployTUI(
 		Headless:               headlessMode,
 		HeadlessWriter:         os.Stdout,
 		JSONMode:               flags.jsonMode,
+		NDJSONStream:           newNDJSONStream(os.Stdout, flags.ndjsonMode),
+		EngineContext:          confProvider.ActiveContextName(),
 		Preflight:              preflightModel,
+		Variables:              variableOverridesOutput(variableOverrides, flags.blueprintFile),
 		OperationConfig:        operationConfig,
+		StreamReconnect:        streamReconnect,
 	})
 	if err != nil {
 		return err
@@ -223,6 +352,20 @@ Examples:
   # Deploy from a specific blueprint file
   %[1]s deploy --blueprint-file ./%[2]s --instance-name my-app
 
+  # Deploy with blueprint variables set on the command line
+  %[1]s deploy --instance-name my-app --var-file vars.json --var version=1.4.2
+
+  # Deploy a plan file written by "stage --out", refusing to deploy if the
+  # blueprint, deploy configuration or instance have changed since staging
+  %[1]s deploy --plan plan.json
+
+  # Stage and deploy all the instances in a deployment manifest,
+  # deploying up to 8 instances at the same time
+  %[1]s deploy --manifest deploy-set.yaml --auto-approve --parallelism 8
+
+  # Stage and deploy, streaming each event as a line of NDJSON for CI systems
+  %[1]s deploy --instance-name my-app --stage --output ndjson
+
   # Deploy with auto-rollback enabled
   %[1]s deploy --instance-name my-app --auto-rollback`, cfg.CLIName, cfg.DefaultBlueprintFile),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -237,22 +380,34 @@ Examples:
 				return err
 			}
 
-			flags := readDeployFlags(confProvider, cfg)
+			flags, err := readDeployFlags(confProvider, cfg)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
 
 			if flags.jsonMode {
 				cmd.SilenceUsage = true
 				cmd.SilenceErrors = true
 			}
 
-			if err := validateDeployFlags(flags); err != nil {
+			if flags.manifestFile != "" {
+				return runDeployManifest(cmd, flags, cfg, confProvider, deployEngine, logger)
+			}
+
+			plan, err := loadDeployPlan(&flags)
+			if err == nil {
+				err = validateDeployFlags(flags)
+			}
+			if err != nil {
 				if flags.jsonMode {
-					jsonout.WriteJSON(os.Stdout, jsonout.NewErrorOutput(err))
+					writeJSONError(os.Stdout, err, flags.ndjsonMode)
 					return errDeploymentFailed
 				}
 				return err
 			}
 
-			return runDeployTUI(cmd, flags, cfg, confProvider, deployEngine, logger)
+			return runDeployTUI(cmd, flags, plan, cfg, confProvider, deployEngine, logger)
 		},
 	}
 
@@ -346,5 +501,40 @@ Examples:
 	)
 	confProvider.BindPFlag("deployJson", deployCmd.PersistentFlags().Lookup("json"))
 
+	deployCmd.PersistentFlags().String("output", outputFormatText, outputFlagUsage)
+	confProvider.BindPFlag("deployOutputFormat", deployCmd.PersistentFlags().Lookup("output"))
+	confProvider.BindEnvVar("deployOutputFormat", prefix+"_DEPLOY_OUTPUT")
+
+	deployCmd.PersistentFlags().String("plan", "",
+		"A plan file written by \"stage --out\" to deploy. "+
+			"The change set and instance are taken from the plan and the deployment is refused "+
+			"if the blueprint file, deploy configuration or instance have changed since staging.",
+	)
+	confProvider.BindPFlag("deployPlan", deployCmd.PersistentFlags().Lookup("plan"))
+	confProvider.BindEnvVar("deployPlan", prefix+"_DEPLOY_PLAN")
+
+	deployCmd.PersistentFlags().String("manifest", "",
+		"A deployment manifest (.yaml, .yml or .json) listing the blueprint file, instance name "+
+			"and optional deploy config file of multiple instances to stage and deploy together. "+
+			"Instances can depend on other instances in the manifest with \"dependsOn\" and are only "+
+			"deployed once their dependencies have been deployed successfully. "+
+			"Requires --auto-approve as the changes staged for each instance are not reviewed before deploying.",
+	)
+	confProvider.BindPFlag("deployManifest", deployCmd.PersistentFlags().Lookup("manifest"))
+	confProvider.BindEnvVar("deployManifest", prefix+"_DEPLOY_MANIFEST")
+
+	deployCmd.PersistentFlags().Int("parallelism", 0,
+		"The maximum number of instances to deploy at the same time when using --manifest. "+
+			"Overrides the parallelism set in the manifest, "+
+			fmt.Sprintf("defaults to %d when neither is set.", batchui.DefaultParallelism),
+	)
+	confProvider.BindPFlag("deployParallelism", deployCmd.PersistentFlags().Lookup("parallelism"))
+	confProvider.BindEnvVar("deployParallelism", prefix+"_DEPLOY_PARALLELISM")
+
+	registerVariableOverrideFlags(deployCmd, confProvider, "deploy", prefix+"_DEPLOY")
+
+	registerFlagCompletions(deployCmd, confProvider, cfg)
+
+	registerRecordSessionFlag(rootCmd, confProvider, cfg)
 	rootCmd.AddCommand(deployCmd)
 }
@@ -0,0 +1,161 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/newstack-cloud/deploy-cli-sdk/config"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/batchui"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"golang.org/x/term"
+)
+
+// validateManifestDeployFlags makes sure that flags that target a single
+// instance are not combined with a deployment manifest.
+func validateManifestDeployFlags(flags deployFlags) error {
+	if err := validateOutputFormat(flags.outputFormat); err != nil {
+		return err
+	}
+	conflicting := []struct {
+		name  string
+		isSet bool
+	}{
+		{flagInstanceName, !flags.instanceNameIsDefault && flags.instanceName != ""},
+		{flagInstanceID, !flags.instanceIDIsDefault && flags.instanceID != ""},
+		{flagChangeSetID, !flags.changesetIDIsDefault && flags.changesetID != ""},
+		{"plan", flags.planFile != ""},
+		{"stage", flags.stageFirst},
+	}
+	for _, flag := range conflicting {
+		if flag.isSet {
+			return fmt.Errorf(
+				"--%s can not be used with --manifest, instances are defined in the manifest "+
+					"and are always staged before being deployed",
+				flag.name,
+			)
+		}
+	}
+	if flags.ndjsonMode {
+		return fmt.Errorf(
+			"--output %s can not be used with --manifest, use --json for a summary of the batch deployment",
+			outputFormatNDJSON,
+		)
+	}
+	if !flags.autoApprove {
+		return errors.New(
+			"--manifest requires --auto-approve, the changes staged for each instance " +
+				"in the manifest are deployed without a prompt for approval",
+		)
+	}
+	if flags.parallelismErr != nil {
+		return flags.parallelismErr
+	}
+	if flags.parallelism < 0 {
+		return fmt.Errorf("--parallelism must be a positive number, got %d", flags.parallelism)
+	}
+	return nil
+}
+
+func runDeployManifest(
+	cmd *cobra.Command,
+	flags deployFlags,
+	cfg *CLIConfig,
+	confProvider *config.Provider,
+	deployEngine engine.DeployEngine,
+	logger *zap.Logger,
+) error {
+	manifest, err := batchui.LoadManifest(flags.manifestFile)
+	if err == nil {
+		err = validateManifestDeployFlags(flags)
+	}
+	if err != nil {
+		if flags.jsonMode {
+			writeJSONError(os.Stdout, err, flags.ndjsonMode)
+			return errDeploymentFailed
+		}
+		return err
+	}
+
+	if _, err := tea.LogToFile(fmt.Sprintf("%s-output.log", cfg.CLIName), "simple"); err != nil {
+		log.Fatal(err)
+	}
+
+	cmd.SilenceUsage = true
+
+	styles := stylespkg.NewStyles(
+		lipgloss.NewRenderer(os.Stdout),
+		cfg.Palette,
+	)
+	inTerminal := term.IsTerminal(int(os.Stdout.Fd()))
+	headlessMode := !inTerminal || flags.jsonMode
+
+	if cfg.PreCommandStep != nil {
+		if err := RunPreCommandStep(cmd.Context(), cfg.PreCommandStep, confProvider, "deploy", styles, headlessMode, os.Stdout); err != nil {
+			return err
+		}
+	}
+
+	// The deploy config from the command configuration is used for
+	// instances that do not set their own deploy config file in the manifest.
+	defaultOperationConfig, err := config.LoadOperationConfig(confProvider)
+	if err != nil {
+		return err
+	}
+	operationConfigs, err := manifest.LoadOperationConfigs(defaultOperationConfig)
+	if err != nil {
+		return err
+	}
+
+	// Variables provided on the command line apply to every instance
+	// in the manifest.
+	variableOverrides, err := loadVariableOverrides(confProvider, "deploy")
+	if err != nil {
+		return err
+	}
+	blueprintFiles := make([]string, 0, len(manifest.Instances))
+	for i, operationConfig := range operationConfigs {
+		operationConfigs[i] = config.ApplyVariableOverrides(operationConfig, variableOverrides)
+		blueprintFiles = append(blueprintFiles, manifest.Instances[i].BlueprintFile)
+	}
+
+	app, err := batchui.NewBatchApp(batchui.BatchAppConfig{
+		Context:          cmd.Context(),
+		DeployEngine:     deployEngine,
+		Logger:           logger,
+		Manifest:         manifest,
+		OperationConfigs: operationConfigs,
+		Parallelism:      flags.parallelism,
+		AutoApprove:      flags.autoApprove,
+		AutoRollback:     flags.autoRollback,
+		Force:            flags.force,
+		Styles:           styles,
+		Headless:         headlessMode,
+		HeadlessWriter:   os.Stdout,
+		JSONMode:         flags.jsonMode,
+		EngineContext:    confProvider.ActiveContextName(),
+		Variables:        variableOverridesOutput(variableOverrides, blueprintFiles...),
+	})
+	if err != nil {
+		return err
+	}
+
+	finalModel, err := tea.NewProgram(app, newTUIProgramOptions(cmd.Context(), headlessMode)...).Run()
+	if err != nil {
+		return err
+	}
+	finalApp := finalModel.(batchui.MainModel)
+
+	if finalApp.Error != nil {
+		cmd.SilenceErrors = true
+		return errDeploymentFailed
+	}
+
+	return nil
+}
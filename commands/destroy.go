@@ -11,7 +11,6 @@ import (
 	"github.com/newstack-cloud/deploy-cli-sdk/config"
 	"github.com/newstack-cloud/deploy-cli-sdk/engine"
 	"github.com/newstack-cloud/deploy-cli-sdk/headless"
-	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
 	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
 	"github.com/newstack-cloud/deploy-cli-sdk/tui/destroyui"
 	"github.com/newstack-cloud/deploy-cli-sdk/tui/driftui"
@@ -36,19 +35,24 @@ type destroyFlags struct {
 	skipPrompts            bool
 	force                  bool
 	jsonMode               bool
+	ndjsonMode             bool
+	outputFormat           string
 }
 
-func readDestroyFlags(confProvider *config.Provider) destroyFlags {
+func readDestroyFlags(confProvider *config.Provider) (destroyFlags, error) {
+	values := newConfigValues(confProvider)
 	changesetID, changesetIDIsDefault := confProvider.GetString("destroyChangeSetID")
 	instanceID, instanceIDIsDefault := confProvider.GetString("destroyInstanceID")
 	instanceName, instanceNameIsDefault := confProvider.GetString("destroyInstanceName")
 	blueprintFile, isDefaultBlueprintFile := confProvider.GetString("destroyBlueprintFile")
-	stageFirst, _ := confProvider.GetBool("destroyStage")
-	autoApprove, _ := confProvider.GetBool("destroyAutoApprove")
-	skipPrompts, _ := confProvider.GetBool("destroySkipPrompts")
-	force, _ := confProvider.GetBool("destroyForce")
-	jsonMode, _ := confProvider.GetBool("destroyJson")
-
+	stageFirst := values.bool("destroyStage")
+	autoApprove := values.bool("destroyAutoApprove")
+	skipPrompts := values.bool("destroySkipPrompts")
+	force := values.bool("destroyForce")
+	jsonMode := values.bool("destroyJson")
+	outputFormat, _ := confProvider.GetString("destroyOutputFormat")
+
+	jsonMode = jsonMode || isJSONOutputFormat(outputFormat)
 	if jsonMode {
 		autoApprove = true
 	}
@@ -67,10 +71,15 @@ func readDestroyFlags(confProvider *config.Provider) destroyFlags {
 		skipPrompts:            skipPrompts,
 		force:                  force,
 		jsonMode:               jsonMode,
-	}
+		ndjsonMode:             outputFormat == outputFormatNDJSON,
+		outputFormat:           outputFormat,
+	}, values.err
 }
 
 func validateDestroyFlags(flags destroyFlags) error {
+	if err := validateOutputFormat(flags.outputFormat); err != nil {
+		return err
+	}
 	return headless.Validate(
 		headless.OneOf(
 			headless.Flag{
@@ -131,11 +140,19 @@ func runDestroyTUI(
 		}
 	}
 
-	preflightModel := createPreflight(cmd.Context(), cfg, confProvider, "destroy", styles, headlessMode, flags.jsonMode)
+	preflightModel, err := createPreflight(cmd.Context(), cfg, confProvider, "destroy", styles, headlessMode, flags.jsonMode)
+	if err != nil {
+		return err
+	}
 
 	// Loaded after the pre-command step so it picks up the deploy configuration
 	// artifacts written by the pre-command step, if any.
-	operationConfig, err := config.LoadOperationConfig(confProvider)
+	operationConfig, variableOverrides, err := loadOperationConfigWithOverrides(confProvider, "destroy")
+	if err != nil {
+		return err
+	}
+
+	streamReconnect, err := loadStreamReconnectPolicy(confProvider)
 	if err != nil {
 		return err
 	}
@@ -157,8 +174,12 @@ func runDestroyTUI(
 		Headless:               headlessMode,
 		HeadlessWriter:         os.Stdout,
 		JSONMode:               flags.jsonMode,
+		NDJSONStream:           newNDJSONStream(os.Stdout, flags.ndjsonMode),
+		EngineContext:          confProvider.ActiveContextName(),
 		Preflight:              preflightModel,
+		Variables:              variableOverridesOutput(variableOverrides, flags.blueprintFile),
 		OperationConfig:        operationConfig,
+		StreamReconnect:        streamReconnect,
 	})
 	if err != nil {
 		return err
@@ -204,6 +225,9 @@ Examples:
   # Stage destroy changes first, then execute with auto-approve
   %[1]s destroy --instance-name my-app --stage --auto-approve
 
+  # Stage and destroy, streaming each event as a line of NDJSON for CI systems
+  %[1]s destroy --instance-name my-app --stage --output ndjson
+
   # Force destroy, overriding state conflicts
   %[1]s destroy --instance-name my-app --force`, cfg.CLIName),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -218,7 +242,11 @@ Examples:
 				return err
 			}
 
-			flags := readDestroyFlags(confProvider)
+			flags, err := readDestroyFlags(confProvider)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
 
 			if flags.jsonMode {
 				cmd.SilenceUsage = true
@@ -227,7 +255,7 @@ Examples:
 
 			if err := validateDestroyFlags(flags); err != nil {
 				if flags.jsonMode {
-					jsonout.WriteJSON(os.Stdout, jsonout.NewErrorOutput(err))
+					writeJSONError(os.Stdout, err, flags.ndjsonMode)
 					return errDestroyFailed
 				}
 				return err
@@ -312,5 +340,14 @@ Examples:
 	)
 	confProvider.BindPFlag("destroyJson", destroyCmd.PersistentFlags().Lookup("json"))
 
+	destroyCmd.PersistentFlags().String("output", outputFormatText, outputFlagUsage)
+	confProvider.BindPFlag("destroyOutputFormat", destroyCmd.PersistentFlags().Lookup("output"))
+	confProvider.BindEnvVar("destroyOutputFormat", prefix+"_DESTROY_OUTPUT")
+
+	registerVariableOverrideFlags(destroyCmd, confProvider, "destroy", prefix+"_DESTROY")
+
+	registerFlagCompletions(destroyCmd, confProvider, cfg)
+
+	registerRecordSessionFlag(rootCmd, confProvider, cfg)
 	rootCmd.AddCommand(destroyCmd)
 }
@@ -0,0 +1,235 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/newstack-cloud/deploy-cli-sdk/config"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/doctorui"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"golang.org/x/term"
+)
+
+var errDoctorFailed = errors.New("doctor found problems with the CLI configuration")
+
+type doctorFlags struct {
+	configFile                string
+	configFileIsDefault       bool
+	engineConfigFile          string
+	engineConfigFileIsDefault bool
+	jsonMode                  bool
+	fix                       bool
+}
+
+func readDoctorFlags(confProvider *config.Provider) (doctorFlags, error) {
+	values := newConfigValues(confProvider)
+	configFile, configFileIsDefault := confProvider.GetString("doctorConfigFile")
+	engineConfigFile, engineConfigFileIsDefault := confProvider.GetString("doctorEngineConfigFile")
+	jsonMode := values.bool("doctorJson")
+	fix := values.bool("doctorFix")
+
+	return doctorFlags{
+		configFile:                configFile,
+		configFileIsDefault:       configFileIsDefault,
+		engineConfigFile:          engineConfigFile,
+		engineConfigFileIsDefault: engineConfigFileIsDefault,
+		jsonMode:                  jsonMode,
+		fix:                       fix,
+	}, values.err
+}
+
+func runDoctorTUI(
+	cmd *cobra.Command,
+	flags doctorFlags,
+	cfg *CLIConfig,
+	confProvider *config.Provider,
+	logger *zap.Logger,
+) error {
+	if _, err := tea.LogToFile(fmt.Sprintf("%s-output.log", cfg.CLIName), "simple"); err != nil {
+		log.Fatal(err)
+	}
+
+	styles := stylespkg.NewStyles(
+		lipgloss.NewRenderer(os.Stdout),
+		cfg.Palette,
+	)
+	inTerminal := term.IsTerminal(int(os.Stdout.Fd()))
+	headlessMode := !inTerminal || flags.jsonMode
+
+	preflightModel, preflightSkipReason, err := createDoctorPreflight(
+		cmd.Context(), flags, cfg, confProvider, styles, headlessMode,
+	)
+	if err != nil {
+		return err
+	}
+
+	app, err := doctorui.NewDoctorApp(doctorui.DoctorAppConfig{
+		Context:      cmd.Context(),
+		ConfProvider: confProvider,
+		CreateEngine: func() (engine.DeployEngine, error) {
+			return engine.Create(confProvider, logger)
+		},
+		CLIName:                   cfg.CLIName,
+		ConfigFile:                flags.configFile,
+		ConfigFileIsDefault:       flags.configFileIsDefault,
+		EngineConfigFile:          flags.engineConfigFile,
+		EngineConfigFileIsDefault: flags.engineConfigFileIsDefault,
+		Preflight:                 preflightModel,
+		PreflightSkipReason:       preflightSkipReason,
+		Styles:                    styles,
+		Headless:                  headlessMode,
+		HeadlessWriter:            os.Stdout,
+		JSONMode:                  flags.jsonMode,
+	})
+	if err != nil {
+		return err
+	}
+
+	finalModel, err := tea.NewProgram(app, newTUIProgramOptions(cmd.Context(), headlessMode)...).Run()
+	if err != nil {
+		return err
+	}
+	finalApp := finalModel.(doctorui.MainModel)
+
+	if finalApp.Error != nil {
+		cmd.SilenceErrors = true
+		return errDoctorFailed
+	}
+
+	return nil
+}
+
+// createDoctorPreflight creates the preflight model for the doctor command,
+// missing plugins are only installed when --fix is set. Otherwise a check-only
+// preflight model is used when the preflight factory can create one, a reason
+// for skipping the preflight checks is returned when it can't.
+func createDoctorPreflight(
+	ctx context.Context,
+	flags doctorFlags,
+	cfg *CLIConfig,
+	confProvider *config.Provider,
+	styles *stylespkg.Styles,
+	headlessMode bool,
+) (tea.Model, string, error) {
+	if flags.fix || cfg.PreflightFactory == nil {
+		preflightModel, err := createPreflight(ctx, cfg, confProvider, "doctor", styles, headlessMode, flags.jsonMode)
+		return preflightModel, "", err
+	}
+
+	skipCheck, _, err := confProvider.GetBoolE("skipPluginCheck")
+	if err != nil || skipCheck {
+		return nil, "", err
+	}
+
+	checkOnlyFactory, canCheckOnly := cfg.PreflightFactory.(CheckOnlyPreflightFactory)
+	if !canCheckOnly {
+		return nil, "the preflight checks install missing plugins, re-run with --fix to run them", nil
+	}
+	return checkOnlyFactory.CreateCheckOnlyPreflight(
+		ctx, confProvider, "doctor", styles, headlessMode, os.Stdout, flags.jsonMode,
+	), "", nil
+}
+
+// SetupDoctorCommand registers a doctor command on the root command,
+// parameterized by CLIConfig for branding and defaults.
+//
+// The doctor command diagnoses problems with the CLI configuration, the
+// connection to the deploy engine, the state storage backend and preflight checks.
+func SetupDoctorCommand(rootCmd *cobra.Command, confProvider *config.Provider, cfg *CLIConfig) {
+	doctorCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose problems with the CLI and deploy engine setup",
+		Long: fmt.Sprintf(`Runs a series of checks to diagnose problems with the CLI configuration,
+connecting and authenticating with the deploy engine, the deploy engine state
+configuration and preflight checks.
+
+The checks are:
+  - The CLI config file can be parsed.
+  - The engine auth config file exists, is only accessible by the current user
+    and is valid for the configured auth method.
+  - The connect protocol is valid and the engine endpoint or unix socket exists.
+  - The deploy engine accepts an authenticated request.
+  - The deploy engine config file is valid and the state storage backend
+    (memfile directory or postgres database) can be reached.
+  - Preflight checks (e.g. plugin dependencies) are satisfied.
+
+The checks do not change the system, missing plugins are reported as a failed
+preflight check and are only installed when --fix is set.
+
+The command exits with a non-zero code when any of the checks fail.
+
+Examples:
+  # Run all checks
+  %[1]s doctor
+
+  # Produce a JSON report to attach to a support ticket
+  %[1]s doctor --json > doctor-report.json
+
+  # Run all checks, installing any missing plugins
+  %[1]s doctor --fix
+
+  # Check a specific deploy engine config file
+  %[1]s doctor --engine-config-file ~/.bluelink/engine/config.json`, cfg.CLIName),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger, handle, err := SetupLogger(cfg.CLIName)
+			if err != nil {
+				return err
+			}
+			defer handle.Close()
+
+			cmd.SilenceUsage = true
+			flags, err := readDoctorFlags(confProvider)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+
+			if flags.jsonMode {
+				cmd.SilenceErrors = true
+			}
+
+			return runDoctorTUI(cmd, flags, cfg, confProvider, logger)
+		},
+	}
+
+	prefix := cfg.EnvVarPrefix
+
+	doctorCmd.Flags().String(
+		"config-file", cfg.DefaultConfigFile,
+		"The CLI config file to check.",
+	)
+	confProvider.BindPFlag("doctorConfigFile", doctorCmd.Flags().Lookup("config-file"))
+	confProvider.BindEnvVar("doctorConfigFile", prefix+"_DOCTOR_CONFIG_FILE")
+
+	doctorCmd.Flags().String(
+		"engine-config-file", "",
+		"Path to the deploy engine config file used to determine the state storage backend. "+
+			"Defaults to the standard deploy engine config location.",
+	)
+	confProvider.BindPFlag("doctorEngineConfigFile", doctorCmd.Flags().Lookup("engine-config-file"))
+	confProvider.BindEnvVar("doctorEngineConfigFile", prefix+"_DOCTOR_ENGINE_CONFIG_FILE")
+
+	doctorCmd.Flags().Bool("json", false,
+		"Output the check results as a single JSON object when the checks complete. "+
+			"Implies non-interactive mode (no TUI, no streaming text output).",
+	)
+	confProvider.BindPFlag("doctorJson", doctorCmd.Flags().Lookup("json"))
+	confProvider.BindEnvVar("doctorJson", prefix+"_DOCTOR_JSON")
+
+	doctorCmd.Flags().Bool("fix", false,
+		"Install missing plugins found by the preflight checks. "+
+			"Without this flag, doctor only reports problems and does not change the system.",
+	)
+	confProvider.BindPFlag("doctorFix", doctorCmd.Flags().Lookup("fix"))
+	confProvider.BindEnvVar("doctorFix", prefix+"_DOCTOR_FIX")
+
+	rootCmd.AddCommand(doctorCmd)
+}
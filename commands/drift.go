@@ -0,0 +1,389 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/newstack-cloud/deploy-cli-sdk/config"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/newstack-cloud/deploy-cli-sdk/headless"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
+	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/driftui"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/reconcileui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	errDriftCheckFailed     = errors.New("drift check failed")
+	errDriftReconcileFailed = errors.New("drift reconciliation failed")
+	errDriftDetected        = errors.New("drift detected")
+)
+
+// SetupDriftCommand registers a drift command with check and reconcile
+// subcommands on the root command, parameterized by CLIConfig for branding.
+//
+// When drift check finds resources or links that need reconciliation,
+// the command returns an ExitError with ExitCodeDriftDetected, see ExitCode.
+func SetupDriftCommand(rootCmd *cobra.Command, confProvider *config.Provider, cfg *CLIConfig) {
+	driftui.SetCLIName(cfg.CLIName)
+	driftCmd := &cobra.Command{
+		Use:   "drift",
+		Short: "Detect and reconcile drift for a blueprint instance",
+		Long: `Commands for detecting resources and links of a blueprint instance that have
+drifted from their persisted state or were left in an interrupted state,
+and for reconciling the persisted state with the external state.`,
+	}
+
+	setupDriftCheckCommand(driftCmd, confProvider, cfg)
+	setupDriftReconcileCommand(driftCmd, confProvider, cfg)
+
+	registerRecordSessionFlag(rootCmd, confProvider, cfg)
+	rootCmd.AddCommand(driftCmd)
+}
+
+func setupDriftCheckCommand(driftCmd *cobra.Command, confProvider *config.Provider, cfg *CLIConfig) {
+	checkCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check a blueprint instance for drift",
+		Long: fmt.Sprintf(`Checks the resources and links of a blueprint instance for drift
+and interrupted state without making any changes.
+
+The command exits with code %[2]d when drift is detected so scheduled jobs
+can alert on drift.
+
+Examples:
+  # Review drift in the interactive view
+  %[1]s drift check --instance-name my-app
+
+  # Check for drift in a nightly job
+  %[1]s drift check --instance-name my-app --json > drift.json`, cfg.CLIName, ExitCodeDriftDetected),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDrift(cmd, confProvider, cfg, reconcileui.ModeCheck)
+		},
+	}
+
+	bindDriftCommonFlags(checkCmd, confProvider, cfg, "driftCheck", "_DRIFT_CHECK")
+
+	driftCmd.AddCommand(checkCmd)
+}
+
+func setupDriftReconcileCommand(driftCmd *cobra.Command, confProvider *config.Provider, cfg *CLIConfig) {
+	reconcileCmd := &cobra.Command{
+		Use:   "reconcile",
+		Short: "Reconcile drift for a blueprint instance",
+		Long: fmt.Sprintf(`Checks a blueprint instance for drift and interrupted state and applies
+reconciliation actions for each resource and link that needs reconciliation.
+
+For each element, the external state is either accepted as the source of truth
+(accept-external) or the persisted state is kept as it is (keep-state).
+Choices can be made with flags or a policy file, flags take precedence
+over the rules in a policy file. When no choice is made for an element,
+the external state is accepted.
+
+Selectors are comma-separated names or resource types prefixed with "type:",
+both support glob patterns. Elements in child blueprints can be selected
+by name or by name qualified by the child blueprint path (e.g. "coreInfra.ordersTable").
+
+Policy files can be written in YAML or JSON:
+
+  default: keep-state
+  rules:
+    - resourceType: aws/dynamodb/table
+      action: accept-external
+    - name: coreInfra.*
+      action: keep-state
+
+Examples:
+  # Review drift and the reconciliation plan before applying it
+  %[1]s drift reconcile --instance-name my-app
+
+  # Accept the external state for a single resource, keep the state for everything else
+  %[1]s drift reconcile --instance-name my-app \
+    --accept-external ordersTable --default-action keep-state
+
+  # Keep the persisted state for all functions
+  %[1]s drift reconcile --instance-name my-app --keep-state "type:aws/lambda/function"
+
+  # Reconcile with a policy file in a scheduled job
+  %[1]s drift reconcile --instance-name my-app --policy-file drift-policy.yaml --json`, cfg.CLIName),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDrift(cmd, confProvider, cfg, reconcileui.ModeReconcile)
+		},
+	}
+
+	prefix := cfg.EnvVarPrefix
+
+	bindDriftCommonFlags(reconcileCmd, confProvider, cfg, "driftReconcile", "_DRIFT_RECONCILE")
+
+	reconcileCmd.PersistentFlags().String(
+		"policy-file", "",
+		"A YAML or JSON file with the rules that determine whether to accept the external state "+
+			"or keep the persisted state for each resource and link.",
+	)
+	confProvider.BindPFlag("driftReconcilePolicyFile", reconcileCmd.PersistentFlags().Lookup("policy-file"))
+	confProvider.BindEnvVar("driftReconcilePolicyFile", prefix+"_DRIFT_RECONCILE_POLICY_FILE")
+
+	reconcileCmd.PersistentFlags().String(
+		"accept-external", "",
+		"Comma-separated selectors for resources and links to accept the external state for "+
+			"(e.g. \"ordersTable,type:aws/s3/bucket\").",
+	)
+	confProvider.BindPFlag("driftReconcileAcceptExternal", reconcileCmd.PersistentFlags().Lookup("accept-external"))
+	confProvider.BindEnvVar("driftReconcileAcceptExternal", prefix+"_DRIFT_RECONCILE_ACCEPT_EXTERNAL")
+
+	reconcileCmd.PersistentFlags().String(
+		"keep-state", "",
+		"Comma-separated selectors for resources and links to keep the persisted state for. "+
+			"These take precedence over --accept-external.",
+	)
+	confProvider.BindPFlag("driftReconcileKeepState", reconcileCmd.PersistentFlags().Lookup("keep-state"))
+	confProvider.BindEnvVar("driftReconcileKeepState", prefix+"_DRIFT_RECONCILE_KEEP_STATE")
+
+	reconcileCmd.PersistentFlags().String(
+		"default-action", "",
+		"The choice for resources and links that are not selected by a flag or policy rule, "+
+			"one of: accept-external, keep-state. "+
+			"Overrides the default of the policy file, defaults to accept-external.",
+	)
+	confProvider.BindPFlag("driftReconcileDefaultAction", reconcileCmd.PersistentFlags().Lookup("default-action"))
+	confProvider.BindEnvVar("driftReconcileDefaultAction", prefix+"_DRIFT_RECONCILE_DEFAULT_ACTION")
+
+	driftCmd.AddCommand(reconcileCmd)
+}
+
+func bindDriftCommonFlags(
+	cmd *cobra.Command,
+	confProvider *config.Provider,
+	cfg *CLIConfig,
+	keyPrefix string,
+	envVarSuffix string,
+) {
+	envPrefix := cfg.EnvVarPrefix + envVarSuffix
+
+	cmd.PersistentFlags().String(
+		flagInstanceID, "",
+		"The ID of the blueprint instance to check for drift.",
+	)
+	confProvider.BindPFlag(keyPrefix+"InstanceID", cmd.PersistentFlags().Lookup(flagInstanceID))
+	confProvider.BindEnvVar(keyPrefix+"InstanceID", envPrefix+"_INSTANCE_ID")
+
+	cmd.PersistentFlags().String(
+		flagInstanceName, "",
+		"The user-defined unique name of the blueprint instance to check for drift.",
+	)
+	confProvider.BindPFlag(keyPrefix+"InstanceName", cmd.PersistentFlags().Lookup(flagInstanceName))
+	confProvider.BindEnvVar(keyPrefix+"InstanceName", envPrefix+"_INSTANCE_NAME")
+
+	cmd.PersistentFlags().String(
+		"blueprint-file", "",
+		"An optional blueprint file for the instance that is sent to the deploy engine "+
+			"with the drift check. This can be a local file, a public URL or a path to a file "+
+			"in an object storage bucket in the format of {scheme}://{bucket-name}/{object-path}.",
+	)
+	confProvider.BindPFlag(keyPrefix+"BlueprintFile", cmd.PersistentFlags().Lookup("blueprint-file"))
+	confProvider.BindEnvVar(keyPrefix+"BlueprintFile", envPrefix+"_BLUEPRINT_FILE")
+
+	cmd.PersistentFlags().Bool("json", false,
+		"Output the result as a single JSON object. "+
+			"Implies non-interactive mode (no TUI).",
+	)
+	confProvider.BindPFlag(keyPrefix+"Json", cmd.PersistentFlags().Lookup("json"))
+	confProvider.BindEnvVar(keyPrefix+"Json", envPrefix+"_JSON")
+}
+
+type driftFlags struct {
+	instanceID            string
+	instanceIDIsDefault   bool
+	instanceName          string
+	instanceNameIsDefault bool
+	blueprintFile         string
+	jsonMode              bool
+	policyFile            string
+	acceptExternal        string
+	keepState             string
+	defaultAction         string
+}
+
+func readDriftFlags(confProvider *config.Provider, mode reconcileui.Mode) (driftFlags, error) {
+	values := newConfigValues(confProvider)
+	keyPrefix := "driftCheck"
+	if mode == reconcileui.ModeReconcile {
+		keyPrefix = "driftReconcile"
+	}
+
+	instanceID, instanceIDIsDefault := confProvider.GetString(keyPrefix + "InstanceID")
+	instanceName, instanceNameIsDefault := confProvider.GetString(keyPrefix + "InstanceName")
+	blueprintFile, _ := confProvider.GetString(keyPrefix + "BlueprintFile")
+	jsonMode := values.bool(keyPrefix + "Json")
+
+	flags := driftFlags{
+		instanceID:            instanceID,
+		instanceIDIsDefault:   instanceIDIsDefault,
+		instanceName:          instanceName,
+		instanceNameIsDefault: instanceNameIsDefault,
+		blueprintFile:         blueprintFile,
+		jsonMode:              jsonMode,
+	}
+
+	if mode == reconcileui.ModeReconcile {
+		flags.policyFile, _ = confProvider.GetString("driftReconcilePolicyFile")
+		flags.acceptExternal, _ = confProvider.GetString("driftReconcileAcceptExternal")
+		flags.keepState, _ = confProvider.GetString("driftReconcileKeepState")
+		flags.defaultAction, _ = confProvider.GetString("driftReconcileDefaultAction")
+	}
+
+	return flags, values.err
+}
+
+func validateDriftFlags(flags driftFlags) error {
+	return headless.Validate(headless.OneOf(
+		headless.Flag{
+			Name:      flagInstanceName,
+			Value:     flags.instanceName,
+			IsDefault: flags.instanceNameIsDefault,
+		},
+		headless.Flag{
+			Name:      flagInstanceID,
+			Value:     flags.instanceID,
+			IsDefault: flags.instanceIDIsDefault,
+		},
+	))
+}
+
+// resolveReconcilePolicy builds the reconciliation policy from the policy file
+// (if any) with the choices expressed with flags taking precedence.
+func resolveReconcilePolicy(flags driftFlags) (*reconcileui.Policy, error) {
+	policy := &reconcileui.Policy{}
+	if flags.policyFile != "" {
+		loaded, err := reconcileui.LoadPolicyFile(flags.policyFile)
+		if err != nil {
+			return nil, err
+		}
+		policy = loaded
+	}
+
+	var defaultChoice reconcileui.Choice
+	if flags.defaultAction != "" {
+		choice, err := reconcileui.ParseChoice(flags.defaultAction)
+		if err != nil {
+			return nil, fmt.Errorf("--default-action: %w", err)
+		}
+		defaultChoice = choice
+	}
+
+	keepRules, err := reconcileui.ParseSelectors(flags.keepState, reconcileui.ChoiceKeepState)
+	if err != nil {
+		return nil, fmt.Errorf("--keep-state: %w", err)
+	}
+
+	acceptRules, err := reconcileui.ParseSelectors(flags.acceptExternal, reconcileui.ChoiceAcceptExternal)
+	if err != nil {
+		return nil, fmt.Errorf("--accept-external: %w", err)
+	}
+
+	return policy.WithOverrides(append(keepRules, acceptRules...), defaultChoice), nil
+}
+
+func runDrift(
+	cmd *cobra.Command,
+	confProvider *config.Provider,
+	cfg *CLIConfig,
+	mode reconcileui.Mode,
+) error {
+	logger, handle, err := SetupLogger(cfg.CLIName)
+	if err != nil {
+		return err
+	}
+	defer handle.Close()
+
+	deployEngine, err := engine.Create(confProvider, logger)
+	if err != nil {
+		return err
+	}
+
+	failedErr := errDriftCheckFailed
+	if mode == reconcileui.ModeReconcile {
+		failedErr = errDriftReconcileFailed
+	}
+
+	flags, err := readDriftFlags(confProvider, mode)
+	if err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+
+	if flags.jsonMode {
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+	}
+
+	policy, policyErr := resolveReconcilePolicy(flags)
+	if err := errors.Join(validateDriftFlags(flags), policyErr); err != nil {
+		if flags.jsonMode {
+			jsonout.WriteJSON(os.Stdout, jsonout.NewErrorOutput(err))
+			return failedErr
+		}
+		return err
+	}
+
+	operationConfig, err := config.LoadOperationConfig(confProvider)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tea.LogToFile(fmt.Sprintf("%s-output.log", cfg.CLIName), "simple"); err != nil {
+		log.Fatal(err)
+	}
+
+	cmd.SilenceUsage = true
+
+	styles := stylespkg.NewStyles(
+		lipgloss.NewRenderer(os.Stdout),
+		cfg.Palette,
+	)
+	inTerminal := term.IsTerminal(int(os.Stdout.Fd()))
+	headlessMode := !inTerminal || flags.jsonMode
+
+	app, err := reconcileui.NewReconcileApp(reconcileui.ReconcileAppConfig{
+		Context:         cmd.Context(),
+		DeployEngine:    deployEngine,
+		Logger:          logger,
+		Mode:            mode,
+		InstanceID:      flags.instanceID,
+		InstanceName:    flags.instanceName,
+		BlueprintFile:   flags.blueprintFile,
+		OperationConfig: operationConfig,
+		Policy:          policy,
+		Styles:          styles,
+		Headless:        headlessMode,
+		HeadlessWriter:  os.Stdout,
+		JSONMode:        flags.jsonMode,
+	})
+	if err != nil {
+		return err
+	}
+
+	finalModel, err := tea.NewProgram(app, newTUIProgramOptions(cmd.Context(), headlessMode)...).Run()
+	if err != nil {
+		return err
+	}
+	finalApp := finalModel.(reconcileui.MainModel)
+
+	if finalApp.Error != nil {
+		cmd.SilenceErrors = true
+		return failedErr
+	}
+
+	if mode == reconcileui.ModeCheck && finalApp.DriftDetected() {
+		cmd.SilenceErrors = true
+		return &ExitError{Code: ExitCodeDriftDetected, Err: errDriftDetected}
+	}
+
+	return nil
+}
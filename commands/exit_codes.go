@@ -0,0 +1,55 @@
+package commands
+
+import "errors"
+
+const (
+	// ExitCodeFailure is the exit code for commands that fail to carry out
+	// the requested operation (e.g. engine errors or invalid flags).
+	ExitCodeFailure = 1
+
+	// ExitCodeValidationFailed is the exit code for a validation that
+	// completed but produced one or more error diagnostics.
+	ExitCodeValidationFailed = 2
+
+	// ExitCodeDriftDetected is the exit code for a drift check that
+	// completed and found one or more resources or links that need reconciliation.
+	ExitCodeDriftDetected = 3
+)
+
+// ExitError wraps an error returned from a command with the exit code
+// the CLI process should exit with.
+// This allows CI pipelines to tell apart an operation that could not be
+// carried out from one that completed with a failing result.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the process exit code for an error returned from
+// a command created by one of the command factories.
+// Returns 0 for a nil error, the code of an ExitError in the chain
+// or ExitCodeFailure for any other error.
+//
+// CLIs should use this when exiting after executing the root command:
+//
+//	if err := rootCmd.ExecuteContext(ctx); err != nil {
+//		os.Exit(commands.ExitCode(err))
+//	}
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return ExitCodeFailure
+}
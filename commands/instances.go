@@ -13,6 +13,7 @@ import (
 	"github.com/newstack-cloud/deploy-cli-sdk/headless"
 	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
 	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/exportsui"
 	"github.com/newstack-cloud/deploy-cli-sdk/tui/inspectui"
 	"github.com/newstack-cloud/deploy-cli-sdk/tui/listui"
 	"github.com/spf13/cobra"
@@ -21,8 +22,9 @@ import (
 
 var errInspectFailed = errors.New("inspect failed")
 var errListFailed = errors.New("list instances failed")
+var errExportsFailed = errors.New("fetching instance exports failed")
 
-// SetupInstancesCommand registers an instances command with inspect and list
+// SetupInstancesCommand registers an instances command with inspect, list and exports
 // subcommands on the root command, parameterized by CLIConfig for branding.
 func SetupInstancesCommand(rootCmd *cobra.Command, confProvider *config.Provider, cfg *CLIConfig) {
 	instancesCmd := &cobra.Command{
@@ -34,7 +36,9 @@ deploy engine. Use subcommands to list, inspect, or manage instances.`,
 
 	setupInstancesInspectCommand(instancesCmd, confProvider, cfg)
 	setupInstancesListCommand(instancesCmd, confProvider, cfg)
+	setupInstancesExportsCommand(instancesCmd, confProvider, cfg)
 
+	registerRecordSessionFlag(rootCmd, confProvider, cfg)
 	rootCmd.AddCommand(instancesCmd)
 }
 
@@ -90,6 +94,8 @@ Examples:
 	confProvider.BindPFlag("instancesInspectJson", inspectCmd.PersistentFlags().Lookup("json"))
 	confProvider.BindEnvVar("instancesInspectJson", prefix+"_INSTANCES_INSPECT_JSON")
 
+	registerFlagCompletions(inspectCmd, confProvider, cfg)
+
 	instancesCmd.AddCommand(inspectCmd)
 }
 
@@ -101,10 +107,11 @@ type inspectFlags struct {
 	jsonMode              bool
 }
 
-func readInspectFlags(confProvider *config.Provider) inspectFlags {
+func readInspectFlags(confProvider *config.Provider) (inspectFlags, error) {
+	values := newConfigValues(confProvider)
 	instanceID, instanceIDIsDefault := confProvider.GetString("instancesInspectInstanceID")
 	instanceName, instanceNameIsDefault := confProvider.GetString("instancesInspectInstanceName")
-	jsonMode, _ := confProvider.GetBool("instancesInspectJson")
+	jsonMode := values.bool("instancesInspectJson")
 
 	return inspectFlags{
 		instanceID:            instanceID,
@@ -112,7 +119,7 @@ func readInspectFlags(confProvider *config.Provider) inspectFlags {
 		instanceName:          instanceName,
 		instanceNameIsDefault: instanceNameIsDefault,
 		jsonMode:              jsonMode,
-	}
+	}, values.err
 }
 
 func validateInspectFlags(flags inspectFlags) error {
@@ -142,7 +149,11 @@ func runInspect(cmd *cobra.Command, confProvider *config.Provider, cfg *CLIConfi
 		return err
 	}
 
-	flags := readInspectFlags(confProvider)
+	flags, err := readInspectFlags(confProvider)
+	if err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
 
 	if flags.jsonMode {
 		cmd.SilenceUsage = true
@@ -170,16 +181,22 @@ func runInspect(cmd *cobra.Command, confProvider *config.Provider, cfg *CLIConfi
 	inTerminal := term.IsTerminal(int(os.Stdout.Fd()))
 	headlessMode := !inTerminal || flags.jsonMode
 
+	streamReconnect, err := loadStreamReconnectPolicy(confProvider)
+	if err != nil {
+		return err
+	}
+
 	app, err := inspectui.NewInspectApp(inspectui.InspectAppConfig{
-		Context:        cmd.Context(),
-		DeployEngine:   deployEngine,
-		Logger:         logger,
-		InstanceID:     flags.instanceID,
-		InstanceName:   flags.instanceName,
-		Styles:         styles,
-		Headless:       headlessMode,
-		HeadlessWriter: os.Stdout,
-		JSONMode:       flags.jsonMode,
+		Context:         cmd.Context(),
+		DeployEngine:    deployEngine,
+		Logger:          logger,
+		InstanceID:      flags.instanceID,
+		InstanceName:    flags.instanceName,
+		Styles:          styles,
+		Headless:        headlessMode,
+		HeadlessWriter:  os.Stdout,
+		JSONMode:        flags.jsonMode,
+		StreamReconnect: streamReconnect,
 	})
 	if err != nil {
 		return err
@@ -253,7 +270,11 @@ func runListInstances(cmd *cobra.Command, confProvider *config.Provider, cfg *CL
 	}
 
 	search, _ := confProvider.GetString("instancesListSearch")
-	jsonMode, _ := confProvider.GetBool("instancesListJson")
+	jsonMode, _, err := confProvider.GetBoolE("instancesListJson")
+	if err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
 
 	if jsonMode {
 		cmd.SilenceUsage = true
@@ -302,3 +323,218 @@ func runListInstances(cmd *cobra.Command, confProvider *config.Provider, cfg *CL
 
 	return nil
 }
+
+func setupInstancesExportsCommand(instancesCmd *cobra.Command, confProvider *config.Provider, cfg *CLIConfig) {
+	exportsCmd := &cobra.Command{
+		Use:   "exports",
+		Short: "Show the exports of a blueprint instance",
+		Long: fmt.Sprintf(`Displays the exported values of a deployed blueprint instance.
+
+In interactive mode, the exports are shown in a table that you can navigate
+to view the full value of each export.
+
+In non-interactive mode, the exports can be written in a format that can be
+consumed by later steps in a CI/CD pipeline.
+Export names are converted to environment variable names for the dotenv and
+shell formats, for example, "queueUrl" becomes "QUEUE_URL".
+
+Examples:
+  # Interactive mode - browse the exports of an instance
+  %[1]s instances exports --instance-name my-app
+
+  # Output as JSON (useful for CI/CD or scripting)
+  %[1]s instances exports --instance-name my-app --json
+
+  # Write the exports to a .env file
+  %[1]s instances exports --instance-name my-app --format dotenv > .env
+
+  # Load the exports into the current shell
+  eval "$(%[1]s instances exports --instance-name my-app --format shell)"
+
+  # Write the raw value of a single export
+  %[1]s instances exports --instance-name my-app --field queueUrl`, cfg.CLIName),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runInstanceExports(cmd, confProvider, cfg)
+		},
+	}
+
+	prefix := cfg.EnvVarPrefix
+
+	exportsCmd.PersistentFlags().String(
+		flagInstanceID, "",
+		"The system-generated ID of the blueprint instance to show exports for. "+
+			"Leave empty if using --instance-name.",
+	)
+	confProvider.BindPFlag("instancesExportsInstanceID", exportsCmd.PersistentFlags().Lookup(flagInstanceID))
+	confProvider.BindEnvVar("instancesExportsInstanceID", prefix+"_INSTANCES_EXPORTS_INSTANCE_ID")
+
+	exportsCmd.PersistentFlags().String(
+		flagInstanceName, "",
+		"The user-defined unique name of the blueprint instance to show exports for. "+
+			"Leave empty if using --instance-id.",
+	)
+	confProvider.BindPFlag("instancesExportsInstanceName", exportsCmd.PersistentFlags().Lookup(flagInstanceName))
+	confProvider.BindEnvVar("instancesExportsInstanceName", prefix+"_INSTANCES_EXPORTS_INSTANCE_NAME")
+
+	exportsCmd.PersistentFlags().String(
+		"format", string(exportsui.OutputFormatText),
+		"The format to write exports in when running in non-interactive mode. "+
+			"One of: text, json, dotenv, shell. "+
+			"Setting a format other than text implies non-interactive mode.",
+	)
+	confProvider.BindPFlag("instancesExportsFormat", exportsCmd.PersistentFlags().Lookup("format"))
+	confProvider.BindEnvVar("instancesExportsFormat", prefix+"_INSTANCES_EXPORTS_FORMAT")
+
+	exportsCmd.PersistentFlags().String(
+		"field", "",
+		"The name of a single export to write the raw value of. "+
+			"Implies non-interactive mode and takes precedence over --format.",
+	)
+	confProvider.BindPFlag("instancesExportsField", exportsCmd.PersistentFlags().Lookup("field"))
+	confProvider.BindEnvVar("instancesExportsField", prefix+"_INSTANCES_EXPORTS_FIELD")
+
+	exportsCmd.PersistentFlags().Bool("json", false,
+		"Output the instance exports as JSON, equivalent to --format json. "+
+			"Implies non-interactive mode (no TUI).",
+	)
+	confProvider.BindPFlag("instancesExportsJson", exportsCmd.PersistentFlags().Lookup("json"))
+	confProvider.BindEnvVar("instancesExportsJson", prefix+"_INSTANCES_EXPORTS_JSON")
+
+	registerFlagCompletions(exportsCmd, confProvider, cfg)
+
+	instancesCmd.AddCommand(exportsCmd)
+}
+
+type exportsFlags struct {
+	instanceID            string
+	instanceIDIsDefault   bool
+	instanceName          string
+	instanceNameIsDefault bool
+	field                 string
+	format                string
+	jsonMode              bool
+}
+
+func readExportsFlags(confProvider *config.Provider) (exportsFlags, error) {
+	values := newConfigValues(confProvider)
+	instanceID, instanceIDIsDefault := confProvider.GetString("instancesExportsInstanceID")
+	instanceName, instanceNameIsDefault := confProvider.GetString("instancesExportsInstanceName")
+	field, _ := confProvider.GetString("instancesExportsField")
+	format, _ := confProvider.GetString("instancesExportsFormat")
+	jsonMode := values.bool("instancesExportsJson")
+
+	return exportsFlags{
+		instanceID:            instanceID,
+		instanceIDIsDefault:   instanceIDIsDefault,
+		instanceName:          instanceName,
+		instanceNameIsDefault: instanceNameIsDefault,
+		field:                 field,
+		format:                format,
+		jsonMode:              jsonMode,
+	}, values.err
+}
+
+func validateExportsFlags(flags exportsFlags) error {
+	return headless.Validate(headless.OneOf(
+		headless.Flag{
+			Name:      flagInstanceName,
+			Value:     flags.instanceName,
+			IsDefault: flags.instanceNameIsDefault,
+		},
+		headless.Flag{
+			Name:      flagInstanceID,
+			Value:     flags.instanceID,
+			IsDefault: flags.instanceIDIsDefault,
+		},
+	))
+}
+
+func resolveExportsFormat(flags exportsFlags) (exportsui.OutputFormat, error) {
+	if flags.jsonMode {
+		return exportsui.OutputFormatJSON, nil
+	}
+	return exportsui.ParseOutputFormat(flags.format)
+}
+
+func runInstanceExports(cmd *cobra.Command, confProvider *config.Provider, cfg *CLIConfig) error {
+	logger, handle, err := SetupLogger(cfg.CLIName)
+	if err != nil {
+		return err
+	}
+	defer handle.Close()
+
+	deployEngine, err := engine.Create(confProvider, logger)
+	if err != nil {
+		return err
+	}
+
+	flags, err := readExportsFlags(confProvider)
+	if err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+	format, formatErr := resolveExportsFormat(flags)
+	jsonMode := format == exportsui.OutputFormatJSON && flags.field == ""
+
+	if jsonMode {
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+	}
+
+	if err := errors.Join(formatErr, validateExportsFlags(flags)); err != nil {
+		if jsonMode {
+			jsonout.WriteJSON(os.Stdout, jsonout.NewErrorOutput(err))
+			return errExportsFailed
+		}
+		return err
+	}
+
+	if _, err := tea.LogToFile(fmt.Sprintf("%s-output.log", cfg.CLIName), "simple"); err != nil {
+		log.Fatal(err)
+	}
+
+	cmd.SilenceUsage = true
+
+	styles := stylespkg.NewStyles(
+		lipgloss.NewRenderer(os.Stdout),
+		cfg.Palette,
+	)
+	inTerminal := term.IsTerminal(int(os.Stdout.Fd()))
+	headlessMode := !inTerminal || format != exportsui.OutputFormatText || flags.field != ""
+
+	app, err := exportsui.NewExportsApp(exportsui.ExportsAppConfig{
+		Context:        cmd.Context(),
+		DeployEngine:   deployEngine,
+		Logger:         logger,
+		InstanceID:     flags.instanceID,
+		InstanceName:   flags.instanceName,
+		Field:          flags.field,
+		Format:         format,
+		Styles:         styles,
+		Headless:       headlessMode,
+		HeadlessWriter: os.Stdout,
+	})
+	if err != nil {
+		return err
+	}
+
+	finalModel, err := tea.NewProgram(app, newTUIProgramOptions(cmd.Context(), headlessMode)...).Run()
+	if err != nil {
+		return err
+	}
+	finalApp := finalModel.(exportsui.MainModel)
+
+	if finalApp.Error == nil {
+		return nil
+	}
+
+	if flags.field == "" && (format == exportsui.OutputFormatText || jsonMode) {
+		// The error has already been rendered in the TUI or written to the output stream.
+		cmd.SilenceErrors = true
+		return errExportsFailed
+	}
+
+	// Dotenv, shell and single field output are consumed by other programs,
+	// so the error is reported on stderr instead of the output stream.
+	return finalApp.Error
+}
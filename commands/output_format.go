@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
+)
+
+// Output formats supported by the --output flag of commands
+// that stream events from the deploy engine.
+const (
+	outputFormatText   = "text"
+	outputFormatJSON   = "json"
+	outputFormatNDJSON = "ndjson"
+)
+
+const outputFlagUsage = "The output format, one of \"text\", \"json\" or \"ndjson\". " +
+	"\"json\" is equivalent to --json. " +
+	"\"ndjson\" writes one JSON object per line for each event received from the deploy engine " +
+	"as it happens, followed by a final line with the same result object that is written with --json. " +
+	"Event lines have the fields \"event\" (the event type), \"operation\" (stage, deploy or destroy), " +
+	"\"id\" (the event ID) and \"data\" (the event data from the deploy engine). " +
+	"\"json\" and \"ndjson\" imply non-interactive mode."
+
+func validateOutputFormat(outputFormat string) error {
+	switch outputFormat {
+	case "", outputFormatText, outputFormatJSON, outputFormatNDJSON:
+		return nil
+	default:
+		return fmt.Errorf(
+			"invalid output format %q, must be one of %q, %q or %q",
+			outputFormat,
+			outputFormatText,
+			outputFormatJSON,
+			outputFormatNDJSON,
+		)
+	}
+}
+
+// isJSONOutputFormat returns true when the output format produces
+// machine-readable JSON output.
+func isJSONOutputFormat(outputFormat string) bool {
+	return outputFormat == outputFormatJSON || outputFormat == outputFormatNDJSON
+}
+
+// newNDJSONStream creates the stream that events are written to
+// when streaming NDJSON output, this is nil for other output formats.
+func newNDJSONStream(w io.Writer, ndjsonMode bool) *jsonout.EventStream {
+	if !ndjsonMode {
+		return nil
+	}
+	return jsonout.NewEventStream(w)
+}
+
+// writeJSONError writes an error as JSON output, the error is written
+// as a single line when streaming NDJSON output.
+func writeJSONError(w io.Writer, err error, ndjsonMode bool) {
+	output := jsonout.NewErrorOutput(err)
+	if ndjsonMode {
+		jsonout.WriteJSONLine(w, output)
+		return
+	}
+	jsonout.WriteJSON(w, output)
+}
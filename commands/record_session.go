@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"github.com/newstack-cloud/deploy-cli-sdk/config"
+	"github.com/spf13/cobra"
+)
+
+const flagRecordSession = "record-session"
+
+// registerRecordSessionFlag registers a hidden global --record-session flag
+// on the root command for commands that interact with the deploy engine.
+// The flag is registered once no matter how many commands call this.
+//
+// When set, all deploy engine calls and stream events are recorded to the
+// provided file so that the session can be attached to a bug report
+// and played back with engine.Replayer, see engine.Create.
+// Secret values are redacted in the session and the file is only
+// readable by the current user.
+func registerRecordSessionFlag(rootCmd *cobra.Command, confProvider *config.Provider, cfg *CLIConfig) {
+	if rootCmd.PersistentFlags().Lookup(flagRecordSession) != nil {
+		return
+	}
+
+	rootCmd.PersistentFlags().String(
+		flagRecordSession, "",
+		"Record all deploy engine calls, responses and stream events "+
+			"to the provided file as newline-delimited JSON, with secret values redacted.",
+	)
+	rootCmd.PersistentFlags().MarkHidden(flagRecordSession)
+	confProvider.BindPFlag("engineRecordSession", rootCmd.PersistentFlags().Lookup(flagRecordSession))
+	confProvider.BindEnvVar("engineRecordSession", cfg.EnvVarPrefix+"_RECORD_SESSION")
+}
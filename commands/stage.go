@@ -5,15 +5,19 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
 	"github.com/newstack-cloud/deploy-cli-sdk/config"
+	"github.com/newstack-cloud/deploy-cli-sdk/consts"
 	"github.com/newstack-cloud/deploy-cli-sdk/engine"
 	"github.com/newstack-cloud/deploy-cli-sdk/headless"
-	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
+	"github.com/newstack-cloud/deploy-cli-sdk/planfile"
 	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
 	"github.com/newstack-cloud/deploy-cli-sdk/tui/driftui"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/shared"
 	"github.com/newstack-cloud/deploy-cli-sdk/tui/stageui"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -32,15 +36,21 @@ type stageFlags struct {
 	destroy                bool
 	skipDriftCheck         bool
 	jsonMode               bool
+	ndjsonMode             bool
+	outputFormat           string
+	outFile                string
 }
 
-func readStageFlags(confProvider *config.Provider) stageFlags {
+func readStageFlags(confProvider *config.Provider) (stageFlags, error) {
+	values := newConfigValues(confProvider)
 	blueprintFile, isDefault := confProvider.GetString("stageBlueprintFile")
 	instanceID, instanceIDIsDefault := confProvider.GetString("stageInstanceID")
 	instanceName, instanceNameIsDefault := confProvider.GetString("stageInstanceName")
-	destroy, _ := confProvider.GetBool("stageDestroy")
-	skipDriftCheck, _ := confProvider.GetBool("stageSkipDriftCheck")
-	jsonMode, _ := confProvider.GetBool("stageJson")
+	destroy := values.bool("stageDestroy")
+	skipDriftCheck := values.bool("stageSkipDriftCheck")
+	jsonMode := values.bool("stageJson")
+	outputFormat, _ := confProvider.GetString("stageOutputFormat")
+	outFile, _ := confProvider.GetString("stageOut")
 
 	return stageFlags{
 		blueprintFile:          blueprintFile,
@@ -51,11 +61,28 @@ func readStageFlags(confProvider *config.Provider) stageFlags {
 		instanceNameIsDefault:  instanceNameIsDefault,
 		destroy:                destroy,
 		skipDriftCheck:         skipDriftCheck,
-		jsonMode:               jsonMode,
-	}
+		jsonMode:               jsonMode || isJSONOutputFormat(outputFormat),
+		ndjsonMode:             outputFormat == outputFormatNDJSON,
+		outputFormat:           outputFormat,
+		outFile:                outFile,
+	}, values.err
 }
 
 func validateStageFlags(flags stageFlags) error {
+	if err := validateOutputFormat(flags.outputFormat); err != nil {
+		return err
+	}
+	if flags.outFile != "" {
+		if flags.destroy {
+			return errors.New("--out can not be used with --destroy, plan files can only be created for deployments")
+		}
+		if shared.BlueprintSourceFromPath(flags.blueprintFile) != consts.BlueprintSourceFile {
+			return errors.New("--out requires a local blueprint file")
+		}
+		if flags.instanceName == "" && flags.instanceID == "" {
+			return errors.New("--out requires --instance-name or --instance-id, plan files are created for a named instance")
+		}
+	}
 	if !flags.destroy {
 		return nil
 	}
@@ -102,11 +129,38 @@ func runStageTUI(
 		}
 	}
 
-	preflightModel := createPreflight(cmd.Context(), cfg, confProvider, "stage", styles, headlessMode, flags.jsonMode)
+	preflightModel, err := createPreflight(cmd.Context(), cfg, confProvider, "stage", styles, headlessMode, flags.jsonMode)
+	if err != nil {
+		return err
+	}
 
 	// Loaded after the pre-command step so it picks up the deploy configuration
 	// artifacts written by the pre-command step, if any.
-	operationConfig, err := config.LoadOperationConfig(confProvider)
+	operationConfig, variableOverrides, err := loadOperationConfigWithOverrides(confProvider, "stage")
+	if err != nil {
+		return err
+	}
+
+	if !flags.destroy {
+		var unsavedVariables []string
+		operationConfig, unsavedVariables, err = resolveMissingVariables(
+			cmd, flags.blueprintFile, operationConfig, confProvider, styles, headlessMode, logger,
+		)
+		if err == nil && flags.outFile != "" && len(unsavedVariables) > 0 {
+			// The digest of the operation config in the plan would include
+			// values that deploy --plan has no way of loading.
+			err = unsavedPlanVariablesError(unsavedVariables)
+		}
+		if err != nil {
+			if flags.jsonMode {
+				writeJSONError(os.Stdout, err, flags.ndjsonMode)
+				return errStagingFailed
+			}
+			return err
+		}
+	}
+
+	streamReconnect, err := loadStreamReconnectPolicy(confProvider)
 	if err != nil {
 		return err
 	}
@@ -125,8 +179,12 @@ func runStageTUI(
 		Headless:               headlessMode,
 		HeadlessWriter:         os.Stdout,
 		JSONMode:               flags.jsonMode,
+		NDJSONStream:           newNDJSONStream(os.Stdout, flags.ndjsonMode),
+		EngineContext:          confProvider.ActiveContextName(),
 		Preflight:              preflightModel,
+		Variables:              variableOverridesOutput(variableOverrides, flags.blueprintFile),
 		OperationConfig:        operationConfig,
+		StreamReconnect:        streamReconnect,
 	})
 	if err != nil {
 		return err
@@ -143,6 +201,52 @@ func runStageTUI(
 		return errStagingFailed
 	}
 
+	if flags.outFile != "" {
+		if err := writeStagePlan(&finalApp, flags, operationConfig); err != nil {
+			if flags.jsonMode {
+				// The staging result has already been written to stdout as JSON,
+				// so the plan file error is reported on stderr.
+				fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+				return errStagingFailed
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeStagePlan(
+	finalApp *stageui.MainModel,
+	flags stageFlags,
+	operationConfig *types.BlueprintOperationConfig,
+) error {
+	result := finalApp.Result()
+	if result == nil {
+		return errors.New("staging did not complete, no plan file was written")
+	}
+	if result.Destroy {
+		return errors.New("plan files can only be created for deployments, no plan file was written")
+	}
+
+	plan, err := planfile.New(planfile.Input{
+		Stage:           result.Output,
+		InstanceState:   result.InstanceState,
+		BlueprintFile:   result.BlueprintFile,
+		OperationConfig: operationConfig,
+		CreatedAt:       time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := planfile.Write(flags.outFile, plan); err != nil {
+		return err
+	}
+
+	if !flags.jsonMode {
+		fmt.Fprintf(os.Stdout, "\nPlan written to %s\n", flags.outFile)
+	}
 	return nil
 }
 
@@ -168,9 +272,18 @@ Examples:
   # Stage changes with JSON output
   %[1]s stage --instance-name my-app --json
 
+  # Stream staging events as NDJSON, one JSON object per line
+  %[1]s stage --instance-name my-app --output ndjson
+
   # Stage changes for an existing instance by ID
   %[1]s stage --instance-id abc123
 
+  # Stage changes with blueprint variables set on the command line
+  %[1]s stage --instance-name my-app --var-file vars.yaml --var replicas=3 --context-var environment=staging
+
+  # Stage changes and write a plan file to deploy with "deploy --plan"
+  %[1]s stage --instance-name my-app --out plan.json
+
   # Stage changes for destroying an instance
   %[1]s stage --instance-name my-app --destroy`, cfg.CLIName),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -185,7 +298,11 @@ Examples:
 				return err
 			}
 
-			flags := readStageFlags(confProvider)
+			flags, err := readStageFlags(confProvider)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
 
 			if flags.jsonMode {
 				cmd.SilenceUsage = true
@@ -194,7 +311,7 @@ Examples:
 
 			if err := validateStageFlags(flags); err != nil {
 				if flags.jsonMode {
-					jsonout.WriteJSON(os.Stdout, jsonout.NewErrorOutput(err))
+					writeJSONError(os.Stdout, err, flags.ndjsonMode)
 					return errStagingFailed
 				}
 				return err
@@ -254,5 +371,25 @@ Examples:
 	confProvider.BindPFlag("stageJson", stageCmd.PersistentFlags().Lookup("json"))
 	confProvider.BindEnvVar("stageJson", prefix+"_STAGE_JSON")
 
+	stageCmd.PersistentFlags().String("output", outputFormatText, outputFlagUsage)
+	confProvider.BindPFlag("stageOutputFormat", stageCmd.PersistentFlags().Lookup("output"))
+	confProvider.BindEnvVar("stageOutputFormat", prefix+"_STAGE_OUTPUT")
+
+	stageCmd.PersistentFlags().String("out", "",
+		"Write a plan file to the given path after staging completes. "+
+			"The plan file can be deployed with \"deploy --plan\", which will refuse to deploy "+
+			"if the blueprint file, deploy configuration or instance have changed since staging. "+
+			"Requires a local blueprint file and --instance-name or --instance-id. "+
+			"Blueprint variables entered at the prompt must be saved to the deploy config file "+
+			"to write a plan file, as secret variables are never saved they must be provided with --var or --var-file.",
+	)
+	confProvider.BindPFlag("stageOut", stageCmd.PersistentFlags().Lookup("out"))
+	confProvider.BindEnvVar("stageOut", prefix+"_STAGE_OUT")
+
+	registerVariableOverrideFlags(stageCmd, confProvider, "stage", prefix+"_STAGE")
+
+	registerFlagCompletions(stageCmd, confProvider, cfg)
+
+	registerRecordSessionFlag(rootCmd, confProvider, cfg)
 	rootCmd.AddCommand(stageCmd)
 }
@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -40,8 +39,18 @@ func SetupStateCommand(rootCmd *cobra.Command, confProvider *config.Provider, cf
 	confProvider.BindPFlag("stateEngineConfigFile", stateCmd.PersistentFlags().Lookup("engine-config-file"))
 	confProvider.BindEnvVar("stateEngineConfigFile", prefix+"_STATE_ENGINE_CONFIG_FILE")
 
+	stateCmd.PersistentFlags().String(
+		"passphrase-file", "",
+		"Path to a file containing the passphrase used to encrypt or decrypt exports. "+
+			"The passphrase can also be set with the "+prefix+"_STATE_PASSPHRASE environment variable "+
+			"or entered when prompted.",
+	)
+	confProvider.BindPFlag("statePassphraseFile", stateCmd.PersistentFlags().Lookup("passphrase-file"))
+	confProvider.BindEnvVar("statePassphraseFile", prefix+"_STATE_PASSPHRASE_FILE")
+
 	setupStateImportCommand(stateCmd, confProvider, cfg)
 	setupStateExportCommand(stateCmd, confProvider, cfg)
+	setupStateKeygenCommand(stateCmd, cfg)
 
 	rootCmd.AddCommand(stateCmd)
 }
@@ -51,22 +60,56 @@ type stateImportFlags struct {
 	filePathIsDefault bool
 	engineConfigFile  string
 	jsonMode          bool
+	batchSize         int
+	batchSizeErr      error
+	identityFile      string
+	encryption        stateEncryptionFlags
+	onConflict        stateio.ConflictStrategy
+	onConflictErr     error
+	dryRun            bool
 }
 
-func readStateImportFlags(confProvider *config.Provider) stateImportFlags {
+func readStateImportFlags(confProvider *config.Provider, cfg *CLIConfig) (stateImportFlags, error) {
+	values := newConfigValues(confProvider)
 	filePath, filePathIsDefault := confProvider.GetString("stateImportFile")
 	engineConfigFile, _ := confProvider.GetString("stateEngineConfigFile")
-	jsonMode, _ := confProvider.GetBool("stateImportJson")
+	jsonMode := values.bool("stateImportJson")
+	batchSize, _, batchSizeErr := confProvider.GetInt32E("stateImportBatchSize")
+	identityFile, _ := confProvider.GetString("stateImportIdentityFile")
+	onConflictFlag, _ := confProvider.GetString("stateImportOnConflict")
+	// Instances are only compared with the state when a conflict strategy is set.
+	var onConflict stateio.ConflictStrategy
+	var onConflictErr error
+	if onConflictFlag != "" {
+		onConflict, onConflictErr = stateio.ParseConflictStrategy(onConflictFlag)
+	}
+	dryRun := values.bool("stateImportDryRun")
 
 	return stateImportFlags{
 		filePath:          filePath,
 		filePathIsDefault: filePathIsDefault,
 		engineConfigFile:  engineConfigFile,
 		jsonMode:          jsonMode,
-	}
+		batchSize:         int(batchSize),
+		batchSizeErr:      batchSizeErr,
+		identityFile:      identityFile,
+		encryption:        readStateEncryptionFlags(confProvider, cfg),
+		onConflict:        onConflict,
+		onConflictErr:     onConflictErr,
+		dryRun:            dryRun,
+	}, values.err
 }
 
 func validateStateImportFlags(flags stateImportFlags) error {
+	if flags.onConflictErr != nil {
+		return flags.onConflictErr
+	}
+	if flags.batchSizeErr != nil {
+		return flags.batchSizeErr
+	}
+	if flags.batchSize < 0 {
+		return fmt.Errorf("--batch-size must be a positive number, got %d", flags.batchSize)
+	}
 	if flags.jsonMode && (flags.filePathIsDefault || flags.filePath == "") {
 		return fmt.Errorf("--file is required when --json is set")
 	}
@@ -97,6 +140,11 @@ func runStateImportTUI(cmd *cobra.Command, flags stateImportFlags, cfg *CLIConfi
 		return err
 	}
 
+	decryption, err := resolveImportDecryption(flags, canPromptForPassphrase(flags.jsonMode))
+	if err != nil {
+		return err
+	}
+
 	styles := stylespkg.NewStyles(
 		lipgloss.NewRenderer(os.Stdout),
 		cfg.Palette,
@@ -111,6 +159,10 @@ func runStateImportTUI(cmd *cobra.Command, flags stateImportFlags, cfg *CLIConfi
 		Headless:       headlessMode,
 		HeadlessWriter: os.Stdout,
 		JSONMode:       flags.jsonMode,
+		BatchSize:      flags.batchSize,
+		Decryption:     decryption,
+		OnConflict:     flags.onConflict,
+		DryRun:         flags.dryRun,
 	})
 	if err != nil {
 		return err
@@ -136,8 +188,29 @@ func setupStateImportCommand(stateCmd *cobra.Command, confProvider *config.Provi
 		Short: "Import state from a file",
 		Long: fmt.Sprintf(`Import deploy engine state from a local file or remote object storage.
 
-The input file must be a JSON array of blueprint instances. This format is
+The input file must be a JSON array of blueprint instances or an export
+envelope written with "state export --envelope", optionally compressed
+with gzip or zstd. This format is
 backend-agnostic and works with any storage backend (memfile, PostgreSQL, etc.).
+The checksums in an envelope are verified and exports written with an
+incompatible format version are rejected.
+The file is streamed and instances are saved in batches, so very large
+exports can be imported without loading the whole file into memory.
+
+Encrypted exports are detected and decrypted automatically. The passphrase
+is read from --passphrase-file or the %[2]s_STATE_PASSPHRASE environment
+variable, or prompted for in a terminal. Exports encrypted for recipients
+are decrypted with the identities in --identity-file.
+
+By default, imported instances replace any existing instances without being
+compared with the state. With --on-conflict, instances that already exist
+in the state backend are compared with the imported state. Identical
+instances are left untouched and instances that differ are handled
+according to the strategy: "overwrite" replaces them, "skip" keeps the
+existing state and "fail" stops the import before any instances are saved.
+--dry-run reports whether each instance is new, identical or differs,
+with a summary of the resource, link and child differences, without
+saving anything.
 
 Examples:
   # Import state from a local file
@@ -146,6 +219,18 @@ Examples:
   # Import from S3
   %[1]s state import --file s3://my-bucket/state.json
 
+  # Save 500 instances at a time
+  %[1]s state import --file ./backup/state.json --batch-size 500
+
+  # Preview what an import would change without saving anything
+  %[1]s state import --file ./backup/state.json --dry-run
+
+  # Only import instances that do not already exist
+  %[1]s state import --file ./backup/state.json --on-conflict skip
+
+  # Import an export encrypted for a recipient
+  %[1]s state import --file ./backup/state.json.enc --identity-file ./state-key.txt
+
   # Import from GCS
   %[1]s state import --file gcs://my-bucket/state.json
 
@@ -153,11 +238,15 @@ Examples:
   %[1]s state import --file azureblob://my-container/state.json
 
   # Use deploy engine config to determine storage backend (flag inherited from state command)
-  %[1]s state --engine-config-file ~/.config/engine/config.json import --file ./state.json`, cfg.CLIName),
+  %[1]s state --engine-config-file ~/.config/engine/config.json import --file ./state.json`, cfg.CLIName, cfg.EnvVarPrefix),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cmd.SilenceUsage = true
 
-			flags := readStateImportFlags(confProvider)
+			flags, err := readStateImportFlags(confProvider, cfg)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
 
 			if flags.jsonMode {
 				cmd.SilenceUsage = true
@@ -191,6 +280,34 @@ Examples:
 	confProvider.BindPFlag("stateImportJson", importCmd.Flags().Lookup("json"))
 	confProvider.BindEnvVar("stateImportJson", prefix+"_STATE_IMPORT_JSON")
 
+	importCmd.Flags().Int(
+		"batch-size", stateio.DefaultImportBatchSize,
+		"Number of instances to save to the state backend at a time.",
+	)
+	confProvider.BindPFlag("stateImportBatchSize", importCmd.Flags().Lookup("batch-size"))
+	confProvider.BindEnvVar("stateImportBatchSize", prefix+"_STATE_IMPORT_BATCH_SIZE")
+
+	importCmd.Flags().String(
+		"identity-file", "",
+		"Path to a file of identities (one per line) used to decrypt exports encrypted for recipients.",
+	)
+	confProvider.BindPFlag("stateImportIdentityFile", importCmd.Flags().Lookup("identity-file"))
+	confProvider.BindEnvVar("stateImportIdentityFile", prefix+"_STATE_IMPORT_IDENTITY_FILE")
+
+	importCmd.Flags().String(
+		"on-conflict", "",
+		"How to handle instances that already exist with a different state (fail, skip or overwrite). "+
+			"When not set, existing instances are replaced without being compared with the imported state.",
+	)
+	confProvider.BindPFlag("stateImportOnConflict", importCmd.Flags().Lookup("on-conflict"))
+	confProvider.BindEnvVar("stateImportOnConflict", prefix+"_STATE_IMPORT_ON_CONFLICT")
+
+	importCmd.Flags().Bool("dry-run", false,
+		"Report what the import would change without saving anything.",
+	)
+	confProvider.BindPFlag("stateImportDryRun", importCmd.Flags().Lookup("dry-run"))
+	confProvider.BindEnvVar("stateImportDryRun", prefix+"_STATE_IMPORT_DRY_RUN")
+
 	stateCmd.AddCommand(importCmd)
 }
 
@@ -200,34 +317,51 @@ type stateExportFlags struct {
 	engineConfigFile  string
 	instanceFilters   []string
 	jsonMode          bool
+	envelope          bool
+	encrypt           bool
+	recipients        []string
+	encryption        stateEncryptionFlags
+	compression       stateio.Compression
+	compressionErr    error
 }
 
-func readStateExportFlags(confProvider *config.Provider) stateExportFlags {
+func readStateExportFlags(confProvider *config.Provider, cfg *CLIConfig) (stateExportFlags, error) {
+	values := newConfigValues(confProvider)
 	filePath, filePathIsDefault := confProvider.GetString("stateExportFile")
 	engineConfigFile, _ := confProvider.GetString("stateEngineConfigFile")
 	instancesFlag, _ := confProvider.GetString("stateExportInstances")
-	jsonMode, _ := confProvider.GetBool("stateExportJson")
-
-	var instanceFilters []string
-	if instancesFlag != "" {
-		for _, inst := range strings.Split(instancesFlag, ",") {
-			trimmed := strings.TrimSpace(inst)
-			if trimmed != "" {
-				instanceFilters = append(instanceFilters, trimmed)
-			}
-		}
+	jsonMode := values.bool("stateExportJson")
+	envelope := values.bool("stateExportEnvelope")
+	encrypt := values.bool("stateExportEncrypt")
+	recipientsFlag, _ := confProvider.GetString("stateExportRecipients")
+	compressFlag, _ := confProvider.GetString("stateExportCompress")
+
+	// Without --compress the compression is determined by the file extension.
+	var compression stateio.Compression
+	var compressionErr error
+	if compressFlag != "" {
+		compression, compressionErr = stateio.ParseCompression(compressFlag)
 	}
 
 	return stateExportFlags{
 		filePath:          filePath,
 		filePathIsDefault: filePathIsDefault,
 		engineConfigFile:  engineConfigFile,
-		instanceFilters:   instanceFilters,
+		instanceFilters:   splitCommaSeparated(instancesFlag),
 		jsonMode:          jsonMode,
-	}
+		envelope:          envelope,
+		encrypt:           encrypt,
+		recipients:        splitCommaSeparated(recipientsFlag),
+		encryption:        readStateEncryptionFlags(confProvider, cfg),
+		compression:       compression,
+		compressionErr:    compressionErr,
+	}, values.err
 }
 
 func validateStateExportFlags(flags stateExportFlags) error {
+	if flags.compressionErr != nil {
+		return flags.compressionErr
+	}
 	if flags.jsonMode && (flags.filePathIsDefault || flags.filePath == "") {
 		return fmt.Errorf("--file is required when --json is set")
 	}
@@ -246,6 +380,11 @@ func runStateExportTUI(cmd *cobra.Command, flags stateExportFlags, cfg *CLIConfi
 		return err
 	}
 
+	encryption, err := resolveExportEncryption(flags, canPromptForPassphrase(flags.jsonMode))
+	if err != nil {
+		return err
+	}
+
 	styles := stylespkg.NewStyles(
 		lipgloss.NewRenderer(os.Stdout),
 		cfg.Palette,
@@ -261,6 +400,10 @@ func runStateExportTUI(cmd *cobra.Command, flags stateExportFlags, cfg *CLIConfi
 		Headless:        headlessMode,
 		HeadlessWriter:  os.Stdout,
 		JSONMode:        flags.jsonMode,
+		Envelope:        flags.envelope,
+		Tool:            cfg.CLIName,
+		Encryption:      encryption,
+		Compression:     flags.compression,
 	})
 	if err != nil {
 		return err
@@ -288,6 +431,22 @@ func setupStateExportCommand(stateCmd *cobra.Command, confProvider *config.Provi
 
 The output file is a JSON array of blueprint instances. This format is
 backend-agnostic and can be imported into any storage backend (memfile, PostgreSQL, etc.).
+Instances are loaded a page at a time and streamed to the output file, which
+is only created once the export has completed.
+
+With --envelope, the instances are wrapped in an envelope that records the
+format version, when and where the export was created, the number of
+instances and checksums that are verified when the export is imported.
+
+Exports are compressed with gzip or zstd when the file name ends in
+".gz" or ".zst", or with the format set with --compress. Imports detect
+compressed files automatically.
+
+With --encrypt, the export is encrypted before it is written so resource
+data such as secrets and connection strings are never stored in plaintext.
+Exports are encrypted with a passphrase read from --passphrase-file or the
+%[2]s_STATE_PASSPHRASE environment variable (or prompted for in a terminal),
+or for the public keys set with --recipient (see "state keygen").
 
 Examples:
   # Export all instances to a local file
@@ -296,6 +455,18 @@ Examples:
   # Export specific instances by name or ID
   %[1]s state export --file ./backup/state.json --instances my-stack,inst-abc123
 
+  # Export with metadata and checksums
+  %[1]s state export --file ./backup/state.json --envelope
+
+  # Compress the export with zstd
+  %[1]s state export --file s3://my-bucket/state.json.zst
+
+  # Encrypt the export with a passphrase
+  %[1]s state export --file s3://my-bucket/state.json.enc --encrypt --passphrase-file ./passphrase.txt
+
+  # Encrypt the export for a recipient
+  %[1]s state export --file s3://my-bucket/state.json.enc --encrypt --recipient x25519:...
+
   # Export to S3
   %[1]s state export --file s3://my-bucket/state.json
 
@@ -306,11 +477,15 @@ Examples:
   %[1]s state export --file azureblob://my-container/state.json
 
   # Use deploy engine config to determine storage backend (flag inherited from state command)
-  %[1]s state --engine-config-file ~/.config/engine/config.json export --file ./state.json`, cfg.CLIName),
+  %[1]s state --engine-config-file ~/.config/engine/config.json export --file ./state.json`, cfg.CLIName, cfg.EnvVarPrefix),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cmd.SilenceUsage = true
 
-			flags := readStateExportFlags(confProvider)
+			flags, err := readStateExportFlags(confProvider, cfg)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
 
 			if flags.jsonMode {
 				cmd.SilenceUsage = true
@@ -351,5 +526,34 @@ Examples:
 	confProvider.BindPFlag("stateExportJson", exportCmd.Flags().Lookup("json"))
 	confProvider.BindEnvVar("stateExportJson", prefix+"_STATE_EXPORT_JSON")
 
+	exportCmd.Flags().Bool("envelope", false,
+		"Wrap the exported instances in an envelope with the format version, "+
+			"source, instance count and checksums that are verified on import.",
+	)
+	confProvider.BindPFlag("stateExportEnvelope", exportCmd.Flags().Lookup("envelope"))
+	confProvider.BindEnvVar("stateExportEnvelope", prefix+"_STATE_EXPORT_ENVELOPE")
+
+	exportCmd.Flags().Bool("encrypt", false,
+		"Encrypt the export with a passphrase, or for the public keys set with --recipient.",
+	)
+	confProvider.BindPFlag("stateExportEncrypt", exportCmd.Flags().Lookup("encrypt"))
+	confProvider.BindEnvVar("stateExportEncrypt", prefix+"_STATE_EXPORT_ENCRYPT")
+
+	exportCmd.Flags().String(
+		"recipient", "",
+		"Comma-separated list of public keys (x25519:...) to encrypt the export for, "+
+			"created with \"state keygen\".",
+	)
+	confProvider.BindPFlag("stateExportRecipients", exportCmd.Flags().Lookup("recipient"))
+	confProvider.BindEnvVar("stateExportRecipients", prefix+"_STATE_EXPORT_RECIPIENTS")
+
+	exportCmd.Flags().String(
+		"compress", "",
+		"Compression format for the export (gzip, zstd or none). "+
+			"Determined by the file extension (.gz, .zst) if not set.",
+	)
+	confProvider.BindPFlag("stateExportCompress", exportCmd.Flags().Lookup("compress"))
+	confProvider.BindEnvVar("stateExportCompress", prefix+"_STATE_EXPORT_COMPRESS")
+
 	stateCmd.AddCommand(exportCmd)
 }
@@ -0,0 +1,265 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/newstack-cloud/deploy-cli-sdk/config"
+	"github.com/newstack-cloud/deploy-cli-sdk/stateio"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// stateEncryptionFlags holds the flags shared by state export and import
+// for encrypted exports.
+// The passphrase itself is never accepted as a flag so it does not end up
+// in shell history or process listings, it is read from a file,
+// an environment variable or prompted for.
+type stateEncryptionFlags struct {
+	passphraseFile string
+	passphraseEnv  string
+}
+
+func readStateEncryptionFlags(confProvider *config.Provider, cfg *CLIConfig) stateEncryptionFlags {
+	passphraseFile, _ := confProvider.GetString("statePassphraseFile")
+	return stateEncryptionFlags{
+		passphraseFile: passphraseFile,
+		passphraseEnv:  cfg.EnvVarPrefix + "_STATE_PASSPHRASE",
+	}
+}
+
+// readPassphrase reads the passphrase from the passphrase file or
+// environment variable, returning nil when neither is set.
+func (f stateEncryptionFlags) readPassphrase() ([]byte, error) {
+	if f.passphraseFile != "" {
+		data, err := os.ReadFile(f.passphraseFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read passphrase file: %w", err)
+		}
+
+		passphrase := bytes.TrimRight(data, "\r\n")
+		if len(passphrase) == 0 {
+			return nil, fmt.Errorf("passphrase file %q is empty", f.passphraseFile)
+		}
+		return passphrase, nil
+	}
+
+	if passphrase := os.Getenv(f.passphraseEnv); passphrase != "" {
+		return []byte(passphrase), nil
+	}
+
+	return nil, nil
+}
+
+func resolveExportEncryption(
+	flags stateExportFlags,
+	canPrompt bool,
+) (*stateio.EncryptionOptions, error) {
+	if !flags.encrypt {
+		if len(flags.recipients) > 0 {
+			return nil, errors.New("--recipient can only be used with --encrypt")
+		}
+		return nil, nil
+	}
+
+	if len(flags.recipients) > 0 {
+		if flags.encryption.passphraseFile != "" {
+			return nil, errors.New(
+				"--passphrase-file can not be used with --recipient, " +
+					"an export is encrypted with a passphrase or for recipients",
+			)
+		}
+		return &stateio.EncryptionOptions{Recipients: flags.recipients}, nil
+	}
+
+	passphrase, err := flags.encryption.readPassphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	if passphrase == nil {
+		if !canPrompt {
+			return nil, fmt.Errorf(
+				"a passphrase is required for --encrypt, set --passphrase-file or the %s "+
+					"environment variable, or encrypt for recipients with --recipient",
+				flags.encryption.passphraseEnv,
+			)
+		}
+
+		passphrase, err = promptNewPassphrase()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &stateio.EncryptionOptions{Passphrase: passphrase}, nil
+}
+
+func resolveImportDecryption(
+	flags stateImportFlags,
+	canPrompt bool,
+) (*stateio.DecryptionOptions, error) {
+	decryption := &stateio.DecryptionOptions{}
+	if flags.identityFile != "" {
+		data, err := os.ReadFile(flags.identityFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read identity file: %w", err)
+		}
+
+		decryption.Identities, err = stateio.ParseIdentities(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid identity file %q: %w", flags.identityFile, err)
+		}
+	}
+
+	passphrase, err := flags.encryption.readPassphrase()
+	if err != nil {
+		return nil, err
+	}
+	decryption.Passphrase = passphrase
+
+	if passphrase == nil && canPrompt && needsPassphrase(flags.filePath) {
+		decryption.Passphrase, err = promptPassphrase("Passphrase: ")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return decryption, nil
+}
+
+// needsPassphrase reads the header of the input file to determine whether
+// it is encrypted with a passphrase.
+// Errors are ignored so they are reported by the import itself.
+func needsPassphrase(filePath string) bool {
+	var input io.ReadCloser
+	var err error
+	if stateio.IsRemoteFile(filePath) {
+		input, err = stateio.OpenRemoteFile(context.Background(), filePath, nil)
+	} else {
+		input, err = os.Open(filePath)
+	}
+	if err != nil {
+		return false
+	}
+	defer input.Close()
+
+	info, err := stateio.InspectEncryption(input)
+	return err == nil && info.Passphrase
+}
+
+// canPromptForPassphrase determines whether a passphrase can be read
+// interactively from the terminal.
+func canPromptForPassphrase(jsonMode bool) bool {
+	return !jsonMode && term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+func promptNewPassphrase() ([]byte, error) {
+	passphrase, err := promptPassphrase("Enter passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+
+	confirmed, err := promptPassphrase("Confirm passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(passphrase, confirmed) {
+		return nil, errors.New("passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+func promptPassphrase(prompt string) ([]byte, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read passphrase: %w", err)
+	}
+
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase must not be empty")
+	}
+	return passphrase, nil
+}
+
+func splitCommaSeparated(value string) []string {
+	var values []string
+	for _, part := range strings.Split(value, ",") {
+		trimmed := strings.TrimSpace(part)
+		if trimmed != "" {
+			values = append(values, trimmed)
+		}
+	}
+	return values
+}
+
+func setupStateKeygenCommand(stateCmd *cobra.Command, cfg *CLIConfig) {
+	keygenCmd := &cobra.Command{
+		Use:   "keygen",
+		Short: "Generate a key pair for encrypted exports",
+		Long: fmt.Sprintf(`Generate an identity (private key) and recipient (public key) for encrypted exports.
+
+Exports encrypted for the recipient with "state export --encrypt --recipient"
+can only be imported with the identity, using "state import --identity-file".
+The identity is written to the output file (or stdout) and the recipient
+is written to stderr.
+
+Examples:
+  # Generate an identity file and share the printed recipient
+  %[1]s state keygen --output ./state-key.txt
+
+  # Export for the recipient and import with the identity
+  %[1]s state export --file s3://my-bucket/state.json --encrypt --recipient x25519:...
+  %[1]s state import --file s3://my-bucket/state.json --identity-file ./state-key.txt`, cfg.CLIName),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			outputPath, _ := cmd.Flags().GetString("output")
+			return runStateKeygen(outputPath)
+		},
+	}
+
+	keygenCmd.Flags().String("output", "", "Path to write the identity file to, stdout is used if not set.")
+
+	stateCmd.AddCommand(keygenCmd)
+}
+
+func runStateKeygen(outputPath string) error {
+	identity, err := stateio.GenerateX25519Identity()
+	if err != nil {
+		return fmt.Errorf("failed to generate identity: %w", err)
+	}
+
+	identityFile := fmt.Sprintf(
+		"# created: %s\n# recipient: %s\n%s\n",
+		time.Now().UTC().Format(time.RFC3339),
+		identity.Recipient(),
+		identity.String(),
+	)
+
+	if outputPath == "" {
+		fmt.Fprint(os.Stdout, identityFile)
+	} else {
+		file, err := os.OpenFile(outputPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to create identity file: %w", err)
+		}
+		defer file.Close()
+
+		if _, err := file.WriteString(identityFile); err != nil {
+			return fmt.Errorf("failed to write identity file: %w", err)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Recipient: %s\n", identity.Recipient())
+	return nil
+}
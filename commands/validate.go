@@ -0,0 +1,226 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/newstack-cloud/deploy-cli-sdk/config"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/validateui"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"golang.org/x/term"
+)
+
+var errValidateFailed = errors.New("validate failed")
+
+type validateFlags struct {
+	blueprintFile          string
+	isDefaultBlueprintFile bool
+	transformSpec          *bool
+	validateAfterTransform *bool
+	jsonMode               bool
+}
+
+func readValidateFlags(confProvider *config.Provider) (validateFlags, error) {
+	values := newConfigValues(confProvider)
+	blueprintFile, isDefault := confProvider.GetString("validateBlueprintFile")
+	jsonMode := values.bool("validateJson")
+
+	return validateFlags{
+		blueprintFile:          blueprintFile,
+		isDefaultBlueprintFile: isDefault,
+		transformSpec:          values.optionalBool("validateTransformSpec"),
+		validateAfterTransform: values.optionalBool("validateValidateAfterTransform"),
+		jsonMode:               jsonMode,
+	}, values.err
+}
+
+func runValidateTUI(
+	cmd *cobra.Command,
+	flags validateFlags,
+	cfg *CLIConfig,
+	confProvider *config.Provider,
+	deployEngine engine.DeployEngine,
+	logger *zap.Logger,
+) error {
+	if _, err := tea.LogToFile(fmt.Sprintf("%s-output.log", cfg.CLIName), "simple"); err != nil {
+		log.Fatal(err)
+	}
+
+	cmd.SilenceUsage = true
+
+	styles := stylespkg.NewStyles(
+		lipgloss.NewRenderer(os.Stdout),
+		cfg.Palette,
+	)
+	inTerminal := term.IsTerminal(int(os.Stdout.Fd()))
+	headlessMode := !inTerminal || flags.jsonMode
+
+	if cfg.PreCommandStep != nil {
+		if err := RunPreCommandStep(cmd.Context(), cfg.PreCommandStep, confProvider, "validate", styles, headlessMode, os.Stdout); err != nil {
+			return err
+		}
+	}
+
+	preflightModel, err := createPreflight(cmd.Context(), cfg, confProvider, "validate", styles, headlessMode, flags.jsonMode)
+	if err != nil {
+		return err
+	}
+
+	// Loaded after the pre-command step so it picks up the deploy configuration
+	// artifacts written by the pre-command step, if any.
+	operationConfig, variableOverrides, err := loadOperationConfigWithOverrides(confProvider, "validate")
+	if err != nil {
+		return err
+	}
+
+	streamReconnect, err := loadStreamReconnectPolicy(confProvider)
+	if err != nil {
+		return err
+	}
+
+	app, err := validateui.NewValidateApp(validateui.ValidateAppConfig{
+		Context:                cmd.Context(),
+		Engine:                 deployEngine,
+		Logger:                 logger,
+		BlueprintFile:          flags.blueprintFile,
+		IsDefaultBlueprintFile: flags.isDefaultBlueprintFile,
+		Styles:                 styles,
+		Headless:               headlessMode,
+		HeadlessWriter:         os.Stdout,
+		JSONMode:               flags.jsonMode,
+		Preflight:              preflightModel,
+		TransformSpec:          flags.transformSpec,
+		ValidateAfterTransform: flags.validateAfterTransform,
+		Variables:              variableOverridesOutput(variableOverrides, flags.blueprintFile),
+		OperationConfig:        operationConfig,
+		StreamReconnect:        streamReconnect,
+	})
+	if err != nil {
+		return err
+	}
+
+	finalModel, err := tea.NewProgram(app, newTUIProgramOptions(cmd.Context(), headlessMode)...).Run()
+	if err != nil {
+		return err
+	}
+	finalApp := finalModel.(validateui.MainModel)
+
+	if errors.Is(finalApp.Error, validateui.ErrValidationFailed) {
+		cmd.SilenceErrors = true
+		return &ExitError{Code: ExitCodeValidationFailed, Err: validateui.ErrValidationFailed}
+	}
+
+	if finalApp.Error != nil {
+		cmd.SilenceErrors = true
+		return errValidateFailed
+	}
+
+	return nil
+}
+
+// SetupValidateCommand registers a validate command on the root command,
+// parameterized by CLIConfig for branding and defaults.
+//
+// When validation completes with error diagnostics, the command returns an
+// ExitError with ExitCodeValidationFailed, see ExitCode.
+func SetupValidateCommand(rootCmd *cobra.Command, confProvider *config.Provider, cfg *CLIConfig) {
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate a blueprint",
+		Long: fmt.Sprintf(`Validates a blueprint file using the deploy engine, reporting errors,
+warnings and informational diagnostics along with suggested actions to resolve them.
+
+The command exits with code %[3]d when the blueprint has validation errors and
+with code %[4]d when the validation could not be carried out.
+
+Examples:
+  # Interactive mode - select the blueprint to validate
+  %[1]s validate
+
+  # Validate a specific blueprint file
+  %[1]s validate --blueprint-file ./%[2]s
+
+  # Validate the blueprint as written without applying transformers
+  %[1]s validate --transform-spec=false
+
+  # Validate with JSON output (useful for CI/CD or scripting)
+  %[1]s validate --blueprint-file ./%[2]s --json`,
+			cfg.CLIName, cfg.DefaultBlueprintFile, ExitCodeValidationFailed, ExitCodeFailure),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger, handle, err := SetupLogger(cfg.CLIName)
+			if err != nil {
+				return err
+			}
+			defer handle.Close()
+
+			deployEngine, err := engine.Create(confProvider, logger)
+			if err != nil {
+				return err
+			}
+
+			flags, err := readValidateFlags(confProvider)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+
+			if flags.jsonMode {
+				cmd.SilenceUsage = true
+				cmd.SilenceErrors = true
+			}
+
+			return runValidateTUI(cmd, flags, cfg, confProvider, deployEngine, logger)
+		},
+	}
+
+	prefix := cfg.EnvVarPrefix
+
+	validateCmd.PersistentFlags().String(
+		"blueprint-file", cfg.DefaultBlueprintFile,
+		"The blueprint file to validate. "+
+			"This can be a local file, a public URL or a path to a file in an object storage bucket. "+
+			"Local files can be specified as a relative or absolute path to the file. "+
+			"Public URLs must start with https:// and represent a valid URL to a blueprint file. "+
+			"Object storage bucket files must be specified in the format of {scheme}://{bucket-name}/{object-path}, "+
+			"where {scheme} is one of the following: s3, gcs, azureblob.",
+	)
+	confProvider.BindPFlag("validateBlueprintFile", validateCmd.PersistentFlags().Lookup("blueprint-file"))
+	confProvider.BindEnvVar("validateBlueprintFile", prefix+"_VALIDATE_BLUEPRINT_FILE")
+
+	validateCmd.PersistentFlags().Bool("transform-spec", true,
+		"Apply transformers to the blueprint before validation. "+
+			"When neither this flag nor --validate-after-transform is set, "+
+			"you will be prompted for both options in interactive mode.",
+	)
+	confProvider.BindPFlag("validateTransformSpec", validateCmd.PersistentFlags().Lookup("transform-spec"))
+	confProvider.BindEnvVar("validateTransformSpec", prefix+"_VALIDATE_TRANSFORM_SPEC")
+
+	validateCmd.PersistentFlags().Bool("validate-after-transform", false,
+		"Validate the expanded blueprint produced by transformers in addition to the original blueprint. "+
+			"Only applicable when --transform-spec is enabled.",
+	)
+	confProvider.BindPFlag(
+		"validateValidateAfterTransform",
+		validateCmd.PersistentFlags().Lookup("validate-after-transform"),
+	)
+	confProvider.BindEnvVar("validateValidateAfterTransform", prefix+"_VALIDATE_VALIDATE_AFTER_TRANSFORM")
+
+	validateCmd.PersistentFlags().Bool("json", false,
+		"Output result as a single JSON object when the operation completes. "+
+			"Implies non-interactive mode (no TUI, no streaming text output).",
+	)
+	confProvider.BindPFlag("validateJson", validateCmd.PersistentFlags().Lookup("json"))
+	confProvider.BindEnvVar("validateJson", prefix+"_VALIDATE_JSON")
+
+	registerVariableOverrideFlags(validateCmd, confProvider, "validate", prefix+"_VALIDATE")
+
+	registerRecordSessionFlag(rootCmd, confProvider, cfg)
+	rootCmd.AddCommand(validateCmd)
+}
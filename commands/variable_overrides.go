@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/deploy-cli-sdk/config"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/shared"
+	"github.com/spf13/cobra"
+)
+
+// registerVariableOverrideFlags registers the --var, --var-file and
+// --context-var flags on a command, the values are bound to config keys
+// prefixed with keyPrefix (e.g. "stageVars") and environment variables
+// prefixed with envVarPrefix (e.g. "CELERITY_CLI_STAGE_VARS").
+// As values can contain commas, the environment variables hold one
+// item per line or a JSON array of strings, see config.Provider.GetStringArray.
+func registerVariableOverrideFlags(
+	cmd *cobra.Command,
+	confProvider *config.Provider,
+	keyPrefix string,
+	envVarPrefix string,
+) {
+	cmd.PersistentFlags().StringArray("var", []string{},
+		"Set a blueprint variable in the form name=value, can be repeated. "+
+			"Takes precedence over values from --var-file and the deploy config file. "+
+			"Values are coerced to booleans and numbers where possible, "+
+			"wrap the value in double quotes to keep it as a string (e.g. --var 'version=\"1.0\"'). "+
+			"The "+envVarPrefix+"_VARS environment variable holds one variable per line or a JSON array.",
+	)
+	confProvider.BindPFlag(keyPrefix+"Vars", cmd.PersistentFlags().Lookup("var"))
+	confProvider.BindEnvVar(keyPrefix+"Vars", envVarPrefix+"_VARS")
+
+	cmd.PersistentFlags().StringArray("var-file", []string{},
+		"A JSON or YAML file that maps blueprint variable names to values, can be repeated. "+
+			"Values in later files take precedence over values in earlier files "+
+			"and values in the deploy config file. "+
+			"The "+envVarPrefix+"_VAR_FILES environment variable holds one file per line or a JSON array.",
+	)
+	confProvider.BindPFlag(keyPrefix+"VarFiles", cmd.PersistentFlags().Lookup("var-file"))
+	confProvider.BindEnvVar(keyPrefix+"VarFiles", envVarPrefix+"_VAR_FILES")
+
+	cmd.PersistentFlags().StringArray("context-var", []string{},
+		"Set a context variable in the form name=value, can be repeated. "+
+			"Takes precedence over context variables in the deploy config file. "+
+			"The "+envVarPrefix+"_CONTEXT_VARS environment variable holds one variable per line or a JSON array.",
+	)
+	confProvider.BindPFlag(keyPrefix+"ContextVars", cmd.PersistentFlags().Lookup("context-var"))
+	confProvider.BindEnvVar(keyPrefix+"ContextVars", envVarPrefix+"_CONTEXT_VARS")
+}
+
+// loadOperationConfigWithOverrides loads the blueprint operation config from the
+// deploy config file and merges the variable overrides provided with the
+// flags registered by registerVariableOverrideFlags on top of it.
+func loadOperationConfigWithOverrides(
+	confProvider *config.Provider,
+	keyPrefix string,
+) (*types.BlueprintOperationConfig, []config.VariableOverride, error) {
+	operationConfig, err := config.LoadOperationConfig(confProvider)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	overrides, err := loadVariableOverrides(confProvider, keyPrefix)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return config.ApplyVariableOverrides(operationConfig, overrides), overrides, nil
+}
+
+func loadVariableOverrides(
+	confProvider *config.Provider,
+	keyPrefix string,
+) ([]config.VariableOverride, error) {
+	varFiles, _, err := confProvider.GetStringArray(keyPrefix + "VarFiles")
+	if err != nil {
+		return nil, err
+	}
+	vars, _, err := confProvider.GetStringArray(keyPrefix + "Vars")
+	if err != nil {
+		return nil, err
+	}
+	contextVars, _, err := confProvider.GetStringArray(keyPrefix + "ContextVars")
+	if err != nil {
+		return nil, err
+	}
+
+	return config.LoadVariableOverrides(config.VariableOverrideOptions{
+		VarFiles:    varFiles,
+		Vars:        vars,
+		ContextVars: contextVars,
+	})
+}
+
+// variableOverridesOutput converts variable overrides to the form recorded
+// in JSON output, with the values of secret variables redacted.
+// Variables declared as secret in the provided local blueprint files
+// are redacted along with variables that have names of secrets.
+func variableOverridesOutput(
+	overrides []config.VariableOverride,
+	blueprintFiles ...string,
+) []jsonout.VariableValue {
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	secretVariables := shared.FindSecretVariables(blueprintFiles...)
+	values := make([]jsonout.VariableValue, 0, len(overrides))
+	for _, override := range overrides {
+		if override.Kind == config.VariableKindBlueprint && secretVariables[override.Name] {
+			override.Secret = true
+		}
+		values = append(values, jsonout.VariableValue{
+			Name:       override.Name,
+			Kind:       string(override.Kind),
+			Value:      override.DisplayValue(),
+			Source:     string(override.Source),
+			SourceName: override.SourceName,
+			Redacted:   override.IsSecret(),
+		})
+	}
+	return values
+}
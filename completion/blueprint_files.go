@@ -0,0 +1,141 @@
+package completion
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// RemoteBlueprintPrefixes holds the prefixes of the object storage locations
+// that blueprint files can be loaded from.
+var RemoteBlueprintPrefixes = []string{
+	"s3://",
+	"gcs://",
+	"azureblob://",
+}
+
+var blueprintFileExtensions = []string{".yaml", ".yml", ".json", ".jsonc"}
+
+// BlueprintFilePatterns derives the glob patterns used to match blueprint
+// files from the default blueprint file name of a CLI.
+// For example, "app.blueprint.yaml" produces "*.blueprint.yaml",
+// "*.blueprint.yml", "*.blueprint.json" and "*.blueprint.jsonc".
+func BlueprintFilePatterns(defaultBlueprintFile string) []string {
+	base := filepath.Base(defaultBlueprintFile)
+	ext := filepath.Ext(base)
+	nameWithoutExt := strings.TrimSuffix(base, ext)
+
+	// The first segment of the file name (e.g. "app") is specific to the
+	// project, the rest (e.g. ".blueprint") is shared by blueprint files.
+	qualifier := ""
+	if dotIndex := strings.Index(nameWithoutExt, "."); dotIndex >= 0 {
+		qualifier = nameWithoutExt[dotIndex:]
+	}
+
+	extensions := blueprintFileExtensions
+	if ext != "" && !slices.Contains(extensions, ext) {
+		extensions = append([]string{ext}, extensions...)
+	}
+
+	patterns := make([]string, 0, len(extensions))
+	for _, extension := range extensions {
+		patterns = append(patterns, "*"+qualifier+extension)
+	}
+	return patterns
+}
+
+// CompleteBlueprintFile returns the completion suggestions for the partial
+// input of a blueprint file flag.
+// Suggestions include directories and files matching the provided patterns
+// relative to the current working directory, along with the object storage
+// prefixes that match the input.
+// Paths in object storage are not listed, so no suggestions are provided
+// once an object storage prefix has been entered.
+func CompleteBlueprintFile(toComplete string, patterns []string) ([]string, cobra.ShellCompDirective) {
+	for _, prefix := range RemoteBlueprintPrefixes {
+		if strings.HasPrefix(toComplete, prefix) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+	}
+
+	suggestions := []string{}
+	hasDirSuggestions := false
+
+	dir, namePrefix := splitPartialPath(toComplete)
+	readDir := dir
+	if readDir == "" {
+		readDir = "."
+	}
+	entries, err := os.ReadDir(readDir)
+	if err != nil {
+		cobra.CompDebugln("failed to read directory for completion: "+err.Error(), false)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, namePrefix) || isHiddenExcluded(name, namePrefix) {
+			continue
+		}
+		if isDir(readDir, entry) {
+			suggestions = append(suggestions, dir+name+"/")
+			hasDirSuggestions = true
+		} else if matchesAnyPattern(name, patterns) {
+			suggestions = append(suggestions, dir+name)
+		}
+	}
+
+	if toComplete != "" {
+		for _, prefix := range RemoteBlueprintPrefixes {
+			if strings.HasPrefix(prefix, toComplete) {
+				suggestions = append(suggestions, prefix)
+				hasDirSuggestions = true
+			}
+		}
+	}
+
+	sort.Strings(suggestions)
+	directive := cobra.ShellCompDirectiveNoFileComp
+	if hasDirSuggestions {
+		// Directories and object storage prefixes are completed further,
+		// so the shell should not add a space after them.
+		directive |= cobra.ShellCompDirectiveNoSpace
+	}
+	return suggestions, directive
+}
+
+func splitPartialPath(toComplete string) (string, string) {
+	lastSlash := strings.LastIndex(toComplete, "/")
+	if lastSlash < 0 {
+		return "", toComplete
+	}
+	return toComplete[:lastSlash+1], toComplete[lastSlash+1:]
+}
+
+// Hidden files and directories are only suggested when the user has
+// started typing a hidden name.
+func isHiddenExcluded(name string, namePrefix string) bool {
+	return strings.HasPrefix(name, ".") && !strings.HasPrefix(namePrefix, ".")
+}
+
+func isDir(parentDir string, entry os.DirEntry) bool {
+	if entry.IsDir() {
+		return true
+	}
+	if entry.Type()&os.ModeSymlink == 0 {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(parentDir, entry.Name()))
+	return err == nil && info.IsDir()
+}
+
+func matchesAnyPattern(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package completion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/suite"
+)
+
+type BlueprintFilesSuite struct {
+	suite.Suite
+	dir      string
+	patterns []string
+}
+
+func TestBlueprintFilesSuite(t *testing.T) {
+	suite.Run(t, new(BlueprintFilesSuite))
+}
+
+func (s *BlueprintFilesSuite) SetupTest() {
+	s.dir = s.T().TempDir() + "/"
+	s.patterns = BlueprintFilePatterns("app.blueprint.yaml")
+
+	for _, name := range []string{
+		"app.blueprint.yaml",
+		"orders.blueprint.jsonc",
+		"app.deploy.jsonc",
+		"README.md",
+		".hidden.blueprint.yaml",
+	} {
+		s.Require().NoError(os.WriteFile(filepath.Join(s.dir, name), []byte{}, 0o600))
+	}
+	s.Require().NoError(os.Mkdir(filepath.Join(s.dir, "services"), 0o755))
+}
+
+func (s *BlueprintFilesSuite) Test_derives_patterns_from_default_blueprint_file() {
+	s.Equal(
+		[]string{"*.blueprint.yaml", "*.blueprint.yml", "*.blueprint.json", "*.blueprint.jsonc"},
+		s.patterns,
+	)
+	s.Equal(
+		[]string{"*.bp", "*.yaml", "*.yml", "*.json", "*.jsonc"},
+		BlueprintFilePatterns("project.bp"),
+	)
+}
+
+func (s *BlueprintFilesSuite) Test_completes_blueprint_files_and_directories() {
+	suggestions, directive := CompleteBlueprintFile(s.dir, s.patterns)
+
+	s.Equal(
+		[]string{
+			s.dir + "app.blueprint.yaml",
+			s.dir + "orders.blueprint.jsonc",
+			s.dir + "services/",
+		},
+		suggestions,
+	)
+	s.Equal(cobra.ShellCompDirectiveNoFileComp|cobra.ShellCompDirectiveNoSpace, directive)
+}
+
+func (s *BlueprintFilesSuite) Test_completes_files_matching_partial_name() {
+	suggestions, directive := CompleteBlueprintFile(s.dir+"or", s.patterns)
+
+	s.Equal([]string{s.dir + "orders.blueprint.jsonc"}, suggestions)
+	s.Equal(cobra.ShellCompDirectiveNoFileComp, directive)
+}
+
+func (s *BlueprintFilesSuite) Test_completes_hidden_files_when_requested() {
+	suggestions, _ := CompleteBlueprintFile(s.dir+".", s.patterns)
+
+	s.Equal([]string{s.dir + ".hidden.blueprint.yaml"}, suggestions)
+}
+
+func (s *BlueprintFilesSuite) Test_completes_object_storage_prefixes() {
+	suggestions, directive := CompleteBlueprintFile("gc", s.patterns)
+	s.Contains(suggestions, "gcs://")
+	s.NotContains(suggestions, "s3://")
+	s.Equal(cobra.ShellCompDirectiveNoFileComp|cobra.ShellCompDirectiveNoSpace, directive)
+
+	suggestions, _ = CompleteBlueprintFile("azure", s.patterns)
+	s.Contains(suggestions, "azureblob://")
+}
+
+func (s *BlueprintFilesSuite) Test_does_not_complete_object_storage_paths() {
+	suggestions, directive := CompleteBlueprintFile("s3://my-bucket/", s.patterns)
+
+	s.Empty(suggestions)
+	s.Equal(cobra.ShellCompDirectiveNoFileComp, directive)
+}
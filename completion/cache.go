@@ -0,0 +1,176 @@
+package completion
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+)
+
+// DefaultCacheTTL is the default amount of time instances fetched
+// for shell completion are cached for.
+const DefaultCacheTTL = 30 * time.Second
+
+const cacheFileName = "completion-cache.json"
+
+// Cache is a file-backed cache of blueprint instances fetched from the
+// deploy engine for shell completion.
+// Each shell completion request runs in a new process, so an in-memory cache
+// would not be shared between requests.
+// Failures to read or write the cache file are ignored, as completion should
+// fall back to querying the deploy engine rather than failing.
+type Cache struct {
+	path string
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+type cacheEntry struct {
+	Key       string                  `json:"key"`
+	Search    string                  `json:"search"`
+	FetchedAt int64                   `json:"fetchedAt"`
+	Complete  bool                    `json:"complete"`
+	Instances []state.InstanceSummary `json:"instances"`
+}
+
+// NewCache creates a new completion cache that stores entries in the file
+// at the provided path, entries are considered stale after the provided TTL.
+func NewCache(path string, ttl time.Duration) *Cache {
+	return &Cache{
+		path: path,
+		ttl:  ttl,
+		now:  time.Now,
+	}
+}
+
+// DefaultCachePath returns the path of the completion cache file
+// in the user's cache directory for the CLI with the provided name
+// (e.g. ~/.cache/bluelink/completion-cache.json on Linux).
+func DefaultCachePath(cliName string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, cliName, cacheFileName), nil
+}
+
+// Get retrieves the cached instances for a search term against the deploy
+// engine identified by the provided key.
+// When there is no entry for the exact search term, a complete entry for a
+// broader search term (a substring of the search term) will be filtered
+// to match the search term.
+func (c *Cache) Get(key string, search string) ([]state.InstanceSummary, bool) {
+	return c.get(key, search, false)
+}
+
+// GetComplete retrieves the cached instances for a search term in the same
+// way as Get, ignoring entries that do not include all the matches for the
+// search term.
+func (c *Cache) GetComplete(key string, search string) ([]state.InstanceSummary, bool) {
+	return c.get(key, search, true)
+}
+
+func (c *Cache) get(key string, search string, requireComplete bool) ([]state.InstanceSummary, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	entries := c.readFresh()
+	if entry, ok := entries[entryID(key, search)]; ok && (entry.Complete || !requireComplete) {
+		return entry.Instances, true
+	}
+
+	lowerSearch := strings.ToLower(search)
+	for _, entry := range entries {
+		if entry.Key != key || !entry.Complete ||
+			!strings.Contains(lowerSearch, strings.ToLower(entry.Search)) {
+			continue
+		}
+		return filterBySearch(entry.Instances, lowerSearch), true
+	}
+
+	return nil, false
+}
+
+// Put stores the instances fetched for a search term against the deploy
+// engine identified by the provided key.
+// Complete should be true when the instances include all the matches for the
+// search term, so the entry can be used for more specific search terms.
+func (c *Cache) Put(key string, search string, instances []state.InstanceSummary, complete bool) {
+	if c == nil {
+		return
+	}
+
+	entries := c.readFresh()
+	entries[entryID(key, search)] = cacheEntry{
+		Key:       key,
+		Search:    search,
+		FetchedAt: c.now().Unix(),
+		Complete:  complete,
+		Instances: instances,
+	}
+	c.write(entries)
+}
+
+func (c *Cache) readFresh() map[string]cacheEntry {
+	entries := map[string]cacheEntry{}
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return entries
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return map[string]cacheEntry{}
+	}
+
+	now := c.now()
+	for id, entry := range entries {
+		if now.Sub(time.Unix(entry.FetchedAt, 0)) > c.ttl {
+			delete(entries, id)
+		}
+	}
+	return entries
+}
+
+func (c *Cache) write(entries map[string]cacheEntry) {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return
+	}
+
+	dir := filepath.Dir(c.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return
+	}
+
+	// Write to a temporary file and rename it so that concurrent completion
+	// requests never read a partially written cache file.
+	tempFile, err := os.CreateTemp(dir, cacheFileName+".*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tempFile.Name())
+
+	_, writeErr := tempFile.Write(data)
+	closeErr := tempFile.Close()
+	if writeErr != nil || closeErr != nil {
+		return
+	}
+	_ = os.Rename(tempFile.Name(), c.path)
+}
+
+func entryID(key string, search string) string {
+	return key + "\n" + strings.ToLower(search)
+}
+
+func filterBySearch(instances []state.InstanceSummary, lowerSearch string) []state.InstanceSummary {
+	filtered := []state.InstanceSummary{}
+	for _, instance := range instances {
+		if strings.Contains(strings.ToLower(instance.InstanceName), lowerSearch) {
+			filtered = append(filtered, instance)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,234 @@
+// Package completion provides dynamic shell completion for command flags
+// that reference blueprint instances and blueprint files.
+package completion
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// DefaultTimeout is the default amount of time to wait for the
+	// deploy engine when fetching instances for shell completion.
+	DefaultTimeout = 2 * time.Second
+	// DefaultLimit is the default maximum number of instances
+	// fetched from the deploy engine for shell completion.
+	DefaultLimit = 100
+)
+
+// InstanceField determines which field of a blueprint instance
+// is suggested for completion.
+type InstanceField int
+
+const (
+	// InstanceFieldName suggests instance names.
+	InstanceFieldName InstanceField = iota
+	// InstanceFieldID suggests instance IDs.
+	InstanceFieldID
+)
+
+// InstanceCompleterConfig holds the configuration for an instance completer.
+type InstanceCompleterConfig struct {
+	// CreateEngine creates the deploy engine client used to list instances,
+	// this is only called when there is no cached result for the input.
+	// Creating the client counts towards the timeout as it can run
+	// credential helpers that take much longer than completion should wait.
+	CreateEngine func() (engine.DeployEngine, error)
+	// Cache is an optional cache for instances fetched from the deploy engine.
+	Cache *Cache
+	// CacheKey identifies the deploy engine in the cache
+	// (e.g. the engine endpoint).
+	CacheKey string
+	// Timeout is the maximum amount of time to wait for the deploy engine
+	// client to be created and to list instances, defaults to DefaultTimeout.
+	Timeout time.Duration
+	// Limit is the maximum number of instances to fetch in a single request,
+	// paging for instance IDs stops once this many matching IDs have been found.
+	// Defaults to DefaultLimit.
+	Limit int
+}
+
+// InstanceCompleter provides shell completion for instance names and IDs
+// by listing instances from the deploy engine.
+type InstanceCompleter struct {
+	createEngine func() (engine.DeployEngine, error)
+	cache        *Cache
+	cacheKey     string
+	timeout      time.Duration
+	limit        int
+}
+
+// NewInstanceCompleter creates a new instance completer.
+func NewInstanceCompleter(config InstanceCompleterConfig) *InstanceCompleter {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	limit := config.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	return &InstanceCompleter{
+		createEngine: config.CreateEngine,
+		cache:        config.Cache,
+		cacheKey:     config.CacheKey,
+		timeout:      timeout,
+		limit:        limit,
+	}
+}
+
+// Complete returns the completion suggestions for the partial input of a flag
+// for the provided instance field.
+// Each suggestion is described with the other identifier of the instance
+// (the ID for names and the name for IDs) for shells that support descriptions.
+// Failures to reach the deploy engine result in no suggestions so that
+// completion never blocks or errors in the user's shell.
+func (c *InstanceCompleter) Complete(
+	ctx context.Context,
+	field InstanceField,
+	toComplete string,
+) ([]string, cobra.ShellCompDirective) {
+	var instances []state.InstanceSummary
+	var err error
+	if field == InstanceFieldID {
+		instances, err = c.listInstancesWithIDPrefix(ctx, toComplete)
+	} else {
+		instances, err = c.listInstances(ctx, toComplete)
+	}
+	if err != nil {
+		cobra.CompDebugln("failed to list instances for completion: "+err.Error(), false)
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	suggestions := []string{}
+	for _, instance := range instances {
+		value, description := instance.InstanceName, instance.InstanceID
+		if field == InstanceFieldID {
+			value, description = instance.InstanceID, instance.InstanceName
+		}
+		if value == "" || !strings.HasPrefix(value, toComplete) {
+			continue
+		}
+		suggestions = append(suggestions, value+"\t"+description)
+	}
+
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (c *InstanceCompleter) listInstances(
+	ctx context.Context,
+	search string,
+) ([]state.InstanceSummary, error) {
+	if instances, ok := c.cache.Get(c.cacheKey, search); ok {
+		return instances, nil
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	deployEngine, err := c.createEngineWithContext(ctxWithTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := deployEngine.ListBlueprintInstances(
+		ctxWithTimeout,
+		state.ListInstancesParams{
+			Search: search,
+			Limit:  c.limit,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	complete := result.TotalCount <= len(result.Instances)
+	c.cache.Put(c.cacheKey, search, result.Instances, complete)
+	return result.Instances, nil
+}
+
+// listInstancesWithIDPrefix pages through the instances from the deploy engine
+// until the limit of instances with IDs that start with the prefix have been
+// found, as the deploy engine only supports searching by name.
+// The matches found so far are returned when the timeout is reached while paging.
+func (c *InstanceCompleter) listInstancesWithIDPrefix(
+	ctx context.Context,
+	prefix string,
+) ([]state.InstanceSummary, error) {
+	// A complete list of instances is cached once every page has been
+	// fetched, so it can be used for any prefix.
+	if instances, ok := c.cache.GetComplete(c.cacheKey, ""); ok {
+		return instances, nil
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	deployEngine, err := c.createEngineWithContext(ctxWithTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	fetched := []state.InstanceSummary{}
+	matches := []state.InstanceSummary{}
+	for len(matches) < c.limit {
+		result, err := deployEngine.ListBlueprintInstances(
+			ctxWithTimeout,
+			state.ListInstancesParams{
+				Offset: len(fetched),
+				Limit:  c.limit,
+			},
+		)
+		if err != nil {
+			if len(matches) > 0 {
+				cobra.CompDebugln("stopped listing instances for completion: "+err.Error(), false)
+				return matches, nil
+			}
+			return nil, err
+		}
+
+		fetched = append(fetched, result.Instances...)
+		for _, instance := range result.Instances {
+			if strings.HasPrefix(instance.InstanceID, prefix) {
+				matches = append(matches, instance)
+			}
+		}
+
+		if len(result.Instances) == 0 || len(fetched) >= result.TotalCount {
+			c.cache.Put(c.cacheKey, "", fetched, true)
+			break
+		}
+	}
+
+	return matches, nil
+}
+
+type createEngineResult struct {
+	deployEngine engine.DeployEngine
+	err          error
+}
+
+// createEngineWithContext creates the deploy engine client, giving up
+// when the context is done before the client has been created.
+// The client continues to be created in the background when the context
+// is done first, the result is discarded.
+func (c *InstanceCompleter) createEngineWithContext(ctx context.Context) (engine.DeployEngine, error) {
+	// Buffered so the goroutine can exit when the result is not received.
+	resultChan := make(chan createEngineResult, 1)
+	go func() {
+		deployEngine, err := c.createEngine()
+		resultChan <- createEngineResult{deployEngine: deployEngine, err: err}
+	}()
+
+	select {
+	case result := <-resultChan:
+		return result.deployEngine, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,253 @@
+package completion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/suite"
+)
+
+type InstanceCompleterSuite struct {
+	suite.Suite
+	engine *listingEngine
+	cache  *Cache
+}
+
+func TestInstanceCompleterSuite(t *testing.T) {
+	suite.Run(t, new(InstanceCompleterSuite))
+}
+
+func (s *InstanceCompleterSuite) SetupTest() {
+	s.engine = &listingEngine{
+		instances: []state.InstanceSummary{
+			{InstanceID: "inst-1", InstanceName: "orders-api"},
+			{InstanceID: "inst-2", InstanceName: "orders-worker"},
+			{InstanceID: "inst-3", InstanceName: "payments-api"},
+		},
+	}
+	s.cache = NewCache(filepath.Join(s.T().TempDir(), "completion-cache.json"), time.Minute)
+}
+
+func (s *InstanceCompleterSuite) Test_completes_instance_names_with_ids_as_descriptions() {
+	suggestions, directive := s.newCompleter().Complete(context.Background(), InstanceFieldName, "orders")
+
+	s.Equal([]string{"orders-api\tinst-1", "orders-worker\tinst-2"}, suggestions)
+	s.Equal(cobra.ShellCompDirectiveNoFileComp, directive)
+	s.Equal([]string{"orders"}, s.engine.searches)
+}
+
+func (s *InstanceCompleterSuite) Test_completes_instance_ids_with_names_as_descriptions() {
+	suggestions, _ := s.newCompleter().Complete(context.Background(), InstanceFieldID, "inst-3")
+
+	s.Equal([]string{"inst-3\tpayments-api"}, suggestions)
+	s.Equal([]string{""}, s.engine.searches)
+}
+
+func (s *InstanceCompleterSuite) Test_pages_through_instances_to_complete_ids() {
+	s.engine.instances = testInstances(DefaultLimit*2 + 50)
+
+	completer := s.newCompleter()
+	suggestions, _ := completer.Complete(context.Background(), InstanceFieldID, "inst-24")
+	s.Equal([]string{
+		"inst-240\tapp-240", "inst-241\tapp-241", "inst-242\tapp-242", "inst-243\tapp-243",
+		"inst-244\tapp-244", "inst-245\tapp-245", "inst-246\tapp-246", "inst-247\tapp-247",
+		"inst-248\tapp-248", "inst-249\tapp-249",
+	}, suggestions)
+	s.Equal([]int{0, DefaultLimit, DefaultLimit * 2}, s.engine.offsets)
+
+	// All the instances have been fetched, so completing
+	// another prefix uses the cached instances.
+	suggestions, _ = completer.Complete(context.Background(), InstanceFieldID, "inst-105")
+	s.Equal([]string{"inst-105\tapp-105"}, suggestions)
+	s.Len(s.engine.offsets, 3)
+}
+
+func (s *InstanceCompleterSuite) Test_stops_paging_once_enough_ids_match() {
+	s.engine.instances = testInstances(DefaultLimit*2 + 50)
+	completer := NewInstanceCompleter(InstanceCompleterConfig{
+		CreateEngine: s.createEngine,
+		Cache:        s.cache,
+		CacheKey:     "http://localhost:8325",
+		Limit:        20,
+	})
+
+	suggestions, _ := completer.Complete(context.Background(), InstanceFieldID, "inst-0")
+	s.Len(suggestions, 20)
+	s.Equal([]int{0}, s.engine.offsets)
+
+	// The first page does not include every instance, so it is not
+	// used from the cache for other prefixes.
+	suggestions, _ = completer.Complete(context.Background(), InstanceFieldID, "inst-249")
+	s.Equal([]string{"inst-249\tapp-249"}, suggestions)
+	s.Equal(0, s.engine.offsets[1])
+	s.Len(s.engine.offsets, 14)
+}
+
+func (s *InstanceCompleterSuite) Test_uses_cache_for_repeated_and_narrower_searches() {
+	completer := s.newCompleter()
+	completer.Complete(context.Background(), InstanceFieldName, "orders")
+	completer.Complete(context.Background(), InstanceFieldName, "orders")
+	suggestions, _ := completer.Complete(context.Background(), InstanceFieldName, "orders-w")
+
+	s.Equal([]string{"orders-worker\tinst-2"}, suggestions)
+	s.Equal([]string{"orders"}, s.engine.searches)
+}
+
+func (s *InstanceCompleterSuite) Test_does_not_narrow_incomplete_cached_results() {
+	completer := NewInstanceCompleter(InstanceCompleterConfig{
+		CreateEngine: s.createEngine,
+		Cache:        s.cache,
+		CacheKey:     "http://localhost:8325",
+		Limit:        1,
+	})
+	completer.Complete(context.Background(), InstanceFieldName, "orders")
+	completer.Complete(context.Background(), InstanceFieldName, "orders-w")
+
+	s.Equal([]string{"orders", "orders-w"}, s.engine.searches)
+}
+
+func (s *InstanceCompleterSuite) Test_refetches_expired_cache_entries() {
+	completer := s.newCompleter()
+	completer.Complete(context.Background(), InstanceFieldName, "orders")
+	s.cache.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	completer.Complete(context.Background(), InstanceFieldName, "orders")
+
+	s.Equal([]string{"orders", "orders"}, s.engine.searches)
+}
+
+func (s *InstanceCompleterSuite) Test_cache_entries_are_scoped_to_the_cache_key() {
+	s.newCompleter().Complete(context.Background(), InstanceFieldName, "orders")
+	otherEngine := NewInstanceCompleter(InstanceCompleterConfig{
+		CreateEngine: s.createEngine,
+		Cache:        s.cache,
+		CacheKey:     "http://engine.example.com",
+	})
+	otherEngine.Complete(context.Background(), InstanceFieldName, "orders")
+
+	s.Equal([]string{"orders", "orders"}, s.engine.searches)
+}
+
+func (s *InstanceCompleterSuite) Test_returns_no_suggestions_when_engine_fails() {
+	s.engine.err = errors.New("connection refused")
+
+	suggestions, directive := s.newCompleter().Complete(context.Background(), InstanceFieldName, "orders")
+	s.Empty(suggestions)
+	s.Equal(cobra.ShellCompDirectiveNoFileComp, directive)
+}
+
+func (s *InstanceCompleterSuite) Test_returns_no_suggestions_when_engine_cannot_be_created() {
+	completer := NewInstanceCompleter(InstanceCompleterConfig{
+		CreateEngine: func() (engine.DeployEngine, error) {
+			return nil, errors.New("invalid connect protocol")
+		},
+	})
+
+	suggestions, _ := completer.Complete(context.Background(), InstanceFieldName, "orders")
+	s.Empty(suggestions)
+}
+
+func (s *InstanceCompleterSuite) Test_applies_timeout_to_engine_request() {
+	s.engine.block = true
+	completer := NewInstanceCompleter(InstanceCompleterConfig{
+		CreateEngine: s.createEngine,
+		Timeout:      50 * time.Millisecond,
+	})
+
+	start := time.Now()
+	suggestions, _ := completer.Complete(context.Background(), InstanceFieldName, "orders")
+	s.Empty(suggestions)
+	s.Less(time.Since(start), time.Second)
+}
+
+func (s *InstanceCompleterSuite) Test_applies_timeout_to_engine_creation() {
+	release := make(chan struct{})
+	defer close(release)
+	completer := NewInstanceCompleter(InstanceCompleterConfig{
+		CreateEngine: func() (engine.DeployEngine, error) {
+			// Simulates a slow credential helper run when creating the client.
+			<-release
+			return s.engine, nil
+		},
+		Timeout: 50 * time.Millisecond,
+	})
+
+	start := time.Now()
+	suggestions, _ := completer.Complete(context.Background(), InstanceFieldName, "orders")
+	s.Empty(suggestions)
+	s.Less(time.Since(start), time.Second)
+	s.Empty(s.engine.searches)
+}
+
+func (s *InstanceCompleterSuite) newCompleter() *InstanceCompleter {
+	return NewInstanceCompleter(InstanceCompleterConfig{
+		CreateEngine: s.createEngine,
+		Cache:        s.cache,
+		CacheKey:     "http://localhost:8325",
+	})
+}
+
+func (s *InstanceCompleterSuite) createEngine() (engine.DeployEngine, error) {
+	return s.engine, nil
+}
+
+// listingEngine is a deploy engine that lists instances matching the search
+// term and records the search terms it was called with.
+type listingEngine struct {
+	engine.DeployEngine
+	instances []state.InstanceSummary
+	searches  []string
+	offsets   []int
+	err       error
+	block     bool
+}
+
+func (e *listingEngine) ListBlueprintInstances(
+	ctx context.Context,
+	params state.ListInstancesParams,
+) (state.ListInstancesResult, error) {
+	e.searches = append(e.searches, params.Search)
+	e.offsets = append(e.offsets, params.Offset)
+	if e.block {
+		<-ctx.Done()
+		return state.ListInstancesResult{}, ctx.Err()
+	}
+	if e.err != nil {
+		return state.ListInstancesResult{}, e.err
+	}
+
+	matches := []state.InstanceSummary{}
+	for _, instance := range e.instances {
+		if strings.Contains(instance.InstanceName, params.Search) {
+			matches = append(matches, instance)
+		}
+	}
+	page := matches[min(params.Offset, len(matches)):]
+	if params.Limit > 0 && len(page) > params.Limit {
+		page = page[:params.Limit]
+	}
+	return state.ListInstancesResult{
+		Instances:  page,
+		TotalCount: len(matches),
+	}, nil
+}
+
+// testInstances creates instances with zero padded IDs (e.g. inst-007)
+// so that IDs can be completed by prefix.
+func testInstances(count int) []state.InstanceSummary {
+	instances := make([]state.InstanceSummary, count)
+	for i := range instances {
+		instances[i] = state.InstanceSummary{
+			InstanceID:   fmt.Sprintf("inst-%03d", i),
+			InstanceName: fmt.Sprintf("app-%03d", i),
+		}
+	}
+	return instances
+}
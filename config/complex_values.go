@@ -0,0 +1,225 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// TypeError is returned by the typed getters of Provider when the value
+// of a configuration value can not be converted to the requested type.
+type TypeError struct {
+	Key   string
+	Value string
+	// Type is the type the value was expected to be, e.g. "int32" or "duration".
+	Type string
+	// Source and SourceName identify where the invalid value was set,
+	// see ResolvedValue.
+	Source     ValueSource
+	SourceName string
+	Err        error
+}
+
+func newTypeError(resolved ResolvedValue, expectedType string, err error) *TypeError {
+	return &TypeError{
+		Key:        resolved.Key,
+		Value:      resolved.Value,
+		Type:       expectedType,
+		Source:     resolved.Source,
+		SourceName: resolved.SourceName,
+		Err:        err,
+	}
+}
+
+func (e *TypeError) Error() string {
+	source := string(e.Source)
+	if e.SourceName != "" {
+		source = fmt.Sprintf("%s (%s)", e.Source, e.SourceName)
+	}
+
+	message := fmt.Sprintf(
+		"invalid value %q for config key %q set in %s, expected %s",
+		e.Value,
+		e.Key,
+		source,
+		e.Type,
+	)
+	if e.Err != nil {
+		message += ": " + e.Err.Error()
+	}
+	return message
+}
+
+func (e *TypeError) Unwrap() error {
+	return e.Err
+}
+
+// GetStringSlice returns the value of a configuration value as a list
+// of strings.
+// Lists can be set with string slice flags, comma-separated environment
+// variables and defaults (e.g. "a,b") or lists in config files.
+// A *TypeError is returned when the config file value is a table or
+// contains items that are not strings, numbers or booleans.
+func (p *Provider) GetStringSlice(configName string) ([]string, bool, error) {
+	resolved, raw := p.resolve(configName)
+
+	switch rawValue := raw.(type) {
+	case *pflag.Flag:
+		if sliceValue, isSlice := rawValue.Value.(pflag.SliceValue); isSlice {
+			return sliceValue.GetSlice(), resolved.IsDefault(), nil
+		}
+	case []any:
+		items := make([]string, 0, len(rawValue))
+		for _, item := range rawValue {
+			strItem, isString := item.(string)
+			if !isString {
+				return nil, resolved.IsDefault(), newTypeError(
+					resolved,
+					"list of strings",
+					errors.New("lists must only contain strings, numbers or booleans"),
+				)
+			}
+			items = append(items, strItem)
+		}
+		return items, resolved.IsDefault(), nil
+	case map[string]any:
+		return nil, resolved.IsDefault(), newTypeError(resolved, "list of strings", nil)
+	}
+
+	return splitList(resolved.Value), resolved.IsDefault(), nil
+}
+
+// GetStringArray returns the value of a configuration value as a list
+// of strings where items can contain commas, such as the values of
+// string array flags that are set in the form name=value.
+// Lists can be set with string array flags, lists in config files and
+// environment variables and defaults with one item per line or
+// as a JSON array of strings (e.g. '["tags=a,b","region=eu-west-2"]').
+// A *TypeError is returned when the config file value is a table,
+// the list contains items that are not strings, numbers or booleans
+// or the value is not a valid JSON array of strings.
+func (p *Provider) GetStringArray(configName string) ([]string, bool, error) {
+	resolved, raw := p.resolve(configName)
+
+	switch raw.(type) {
+	case *pflag.Flag, []any, map[string]any:
+		return p.GetStringSlice(configName)
+	}
+
+	trimmed := strings.TrimSpace(resolved.Value)
+	if strings.HasPrefix(trimmed, "[") {
+		items := []string{}
+		if err := json.Unmarshal([]byte(trimmed), &items); err != nil {
+			return nil, resolved.IsDefault(), newTypeError(resolved, "JSON array of strings", err)
+		}
+		return items, resolved.IsDefault(), nil
+	}
+
+	return splitLines(resolved.Value), resolved.IsDefault(), nil
+}
+
+// GetStringMap returns the value of a configuration value as a map
+// of strings.
+// Maps can be set with string to string flags, environment variables and
+// defaults in the form "key1=value1,key2=value2" or tables in config files.
+// A *TypeError is returned when a value is not in the expected form or a table
+// in the config file contains lists or nested tables.
+func (p *Provider) GetStringMap(configName string) (map[string]string, bool, error) {
+	resolved, raw := p.resolve(configName)
+
+	switch rawValue := raw.(type) {
+	case *pflag.Flag:
+		if rawValue.Value.Type() == "stringToString" {
+			// The string to string flag value is formatted as "[key1=value1,key2=value2]".
+			strValue := strings.TrimSuffix(strings.TrimPrefix(rawValue.Value.String(), "["), "]")
+			return parseStringMap(resolved, strValue)
+		}
+	case map[string]any:
+		values := make(map[string]string, len(rawValue))
+		for key, value := range rawValue {
+			strValue, isString := value.(string)
+			if !isString {
+				return nil, resolved.IsDefault(), newTypeError(
+					resolved,
+					"map of strings",
+					fmt.Errorf("the value of %q must be a string, number or boolean", key),
+				)
+			}
+			values[key] = strValue
+		}
+		return values, resolved.IsDefault(), nil
+	case []any:
+		return nil, resolved.IsDefault(), newTypeError(resolved, "map of strings", nil)
+	}
+
+	return parseStringMap(resolved, resolved.Value)
+}
+
+func parseStringMap(resolved ResolvedValue, strValue string) (map[string]string, bool, error) {
+	values := map[string]string{}
+	for _, pair := range splitList(strValue) {
+		key, value, hasSeparator := strings.Cut(pair, "=")
+		if !hasSeparator || strings.TrimSpace(key) == "" {
+			return nil, resolved.IsDefault(), newTypeError(
+				resolved,
+				"map of strings",
+				fmt.Errorf("%q is not in the form key=value", pair),
+			)
+		}
+		values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return values, resolved.IsDefault(), nil
+}
+
+// GetDuration returns the value of a configuration value as a duration.
+// Durations are expressed in the form accepted by time.ParseDuration,
+// e.g. "30s" or "1h30m".
+// A *TypeError is returned when the value is not a valid duration.
+func (p *Provider) GetDuration(configName string) (time.Duration, bool, error) {
+	resolved, raw := p.resolve(configName)
+
+	switch raw.(type) {
+	case []any, map[string]any:
+		return 0, resolved.IsDefault(), newTypeError(resolved, "duration", nil)
+	}
+
+	if resolved.Value == "" {
+		return 0, resolved.IsDefault(), nil
+	}
+
+	duration, err := time.ParseDuration(resolved.Value)
+	if err != nil {
+		return 0, resolved.IsDefault(), newTypeError(resolved, "duration", err)
+	}
+	return duration, resolved.IsDefault(), nil
+}
+
+func splitLines(value string) []string {
+	items := []string{}
+	for _, line := range strings.Split(value, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+	return items
+}
+
+func splitList(value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		trimmed := strings.TrimSpace(item)
+		if trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+	return items
+}
@@ -0,0 +1,256 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/suite"
+)
+
+type ComplexValuesSuite struct {
+	suite.Suite
+	tempDir string
+}
+
+func (s *ComplexValuesSuite) SetupTest() {
+	s.tempDir = s.T().TempDir()
+}
+
+func (s *ComplexValuesSuite) writeConfigFile(name, content string) string {
+	path := filepath.Join(s.tempDir, name)
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func (s *ComplexValuesSuite) Test_loads_nested_tables_and_lists_from_yaml_config_file() {
+	path := s.writeConfigFile("config.yaml", `
+deploy:
+  instanceName: orders-api
+  timeout: 5m
+  contextVariables:
+    environment: production
+    replicas: 3
+protectedInstances:
+  - orders-api
+  - payments-api
+`)
+	p := NewProvider()
+	s.Require().NoError(p.LoadConfigFile(path))
+
+	instanceName, isDefault := p.GetString("deploy.instanceName")
+	s.Equal("orders-api", instanceName)
+	s.False(isDefault)
+
+	timeout, _, err := p.GetDuration("deploy.timeout")
+	s.Require().NoError(err)
+	s.Equal(5*time.Minute, timeout)
+
+	contextVariables, _, err := p.GetStringMap("deploy.contextVariables")
+	s.Require().NoError(err)
+	s.Equal(map[string]string{"environment": "production", "replicas": "3"}, contextVariables)
+
+	protected, _, err := p.GetStringSlice("protectedInstances")
+	s.Require().NoError(err)
+	s.Equal([]string{"orders-api", "payments-api"}, protected)
+}
+
+func (s *ComplexValuesSuite) Test_loads_nested_tables_and_lists_from_json_config_file() {
+	path := s.writeConfigFile("config.json", `{
+  "deploy": {"instanceName": "orders-api", "parallelism": 4},
+  "protectedInstances": ["orders-api", "payments-api"]
+}`)
+	p := NewProvider()
+	s.Require().NoError(p.LoadConfigFile(path))
+
+	parallelism, _, err := p.GetInt32E("deploy.parallelism")
+	s.Require().NoError(err)
+	s.Equal(int32(4), parallelism)
+
+	protected, _, err := p.GetStringSlice("protectedInstances")
+	s.Require().NoError(err)
+	s.Equal([]string{"orders-api", "payments-api"}, protected)
+}
+
+func (s *ComplexValuesSuite) Test_loads_nested_tables_and_lists_from_toml_config_file() {
+	path := s.writeConfigFile("config.toml", `
+protectedInstances = ["orders-api", "payments-api"]
+
+[deploy]
+instanceName = "orders-api"
+autoRollback = true
+
+[deploy.contextVariables]
+environment = "production"
+`)
+	p := NewProvider()
+	s.Require().NoError(p.LoadConfigFile(path))
+
+	autoRollback, _, err := p.GetBoolE("deploy.autoRollback")
+	s.Require().NoError(err)
+	s.True(autoRollback)
+
+	contextVariables, _, err := p.GetStringMap("deploy.contextVariables")
+	s.Require().NoError(err)
+	s.Equal(map[string]string{"environment": "production"}, contextVariables)
+
+	protected, _, err := p.GetStringSlice("protectedInstances")
+	s.Require().NoError(err)
+	s.Equal([]string{"orders-api", "payments-api"}, protected)
+}
+
+func (s *ComplexValuesSuite) Test_string_slice_precedence() {
+	path := s.writeConfigFile("config.yaml", `
+protectedInstances: [from-file]
+`)
+	p := NewProvider()
+	s.Require().NoError(p.LoadConfigFile(path))
+	flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flagSet.StringSlice("protected-instance", []string{}, "Protected instances")
+	p.BindPFlag("protectedInstances", flagSet.Lookup("protected-instance"))
+	p.BindEnvVar("protectedInstances", "TEST_COMPLEX_PROTECTED_INSTANCES")
+
+	fromFile, isDefault, err := p.GetStringSlice("protectedInstances")
+	s.Require().NoError(err)
+	s.Equal([]string{"from-file"}, fromFile)
+	s.False(isDefault)
+
+	s.T().Setenv("TEST_COMPLEX_PROTECTED_INSTANCES", "env-a, env-b")
+	fromEnv, _, err := p.GetStringSlice("protectedInstances")
+	s.Require().NoError(err)
+	s.Equal([]string{"env-a", "env-b"}, fromEnv)
+
+	s.Require().NoError(flagSet.Parse([]string{
+		"--protected-instance=flag-a",
+		"--protected-instance=flag-b",
+	}))
+	fromFlag, _, err := p.GetStringSlice("protectedInstances")
+	s.Require().NoError(err)
+	s.Equal([]string{"flag-a", "flag-b"}, fromFlag)
+}
+
+func (s *ComplexValuesSuite) Test_string_slice_from_provider_default() {
+	p := NewProvider()
+	p.SetDefault("protectedInstances", "orders-api,payments-api")
+
+	protected, isDefault, err := p.GetStringSlice("protectedInstances")
+	s.Require().NoError(err)
+	s.Equal([]string{"orders-api", "payments-api"}, protected)
+	s.True(isDefault)
+
+	unset, _, err := p.GetStringSlice("missing")
+	s.Require().NoError(err)
+	s.Empty(unset)
+}
+
+func (s *ComplexValuesSuite) Test_string_array_keeps_commas_in_env_var_items() {
+	p := NewProvider()
+	flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flagSet.StringArray("var", []string{}, "Variables")
+	p.BindPFlag("deployVars", flagSet.Lookup("var"))
+	p.BindEnvVar("deployVars", "TEST_COMPLEX_DEPLOY_VARS")
+
+	s.T().Setenv("TEST_COMPLEX_DEPLOY_VARS", "tags=a,b\nregion=eu-west-2\n")
+	fromLines, _, err := p.GetStringArray("deployVars")
+	s.Require().NoError(err)
+	s.Equal([]string{"tags=a,b", "region=eu-west-2"}, fromLines)
+
+	s.T().Setenv("TEST_COMPLEX_DEPLOY_VARS", `["tags=a,b", "region=eu-west-2"]`)
+	fromJSON, _, err := p.GetStringArray("deployVars")
+	s.Require().NoError(err)
+	s.Equal([]string{"tags=a,b", "region=eu-west-2"}, fromJSON)
+
+	s.T().Setenv("TEST_COMPLEX_DEPLOY_VARS", `["tags=a,b"`)
+	_, _, err = p.GetStringArray("deployVars")
+	typeErr := &TypeError{}
+	s.Require().ErrorAs(err, &typeErr)
+	s.Equal(SourceEnvVar, typeErr.Source)
+
+	s.Require().NoError(flagSet.Parse([]string{"--var=tags=c,d"}))
+	fromFlag, _, err := p.GetStringArray("deployVars")
+	s.Require().NoError(err)
+	s.Equal([]string{"tags=c,d"}, fromFlag)
+}
+
+func (s *ComplexValuesSuite) Test_string_map_from_flag_and_env_var() {
+	p := NewProvider()
+	flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flagSet.StringToString("context-var", map[string]string{}, "Context variables")
+	p.BindPFlag("contextVariables", flagSet.Lookup("context-var"))
+	p.BindEnvVar("contextVariables", "TEST_COMPLEX_CONTEXT_VARIABLES")
+
+	s.T().Setenv("TEST_COMPLEX_CONTEXT_VARIABLES", "environment=staging,region=eu-west-2")
+	fromEnv, _, err := p.GetStringMap("contextVariables")
+	s.Require().NoError(err)
+	s.Equal(map[string]string{"environment": "staging", "region": "eu-west-2"}, fromEnv)
+
+	s.Require().NoError(flagSet.Parse([]string{"--context-var=environment=production"}))
+	fromFlag, _, err := p.GetStringMap("contextVariables")
+	s.Require().NoError(err)
+	s.Equal(map[string]string{"environment": "production"}, fromFlag)
+}
+
+func (s *ComplexValuesSuite) Test_reports_type_errors() {
+	path := s.writeConfigFile("config.yaml", `
+parallelism: lots
+timeout: soon
+contextVariables:
+  nested:
+    value: 1
+protectedInstances:
+  environment: production
+`)
+	p := NewProvider()
+	s.Require().NoError(p.LoadConfigFile(path))
+
+	_, _, err := p.GetInt32E("parallelism")
+	typeErr := &TypeError{}
+	s.Require().ErrorAs(err, &typeErr)
+	s.Equal("parallelism", typeErr.Key)
+	s.Equal("lots", typeErr.Value)
+	s.Equal("int32", typeErr.Type)
+	s.Equal(SourceConfigFile, typeErr.Source)
+	s.Contains(err.Error(), path)
+
+	_, _, err = p.GetDuration("timeout")
+	s.Require().ErrorAs(err, &typeErr)
+	s.Equal("duration", typeErr.Type)
+
+	_, _, err = p.GetStringMap("contextVariables")
+	s.Require().ErrorAs(err, &typeErr)
+	s.Contains(err.Error(), `the value of "nested" must be a string`)
+
+	_, _, err = p.GetStringSlice("protectedInstances")
+	s.Require().ErrorAs(err, &typeErr)
+	s.Equal("list of strings", typeErr.Type)
+}
+
+func (s *ComplexValuesSuite) Test_reports_type_errors_for_env_vars() {
+	p := NewProvider()
+	p.BindEnvVar("contextVariables", "TEST_COMPLEX_INVALID_CONTEXT_VARIABLES")
+	s.T().Setenv("TEST_COMPLEX_INVALID_CONTEXT_VARIABLES", "environment")
+
+	_, _, err := p.GetStringMap("contextVariables")
+	s.Require().Error(err)
+	s.Contains(err.Error(), "TEST_COMPLEX_INVALID_CONTEXT_VARIABLES")
+	s.Contains(err.Error(), `"environment" is not in the form key=value`)
+}
+
+func (s *ComplexValuesSuite) Test_resolve_formats_lists_and_tables() {
+	path := s.writeConfigFile("config.yaml", `
+protectedInstances: [orders-api, payments-api]
+deploy:
+  instanceName: orders-api
+`)
+	p := NewProvider()
+	s.Require().NoError(p.LoadConfigFile(path))
+
+	s.Equal("orders-api,payments-api", p.Resolve("protectedInstances").Value)
+	s.Equal([]string{"deploy.instanceName", "protectedInstances"}, p.Keys())
+}
+
+func TestComplexValuesSuite(t *testing.T) {
+	suite.Run(t, new(ComplexValuesSuite))
+}
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// ContextConfigKey is the configuration value that holds the name
+	// of the engine context selected with the --context flag,
+	// the <PREFIX>_CONTEXT environment variable or the "context" key
+	// of the config file.
+	ContextConfigKey = "context"
+
+	// contextsConfigKey is the section of the config file
+	// that defines named engine contexts.
+	contextsConfigKey = "contexts"
+)
+
+// Configuration values that are taken from the active engine context
+// when they are not set with a flag or environment variable.
+var contextConfigKeys = []string{
+	"engineEndpoint",
+	"connectProtocol",
+	"engineAuthConfigFile",
+}
+
+// ErrContextNotFound is returned when the selected engine context
+// is not defined in the config file.
+var ErrContextNotFound = errors.New("engine context not found")
+
+// EngineContext is a named set of deploy engine connection settings,
+// similar to contexts in a kubeconfig file.
+// Contexts are defined in the "contexts" section of the CLI config file,
+// for example:
+//
+//	contexts:
+//	  staging:
+//	    engineEndpoint: https://staging.engine.example.com
+//	    engineAuthConfigFile: ~/.bluelink/staging.auth.json
+//	  prod:
+//	    engineEndpoint: https://engine.example.com
+//	    engineAuthConfigFile: ~/.bluelink/prod.auth.json
+type EngineContext struct {
+	EngineEndpoint       string
+	ConnectProtocol      string
+	EngineAuthConfigFile string
+}
+
+func (c *EngineContext) value(configName string) string {
+	switch configName {
+	case "engineEndpoint":
+		return c.EngineEndpoint
+	case "connectProtocol":
+		return c.ConnectProtocol
+	case "engineAuthConfigFile":
+		return c.EngineAuthConfigFile
+	}
+	return ""
+}
+
+func (p *Provider) addContexts(contexts map[string]EngineContext) {
+	for name, engineContext := range contexts {
+		p.contexts[name] = engineContext
+	}
+}
+
+// Contexts returns the engine contexts defined in loaded config files
+// keyed by context name.
+func (p *Provider) Contexts() map[string]EngineContext {
+	contexts := make(map[string]EngineContext, len(p.contexts))
+	for name, engineContext := range p.contexts {
+		contexts[name] = engineContext
+	}
+	return contexts
+}
+
+// ContextNames returns the names of the engine contexts defined
+// in loaded config files, sorted by name.
+func (p *Provider) ContextNames() []string {
+	names := make([]string, 0, len(p.contexts))
+	for name := range p.contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetCurrentContextFile sets the file that holds the engine context
+// selected with the "context use" command.
+// The context in this file takes precedence over the "context" key of the
+// config file but not over the --context flag or environment variable.
+func (p *Provider) SetCurrentContextFile(path string) {
+	p.currentContextFile = path
+}
+
+// CurrentContextFile returns the file that holds the engine context
+// selected with the "context use" command.
+func (p *Provider) CurrentContextFile() string {
+	return p.currentContextFile
+}
+
+// UseContext persists the provided context as the current context
+// in the current context file.
+func (p *Provider) UseContext(name string) error {
+	if _, exists := p.contexts[name]; !exists {
+		return contextNotFoundError(name)
+	}
+
+	if p.currentContextFile == "" {
+		return errors.New("no current context file has been configured")
+	}
+
+	err := os.MkdirAll(filepath.Dir(p.currentContextFile), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p.currentContextFile, []byte(name+"\n"), 0o644)
+}
+
+// ActiveContextName returns the name of the selected engine context,
+// or an empty string if no context has been selected.
+// The context is selected with, in order of precedence,
+// the --context flag or environment variable, the "context use" command
+// and the "context" key of the config file.
+func (p *Provider) ActiveContextName() string {
+	resolved := p.Resolve(ContextConfigKey)
+	if resolved.Source == SourceFlag || resolved.Source == SourceEnvVar {
+		return resolved.Value
+	}
+
+	if currentContext := p.readCurrentContextFile(); currentContext != "" {
+		return currentContext
+	}
+
+	return p.config[ContextConfigKey]
+}
+
+// ActiveContext returns the name and settings of the selected engine context.
+// An empty name and nil context are returned when no context has been
+// selected, an error is returned when the selected context is not defined.
+func (p *Provider) ActiveContext() (string, *EngineContext, error) {
+	name := p.ActiveContextName()
+	if name == "" {
+		return "", nil, nil
+	}
+
+	engineContext, exists := p.contexts[name]
+	if !exists {
+		return name, nil, contextNotFoundError(name)
+	}
+
+	return name, &engineContext, nil
+}
+
+func (p *Provider) readCurrentContextFile() string {
+	if p.currentContextFile == "" {
+		return ""
+	}
+
+	contents, err := os.ReadFile(p.currentContextFile)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(contents))
+}
+
+func (p *Provider) resolveFromContext(configName string) (string, string, bool) {
+	if !isContextConfigKey(configName) {
+		return "", "", false
+	}
+
+	name, engineContext, err := p.ActiveContext()
+	if err != nil || engineContext == nil {
+		return "", "", false
+	}
+
+	value := engineContext.value(configName)
+	return value, name, value != ""
+}
+
+func isContextConfigKey(configName string) bool {
+	for _, key := range contextConfigKeys {
+		if key == configName {
+			return true
+		}
+	}
+	return false
+}
+
+func contextNotFoundError(name string) error {
+	return fmt.Errorf("%w: %q is not defined in the contexts section of the config file", ErrContextNotFound, name)
+}
+
+// DefaultCurrentContextFile returns the default location of the file
+// that holds the engine context selected with the "context use" command
+// for the CLI with the provided name.
+func DefaultCurrentContextFile(cliName string) string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = os.TempDir()
+	}
+	return filepath.Join(configDir, cliName, "current-context")
+}
@@ -0,0 +1,226 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/suite"
+)
+
+type ContextsSuite struct {
+	suite.Suite
+	tempDir string
+}
+
+func (s *ContextsSuite) SetupTest() {
+	s.tempDir = s.T().TempDir()
+}
+
+func (s *ContextsSuite) writeConfigFile(name, content string) string {
+	path := filepath.Join(s.tempDir, name)
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func (s *ContextsSuite) Test_loads_contexts_from_yaml_config_file() {
+	path := s.writeConfigFile("config.yaml", `
+engineEndpoint: http://localhost:8325
+context: staging
+contexts:
+  staging:
+    engineEndpoint: https://staging.engine.example.com
+    engineAuthConfigFile: /auth/staging.json
+  prod:
+    engineEndpoint: https://engine.example.com
+    connectProtocol: tcp
+`)
+	p := NewProvider()
+	s.Require().NoError(p.LoadConfigFile(path))
+
+	s.Equal([]string{"prod", "staging"}, p.ContextNames())
+	s.Equal(
+		EngineContext{EngineEndpoint: "https://engine.example.com", ConnectProtocol: "tcp"},
+		p.Contexts()["prod"],
+	)
+
+	name, engineContext, err := p.ActiveContext()
+	s.Require().NoError(err)
+	s.Equal("staging", name)
+	s.Equal("/auth/staging.json", engineContext.EngineAuthConfigFile)
+}
+
+func (s *ContextsSuite) Test_loads_contexts_from_json_config_file() {
+	path := s.writeConfigFile("config.json", `{
+  "timeout": 30,
+  "contexts": {
+    "prod": {"engineEndpoint": "https://engine.example.com"}
+  }
+}`)
+	p := NewProvider()
+	s.Require().NoError(p.LoadConfigFile(path))
+
+	s.Equal([]string{"prod"}, p.ContextNames())
+	timeout, _ := p.GetInt32("timeout")
+	s.Equal(int32(30), timeout)
+}
+
+func (s *ContextsSuite) Test_loads_contexts_from_toml_config_file() {
+	path := s.writeConfigFile("config.toml", `
+context = "prod"
+
+[contexts.prod]
+engineEndpoint = "https://engine.example.com"
+`)
+	p := NewProvider()
+	s.Require().NoError(p.LoadConfigFile(path))
+
+	endpoint, isDefault := p.GetString("engineEndpoint")
+	s.Equal("https://engine.example.com", endpoint)
+	s.False(isDefault)
+}
+
+func (s *ContextsSuite) Test_contexts_that_are_not_tables_are_rejected() {
+	path := s.writeConfigFile("config.yaml", `
+contexts:
+  prod: https://engine.example.com
+`)
+	p := NewProvider()
+	err := p.LoadConfigFile(path)
+	s.Require().Error(err)
+	s.Contains(err.Error(), `context "prod" must be a table`)
+}
+
+func (s *ContextsSuite) Test_context_values_take_precedence_over_config_file_values() {
+	path := s.writeConfigFile("config.yaml", `
+engineEndpoint: http://localhost:8325
+connectProtocol: unix
+context: prod
+contexts:
+  prod:
+    engineEndpoint: https://engine.example.com
+`)
+	p := NewProvider()
+	s.Require().NoError(p.LoadConfigFile(path))
+
+	resolved := p.Resolve("engineEndpoint")
+	s.Equal("https://engine.example.com", resolved.Value)
+	s.Equal(SourceContext, resolved.Source)
+	s.Equal("prod", resolved.SourceName)
+
+	// Values that are not set in the context fall back to the config file.
+	protocol, _ := p.GetString("connectProtocol")
+	s.Equal("unix", protocol)
+}
+
+func (s *ContextsSuite) Test_expands_home_dir_in_context_auth_config_file() {
+	homeDir := filepath.Join(s.tempDir, "home")
+	s.T().Setenv("HOME", homeDir)
+	s.Require().NoError(os.MkdirAll(filepath.Join(homeDir, ".bluelink"), 0o700))
+	s.Require().NoError(os.WriteFile(
+		filepath.Join(homeDir, ".bluelink", "staging.auth.json"),
+		[]byte(`{"method": "apiKey", "apiKey": "env:BLUELINK_API_KEY"}`),
+		0o600,
+	))
+	path := s.writeConfigFile("config.toml", `
+context = "staging"
+
+[contexts.staging]
+engineAuthConfigFile = "~/.bluelink/staging.auth.json"
+`)
+	p := NewProvider()
+	s.Require().NoError(p.LoadConfigFile(path))
+
+	s.Equal(filepath.Join(homeDir, ".bluelink", "staging.auth.json"), EngineAuthConfigFile(p))
+	authConfig, err := LoadEngineAuthConfig(p)
+	s.Require().NoError(err)
+	s.Equal("apiKey", authConfig.Method)
+}
+
+func (s *ContextsSuite) Test_env_var_takes_precedence_over_context_values() {
+	path := s.writeConfigFile("config.yaml", `
+context: prod
+contexts:
+  prod:
+    engineEndpoint: https://engine.example.com
+`)
+	p := NewProvider()
+	s.Require().NoError(p.LoadConfigFile(path))
+	p.BindEnvVar("engineEndpoint", "TEST_CONTEXTS_ENGINE_ENDPOINT")
+	s.T().Setenv("TEST_CONTEXTS_ENGINE_ENDPOINT", "http://localhost:9000")
+
+	endpoint, _ := p.GetString("engineEndpoint")
+	s.Equal("http://localhost:9000", endpoint)
+}
+
+func (s *ContextsSuite) Test_context_selection_precedence() {
+	path := s.writeConfigFile("config.yaml", `
+context: dev
+contexts:
+  dev:
+    engineEndpoint: http://localhost:8325
+  staging:
+    engineEndpoint: https://staging.engine.example.com
+  prod:
+    engineEndpoint: https://engine.example.com
+`)
+	p := NewProvider()
+	s.Require().NoError(p.LoadConfigFile(path))
+	p.SetCurrentContextFile(filepath.Join(s.tempDir, "state", "current-context"))
+	s.Equal("dev", p.ActiveContextName())
+
+	s.Require().NoError(p.UseContext("staging"))
+	s.Equal("staging", p.ActiveContextName())
+
+	p.BindEnvVar(ContextConfigKey, "TEST_CONTEXTS_CONTEXT")
+	s.T().Setenv("TEST_CONTEXTS_CONTEXT", "prod")
+	s.Equal("prod", p.ActiveContextName())
+
+	flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flagSet.String("context", "", "Engine context")
+	s.Require().NoError(flagSet.Parse([]string{"--context=dev"}))
+	p.BindPFlag(ContextConfigKey, flagSet.Lookup("context"))
+	s.Equal("dev", p.ActiveContextName())
+}
+
+func (s *ContextsSuite) Test_use_context_fails_for_unknown_context() {
+	p := NewProvider()
+	p.SetCurrentContextFile(filepath.Join(s.tempDir, "current-context"))
+
+	err := p.UseContext("prod")
+	s.ErrorIs(err, ErrContextNotFound)
+	s.NoFileExists(filepath.Join(s.tempDir, "current-context"))
+}
+
+func (s *ContextsSuite) Test_active_context_fails_for_unknown_context() {
+	path := s.writeConfigFile("config.yaml", `
+engineEndpoint: http://localhost:8325
+context: prod
+`)
+	p := NewProvider()
+	s.Require().NoError(p.LoadConfigFile(path))
+
+	name, engineContext, err := p.ActiveContext()
+	s.ErrorIs(err, ErrContextNotFound)
+	s.Equal("prod", name)
+	s.Nil(engineContext)
+
+	// Engine settings from the config file are still resolved so that
+	// the error can be reported by the command using them.
+	endpoint, _ := p.GetString("engineEndpoint")
+	s.Equal("http://localhost:8325", endpoint)
+}
+
+func (s *ContextsSuite) Test_no_active_context_when_none_selected() {
+	p := NewProvider()
+
+	name, engineContext, err := p.ActiveContext()
+	s.NoError(err)
+	s.Empty(name)
+	s.Nil(engineContext)
+}
+
+func TestContextsSuite(t *testing.T) {
+	suite.Run(t, new(ContextsSuite))
+}
@@ -0,0 +1,258 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ConfigLayer identifies the level a discovered config file applies to.
+type ConfigLayer string
+
+const (
+	// ConfigLayerUser is the config file in the user's config directory
+	// (e.g. ~/.config/bluelink/bluelink.config.toml).
+	ConfigLayerUser ConfigLayer = "user"
+	// ConfigLayerRepository is the config file found by walking up from
+	// the working directory to the root of the repository.
+	ConfigLayerRepository ConfigLayer = "repository"
+	// ConfigLayerExplicit is the config file provided with the --config flag
+	// or the <PREFIX>_CONFIG environment variable.
+	ConfigLayerExplicit ConfigLayer = "explicit"
+)
+
+// Directories that mark the root of a version controlled repository,
+// the search for a repository config file stops at the first
+// directory that contains one of these.
+var vcsRootMarkers = []string{".git", ".hg", ".svn"}
+
+// DiscoveredConfigFile is a config file found by DiscoverConfigFiles.
+type DiscoveredConfigFile struct {
+	Path  string
+	Layer ConfigLayer
+}
+
+// DiscoveryOptions configures how config files are discovered.
+type DiscoveryOptions struct {
+	// FileName is the name of the config file to search for
+	// (e.g. "bluelink.config.toml").
+	FileName string
+	// AppName is the name of the directory in the user's config directory
+	// that holds the user config file (e.g. "bluelink").
+	AppName string
+	// WorkingDir is the directory the search for a repository config file
+	// starts from, defaults to the current working directory.
+	WorkingDir string
+	// UserConfigDir is the directory that holds the config directories of
+	// applications, defaults to UserConfigDir.
+	UserConfigDir string
+	// ExplicitFile is a config file path provided by the user,
+	// an error is returned if this file does not exist.
+	ExplicitFile string
+}
+
+// DiscoverConfigFiles finds the config files that apply to the CLI,
+// ordered from lowest to highest precedence:
+//  1. The user config file, <user config dir>/<app name>/<file name>
+//  2. The repository config file, the nearest file named <file name> found
+//     by walking up from the working directory to the repository root
+//  3. The explicit config file
+//
+// The repository search stops at the first directory that contains a
+// .git, .hg or .svn directory; when the working directory is not in a
+// repository, only the working directory is searched.
+func DiscoverConfigFiles(opts DiscoveryOptions) ([]DiscoveredConfigFile, error) {
+	files := []DiscoveredConfigFile{}
+	seen := map[string]bool{}
+	addFile := func(path string, layer ConfigLayer) {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			absPath = path
+		}
+		if seen[absPath] {
+			return
+		}
+		seen[absPath] = true
+		files = append(files, DiscoveredConfigFile{Path: path, Layer: layer})
+	}
+
+	if opts.FileName != "" {
+		if userFile := userConfigFilePath(opts); userFile != "" && fileExists(userFile) {
+			addFile(userFile, ConfigLayerUser)
+		}
+
+		repoFile, err := findRepositoryConfigFile(opts)
+		if err != nil {
+			return nil, err
+		}
+		if repoFile != "" {
+			addFile(repoFile, ConfigLayerRepository)
+		}
+	}
+
+	if opts.ExplicitFile != "" {
+		if _, err := os.Stat(opts.ExplicitFile); err != nil {
+			return nil, fmt.Errorf("config file %s could not be read: %w", opts.ExplicitFile, err)
+		}
+		addFile(opts.ExplicitFile, ConfigLayerExplicit)
+	}
+
+	return files, nil
+}
+
+// LoadConfigFiles discovers config files with DiscoverConfigFiles and
+// loads them in order so that values in the repository config file override
+// values in the user config file and values in the explicit config file
+// override both.
+// The config file each value was loaded from is reported by Resolve.
+func (p *Provider) LoadConfigFiles(opts DiscoveryOptions) ([]DiscoveredConfigFile, error) {
+	files, err := DiscoverConfigFiles(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		if err := p.LoadConfigFile(file.Path); err != nil {
+			return nil, fmt.Errorf("failed to load %s config file %s: %w", file.Layer, file.Path, err)
+		}
+	}
+	return files, nil
+}
+
+// SetConfigFileDiscovery sets the function that provides the options used to
+// discover config files in LoadDiscoveredConfigFiles.
+// The options are provided by a function as they can depend on configuration
+// values that are only known when the config files are loaded,
+// such as the path of an explicit config file set with a flag.
+func (p *Provider) SetConfigFileDiscovery(discoveryOptions func() DiscoveryOptions) {
+	p.configFileDiscovery = discoveryOptions
+}
+
+// LoadDiscoveredConfigFiles discovers and loads config files with the options
+// set with SetConfigFileDiscovery.
+// Config files are only loaded the first time this is called, later calls
+// return the error of the first call so that config files can be loaded
+// lazily by every code path that needs them, such as shell completion
+// which runs without the hooks of the root command.
+// Nothing is loaded when config file discovery has not been set.
+func (p *Provider) LoadDiscoveredConfigFiles() error {
+	if p.configFileDiscovery == nil || p.discoveredConfigFilesLoaded {
+		return p.discoveredConfigFilesErr
+	}
+
+	p.discoveredConfigFilesLoaded = true
+	_, p.discoveredConfigFilesErr = p.LoadConfigFiles(p.configFileDiscovery())
+	return p.discoveredConfigFilesErr
+}
+
+// UserConfigDir returns the directory that holds the config directories of
+// applications, $XDG_CONFIG_HOME when set, otherwise ~/.config on Unix systems
+// and the directory returned by os.UserConfigDir on Windows.
+func UserConfigDir() (string, error) {
+	if xdgConfigHome := os.Getenv("XDG_CONFIG_HOME"); xdgConfigHome != "" {
+		return xdgConfigHome, nil
+	}
+
+	if os.PathSeparator == '\\' {
+		return os.UserConfigDir()
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config"), nil
+}
+
+// ExpandHomeDir replaces a leading "~" path segment with the user's home
+// directory, paths in config files are not expanded by a shell.
+// The path is returned as it is when it does not start with "~/"
+// or the home directory can not be determined.
+func ExpandHomeDir(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") &&
+		!strings.HasPrefix(path, "~"+string(os.PathSeparator)) {
+		return path
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(homeDir, path[1:])
+}
+
+func userConfigFilePath(opts DiscoveryOptions) string {
+	configDir := opts.UserConfigDir
+	if configDir == "" {
+		var err error
+		configDir, err = UserConfigDir()
+		if err != nil {
+			return ""
+		}
+	}
+	return filepath.Join(configDir, opts.AppName, opts.FileName)
+}
+
+func findRepositoryConfigFile(opts DiscoveryOptions) (string, error) {
+	workingDir := opts.WorkingDir
+	if workingDir == "" {
+		var err error
+		workingDir, err = os.Getwd()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	workingDir, err := filepath.Abs(workingDir)
+	if err != nil {
+		return "", err
+	}
+
+	repoRoot := findRepositoryRoot(workingDir)
+	if repoRoot == "" {
+		candidate := filepath.Join(workingDir, opts.FileName)
+		if fileExists(candidate) {
+			return candidate, nil
+		}
+		return "", nil
+	}
+
+	dir := workingDir
+	for {
+		candidate := filepath.Join(dir, opts.FileName)
+		if fileExists(candidate) {
+			return candidate, nil
+		}
+		if dir == repoRoot {
+			return "", nil
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+func findRepositoryRoot(startDir string) string {
+	dir := startDir
+	for {
+		for _, marker := range vcsRootMarkers {
+			if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+				return dir
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return !errors.Is(err, os.ErrNotExist)
+	}
+	return !info.IsDir()
+}
@@ -0,0 +1,219 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type DiscoverySuite struct {
+	suite.Suite
+	userConfigDir string
+	repoDir       string
+	workingDir    string
+}
+
+func (s *DiscoverySuite) SetupTest() {
+	s.userConfigDir = s.T().TempDir()
+	s.repoDir = s.T().TempDir()
+	s.workingDir = filepath.Join(s.repoDir, "services", "orders")
+	s.Require().NoError(os.MkdirAll(filepath.Join(s.repoDir, ".git"), 0o755))
+	s.Require().NoError(os.MkdirAll(s.workingDir, 0o755))
+}
+
+func (s *DiscoverySuite) writeFile(path, content string) string {
+	s.Require().NoError(os.MkdirAll(filepath.Dir(path), 0o755))
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func (s *DiscoverySuite) options(explicitFile string) DiscoveryOptions {
+	return DiscoveryOptions{
+		FileName:      "bluelink.config.yaml",
+		AppName:       "bluelink",
+		WorkingDir:    s.workingDir,
+		UserConfigDir: s.userConfigDir,
+		ExplicitFile:  explicitFile,
+	}
+}
+
+func (s *DiscoverySuite) Test_discovers_user_repository_and_explicit_config_files_in_order() {
+	userFile := s.writeFile(
+		filepath.Join(s.userConfigDir, "bluelink", "bluelink.config.yaml"),
+		"engineEndpoint: http://localhost:8325\n",
+	)
+	repoFile := s.writeFile(
+		filepath.Join(s.repoDir, "bluelink.config.yaml"),
+		"deployInstanceName: orders\n",
+	)
+	explicitFile := s.writeFile(
+		filepath.Join(s.T().TempDir(), "ci.config.yaml"),
+		"deployInstanceName: orders-ci\n",
+	)
+
+	files, err := DiscoverConfigFiles(s.options(explicitFile))
+	s.Require().NoError(err)
+	s.Equal([]DiscoveredConfigFile{
+		{Path: userFile, Layer: ConfigLayerUser},
+		{Path: repoFile, Layer: ConfigLayerRepository},
+		{Path: explicitFile, Layer: ConfigLayerExplicit},
+	}, files)
+}
+
+func (s *DiscoverySuite) Test_uses_nearest_repository_config_file() {
+	s.writeFile(filepath.Join(s.repoDir, "bluelink.config.yaml"), "a: root\n")
+	nearest := s.writeFile(
+		filepath.Join(s.repoDir, "services", "bluelink.config.yaml"),
+		"a: services\n",
+	)
+
+	files, err := DiscoverConfigFiles(s.options(""))
+	s.Require().NoError(err)
+	s.Equal([]DiscoveredConfigFile{{Path: nearest, Layer: ConfigLayerRepository}}, files)
+}
+
+func (s *DiscoverySuite) Test_does_not_search_above_repository_root() {
+	// The parent of the repository is outside of the repository
+	// so its config file must not be picked up.
+	outerDir := s.T().TempDir()
+	s.repoDir = filepath.Join(outerDir, "repo")
+	s.workingDir = filepath.Join(s.repoDir, "services")
+	s.Require().NoError(os.MkdirAll(filepath.Join(s.repoDir, ".git"), 0o755))
+	s.Require().NoError(os.MkdirAll(s.workingDir, 0o755))
+	s.writeFile(filepath.Join(outerDir, "bluelink.config.yaml"), "a: outer\n")
+
+	files, err := DiscoverConfigFiles(s.options(""))
+	s.Require().NoError(err)
+	s.Empty(files)
+}
+
+func (s *DiscoverySuite) Test_only_searches_working_dir_outside_of_a_repository() {
+	outerDir := s.T().TempDir()
+	s.workingDir = filepath.Join(outerDir, "project")
+	s.Require().NoError(os.MkdirAll(s.workingDir, 0o755))
+	s.writeFile(filepath.Join(outerDir, "bluelink.config.yaml"), "a: outer\n")
+
+	files, err := DiscoverConfigFiles(s.options(""))
+	s.Require().NoError(err)
+	s.Empty(files)
+
+	inWorkingDir := s.writeFile(filepath.Join(s.workingDir, "bluelink.config.yaml"), "a: project\n")
+	files, err = DiscoverConfigFiles(s.options(""))
+	s.Require().NoError(err)
+	s.Equal([]DiscoveredConfigFile{{Path: inWorkingDir, Layer: ConfigLayerRepository}}, files)
+}
+
+func (s *DiscoverySuite) Test_explicit_config_file_that_is_also_discovered_is_loaded_once() {
+	repoFile := s.writeFile(filepath.Join(s.repoDir, "bluelink.config.yaml"), "a: repo\n")
+
+	files, err := DiscoverConfigFiles(s.options(repoFile))
+	s.Require().NoError(err)
+	s.Equal([]DiscoveredConfigFile{{Path: repoFile, Layer: ConfigLayerRepository}}, files)
+}
+
+func (s *DiscoverySuite) Test_fails_for_missing_explicit_config_file() {
+	_, err := DiscoverConfigFiles(s.options(filepath.Join(s.repoDir, "missing.yaml")))
+	s.Require().Error(err)
+	s.ErrorIs(err, os.ErrNotExist)
+}
+
+func (s *DiscoverySuite) Test_later_config_files_override_earlier_ones_with_provenance() {
+	userFile := s.writeFile(
+		filepath.Join(s.userConfigDir, "bluelink", "bluelink.config.yaml"),
+		`
+engineEndpoint: http://localhost:8325
+deploy:
+  instanceName: user-instance
+  contextVariables:
+    environment: development
+    region: eu-west-2
+`,
+	)
+	repoFile := s.writeFile(
+		filepath.Join(s.repoDir, "bluelink.config.yaml"),
+		`
+deploy:
+  instanceName: orders
+  contextVariables:
+    environment: staging
+`,
+	)
+	explicitFile := s.writeFile(
+		filepath.Join(s.T().TempDir(), "ci.config.json"),
+		`{"deploy": {"instanceName": "orders-ci"}}`,
+	)
+
+	p := NewProvider()
+	files, err := p.LoadConfigFiles(s.options(explicitFile))
+	s.Require().NoError(err)
+	s.Len(files, 3)
+	s.Equal([]string{userFile, repoFile, explicitFile}, p.ConfigFiles())
+	s.Equal(explicitFile, p.ConfigFile())
+
+	instanceName := p.Resolve("deploy.instanceName")
+	s.Equal("orders-ci", instanceName.Value)
+	s.Equal(SourceConfigFile, instanceName.Source)
+	s.Equal(explicitFile, instanceName.SourceName)
+
+	endpoint := p.Resolve("engineEndpoint")
+	s.Equal("http://localhost:8325", endpoint.Value)
+	s.Equal(userFile, endpoint.SourceName)
+
+	environment := p.Resolve("deploy.contextVariables.environment")
+	s.Equal("staging", environment.Value)
+	s.Equal(repoFile, environment.SourceName)
+
+	contextVariables, _, err := p.GetStringMap("deploy.contextVariables")
+	s.Require().NoError(err)
+	s.Equal(map[string]string{"environment": "staging", "region": "eu-west-2"}, contextVariables)
+}
+
+func (s *DiscoverySuite) Test_reports_config_file_that_fails_to_load() {
+	repoFile := s.writeFile(filepath.Join(s.repoDir, "bluelink.config.yaml"), "- not\n- a mapping\n")
+
+	_, err := NewProvider().LoadConfigFiles(s.options(""))
+	s.Require().Error(err)
+	s.Contains(err.Error(), "repository config file "+repoFile)
+}
+
+func (s *DiscoverySuite) Test_loads_discovered_config_files_once() {
+	userFile := s.writeFile(
+		filepath.Join(s.userConfigDir, "bluelink", "bluelink.config.yaml"),
+		"engineEndpoint: http://localhost:8325\n",
+	)
+	p := NewProvider()
+	s.Require().NoError(p.LoadDiscoveredConfigFiles())
+
+	discoveries := 0
+	p.SetConfigFileDiscovery(func() DiscoveryOptions {
+		discoveries += 1
+		return s.options("")
+	})
+	s.Require().NoError(p.LoadDiscoveredConfigFiles())
+	s.Require().NoError(p.LoadDiscoveredConfigFiles())
+
+	s.Equal(1, discoveries)
+	resolved := p.Resolve("engineEndpoint")
+	s.Equal("http://localhost:8325", resolved.Value)
+	s.Equal(userFile, resolved.SourceName)
+}
+
+func (s *DiscoverySuite) Test_user_config_dir_defaults_to_xdg_config_home() {
+	s.T().Setenv("XDG_CONFIG_HOME", s.userConfigDir)
+	userFile := s.writeFile(
+		filepath.Join(s.userConfigDir, "bluelink", "bluelink.config.yaml"),
+		"a: user\n",
+	)
+
+	opts := s.options("")
+	opts.UserConfigDir = ""
+	files, err := DiscoverConfigFiles(opts)
+	s.Require().NoError(err)
+	s.Equal([]DiscoveredConfigFile{{Path: userFile, Layer: ConfigLayerUser}}, files)
+}
+
+func TestDiscoverySuite(t *testing.T) {
+	suite.Run(t, new(DiscoverySuite))
+}
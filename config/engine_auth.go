@@ -2,7 +2,10 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
+	"strings"
 )
 
 // EngineAuthConfig is the configuration for the deploy engine authentication.
@@ -46,12 +49,23 @@ type BluelinkSignatureV1KeyPair struct {
 	SecretKey string `json:"secretKey"`
 }
 
+// EngineAuthConfigFile returns the path of the engine auth config file
+// set with the engineAuthConfigFile config value, with a leading "~/"
+// expanded to the user's home directory.
+func EngineAuthConfigFile(confProvider *Provider) string {
+	engineAuthConfigFile, _ := confProvider.GetString("engineAuthConfigFile")
+	return ExpandHomeDir(engineAuthConfigFile)
+}
+
 // LoadEngineAuthConfig loads the engine authentication configuration from
 // an auth config file.
+// Secret fields (apiKey, oauth2.clientSecret and bluelinkSignatureV1.keyPair.secretKey)
+// can hold secret references (env:NAME, file:/path or exec:command) instead of
+// plaintext secrets, these are left as they are in the loaded config
+// so that they are only resolved when needed, see ResolveSecrets.
 func LoadEngineAuthConfig(confProvider *Provider) (*EngineAuthConfig, error) {
 	engineAuthConfig := &EngineAuthConfig{}
-	engineAuthConfigFile, _ := confProvider.GetString("engineAuthConfigFile")
-	engineAuthConfigBytes, err := os.ReadFile(engineAuthConfigFile)
+	engineAuthConfigBytes, err := os.ReadFile(EngineAuthConfigFile(confProvider))
 	if err != nil {
 		return nil, err
 	}
@@ -63,3 +77,128 @@ func LoadEngineAuthConfig(confProvider *Provider) (*EngineAuthConfig, error) {
 
 	return engineAuthConfig, nil
 }
+
+// Validate checks that the auth config uses a supported auth method
+// and that the fields required by the method are set.
+func (c *EngineAuthConfig) Validate() error {
+	switch c.Method {
+	case "apiKey":
+		if c.APIKey == "" {
+			return errors.New("the apiKey auth method requires an apiKey to be set")
+		}
+	case "oauth2":
+		if c.OAuth2 == nil {
+			return errors.New("the oauth2 auth method requires an oauth2 section to be set")
+		}
+		if c.OAuth2.ClientID == "" || c.OAuth2.ClientSecret == "" {
+			return errors.New("the oauth2 auth method requires oauth2.clientId and oauth2.clientSecret to be set")
+		}
+		if c.OAuth2.ProviderBaseURL == "" && c.OAuth2.TokenEndpoint == "" {
+			return errors.New(
+				"the oauth2 auth method requires one of oauth2.providerBaseURL or oauth2.tokenEndpoint to be set",
+			)
+		}
+	case "bluelinkSignatureV1":
+		if c.BluelinkSignatureV1 == nil {
+			return errors.New(
+				"the bluelinkSignatureV1 auth method requires a bluelinkSignatureV1 section to be set",
+			)
+		}
+		keyPair := c.BluelinkSignatureV1.KeyPair
+		if keyPair.KeyID == "" || keyPair.SecretKey == "" {
+			return errors.New(
+				"the bluelinkSignatureV1 auth method requires bluelinkSignatureV1.keyPair.keyId " +
+					"and bluelinkSignatureV1.keyPair.secretKey to be set",
+			)
+		}
+	case "":
+		return errors.New("an auth method must be set")
+	default:
+		return fmt.Errorf(
+			"invalid auth method: %s, must be either 'apiKey', 'oauth2' or 'bluelinkSignatureV1'",
+			c.Method,
+		)
+	}
+
+	for _, field := range c.secretFields() {
+		if isEmptySecretRef(*field.value) {
+			return fmt.Errorf(
+				"%s: %w %q: a reference target must be provided",
+				field.name,
+				ErrInvalidSecretRef,
+				*field.value,
+			)
+		}
+	}
+	return nil
+}
+
+// ResolveSecrets returns a copy of the auth config with the secret references
+// of the secret fields used by the configured auth method replaced
+// with the secrets they reference.
+// Secrets of other auth methods are not resolved so that, for example,
+// a credential helper for OAuth2 is not run when the apiKey method is used.
+func (c *EngineAuthConfig) ResolveSecrets() (*EngineAuthConfig, error) {
+	resolved := *c
+	if c.OAuth2 != nil {
+		oauth2 := *c.OAuth2
+		resolved.OAuth2 = &oauth2
+	}
+	if c.BluelinkSignatureV1 != nil {
+		bluelinkSignatureV1 := *c.BluelinkSignatureV1
+		resolved.BluelinkSignatureV1 = &bluelinkSignatureV1
+	}
+
+	for _, field := range resolved.secretFields() {
+		secret, err := ResolveSecretRef(*field.value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", field.name, err)
+		}
+		*field.value = secret
+	}
+	return &resolved, nil
+}
+
+// HasPlaintextSecrets determines whether any of the secret fields used by the
+// configured auth method hold a plaintext secret instead of a secret reference.
+func (c *EngineAuthConfig) HasPlaintextSecrets() bool {
+	for _, field := range c.secretFields() {
+		if *field.value != "" && !IsSecretRef(*field.value) {
+			return true
+		}
+	}
+	return false
+}
+
+type secretField struct {
+	name  string
+	value *string
+}
+
+func (c *EngineAuthConfig) secretFields() []secretField {
+	switch c.Method {
+	case "apiKey":
+		return []secretField{{name: "apiKey", value: &c.APIKey}}
+	case "oauth2":
+		if c.OAuth2 != nil {
+			return []secretField{{name: "oauth2.clientSecret", value: &c.OAuth2.ClientSecret}}
+		}
+	case "bluelinkSignatureV1":
+		if c.BluelinkSignatureV1 != nil {
+			return []secretField{{
+				name:  "bluelinkSignatureV1.keyPair.secretKey",
+				value: &c.BluelinkSignatureV1.KeyPair.SecretKey,
+			}}
+		}
+	}
+	return nil
+}
+
+func isEmptySecretRef(value string) bool {
+	for _, prefix := range []string{SecretRefEnvPrefix, SecretRefFilePrefix, SecretRefExecPrefix} {
+		if strings.HasPrefix(value, prefix) && strings.TrimSpace(strings.TrimPrefix(value, prefix)) == "" {
+			return true
+		}
+	}
+	return false
+}
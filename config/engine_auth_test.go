@@ -0,0 +1,130 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type EngineAuthConfigSuite struct {
+	suite.Suite
+}
+
+func (s *EngineAuthConfigSuite) Test_validate_accepts_api_key_config() {
+	authConfig := &EngineAuthConfig{Method: "apiKey", APIKey: "test-key"}
+	s.NoError(authConfig.Validate())
+}
+
+func (s *EngineAuthConfigSuite) Test_validate_accepts_oauth2_config_with_token_endpoint() {
+	authConfig := &EngineAuthConfig{
+		Method: "oauth2",
+		OAuth2: &OAuth2Config{
+			ClientID:      "client-id",
+			ClientSecret:  "client-secret",
+			TokenEndpoint: "https://auth.example.com/oauth2/token",
+		},
+	}
+	s.NoError(authConfig.Validate())
+}
+
+func (s *EngineAuthConfigSuite) Test_validate_accepts_bluelink_signature_v1_config() {
+	authConfig := &EngineAuthConfig{
+		Method: "bluelinkSignatureV1",
+		BluelinkSignatureV1: &BluelinkSignatureV1Config{
+			KeyPair: BluelinkSignatureV1KeyPair{KeyID: "key-id", SecretKey: "secret"},
+		},
+	}
+	s.NoError(authConfig.Validate())
+}
+
+func (s *EngineAuthConfigSuite) Test_validate_rejects_missing_method() {
+	err := (&EngineAuthConfig{}).Validate()
+	s.Require().Error(err)
+	s.Contains(err.Error(), "an auth method must be set")
+}
+
+func (s *EngineAuthConfigSuite) Test_validate_rejects_unknown_method() {
+	err := (&EngineAuthConfig{Method: "basic"}).Validate()
+	s.Require().Error(err)
+	s.Contains(err.Error(), "invalid auth method: basic")
+}
+
+func (s *EngineAuthConfigSuite) Test_validate_rejects_api_key_method_without_key() {
+	err := (&EngineAuthConfig{Method: "apiKey"}).Validate()
+	s.Require().Error(err)
+	s.Contains(err.Error(), "requires an apiKey")
+}
+
+func (s *EngineAuthConfigSuite) Test_validate_rejects_oauth2_method_without_provider() {
+	authConfig := &EngineAuthConfig{
+		Method: "oauth2",
+		OAuth2: &OAuth2Config{ClientID: "client-id", ClientSecret: "client-secret"},
+	}
+	err := authConfig.Validate()
+	s.Require().Error(err)
+	s.Contains(err.Error(), "oauth2.providerBaseURL or oauth2.tokenEndpoint")
+}
+
+func (s *EngineAuthConfigSuite) Test_validate_rejects_bluelink_signature_v1_method_without_key_pair() {
+	err := (&EngineAuthConfig{
+		Method:              "bluelinkSignatureV1",
+		BluelinkSignatureV1: &BluelinkSignatureV1Config{},
+	}).Validate()
+	s.Require().Error(err)
+	s.Contains(err.Error(), "keyPair.keyId")
+}
+
+func (s *EngineAuthConfigSuite) Test_validate_rejects_secret_reference_without_target() {
+	err := (&EngineAuthConfig{Method: "apiKey", APIKey: "env:"}).Validate()
+	s.Require().Error(err)
+	s.ErrorIs(err, ErrInvalidSecretRef)
+	s.Contains(err.Error(), "apiKey")
+}
+
+func (s *EngineAuthConfigSuite) Test_resolve_secrets_resolves_references_of_configured_method() {
+	s.T().Setenv("TEST_ENGINE_AUTH_CLIENT_SECRET", "client-secret")
+	authConfig := &EngineAuthConfig{
+		Method: "oauth2",
+		// The API key is not used by the oauth2 method so it must not be resolved.
+		APIKey: "env:TEST_ENGINE_AUTH_UNSET_API_KEY",
+		OAuth2: &OAuth2Config{
+			ClientID:      "client-id",
+			ClientSecret:  "env:TEST_ENGINE_AUTH_CLIENT_SECRET",
+			TokenEndpoint: "https://auth.example.com/oauth2/token",
+		},
+	}
+
+	resolved, err := authConfig.ResolveSecrets()
+	s.Require().NoError(err)
+	s.Equal("client-secret", resolved.OAuth2.ClientSecret)
+	s.Equal("env:TEST_ENGINE_AUTH_UNSET_API_KEY", resolved.APIKey)
+	// The loaded config keeps the references.
+	s.Equal("env:TEST_ENGINE_AUTH_CLIENT_SECRET", authConfig.OAuth2.ClientSecret)
+}
+
+func (s *EngineAuthConfigSuite) Test_resolve_secrets_reports_field_that_failed() {
+	authConfig := &EngineAuthConfig{
+		Method: "bluelinkSignatureV1",
+		BluelinkSignatureV1: &BluelinkSignatureV1Config{
+			KeyPair: BluelinkSignatureV1KeyPair{
+				KeyID:     "key-id",
+				SecretKey: "file:" + filepath.Join(s.T().TempDir(), "missing"),
+			},
+		},
+	}
+
+	_, err := authConfig.ResolveSecrets()
+	s.Require().Error(err)
+	s.ErrorIs(err, ErrInvalidSecretRef)
+	s.Contains(err.Error(), "bluelinkSignatureV1.keyPair.secretKey")
+}
+
+func (s *EngineAuthConfigSuite) Test_has_plaintext_secrets() {
+	s.True((&EngineAuthConfig{Method: "apiKey", APIKey: "test-key"}).HasPlaintextSecrets())
+	s.False((&EngineAuthConfig{Method: "apiKey", APIKey: "exec:helper"}).HasPlaintextSecrets())
+}
+
+func TestEngineAuthConfigSuite(t *testing.T) {
+	suite.Run(t, new(EngineAuthConfigSuite))
+}
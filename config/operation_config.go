@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
 	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
 )
 
@@ -27,6 +28,12 @@ func LoadOperationConfig(confProvider *Provider) (*types.BlueprintOperationConfi
 		return nil, nil
 	}
 
+	return LoadOperationConfigFile(path)
+}
+
+// LoadOperationConfigFile loads the blueprint operation config from the
+// deploy config file at the given path.
+func LoadOperationConfigFile(path string) (*types.BlueprintOperationConfig, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("reading deploy config file %s: %w", path, err)
@@ -39,3 +46,53 @@ func LoadOperationConfig(confProvider *Provider) (*types.BlueprintOperationConfi
 
 	return &opConfig, nil
 }
+
+// SaveBlueprintVariables writes blueprint variable values to the
+// blueprintVariables section of the deploy config file at the given path,
+// creating the file if it does not exist.
+// Existing variable values are replaced and all other content of the
+// file is preserved.
+func SaveBlueprintVariables(path string, values map[string]*core.ScalarValue) error {
+	content := map[string]json.RawMessage{}
+	fileMode := os.FileMode(0o644)
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading deploy config file %s: %w", path, err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &content); err != nil {
+			return fmt.Errorf("parsing deploy config file %s: %w", path, err)
+		}
+		if info, statErr := os.Stat(path); statErr == nil {
+			fileMode = info.Mode().Perm()
+		}
+	}
+
+	variables := map[string]*core.ScalarValue{}
+	if existing, hasVariables := content["blueprintVariables"]; hasVariables {
+		if err := json.Unmarshal(existing, &variables); err != nil {
+			return fmt.Errorf("parsing blueprint variables in deploy config file %s: %w", path, err)
+		}
+		if variables == nil {
+			variables = map[string]*core.ScalarValue{}
+		}
+	}
+	for name, value := range values {
+		variables[name] = value
+	}
+
+	variablesJSON, err := json.Marshal(variables)
+	if err != nil {
+		return err
+	}
+	content["blueprintVariables"] = variablesJSON
+
+	output, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(output, '\n'), fileMode); err != nil {
+		return fmt.Errorf("writing deploy config file %s: %w", path, err)
+	}
+	return nil
+}
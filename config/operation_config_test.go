@@ -0,0 +1,70 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/stretchr/testify/suite"
+)
+
+type OperationConfigSuite struct {
+	suite.Suite
+	tempDir string
+}
+
+func (s *OperationConfigSuite) SetupTest() {
+	s.tempDir = s.T().TempDir()
+}
+
+func (s *OperationConfigSuite) Test_saves_blueprint_variables_preserving_existing_content() {
+	path := filepath.Join(s.tempDir, "deploy.json")
+	s.Require().NoError(os.WriteFile(path, []byte(`{
+  "providers": {"aws": {"region": "eu-west-1"}},
+  "blueprintVariables": {"logLevel": "debug", "replicas": 1},
+  "custom": {"kept": true}
+}`), 0o600))
+
+	err := SaveBlueprintVariables(path, map[string]*core.ScalarValue{
+		"replicas": core.ScalarFromInt(3),
+		"region":   core.ScalarFromString("eu-west-2"),
+	})
+	s.Require().NoError(err)
+
+	opConfig, err := LoadOperationConfigFile(path)
+	s.Require().NoError(err)
+	s.Equal(map[string]*core.ScalarValue{
+		"logLevel": core.ScalarFromString("debug"),
+		"replicas": core.ScalarFromInt(3),
+		"region":   core.ScalarFromString("eu-west-2"),
+	}, opConfig.BlueprintVariables)
+	s.Equal(core.ScalarFromString("eu-west-1"), opConfig.Providers["aws"]["region"])
+
+	data, err := os.ReadFile(path)
+	s.Require().NoError(err)
+	s.Contains(string(data), `"custom"`)
+
+	info, err := os.Stat(path)
+	s.Require().NoError(err)
+	s.Equal(os.FileMode(0o600), info.Mode().Perm())
+}
+
+func (s *OperationConfigSuite) Test_creates_deploy_config_file_when_saving_to_a_new_file() {
+	path := filepath.Join(s.tempDir, "new.deploy.json")
+
+	err := SaveBlueprintVariables(path, map[string]*core.ScalarValue{
+		"enabled": core.ScalarFromBool(true),
+	})
+	s.Require().NoError(err)
+
+	opConfig, err := LoadOperationConfigFile(path)
+	s.Require().NoError(err)
+	s.Equal(map[string]*core.ScalarValue{
+		"enabled": core.ScalarFromBool(true),
+	}, opConfig.BlueprintVariables)
+}
+
+func TestOperationConfigSuite(t *testing.T) {
+	suite.Run(t, new(OperationConfigSuite))
+}
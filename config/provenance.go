@@ -0,0 +1,241 @@
+package config
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// ValueSource is the source a configuration value was resolved from.
+type ValueSource string
+
+const (
+	// SourceFlag indicates the value was set with a command line flag.
+	SourceFlag ValueSource = "flag"
+	// SourceEnvVar indicates the value was set with an environment variable.
+	SourceEnvVar ValueSource = "env"
+	// SourceContext indicates the value was taken from the active engine context.
+	SourceContext ValueSource = "context"
+	// SourceConfigFile indicates the value was set in the config file.
+	SourceConfigFile ValueSource = "config file"
+	// SourceVarFile indicates a variable value was set in a var file
+	// provided with --var-file, see VariableOverride.
+	SourceVarFile ValueSource = "var file"
+	// SourceFlagDefault indicates the value is the default value of a flag.
+	SourceFlagDefault ValueSource = "flag default"
+	// SourceDefault indicates the value is a default set on the provider.
+	SourceDefault ValueSource = "default"
+	// SourceUnset indicates no value has been set for the configuration value.
+	SourceUnset ValueSource = "unset"
+)
+
+// ResolvedValue holds the effective value of a configuration value
+// along with where the value came from.
+type ResolvedValue struct {
+	Key   string
+	Value string
+	// Source is the source that the value was resolved from.
+	Source ValueSource
+	// SourceName identifies where in the source the value was found,
+	// this is the flag name (e.g. "--instance-name") for flags and flag defaults,
+	// the environment variable name for environment variables,
+	// the context name for the active engine context
+	// and the file path for the config file.
+	// This is empty for provider defaults and unset values.
+	SourceName string
+}
+
+// IsDefault returns true when the value was not set by the user.
+func (r ResolvedValue) IsDefault() bool {
+	return r.Source == SourceFlagDefault ||
+		r.Source == SourceDefault ||
+		r.Source == SourceUnset
+}
+
+// Resolve returns the effective value of a configuration value along with
+// the source it was resolved from, following the precedence order
+// described for Provider.
+// Lists and tables from config files are formatted in the form accepted
+// for environment variables, e.g. "a,b" for lists and "a=1,b=2" for tables.
+func (p *Provider) Resolve(configName string) ResolvedValue {
+	resolved, _ := p.resolve(configName)
+	return resolved
+}
+
+// resolve returns the effective value of a configuration value along with
+// the raw value it was resolved from when the value is not a plain string,
+// this is the flag for flags and flag defaults and the list or table
+// for lists and tables from config files.
+func (p *Provider) resolve(configName string) (ResolvedValue, any) {
+	flag, hasFlag := p.pFlags[configName]
+	defaultFlagValue := ""
+	if hasFlag && flag != nil {
+		value := flag.Value.String()
+		if strings.TrimSpace(value) != "" {
+			if flag.Changed {
+				// Flag set by user.
+				return ResolvedValue{
+					Key:        configName,
+					Value:      value,
+					Source:     SourceFlag,
+					SourceName: "--" + flag.Name,
+				}, flag
+			} else {
+				// Flag not set by user, fallback to default value.
+				defaultFlagValue = value
+			}
+		}
+	}
+
+	envVarName, hasEnvVarName := p.envVars[configName]
+	if hasEnvVarName {
+		envVar, envVarExists := os.LookupEnv(envVarName)
+		if envVarExists && strings.TrimSpace(envVar) != "" {
+			return ResolvedValue{
+				Key:        configName,
+				Value:      envVar,
+				Source:     SourceEnvVar,
+				SourceName: envVarName,
+			}, nil
+		}
+	}
+
+	contextValue, contextName, hasContextValue := p.resolveFromContext(configName)
+	if hasContextValue {
+		return ResolvedValue{
+			Key:        configName,
+			Value:      contextValue,
+			Source:     SourceContext,
+			SourceName: contextName,
+		}, nil
+	}
+
+	configValue, hasConfigValue := p.config[configName]
+	if hasConfigValue {
+		return ResolvedValue{
+			Key:        configName,
+			Value:      configValue,
+			Source:     SourceConfigFile,
+			SourceName: p.configSources[configName],
+		}, nil
+	}
+
+	complexValue, hasComplexValue := p.configValues[configName]
+	if hasComplexValue {
+		return ResolvedValue{
+			Key:        configName,
+			Value:      formatConfigValue(complexValue),
+			Source:     SourceConfigFile,
+			SourceName: p.configSources[configName],
+		}, complexValue
+	}
+
+	if defaultFlagValue != "" {
+		return ResolvedValue{
+			Key:        configName,
+			Value:      defaultFlagValue,
+			Source:     SourceFlagDefault,
+			SourceName: "--" + flag.Name,
+		}, flag
+	}
+
+	defaultValue, hasDefault := p.defaults[configName]
+	if hasDefault {
+		return ResolvedValue{
+			Key:    configName,
+			Value:  defaultValue,
+			Source: SourceDefault,
+		}, nil
+	}
+
+	return ResolvedValue{
+		Key:    configName,
+		Source: SourceUnset,
+	}, nil
+}
+
+// Keys returns the names of all configuration values known to the provider,
+// sorted by name. This includes values bound to flags or environment
+// variables, values with provider defaults and values set in the config file.
+// Values in nested tables of the config file are listed by their key path
+// (e.g. "deploy.instanceName") rather than by the name of the table.
+func (p *Provider) Keys() []string {
+	keySet := map[string]struct{}{}
+	for key := range p.pFlags {
+		keySet[key] = struct{}{}
+	}
+	for key := range p.envVars {
+		keySet[key] = struct{}{}
+	}
+	for key := range p.defaults {
+		keySet[key] = struct{}{}
+	}
+	for key := range p.config {
+		keySet[key] = struct{}{}
+	}
+	for key, value := range p.configValues {
+		if _, isTable := value.(map[string]any); !isTable {
+			keySet[key] = struct{}{}
+		}
+	}
+
+	keys := make([]string, 0, len(keySet))
+	for key := range keySet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// EnvVarName returns the environment variable bound to a configuration value,
+// or an empty string if no environment variable is bound.
+func (p *Provider) EnvVarName(configName string) string {
+	return p.envVars[configName]
+}
+
+// ConfigFile returns the path of the last config file loaded by the provider,
+// or an empty string if no config file has been loaded.
+func (p *Provider) ConfigFile() string {
+	return p.configFile
+}
+
+// ConfigFiles returns the paths of all config files loaded by the provider
+// in the order they were loaded, where values in later files take
+// precedence over values in earlier files.
+func (p *Provider) ConfigFiles() []string {
+	return append([]string{}, p.configFiles...)
+}
+
+// RedactedValue replaces the value of secret configuration values
+// when they are displayed.
+const RedactedValue = "********"
+
+// Redacted returns a copy of the resolved value with the value replaced
+// by RedactedValue when the key holds a secret and a value is set.
+func (r ResolvedValue) Redacted() ResolvedValue {
+	if r.Value != "" && IsSecretKey(r.Key) {
+		r.Value = RedactedValue
+	}
+	return r
+}
+
+var secretKeyMarkers = []string{
+	"apikey",
+	"secret",
+	"password",
+	"token",
+	"privatekey",
+}
+
+// IsSecretKey returns true when the name of a configuration value
+// indicates it holds a secret (e.g. an API key or password) that should
+// be redacted when displayed.
+func IsSecretKey(configName string) bool {
+	lowerName := strings.ToLower(configName)
+	for _, marker := range secretKeyMarkers {
+		if strings.Contains(lowerName, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProvenanceSuite struct {
+	suite.Suite
+	tempDir string
+}
+
+func (s *ProvenanceSuite) SetupTest() {
+	s.tempDir = s.T().TempDir()
+}
+
+func (s *ProvenanceSuite) Test_resolve_reports_flag_source() {
+	p := NewProvider()
+	flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flagSet.String("instance-name", "", "Instance name")
+	flagSet.Parse([]string{"--instance-name=orders-api"})
+	p.BindPFlag("deployInstanceName", flagSet.Lookup("instance-name"))
+	p.BindEnvVar("deployInstanceName", "TEST_DEPLOY_INSTANCE_NAME")
+	os.Setenv("TEST_DEPLOY_INSTANCE_NAME", "env-value")
+	defer os.Unsetenv("TEST_DEPLOY_INSTANCE_NAME")
+
+	resolved := p.Resolve("deployInstanceName")
+	s.Equal("orders-api", resolved.Value)
+	s.Equal(SourceFlag, resolved.Source)
+	s.Equal("--instance-name", resolved.SourceName)
+	s.False(resolved.IsDefault())
+}
+
+func (s *ProvenanceSuite) Test_resolve_reports_env_var_source() {
+	p := NewProvider()
+	p.BindEnvVar("engineEndpoint", "TEST_ENGINE_ENDPOINT")
+	os.Setenv("TEST_ENGINE_ENDPOINT", "http://engine.example.com")
+	defer os.Unsetenv("TEST_ENGINE_ENDPOINT")
+
+	resolved := p.Resolve("engineEndpoint")
+	s.Equal("http://engine.example.com", resolved.Value)
+	s.Equal(SourceEnvVar, resolved.Source)
+	s.Equal("TEST_ENGINE_ENDPOINT", resolved.SourceName)
+}
+
+func (s *ProvenanceSuite) Test_resolve_reports_config_file_source() {
+	path := filepath.Join(s.tempDir, "config.toml")
+	s.Require().NoError(os.WriteFile(path, []byte(`engineEndpoint = "http://localhost:8325"`), 0o600))
+	p := NewProvider()
+	s.Require().NoError(p.LoadConfigFile(path))
+
+	resolved := p.Resolve("engineEndpoint")
+	s.Equal("http://localhost:8325", resolved.Value)
+	s.Equal(SourceConfigFile, resolved.Source)
+	s.Equal(path, resolved.SourceName)
+	s.Equal(path, p.ConfigFile())
+}
+
+func (s *ProvenanceSuite) Test_resolve_reports_flag_default_source() {
+	p := NewProvider()
+	flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flagSet.String("blueprint-file", "app.blueprint.yaml", "Blueprint file")
+	flagSet.Parse([]string{})
+	p.BindPFlag("deployBlueprintFile", flagSet.Lookup("blueprint-file"))
+
+	resolved := p.Resolve("deployBlueprintFile")
+	s.Equal("app.blueprint.yaml", resolved.Value)
+	s.Equal(SourceFlagDefault, resolved.Source)
+	s.Equal("--blueprint-file", resolved.SourceName)
+	s.True(resolved.IsDefault())
+}
+
+func (s *ProvenanceSuite) Test_resolve_reports_provider_default_and_unset_sources() {
+	p := NewProvider()
+	p.SetDefault("connectProtocol", "tcp")
+
+	s.Equal(SourceDefault, p.Resolve("connectProtocol").Source)
+
+	unset := p.Resolve("engineEndpoint")
+	s.Equal(SourceUnset, unset.Source)
+	s.Equal("", unset.Value)
+	s.True(unset.IsDefault())
+}
+
+func (s *ProvenanceSuite) Test_keys_lists_all_known_keys_sorted() {
+	path := filepath.Join(s.tempDir, "config.yaml")
+	s.Require().NoError(os.WriteFile(path, []byte("engineEndpoint: http://localhost:8325\n"), 0o600))
+	p := NewProvider()
+	s.Require().NoError(p.LoadConfigFile(path))
+	p.SetDefault("connectProtocol", "tcp")
+	p.BindEnvVar("stageJson", "TEST_STAGE_JSON")
+	flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flagSet.String("instance-name", "", "Instance name")
+	p.BindPFlag("deployInstanceName", flagSet.Lookup("instance-name"))
+	p.BindEnvVar("deployInstanceName", "TEST_DEPLOY_INSTANCE_NAME")
+
+	s.Equal(
+		[]string{"connectProtocol", "deployInstanceName", "engineEndpoint", "stageJson"},
+		p.Keys(),
+	)
+	s.Equal("TEST_DEPLOY_INSTANCE_NAME", p.EnvVarName("deployInstanceName"))
+}
+
+func (s *ProvenanceSuite) Test_is_secret_key() {
+	s.True(IsSecretKey("apiKey"))
+	s.True(IsSecretKey("oauth2ClientSecret"))
+	s.True(IsSecretKey("postgresPassword"))
+	s.False(IsSecretKey("engineEndpoint"))
+	s.False(IsSecretKey("deployInstanceName"))
+}
+
+func (s *ProvenanceSuite) Test_redacted_hides_secret_values() {
+	secret := ResolvedValue{Key: "apiKey", Value: "test-key", Source: SourceEnvVar}
+	s.Equal(RedactedValue, secret.Redacted().Value)
+	s.Equal(SourceEnvVar, secret.Redacted().Source)
+
+	unsetSecret := ResolvedValue{Key: "apiKey", Source: SourceUnset}
+	s.Equal("", unsetSecret.Redacted().Value)
+
+	plain := ResolvedValue{Key: "engineEndpoint", Value: "http://localhost:8325"}
+	s.Equal("http://localhost:8325", plain.Redacted().Value)
+}
+
+func TestProvenanceSuite(t *testing.T) {
+	suite.Run(t, new(ProvenanceSuite))
+}
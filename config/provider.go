@@ -1,15 +1,12 @@
 package config
 
 import (
-	"encoding/json"
 	"errors"
 	"os"
 	"strconv"
 	"strings"
 
-	"github.com/BurntSushi/toml"
 	"github.com/spf13/pflag"
-	"gopkg.in/yaml.v3"
 )
 
 // Provider is a simple config provider for the CLI that can fall back to
@@ -21,53 +18,91 @@ import (
 // The precendence of config values is as follows:
 // 1. Flags
 // 2. Environment variables
-// 3. Config file
-// 4. Flag defaults
-// 5. Config provider defaults
+// 3. Active engine context (engine connection settings only, see EngineContext)
+// 4. Config file
+// 5. Flag defaults
+// 6. Config provider defaults
 //
-// Provider only supports strings, booleans, integers and floats
-// as configuration values. Complex types such as arrays, structs and maps
-// are not supported.
+// Provider supports strings, booleans, integers, floats and durations
+// as configuration values along with lists of strings and maps of strings,
+// see GetStringSlice and GetStringMap.
+// Nested tables in config files are accessed with dot-separated key paths,
+// for example "deploy.instanceName" for the instanceName key of
+// a deploy table.
 //
 // YAML, JSON and TOML are supported as config file formats.
 type Provider struct {
-	config   map[string]string
-	pFlags   map[string]*pflag.Flag
-	envVars  map[string]string
-	defaults map[string]string
+	config     map[string]string
+	configFile string
+	// configFiles holds the paths of all loaded config files
+	// in the order they were loaded.
+	configFiles []string
+	// configSources holds the path of the config file
+	// each config file value was loaded from.
+	configSources map[string]string
+	pFlags        map[string]*pflag.Flag
+	envVars       map[string]string
+	defaults      map[string]string
+
+	// configValues holds lists and nested tables from config files
+	// keyed by their key path, scalar values are held in config.
+	configValues map[string]any
+	contexts     map[string]EngineContext
+	// currentContextFile is the file that holds the context
+	// selected with the "context use" command.
+	currentContextFile string
+
+	// configFileDiscovery provides the options used to discover
+	// the config files loaded by LoadDiscoveredConfigFiles.
+	configFileDiscovery         func() DiscoveryOptions
+	discoveredConfigFilesLoaded bool
+	discoveredConfigFilesErr    error
 }
 
 // NewProvider creates a new Provider of configuration
 // values for the CLI.
 func NewProvider() *Provider {
 	return &Provider{
-		config:   map[string]string{},
-		pFlags:   map[string]*pflag.Flag{},
-		envVars:  map[string]string{},
-		defaults: map[string]string{},
+		config:        map[string]string{},
+		configValues:  map[string]any{},
+		configSources: map[string]string{},
+		pFlags:        map[string]*pflag.Flag{},
+		envVars:       map[string]string{},
+		defaults:      map[string]string{},
+		contexts:      map[string]EngineContext{},
 	}
 }
 
+// LoadConfigFile loads configuration values and engine contexts
+// from a YAML, JSON or TOML config file.
+// Values from the config file are merged with values from config files
+// that were previously loaded, where values in this file take precedence.
 func (p *Provider) LoadConfigFile(configFilePath string) error {
 	configFile, err := os.Open(configFilePath)
 	if err != nil {
 		return err
 	}
+	defer configFile.Close()
 
+	var values map[string]any
 	if strings.HasSuffix(configFilePath, ".yaml") || strings.HasSuffix(configFilePath, ".yml") {
-		return yaml.NewDecoder(configFile).Decode(&p.config)
+		values, err = decodeYAMLConfig(configFile)
+	} else if strings.HasSuffix(configFilePath, ".json") {
+		values, err = decodeJSONConfig(configFile)
+	} else if strings.HasSuffix(configFilePath, ".toml") {
+		values, err = decodeTOMLConfig(configFile)
+	} else {
+		return ErrUnsupportedConfigFileFormat
 	}
-
-	if strings.HasSuffix(configFilePath, ".json") {
-		return json.NewDecoder(configFile).Decode(&p.config)
-	}
-
-	if strings.HasSuffix(configFilePath, ".toml") {
-		_, err := toml.NewDecoder(configFile).Decode(&p.config)
+	if err != nil {
 		return err
 	}
 
-	return ErrUnsupportedConfigFileFormat
+	// Recorded so the source of config file values can be reported,
+	// see Resolve.
+	p.configFile = configFilePath
+	p.configFiles = append(p.configFiles, configFilePath)
+	return p.loadConfigValues(values)
 }
 
 func (p *Provider) BindEnvVar(configName, envVarName string) {
@@ -86,137 +121,176 @@ func (p *Provider) SetDefault(configName, value string) {
 // It also returns a boolean indicating whether the value was set by the user
 // or if it's a default value. `true` means the value is a default value.
 func (p *Provider) GetString(configName string) (string, bool) {
-	flag, hasFlag := p.pFlags[configName]
-	defaultFlagValue := ""
-	if hasFlag && flag != nil {
-		value := flag.Value.String()
-		if strings.TrimSpace(value) != "" {
-			if flag.Changed {
-				// Flag set by user.
-				return value, false
-			} else {
-				// Flag not set by user, fallback to default value.
-				defaultFlagValue = value
-			}
-		}
-	}
-
-	envVarName, hasEnvVarName := p.envVars[configName]
-	if hasEnvVarName {
-		envVar, envVarExists := os.LookupEnv(envVarName)
-		if envVarExists && strings.TrimSpace(envVar) != "" {
-			return envVar, false
-		}
-	}
-
-	configValue, hasConfigValue := p.config[configName]
-	if hasConfigValue {
-		return configValue, false
-	}
-
-	if defaultFlagValue != "" {
-		return defaultFlagValue, true
-	}
-
-	return p.defaults[configName], true
+	resolved := p.Resolve(configName)
+	return resolved.Value, resolved.IsDefault()
 }
 
+// GetInt32 returns the value of a configuration value as an int32,
+// zero is returned when the value is not a valid int32,
+// use GetInt32E to report invalid values.
 func (p *Provider) GetInt32(configName string) (int32, bool) {
-	strVal, isDefault := p.GetString(configName)
-	if strVal == "" {
-		return 0, isDefault
+	intVal, isDefault, _ := p.GetInt32E(configName)
+	return intVal, isDefault
+}
+
+// GetInt32E returns the value of a configuration value as an int32
+// along with a *TypeError when the value is not a valid int32.
+func (p *Provider) GetInt32E(configName string) (int32, bool, error) {
+	resolved := p.Resolve(configName)
+	if resolved.Value == "" {
+		return 0, resolved.IsDefault(), nil
 	}
 
-	intVal, err := strconv.ParseInt(strVal, 10, 32)
+	intVal, err := strconv.ParseInt(resolved.Value, 10, 32)
 	if err != nil {
-		return 0, isDefault
+		return 0, resolved.IsDefault(), newTypeError(resolved, "int32", err)
 	}
 
-	return int32(intVal), isDefault
+	return int32(intVal), resolved.IsDefault(), nil
 }
 
+// GetInt64 returns the value of a configuration value as an int64,
+// zero is returned when the value is not a valid int64,
+// use GetInt64E to report invalid values.
 func (p *Provider) GetInt64(configName string) (int64, bool) {
-	strVal, isDefault := p.GetString(configName)
-	if strVal == "" {
-		return 0, isDefault
+	intVal, isDefault, _ := p.GetInt64E(configName)
+	return intVal, isDefault
+}
+
+// GetInt64E returns the value of a configuration value as an int64
+// along with a *TypeError when the value is not a valid int64.
+func (p *Provider) GetInt64E(configName string) (int64, bool, error) {
+	resolved := p.Resolve(configName)
+	if resolved.Value == "" {
+		return 0, resolved.IsDefault(), nil
 	}
 
-	intVal, err := strconv.ParseInt(strVal, 10, 64)
+	intVal, err := strconv.ParseInt(resolved.Value, 10, 64)
 	if err != nil {
-		return 0, isDefault
+		return 0, resolved.IsDefault(), newTypeError(resolved, "int64", err)
 	}
 
-	return intVal, isDefault
+	return intVal, resolved.IsDefault(), nil
 }
 
+// GetUint32 returns the value of a configuration value as a uint32,
+// zero is returned when the value is not a valid uint32,
+// use GetUint32E to report invalid values.
 func (p *Provider) GetUint32(configName string) (uint32, bool) {
-	strVal, isDefault := p.GetString(configName)
-	if strVal == "" {
-		return 0, isDefault
+	intVal, isDefault, _ := p.GetUint32E(configName)
+	return intVal, isDefault
+}
+
+// GetUint32E returns the value of a configuration value as a uint32
+// along with a *TypeError when the value is not a valid uint32.
+func (p *Provider) GetUint32E(configName string) (uint32, bool, error) {
+	resolved := p.Resolve(configName)
+	if resolved.Value == "" {
+		return 0, resolved.IsDefault(), nil
 	}
 
-	intVal, err := strconv.ParseUint(strVal, 10, 32)
+	intVal, err := strconv.ParseUint(resolved.Value, 10, 32)
 	if err != nil {
-		return 0, isDefault
+		return 0, resolved.IsDefault(), newTypeError(resolved, "uint32", err)
 	}
 
-	return uint32(intVal), isDefault
+	return uint32(intVal), resolved.IsDefault(), nil
 }
 
+// GetUint64 returns the value of a configuration value as a uint64,
+// zero is returned when the value is not a valid uint64,
+// use GetUint64E to report invalid values.
 func (p *Provider) GetUint64(configName string) (uint64, bool) {
-	strVal, isDefault := p.GetString(configName)
-	if strVal == "" {
-		return 0, isDefault
+	intVal, isDefault, _ := p.GetUint64E(configName)
+	return intVal, isDefault
+}
+
+// GetUint64E returns the value of a configuration value as a uint64
+// along with a *TypeError when the value is not a valid uint64.
+func (p *Provider) GetUint64E(configName string) (uint64, bool, error) {
+	resolved := p.Resolve(configName)
+	if resolved.Value == "" {
+		return 0, resolved.IsDefault(), nil
 	}
 
-	intVal, err := strconv.ParseUint(strVal, 10, 64)
+	intVal, err := strconv.ParseUint(resolved.Value, 10, 64)
 	if err != nil {
-		return 0, isDefault
+		return 0, resolved.IsDefault(), newTypeError(resolved, "uint64", err)
 	}
 
-	return intVal, isDefault
+	return intVal, resolved.IsDefault(), nil
 }
 
+// GetFloat32 returns the value of a configuration value as a float32,
+// zero is returned when the value is not a valid float32,
+// use GetFloat32E to report invalid values.
 func (p *Provider) GetFloat32(configName string) (float32, bool) {
-	strVal, isDefault := p.GetString(configName)
-	if strVal == "" {
-		return 0.0, isDefault
+	floatVal, isDefault, _ := p.GetFloat32E(configName)
+	return floatVal, isDefault
+}
+
+// GetFloat32E returns the value of a configuration value as a float32
+// along with a *TypeError when the value is not a valid float32.
+func (p *Provider) GetFloat32E(configName string) (float32, bool, error) {
+	resolved := p.Resolve(configName)
+	if resolved.Value == "" {
+		return 0.0, resolved.IsDefault(), nil
 	}
 
-	floatVal, err := strconv.ParseFloat(strVal, 32)
+	floatVal, err := strconv.ParseFloat(resolved.Value, 32)
 	if err != nil {
-		return 0.0, isDefault
+		return 0.0, resolved.IsDefault(), newTypeError(resolved, "float32", err)
 	}
 
-	return float32(floatVal), isDefault
+	return float32(floatVal), resolved.IsDefault(), nil
 }
 
+// GetFloat64 returns the value of a configuration value as a float64,
+// zero is returned when the value is not a valid float64,
+// use GetFloat64E to report invalid values.
 func (p *Provider) GetFloat64(configName string) (float64, bool) {
-	strVal, isDefault := p.GetString(configName)
-	if strVal == "" {
-		return 0.0, isDefault
+	floatVal, isDefault, _ := p.GetFloat64E(configName)
+	return floatVal, isDefault
+}
+
+// GetFloat64E returns the value of a configuration value as a float64
+// along with a *TypeError when the value is not a valid float64.
+func (p *Provider) GetFloat64E(configName string) (float64, bool, error) {
+	resolved := p.Resolve(configName)
+	if resolved.Value == "" {
+		return 0.0, resolved.IsDefault(), nil
 	}
 
-	floatVal, err := strconv.ParseFloat(strVal, 64)
+	floatVal, err := strconv.ParseFloat(resolved.Value, 64)
 	if err != nil {
-		return 0.0, isDefault
+		return 0.0, resolved.IsDefault(), newTypeError(resolved, "float64", err)
 	}
 
-	return floatVal, isDefault
+	return floatVal, resolved.IsDefault(), nil
 }
 
+// GetBool returns the value of a configuration value as a bool,
+// false is returned when the value is not a valid bool,
+// use GetBoolE to report invalid values.
 func (p *Provider) GetBool(configName string) (bool, bool) {
-	strVal, isDefault := p.GetString(configName)
-	if strVal == "" {
-		return false, isDefault
+	boolVal, isDefault, _ := p.GetBoolE(configName)
+	return boolVal, isDefault
+}
+
+// GetBoolE returns the value of a configuration value as a bool
+// along with a *TypeError when the value is not a valid bool.
+func (p *Provider) GetBoolE(configName string) (bool, bool, error) {
+	resolved := p.Resolve(configName)
+	if resolved.Value == "" {
+		return false, resolved.IsDefault(), nil
 	}
 
-	boolVal, err := strconv.ParseBool(strVal)
+	boolVal, err := strconv.ParseBool(resolved.Value)
 	if err != nil {
-		return false, isDefault
+		return false, resolved.IsDefault(), newTypeError(resolved, "bool", err)
 	}
 
-	return boolVal, isDefault
+	return boolVal, resolved.IsDefault(), nil
 }
 
 var (
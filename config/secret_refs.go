@@ -0,0 +1,154 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Prefixes of secret references that can be used in place of plaintext
+// secrets in the engine auth config file.
+const (
+	// SecretRefEnvPrefix references an environment variable,
+	// e.g. "env:BLUELINK_API_KEY".
+	SecretRefEnvPrefix = "env:"
+	// SecretRefFilePrefix references a file that holds the secret,
+	// e.g. "file:/run/secrets/bluelink-api-key".
+	// Trailing new lines are removed from the contents of the file.
+	SecretRefFilePrefix = "file:"
+	// SecretRefExecPrefix references a credential helper command that writes
+	// the secret to stdout as a JSON object with a "secret" field,
+	// e.g. "exec:bluelink-credential-helper get --profile ci".
+	SecretRefExecPrefix = "exec:"
+)
+
+// secretHelperTimeout is the maximum amount of time a credential helper
+// command can take to return a secret.
+const secretHelperTimeout = 30 * time.Second
+
+// ErrInvalidSecretRef is returned when a secret reference is malformed
+// or the secret it references could not be resolved.
+var ErrInvalidSecretRef = errors.New("invalid secret reference")
+
+// IsSecretRef determines whether the provided value is a reference
+// to a secret rather than a plaintext secret.
+func IsSecretRef(value string) bool {
+	return strings.HasPrefix(value, SecretRefEnvPrefix) ||
+		strings.HasPrefix(value, SecretRefFilePrefix) ||
+		strings.HasPrefix(value, SecretRefExecPrefix)
+}
+
+// ResolveSecretRef resolves the secret for a secret reference
+// in one of the following forms:
+//   - env:NAME reads the secret from the NAME environment variable
+//   - file:/path reads the secret from a file
+//   - exec:command [args...] runs a credential helper command that writes
+//     a JSON object in the form {"secret": "..."} to stdout
+//
+// Values that are not secret references are returned as they are.
+func ResolveSecretRef(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, SecretRefEnvPrefix):
+		name := strings.TrimPrefix(value, SecretRefEnvPrefix)
+		if name == "" {
+			return "", secretRefError(value, "an environment variable name must be provided")
+		}
+		secret, isSet := os.LookupEnv(name)
+		if !isSet || secret == "" {
+			return "", secretRefError(value, fmt.Sprintf("the %s environment variable is not set", name))
+		}
+		return secret, nil
+	case strings.HasPrefix(value, SecretRefFilePrefix):
+		path := strings.TrimPrefix(value, SecretRefFilePrefix)
+		if path == "" {
+			return "", secretRefError(value, "a file path must be provided")
+		}
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return "", secretRefError(value, err.Error())
+		}
+		secret := strings.TrimRight(string(contents), "\r\n")
+		if secret == "" {
+			return "", secretRefError(value, fmt.Sprintf("the file %s is empty", path))
+		}
+		return secret, nil
+	case strings.HasPrefix(value, SecretRefExecPrefix):
+		return runCredentialHelper(value)
+	}
+
+	return value, nil
+}
+
+type credentialHelperOutput struct {
+	Secret string `json:"secret"`
+}
+
+func runCredentialHelper(value string) (string, error) {
+	args := strings.Fields(strings.TrimPrefix(value, SecretRefExecPrefix))
+	if len(args) == 0 {
+		return "", secretRefError(value, "a credential helper command must be provided")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretHelperTimeout)
+	defer cancel()
+
+	stderr := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stderr = stderr
+	stdout, err := cmd.Output()
+	if err != nil {
+		message := fmt.Sprintf("the credential helper %s failed: %s", args[0], err)
+		if helperErr := strings.TrimSpace(stderr.String()); helperErr != "" {
+			message += ": " + helperErr
+		}
+		return "", secretRefError(value, message)
+	}
+
+	output := credentialHelperOutput{}
+	if err := json.Unmarshal(stdout, &output); err != nil {
+		return "", secretRefError(
+			value,
+			fmt.Sprintf("the credential helper %s must write a JSON object to stdout: %s", args[0], err),
+		)
+	}
+	if output.Secret == "" {
+		return "", secretRefError(
+			value,
+			fmt.Sprintf("the credential helper %s did not return a \"secret\" field", args[0]),
+		)
+	}
+	return output.Secret, nil
+}
+
+func secretRefError(value string, reason string) error {
+	return fmt.Errorf("%w %q: %s", ErrInvalidSecretRef, secretRefDisplay(value), reason)
+}
+
+// secretRefDisplay returns the form of a secret reference that is safe to
+// include in error messages, exec references are reduced to the command name
+// as arguments may contain sensitive values.
+func secretRefDisplay(value string) string {
+	if !strings.HasPrefix(value, SecretRefExecPrefix) {
+		return value
+	}
+	args := strings.Fields(strings.TrimPrefix(value, SecretRefExecPrefix))
+	if len(args) == 0 {
+		return SecretRefExecPrefix
+	}
+	return SecretRefExecPrefix + args[0]
+}
+
+// IsAccessibleByOthers determines whether the file at the provided path
+// can be accessed by users other than its owner.
+// This always returns false on Windows where file mode permission bits
+// do not reflect who can access a file.
+func IsAccessibleByOthers(info os.FileInfo) bool {
+	return runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0
+}
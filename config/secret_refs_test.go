@@ -0,0 +1,111 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SecretRefsSuite struct {
+	suite.Suite
+	tempDir string
+}
+
+func (s *SecretRefsSuite) SetupTest() {
+	s.tempDir = s.T().TempDir()
+}
+
+// writeHelper writes a credential helper script that prints the provided output.
+func (s *SecretRefsSuite) writeHelper(output string, exitCode int) string {
+	if runtime.GOOS == "windows" {
+		s.T().Skip("credential helper scripts require a POSIX shell")
+	}
+	path := filepath.Join(s.tempDir, "credential-helper")
+	script := "#!/bin/sh\necho '" + output + "'\nexit " + strconv.Itoa(exitCode) + "\n"
+	s.Require().NoError(os.WriteFile(path, []byte(script), 0o700))
+	return path
+}
+
+func (s *SecretRefsSuite) Test_plaintext_values_are_returned_as_they_are() {
+	secret, err := ResolveSecretRef("test-key")
+	s.Require().NoError(err)
+	s.Equal("test-key", secret)
+	s.False(IsSecretRef("test-key"))
+}
+
+func (s *SecretRefsSuite) Test_resolves_env_reference() {
+	s.T().Setenv("TEST_SECRET_REFS_API_KEY", "from-env")
+
+	secret, err := ResolveSecretRef("env:TEST_SECRET_REFS_API_KEY")
+	s.Require().NoError(err)
+	s.Equal("from-env", secret)
+}
+
+func (s *SecretRefsSuite) Test_fails_for_unset_env_reference() {
+	_, err := ResolveSecretRef("env:TEST_SECRET_REFS_UNSET")
+	s.Require().ErrorIs(err, ErrInvalidSecretRef)
+	s.Contains(err.Error(), "the TEST_SECRET_REFS_UNSET environment variable is not set")
+}
+
+func (s *SecretRefsSuite) Test_resolves_file_reference_without_trailing_new_line() {
+	path := filepath.Join(s.tempDir, "api-key")
+	s.Require().NoError(os.WriteFile(path, []byte("from-file\n"), 0o600))
+
+	secret, err := ResolveSecretRef("file:" + path)
+	s.Require().NoError(err)
+	s.Equal("from-file", secret)
+}
+
+func (s *SecretRefsSuite) Test_fails_for_missing_file_reference() {
+	_, err := ResolveSecretRef("file:" + filepath.Join(s.tempDir, "missing"))
+	s.Require().ErrorIs(err, ErrInvalidSecretRef)
+}
+
+func (s *SecretRefsSuite) Test_resolves_exec_reference() {
+	helper := s.writeHelper(`{"secret": "from-helper"}`, 0)
+
+	secret, err := ResolveSecretRef("exec:" + helper + " get")
+	s.Require().NoError(err)
+	s.Equal("from-helper", secret)
+}
+
+func (s *SecretRefsSuite) Test_fails_for_exec_reference_without_secret_field() {
+	helper := s.writeHelper(`{"username": "ci"}`, 0)
+
+	_, err := ResolveSecretRef("exec:" + helper)
+	s.Require().ErrorIs(err, ErrInvalidSecretRef)
+	s.Contains(err.Error(), `did not return a "secret" field`)
+}
+
+func (s *SecretRefsSuite) Test_fails_for_failing_exec_reference_without_leaking_arguments() {
+	helper := s.writeHelper("not authorised", 1)
+
+	_, err := ResolveSecretRef("exec:" + helper + " --token sensitive")
+	s.Require().ErrorIs(err, ErrInvalidSecretRef)
+	s.Contains(err.Error(), "failed")
+	s.NotContains(err.Error(), "sensitive")
+}
+
+func (s *SecretRefsSuite) Test_is_accessible_by_others() {
+	if runtime.GOOS == "windows" {
+		s.T().Skip("file mode permission bits are not used on windows")
+	}
+	path := filepath.Join(s.tempDir, "auth.json")
+	s.Require().NoError(os.WriteFile(path, []byte("{}"), 0o600))
+	info, err := os.Stat(path)
+	s.Require().NoError(err)
+	s.False(IsAccessibleByOthers(info))
+
+	s.Require().NoError(os.Chmod(path, 0o640))
+	info, err = os.Stat(path)
+	s.Require().NoError(err)
+	s.True(IsAccessibleByOthers(info))
+}
+
+func TestSecretRefsSuite(t *testing.T) {
+	suite.Run(t, new(SecretRefsSuite))
+}
@@ -0,0 +1,250 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// keyPathSeparator separates the keys of nested tables in key paths,
+// e.g. "deploy.instanceName".
+const keyPathSeparator = "."
+
+// Config files are decoded into a tree where scalars are strings,
+// lists are []any and tables are map[string]any so that values are
+// handled the same way regardless of the config file format.
+// Scalars are kept as the text used in the config file where possible
+// so that, for example, a version of 1.10 is not read back as 1.1.
+
+func decodeYAMLConfig(configFile io.Reader) (map[string]any, error) {
+	document := yaml.Node{}
+	if err := yaml.NewDecoder(configFile).Decode(&document); err != nil {
+		if errors.Is(err, io.EOF) {
+			return map[string]any{}, nil
+		}
+		return nil, err
+	}
+
+	value, err := yamlNodeValue(&document)
+	if err != nil {
+		return nil, err
+	}
+
+	table, isTable := value.(map[string]any)
+	if !isTable {
+		return nil, errors.New("the config file must contain a mapping of config keys to values")
+	}
+	return table, nil
+}
+
+func yamlNodeValue(node *yaml.Node) (any, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return map[string]any{}, nil
+		}
+		return yamlNodeValue(node.Content[0])
+	case yaml.AliasNode:
+		return yamlNodeValue(node.Alias)
+	case yaml.SequenceNode:
+		list := make([]any, 0, len(node.Content))
+		for _, item := range node.Content {
+			value, err := yamlNodeValue(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		return list, nil
+	case yaml.MappingNode:
+		table := make(map[string]any, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value, err := yamlNodeValue(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			table[node.Content[i].Value] = value
+		}
+		return table, nil
+	}
+
+	if node.Tag == "!!null" {
+		return "", nil
+	}
+	return node.Value, nil
+}
+
+func decodeJSONConfig(configFile io.Reader) (map[string]any, error) {
+	decoder := json.NewDecoder(configFile)
+	// Numbers are decoded as json.Number to keep the text used in the file
+	// instead of reformatting them (e.g. 1e+06 for 1000000).
+	decoder.UseNumber()
+
+	rawConfig := map[string]any{}
+	if err := decoder.Decode(&rawConfig); err != nil {
+		return nil, err
+	}
+
+	return normaliseConfigTable(rawConfig), nil
+}
+
+func decodeTOMLConfig(configFile io.Reader) (map[string]any, error) {
+	rawConfig := map[string]any{}
+	if _, err := toml.NewDecoder(configFile).Decode(&rawConfig); err != nil {
+		return nil, err
+	}
+
+	return normaliseConfigTable(rawConfig), nil
+}
+
+func normaliseConfigTable(rawTable map[string]any) map[string]any {
+	table := make(map[string]any, len(rawTable))
+	for key, rawValue := range rawTable {
+		table[key] = normaliseConfigValue(rawValue)
+	}
+	return table
+}
+
+func normaliseConfigValue(rawValue any) any {
+	switch value := rawValue.(type) {
+	case map[string]any:
+		return normaliseConfigTable(value)
+	case []map[string]any:
+		// TOML arrays of tables.
+		list := make([]any, 0, len(value))
+		for _, item := range value {
+			list = append(list, normaliseConfigTable(item))
+		}
+		return list
+	case []any:
+		list := make([]any, 0, len(value))
+		for _, item := range value {
+			list = append(list, normaliseConfigValue(item))
+		}
+		return list
+	case string:
+		return value
+	case nil:
+		return ""
+	}
+
+	return fmt.Sprint(rawValue)
+}
+
+// loadConfigValues merges the values of a decoded config file
+// into the provider, flattening nested tables into key paths.
+func (p *Provider) loadConfigValues(values map[string]any) error {
+	for key, value := range values {
+		if key == contextsConfigKey {
+			contexts, err := engineContextsFromConfigValue(value)
+			if err != nil {
+				return err
+			}
+			p.addContexts(contexts)
+			continue
+		}
+
+		p.setConfigValue(key, value)
+	}
+
+	return nil
+}
+
+func (p *Provider) setConfigValue(keyPath string, value any) {
+	p.configSources[keyPath] = p.configFile
+
+	switch typedValue := value.(type) {
+	case string:
+		p.config[keyPath] = typedValue
+		delete(p.configValues, keyPath)
+	case map[string]any:
+		delete(p.config, keyPath)
+		// Tables are merged with tables of the same key path from
+		// previously loaded config files so that later files only
+		// override the keys they set.
+		merged := map[string]any{}
+		if existing, isTable := p.configValues[keyPath].(map[string]any); isTable {
+			for key, existingValue := range existing {
+				merged[key] = existingValue
+			}
+		}
+		for key, nestedValue := range typedValue {
+			merged[key] = nestedValue
+		}
+		p.configValues[keyPath] = merged
+		for key, nestedValue := range typedValue {
+			p.setConfigValue(keyPath+keyPathSeparator+key, nestedValue)
+		}
+	default:
+		delete(p.config, keyPath)
+		p.configValues[keyPath] = typedValue
+	}
+}
+
+func engineContextsFromConfigValue(value any) (map[string]EngineContext, error) {
+	table, isTable := value.(map[string]any)
+	if !isTable {
+		return nil, fmt.Errorf(
+			"%q must be a table of context names to engine settings",
+			contextsConfigKey,
+		)
+	}
+
+	contexts := make(map[string]EngineContext, len(table))
+	for name, contextValue := range table {
+		settings, isTable := contextValue.(map[string]any)
+		if !isTable {
+			return nil, fmt.Errorf(
+				"context %q must be a table of engine settings",
+				name,
+			)
+		}
+
+		contexts[name] = EngineContext{
+			EngineEndpoint:       stringOrEmpty(settings["engineEndpoint"]),
+			ConnectProtocol:      stringOrEmpty(settings["connectProtocol"]),
+			EngineAuthConfigFile: stringOrEmpty(settings["engineAuthConfigFile"]),
+		}
+	}
+	return contexts, nil
+}
+
+func stringOrEmpty(value any) string {
+	strValue, isString := value.(string)
+	if !isString {
+		return ""
+	}
+	return strValue
+}
+
+// formatConfigValue formats a list or table from a config file
+// in the same form accepted for environment variables,
+// e.g. "a,b" for lists and "a=1,b=2" for tables.
+func formatConfigValue(value any) string {
+	switch typedValue := value.(type) {
+	case []any:
+		items := make([]string, 0, len(typedValue))
+		for _, item := range typedValue {
+			items = append(items, formatConfigValue(item))
+		}
+		return strings.Join(items, ",")
+	case map[string]any:
+		keys := make([]string, 0, len(typedValue))
+		for key := range typedValue {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, 0, len(keys))
+		for _, key := range keys {
+			pairs = append(pairs, key+"="+formatConfigValue(typedValue[key]))
+		}
+		return strings.Join(pairs, ",")
+	}
+	return fmt.Sprint(value)
+}
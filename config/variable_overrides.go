@@ -0,0 +1,221 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"gopkg.in/yaml.v3"
+)
+
+// VariableKind distinguishes blueprint variables from context variables.
+type VariableKind string
+
+const (
+	// VariableKindBlueprint is a variable declared in the blueprint
+	// that is set with --var or --var-file.
+	VariableKindBlueprint VariableKind = "blueprint"
+	// VariableKindContext is a context variable that is set
+	// with --context-var.
+	VariableKindContext VariableKind = "context"
+)
+
+// VariableOverride is a blueprint or context variable value provided on the
+// command line that overrides the value from the deploy config file.
+type VariableOverride struct {
+	Kind  VariableKind
+	Name  string
+	Value *core.ScalarValue
+	// Source is either SourceFlag or SourceVarFile.
+	Source ValueSource
+	// SourceName is the path of the var file for values from var files.
+	SourceName string
+	// Secret is set for blueprint variables that are declared
+	// as secret in the blueprint.
+	Secret bool
+}
+
+// IsSecret determines whether the value of the override must be redacted,
+// either because the variable is declared as secret in the blueprint
+// or because its name indicates it holds a secret, see IsSecretKey.
+func (o VariableOverride) IsSecret() bool {
+	return o.Secret || IsSecretKey(o.Name)
+}
+
+// DisplayValue returns the value of the override as a string with the value
+// replaced by RedactedValue when the variable holds a secret, see IsSecret.
+func (o VariableOverride) DisplayValue() string {
+	if o.IsSecret() {
+		return RedactedValue
+	}
+	return o.Value.ToString()
+}
+
+// VariableOverrideOptions holds the raw variable values provided
+// on the command line.
+type VariableOverrideOptions struct {
+	// VarFiles are paths to JSON or YAML files that map blueprint variable
+	// names to values, later files take precedence over earlier files.
+	VarFiles []string
+	// Vars are blueprint variable assignments in the form name=value,
+	// these take precedence over values from var files.
+	Vars []string
+	// ContextVars are context variable assignments in the form name=value.
+	ContextVars []string
+}
+
+// LoadVariableOverrides parses the variable values provided on the command line
+// into overrides ordered from lowest to highest precedence:
+//  1. Values from var files, in the order the files were provided
+//  2. Blueprint variables set with --var
+//  3. Context variables set with --context-var
+//
+// Values set with --var and --context-var are coerced to the type of the
+// literal, "true" and "false" are booleans, numbers are integers or floats
+// and anything else is a string.
+// A value can be forced to be a string by wrapping it in double quotes,
+// e.g. --var 'version="1.0"'.
+// The literal null is treated as a string as variables can not be unset.
+func LoadVariableOverrides(opts VariableOverrideOptions) ([]VariableOverride, error) {
+	overrides := []VariableOverride{}
+
+	for _, varFile := range opts.VarFiles {
+		fileOverrides, err := loadVarFile(varFile)
+		if err != nil {
+			return nil, err
+		}
+		overrides = append(overrides, fileOverrides...)
+	}
+
+	for _, assignment := range opts.Vars {
+		override, err := parseVariableAssignment(VariableKindBlueprint, assignment)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --var value: %w", err)
+		}
+		overrides = append(overrides, override)
+	}
+
+	for _, assignment := range opts.ContextVars {
+		override, err := parseVariableAssignment(VariableKindContext, assignment)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --context-var value: %w", err)
+		}
+		overrides = append(overrides, override)
+	}
+
+	return overrides, nil
+}
+
+// ApplyVariableOverrides merges variable overrides on top of the blueprint
+// operation config loaded from the deploy config file, later overrides take
+// precedence over earlier overrides for the same variable.
+// A new operation config is created when opConfig is nil and there are
+// overrides to apply.
+func ApplyVariableOverrides(
+	opConfig *types.BlueprintOperationConfig,
+	overrides []VariableOverride,
+) *types.BlueprintOperationConfig {
+	if len(overrides) == 0 {
+		return opConfig
+	}
+
+	if opConfig == nil {
+		opConfig = &types.BlueprintOperationConfig{}
+	}
+	if opConfig.BlueprintVariables == nil {
+		opConfig.BlueprintVariables = map[string]*core.ScalarValue{}
+	}
+	if opConfig.ContextVariables == nil {
+		opConfig.ContextVariables = map[string]*core.ScalarValue{}
+	}
+
+	for _, override := range overrides {
+		switch override.Kind {
+		case VariableKindBlueprint:
+			opConfig.BlueprintVariables[override.Name] = override.Value
+		case VariableKindContext:
+			opConfig.ContextVariables[override.Name] = override.Value
+		}
+	}
+	return opConfig
+}
+
+func parseVariableAssignment(kind VariableKind, assignment string) (VariableOverride, error) {
+	name, rawValue, hasSeparator := strings.Cut(assignment, "=")
+	name = strings.TrimSpace(name)
+	if !hasSeparator || name == "" {
+		return VariableOverride{}, fmt.Errorf("%q is not in the form name=value", assignment)
+	}
+
+	return VariableOverride{
+		Kind:   kind,
+		Name:   name,
+		Value:  parseScalarLiteral(rawValue),
+		Source: SourceFlag,
+	}, nil
+}
+
+func parseScalarLiteral(rawValue string) *core.ScalarValue {
+	// A JSON null decodes into an empty scalar that is read as 0.
+	if strings.TrimSpace(rawValue) == "null" {
+		return core.ScalarFromString(rawValue)
+	}
+
+	value := &core.ScalarValue{}
+	if err := json.Unmarshal([]byte(rawValue), value); err == nil {
+		return value
+	}
+	return core.ScalarFromString(rawValue)
+}
+
+func loadVarFile(path string) ([]VariableOverride, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading var file %s: %w", path, err)
+	}
+
+	values := map[string]*core.ScalarValue{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &values)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	default:
+		return nil, fmt.Errorf(
+			"var file %s must have a .json, .yaml or .yml extension",
+			path,
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf(
+			"parsing var file %s, var files must map variable names to strings, numbers or booleans: %w",
+			path,
+			err,
+		)
+	}
+
+	overrides := make([]VariableOverride, 0, len(values))
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		value := values[name]
+		if value == nil {
+			return nil, fmt.Errorf("var file %s does not set a value for %q", path, name)
+		}
+		// Source positions in the var file are not relevant
+		// to the engine.
+		value.SourceMeta = nil
+		overrides = append(overrides, VariableOverride{
+			Kind:       VariableKindBlueprint,
+			Name:       name,
+			Value:      value,
+			Source:     SourceVarFile,
+			SourceName: path,
+		})
+	}
+	return overrides, nil
+}
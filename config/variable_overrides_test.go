@@ -0,0 +1,169 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type VariableOverridesSuite struct {
+	suite.Suite
+	tempDir string
+}
+
+func (s *VariableOverridesSuite) SetupTest() {
+	s.tempDir = s.T().TempDir()
+}
+
+func (s *VariableOverridesSuite) writeVarFile(name, content string) string {
+	path := filepath.Join(s.tempDir, name)
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func (s *VariableOverridesSuite) Test_coerces_var_values_to_the_type_of_the_literal() {
+	overrides, err := LoadVariableOverrides(VariableOverrideOptions{
+		Vars: []string{
+			"replicas=3",
+			"ratio=0.5",
+			"enabled=true",
+			"region=eu-west-2",
+			`version="1"`,
+			"query=a=b",
+			"empty=",
+		},
+	})
+	s.Require().NoError(err)
+	s.Require().Len(overrides, 7)
+
+	s.Equal(core.ScalarFromInt(3), overrides[0].Value)
+	s.Equal(core.ScalarFromFloat(0.5), overrides[1].Value)
+	s.Equal(core.ScalarFromBool(true), overrides[2].Value)
+	s.Equal(core.ScalarFromString("eu-west-2"), overrides[3].Value)
+	s.Equal(core.ScalarFromString("1"), overrides[4].Value)
+	s.Equal("query", overrides[5].Name)
+	s.Equal(core.ScalarFromString("a=b"), overrides[5].Value)
+	s.Equal(core.ScalarFromString(""), overrides[6].Value)
+	for _, override := range overrides {
+		s.Equal(VariableKindBlueprint, override.Kind)
+		s.Equal(SourceFlag, override.Source)
+	}
+}
+
+func (s *VariableOverridesSuite) Test_treats_null_var_values_as_strings() {
+	overrides, err := LoadVariableOverrides(VariableOverrideOptions{
+		Vars:        []string{"x=null"},
+		ContextVars: []string{"y= null"},
+	})
+	s.Require().NoError(err)
+
+	s.Equal(core.ScalarFromString("null"), overrides[0].Value)
+	s.Equal(core.ScalarFromString(" null"), overrides[1].Value)
+}
+
+func (s *VariableOverridesSuite) Test_rejects_vars_not_in_name_value_form() {
+	_, err := LoadVariableOverrides(VariableOverrideOptions{Vars: []string{"region"}})
+	s.Require().Error(err)
+	s.Contains(err.Error(), `invalid --var value: "region" is not in the form name=value`)
+
+	_, err = LoadVariableOverrides(VariableOverrideOptions{ContextVars: []string{"=staging"}})
+	s.Require().Error(err)
+	s.Contains(err.Error(), "invalid --context-var value")
+}
+
+func (s *VariableOverridesSuite) Test_loads_json_and_yaml_var_files() {
+	jsonFile := s.writeVarFile("vars.json", `{"replicas": 2, "region": "eu-west-1"}`)
+	yamlFile := s.writeVarFile("vars.yaml", "enabled: true\nratio: 0.25\n")
+
+	overrides, err := LoadVariableOverrides(VariableOverrideOptions{
+		VarFiles: []string{jsonFile, yamlFile},
+	})
+	s.Require().NoError(err)
+	s.Equal([]VariableOverride{
+		{Kind: VariableKindBlueprint, Name: "region", Value: core.ScalarFromString("eu-west-1"), Source: SourceVarFile, SourceName: jsonFile},
+		{Kind: VariableKindBlueprint, Name: "replicas", Value: core.ScalarFromInt(2), Source: SourceVarFile, SourceName: jsonFile},
+		{Kind: VariableKindBlueprint, Name: "enabled", Value: core.ScalarFromBool(true), Source: SourceVarFile, SourceName: yamlFile},
+		{Kind: VariableKindBlueprint, Name: "ratio", Value: core.ScalarFromFloat(0.25), Source: SourceVarFile, SourceName: yamlFile},
+	}, overrides)
+}
+
+func (s *VariableOverridesSuite) Test_rejects_var_files_with_non_scalar_values() {
+	varFile := s.writeVarFile("vars.yaml", "regions:\n  - eu-west-1\n")
+
+	_, err := LoadVariableOverrides(VariableOverrideOptions{VarFiles: []string{varFile}})
+	s.Require().Error(err)
+	s.Contains(err.Error(), "parsing var file "+varFile)
+}
+
+func (s *VariableOverridesSuite) Test_rejects_var_files_with_unsupported_extension() {
+	varFile := s.writeVarFile("vars.toml", `region = "eu-west-1"`)
+
+	_, err := LoadVariableOverrides(VariableOverrideOptions{VarFiles: []string{varFile}})
+	s.Require().Error(err)
+	s.Contains(err.Error(), "must have a .json, .yaml or .yml extension")
+}
+
+func (s *VariableOverridesSuite) Test_applies_overrides_with_precedence() {
+	firstFile := s.writeVarFile("first.json", `{"region": "us-east-1", "replicas": 1}`)
+	secondFile := s.writeVarFile("second.yaml", "region: eu-west-1\n")
+	overrides, err := LoadVariableOverrides(VariableOverrideOptions{
+		VarFiles:    []string{firstFile, secondFile},
+		Vars:        []string{"replicas=5"},
+		ContextVars: []string{"environment=staging"},
+	})
+	s.Require().NoError(err)
+
+	opConfig := &types.BlueprintOperationConfig{
+		BlueprintVariables: map[string]*core.ScalarValue{
+			"region":  core.ScalarFromString("ap-southeast-2"),
+			"logging": core.ScalarFromBool(true),
+		},
+		ContextVariables: map[string]*core.ScalarValue{
+			"environment": core.ScalarFromString("production"),
+		},
+	}
+
+	merged := ApplyVariableOverrides(opConfig, overrides)
+	s.Equal(map[string]*core.ScalarValue{
+		"region":   core.ScalarFromString("eu-west-1"),
+		"replicas": core.ScalarFromInt(5),
+		"logging":  core.ScalarFromBool(true),
+	}, merged.BlueprintVariables)
+	s.Equal(map[string]*core.ScalarValue{
+		"environment": core.ScalarFromString("staging"),
+	}, merged.ContextVariables)
+}
+
+func (s *VariableOverridesSuite) Test_creates_operation_config_when_none_loaded() {
+	s.Nil(ApplyVariableOverrides(nil, nil))
+
+	overrides, err := LoadVariableOverrides(VariableOverrideOptions{Vars: []string{"region=eu-west-1"}})
+	s.Require().NoError(err)
+
+	merged := ApplyVariableOverrides(nil, overrides)
+	s.Require().NotNil(merged)
+	s.Equal(core.ScalarFromString("eu-west-1"), merged.BlueprintVariables["region"])
+}
+
+func (s *VariableOverridesSuite) Test_display_value_masks_secret_variables() {
+	overrides, err := LoadVariableOverrides(VariableOverrideOptions{
+		Vars: []string{"databasePassword=hunter2", "region=eu-west-1"},
+	})
+	s.Require().NoError(err)
+
+	s.Equal(RedactedValue, overrides[0].DisplayValue())
+	s.Equal("eu-west-1", overrides[1].DisplayValue())
+
+	// Variables declared as secret in the blueprint can have any name.
+	overrides[1].Secret = true
+	s.True(overrides[1].IsSecret())
+	s.Equal(RedactedValue, overrides[1].DisplayValue())
+}
+
+func TestVariableOverridesSuite(t *testing.T) {
+	suite.Run(t, new(VariableOverridesSuite))
+}
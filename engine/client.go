@@ -2,6 +2,8 @@ package engine
 
 import (
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/newstack-cloud/bluelink/libs/common/sigv1"
 	deployengine "github.com/newstack-cloud/bluelink/libs/deploy-engine-client"
@@ -10,18 +12,42 @@ import (
 )
 
 // Create a new deploy engine client based on how the CLI is configured.
+// When an engine context is selected, the endpoint, connect protocol and
+// auth config of the context are used unless they are overridden
+// with flags or environment variables.
+//
+// Failed calls are retried when the engineMaxRetries config value
+// is greater than zero, see WithRetry for the calls that are retried.
+//
+// When the engineRecordSession config value is set, all calls and stream
+// events are recorded to the session file at the provided path,
+// see Recorder. Any existing file at the path is overwritten.
 func Create(confProvider *config.Provider, logger *zap.Logger) (DeployEngine, error) {
+	// Fail early for an unknown context instead of silently falling back
+	// to the engine settings of the config file.
+	if _, _, err := confProvider.ActiveContext(); err != nil {
+		return nil, err
+	}
+
 	engineEndpoint, _ := confProvider.GetString("engineEndpoint")
 	connectProtocol, err := getConnectProtocol(confProvider)
 	if err != nil {
 		return nil, err
 	}
 
-	engineAuthConfig, err := config.LoadEngineAuthConfig(confProvider)
+	rawEngineAuthConfig, err := config.LoadEngineAuthConfig(confProvider)
 	if err != nil {
 		return nil, err
 	}
-	authMethod, err := toDeployEngineAuthMethod(engineAuthConfig.Method)
+	authMethod, err := toDeployEngineAuthMethod(rawEngineAuthConfig.Method)
+	if err != nil {
+		return nil, err
+	}
+	warnIfAuthConfigFileExposed(confProvider, rawEngineAuthConfig, logger)
+
+	// Secret references are resolved as late as possible so that
+	// credential helpers only run when a client is needed.
+	engineAuthConfig, err := rawEngineAuthConfig.ResolveSecrets()
 	if err != nil {
 		return nil, err
 	}
@@ -53,9 +79,91 @@ func Create(confProvider *config.Provider, logger *zap.Logger) (DeployEngine, er
 		)
 	}
 
-	return deployengine.NewClient(
+	retryPolicy, err := getRetryPolicy(confProvider, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := deployengine.NewClient(
 		options...,
 	)
+	if err != nil {
+		return nil, err
+	}
+	deployEngine := WithRetry(client, retryPolicy)
+
+	sessionFile, _ := confProvider.GetString("engineRecordSession")
+	if sessionFile == "" {
+		return deployEngine, nil
+	}
+	// The session file is left open for the lifetime of the process as each
+	// entry is written in full as soon as it is recorded.
+	// Only the owner can read the session as it records the responses
+	// of the deploy engine.
+	session, err := os.OpenFile(sessionFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session file: %w", err)
+	}
+	// The permissions are only applied to new files by OpenFile.
+	if err := session.Chmod(0o600); err != nil {
+		session.Close()
+		return nil, fmt.Errorf("failed to restrict access to session file: %w", err)
+	}
+	logger.Debug("recording deploy engine session", zap.String("file", sessionFile))
+	return NewRecorder(deployEngine, session), nil
+}
+
+func getRetryPolicy(confProvider *config.Provider, logger *zap.Logger) (RetryPolicy, error) {
+	maxRetries, _, err := confProvider.GetInt32E("engineMaxRetries")
+	if err != nil {
+		return RetryPolicy{}, err
+	}
+	maxWait, _, err := confProvider.GetDuration("engineRetryMaxWait")
+	if err != nil {
+		return RetryPolicy{}, err
+	}
+
+	return RetryPolicy{
+		MaxRetries: int(maxRetries),
+		MaxWait:    maxWait,
+		OnRetry: func(method string, attempt int, wait time.Duration, err error) {
+			logger.Debug(
+				"retrying deploy engine call",
+				zap.String("method", method),
+				zap.Int("attempt", attempt),
+				zap.Duration("wait", wait),
+				zap.Error(err),
+			)
+		},
+	}, nil
+}
+
+// warnIfAuthConfigFileExposed logs a warning when the engine auth config file
+// holds plaintext secrets and can be read by users other than its owner.
+// Auth config files that only hold secret references are safe to share
+// so no warning is logged for them.
+func warnIfAuthConfigFileExposed(
+	confProvider *config.Provider,
+	authConfig *config.EngineAuthConfig,
+	logger *zap.Logger,
+) {
+	if !authConfig.HasPlaintextSecrets() {
+		return
+	}
+
+	authConfigFile := config.EngineAuthConfigFile(confProvider)
+	info, err := os.Stat(authConfigFile)
+	if err != nil || !config.IsAccessibleByOthers(info) {
+		return
+	}
+
+	logger.Warn(
+		"the engine auth config file holds plaintext secrets and can be read by other users, "+
+			"restrict access to the file with chmod 600 or use secret references "+
+			"(env:NAME, file:/path or exec:command) for secret fields",
+		zap.String("file", authConfigFile),
+		zap.String("mode", info.Mode().Perm().String()),
+	)
 }
 
 func getConnectProtocol(confProvider *config.Provider) (deployengine.ConnectProtocol, error) {
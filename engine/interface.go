@@ -2,6 +2,7 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/newstack-cloud/bluelink/libs/blueprint-state/manage"
@@ -235,3 +236,44 @@ type DeployEngine interface {
 		payload *types.ApplyReconciliationPayload,
 	) (*container.ApplyReconciliationResult, error)
 }
+
+// ReconciliationChecker is implemented by deploy engines that can check
+// a blueprint instance for drift and interrupted state.
+// This is kept separate from DeployEngine so implementations of DeployEngine
+// written before reconciliation checks were supported do not need to change,
+// use CheckReconciliation to run a check with a DeployEngine.
+type ReconciliationChecker interface {
+	// CheckReconciliation checks for drift and interrupted state in a blueprint instance.
+	// This is a synchronous operation that returns the reconciliation check result
+	// containing any resources or links that need reconciliation.
+	//
+	// The instanceID parameter can be either the unique instance ID or
+	// the user-defined instance name.
+	CheckReconciliation(
+		ctx context.Context,
+		instanceID string,
+		payload *types.CheckReconciliationPayload,
+	) (*container.ReconciliationCheckResult, error)
+}
+
+// ErrReconciliationCheckUnsupported is returned by CheckReconciliation
+// when the deploy engine does not implement ReconciliationChecker.
+var ErrReconciliationCheckUnsupported = errors.New(
+	"the deploy engine client does not support reconciliation checks",
+)
+
+// CheckReconciliation checks for drift and interrupted state in a blueprint instance
+// with the provided deploy engine, returning ErrReconciliationCheckUnsupported
+// when the deploy engine does not implement ReconciliationChecker.
+func CheckReconciliation(
+	ctx context.Context,
+	deployEngine DeployEngine,
+	instanceID string,
+	payload *types.CheckReconciliationPayload,
+) (*container.ReconciliationCheckResult, error) {
+	checker, ok := deployEngine.(ReconciliationChecker)
+	if !ok {
+		return nil, ErrReconciliationCheckUnsupported
+	}
+	return checker.CheckReconciliation(ctx, instanceID, payload)
+}
@@ -0,0 +1,460 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint-state/manage"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/container"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+)
+
+// Recorder is a deploy engine decorator that records every call made to
+// the wrapped engine with its arguments, response or error along with
+// every event received from streams to a session.
+// Sessions are written as newline-delimited JSON with one SessionEntry per
+// line and can be played back with a Replayer, this is useful for attaching
+// a session to a bug report or as a fixture for tests.
+// Values that can hold secrets, such as blueprint variables, provider
+// config and export values, are redacted before they are written.
+//
+// Recording never causes calls to fail, the first error that occurs
+// when writing the session is available from Err.
+type Recorder struct {
+	engine DeployEngine
+	mu     sync.Mutex
+	w      io.Writer
+	seq    int
+	err    error
+}
+
+// NewRecorder creates a recorder that wraps the provided deploy engine
+// and writes the session to w.
+func NewRecorder(engine DeployEngine, w io.Writer) *Recorder {
+	return &Recorder{
+		engine: engine,
+		w:      w,
+	}
+}
+
+// Err returns the first error that occurred when writing the session.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *Recorder) CreateBlueprintValidation(
+	ctx context.Context,
+	payload *types.CreateBlueprintValidationPayload,
+	query *types.CreateBlueprintValidationQuery,
+) (*types.BlueprintValidationResponse, error) {
+	return recordCall(r, "CreateBlueprintValidation", map[string]any{"payload": payload, "query": query},
+		func() (*types.BlueprintValidationResponse, error) {
+			return r.engine.CreateBlueprintValidation(ctx, payload, query)
+		},
+	)
+}
+
+func (r *Recorder) GetBlueprintValidation(
+	ctx context.Context,
+	validationID string,
+) (*manage.BlueprintValidation, error) {
+	return recordCall(r, "GetBlueprintValidation", map[string]any{"validationID": validationID},
+		func() (*manage.BlueprintValidation, error) {
+			return r.engine.GetBlueprintValidation(ctx, validationID)
+		},
+	)
+}
+
+func (r *Recorder) StreamBlueprintValidationEvents(
+	ctx context.Context,
+	validationID string,
+	lastEventID string,
+	streamTo chan<- types.BlueprintValidationEvent,
+	errChan chan<- error,
+) error {
+	return recordStream(
+		ctx, r, "StreamBlueprintValidationEvents",
+		map[string]any{"validationID": validationID, "lastEventID": lastEventID},
+		streamTo, errChan,
+		func(event types.BlueprintValidationEvent) (string, any) {
+			return event.ID, event
+		},
+		func(innerStreamTo chan<- types.BlueprintValidationEvent, innerErrChan chan<- error) error {
+			return r.engine.StreamBlueprintValidationEvents(ctx, validationID, lastEventID, innerStreamTo, innerErrChan)
+		},
+	)
+}
+
+func (r *Recorder) CleanupBlueprintValidations(
+	ctx context.Context,
+) (*manage.CleanupOperation, error) {
+	return recordCall(r, "CleanupBlueprintValidations", nil,
+		func() (*manage.CleanupOperation, error) {
+			return r.engine.CleanupBlueprintValidations(ctx)
+		},
+	)
+}
+
+func (r *Recorder) CreateChangeset(
+	ctx context.Context,
+	payload *types.CreateChangesetPayload,
+) (*types.ChangesetResponse, error) {
+	return recordCall(r, "CreateChangeset", map[string]any{"payload": payload},
+		func() (*types.ChangesetResponse, error) {
+			return r.engine.CreateChangeset(ctx, payload)
+		},
+	)
+}
+
+func (r *Recorder) GetChangeset(
+	ctx context.Context,
+	changesetID string,
+) (*manage.Changeset, error) {
+	return recordCall(r, "GetChangeset", map[string]any{"changesetID": changesetID},
+		func() (*manage.Changeset, error) {
+			return r.engine.GetChangeset(ctx, changesetID)
+		},
+	)
+}
+
+func (r *Recorder) StreamChangeStagingEvents(
+	ctx context.Context,
+	changesetID string,
+	lastEventID string,
+	streamTo chan<- types.ChangeStagingEvent,
+	errChan chan<- error,
+) error {
+	return recordStream(
+		ctx, r, "StreamChangeStagingEvents",
+		map[string]any{"changesetID": changesetID, "lastEventID": lastEventID},
+		streamTo, errChan,
+		func(event types.ChangeStagingEvent) (string, any) {
+			return event.ID, event
+		},
+		func(innerStreamTo chan<- types.ChangeStagingEvent, innerErrChan chan<- error) error {
+			return r.engine.StreamChangeStagingEvents(ctx, changesetID, lastEventID, innerStreamTo, innerErrChan)
+		},
+	)
+}
+
+func (r *Recorder) CleanupChangesets(
+	ctx context.Context,
+) (*manage.CleanupOperation, error) {
+	return recordCall(r, "CleanupChangesets", nil,
+		func() (*manage.CleanupOperation, error) {
+			return r.engine.CleanupChangesets(ctx)
+		},
+	)
+}
+
+func (r *Recorder) CreateBlueprintInstance(
+	ctx context.Context,
+	payload *types.BlueprintInstancePayload,
+) (*types.BlueprintInstanceResponse, error) {
+	return recordCall(r, "CreateBlueprintInstance", map[string]any{"payload": payload},
+		func() (*types.BlueprintInstanceResponse, error) {
+			return r.engine.CreateBlueprintInstance(ctx, payload)
+		},
+	)
+}
+
+func (r *Recorder) UpdateBlueprintInstance(
+	ctx context.Context,
+	instanceID string,
+	payload *types.BlueprintInstancePayload,
+) (*types.BlueprintInstanceResponse, error) {
+	return recordCall(r, "UpdateBlueprintInstance", map[string]any{"instanceID": instanceID, "payload": payload},
+		func() (*types.BlueprintInstanceResponse, error) {
+			return r.engine.UpdateBlueprintInstance(ctx, instanceID, payload)
+		},
+	)
+}
+
+func (r *Recorder) GetBlueprintInstance(
+	ctx context.Context,
+	instanceID string,
+) (*state.InstanceState, error) {
+	return recordCall(r, "GetBlueprintInstance", map[string]any{"instanceID": instanceID},
+		func() (*state.InstanceState, error) {
+			return r.engine.GetBlueprintInstance(ctx, instanceID)
+		},
+	)
+}
+
+func (r *Recorder) ListBlueprintInstances(
+	ctx context.Context,
+	params state.ListInstancesParams,
+) (state.ListInstancesResult, error) {
+	return recordCall(r, "ListBlueprintInstances", map[string]any{"params": params},
+		func() (state.ListInstancesResult, error) {
+			return r.engine.ListBlueprintInstances(ctx, params)
+		},
+	)
+}
+
+func (r *Recorder) GetBlueprintInstanceExports(
+	ctx context.Context,
+	instanceID string,
+) (map[string]*state.ExportState, error) {
+	return recordCall(r, "GetBlueprintInstanceExports", map[string]any{"instanceID": instanceID},
+		func() (map[string]*state.ExportState, error) {
+			return r.engine.GetBlueprintInstanceExports(ctx, instanceID)
+		},
+	)
+}
+
+func (r *Recorder) DestroyBlueprintInstance(
+	ctx context.Context,
+	instanceID string,
+	payload *types.DestroyBlueprintInstancePayload,
+) (*types.BlueprintInstanceResponse, error) {
+	return recordCall(r, "DestroyBlueprintInstance", map[string]any{"instanceID": instanceID, "payload": payload},
+		func() (*types.BlueprintInstanceResponse, error) {
+			return r.engine.DestroyBlueprintInstance(ctx, instanceID, payload)
+		},
+	)
+}
+
+func (r *Recorder) StreamBlueprintInstanceEvents(
+	ctx context.Context,
+	instanceID string,
+	lastEventID string,
+	streamTo chan<- types.BlueprintInstanceEvent,
+	errChan chan<- error,
+) error {
+	return recordStream(
+		ctx, r, "StreamBlueprintInstanceEvents",
+		map[string]any{"instanceID": instanceID, "lastEventID": lastEventID},
+		streamTo, errChan,
+		func(event types.BlueprintInstanceEvent) (string, any) {
+			// The deploy event takes over serialisation of the instance event
+			// and drops the ID, so the ID is only recorded for the entry.
+			return event.ID, &event.DeployEvent
+		},
+		func(innerStreamTo chan<- types.BlueprintInstanceEvent, innerErrChan chan<- error) error {
+			return r.engine.StreamBlueprintInstanceEvents(ctx, instanceID, lastEventID, innerStreamTo, innerErrChan)
+		},
+	)
+}
+
+func (r *Recorder) CleanupEvents(
+	ctx context.Context,
+) (*manage.CleanupOperation, error) {
+	return recordCall(r, "CleanupEvents", nil,
+		func() (*manage.CleanupOperation, error) {
+			return r.engine.CleanupEvents(ctx)
+		},
+	)
+}
+
+func (r *Recorder) CleanupReconciliationResults(
+	ctx context.Context,
+) (*manage.CleanupOperation, error) {
+	return recordCall(r, "CleanupReconciliationResults", nil,
+		func() (*manage.CleanupOperation, error) {
+			return r.engine.CleanupReconciliationResults(ctx)
+		},
+	)
+}
+
+func (r *Recorder) GetCleanupOperation(
+	ctx context.Context,
+	cleanupType manage.CleanupType,
+	operationID string,
+) (*manage.CleanupOperation, error) {
+	return recordCall(r, "GetCleanupOperation", map[string]any{"cleanupType": cleanupType, "operationID": operationID},
+		func() (*manage.CleanupOperation, error) {
+			return r.engine.GetCleanupOperation(ctx, cleanupType, operationID)
+		},
+	)
+}
+
+func (r *Recorder) WaitForCleanupCompletion(
+	ctx context.Context,
+	cleanupType manage.CleanupType,
+	operationID string,
+	pollInterval time.Duration,
+) (*manage.CleanupOperation, error) {
+	return recordCall(
+		r, "WaitForCleanupCompletion",
+		map[string]any{"cleanupType": cleanupType, "operationID": operationID, "pollInterval": pollInterval},
+		func() (*manage.CleanupOperation, error) {
+			return r.engine.WaitForCleanupCompletion(ctx, cleanupType, operationID, pollInterval)
+		},
+	)
+}
+
+func (r *Recorder) CheckReconciliation(
+	ctx context.Context,
+	instanceID string,
+	payload *types.CheckReconciliationPayload,
+) (*container.ReconciliationCheckResult, error) {
+	return recordCall(r, "CheckReconciliation", map[string]any{"instanceID": instanceID, "payload": payload},
+		func() (*container.ReconciliationCheckResult, error) {
+			return CheckReconciliation(ctx, r.engine, instanceID, payload)
+		},
+	)
+}
+
+func (r *Recorder) ApplyReconciliation(
+	ctx context.Context,
+	instanceID string,
+	payload *types.ApplyReconciliationPayload,
+) (*container.ApplyReconciliationResult, error) {
+	return recordCall(r, "ApplyReconciliation", map[string]any{"instanceID": instanceID, "payload": payload},
+		func() (*container.ApplyReconciliationResult, error) {
+			return r.engine.ApplyReconciliation(ctx, instanceID, payload)
+		},
+	)
+}
+
+func recordCall[Result any](
+	r *Recorder,
+	method string,
+	args map[string]any,
+	call func() (Result, error),
+) (Result, error) {
+	start := time.Now()
+	result, err := call()
+
+	entry := &SessionEntry{
+		Kind:     SessionEntryCall,
+		Method:   method,
+		Duration: time.Since(start),
+		Error:    newSessionError(err),
+	}
+	if args != nil {
+		entry.Args = r.marshal(args)
+	}
+	if err == nil {
+		entry.Response = r.marshal(result)
+	}
+	r.write(entry)
+
+	return result, err
+}
+
+// recordStream starts the stream with channels owned by the recorder
+// so that events and errors can be recorded before they are forwarded
+// to the channels of the caller.
+// The stream channel of the caller is closed when the wrapped engine
+// closes the stream.
+func recordStream[Event any](
+	ctx context.Context,
+	r *Recorder,
+	method string,
+	args map[string]any,
+	streamTo chan<- Event,
+	errChan chan<- error,
+	encodeEvent func(Event) (string, any),
+	startStream func(chan<- Event, chan<- error) error,
+) error {
+	innerStreamTo := make(chan Event, cap(streamTo))
+	innerErrChan := make(chan error, cap(errChan))
+
+	start := time.Now()
+	err := startStream(innerStreamTo, innerErrChan)
+	callSeq := r.write(&SessionEntry{
+		Kind:     SessionEntryCall,
+		Method:   method,
+		Duration: time.Since(start),
+		Args:     r.marshal(args),
+		Error:    newSessionError(err),
+	})
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case streamErr := <-innerErrChan:
+				r.write(&SessionEntry{
+					Kind:   SessionEntryStreamError,
+					Method: method,
+					Call:   callSeq,
+					Error:  newSessionError(streamErr),
+				})
+				select {
+				case <-ctx.Done():
+					return
+				case errChan <- streamErr:
+				}
+			case event, ok := <-innerStreamTo:
+				if !ok {
+					r.write(&SessionEntry{
+						Kind:   SessionEntryStreamClosed,
+						Method: method,
+						Call:   callSeq,
+					})
+					close(streamTo)
+					return
+				}
+				eventID, eventData := encodeEvent(event)
+				r.write(&SessionEntry{
+					Kind:    SessionEntryEvent,
+					Method:  method,
+					Call:    callSeq,
+					EventID: eventID,
+					Event:   r.marshal(eventData),
+				})
+				select {
+				case <-ctx.Done():
+					return
+				case streamTo <- event:
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// marshal encodes a value to be recorded with values that can hold
+// secrets redacted, see redactSessionData.
+func (r *Recorder) marshal(value any) json.RawMessage {
+	data, err := json.Marshal(value)
+	if err == nil {
+		data, err = redactSessionData(data)
+	}
+	if err != nil {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.err == nil {
+			r.err = err
+		}
+		return nil
+	}
+	return data
+}
+
+// write assigns the next sequence number and the current time to the entry
+// before writing it to the session, the sequence number is returned so that
+// stream entries can refer to the call that started the stream.
+func (r *Recorder) write(entry *SessionEntry) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.seq += 1
+	entry.Seq = r.seq
+	entry.Time = time.Now()
+
+	data, err := json.Marshal(entry)
+	if err == nil {
+		// Each entry is written with a single write so that a session
+		// is readable up to the last entry when the process exits early.
+		_, err = r.w.Write(append(data, '\n'))
+	}
+	if err != nil && r.err == nil {
+		r.err = err
+	}
+	return entry.Seq
+}
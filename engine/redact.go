@@ -0,0 +1,142 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/newstack-cloud/deploy-cli-sdk/config"
+)
+
+// redactSessionData replaces values that can hold secrets in the JSON
+// encoded arguments, responses and events of a session with
+// config.RedactedValue so sessions can be attached to public issues.
+//
+// The following values are redacted wherever they appear:
+//   - All blueprint variable values, blueprints can declare any variable
+//     as secret and the blueprint is not available to the recorder
+//   - Context variable values with names that indicate they hold a secret,
+//     see config.IsSecretKey
+//   - All provider and transformer config values
+//   - Export values
+//   - Resource spec data of instance state and resolved resources
+//   - Previous and new values of field changes in change sets and drift,
+//     which covers the new and modified fields of change sets
+//   - External and persisted state of reconciliation results
+func redactSessionData(data json.RawMessage) (json.RawMessage, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// Numbers are decoded as json.Number so they are written back as they are.
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(redactSessionValue(value))
+}
+
+func redactSessionValue(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, fieldValue := range typed {
+			typed[key] = redactSessionField(key, fieldValue)
+		}
+	case []any:
+		for i, item := range typed {
+			typed[i] = redactSessionValue(item)
+		}
+	}
+	return value
+}
+
+func redactSessionField(key string, value any) any {
+	switch key {
+	case "blueprintVariables", "providers", "transformers":
+		return redactAll(value)
+	case "contextVariables":
+		return redactSecretNamedValues(value)
+	case "exports":
+		return redactExportValues(value)
+	case "specData", "resourceSpecData",
+		"prevValue", "newValue", "stateValue", "driftedValue",
+		"externalState", "persistedState":
+		return redactAll(value)
+	case "resourceWithResolvedSubs":
+		return redactSpec(value)
+	}
+	return redactSessionValue(value)
+}
+
+// redactSpec redacts the spec of a resolved resource,
+// the resource type and metadata are kept.
+func redactSpec(value any) any {
+	fields, isMap := value.(map[string]any)
+	if !isMap {
+		return redactSessionValue(value)
+	}
+
+	for key, fieldValue := range fields {
+		if key == "spec" {
+			fields[key] = redactAll(fieldValue)
+			continue
+		}
+		fields[key] = redactSessionField(key, fieldValue)
+	}
+	return fields
+}
+
+// redactAll replaces every value nested in the provided value,
+// the structure of maps and lists is kept.
+func redactAll(value any) any {
+	switch typed := value.(type) {
+	case nil:
+		return nil
+	case map[string]any:
+		for key, fieldValue := range typed {
+			typed[key] = redactAll(fieldValue)
+		}
+		return typed
+	case []any:
+		for i, item := range typed {
+			typed[i] = redactAll(item)
+		}
+		return typed
+	}
+	return config.RedactedValue
+}
+
+func redactSecretNamedValues(value any) any {
+	values, isMap := value.(map[string]any)
+	if !isMap {
+		return redactSessionValue(value)
+	}
+
+	for name, fieldValue := range values {
+		if config.IsSecretKey(name) {
+			values[name] = redactAll(fieldValue)
+		}
+	}
+	return values
+}
+
+func redactExportValues(value any) any {
+	exports, isMap := value.(map[string]any)
+	if !isMap {
+		return redactSessionValue(value)
+	}
+
+	for name, export := range exports {
+		exportFields, isExport := export.(map[string]any)
+		if !isExport {
+			exports[name] = redactSessionValue(export)
+			continue
+		}
+		for key, fieldValue := range exportFields {
+			if key == "value" {
+				exportFields[key] = redactAll(fieldValue)
+				continue
+			}
+			exportFields[key] = redactSessionValue(fieldValue)
+		}
+	}
+	return exports
+}
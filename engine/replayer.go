@@ -0,0 +1,372 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint-state/manage"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/container"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+)
+
+// ErrNoRecordedCall is returned by a Replayer when a method is called
+// more times than it was called in the recorded session.
+var ErrNoRecordedCall = errors.New("no recorded call left in the session")
+
+// Replayer is a deploy engine that plays back a session recorded by a Recorder.
+//
+// Calls are matched to recorded calls of the same method in the order they
+// were recorded, the arguments of calls are not compared with the recorded
+// arguments.
+// Recorded responses and errors are returned and recorded stream events,
+// errors and closures are played back for streams.
+//
+// The speed controls the timing of the playback, a speed of 1 plays the session
+// back with the original timing, a speed of 10 plays the session back ten
+// times faster and a speed of 0 or less plays the session back without waiting.
+type Replayer struct {
+	speed float64
+	mu    sync.Mutex
+	// calls holds the recorded calls that are yet to be replayed
+	// for each method in the order they were recorded.
+	calls map[string][]*SessionEntry
+	// streams holds the recorded stream entries for each call
+	// that started a stream.
+	streams map[int][]*SessionEntry
+}
+
+// NewReplayer creates a deploy engine that plays back the provided
+// session entries at the provided speed.
+func NewReplayer(entries []*SessionEntry, speed float64) *Replayer {
+	replayer := &Replayer{
+		speed:   speed,
+		calls:   map[string][]*SessionEntry{},
+		streams: map[int][]*SessionEntry{},
+	}
+	for _, entry := range entries {
+		if entry.Kind == SessionEntryCall {
+			replayer.calls[entry.Method] = append(replayer.calls[entry.Method], entry)
+		} else {
+			replayer.streams[entry.Call] = append(replayer.streams[entry.Call], entry)
+		}
+	}
+	return replayer
+}
+
+// NewReplayerFromFile creates a deploy engine that plays back the session
+// recorded in the provided file at the provided speed.
+func NewReplayerFromFile(path string, speed float64) (*Replayer, error) {
+	entries, err := ReadSessionFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayer(entries, speed), nil
+}
+
+func (r *Replayer) CreateBlueprintValidation(
+	ctx context.Context,
+	payload *types.CreateBlueprintValidationPayload,
+	query *types.CreateBlueprintValidationQuery,
+) (*types.BlueprintValidationResponse, error) {
+	return replayCall[*types.BlueprintValidationResponse](ctx, r, "CreateBlueprintValidation")
+}
+
+func (r *Replayer) GetBlueprintValidation(
+	ctx context.Context,
+	validationID string,
+) (*manage.BlueprintValidation, error) {
+	return replayCall[*manage.BlueprintValidation](ctx, r, "GetBlueprintValidation")
+}
+
+func (r *Replayer) StreamBlueprintValidationEvents(
+	ctx context.Context,
+	validationID string,
+	lastEventID string,
+	streamTo chan<- types.BlueprintValidationEvent,
+	errChan chan<- error,
+) error {
+	return replayStream(
+		ctx, r, "StreamBlueprintValidationEvents", streamTo, errChan,
+		func(entry *SessionEntry) (types.BlueprintValidationEvent, error) {
+			event := types.BlueprintValidationEvent{}
+			err := json.Unmarshal(entry.Event, &event)
+			return event, err
+		},
+	)
+}
+
+func (r *Replayer) CleanupBlueprintValidations(
+	ctx context.Context,
+) (*manage.CleanupOperation, error) {
+	return replayCall[*manage.CleanupOperation](ctx, r, "CleanupBlueprintValidations")
+}
+
+func (r *Replayer) CreateChangeset(
+	ctx context.Context,
+	payload *types.CreateChangesetPayload,
+) (*types.ChangesetResponse, error) {
+	return replayCall[*types.ChangesetResponse](ctx, r, "CreateChangeset")
+}
+
+func (r *Replayer) GetChangeset(
+	ctx context.Context,
+	changesetID string,
+) (*manage.Changeset, error) {
+	return replayCall[*manage.Changeset](ctx, r, "GetChangeset")
+}
+
+func (r *Replayer) StreamChangeStagingEvents(
+	ctx context.Context,
+	changesetID string,
+	lastEventID string,
+	streamTo chan<- types.ChangeStagingEvent,
+	errChan chan<- error,
+) error {
+	return replayStream(
+		ctx, r, "StreamChangeStagingEvents", streamTo, errChan,
+		func(entry *SessionEntry) (types.ChangeStagingEvent, error) {
+			event := types.ChangeStagingEvent{}
+			err := json.Unmarshal(entry.Event, &event)
+			return event, err
+		},
+	)
+}
+
+func (r *Replayer) CleanupChangesets(
+	ctx context.Context,
+) (*manage.CleanupOperation, error) {
+	return replayCall[*manage.CleanupOperation](ctx, r, "CleanupChangesets")
+}
+
+func (r *Replayer) CreateBlueprintInstance(
+	ctx context.Context,
+	payload *types.BlueprintInstancePayload,
+) (*types.BlueprintInstanceResponse, error) {
+	return replayCall[*types.BlueprintInstanceResponse](ctx, r, "CreateBlueprintInstance")
+}
+
+func (r *Replayer) UpdateBlueprintInstance(
+	ctx context.Context,
+	instanceID string,
+	payload *types.BlueprintInstancePayload,
+) (*types.BlueprintInstanceResponse, error) {
+	return replayCall[*types.BlueprintInstanceResponse](ctx, r, "UpdateBlueprintInstance")
+}
+
+func (r *Replayer) GetBlueprintInstance(
+	ctx context.Context,
+	instanceID string,
+) (*state.InstanceState, error) {
+	return replayCall[*state.InstanceState](ctx, r, "GetBlueprintInstance")
+}
+
+func (r *Replayer) ListBlueprintInstances(
+	ctx context.Context,
+	params state.ListInstancesParams,
+) (state.ListInstancesResult, error) {
+	return replayCall[state.ListInstancesResult](ctx, r, "ListBlueprintInstances")
+}
+
+func (r *Replayer) GetBlueprintInstanceExports(
+	ctx context.Context,
+	instanceID string,
+) (map[string]*state.ExportState, error) {
+	return replayCall[map[string]*state.ExportState](ctx, r, "GetBlueprintInstanceExports")
+}
+
+func (r *Replayer) DestroyBlueprintInstance(
+	ctx context.Context,
+	instanceID string,
+	payload *types.DestroyBlueprintInstancePayload,
+) (*types.BlueprintInstanceResponse, error) {
+	return replayCall[*types.BlueprintInstanceResponse](ctx, r, "DestroyBlueprintInstance")
+}
+
+func (r *Replayer) StreamBlueprintInstanceEvents(
+	ctx context.Context,
+	instanceID string,
+	lastEventID string,
+	streamTo chan<- types.BlueprintInstanceEvent,
+	errChan chan<- error,
+) error {
+	return replayStream(
+		ctx, r, "StreamBlueprintInstanceEvents", streamTo, errChan,
+		func(entry *SessionEntry) (types.BlueprintInstanceEvent, error) {
+			event := types.BlueprintInstanceEvent{ID: entry.EventID}
+			err := json.Unmarshal(entry.Event, &event.DeployEvent)
+			return event, err
+		},
+	)
+}
+
+func (r *Replayer) CleanupEvents(
+	ctx context.Context,
+) (*manage.CleanupOperation, error) {
+	return replayCall[*manage.CleanupOperation](ctx, r, "CleanupEvents")
+}
+
+func (r *Replayer) CleanupReconciliationResults(
+	ctx context.Context,
+) (*manage.CleanupOperation, error) {
+	return replayCall[*manage.CleanupOperation](ctx, r, "CleanupReconciliationResults")
+}
+
+func (r *Replayer) GetCleanupOperation(
+	ctx context.Context,
+	cleanupType manage.CleanupType,
+	operationID string,
+) (*manage.CleanupOperation, error) {
+	return replayCall[*manage.CleanupOperation](ctx, r, "GetCleanupOperation")
+}
+
+func (r *Replayer) WaitForCleanupCompletion(
+	ctx context.Context,
+	cleanupType manage.CleanupType,
+	operationID string,
+	pollInterval time.Duration,
+) (*manage.CleanupOperation, error) {
+	return replayCall[*manage.CleanupOperation](ctx, r, "WaitForCleanupCompletion")
+}
+
+func (r *Replayer) CheckReconciliation(
+	ctx context.Context,
+	instanceID string,
+	payload *types.CheckReconciliationPayload,
+) (*container.ReconciliationCheckResult, error) {
+	return replayCall[*container.ReconciliationCheckResult](ctx, r, "CheckReconciliation")
+}
+
+func (r *Replayer) ApplyReconciliation(
+	ctx context.Context,
+	instanceID string,
+	payload *types.ApplyReconciliationPayload,
+) (*container.ApplyReconciliationResult, error) {
+	return replayCall[*container.ApplyReconciliationResult](ctx, r, "ApplyReconciliation")
+}
+
+func (r *Replayer) nextCall(method string) (*SessionEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	calls := r.calls[method]
+	if len(calls) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoRecordedCall, method)
+	}
+	r.calls[method] = calls[1:]
+	return calls[0], nil
+}
+
+// wait sleeps for the provided recorded duration adjusted for
+// the speed of the replayer, false is returned when the context
+// is cancelled while waiting.
+func (r *Replayer) wait(ctx context.Context, recorded time.Duration) bool {
+	if r.speed <= 0 || recorded <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(time.Duration(float64(recorded) / r.speed))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func replayCall[Result any](
+	ctx context.Context,
+	r *Replayer,
+	method string,
+) (Result, error) {
+	var result Result
+	entry, err := r.nextCall(method)
+	if err != nil {
+		return result, err
+	}
+
+	if !r.wait(ctx, entry.Duration) {
+		return result, ctx.Err()
+	}
+
+	if entry.Error != nil {
+		return result, entry.Error.Err()
+	}
+	if len(entry.Response) > 0 {
+		if err := json.Unmarshal(entry.Response, &result); err != nil {
+			return result, fmt.Errorf("invalid recorded response for %s: %w", method, err)
+		}
+	}
+	return result, nil
+}
+
+// replayStream plays back the entries recorded for the next call of the
+// stream method with the original gaps between entries adjusted for the speed
+// of the replayer.
+// Streams that were not closed in the recorded session are left open.
+func replayStream[Event any](
+	ctx context.Context,
+	r *Replayer,
+	method string,
+	streamTo chan<- Event,
+	errChan chan<- error,
+	decodeEvent func(*SessionEntry) (Event, error),
+) error {
+	call, err := r.nextCall(method)
+	if err != nil {
+		return err
+	}
+	if !r.wait(ctx, call.Duration) {
+		return ctx.Err()
+	}
+	if call.Error != nil {
+		return call.Error.Err()
+	}
+
+	r.mu.Lock()
+	entries := r.streams[call.Seq]
+	r.mu.Unlock()
+
+	go func() {
+		previous := call.Time
+		for _, entry := range entries {
+			if !r.wait(ctx, entry.Time.Sub(previous)) {
+				return
+			}
+			previous = entry.Time
+
+			switch entry.Kind {
+			case SessionEntryEvent:
+				event, err := decodeEvent(entry)
+				if err != nil {
+					sendReplayError(ctx, errChan, fmt.Errorf("invalid recorded event for %s: %w", method, err))
+					continue
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case streamTo <- event:
+				}
+			case SessionEntryStreamError:
+				sendReplayError(ctx, errChan, entry.Error.Err())
+			case SessionEntryStreamClosed:
+				close(streamTo)
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+func sendReplayError(ctx context.Context, errChan chan<- error, err error) {
+	select {
+	case <-ctx.Done():
+	case errChan <- err:
+	}
+}
@@ -0,0 +1,360 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint-state/manage"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/container"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	deerrors "github.com/newstack-cloud/bluelink/libs/deploy-engine-client/errors"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+)
+
+const (
+	// DefaultRetryInitialWait is the wait before the first retry
+	// when a retry policy does not set an initial wait.
+	DefaultRetryInitialWait = 500 * time.Millisecond
+	// DefaultRetryMaxWait is the upper bound of the wait between retries
+	// when a retry policy does not set a max wait.
+	DefaultRetryMaxWait = 10 * time.Second
+)
+
+// RetryPolicy determines how failed deploy engine calls are retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of times a failed call is retried,
+	// retries are disabled when this is zero or less.
+	MaxRetries int
+	// InitialWait is the wait before the first retry, the wait doubles
+	// for each subsequent retry up to MaxWait.
+	// Defaults to DefaultRetryInitialWait.
+	InitialWait time.Duration
+	// MaxWait is the upper bound of the wait between retries.
+	// Defaults to DefaultRetryMaxWait.
+	MaxWait time.Duration
+	// OnRetry is called before waiting to retry a failed call,
+	// this is useful for logging.
+	OnRetry func(method string, attempt int, wait time.Duration, err error)
+}
+
+// WithRetry wraps a deploy engine so that failed calls are retried with
+// exponential backoff and jitter according to the provided policy.
+//
+// Reads (Get* and List* methods) are retried for connection errors and
+// responses that indicate the engine or a proxy in front of it is temporarily
+// unavailable (429, 502, 503 and 504).
+// All other calls create or change something in the engine, so they are only
+// retried for errors that prove the request never reached the engine,
+// such as a refused connection or a failed DNS lookup.
+// Streams are not retried.
+//
+// The provided engine is returned as is when retries are disabled by the policy.
+func WithRetry(engine DeployEngine, policy RetryPolicy) DeployEngine {
+	if policy.MaxRetries <= 0 {
+		return engine
+	}
+	if policy.InitialWait <= 0 {
+		policy.InitialWait = DefaultRetryInitialWait
+	}
+	if policy.MaxWait <= 0 {
+		policy.MaxWait = DefaultRetryMaxWait
+	}
+	return &retryingDeployEngine{
+		DeployEngine: engine,
+		policy:       policy,
+	}
+}
+
+// retryingDeployEngine embeds the wrapped engine so that calls
+// that are never retried pass straight through.
+type retryingDeployEngine struct {
+	DeployEngine
+	policy RetryPolicy
+}
+
+func (e *retryingDeployEngine) CreateBlueprintValidation(
+	ctx context.Context,
+	payload *types.CreateBlueprintValidationPayload,
+	query *types.CreateBlueprintValidationQuery,
+) (*types.BlueprintValidationResponse, error) {
+	return withRetries(ctx, e.policy, "CreateBlueprintValidation", IsNotSentError,
+		func() (*types.BlueprintValidationResponse, error) {
+			return e.DeployEngine.CreateBlueprintValidation(ctx, payload, query)
+		},
+	)
+}
+
+func (e *retryingDeployEngine) GetBlueprintValidation(
+	ctx context.Context,
+	validationID string,
+) (*manage.BlueprintValidation, error) {
+	return withRetries(ctx, e.policy, "GetBlueprintValidation", IsTransientError,
+		func() (*manage.BlueprintValidation, error) {
+			return e.DeployEngine.GetBlueprintValidation(ctx, validationID)
+		},
+	)
+}
+
+func (e *retryingDeployEngine) CleanupBlueprintValidations(
+	ctx context.Context,
+) (*manage.CleanupOperation, error) {
+	return withRetries(ctx, e.policy, "CleanupBlueprintValidations", IsNotSentError,
+		func() (*manage.CleanupOperation, error) {
+			return e.DeployEngine.CleanupBlueprintValidations(ctx)
+		},
+	)
+}
+
+func (e *retryingDeployEngine) CreateChangeset(
+	ctx context.Context,
+	payload *types.CreateChangesetPayload,
+) (*types.ChangesetResponse, error) {
+	return withRetries(ctx, e.policy, "CreateChangeset", IsNotSentError,
+		func() (*types.ChangesetResponse, error) {
+			return e.DeployEngine.CreateChangeset(ctx, payload)
+		},
+	)
+}
+
+func (e *retryingDeployEngine) GetChangeset(
+	ctx context.Context,
+	changesetID string,
+) (*manage.Changeset, error) {
+	return withRetries(ctx, e.policy, "GetChangeset", IsTransientError,
+		func() (*manage.Changeset, error) {
+			return e.DeployEngine.GetChangeset(ctx, changesetID)
+		},
+	)
+}
+
+func (e *retryingDeployEngine) CleanupChangesets(
+	ctx context.Context,
+) (*manage.CleanupOperation, error) {
+	return withRetries(ctx, e.policy, "CleanupChangesets", IsNotSentError,
+		func() (*manage.CleanupOperation, error) {
+			return e.DeployEngine.CleanupChangesets(ctx)
+		},
+	)
+}
+
+func (e *retryingDeployEngine) CreateBlueprintInstance(
+	ctx context.Context,
+	payload *types.BlueprintInstancePayload,
+) (*types.BlueprintInstanceResponse, error) {
+	return withRetries(ctx, e.policy, "CreateBlueprintInstance", IsNotSentError,
+		func() (*types.BlueprintInstanceResponse, error) {
+			return e.DeployEngine.CreateBlueprintInstance(ctx, payload)
+		},
+	)
+}
+
+func (e *retryingDeployEngine) UpdateBlueprintInstance(
+	ctx context.Context,
+	instanceID string,
+	payload *types.BlueprintInstancePayload,
+) (*types.BlueprintInstanceResponse, error) {
+	return withRetries(ctx, e.policy, "UpdateBlueprintInstance", IsNotSentError,
+		func() (*types.BlueprintInstanceResponse, error) {
+			return e.DeployEngine.UpdateBlueprintInstance(ctx, instanceID, payload)
+		},
+	)
+}
+
+func (e *retryingDeployEngine) GetBlueprintInstance(
+	ctx context.Context,
+	instanceID string,
+) (*state.InstanceState, error) {
+	return withRetries(ctx, e.policy, "GetBlueprintInstance", IsTransientError,
+		func() (*state.InstanceState, error) {
+			return e.DeployEngine.GetBlueprintInstance(ctx, instanceID)
+		},
+	)
+}
+
+func (e *retryingDeployEngine) ListBlueprintInstances(
+	ctx context.Context,
+	params state.ListInstancesParams,
+) (state.ListInstancesResult, error) {
+	return withRetries(ctx, e.policy, "ListBlueprintInstances", IsTransientError,
+		func() (state.ListInstancesResult, error) {
+			return e.DeployEngine.ListBlueprintInstances(ctx, params)
+		},
+	)
+}
+
+func (e *retryingDeployEngine) GetBlueprintInstanceExports(
+	ctx context.Context,
+	instanceID string,
+) (map[string]*state.ExportState, error) {
+	return withRetries(ctx, e.policy, "GetBlueprintInstanceExports", IsTransientError,
+		func() (map[string]*state.ExportState, error) {
+			return e.DeployEngine.GetBlueprintInstanceExports(ctx, instanceID)
+		},
+	)
+}
+
+func (e *retryingDeployEngine) DestroyBlueprintInstance(
+	ctx context.Context,
+	instanceID string,
+	payload *types.DestroyBlueprintInstancePayload,
+) (*types.BlueprintInstanceResponse, error) {
+	return withRetries(ctx, e.policy, "DestroyBlueprintInstance", IsNotSentError,
+		func() (*types.BlueprintInstanceResponse, error) {
+			return e.DeployEngine.DestroyBlueprintInstance(ctx, instanceID, payload)
+		},
+	)
+}
+
+func (e *retryingDeployEngine) CleanupEvents(
+	ctx context.Context,
+) (*manage.CleanupOperation, error) {
+	return withRetries(ctx, e.policy, "CleanupEvents", IsNotSentError,
+		func() (*manage.CleanupOperation, error) {
+			return e.DeployEngine.CleanupEvents(ctx)
+		},
+	)
+}
+
+func (e *retryingDeployEngine) CleanupReconciliationResults(
+	ctx context.Context,
+) (*manage.CleanupOperation, error) {
+	return withRetries(ctx, e.policy, "CleanupReconciliationResults", IsNotSentError,
+		func() (*manage.CleanupOperation, error) {
+			return e.DeployEngine.CleanupReconciliationResults(ctx)
+		},
+	)
+}
+
+func (e *retryingDeployEngine) GetCleanupOperation(
+	ctx context.Context,
+	cleanupType manage.CleanupType,
+	operationID string,
+) (*manage.CleanupOperation, error) {
+	return withRetries(ctx, e.policy, "GetCleanupOperation", IsTransientError,
+		func() (*manage.CleanupOperation, error) {
+			return e.DeployEngine.GetCleanupOperation(ctx, cleanupType, operationID)
+		},
+	)
+}
+
+func (e *retryingDeployEngine) CheckReconciliation(
+	ctx context.Context,
+	instanceID string,
+	payload *types.CheckReconciliationPayload,
+) (*container.ReconciliationCheckResult, error) {
+	return withRetries(ctx, e.policy, "CheckReconciliation", IsNotSentError,
+		func() (*container.ReconciliationCheckResult, error) {
+			return CheckReconciliation(ctx, e.DeployEngine, instanceID, payload)
+		},
+	)
+}
+
+func (e *retryingDeployEngine) ApplyReconciliation(
+	ctx context.Context,
+	instanceID string,
+	payload *types.ApplyReconciliationPayload,
+) (*container.ApplyReconciliationResult, error) {
+	return withRetries(ctx, e.policy, "ApplyReconciliation", IsNotSentError,
+		func() (*container.ApplyReconciliationResult, error) {
+			return e.DeployEngine.ApplyReconciliation(ctx, instanceID, payload)
+		},
+	)
+}
+
+func withRetries[Result any](
+	ctx context.Context,
+	policy RetryPolicy,
+	method string,
+	isRetryable func(error) bool,
+	call func() (Result, error),
+) (Result, error) {
+	result, err := call()
+	for attempt := 1; err != nil && attempt <= policy.MaxRetries && isRetryable(err); attempt += 1 {
+		wait := retryWait(policy, attempt)
+		if policy.OnRetry != nil {
+			policy.OnRetry(method, attempt, wait, err)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, errors.Join(ctx.Err(), err)
+		case <-timer.C:
+		}
+
+		result, err = call()
+	}
+	return result, err
+}
+
+// retryWait doubles the initial wait for each attempt up to the max wait
+// and applies jitter by picking a random wait between half and the full
+// computed wait so that clients that failed together do not retry together.
+func retryWait(policy RetryPolicy, attempt int) time.Duration {
+	wait := policy.InitialWait
+	for i := 1; i < attempt && wait < policy.MaxWait; i += 1 {
+		wait *= 2
+	}
+	wait = min(wait, policy.MaxWait)
+
+	half := wait / 2
+	return half + rand.N(wait-half+1)
+}
+
+// IsTransientError determines whether an error from a deploy engine call
+// is likely to be temporary, these are connection errors and responses
+// that indicate the engine or a proxy in front of it is temporarily
+// unavailable.
+// Requests that fail with transient errors may have been processed by the
+// engine, so only requests that are safe to repeat should be retried.
+func IsTransientError(err error) bool {
+	requestErr := &deerrors.RequestError{}
+	if errors.As(err, &requestErr) {
+		return !errors.Is(requestErr.Err, context.Canceled) &&
+			!errors.Is(requestErr.Err, context.DeadlineExceeded)
+	}
+
+	clientErr := &deerrors.ClientError{}
+	if errors.As(err, &clientErr) {
+		switch clientErr.StatusCode {
+		case http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout:
+			return true
+		}
+	}
+	return false
+}
+
+// IsNotSentError determines whether an error from a deploy engine call
+// proves that the request never reached the engine, such as a refused
+// connection or a failed DNS lookup.
+// Requests that fail with these errors are safe to retry even when they
+// would create or change something in the engine.
+func IsNotSentError(err error) bool {
+	// The request error does not support unwrapping,
+	// so the underlying network error is inspected directly.
+	requestErr := &deerrors.RequestError{}
+	if !errors.As(err, &requestErr) || requestErr.Err == nil {
+		return false
+	}
+
+	if errors.Is(requestErr.Err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	dnsErr := &net.DNSError{}
+	if errors.As(requestErr.Err, &dnsErr) {
+		return true
+	}
+
+	opErr := &net.OpError{}
+	return errors.As(requestErr.Err, &opErr) && opErr.Op == "dial"
+}
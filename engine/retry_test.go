@@ -0,0 +1,189 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	deerrors "github.com/newstack-cloud/bluelink/libs/deploy-engine-client/errors"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type RetrySuite struct {
+	suite.Suite
+	policy RetryPolicy
+}
+
+func (s *RetrySuite) SetupTest() {
+	s.policy = RetryPolicy{
+		MaxRetries:  3,
+		InitialWait: time.Millisecond,
+		MaxWait:     2 * time.Millisecond,
+	}
+}
+
+func (s *RetrySuite) Test_retries_reads_until_they_succeed() {
+	fake := &failingDeployEngine{
+		errs: []error{
+			&deerrors.ClientError{StatusCode: http.StatusBadGateway},
+			&deerrors.RequestError{Err: errors.New("connection reset by peer")},
+		},
+	}
+	retries := []string{}
+	s.policy.OnRetry = func(method string, attempt int, wait time.Duration, err error) {
+		retries = append(retries, method)
+	}
+
+	instance, err := WithRetry(fake, s.policy).GetBlueprintInstance(context.Background(), "instance-1")
+	s.Require().NoError(err)
+	s.Equal("instance-1", instance.InstanceID)
+	s.Equal(3, fake.calls)
+	s.Equal([]string{"GetBlueprintInstance", "GetBlueprintInstance"}, retries)
+}
+
+func (s *RetrySuite) Test_stops_retrying_reads_after_max_retries() {
+	unavailableErr := &deerrors.ClientError{StatusCode: http.StatusServiceUnavailable}
+	fake := &failingDeployEngine{
+		errs: []error{unavailableErr, unavailableErr, unavailableErr, unavailableErr, unavailableErr},
+	}
+
+	_, err := WithRetry(fake, s.policy).GetBlueprintInstance(context.Background(), "instance-1")
+	s.Equal(unavailableErr, err)
+	s.Equal(4, fake.calls)
+}
+
+func (s *RetrySuite) Test_does_not_retry_reads_for_client_errors() {
+	notFoundErr := &deerrors.ClientError{StatusCode: http.StatusNotFound}
+	fake := &failingDeployEngine{errs: []error{notFoundErr}}
+
+	_, err := WithRetry(fake, s.policy).GetBlueprintInstance(context.Background(), "instance-1")
+	s.Equal(notFoundErr, err)
+	s.Equal(1, fake.calls)
+}
+
+func (s *RetrySuite) Test_retries_creates_when_the_request_was_never_sent() {
+	fake := &failingDeployEngine{
+		errs: []error{
+			&deerrors.RequestError{Err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}},
+			&deerrors.RequestError{Err: &net.DNSError{Err: "no such host", Name: "engine.example.com"}},
+		},
+	}
+
+	response, err := WithRetry(fake, s.policy).CreateChangeset(context.Background(), &types.CreateChangesetPayload{})
+	s.Require().NoError(err)
+	s.NotNil(response)
+	s.Equal(3, fake.calls)
+}
+
+func (s *RetrySuite) Test_does_not_retry_creates_that_may_have_reached_the_engine() {
+	badGatewayErr := &deerrors.ClientError{StatusCode: http.StatusBadGateway}
+	fake := &failingDeployEngine{errs: []error{badGatewayErr}}
+
+	_, err := WithRetry(fake, s.policy).CreateChangeset(context.Background(), &types.CreateChangesetPayload{})
+	s.Equal(badGatewayErr, err)
+	s.Equal(1, fake.calls)
+
+	resetErr := &deerrors.RequestError{Err: &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}}
+	fake = &failingDeployEngine{errs: []error{resetErr}}
+
+	_, err = WithRetry(fake, s.policy).CreateChangeset(context.Background(), &types.CreateChangesetPayload{})
+	s.Equal(resetErr, err)
+	s.Equal(1, fake.calls)
+}
+
+func (s *RetrySuite) Test_stops_waiting_to_retry_when_the_context_is_cancelled() {
+	unavailableErr := &deerrors.ClientError{StatusCode: http.StatusServiceUnavailable}
+	fake := &failingDeployEngine{errs: []error{unavailableErr, unavailableErr}}
+	s.policy.InitialWait = time.Hour
+	s.policy.MaxWait = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.policy.OnRetry = func(method string, attempt int, wait time.Duration, err error) {
+		cancel()
+	}
+
+	_, err := WithRetry(fake, s.policy).GetBlueprintInstance(ctx, "instance-1")
+	s.ErrorIs(err, context.Canceled)
+	s.ErrorIs(err, unavailableErr)
+	s.Equal(1, fake.calls)
+}
+
+func (s *RetrySuite) Test_returns_the_engine_as_is_when_retries_are_disabled() {
+	fake := &failingDeployEngine{}
+	s.Same(fake, WithRetry(fake, RetryPolicy{}))
+}
+
+func (s *RetrySuite) Test_backs_off_exponentially_up_to_the_max_wait() {
+	policy := RetryPolicy{InitialWait: 100 * time.Millisecond, MaxWait: time.Second}
+	for attempt, expectedWait := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		4: 800 * time.Millisecond,
+		5: time.Second,
+		6: time.Second,
+	} {
+		wait := retryWait(policy, attempt)
+		s.GreaterOrEqual(wait, expectedWait/2)
+		s.LessOrEqual(wait, expectedWait)
+	}
+}
+
+func (s *RetrySuite) Test_reports_reconciliation_checks_unsupported_by_the_wrapped_engine() {
+	fake := &failingDeployEngine{}
+
+	_, err := CheckReconciliation(
+		context.Background(),
+		WithRetry(fake, s.policy),
+		"instance-1",
+		&types.CheckReconciliationPayload{},
+	)
+	s.ErrorIs(err, ErrReconciliationCheckUnsupported)
+	s.Equal(0, fake.calls)
+}
+
+// failingDeployEngine fails calls with the provided errors in order
+// before succeeding, methods that are not overridden panic.
+type failingDeployEngine struct {
+	DeployEngine
+	errs  []error
+	calls int
+}
+
+func (e *failingDeployEngine) nextErr() error {
+	e.calls += 1
+	if e.calls <= len(e.errs) {
+		return e.errs[e.calls-1]
+	}
+	return nil
+}
+
+func (e *failingDeployEngine) GetBlueprintInstance(
+	ctx context.Context,
+	instanceID string,
+) (*state.InstanceState, error) {
+	if err := e.nextErr(); err != nil {
+		return nil, err
+	}
+	return &state.InstanceState{InstanceID: instanceID}, nil
+}
+
+func (e *failingDeployEngine) CreateChangeset(
+	ctx context.Context,
+	payload *types.CreateChangesetPayload,
+) (*types.ChangesetResponse, error) {
+	if err := e.nextErr(); err != nil {
+		return nil, err
+	}
+	return &types.ChangesetResponse{}, nil
+}
+
+func TestRetrySuite(t *testing.T) {
+	suite.Run(t, new(RetrySuite))
+}
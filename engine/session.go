@@ -0,0 +1,204 @@
+package engine
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	deerrors "github.com/newstack-cloud/bluelink/libs/deploy-engine-client/errors"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+)
+
+// SessionEntryKind is the kind of an entry in a recorded deploy engine session.
+type SessionEntryKind string
+
+const (
+	// SessionEntryCall is an entry for a call made to the deploy engine,
+	// holding the arguments along with the response or error.
+	SessionEntryCall SessionEntryKind = "call"
+	// SessionEntryEvent is an entry for an event received from a stream.
+	SessionEntryEvent SessionEntryKind = "event"
+	// SessionEntryStreamError is an entry for an error sent to the
+	// error channel of a stream.
+	SessionEntryStreamError SessionEntryKind = "streamError"
+	// SessionEntryStreamClosed is an entry for a stream that was closed
+	// by the deploy engine client.
+	SessionEntryStreamClosed SessionEntryKind = "streamClosed"
+)
+
+// SessionEntry is a single line of a recorded deploy engine session,
+// sessions are stored as newline-delimited JSON (NDJSON) with one entry per line.
+type SessionEntry struct {
+	// Seq is the position of the entry in the session starting from 1.
+	Seq int `json:"seq"`
+	// Time is when the call returned or when the stream entry was received.
+	Time time.Time        `json:"time"`
+	Kind SessionEntryKind `json:"kind"`
+	// Method is the name of the DeployEngine method that was called
+	// or that started the stream.
+	Method string `json:"method"`
+	// Call is the sequence number of the call that started the stream
+	// for event, stream error and stream closed entries.
+	Call int `json:"call,omitempty"`
+	// Duration is how long a call took in nanoseconds.
+	Duration time.Duration `json:"duration,omitempty"`
+	// Args holds the arguments of a call other than the context and
+	// stream channels keyed by parameter name.
+	Args json.RawMessage `json:"args,omitempty"`
+	// Response holds the result of a successful call.
+	Response json.RawMessage `json:"response,omitempty"`
+	// EventID is the ID of a stream event, this is recorded separately from
+	// the event as not all event types include the ID when serialised.
+	EventID string          `json:"eventId,omitempty"`
+	Event   json.RawMessage `json:"event,omitempty"`
+	Error   *SessionError   `json:"error,omitempty"`
+}
+
+// SessionErrorType is the type of a deploy engine client error
+// recorded in a session.
+type SessionErrorType string
+
+const (
+	// SessionErrorClient is recorded for deploy engine client errors
+	// caused by an unexpected response.
+	SessionErrorClient SessionErrorType = "client"
+	// SessionErrorRequest is recorded for deploy engine client errors
+	// caused by a failed request, such as a connection error.
+	SessionErrorRequest SessionErrorType = "request"
+	// SessionErrorStream is recorded for errors received from the
+	// deploy engine in an event stream.
+	SessionErrorStream SessionErrorType = "stream"
+	// SessionErrorOther is recorded for all other errors.
+	SessionErrorOther SessionErrorType = "other"
+)
+
+// SessionError is an error recorded in a session that holds enough
+// information to reproduce the original deploy engine client error
+// when the session is replayed.
+type SessionError struct {
+	Type                  SessionErrorType               `json:"type"`
+	Message               string                         `json:"message"`
+	StatusCode            int                            `json:"statusCode,omitempty"`
+	Code                  string                         `json:"code,omitempty"`
+	ValidationErrors      []*deerrors.ValidationError    `json:"validationErrors,omitempty"`
+	ValidationDiagnostics []*core.Diagnostic             `json:"validationDiagnostics,omitempty"`
+	DriftBlockedResponse  *types.DriftBlockedResponse    `json:"driftBlockedResponse,omitempty"`
+	StreamEvent           *types.StreamErrorMessageEvent `json:"streamEvent,omitempty"`
+}
+
+func newSessionError(err error) *SessionError {
+	if err == nil {
+		return nil
+	}
+
+	clientErr := &deerrors.ClientError{}
+	if errors.As(err, &clientErr) {
+		return &SessionError{
+			Type:                  SessionErrorClient,
+			Message:               clientErr.Message,
+			StatusCode:            clientErr.StatusCode,
+			Code:                  clientErr.Code,
+			ValidationErrors:      clientErr.ValidationErrors,
+			ValidationDiagnostics: clientErr.ValidationDiagnostics,
+			DriftBlockedResponse:  clientErr.DriftBlockedResponse,
+		}
+	}
+
+	requestErr := &deerrors.RequestError{}
+	if errors.As(err, &requestErr) && requestErr.Err != nil {
+		return &SessionError{
+			Type:    SessionErrorRequest,
+			Message: requestErr.Err.Error(),
+		}
+	}
+
+	streamErr := &deerrors.StreamError{}
+	if errors.As(err, &streamErr) && streamErr.Event != nil {
+		return &SessionError{
+			Type:        SessionErrorStream,
+			Message:     streamErr.Event.Message,
+			StreamEvent: streamErr.Event,
+		}
+	}
+
+	return &SessionError{
+		Type:    SessionErrorOther,
+		Message: err.Error(),
+	}
+}
+
+// Err reproduces the recorded error, deploy engine client errors are
+// reproduced with their original type so that replayed sessions are handled
+// in the same way as the recorded session.
+// The underlying cause of request errors is only available as a message.
+func (e *SessionError) Err() error {
+	if e == nil {
+		return nil
+	}
+
+	switch e.Type {
+	case SessionErrorClient:
+		return &deerrors.ClientError{
+			StatusCode:            e.StatusCode,
+			Message:               e.Message,
+			Code:                  e.Code,
+			ValidationErrors:      e.ValidationErrors,
+			ValidationDiagnostics: e.ValidationDiagnostics,
+			DriftBlockedResponse:  e.DriftBlockedResponse,
+		}
+	case SessionErrorRequest:
+		return &deerrors.RequestError{Err: errors.New(e.Message)}
+	case SessionErrorStream:
+		event := e.StreamEvent
+		if event == nil {
+			event = &types.StreamErrorMessageEvent{Message: e.Message}
+		}
+		return &deerrors.StreamError{Event: event}
+	default:
+		return errors.New(e.Message)
+	}
+}
+
+// ReadSession reads the entries of a session recorded by a Recorder.
+func ReadSession(r io.Reader) ([]*SessionEntry, error) {
+	entries := []*SessionEntry{}
+	scanner := bufio.NewScanner(r)
+	// Responses such as instance state for large blueprints
+	// can exceed the default max line length of the scanner.
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line += 1
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		entry := &SessionEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return nil, fmt.Errorf("invalid session entry on line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ReadSessionFile reads the entries of a session file recorded by a Recorder.
+func ReadSessionFile(path string) ([]*SessionEntry, error) {
+	sessionFile, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer sessionFile.Close()
+
+	return ReadSession(sessionFile)
+}
@@ -0,0 +1,365 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint-state/manage"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/changes"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/container"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/provider"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/schema"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	deerrors "github.com/newstack-cloud/bluelink/libs/deploy-engine-client/errors"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/deploy-cli-sdk/config"
+	"github.com/stretchr/testify/suite"
+)
+
+type SessionSuite struct {
+	suite.Suite
+	session *bytes.Buffer
+}
+
+func (s *SessionSuite) SetupTest() {
+	s.session = &bytes.Buffer{}
+}
+
+func (s *SessionSuite) Test_replays_recorded_responses_and_errors() {
+	driftErr := &deerrors.ClientError{
+		StatusCode: http.StatusConflict,
+		Message:    "drift detected",
+		DriftBlockedResponse: &types.DriftBlockedResponse{
+			ChangesetID: "changeset-1",
+			Message:     "drift detected",
+		},
+	}
+	recorder := NewRecorder(&sessionDeployEngine{createChangesetErr: driftErr}, s.session)
+
+	_, err := recorder.GetBlueprintInstance(context.Background(), "instance-1")
+	s.Require().NoError(err)
+	_, err = recorder.CreateChangeset(context.Background(), &types.CreateChangesetPayload{InstanceID: "instance-1"})
+	s.Require().Error(err)
+	s.Require().NoError(recorder.Err())
+
+	replayer := s.replayer(0)
+	instance, err := replayer.GetBlueprintInstance(context.Background(), "instance-1")
+	s.Require().NoError(err)
+	s.Equal("instance-1", instance.InstanceID)
+	s.Equal(core.InstanceStatusDeployed, instance.Status)
+
+	_, err = replayer.CreateChangeset(context.Background(), &types.CreateChangesetPayload{})
+	clientErr, isDriftBlocked := deerrors.IsDriftBlockedError(err)
+	s.True(isDriftBlocked)
+	s.Equal("changeset-1", clientErr.DriftBlockedResponse.ChangesetID)
+}
+
+func (s *SessionSuite) Test_records_call_arguments() {
+	recorder := NewRecorder(&sessionDeployEngine{}, s.session)
+	_, err := recorder.GetBlueprintInstance(context.Background(), "instance-1")
+	s.Require().NoError(err)
+
+	entries, err := ReadSession(s.session)
+	s.Require().NoError(err)
+	s.Require().Len(entries, 1)
+	s.Equal(1, entries[0].Seq)
+	s.Equal(SessionEntryCall, entries[0].Kind)
+	s.Equal("GetBlueprintInstance", entries[0].Method)
+	s.JSONEq(`{"instanceID":"instance-1"}`, string(entries[0].Args))
+}
+
+func (s *SessionSuite) Test_redacts_secrets_in_recorded_calls() {
+	recorder := NewRecorder(&sessionDeployEngine{
+		instanceExports: map[string]*state.ExportState{
+			"dbPassword": {Value: core.MappingNodeFromString("export-secret")},
+		},
+	}, s.session)
+
+	_, err := recorder.CreateChangeset(context.Background(), &types.CreateChangesetPayload{
+		InstanceID: "instance-1",
+		Config: &types.BlueprintOperationConfig{
+			Providers: map[string]map[string]*core.ScalarValue{
+				"aws": {"accessKeyId": core.ScalarFromString("provider-secret")},
+			},
+			ContextVariables: map[string]*core.ScalarValue{
+				"region":   core.ScalarFromString("eu-west-2"),
+				"apiToken": core.ScalarFromString("context-secret"),
+			},
+			BlueprintVariables: map[string]*core.ScalarValue{
+				"databaseUser": core.ScalarFromString("variable-secret"),
+			},
+		},
+	})
+	s.Require().NoError(err)
+	_, err = recorder.GetBlueprintInstance(context.Background(), "instance-1")
+	s.Require().NoError(err)
+	s.Require().NoError(recorder.Err())
+
+	recorded := s.session.String()
+	for _, secret := range []string{
+		"provider-secret", "context-secret", "variable-secret", "export-secret",
+	} {
+		s.NotContains(recorded, secret)
+	}
+	s.Contains(recorded, config.RedactedValue)
+	s.Contains(recorded, "eu-west-2")
+
+	// Redacted sessions can still be replayed.
+	replayer := s.replayer(0)
+	_, err = replayer.CreateChangeset(context.Background(), &types.CreateChangesetPayload{})
+	s.Require().NoError(err)
+	instance, err := replayer.GetBlueprintInstance(context.Background(), "instance-1")
+	s.Require().NoError(err)
+	s.Equal(config.RedactedValue, core.StringValue(instance.Exports["dbPassword"].Value))
+}
+
+func (s *SessionSuite) Test_redacts_secrets_in_change_sets_and_reconciliation_results() {
+	recorder := NewRecorder(&sessionDeployEngine{}, s.session)
+
+	changeset, err := recorder.GetChangeset(context.Background(), "changeset-1")
+	s.Require().NoError(err)
+	s.Require().NotNil(changeset.Changes)
+	_, err = recorder.CheckReconciliation(
+		context.Background(),
+		"instance-1",
+		&types.CheckReconciliationPayload{},
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(recorder.Err())
+
+	recorded := s.session.String()
+	for _, secret := range []string{
+		"new-field-secret", "resolved-spec-secret", "prev-value-secret",
+		"modified-value-secret", "external-secret", "persisted-secret",
+	} {
+		s.NotContains(recorded, secret)
+	}
+	s.Contains(recorded, "spec.password")
+	s.Contains(recorded, "aws/rds/instance")
+
+	replayer := s.replayer(0)
+	_, err = replayer.GetChangeset(context.Background(), "changeset-1")
+	s.Require().NoError(err)
+	result, err := replayer.CheckReconciliation(
+		context.Background(),
+		"instance-1",
+		&types.CheckReconciliationPayload{},
+	)
+	s.Require().NoError(err)
+	s.Equal(config.RedactedValue, core.StringValue(result.Resources[0].ExternalState))
+}
+
+func (s *SessionSuite) Test_replays_recorded_stream_events() {
+	recorder := NewRecorder(&sessionDeployEngine{}, s.session)
+	s.Equal(
+		[]string{"event-1", "event-2"},
+		s.collectEventIDs(recorder),
+	)
+
+	// The stream error is recorded as well as forwarded.
+	entries, err := ReadSession(bytes.NewReader(s.session.Bytes()))
+	s.Require().NoError(err)
+	kinds := []SessionEntryKind{}
+	for _, entry := range entries {
+		kinds = append(kinds, entry.Kind)
+	}
+	s.Equal([]SessionEntryKind{
+		SessionEntryCall,
+		SessionEntryEvent,
+		SessionEntryStreamError,
+		SessionEntryEvent,
+		SessionEntryStreamClosed,
+	}, kinds)
+
+	s.Equal(
+		[]string{"event-1", "event-2"},
+		s.collectEventIDs(s.replayer(0)),
+	)
+}
+
+func (s *SessionSuite) Test_replays_without_waiting_when_the_speed_is_zero() {
+	start := time.Now()
+	replayer := NewReplayer([]*SessionEntry{
+		{
+			Seq:      1,
+			Time:     start,
+			Kind:     SessionEntryCall,
+			Method:   "GetBlueprintInstance",
+			Duration: time.Hour,
+			Response: []byte(`{"id":"instance-1"}`),
+		},
+	}, 0)
+
+	instance, err := replayer.GetBlueprintInstance(context.Background(), "instance-1")
+	s.Require().NoError(err)
+	s.Equal("instance-1", instance.InstanceID)
+	s.Less(time.Since(start), time.Minute)
+}
+
+func (s *SessionSuite) Test_fails_when_no_recorded_call_is_left() {
+	_, err := NewReplayer(nil, 1).GetBlueprintInstance(context.Background(), "instance-1")
+	s.ErrorIs(err, ErrNoRecordedCall)
+	s.Contains(err.Error(), "GetBlueprintInstance")
+}
+
+func (s *SessionSuite) Test_reports_invalid_session_entries() {
+	_, err := ReadSession(bytes.NewBufferString("{\"seq\":1}\nnot json\n"))
+	s.ErrorContains(err, "line 2")
+}
+
+func (s *SessionSuite) replayer(speed float64) *Replayer {
+	entries, err := ReadSession(bytes.NewReader(s.session.Bytes()))
+	s.Require().NoError(err)
+	return NewReplayer(entries, speed)
+}
+
+func (s *SessionSuite) collectEventIDs(deployEngine DeployEngine) []string {
+	streamTo := make(chan types.BlueprintInstanceEvent)
+	errChan := make(chan error)
+	err := deployEngine.StreamBlueprintInstanceEvents(
+		context.Background(), "instance-1", "", streamTo, errChan,
+	)
+	s.Require().NoError(err)
+
+	eventIDs := []string{}
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event, ok := <-streamTo:
+			if !ok {
+				return eventIDs
+			}
+			s.NotNil(event.ResourceUpdateEvent)
+			eventIDs = append(eventIDs, event.ID)
+		case err := <-errChan:
+			s.ErrorContains(err, "connection reset by peer")
+		case <-timeout:
+			s.Fail("timed out waiting for the stream to close")
+			return eventIDs
+		}
+	}
+}
+
+// sessionDeployEngine is a fake deploy engine for the calls used to
+// test recording and replaying sessions, methods that are not
+// overridden panic.
+type sessionDeployEngine struct {
+	DeployEngine
+	createChangesetErr error
+	instanceExports    map[string]*state.ExportState
+}
+
+func (e *sessionDeployEngine) GetBlueprintInstance(
+	ctx context.Context,
+	instanceID string,
+) (*state.InstanceState, error) {
+	return &state.InstanceState{
+		InstanceID: instanceID,
+		Status:     core.InstanceStatusDeployed,
+		Exports:    e.instanceExports,
+	}, nil
+}
+
+func (e *sessionDeployEngine) CreateChangeset(
+	ctx context.Context,
+	payload *types.CreateChangesetPayload,
+) (*types.ChangesetResponse, error) {
+	if e.createChangesetErr != nil {
+		return nil, e.createChangesetErr
+	}
+	return &types.ChangesetResponse{}, nil
+}
+
+func (e *sessionDeployEngine) GetChangeset(
+	ctx context.Context,
+	changesetID string,
+) (*manage.Changeset, error) {
+	return &manage.Changeset{
+		ID: changesetID,
+		Changes: &changes.BlueprintChanges{
+			NewResources: map[string]provider.Changes{
+				"database": {
+					AppliedResourceInfo: provider.ResourceInfo{
+						ResourceWithResolvedSubs: &provider.ResolvedResource{
+							Type: &schema.ResourceTypeWrapper{Value: "aws/rds/instance"},
+							Spec: core.MappingNodeFromString("resolved-spec-secret"),
+						},
+					},
+					NewFields: []provider.FieldChange{
+						{
+							FieldPath: "spec.password",
+							NewValue:  core.MappingNodeFromString("new-field-secret"),
+						},
+					},
+				},
+			},
+			ResourceChanges: map[string]provider.Changes{
+				"cache": {
+					ModifiedFields: []provider.FieldChange{
+						{
+							FieldPath: "spec.authToken",
+							PrevValue: core.MappingNodeFromString("prev-value-secret"),
+							NewValue:  core.MappingNodeFromString("modified-value-secret"),
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func (e *sessionDeployEngine) CheckReconciliation(
+	ctx context.Context,
+	instanceID string,
+	payload *types.CheckReconciliationPayload,
+) (*container.ReconciliationCheckResult, error) {
+	return &container.ReconciliationCheckResult{
+		InstanceID: instanceID,
+		Resources: []container.ResourceReconcileResult{
+			{
+				ResourceName:   "database",
+				ExternalState:  core.MappingNodeFromString("external-secret"),
+				PersistedState: core.MappingNodeFromString("persisted-secret"),
+			},
+		},
+		HasDrift: true,
+	}, nil
+}
+
+func (e *sessionDeployEngine) StreamBlueprintInstanceEvents(
+	ctx context.Context,
+	instanceID string,
+	lastEventID string,
+	streamTo chan<- types.BlueprintInstanceEvent,
+	errChan chan<- error,
+) error {
+	go func() {
+		streamTo <- resourceUpdateEvent("event-1")
+		errChan <- &deerrors.RequestError{Err: errors.New("connection reset by peer")}
+		streamTo <- resourceUpdateEvent("event-2")
+		close(streamTo)
+	}()
+	return nil
+}
+
+func resourceUpdateEvent(id string) types.BlueprintInstanceEvent {
+	return types.BlueprintInstanceEvent{
+		ID: id,
+		DeployEvent: container.DeployEvent{
+			ResourceUpdateEvent: &container.ResourceDeployUpdateMessage{
+				InstanceID:   "instance-1",
+				ResourceName: "orders",
+				Status:       core.ResourceStatusCreated,
+			},
+		},
+	}
+}
+
+func TestSessionSuite(t *testing.T) {
+	suite.Run(t, new(SessionSuite))
+}
@@ -0,0 +1,125 @@
+package jsonout
+
+import (
+	"io"
+	"sync"
+
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+)
+
+// Operations that stream events to NDJSON output.
+const (
+	OperationStage   = "stage"
+	OperationDeploy  = "deploy"
+	OperationDestroy = "destroy"
+)
+
+// StreamEvent represents an event received from a deploy engine event stream,
+// each event is written as a single line of NDJSON output.
+//
+// The field names of stream events are stable so that they can be relied on
+// by tools that consume NDJSON output.
+type StreamEvent struct {
+	// Event is the type of the event as it appears in the `event` field of the
+	// raw deploy engine event stream.
+	// For change staging events this is one of "resourceChanges",
+	// "childChanges", "linkChanges", "driftDetected" or "changeStagingComplete".
+	// For deployment and destroy events this is one of "resource", "child",
+	// "link", "instanceUpdate", "preRollbackState" or "finish".
+	Event string `json:"event"`
+	// Operation is the CLI operation that received the event,
+	// one of "stage", "deploy" or "destroy".
+	// Events received while staging changes as a part of a deploy or destroy
+	// operation have the "stage" operation.
+	Operation string `json:"operation"`
+	// ID is the ID of the event assigned by the deploy engine.
+	ID string `json:"id"`
+	// Data holds the event data as received from the deploy engine.
+	Data any `json:"data"`
+}
+
+// EventStream writes events received from deploy engine event streams
+// as NDJSON, one JSON object per line.
+// The final result of the operation is written as the last line
+// with the same structure as the result written in JSON mode.
+//
+// All methods can be called on a nil EventStream, in which case nothing
+// is written, so callers that are not streaming events can hold a nil stream.
+type EventStream struct {
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// NewEventStream creates a new event stream that writes NDJSON lines
+// to the provided writer.
+func NewEventStream(w io.Writer) *EventStream {
+	return &EventStream{w: w}
+}
+
+// WriteChangeStagingEvent writes a change staging event
+// received for the provided operation.
+func (s *EventStream) WriteChangeStagingEvent(operation string, event *types.ChangeStagingEvent) {
+	if s == nil || event == nil {
+		return
+	}
+
+	streamEvent := StreamEvent{
+		Event:     string(event.GetType()),
+		Operation: operation,
+		ID:        event.ID,
+	}
+	switch {
+	case event.ResourceChanges != nil:
+		streamEvent.Data = event.ResourceChanges
+	case event.ChildChanges != nil:
+		streamEvent.Data = event.ChildChanges
+	case event.LinkChanges != nil:
+		streamEvent.Data = event.LinkChanges
+	case event.CompleteChanges != nil:
+		streamEvent.Data = event.CompleteChanges
+	case event.DriftDetected != nil:
+		streamEvent.Data = event.DriftDetected
+	}
+	s.WriteLine(streamEvent)
+}
+
+// WriteBlueprintInstanceEvent writes a deployment or destroy event
+// received for the provided operation.
+func (s *EventStream) WriteBlueprintInstanceEvent(operation string, event *types.BlueprintInstanceEvent) {
+	if s == nil || event == nil {
+		return
+	}
+
+	streamEvent := StreamEvent{
+		Event:     string(event.GetType()),
+		Operation: operation,
+		ID:        event.ID,
+	}
+	switch {
+	case event.ResourceUpdateEvent != nil:
+		streamEvent.Data = event.ResourceUpdateEvent
+	case event.ChildUpdateEvent != nil:
+		streamEvent.Data = event.ChildUpdateEvent
+	case event.LinkUpdateEvent != nil:
+		streamEvent.Data = event.LinkUpdateEvent
+	case event.DeploymentUpdateEvent != nil:
+		streamEvent.Data = event.DeploymentUpdateEvent
+	case event.FinishEvent != nil:
+		streamEvent.Data = event.FinishEvent
+	case event.PreRollbackStateEvent != nil:
+		streamEvent.Data = event.PreRollbackStateEvent
+	}
+	s.WriteLine(streamEvent)
+}
+
+// WriteLine writes a value as a single line of the stream,
+// this is used for the final result of an operation.
+func (s *EventStream) WriteLine(v any) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return WriteJSONLine(s.w, v)
+}
@@ -1,9 +1,12 @@
 package jsonout
 
 import (
+	"encoding/json"
+
 	"github.com/newstack-cloud/bluelink/libs/blueprint/changes"
 	"github.com/newstack-cloud/bluelink/libs/blueprint/container"
 	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/deploy-cli-sdk/stateio"
 )
 
 // StageOutput represents a successful staging result.
@@ -12,6 +15,8 @@ type StageOutput struct {
 	ChangesetID  string                    `json:"changesetId"`
 	InstanceID   string                    `json:"instanceId,omitempty"`
 	InstanceName string                    `json:"instanceName,omitempty"`
+	Context      string                    `json:"context,omitempty"`
+	Variables    []VariableValue           `json:"variables,omitempty"`
 	Changes      *changes.BlueprintChanges `json:"changes"`
 	Summary      ChangeSummary             `json:"summary"`
 }
@@ -22,6 +27,7 @@ type StageDriftOutput struct {
 	DriftDetected  bool                                `json:"driftDetected"`
 	InstanceID     string                              `json:"instanceId"`
 	InstanceName   string                              `json:"instanceName,omitempty"`
+	Context        string                              `json:"context,omitempty"`
 	Message        string                              `json:"message"`
 	Reconciliation *container.ReconciliationCheckResult `json:"reconciliation"`
 }
@@ -29,6 +35,7 @@ type StageDriftOutput struct {
 // ErrorOutput represents a structured error output.
 type ErrorOutput struct {
 	Success bool        `json:"success"`
+	Context string      `json:"context,omitempty"`
 	Error   ErrorDetail `json:"error"`
 }
 
@@ -121,6 +128,8 @@ type DeployOutput struct {
 	Success          bool                              `json:"success"`
 	InstanceID       string                            `json:"instanceId"`
 	InstanceName     string                            `json:"instanceName,omitempty"`
+	Context          string                            `json:"context,omitempty"`
+	Variables        []VariableValue                   `json:"variables,omitempty"`
 	ChangesetID      string                            `json:"changesetId"`
 	Status           string                            `json:"status"`
 	InstanceState    *state.InstanceState              `json:"instanceState,omitempty"`
@@ -153,6 +162,7 @@ type DeployDriftOutput struct {
 	DriftDetected  bool                                 `json:"driftDetected"`
 	InstanceID     string                               `json:"instanceId"`
 	InstanceName   string                               `json:"instanceName,omitempty"`
+	Context        string                               `json:"context,omitempty"`
 	ChangesetID    string                               `json:"changesetId,omitempty"`
 	Message        string                               `json:"message"`
 	Reconciliation *container.ReconciliationCheckResult `json:"reconciliation"`
@@ -163,6 +173,8 @@ type DestroyOutput struct {
 	Success         bool                 `json:"success"`
 	InstanceID      string               `json:"instanceId"`
 	InstanceName    string               `json:"instanceName,omitempty"`
+	Context         string               `json:"context,omitempty"`
+	Variables       []VariableValue      `json:"variables,omitempty"`
 	ChangesetID     string               `json:"changesetId"`
 	Status          string               `json:"status"`
 	InstanceState   *state.InstanceState `json:"instanceState,omitempty"`
@@ -194,6 +206,7 @@ type DestroyDriftOutput struct {
 	DriftDetected  bool                                 `json:"driftDetected"`
 	InstanceID     string                               `json:"instanceId"`
 	InstanceName   string                               `json:"instanceName,omitempty"`
+	Context        string                               `json:"context,omitempty"`
 	Message        string                               `json:"message"`
 	Reconciliation *container.ReconciliationCheckResult `json:"reconciliation"`
 }
@@ -214,11 +227,203 @@ type ListInstanceItem struct {
 	LastDeployedTimestamp int64  `json:"lastDeployedTimestamp"`
 }
 
+// InstanceExportsOutput represents the exports of a blueprint instance.
+type InstanceExportsOutput struct {
+	Success      bool                          `json:"success"`
+	InstanceID   string                        `json:"instanceId,omitempty"`
+	InstanceName string                        `json:"instanceName,omitempty"`
+	Exports      map[string]*state.ExportState `json:"exports"`
+}
+
 // StateImportOutput represents a state import result.
+// Summary and Instances are only set for imports that check for conflicts
+// and dry runs, Instances reports whether each instance is new, identical
+// or differs from the state along with the action taken.
 type StateImportOutput struct {
-	Success        bool   `json:"success"`
-	Mode           string `json:"mode"`
-	InstancesCount int    `json:"instancesCount,omitempty"`
-	FilesExtracted int    `json:"filesExtracted,omitempty"`
-	Message        string `json:"message"`
+	Success        bool                           `json:"success"`
+	Mode           string                         `json:"mode"`
+	InstancesCount int                            `json:"instancesCount,omitempty"`
+	FilesExtracted int                            `json:"filesExtracted,omitempty"`
+	Message        string                         `json:"message"`
+	DryRun         bool                           `json:"dryRun,omitempty"`
+	Summary        *stateio.ImportSummary         `json:"summary,omitempty"`
+	Instances      []stateio.InstanceImportResult `json:"instances,omitempty"`
+}
+
+// ValidateOutput represents the result of a blueprint validation.
+// Success is false when the validation produced one or more error diagnostics.
+type ValidateOutput struct {
+	Success       bool            `json:"success"`
+	BlueprintFile string          `json:"blueprintFile"`
+	Variables     []VariableValue `json:"variables,omitempty"`
+	Diagnostics   []Diagnostic    `json:"diagnostics"`
+	Summary       ValidateSummary `json:"summary"`
+}
+
+// ValidateSummary contains diagnostic counts by level for a validation.
+type ValidateSummary struct {
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+	Info     int `json:"info"`
+}
+
+// DriftCheckOutput represents the result of checking a blueprint instance for drift.
+// Success is true when the check completed, regardless of whether drift was detected.
+type DriftCheckOutput struct {
+	Success        bool                                 `json:"success"`
+	DriftDetected  bool                                 `json:"driftDetected"`
+	InstanceID     string                               `json:"instanceId"`
+	InstanceName   string                               `json:"instanceName,omitempty"`
+	Reconciliation *container.ReconciliationCheckResult `json:"reconciliation"`
+}
+
+// DriftReconcileOutput represents the result of reconciling drift for a blueprint instance.
+type DriftReconcileOutput struct {
+	Success          bool                                 `json:"success"`
+	DriftDetected    bool                                 `json:"driftDetected"`
+	InstanceID       string                               `json:"instanceId"`
+	InstanceName     string                               `json:"instanceName,omitempty"`
+	Reconciliation   *container.ReconciliationCheckResult `json:"reconciliation"`
+	Decisions        []ReconcileDecision                  `json:"decisions"`
+	ResourcesUpdated int                                  `json:"resourcesUpdated"`
+	LinksUpdated     int                                  `json:"linksUpdated"`
+	Errors           []ReconcileError                     `json:"errors,omitempty"`
+}
+
+// ReconcileDecision represents the choice made for a single element
+// that needed reconciliation.
+type ReconcileDecision struct {
+	ElementType  string `json:"elementType"` // "resource" or "link"
+	Name         string `json:"name"`
+	ChildPath    string `json:"childPath,omitempty"`
+	ResourceType string `json:"resourceType,omitempty"`
+	DriftType    string `json:"driftType"`        // "drift", "interrupted" or "state_refresh"
+	Choice       string `json:"choice"`           // "accept-external" or "keep-state"
+	Action       string `json:"action,omitempty"` // the reconciliation action applied when accepting
+}
+
+// ReconcileError represents a failure to reconcile a single element.
+type ReconcileError struct {
+	ElementID   string `json:"elementId"`
+	ElementName string `json:"elementName"`
+	ElementType string `json:"elementType"`
+	Error       string `json:"error"`
+}
+
+// BatchDeployOutput represents the aggregated result of deploying
+// the instances listed in a deployment manifest.
+type BatchDeployOutput struct {
+	Success   bool                  `json:"success"`
+	Context   string                `json:"context,omitempty"`
+	Variables []VariableValue       `json:"variables,omitempty"`
+	Summary   BatchDeploySummary    `json:"summary"`
+	Instances []BatchInstanceOutput `json:"instances"`
+}
+
+// BatchDeploySummary contains counts of instances by outcome.
+type BatchDeploySummary struct {
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped"`
+}
+
+// BatchInstanceOutput represents the outcome of deploying a single
+// instance from a deployment manifest.
+type BatchInstanceOutput struct {
+	InstanceName  string   `json:"instanceName"`
+	BlueprintFile string   `json:"blueprintFile"`
+	DependsOn     []string `json:"dependsOn,omitempty"`
+	Status        string   `json:"status"` // "succeeded", "failed" or "skipped"
+	Error         string   `json:"error,omitempty"`
+	// Result holds the JSON output of the deployment of the instance,
+	// in the same form as the output of the deploy command.
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+// DoctorOutput represents the report produced by the doctor command,
+// intended to be attached to support tickets.
+type DoctorOutput struct {
+	Success  bool          `json:"success"`
+	CLIName  string        `json:"cliName,omitempty"`
+	Platform string        `json:"platform"`
+	Summary  DoctorSummary `json:"summary"`
+	Checks   []DoctorCheck `json:"checks"`
+}
+
+// DoctorSummary contains counts of doctor checks by status.
+type DoctorSummary struct {
+	Passed   int `json:"passed"`
+	Warnings int `json:"warnings"`
+	Failed   int `json:"failed"`
+	Skipped  int `json:"skipped"`
+}
+
+// DoctorCheck represents the result of a single doctor check.
+type DoctorCheck struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Status  string `json:"status"` // "pass", "warn", "fail" or "skip"
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// ConfigShowOutput represents the effective CLI configuration
+// along with the source of each value.
+type ConfigShowOutput struct {
+	Success bool `json:"success"`
+	// ConfigFile is the config file with the highest precedence.
+	ConfigFile string `json:"configFile,omitempty"`
+	// ConfigFiles holds all loaded config files from lowest
+	// to highest precedence.
+	ConfigFiles []string      `json:"configFiles,omitempty"`
+	Context     string        `json:"context,omitempty"`
+	Values      []ConfigValue `json:"values"`
+}
+
+// ConfigValue represents the effective value of a single configuration key.
+type ConfigValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	// Source is one of "flag", "env", "context", "config file", "flag default",
+	// "default" or "unset".
+	Source string `json:"source"`
+	// SourceName is the flag name, environment variable name, context name
+	// or config file path the value was resolved from.
+	SourceName string `json:"sourceName,omitempty"`
+	// EnvVar is the environment variable bound to the key, if any.
+	EnvVar   string `json:"envVar,omitempty"`
+	Redacted bool   `json:"redacted,omitempty"`
+}
+
+// VariableValue represents a blueprint or context variable value
+// provided on the command line with --var, --var-file or --context-var.
+type VariableValue struct {
+	Name string `json:"name"`
+	// Kind is either "blueprint" or "context".
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+	// Source is either "flag" or "var file".
+	Source string `json:"source"`
+	// SourceName is the path of the var file the value was set in.
+	SourceName string `json:"sourceName,omitempty"`
+	Redacted   bool   `json:"redacted,omitempty"`
+}
+
+// ContextListOutput represents the engine contexts defined
+// in the CLI config file.
+type ContextListOutput struct {
+	Success bool `json:"success"`
+	// Current is the name of the selected context, if any.
+	Current  string          `json:"current,omitempty"`
+	Contexts []EngineContext `json:"contexts"`
+}
+
+// EngineContext represents a single named engine context.
+type EngineContext struct {
+	Name                 string `json:"name"`
+	Current              bool   `json:"current"`
+	EngineEndpoint       string `json:"engineEndpoint,omitempty"`
+	ConnectProtocol      string `json:"connectProtocol,omitempty"`
+	EngineAuthConfigFile string `json:"engineAuthConfigFile,omitempty"`
 }
@@ -0,0 +1,30 @@
+package jsonout
+
+import (
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+)
+
+// NewValidateOutput builds a ValidateOutput from the diagnostics collected
+// from a blueprint validation stream.
+// Suggested actions are resolved to concrete commands and links
+// in the same way as diagnostics attached to error outputs.
+func NewValidateOutput(blueprintFile string, diagnostics []*core.Diagnostic) ValidateOutput {
+	summary := ValidateSummary{}
+	for _, d := range diagnostics {
+		switch d.Level {
+		case core.DiagnosticLevelError:
+			summary.Errors += 1
+		case core.DiagnosticLevelWarning:
+			summary.Warnings += 1
+		case core.DiagnosticLevelInfo:
+			summary.Info += 1
+		}
+	}
+
+	return ValidateOutput{
+		Success:       summary.Errors == 0,
+		BlueprintFile: blueprintFile,
+		Diagnostics:   convertDiagnostics(diagnostics),
+		Summary:       summary,
+	}
+}
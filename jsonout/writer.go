@@ -11,3 +11,14 @@ func WriteJSON(w io.Writer, v any) error {
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(v)
 }
+
+// WriteJSONLine writes a value as compact JSON followed by a newline
+// to the writer, as used for each line of NDJSON output.
+func WriteJSONLine(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
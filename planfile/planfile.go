@@ -0,0 +1,221 @@
+// Package planfile provides the plan artifact that is written by the stage
+// command (stage --out) and consumed by the deploy command (deploy --plan).
+//
+// A plan captures the change set that was reviewed along with digests of the
+// inputs that were used to produce it, so a deployment can be refused when
+// the blueprint, the deploy configuration or the target instance have changed
+// since the changes were staged.
+package planfile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/changes"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/deploy-cli-sdk/consts"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/shared"
+)
+
+// FormatVersion is the version of the plan file format written by this package.
+const FormatVersion = 1
+
+const digestPrefix = "sha256:"
+
+// Plan is the plan artifact that binds a staged change set to the
+// inputs that were used to stage it.
+type Plan struct {
+	Version      int    `json:"version"`
+	CreatedAt    int64  `json:"createdAt"`
+	ChangesetID  string `json:"changesetId"`
+	InstanceID   string `json:"instanceId,omitempty"`
+	InstanceName string `json:"instanceName,omitempty"`
+	// InstanceLastDeployedTimestamp is the last deployed timestamp of the
+	// instance at the time the changes were staged, this is 0 for new deployments.
+	InstanceLastDeployedTimestamp int                       `json:"instanceLastDeployedTimestamp"`
+	BlueprintFile                 string                    `json:"blueprintFile"`
+	BlueprintDigest               string                    `json:"blueprintDigest"`
+	OperationConfigDigest         string                    `json:"operationConfigDigest"`
+	Changes                       *changes.BlueprintChanges `json:"changes"`
+	Summary                       jsonout.ChangeSummary     `json:"summary"`
+	// Digest is a digest of all the other fields of the plan,
+	// used to detect plan files that have been modified after they were written.
+	Digest string `json:"digest"`
+}
+
+// Input holds the information needed to create a plan.
+type Input struct {
+	// Stage is the result of the staging run, as produced for
+	// the JSON output of the stage command.
+	Stage jsonout.StageOutput
+	// InstanceState is the state of the instance at the time the changes
+	// were staged, this should be nil for new deployments.
+	InstanceState   *state.InstanceState
+	BlueprintFile   string
+	OperationConfig *types.BlueprintOperationConfig
+	CreatedAt       time.Time
+}
+
+// New creates a sealed plan from the result of a staging run.
+func New(input Input) (*Plan, error) {
+	if input.Stage.ChangesetID == "" || input.Stage.Changes == nil {
+		return nil, errors.New("staging did not produce a change set to write to a plan file")
+	}
+
+	plan := &Plan{
+		Version:      FormatVersion,
+		CreatedAt:    input.CreatedAt.Unix(),
+		ChangesetID:  input.Stage.ChangesetID,
+		InstanceID:   input.Stage.InstanceID,
+		InstanceName: input.Stage.InstanceName,
+		Changes:      input.Stage.Changes,
+		Summary:      input.Stage.Summary,
+	}
+	if input.InstanceState != nil {
+		plan.InstanceID = input.InstanceState.InstanceID
+		plan.InstanceLastDeployedTimestamp = input.InstanceState.LastDeployedTimestamp
+	}
+	if plan.InstanceID == "" && plan.InstanceName == "" {
+		return nil, errors.New("a plan file requires an instance name or instance ID")
+	}
+
+	blueprintDigest, err := BlueprintDigest(input.BlueprintFile)
+	if err != nil {
+		return nil, err
+	}
+	plan.BlueprintFile = input.BlueprintFile
+	plan.BlueprintDigest = blueprintDigest
+
+	configDigest, err := OperationConfigDigest(input.OperationConfig)
+	if err != nil {
+		return nil, err
+	}
+	plan.OperationConfigDigest = configDigest
+
+	plan.Digest, err = plan.computeDigest()
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// InstanceIdentifier returns the instance ID of the plan if present,
+// otherwise the instance name.
+func (p *Plan) InstanceIdentifier() string {
+	if p.InstanceID != "" {
+		return p.InstanceID
+	}
+	return p.InstanceName
+}
+
+// Write writes the plan to the given file path.
+func Write(path string, plan *Plan) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode plan file: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write plan file: %w", err)
+	}
+	return nil
+}
+
+// Read loads a plan from the given file path and checks that it has
+// not been modified since it was written.
+func Read(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan file: %w", err)
+	}
+
+	plan := &Plan{}
+	if err := json.Unmarshal(data, plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan file %q: %w", path, err)
+	}
+
+	if plan.Version != FormatVersion {
+		return nil, fmt.Errorf(
+			"unsupported plan file version %d, expected version %d",
+			plan.Version,
+			FormatVersion,
+		)
+	}
+
+	expectedDigest, err := plan.computeDigest()
+	if err != nil {
+		return nil, err
+	}
+	if plan.Digest != expectedDigest {
+		return nil, fmt.Errorf("plan file %q has been modified since it was written", path)
+	}
+
+	return plan, nil
+}
+
+// BlueprintDigest computes the digest of the contents of a local blueprint file.
+// Plan files can only be created for local blueprint files, as there is no
+// reliable way to detect changes to remote blueprint sources.
+func BlueprintDigest(blueprintFile string) (string, error) {
+	if shared.BlueprintSourceFromPath(blueprintFile) != consts.BlueprintSourceFile {
+		return "", fmt.Errorf(
+			"plan files are only supported for local blueprint files, %q is a remote blueprint source",
+			blueprintFile,
+		)
+	}
+
+	data, err := os.ReadFile(blueprintFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read blueprint file: %w", err)
+	}
+	return digest(data), nil
+}
+
+// OperationConfigDigest computes the digest of the operation config
+// (provider, transformer and context variable values) sent to the deploy engine.
+func OperationConfigDigest(operationConfig *types.BlueprintOperationConfig) (string, error) {
+	data, err := json.Marshal(operationConfig)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode deploy configuration: %w", err)
+	}
+	return digest(data), nil
+}
+
+func (p *Plan) computeDigest() (string, error) {
+	unsealed := *p
+	unsealed.Digest = ""
+	data, err := json.Marshal(&unsealed)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode plan file: %w", err)
+	}
+	return digest(data), nil
+}
+
+func changesDigest(blueprintChanges *changes.BlueprintChanges) (string, error) {
+	data, err := json.Marshal(blueprintChanges)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode changes: %w", err)
+	}
+	return digest(data), nil
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return digestPrefix + hex.EncodeToString(sum[:])
+}
+
+func shortDigest(value string) string {
+	trimmed := strings.TrimPrefix(value, digestPrefix)
+	if len(trimmed) > 12 {
+		return trimmed[:12]
+	}
+	return trimmed
+}
@@ -0,0 +1,304 @@
+package planfile
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint-state/manage"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/changes"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/provider"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	deerrors "github.com/newstack-cloud/bluelink/libs/deploy-engine-client/errors"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
+	"github.com/newstack-cloud/deploy-cli-sdk/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+const testBlueprint = `version: 2025-11-02
+resources:
+  ordersTable:
+    type: aws/dynamodb/table
+`
+
+type PlanFileSuite struct {
+	suite.Suite
+	dir           string
+	blueprintFile string
+}
+
+func TestPlanFileSuite(t *testing.T) {
+	suite.Run(t, new(PlanFileSuite))
+}
+
+func (s *PlanFileSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+	s.blueprintFile = filepath.Join(s.dir, "app.blueprint.yaml")
+	s.Require().NoError(os.WriteFile(s.blueprintFile, []byte(testBlueprint), 0o600))
+}
+
+func (s *PlanFileSuite) Test_New_requires_a_change_set() {
+	_, err := New(Input{
+		Stage:         jsonout.StageOutput{InstanceName: "my-app"},
+		BlueprintFile: s.blueprintFile,
+	})
+	s.Require().Error(err)
+	s.Contains(err.Error(), "did not produce a change set")
+}
+
+func (s *PlanFileSuite) Test_New_requires_an_instance_identifier() {
+	_, err := New(Input{
+		Stage: jsonout.StageOutput{
+			ChangesetID: "changeset-1",
+			Changes:     testChanges(),
+		},
+		BlueprintFile: s.blueprintFile,
+	})
+	s.Require().Error(err)
+	s.Contains(err.Error(), "requires an instance name or instance ID")
+}
+
+func (s *PlanFileSuite) Test_New_rejects_remote_blueprint_sources() {
+	_, err := New(Input{
+		Stage:         testStageOutput(),
+		BlueprintFile: "s3://bucket/app.blueprint.yaml",
+	})
+	s.Require().Error(err)
+	s.Contains(err.Error(), "only supported for local blueprint files")
+}
+
+func (s *PlanFileSuite) Test_New_uses_instance_state_for_instance_identity() {
+	plan := s.newPlan(testInstanceState())
+
+	s.Equal("instance-1", plan.InstanceID)
+	s.Equal("my-app", plan.InstanceName)
+	s.Equal(1700000000, plan.InstanceLastDeployedTimestamp)
+	s.Equal("instance-1", plan.InstanceIdentifier())
+}
+
+func (s *PlanFileSuite) Test_Write_and_Read_round_trip() {
+	plan := s.newPlan(testInstanceState())
+	planFile := filepath.Join(s.dir, "plan.json")
+
+	s.Require().NoError(Write(planFile, plan))
+	loaded, err := Read(planFile)
+	s.Require().NoError(err)
+
+	s.Equal(plan.ChangesetID, loaded.ChangesetID)
+	s.Equal(plan.BlueprintDigest, loaded.BlueprintDigest)
+	s.Equal(plan.OperationConfigDigest, loaded.OperationConfigDigest)
+	s.Equal(plan.Digest, loaded.Digest)
+}
+
+func (s *PlanFileSuite) Test_Read_rejects_modified_plan() {
+	plan := s.newPlan(testInstanceState())
+	plan.ChangesetID = "changeset-2"
+	planFile := filepath.Join(s.dir, "plan.json")
+	s.Require().NoError(Write(planFile, plan))
+
+	_, err := Read(planFile)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "has been modified since it was written")
+}
+
+func (s *PlanFileSuite) Test_Read_rejects_unsupported_version() {
+	planFile := filepath.Join(s.dir, "plan.json")
+	s.Require().NoError(os.WriteFile(planFile, []byte(`{"version": 99}`), 0o600))
+
+	_, err := Read(planFile)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "unsupported plan file version 99")
+}
+
+func (s *PlanFileSuite) Test_Verify_succeeds_for_unchanged_inputs() {
+	plan := s.readBack(s.newPlan(testInstanceState()))
+
+	err := plan.Verify(context.Background(), s.engine(testInstanceState()), s.verifyInput())
+	s.Require().NoError(err)
+}
+
+func (s *PlanFileSuite) Test_Verify_fails_when_blueprint_changed() {
+	plan := s.readBack(s.newPlan(testInstanceState()))
+	s.Require().NoError(os.WriteFile(s.blueprintFile, []byte(testBlueprint+"  # changed\n"), 0o600))
+
+	err := plan.Verify(context.Background(), s.engine(testInstanceState()), s.verifyInput())
+	s.Require().Error(err)
+	s.Contains(err.Error(), "has changed since the plan was created")
+}
+
+func (s *PlanFileSuite) Test_Verify_fails_when_deploy_config_changed() {
+	plan := s.readBack(s.newPlan(testInstanceState()))
+	input := s.verifyInput()
+	input.OperationConfig = &types.BlueprintOperationConfig{
+		ContextVariables: map[string]*core.ScalarValue{
+			"environment": core.ScalarFromString("staging"),
+		},
+	}
+
+	err := plan.Verify(context.Background(), s.engine(testInstanceState()), input)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "deploy configuration has changed")
+}
+
+func (s *PlanFileSuite) Test_Verify_fails_when_instance_redeployed() {
+	plan := s.readBack(s.newPlan(testInstanceState()))
+	redeployed := testInstanceState()
+	redeployed.LastDeployedTimestamp = 1700000500
+
+	err := plan.Verify(context.Background(), s.engine(redeployed), s.verifyInput())
+	s.Require().Error(err)
+	s.Contains(err.Error(), "instance \"instance-1\" has been deployed since the plan was created")
+}
+
+func (s *PlanFileSuite) Test_Verify_succeeds_for_new_instance_that_is_not_found() {
+	plan := s.readBack(s.newPlan(nil))
+	deployEngine := &instanceErrorDeployEngine{
+		DeployEngine: s.engine(nil),
+		err:          &deerrors.ClientError{StatusCode: http.StatusNotFound},
+	}
+
+	err := plan.Verify(context.Background(), deployEngine, s.verifyInput())
+	s.Require().NoError(err)
+}
+
+func (s *PlanFileSuite) Test_Verify_returns_errors_fetching_the_instance() {
+	plan := s.readBack(s.newPlan(testInstanceState()))
+	fetchErr := &deerrors.ClientError{StatusCode: http.StatusUnauthorized}
+	deployEngine := &instanceErrorDeployEngine{
+		DeployEngine: s.engine(testInstanceState()),
+		err:          fetchErr,
+	}
+
+	err := plan.Verify(context.Background(), deployEngine, s.verifyInput())
+	s.Require().Error(err)
+	s.Same(fetchErr, err)
+}
+
+func (s *PlanFileSuite) Test_Verify_fails_when_change_set_changes_differ() {
+	plan := s.readBack(s.newPlan(testInstanceState()))
+	deployEngine := testEngine(testInstanceState(), &testutils.StageScenario{
+		ChangesetID: "changeset-1",
+		Changes:     &changes.BlueprintChanges{RemovedResources: []string{"ordersTable"}},
+	})
+
+	err := plan.Verify(context.Background(), deployEngine, s.verifyInput())
+	s.Require().Error(err)
+	s.Contains(err.Error(), "do not match the changes in the plan")
+}
+
+func (s *PlanFileSuite) Test_Verify_fails_when_change_set_not_staged() {
+	plan := s.readBack(s.newPlan(testInstanceState()))
+	deployEngine := testEngine(testInstanceState(), &testutils.StageScenario{
+		ChangesetID: "changeset-1",
+		Changes:     testChanges(),
+		Status:      manage.ChangesetStatusFailed,
+	})
+
+	err := plan.Verify(context.Background(), deployEngine, s.verifyInput())
+	s.Require().Error(err)
+	s.Contains(err.Error(), "is not ready to be deployed")
+}
+
+func (s *PlanFileSuite) newPlan(instanceState *state.InstanceState) *Plan {
+	plan, err := New(Input{
+		Stage:           testStageOutput(),
+		InstanceState:   instanceState,
+		BlueprintFile:   s.blueprintFile,
+		OperationConfig: testOperationConfig(),
+		CreatedAt:       time.Unix(1700000100, 0),
+	})
+	s.Require().NoError(err)
+	return plan
+}
+
+// readBack writes the plan to disk and reads it again so verification
+// runs against a plan in the same form as one loaded by the deploy command.
+func (s *PlanFileSuite) readBack(plan *Plan) *Plan {
+	planFile := filepath.Join(s.dir, "plan.json")
+	s.Require().NoError(Write(planFile, plan))
+	loaded, err := Read(planFile)
+	s.Require().NoError(err)
+	return loaded
+}
+
+func (s *PlanFileSuite) verifyInput() VerifyInput {
+	return VerifyInput{
+		BlueprintFile:   s.blueprintFile,
+		OperationConfig: testOperationConfig(),
+	}
+}
+
+func (s *PlanFileSuite) engine(instanceState *state.InstanceState) engine.DeployEngine {
+	return testEngine(instanceState, &testutils.StageScenario{
+		ChangesetID: "changeset-1",
+		Changes:     testChanges(),
+	})
+}
+
+// testEngine creates a deploy engine for the instance-1 instance
+// with the provided change set stage.
+func testEngine(instanceState *state.InstanceState, stage *testutils.StageScenario) engine.DeployEngine {
+	builder := testutils.NewScenarioBuilder()
+	builder.Instance("my-app").
+		WithID("instance-1").
+		WithState(instanceState).
+		Stage(stage)
+	return builder.Build()
+}
+
+// instanceErrorDeployEngine returns the provided error
+// when fetching the instance state.
+type instanceErrorDeployEngine struct {
+	engine.DeployEngine
+	err error
+}
+
+func (e *instanceErrorDeployEngine) GetBlueprintInstance(
+	ctx context.Context,
+	instanceID string,
+) (*state.InstanceState, error) {
+	return nil, e.err
+}
+
+func testStageOutput() jsonout.StageOutput {
+	return jsonout.StageOutput{
+		Success:      true,
+		ChangesetID:  "changeset-1",
+		InstanceName: "my-app",
+		Changes:      testChanges(),
+	}
+}
+
+func testInstanceState() *state.InstanceState {
+	return &state.InstanceState{
+		InstanceID:            "instance-1",
+		InstanceName:          "my-app",
+		LastDeployedTimestamp: 1700000000,
+	}
+}
+
+func testOperationConfig() *types.BlueprintOperationConfig {
+	return &types.BlueprintOperationConfig{
+		ContextVariables: map[string]*core.ScalarValue{
+			"environment": core.ScalarFromString("production"),
+		},
+	}
+}
+
+func testChanges() *changes.BlueprintChanges {
+	return &changes.BlueprintChanges{
+		NewResources: map[string]provider.Changes{
+			"ordersTable": {
+				AppliedResourceInfo: provider.ResourceInfo{
+					ResourceName: "ordersTable",
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,159 @@
+package planfile
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint-state/manage"
+	deerrors "github.com/newstack-cloud/bluelink/libs/deploy-engine-client/errors"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+)
+
+// VerifyInput holds the current inputs of a deployment that are checked
+// against the inputs captured in a plan.
+type VerifyInput struct {
+	BlueprintFile   string
+	OperationConfig *types.BlueprintOperationConfig
+}
+
+// Verify checks that a plan is still valid for deployment.
+// This fails when the blueprint file or deploy configuration have changed
+// since the changes were staged, when the instance has been deployed since,
+// or when the change set in the deploy engine no longer matches the plan.
+func (p *Plan) Verify(
+	ctx context.Context,
+	deployEngine engine.DeployEngine,
+	input VerifyInput,
+) error {
+	if err := p.verifyBlueprint(input.BlueprintFile); err != nil {
+		return err
+	}
+
+	if err := p.verifyOperationConfig(input.OperationConfig); err != nil {
+		return err
+	}
+
+	if err := p.verifyInstance(ctx, deployEngine); err != nil {
+		return err
+	}
+
+	return p.verifyChangeset(ctx, deployEngine)
+}
+
+func (p *Plan) verifyBlueprint(blueprintFile string) error {
+	currentDigest, err := BlueprintDigest(blueprintFile)
+	if err != nil {
+		return err
+	}
+	if currentDigest != p.BlueprintDigest {
+		return fmt.Errorf(
+			"blueprint file %q has changed since the plan was created (expected %s, found %s), "+
+				"stage the changes again to create a new plan",
+			blueprintFile,
+			shortDigest(p.BlueprintDigest),
+			shortDigest(currentDigest),
+		)
+	}
+	return nil
+}
+
+func (p *Plan) verifyOperationConfig(operationConfig *types.BlueprintOperationConfig) error {
+	currentDigest, err := OperationConfigDigest(operationConfig)
+	if err != nil {
+		return err
+	}
+	if currentDigest != p.OperationConfigDigest {
+		return fmt.Errorf(
+			"deploy configuration has changed since the plan was created (expected %s, found %s), "+
+				"stage the changes again to create a new plan",
+			shortDigest(p.OperationConfigDigest),
+			shortDigest(currentDigest),
+		)
+	}
+	return nil
+}
+
+func (p *Plan) verifyInstance(ctx context.Context, deployEngine engine.DeployEngine) error {
+	lastDeployed, err := p.currentLastDeployedTimestamp(ctx, deployEngine)
+	if err != nil {
+		return err
+	}
+
+	if lastDeployed != p.InstanceLastDeployedTimestamp {
+		return fmt.Errorf(
+			"instance %q has been deployed since the plan was created, "+
+				"stage the changes again to create a new plan",
+			p.InstanceIdentifier(),
+		)
+	}
+	return nil
+}
+
+// currentLastDeployedTimestamp returns the timestamp of the last deployment
+// of the plan's instance, 0 is returned when the instance does not exist.
+// Only not found errors are treated as the instance not having been deployed,
+// so failures to reach the deploy engine are not mistaken for a new instance.
+func (p *Plan) currentLastDeployedTimestamp(
+	ctx context.Context,
+	deployEngine engine.DeployEngine,
+) (int, error) {
+	identifier := p.InstanceIdentifier()
+	if identifier == "" {
+		return 0, nil
+	}
+
+	instanceState, err := deployEngine.GetBlueprintInstance(ctx, identifier)
+	if err != nil {
+		if _, isNotFound := deerrors.IsNotFoundError(err); isNotFound {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	if instanceState == nil {
+		return 0, nil
+	}
+	return instanceState.LastDeployedTimestamp, nil
+}
+
+func (p *Plan) verifyChangeset(ctx context.Context, deployEngine engine.DeployEngine) error {
+	changeset, err := deployEngine.GetChangeset(ctx, p.ChangesetID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch change set %q for the plan: %w", p.ChangesetID, err)
+	}
+
+	if changeset.Status != manage.ChangesetStatusChangesStaged {
+		return fmt.Errorf(
+			"change set %q is not ready to be deployed (status: %s)",
+			p.ChangesetID,
+			changeset.Status,
+		)
+	}
+
+	if changeset.InstanceID != "" && p.InstanceID != "" && changeset.InstanceID != p.InstanceID {
+		return fmt.Errorf(
+			"change set %q was staged for instance %q, but the plan targets instance %q",
+			p.ChangesetID,
+			changeset.InstanceID,
+			p.InstanceID,
+		)
+	}
+
+	planChanges, err := changesDigest(p.Changes)
+	if err != nil {
+		return err
+	}
+	changesetChanges, err := changesDigest(changeset.Changes)
+	if err != nil {
+		return err
+	}
+	if planChanges != changesetChanges {
+		return fmt.Errorf(
+			"the changes in change set %q do not match the changes in the plan",
+			p.ChangesetID,
+		)
+	}
+
+	return nil
+}
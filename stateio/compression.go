@@ -0,0 +1,171 @@
+package stateio
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression is the format used to compress an export.
+type Compression string
+
+const (
+	// CompressionNone writes exports without compression.
+	CompressionNone Compression = "none"
+	// CompressionGzip compresses exports with gzip.
+	CompressionGzip Compression = "gzip"
+	// CompressionZstd compresses exports with Zstandard.
+	CompressionZstd Compression = "zstd"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// ParseCompression parses the name of a compression format,
+// an empty string is parsed as no compression.
+func ParseCompression(value string) (Compression, error) {
+	switch Compression(strings.ToLower(strings.TrimSpace(value))) {
+	case "", CompressionNone:
+		return CompressionNone, nil
+	case CompressionGzip, "gz":
+		return CompressionGzip, nil
+	case CompressionZstd, "zst":
+		return CompressionZstd, nil
+	default:
+		return "", fmt.Errorf(
+			"unsupported compression %q, only \"gzip\", \"zstd\" and \"none\" are supported",
+			value,
+		)
+	}
+}
+
+// CompressionFromPath determines the compression format of an export from
+// the extension of its local path or remote URL, ".gz" for gzip and ".zst"
+// for Zstandard, exports with any other extension are not compressed.
+func CompressionFromPath(filePath string) Compression {
+	if IsRemoteFile(filePath) {
+		if parsed, err := url.Parse(filePath); err == nil {
+			filePath = parsed.Path
+		}
+	}
+
+	switch strings.ToLower(path.Ext(filePath)) {
+	case ".gz":
+		return CompressionGzip
+	case ".zst", ".zstd":
+		return CompressionZstd
+	default:
+		return CompressionNone
+	}
+}
+
+// NewCompressWriter creates a writer that compresses everything written to it
+// before passing it on to w, Close must be called to flush the compressed
+// output, it does not close w.
+func NewCompressWriter(w io.Writer, compression Compression) (io.WriteCloser, error) {
+	switch compression {
+	case CompressionGzip:
+		return gzip.NewWriter(w), nil
+	case CompressionZstd:
+		encoder, err := zstd.NewWriter(w)
+		if err != nil {
+			return nil, &ExportError{
+				Code:    ErrCodeExportFailed,
+				Message: "failed to create zstd encoder",
+				Err:     err,
+			}
+		}
+		return encoder, nil
+	case CompressionNone, "":
+		return nopWriteCloser{w}, nil
+	default:
+		return nil, &ExportError{
+			Code:    ErrCodeExportFailed,
+			Message: fmt.Sprintf("unsupported compression %q", compression),
+		}
+	}
+}
+
+// NewDecompressReader returns a reader for the decompressed contents of r when
+// r starts with the magic bytes of gzip or Zstandard, otherwise the contents
+// of r are returned unchanged.
+// The returned reader must be closed to release the resources of the decompressor,
+// closing it does not close r.
+func NewDecompressReader(r io.Reader) (io.ReadCloser, error) {
+	reader := bufio.NewReader(r)
+	start, err := reader.Peek(len(zstdMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(start, gzipMagic):
+		decompressor, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, createDecompressError(err)
+		}
+		return &decompressReader{r: decompressor, close: decompressor.Close}, nil
+	case bytes.HasPrefix(start, zstdMagic):
+		decoder, err := zstd.NewReader(reader)
+		if err != nil {
+			return nil, createDecompressError(err)
+		}
+		return &decompressReader{r: decoder, close: func() error {
+			decoder.Close()
+			return nil
+		}}, nil
+	default:
+		return io.NopCloser(reader), nil
+	}
+}
+
+// decompressReader reports errors reading the compressed input as import errors
+// so corrupt exports can be told apart from malformed JSON.
+type decompressReader struct {
+	r     io.Reader
+	close func() error
+}
+
+func (d *decompressReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	if err != nil && err != io.EOF {
+		if _, isImportErr := err.(*ImportError); !isImportErr {
+			err = createDecompressError(err)
+		}
+	}
+	return n, err
+}
+
+func (d *decompressReader) Close() error {
+	return d.close()
+}
+
+func createDecompressError(err error) error {
+	return &ImportError{
+		Code:    ErrCodeDecompressionFailed,
+		Message: "failed to decompress export",
+		Err:     err,
+	}
+}
+
+func decompressData(data []byte) ([]byte, error) {
+	reader, err := NewDecompressReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	if _, isCompressed := reader.(*decompressReader); !isCompressed {
+		return data, nil
+	}
+	return io.ReadAll(reader)
+}
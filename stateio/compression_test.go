@@ -0,0 +1,107 @@
+package stateio
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CompressionTestSuite struct {
+	suite.Suite
+}
+
+func (s *CompressionTestSuite) Test_determines_compression_from_file_extension() {
+	s.Equal(CompressionGzip, CompressionFromPath("./backup/state.json.gz"))
+	s.Equal(CompressionZstd, CompressionFromPath("./backup/state.json.zst"))
+	s.Equal(CompressionZstd, CompressionFromPath("s3://my-bucket/state.json.zst"))
+	s.Equal(CompressionNone, CompressionFromPath("gcs://my-bucket/state.json"))
+	s.Equal(CompressionNone, CompressionFromPath("./backup/state.json.enc"))
+}
+
+func (s *CompressionTestSuite) Test_parses_compression_names() {
+	for value, expected := range map[string]Compression{
+		"":     CompressionNone,
+		"none": CompressionNone,
+		"gzip": CompressionGzip,
+		"GZ":   CompressionGzip,
+		"zstd": CompressionZstd,
+	} {
+		compression, err := ParseCompression(value)
+		s.Require().NoError(err)
+		s.Equal(expected, compression)
+	}
+
+	_, err := ParseCompression("brotli")
+	s.Error(err)
+}
+
+func (s *CompressionTestSuite) Test_decompresses_data_in_any_supported_format() {
+	data := bytes.Repeat([]byte(`{"id":"inst-001"},`), 1000)
+
+	for _, compression := range []Compression{CompressionGzip, CompressionZstd, CompressionNone} {
+		compressed := s.compress(data, compression)
+		if compression != CompressionNone {
+			s.Less(len(compressed), len(data))
+		}
+
+		reader, err := NewDecompressReader(bytes.NewReader(compressed))
+		s.Require().NoError(err)
+		decompressed, err := io.ReadAll(reader)
+		s.Require().NoError(err)
+		s.Require().NoError(reader.Close())
+		s.Equal(data, decompressed, string(compression))
+	}
+}
+
+func (s *CompressionTestSuite) Test_reports_corrupt_compressed_data() {
+	compressed := s.compress(bytes.Repeat([]byte("instances"), 1000), CompressionGzip)
+	truncated := compressed[:len(compressed)/2]
+
+	reader, err := NewDecompressReader(bytes.NewReader(truncated))
+	s.Require().NoError(err)
+	_, err = io.ReadAll(reader)
+
+	var importErr *ImportError
+	s.Require().ErrorAs(err, &importErr)
+	s.Equal(ErrCodeDecompressionFailed, importErr.Code)
+}
+
+func (s *CompressionTestSuite) Test_imports_compressed_instances() {
+	data := []byte(`[{"id":"inst-001","name":"Instance One"},{"id":"inst-002","name":"Instance Two"}]`)
+
+	for _, compression := range []Compression{CompressionGzip, CompressionZstd} {
+		importer := &batchRecordingImporter{}
+		result, err := ExecuteInstancesImport(context.Background(), importer, s.compress(data, compression))
+		s.Require().NoError(err)
+		s.Equal(2, result.InstancesCount)
+
+		importer = &batchRecordingImporter{}
+		result, err = ExecuteInstancesImportStream(
+			context.Background(),
+			importer,
+			bytes.NewReader(s.compress(data, compression)),
+			0,
+		)
+		s.Require().NoError(err)
+		s.Equal(2, result.InstancesCount)
+		s.Equal([][]string{{"inst-001", "inst-002"}}, importer.batches)
+	}
+}
+
+func (s *CompressionTestSuite) compress(data []byte, compression Compression) []byte {
+	compressed := &bytes.Buffer{}
+	writer, err := NewCompressWriter(compressed, compression)
+	s.Require().NoError(err)
+
+	_, err = writer.Write(data)
+	s.Require().NoError(err)
+	s.Require().NoError(writer.Close())
+	return compressed.Bytes()
+}
+
+func TestCompressionTestSuite(t *testing.T) {
+	suite.Run(t, new(CompressionTestSuite))
+}
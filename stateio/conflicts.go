@@ -0,0 +1,409 @@
+package stateio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+)
+
+// ConflictStrategy determines how instances in an import that already exist
+// in the state with a different state are handled.
+type ConflictStrategy string
+
+const (
+	// ConflictFail fails the import when an instance already exists
+	// with a different state, all instances are compared with the state
+	// before any instances are saved.
+	ConflictFail ConflictStrategy = "fail"
+	// ConflictSkip keeps the existing state of instances that already exist.
+	ConflictSkip ConflictStrategy = "skip"
+	// ConflictOverwrite replaces the existing state of instances that already exist.
+	ConflictOverwrite ConflictStrategy = "overwrite"
+)
+
+// ParseConflictStrategy parses the name of a conflict strategy.
+func ParseConflictStrategy(value string) (ConflictStrategy, error) {
+	switch strategy := ConflictStrategy(strings.ToLower(strings.TrimSpace(value))); strategy {
+	case ConflictFail, ConflictSkip, ConflictOverwrite:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf(
+			"unsupported conflict strategy %q, only \"fail\", \"skip\" and \"overwrite\" are supported",
+			value,
+		)
+	}
+}
+
+// InstanceImportStatus describes how an imported instance compares
+// to the instance with the same ID in the state.
+type InstanceImportStatus string
+
+const (
+	// InstanceImportNew is used for instances that do not exist in the state.
+	InstanceImportNew InstanceImportStatus = "new"
+	// InstanceImportIdentical is used for instances that exist in the state
+	// with the same state as the import.
+	InstanceImportIdentical InstanceImportStatus = "identical"
+	// InstanceImportDiffers is used for instances that exist in the state
+	// with a different state to the import.
+	InstanceImportDiffers InstanceImportStatus = "differs"
+)
+
+// InstanceImportAction is what an import does, or would do for a dry run,
+// with an instance.
+type InstanceImportAction string
+
+const (
+	// InstanceImportCreate saves an instance that does not exist in the state.
+	InstanceImportCreate InstanceImportAction = "create"
+	// InstanceImportOverwrite replaces the state of an existing instance.
+	InstanceImportOverwrite InstanceImportAction = "overwrite"
+	// InstanceImportSkip keeps the state of an existing instance.
+	InstanceImportSkip InstanceImportAction = "skip"
+	// InstanceImportUnchanged is used for identical instances
+	// that do not need to be saved.
+	InstanceImportUnchanged InstanceImportAction = "unchanged"
+	// InstanceImportConflict is used for instances that fail the import
+	// with the ConflictFail strategy.
+	InstanceImportConflict InstanceImportAction = "conflict"
+)
+
+// InstanceImportResult holds the outcome of importing a single instance.
+type InstanceImportResult struct {
+	InstanceID   string               `json:"instanceId"`
+	InstanceName string               `json:"instanceName,omitempty"`
+	Status       InstanceImportStatus `json:"status"`
+	Action       InstanceImportAction `json:"action"`
+	// Differences summarises how the imported instance differs from
+	// the instance in the state, only set when Status is InstanceImportDiffers.
+	Differences *InstanceDifferences `json:"differences,omitempty"`
+}
+
+// InstanceDifferences summarises the differences between an imported
+// instance and the instance with the same ID in the state.
+type InstanceDifferences struct {
+	Resources ElementDifferences `json:"resources"`
+	Links     ElementDifferences `json:"links"`
+	Children  ElementDifferences `json:"children"`
+	// Fields holds the names of any other top-level fields of the
+	// instance that differ (e.g. "status" or "exports").
+	Fields []string `json:"fields,omitempty"`
+}
+
+// ElementDifferences holds the names of the resources, links or child blueprints
+// that are only in the import (added), only in the state (removed)
+// or in both with a different state (changed).
+type ElementDifferences struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	Changed []string `json:"changed,omitempty"`
+}
+
+// IsEmpty returns true when there are no differences.
+func (d ElementDifferences) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// ImportSummary holds the number of instances for each action taken by an import.
+type ImportSummary struct {
+	Created     int `json:"created"`
+	Overwritten int `json:"overwritten"`
+	Skipped     int `json:"skipped"`
+	Unchanged   int `json:"unchanged"`
+	Conflicts   int `json:"conflicts,omitempty"`
+}
+
+// ExistingInstancesGetter is implemented by importers that can look up the
+// instances that already exist in the state, which is required to check
+// for conflicts and for dry runs.
+type ExistingInstancesGetter interface {
+	// GetExistingInstances returns the instances with the given IDs
+	// that exist in the state, keyed by instance ID.
+	GetExistingInstances(ctx context.Context, instanceIDs []string) (map[string]state.InstanceState, error)
+}
+
+// GetExistingInstances returns the instances with the given IDs
+// that exist in the state, keyed by instance ID.
+func (i *ContainerStateImporter) GetExistingInstances(
+	ctx context.Context,
+	instanceIDs []string,
+) (map[string]state.InstanceState, error) {
+	instances, err := i.container.Instances().GetBatch(ctx, instanceIDs)
+	var notFoundErr *state.InstancesNotFoundError
+	if errors.As(err, &notFoundErr) {
+		// Only the instances that exist are needed.
+		instanceIDs = slices.DeleteFunc(slices.Clone(instanceIDs), func(id string) bool {
+			return slices.Contains(notFoundErr.MissingIDsOrNames, id)
+		})
+		if len(instanceIDs) == 0 {
+			return map[string]state.InstanceState{}, nil
+		}
+		instances, err = i.container.Instances().GetBatch(ctx, instanceIDs)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]state.InstanceState, len(instances))
+	for _, instance := range instances {
+		existing[instance.InstanceID] = instance
+	}
+	return existing, nil
+}
+
+// conflictCheckingImporter compares each batch of instances with the
+// instances in the state before importing them, applying the conflict strategy
+// and recording the outcome for each instance.
+// Nothing is imported for dry runs.
+type conflictCheckingImporter struct {
+	importer StateImporter
+	existing ExistingInstancesGetter
+	strategy ConflictStrategy
+	dryRun   bool
+	results  []InstanceImportResult
+	summary  ImportSummary
+}
+
+func newConflictCheckingImporter(
+	importer StateImporter,
+	strategy ConflictStrategy,
+	dryRun bool,
+) (*conflictCheckingImporter, error) {
+	existing, canCheck := importer.(ExistingInstancesGetter)
+	if !canCheck {
+		return nil, errors.New(
+			"conflict checks and dry runs require an importer that can look up existing instances",
+		)
+	}
+
+	if strategy == "" {
+		strategy = ConflictOverwrite
+	}
+
+	return &conflictCheckingImporter{
+		importer: importer,
+		existing: existing,
+		strategy: strategy,
+		dryRun:   dryRun,
+		results:  []InstanceImportResult{},
+	}, nil
+}
+
+func (c *conflictCheckingImporter) ImportInstances(ctx context.Context, instances []state.InstanceState) error {
+	instanceIDs := make([]string, len(instances))
+	for i, instance := range instances {
+		instanceIDs[i] = instance.InstanceID
+	}
+
+	existing, err := c.existing.GetExistingInstances(ctx, instanceIDs)
+	if err != nil {
+		return fmt.Errorf("failed to look up existing instances: %w", err)
+	}
+
+	toSave := []state.InstanceState{}
+	conflicts := []string{}
+	for _, instance := range instances {
+		result := c.compare(instance, existing)
+		switch result.Action {
+		case InstanceImportCreate, InstanceImportOverwrite:
+			toSave = append(toSave, instance)
+		case InstanceImportConflict:
+			conflicts = append(conflicts, instance.InstanceID)
+		}
+		c.record(result)
+	}
+
+	if len(conflicts) > 0 && !c.dryRun {
+		return createConflictError(conflicts)
+	}
+
+	if c.dryRun || len(toSave) == 0 {
+		return nil
+	}
+	return c.importer.ImportInstances(ctx, toSave)
+}
+
+func createConflictError(conflicts []string) error {
+	return &ImportError{
+		Code: ErrCodeInstanceConflict,
+		Message: fmt.Sprintf(
+			"instances already exist with a different state: %s, "+
+				"run a dry run of the import to see the differences",
+			strings.Join(conflicts, ", "),
+		),
+	}
+}
+
+func (c *conflictCheckingImporter) compare(
+	instance state.InstanceState,
+	existing map[string]state.InstanceState,
+) InstanceImportResult {
+	result := InstanceImportResult{
+		InstanceID:   instance.InstanceID,
+		InstanceName: instance.InstanceName,
+	}
+
+	existingInstance, exists := existing[instance.InstanceID]
+	if !exists {
+		result.Status = InstanceImportNew
+		result.Action = InstanceImportCreate
+		return result
+	}
+
+	differences := CompareInstances(existingInstance, instance)
+	if differences == nil {
+		result.Status = InstanceImportIdentical
+		result.Action = InstanceImportUnchanged
+		return result
+	}
+
+	result.Status = InstanceImportDiffers
+	result.Differences = differences
+	switch c.strategy {
+	case ConflictSkip:
+		result.Action = InstanceImportSkip
+	case ConflictFail:
+		result.Action = InstanceImportConflict
+	default:
+		result.Action = InstanceImportOverwrite
+	}
+	return result
+}
+
+func (c *conflictCheckingImporter) record(result InstanceImportResult) {
+	c.results = append(c.results, result)
+	switch result.Action {
+	case InstanceImportCreate:
+		c.summary.Created++
+	case InstanceImportOverwrite:
+		c.summary.Overwritten++
+	case InstanceImportSkip:
+		c.summary.Skipped++
+	case InstanceImportUnchanged:
+		c.summary.Unchanged++
+	case InstanceImportConflict:
+		c.summary.Conflicts++
+	}
+}
+
+// CompareInstances summarises the differences between the state of an instance
+// and the state it would be replaced with, returning nil when they are identical.
+func CompareInstances(existing, imported state.InstanceState) *InstanceDifferences {
+	differences := &InstanceDifferences{
+		Resources: compareElements(
+			resourcesByName(existing.Resources),
+			resourcesByName(imported.Resources),
+		),
+		Links:    compareElements(existing.Links, imported.Links),
+		Children: compareElements(existing.ChildBlueprints, imported.ChildBlueprints),
+		Fields:   compareInstanceFields(existing, imported),
+	}
+
+	if differences.Resources.IsEmpty() &&
+		differences.Links.IsEmpty() &&
+		differences.Children.IsEmpty() &&
+		len(differences.Fields) == 0 {
+		return nil
+	}
+	return differences
+}
+
+func resourcesByName(resources map[string]*state.ResourceState) map[string]*state.ResourceState {
+	byName := make(map[string]*state.ResourceState, len(resources))
+	for resourceID, resource := range resources {
+		name := resourceID
+		if resource != nil && resource.Name != "" {
+			name = resource.Name
+		}
+		byName[name] = resource
+	}
+	return byName
+}
+
+func compareElements[Element any](existing, imported map[string]Element) ElementDifferences {
+	differences := ElementDifferences{}
+	for name, importedElement := range imported {
+		existingElement, exists := existing[name]
+		if !exists {
+			differences.Added = append(differences.Added, name)
+		} else if !jsonEqual(existingElement, importedElement) {
+			differences.Changed = append(differences.Changed, name)
+		}
+	}
+
+	for name := range existing {
+		if _, exists := imported[name]; !exists {
+			differences.Removed = append(differences.Removed, name)
+		}
+	}
+
+	slices.Sort(differences.Added)
+	slices.Sort(differences.Removed)
+	slices.Sort(differences.Changed)
+	return differences
+}
+
+// elementFields are the fields of an instance that are
+// compared element by element.
+var elementFields = []string{"resources", "resourceIds", "links", "childBlueprints"}
+
+func compareInstanceFields(existing, imported state.InstanceState) []string {
+	existingFields := instanceFields(existing)
+	importedFields := instanceFields(imported)
+
+	fields := []string{}
+	for name, importedValue := range importedFields {
+		if !rawJSONEqual(existingFields[name], importedValue) {
+			fields = append(fields, name)
+		}
+	}
+	for name, existingValue := range existingFields {
+		if _, exists := importedFields[name]; !exists && !isEmptyJSON(existingValue) {
+			fields = append(fields, name)
+		}
+	}
+
+	slices.Sort(fields)
+	return fields
+}
+
+func instanceFields(instance state.InstanceState) map[string]json.RawMessage {
+	data, _ := json.Marshal(instance)
+	fields := map[string]json.RawMessage{}
+	json.Unmarshal(data, &fields)
+	for _, name := range elementFields {
+		delete(fields, name)
+	}
+	return fields
+}
+
+func jsonEqual(a, b any) bool {
+	aData, aErr := json.Marshal(a)
+	bData, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && rawJSONEqual(aData, bData)
+}
+
+// rawJSONEqual compares JSON values, treating null and empty
+// objects or arrays as equal as state backends do not
+// consistently preserve the difference.
+func rawJSONEqual(a, b json.RawMessage) bool {
+	if isEmptyJSON(a) && isEmptyJSON(b) {
+		return true
+	}
+	return bytes.Equal(a, b)
+}
+
+func isEmptyJSON(value json.RawMessage) bool {
+	switch string(bytes.TrimSpace(value)) {
+	case "", "null", "{}", "[]":
+		return true
+	default:
+		return false
+	}
+}
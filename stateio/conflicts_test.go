@@ -0,0 +1,210 @@
+package stateio
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint-state/memfile"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+type ConflictsTestSuite struct {
+	suite.Suite
+	fs           afero.Fs
+	engineConfig *EngineConfig
+}
+
+func (s *ConflictsTestSuite) SetupTest() {
+	s.fs = afero.NewMemMapFs()
+	s.Require().NoError(s.fs.MkdirAll("/test/state", 0755))
+	s.engineConfig = &EngineConfig{
+		State: StateConfig{
+			StorageEngine:   StorageEngineMemfile,
+			MemFileStateDir: "/test/state",
+		},
+	}
+
+	_, err := s.importInstances(ImportParams{}, existingInstances()...)
+	s.Require().NoError(err)
+}
+
+func (s *ConflictsTestSuite) Test_dry_run_reports_differences_without_saving() {
+	result, err := s.importInstances(ImportParams{DryRun: true}, importedInstances()...)
+
+	s.Require().NoError(err)
+	s.True(result.DryRun)
+	s.Equal(3, result.InstancesCount)
+	s.Equal(&ImportSummary{Created: 1, Overwritten: 1, Unchanged: 1}, result.Summary)
+	s.Equal([]InstanceImportResult{
+		{
+			InstanceID:   "inst-001",
+			InstanceName: "orders",
+			Status:       InstanceImportDiffers,
+			Action:       InstanceImportOverwrite,
+			Differences: &InstanceDifferences{
+				Resources: ElementDifferences{
+					Added:   []string{"queue"},
+					Removed: []string{"bucket"},
+					Changed: []string{"table"},
+				},
+				Children: ElementDifferences{Added: []string{"networking"}},
+				Fields:   []string{"status"},
+			},
+		},
+		{InstanceID: "inst-002", InstanceName: "payments", Status: InstanceImportIdentical, Action: InstanceImportUnchanged},
+		{InstanceID: "inst-003", InstanceName: "search", Status: InstanceImportNew, Action: InstanceImportCreate},
+	}, result.Instances)
+
+	orders := s.loadInstance("inst-001")
+	s.Equal(core.InstanceStatusDeployed, orders.Status)
+	s.Contains(orders.Resources, "res-bucket")
+	_, err = s.loadContainer().Instances().Get(context.Background(), "inst-003")
+	s.True(state.IsInstanceNotFound(err))
+}
+
+func (s *ConflictsTestSuite) Test_skips_existing_instances() {
+	result, err := s.importInstances(ImportParams{OnConflict: ConflictSkip}, importedInstances()...)
+
+	s.Require().NoError(err)
+	s.Equal(1, result.InstancesCount)
+	s.Equal(&ImportSummary{Created: 1, Skipped: 1, Unchanged: 1}, result.Summary)
+	s.Equal(core.InstanceStatusDeployed, s.loadInstance("inst-001").Status)
+	s.Equal("search", s.loadInstance("inst-003").InstanceName)
+}
+
+func (s *ConflictsTestSuite) Test_overwrites_existing_instances() {
+	result, err := s.importInstances(ImportParams{OnConflict: ConflictOverwrite}, importedInstances()...)
+
+	s.Require().NoError(err)
+	s.Equal(2, result.InstancesCount)
+	s.Equal(&ImportSummary{Created: 1, Overwritten: 1, Unchanged: 1}, result.Summary)
+	s.Equal(core.InstanceStatusUpdateFailed, s.loadInstance("inst-001").Status)
+}
+
+func (s *ConflictsTestSuite) Test_fails_on_conflicting_instances() {
+	_, err := s.importInstances(ImportParams{OnConflict: ConflictFail}, importedInstances()...)
+
+	var importErr *ImportError
+	s.Require().ErrorAs(err, &importErr)
+	s.Equal(ErrCodeInstanceConflict, importErr.Code)
+	s.Contains(importErr.Message, "inst-001")
+	s.Equal(core.InstanceStatusDeployed, s.loadInstance("inst-001").Status)
+	_, err = s.loadContainer().Instances().Get(context.Background(), "inst-003")
+	s.True(state.IsInstanceNotFound(err))
+}
+
+func (s *ConflictsTestSuite) Test_fails_before_saving_any_instances() {
+	imported := importedInstances()
+	// The new instance is in the first batch and the conflict in the last.
+	slices.Reverse(imported)
+
+	_, err := s.importInstances(ImportParams{OnConflict: ConflictFail, BatchSize: 1}, imported...)
+
+	var importErr *ImportError
+	s.Require().ErrorAs(err, &importErr)
+	s.Equal(ErrCodeInstanceConflict, importErr.Code)
+	s.Contains(importErr.Message, "inst-001")
+	_, err = s.loadContainer().Instances().Get(context.Background(), "inst-003")
+	s.True(state.IsInstanceNotFound(err))
+}
+
+func (s *ConflictsTestSuite) Test_fail_strategy_saves_instances_without_conflicts() {
+	imported := importedInstances()[1:]
+
+	result, err := s.importInstances(ImportParams{OnConflict: ConflictFail, BatchSize: 1}, imported...)
+
+	s.Require().NoError(err)
+	s.Equal(&ImportSummary{Created: 1, Unchanged: 1}, result.Summary)
+	s.Equal("search", s.loadInstance("inst-003").InstanceName)
+}
+
+func (s *ConflictsTestSuite) Test_dry_run_reports_conflicts_for_the_fail_strategy() {
+	result, err := s.importInstances(
+		ImportParams{OnConflict: ConflictFail, DryRun: true},
+		importedInstances()...,
+	)
+
+	s.Require().NoError(err)
+	s.Equal(&ImportSummary{Created: 1, Unchanged: 1, Conflicts: 1}, result.Summary)
+	s.Equal(InstanceImportConflict, result.Instances[0].Action)
+}
+
+func (s *ConflictsTestSuite) Test_parses_conflict_strategies() {
+	strategy, err := ParseConflictStrategy("Skip")
+	s.Require().NoError(err)
+	s.Equal(ConflictSkip, strategy)
+
+	_, err = ParseConflictStrategy("merge")
+	s.Error(err)
+}
+
+func (s *ConflictsTestSuite) importInstances(
+	params ImportParams,
+	instances ...state.InstanceState,
+) (*ImportResult, error) {
+	data, err := json.Marshal(instances)
+	s.Require().NoError(err)
+
+	params.EngineConfig = s.engineConfig
+	params.FileSystem = s.fs
+	params.FileData = data
+	params.Logger = core.NewNopLogger()
+	return Import(params)
+}
+
+func (s *ConflictsTestSuite) loadContainer() state.Container {
+	container, err := memfile.LoadStateContainer("/test/state", s.fs, core.NewNopLogger())
+	s.Require().NoError(err)
+	return container
+}
+
+func (s *ConflictsTestSuite) loadInstance(instanceID string) state.InstanceState {
+	instance, err := s.loadContainer().Instances().Get(context.Background(), instanceID)
+	s.Require().NoError(err)
+	return instance
+}
+
+func existingInstances() []state.InstanceState {
+	return []state.InstanceState{
+		{
+			InstanceID:   "inst-001",
+			InstanceName: "orders",
+			Status:       core.InstanceStatusDeployed,
+			ResourceIDs:  map[string]string{"table": "res-table", "bucket": "res-bucket"},
+			Resources: map[string]*state.ResourceState{
+				"res-table":  {ResourceID: "res-table", Name: "table", Type: "aws/dynamodb/table"},
+				"res-bucket": {ResourceID: "res-bucket", Name: "bucket", Type: "aws/s3/bucket"},
+			},
+		},
+		{InstanceID: "inst-002", InstanceName: "payments", Status: core.InstanceStatusDeployed},
+	}
+}
+
+func importedInstances() []state.InstanceState {
+	return []state.InstanceState{
+		{
+			InstanceID:   "inst-001",
+			InstanceName: "orders",
+			Status:       core.InstanceStatusUpdateFailed,
+			ResourceIDs:  map[string]string{"table": "res-table", "queue": "res-queue"},
+			Resources: map[string]*state.ResourceState{
+				"res-table": {ResourceID: "res-table", Name: "table", Type: "aws/dynamodb/globalTable"},
+				"res-queue": {ResourceID: "res-queue", Name: "queue", Type: "aws/sqs/queue"},
+			},
+			ChildBlueprints: map[string]*state.InstanceState{
+				"networking": {InstanceID: "inst-001-networking", InstanceName: "networking"},
+			},
+		},
+		{InstanceID: "inst-002", InstanceName: "payments", Status: core.InstanceStatusDeployed},
+		{InstanceID: "inst-003", InstanceName: "search", Status: core.InstanceStatusDeployed},
+	}
+}
+
+func TestConflictsTestSuite(t *testing.T) {
+	suite.Run(t, new(ConflictsTestSuite))
+}
@@ -0,0 +1,764 @@
+package stateio
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Encrypted exports wrap the serialized export in a format modelled on age
+// (https://age-encryption.org/v1), so encryption works the same way for every
+// storage backend and remote storage location:
+//
+//	deploy-cli-sdk/encrypted-state/v1
+//	-> scrypt <salt> <work factor>
+//	<file key wrapped with the key derived from the passphrase>
+//	--- <MAC of the header>
+//	<payload nonce><payload>
+//
+// Exports encrypted for recipients have an "-> X25519 <ephemeral public key>"
+// stanza per recipient instead of the scrypt stanza.
+// The payload is split into 64KiB chunks that are each sealed with AES-256-GCM
+// using a key derived from the random file key, the nonce of each chunk
+// holds its position and whether it is the last chunk so reordered or
+// truncated payloads are detected.
+const encryptionMagic = "deploy-cli-sdk/encrypted-state/v1"
+
+const (
+	encryptionChunkSize   = 64 * 1024
+	encryptionFileKeySize = 16
+	encryptionNonceSize   = 16
+	// maxScryptWorkFactor limits the work factor accepted from the header of an
+	// encrypted export so a crafted export can not exhaust memory on import.
+	maxScryptWorkFactor = 22
+	maxHeaderStanzas    = 64
+
+	scryptStanzaType = "scrypt"
+	x25519StanzaType = "X25519"
+
+	x25519RecipientPrefix = "x25519:"
+	x25519IdentityPrefix  = "x25519-secret:"
+)
+
+// scryptWorkFactor is the base 2 logarithm of the scrypt cost parameter
+// used to derive keys from passphrases.
+var scryptWorkFactor = 18
+
+// EncryptionOptions configures the encryption of an export.
+// An export is either encrypted with a passphrase or for one or more recipients.
+type EncryptionOptions struct {
+	// Passphrase is used to derive the key that protects the export.
+	Passphrase []byte
+	// Recipients are X25519 public keys in the form "x25519:<key>",
+	// the export can be decrypted with the identity of any recipient,
+	// see GenerateX25519Identity.
+	Recipients []string
+}
+
+// DecryptionOptions holds the secrets used to decrypt encrypted exports on import.
+type DecryptionOptions struct {
+	// Passphrase is used for exports encrypted with a passphrase.
+	Passphrase []byte
+	// Identities are X25519 private keys in the form "x25519-secret:<key>"
+	// used for exports encrypted for recipients.
+	Identities []string
+}
+
+// EncryptionInfo describes how an export is encrypted.
+type EncryptionInfo struct {
+	// Encrypted is true when the export is encrypted.
+	Encrypted bool
+	// Passphrase is true when a passphrase is needed to decrypt the export.
+	Passphrase bool
+	// Recipients is the number of recipients the export is encrypted for.
+	Recipients int
+}
+
+// InspectEncryption reads the start of an export to determine whether and how
+// it is encrypted, allowing a passphrase to only be asked for when needed.
+func InspectEncryption(r io.Reader) (*EncryptionInfo, error) {
+	reader := bufio.NewReader(r)
+	encrypted, err := isEncrypted(reader)
+	if err != nil || !encrypted {
+		return &EncryptionInfo{}, err
+	}
+
+	header, err := readEncryptionHeader(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &EncryptionInfo{Encrypted: true}
+	for _, stanza := range header.stanzas {
+		switch stanza.stanzaType {
+		case scryptStanzaType:
+			info.Passphrase = true
+		case x25519StanzaType:
+			info.Recipients++
+		}
+	}
+	return info, nil
+}
+
+// NewEncryptWriter creates a writer that encrypts everything written to it before
+// passing it on to w, Close must be called to write the final chunk of the export,
+// it does not close w.
+func NewEncryptWriter(w io.Writer, opts *EncryptionOptions) (io.WriteCloser, error) {
+	fileKey := make([]byte, encryptionFileKeySize)
+	rand.Read(fileKey)
+
+	stanzas, err := createStanzas(fileKey, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	header := &encryptionHeader{stanzas: stanzas}
+	headerData, err := header.marshal(fileKey)
+	if err != nil {
+		return nil, createEncryptionError(err)
+	}
+
+	nonce := make([]byte, encryptionNonceSize)
+	rand.Read(nonce)
+	aead, err := payloadAEAD(fileKey, nonce)
+	if err != nil {
+		return nil, createEncryptionError(err)
+	}
+
+	if _, err := w.Write(append(headerData, nonce...)); err != nil {
+		return nil, err
+	}
+
+	return &encryptWriter{
+		w:    w,
+		aead: aead,
+		buf:  make([]byte, 0, encryptionChunkSize),
+	}, nil
+}
+
+func createStanzas(fileKey []byte, opts *EncryptionOptions) ([]*stanza, error) {
+	if opts == nil || (len(opts.Passphrase) == 0 && len(opts.Recipients) == 0) {
+		return nil, &ExportError{
+			Code:    ErrCodeEncryptionFailed,
+			Message: "a passphrase or at least one recipient is required to encrypt an export",
+		}
+	}
+
+	if len(opts.Passphrase) > 0 && len(opts.Recipients) > 0 {
+		return nil, &ExportError{
+			Code:    ErrCodeEncryptionFailed,
+			Message: "an export can be encrypted with a passphrase or for recipients but not both",
+		}
+	}
+
+	if len(opts.Passphrase) > 0 {
+		scryptStanza, err := wrapWithPassphrase(fileKey, opts.Passphrase)
+		if err != nil {
+			return nil, createEncryptionError(err)
+		}
+		return []*stanza{scryptStanza}, nil
+	}
+
+	stanzas := make([]*stanza, 0, len(opts.Recipients))
+	for _, recipient := range opts.Recipients {
+		publicKey, err := parseX25519Recipient(recipient)
+		if err != nil {
+			return nil, &ExportError{
+				Code:    ErrCodeEncryptionFailed,
+				Message: fmt.Sprintf("invalid recipient %q", recipient),
+				Err:     err,
+			}
+		}
+
+		recipientStanza, err := wrapForRecipient(fileKey, publicKey)
+		if err != nil {
+			return nil, createEncryptionError(err)
+		}
+		stanzas = append(stanzas, recipientStanza)
+	}
+	return stanzas, nil
+}
+
+func createEncryptionError(err error) error {
+	return &ExportError{
+		Code:    ErrCodeEncryptionFailed,
+		Message: "failed to encrypt export",
+		Err:     err,
+	}
+}
+
+type encryptWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	buf     []byte
+	counter uint64
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		// A full chunk is only sealed once there is more data,
+		// as the last chunk is sealed differently on close.
+		if len(e.buf) == encryptionChunkSize {
+			if err := e.sealChunk(false); err != nil {
+				return written, err
+			}
+		}
+
+		n := copy(e.buf[len(e.buf):encryptionChunkSize], p)
+		e.buf = e.buf[:len(e.buf)+n]
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+func (e *encryptWriter) Close() error {
+	return e.sealChunk(true)
+}
+
+func (e *encryptWriter) sealChunk(last bool) error {
+	sealed := e.aead.Seal(nil, chunkNonce(e.counter, last), e.buf, nil)
+	e.buf = e.buf[:0]
+	e.counter++
+	_, err := e.w.Write(sealed)
+	return err
+}
+
+// NewDecryptReader returns a reader for the decrypted contents of r when
+// r is an encrypted export, otherwise the contents of r are returned unchanged.
+func NewDecryptReader(r io.Reader, opts *DecryptionOptions) (io.Reader, error) {
+	reader := bufio.NewReader(r)
+	encrypted, err := isEncrypted(reader)
+	if err != nil || !encrypted {
+		return reader, err
+	}
+
+	header, err := readEncryptionHeader(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	fileKey, err := header.unwrap(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, encryptionNonceSize)
+	if _, err := io.ReadFull(reader, nonce); err != nil {
+		return nil, createCorruptExportError(err)
+	}
+
+	aead, err := payloadAEAD(fileKey, nonce)
+	if err != nil {
+		return nil, createCorruptExportError(err)
+	}
+
+	return &decryptReader{
+		r:     reader,
+		aead:  aead,
+		chunk: make([]byte, encryptionChunkSize+aead.Overhead()),
+	}, nil
+}
+
+func isEncrypted(reader *bufio.Reader) (bool, error) {
+	start, err := reader.Peek(len(encryptionMagic))
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	return string(start) == encryptionMagic, nil
+}
+
+type decryptReader struct {
+	r         *bufio.Reader
+	aead      cipher.AEAD
+	chunk     []byte
+	plaintext []byte
+	counter   uint64
+	done      bool
+	err       error
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.plaintext) == 0 {
+		if d.err != nil {
+			return 0, d.err
+		}
+		if d.done {
+			return 0, io.EOF
+		}
+		d.err = d.openChunk()
+	}
+
+	n := copy(p, d.plaintext)
+	d.plaintext = d.plaintext[n:]
+	return n, nil
+}
+
+func (d *decryptReader) openChunk() error {
+	n, err := io.ReadFull(d.r, d.chunk)
+	last := false
+	switch {
+	case err == io.EOF:
+		return createCorruptExportError(errors.New("the payload ends before its last chunk"))
+	case err == io.ErrUnexpectedEOF:
+		last = true
+	case err != nil:
+		return err
+	default:
+		if _, peekErr := d.r.Peek(1); peekErr == io.EOF {
+			last = true
+		} else if peekErr != nil {
+			return peekErr
+		}
+	}
+
+	plaintext, err := d.aead.Open(d.chunk[:0], chunkNonce(d.counter, last), d.chunk[:n], nil)
+	if err != nil {
+		return createCorruptExportError(err)
+	}
+	if last && len(plaintext) == 0 && d.counter > 0 {
+		return createCorruptExportError(errors.New("the payload ends with an empty chunk"))
+	}
+
+	d.plaintext = plaintext
+	d.counter++
+	d.done = last
+	return nil
+}
+
+func createCorruptExportError(err error) error {
+	return &ImportError{
+		Code:    ErrCodeDecryptionFailed,
+		Message: "encrypted export is corrupt or was truncated",
+		Err:     err,
+	}
+}
+
+func chunkNonce(counter uint64, last bool) []byte {
+	nonce := make([]byte, 12)
+	binary.BigEndian.PutUint64(nonce[3:11], counter)
+	if last {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+func payloadAEAD(fileKey, nonce []byte) (cipher.AEAD, error) {
+	key, err := hkdf.Key(sha256.New, fileKey, nonce, "payload", 32)
+	if err != nil {
+		return nil, err
+	}
+	return newAESGCM(key)
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+type stanza struct {
+	stanzaType string
+	args       []string
+	body       []byte
+}
+
+type encryptionHeader struct {
+	stanzas []*stanza
+	mac     []byte
+	// signed holds the bytes of the header covered by the MAC.
+	signed []byte
+}
+
+func (h *encryptionHeader) marshal(fileKey []byte) ([]byte, error) {
+	header := &bytes.Buffer{}
+	header.WriteString(encryptionMagic + "\n")
+	for _, s := range h.stanzas {
+		fmt.Fprintf(header, "-> %s\n", strings.Join(append([]string{s.stanzaType}, s.args...), " "))
+		header.WriteString(base64.RawStdEncoding.EncodeToString(s.body) + "\n")
+	}
+	header.WriteString("---")
+
+	mac, err := headerMAC(fileKey, header.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	header.WriteString(" " + base64.RawStdEncoding.EncodeToString(mac) + "\n")
+	return header.Bytes(), nil
+}
+
+func readEncryptionHeader(reader *bufio.Reader) (*encryptionHeader, error) {
+	signed := &bytes.Buffer{}
+	magic, err := readHeaderLine(reader, signed)
+	if err != nil || magic != encryptionMagic {
+		return nil, createInvalidHeaderError(err)
+	}
+
+	header := &encryptionHeader{}
+	for len(header.stanzas) <= maxHeaderStanzas {
+		line, err := readHeaderLine(reader, signed)
+		if err != nil {
+			return nil, createInvalidHeaderError(err)
+		}
+
+		if macPart, isEnd := strings.CutPrefix(line, "--- "); isEnd {
+			header.mac, err = base64.RawStdEncoding.DecodeString(macPart)
+			if err != nil {
+				return nil, createInvalidHeaderError(err)
+			}
+			header.signed = signed.Bytes()[:signed.Len()-len(line)+len("---")-1]
+			return header, nil
+		}
+
+		args := strings.Fields(strings.TrimPrefix(line, "-> "))
+		if !strings.HasPrefix(line, "-> ") || len(args) == 0 {
+			return nil, createInvalidHeaderError(fmt.Errorf("unexpected header line %q", line))
+		}
+
+		bodyLine, err := readHeaderLine(reader, signed)
+		if err != nil {
+			return nil, createInvalidHeaderError(err)
+		}
+		body, err := base64.RawStdEncoding.DecodeString(bodyLine)
+		if err != nil {
+			return nil, createInvalidHeaderError(err)
+		}
+
+		header.stanzas = append(header.stanzas, &stanza{
+			stanzaType: args[0],
+			args:       args[1:],
+			body:       body,
+		})
+	}
+
+	return nil, createInvalidHeaderError(errors.New("too many recipients"))
+}
+
+// readHeaderLine reads a line of the header without its trailing new line,
+// recording the raw line so the header MAC can be verified.
+func readHeaderLine(reader *bufio.Reader, signed *bytes.Buffer) (string, error) {
+	// ReadSlice fails for lines longer than the reader's buffer,
+	// which is far longer than any valid header line.
+	line, err := reader.ReadSlice('\n')
+	if err != nil {
+		return "", err
+	}
+	signed.Write(line)
+	return strings.TrimSuffix(string(line), "\n"), nil
+}
+
+func createInvalidHeaderError(err error) error {
+	return &ImportError{
+		Code:    ErrCodeDecryptionFailed,
+		Message: "encrypted export has an invalid header",
+		Err:     err,
+	}
+}
+
+func (h *encryptionHeader) unwrap(opts *DecryptionOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &DecryptionOptions{}
+	}
+
+	hasScrypt := false
+	for _, s := range h.stanzas {
+		hasScrypt = hasScrypt || s.stanzaType == scryptStanzaType
+	}
+	if hasScrypt && len(h.stanzas) > 1 {
+		return nil, createInvalidHeaderError(
+			errors.New("a passphrase stanza must be the only stanza in the header"),
+		)
+	}
+
+	if hasScrypt {
+		if len(opts.Passphrase) == 0 {
+			return nil, &ImportError{
+				Code:    ErrCodeDecryptionRequired,
+				Message: "export is encrypted with a passphrase, provide the passphrase to import it",
+			}
+		}
+		return h.verify(unwrapWithPassphrase(h.stanzas[0], opts.Passphrase))
+	}
+
+	if len(opts.Identities) == 0 {
+		return nil, &ImportError{
+			Code:    ErrCodeDecryptionRequired,
+			Message: "export is encrypted for recipients, provide an identity to import it",
+		}
+	}
+
+	for _, identity := range opts.Identities {
+		privateKey, err := parseX25519Identity(identity)
+		if err != nil {
+			return nil, &ImportError{
+				Code:    ErrCodeDecryptionFailed,
+				Message: "invalid identity",
+				Err:     err,
+			}
+		}
+
+		for _, s := range h.stanzas {
+			if s.stanzaType != x25519StanzaType {
+				continue
+			}
+			fileKey, err := unwrapForIdentity(s, privateKey)
+			if err == nil {
+				return h.verify(fileKey, nil)
+			}
+		}
+	}
+
+	return nil, &ImportError{
+		Code:    ErrCodeDecryptionFailed,
+		Message: "none of the provided identities can decrypt the export",
+	}
+}
+
+func (h *encryptionHeader) verify(fileKey []byte, unwrapErr error) ([]byte, error) {
+	if unwrapErr != nil {
+		return nil, unwrapErr
+	}
+
+	mac, err := headerMAC(fileKey, h.signed)
+	if err != nil || !hmac.Equal(mac, h.mac) {
+		return nil, createInvalidHeaderError(errors.New("header MAC does not match"))
+	}
+	return fileKey, nil
+}
+
+func headerMAC(fileKey, header []byte) ([]byte, error) {
+	key, err := hkdf.Key(sha256.New, fileKey, nil, "header", 32)
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(header)
+	return mac.Sum(nil), nil
+}
+
+func wrapWithPassphrase(fileKey, passphrase []byte) (*stanza, error) {
+	salt := make([]byte, 16)
+	rand.Read(salt)
+
+	key, err := passphraseKey(passphrase, salt, scryptWorkFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := wrapFileKey(key, fileKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stanza{
+		stanzaType: scryptStanzaType,
+		args: []string{
+			base64.RawStdEncoding.EncodeToString(salt),
+			strconv.Itoa(scryptWorkFactor),
+		},
+		body: body,
+	}, nil
+}
+
+func unwrapWithPassphrase(s *stanza, passphrase []byte) ([]byte, error) {
+	if len(s.args) != 2 {
+		return nil, createInvalidHeaderError(errors.New("invalid scrypt stanza"))
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(s.args[0])
+	if err != nil {
+		return nil, createInvalidHeaderError(err)
+	}
+	workFactor, err := strconv.Atoi(s.args[1])
+	if err != nil || workFactor <= 0 || workFactor > maxScryptWorkFactor {
+		return nil, createInvalidHeaderError(
+			fmt.Errorf("scrypt work factor must be between 1 and %d", maxScryptWorkFactor),
+		)
+	}
+
+	key, err := passphraseKey(passphrase, salt, workFactor)
+	if err != nil {
+		return nil, createInvalidHeaderError(err)
+	}
+
+	fileKey, err := unwrapFileKey(key, s.body)
+	if err != nil {
+		return nil, &ImportError{
+			Code:    ErrCodeDecryptionFailed,
+			Message: "incorrect passphrase",
+		}
+	}
+	return fileKey, nil
+}
+
+func passphraseKey(passphrase, salt []byte, workFactor int) ([]byte, error) {
+	scryptSalt := append([]byte(encryptionMagic+"/scrypt"), salt...)
+	return scrypt.Key(passphrase, scryptSalt, 1<<workFactor, 8, 1, 32)
+}
+
+func wrapForRecipient(fileKey []byte, publicKey *ecdh.PublicKey) (*stanza, error) {
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := recipientKey(ephemeral, publicKey, ephemeral.PublicKey(), publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := wrapFileKey(key, fileKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stanza{
+		stanzaType: x25519StanzaType,
+		args:       []string{base64.RawStdEncoding.EncodeToString(ephemeral.PublicKey().Bytes())},
+		body:       body,
+	}, nil
+}
+
+func unwrapForIdentity(s *stanza, privateKey *ecdh.PrivateKey) ([]byte, error) {
+	if len(s.args) != 1 {
+		return nil, errors.New("invalid X25519 stanza")
+	}
+
+	ephemeralBytes, err := base64.RawStdEncoding.DecodeString(s.args[0])
+	if err != nil {
+		return nil, err
+	}
+	ephemeralPublicKey, err := ecdh.X25519().NewPublicKey(ephemeralBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := recipientKey(privateKey, ephemeralPublicKey, ephemeralPublicKey, privateKey.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+
+	return unwrapFileKey(key, s.body)
+}
+
+// recipientKey derives the key that wraps the file key for a recipient from the
+// shared secret of the ephemeral key and the recipient's key.
+func recipientKey(
+	privateKey *ecdh.PrivateKey,
+	peerPublicKey *ecdh.PublicKey,
+	ephemeralPublicKey *ecdh.PublicKey,
+	recipientPublicKey *ecdh.PublicKey,
+) ([]byte, error) {
+	sharedSecret, err := privateKey.ECDH(peerPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := append(ephemeralPublicKey.Bytes(), recipientPublicKey.Bytes()...)
+	return hkdf.Key(sha256.New, sharedSecret, salt, encryptionMagic+"/X25519", 32)
+}
+
+// File keys are wrapped with a zero nonce as every wrapping key is only used once.
+func wrapFileKey(key, fileKey []byte) ([]byte, error) {
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(nil, make([]byte, aead.NonceSize()), fileKey, nil), nil
+}
+
+func unwrapFileKey(key, wrapped []byte) ([]byte, error) {
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Open(nil, make([]byte, aead.NonceSize()), wrapped, nil)
+}
+
+// X25519Identity is a key pair that exports can be encrypted for,
+// the recipient (public key) is shared with whoever creates exports and
+// the identity (private key) is kept secret by whoever imports them.
+type X25519Identity struct {
+	privateKey *ecdh.PrivateKey
+}
+
+// GenerateX25519Identity generates a new random identity.
+func GenerateX25519Identity() (*X25519Identity, error) {
+	privateKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &X25519Identity{privateKey: privateKey}, nil
+}
+
+// String returns the identity in the form "x25519-secret:<key>".
+func (i *X25519Identity) String() string {
+	return x25519IdentityPrefix + base64.RawURLEncoding.EncodeToString(i.privateKey.Bytes())
+}
+
+// Recipient returns the public key of the identity in the form "x25519:<key>".
+func (i *X25519Identity) Recipient() string {
+	return x25519RecipientPrefix + base64.RawURLEncoding.EncodeToString(i.privateKey.PublicKey().Bytes())
+}
+
+func parseX25519Recipient(recipient string) (*ecdh.PublicKey, error) {
+	encoded, hasPrefix := strings.CutPrefix(strings.TrimSpace(recipient), x25519RecipientPrefix)
+	if !hasPrefix {
+		return nil, fmt.Errorf("recipients must start with %q", x25519RecipientPrefix)
+	}
+
+	keyBytes, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	return ecdh.X25519().NewPublicKey(keyBytes)
+}
+
+func parseX25519Identity(identity string) (*ecdh.PrivateKey, error) {
+	encoded, hasPrefix := strings.CutPrefix(strings.TrimSpace(identity), x25519IdentityPrefix)
+	if !hasPrefix {
+		return nil, fmt.Errorf("identities must start with %q", x25519IdentityPrefix)
+	}
+
+	keyBytes, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	return ecdh.X25519().NewPrivateKey(keyBytes)
+}
+
+// ParseIdentities parses the identities in the contents of an identity file,
+// with one identity per line, blank lines and lines starting with "#" are ignored.
+func ParseIdentities(data []byte) ([]string, error) {
+	identities := []string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if _, err := parseX25519Identity(line); err != nil {
+			return nil, err
+		}
+		identities = append(identities, line)
+	}
+	return identities, nil
+}
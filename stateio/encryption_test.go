@@ -0,0 +1,220 @@
+package stateio
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type EncryptionTestSuite struct {
+	suite.Suite
+	originalWorkFactor int
+}
+
+func (s *EncryptionTestSuite) SetupTest() {
+	// Keep key derivation fast in tests.
+	s.originalWorkFactor = scryptWorkFactor
+	scryptWorkFactor = 10
+}
+
+func (s *EncryptionTestSuite) TearDownTest() {
+	scryptWorkFactor = s.originalWorkFactor
+}
+
+func (s *EncryptionTestSuite) Test_decrypts_exports_encrypted_with_a_passphrase() {
+	// Spans multiple chunks to exercise the chunk counter and the final chunk.
+	plaintext := make([]byte, encryptionChunkSize*2+100)
+	rand.Read(plaintext)
+
+	encrypted := s.encrypt(plaintext, &EncryptionOptions{Passphrase: []byte("correct horse")})
+	s.NotContains(string(encrypted), string(plaintext[:32]))
+
+	decrypted := s.decrypt(encrypted, &DecryptionOptions{Passphrase: []byte("correct horse")})
+	s.Equal(plaintext, decrypted)
+}
+
+func (s *EncryptionTestSuite) Test_decrypts_exports_that_fill_their_last_chunk() {
+	plaintext := bytes.Repeat([]byte("a"), encryptionChunkSize)
+
+	encrypted := s.encrypt(plaintext, &EncryptionOptions{Passphrase: []byte("passphrase")})
+
+	s.Equal(plaintext, s.decrypt(encrypted, &DecryptionOptions{Passphrase: []byte("passphrase")}))
+}
+
+func (s *EncryptionTestSuite) Test_decrypts_exports_with_any_recipient_identity() {
+	first, err := GenerateX25519Identity()
+	s.Require().NoError(err)
+	second, err := GenerateX25519Identity()
+	s.Require().NoError(err)
+	other, err := GenerateX25519Identity()
+	s.Require().NoError(err)
+
+	encrypted := s.encrypt([]byte(`[]`), &EncryptionOptions{
+		Recipients: []string{first.Recipient(), second.Recipient()},
+	})
+
+	decrypted := s.decrypt(encrypted, &DecryptionOptions{
+		Identities: []string{other.String(), second.String()},
+	})
+	s.Equal(`[]`, string(decrypted))
+
+	_, err = NewDecryptReader(bytes.NewReader(encrypted), &DecryptionOptions{
+		Identities: []string{other.String()},
+	})
+	s.assertImportErrorCode(err, ErrCodeDecryptionFailed)
+}
+
+func (s *EncryptionTestSuite) Test_rejects_an_incorrect_passphrase() {
+	encrypted := s.encrypt([]byte(`[]`), &EncryptionOptions{Passphrase: []byte("passphrase")})
+
+	_, err := NewDecryptReader(bytes.NewReader(encrypted), &DecryptionOptions{Passphrase: []byte("wrong")})
+
+	s.assertImportErrorCode(err, ErrCodeDecryptionFailed)
+	s.Contains(err.Error(), "incorrect passphrase")
+}
+
+func (s *EncryptionTestSuite) Test_requires_a_passphrase_for_encrypted_exports() {
+	encrypted := s.encrypt([]byte(`[]`), &EncryptionOptions{Passphrase: []byte("passphrase")})
+
+	_, err := NewDecryptReader(bytes.NewReader(encrypted), nil)
+
+	s.assertImportErrorCode(err, ErrCodeDecryptionRequired)
+}
+
+func (s *EncryptionTestSuite) Test_detects_truncated_and_modified_exports() {
+	plaintext := make([]byte, encryptionChunkSize+100)
+	encrypted := s.encrypt(plaintext, &EncryptionOptions{Passphrase: []byte("passphrase")})
+	opts := &DecryptionOptions{Passphrase: []byte("passphrase")}
+
+	truncated := encrypted[:len(encrypted)-150]
+	s.assertDecryptErrorCode(truncated, opts, ErrCodeDecryptionFailed)
+
+	modified := bytes.Clone(encrypted)
+	modified[len(modified)-1] ^= 1
+	s.assertDecryptErrorCode(modified, opts, ErrCodeDecryptionFailed)
+
+	modifiedHeader := bytes.Replace(encrypted, []byte(" 10\n"), []byte(" 11\n"), 1)
+	_, err := NewDecryptReader(bytes.NewReader(modifiedHeader), opts)
+	s.assertImportErrorCode(err, ErrCodeDecryptionFailed)
+}
+
+func (s *EncryptionTestSuite) Test_rejects_headers_with_removed_recipients() {
+	first, err := GenerateX25519Identity()
+	s.Require().NoError(err)
+	second, err := GenerateX25519Identity()
+	s.Require().NoError(err)
+
+	encrypted := s.encrypt([]byte(`[]`), &EncryptionOptions{
+		Recipients: []string{first.Recipient(), second.Recipient()},
+	})
+	lines := bytes.SplitN(encrypted, []byte("\n"), 4)
+	// Drops the first recipient's stanza line and wrapped key.
+	withoutFirst := bytes.Join([][]byte{lines[0], lines[3]}, []byte("\n"))
+
+	_, err = NewDecryptReader(bytes.NewReader(withoutFirst), &DecryptionOptions{
+		Identities: []string{second.String()},
+	})
+
+	s.assertImportErrorCode(err, ErrCodeDecryptionFailed)
+	s.Contains(err.Error(), "header MAC")
+}
+
+func (s *EncryptionTestSuite) Test_passes_unencrypted_exports_through() {
+	decrypted := s.decrypt([]byte(`[{"id":"inst-001"}]`), nil)
+
+	s.Equal(`[{"id":"inst-001"}]`, string(decrypted))
+}
+
+func (s *EncryptionTestSuite) Test_inspects_how_an_export_is_encrypted() {
+	identity, err := GenerateX25519Identity()
+	s.Require().NoError(err)
+
+	info, err := InspectEncryption(bytes.NewReader(
+		s.encrypt([]byte(`[]`), &EncryptionOptions{Passphrase: []byte("passphrase")}),
+	))
+	s.Require().NoError(err)
+	s.Equal(&EncryptionInfo{Encrypted: true, Passphrase: true}, info)
+
+	info, err = InspectEncryption(bytes.NewReader(
+		s.encrypt([]byte(`[]`), &EncryptionOptions{Recipients: []string{identity.Recipient()}}),
+	))
+	s.Require().NoError(err)
+	s.Equal(&EncryptionInfo{Encrypted: true, Recipients: 1}, info)
+
+	info, err = InspectEncryption(bytes.NewReader([]byte(`[]`)))
+	s.Require().NoError(err)
+	s.False(info.Encrypted)
+}
+
+func (s *EncryptionTestSuite) Test_rejects_invalid_encryption_options() {
+	identity, err := GenerateX25519Identity()
+	s.Require().NoError(err)
+
+	for _, opts := range []*EncryptionOptions{
+		{},
+		{Passphrase: []byte("passphrase"), Recipients: []string{identity.Recipient()}},
+		{Recipients: []string{identity.String()}},
+	} {
+		_, err := NewEncryptWriter(io.Discard, opts)
+		var exportErr *ExportError
+		s.Require().ErrorAs(err, &exportErr)
+		s.Equal(ErrCodeEncryptionFailed, exportErr.Code)
+	}
+}
+
+func (s *EncryptionTestSuite) Test_parses_identity_files() {
+	identity, err := GenerateX25519Identity()
+	s.Require().NoError(err)
+
+	identities, err := ParseIdentities([]byte("# created for CI\n\n" + identity.String() + "\n"))
+	s.Require().NoError(err)
+	s.Equal([]string{identity.String()}, identities)
+
+	_, err = ParseIdentities([]byte(identity.Recipient()))
+	s.Error(err)
+}
+
+func (s *EncryptionTestSuite) encrypt(plaintext []byte, opts *EncryptionOptions) []byte {
+	encrypted := &bytes.Buffer{}
+	writer, err := NewEncryptWriter(encrypted, opts)
+	s.Require().NoError(err)
+
+	_, err = writer.Write(plaintext)
+	s.Require().NoError(err)
+	s.Require().NoError(writer.Close())
+	return encrypted.Bytes()
+}
+
+func (s *EncryptionTestSuite) decrypt(encrypted []byte, opts *DecryptionOptions) []byte {
+	reader, err := NewDecryptReader(bytes.NewReader(encrypted), opts)
+	s.Require().NoError(err)
+
+	decrypted, err := io.ReadAll(reader)
+	s.Require().NoError(err)
+	return decrypted
+}
+
+func (s *EncryptionTestSuite) assertDecryptErrorCode(
+	encrypted []byte,
+	opts *DecryptionOptions,
+	code ImportErrorCode,
+) {
+	reader, err := NewDecryptReader(bytes.NewReader(encrypted), opts)
+	s.Require().NoError(err)
+
+	_, err = io.ReadAll(reader)
+	s.assertImportErrorCode(err, code)
+}
+
+func (s *EncryptionTestSuite) assertImportErrorCode(err error, code ImportErrorCode) {
+	var importErr *ImportError
+	s.Require().ErrorAs(err, &importErr)
+	s.Equal(code, importErr.Code)
+}
+
+func TestEncryptionTestSuite(t *testing.T) {
+	suite.Run(t, new(EncryptionTestSuite))
+}
@@ -1,10 +1,13 @@
 package stateio
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"runtime"
+
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // StateConfig holds the state-related configuration from an engine config file.
@@ -105,3 +108,23 @@ func BuildPostgresDatabaseURL(config *StateConfig) string {
 		config.PostgresPoolMaxConnLifetime,
 	)
 }
+
+// PingPostgres checks that the postgres database described by the state config
+// can be reached with the configured credentials.
+func PingPostgres(ctx context.Context, config *StateConfig) error {
+	pool, err := pgxpool.New(ctx, BuildPostgresDatabaseURL(config))
+	if err != nil {
+		return fmt.Errorf("failed to create postgres connection pool: %w", err)
+	}
+	defer pool.Close()
+
+	if err := pool.Ping(ctx); err != nil {
+		return fmt.Errorf(
+			"failed to connect to postgres at %s:%d: %w",
+			config.PostgresHost,
+			config.PostgresPort,
+			err,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,405 @@
+package stateio
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+)
+
+// ExportFormatVersion is the version of the envelope format written by exports.
+// The major version is only bumped for changes that older versions of the SDK
+// can not import, newer minor versions can always be imported.
+const ExportFormatVersion = "1.0"
+
+// ExportEnvelope holds the metadata of an export written in the envelope format.
+//
+// An envelope is a JSON object that wraps the exported instances:
+//
+//	{
+//	  "formatVersion": "1.0",
+//	  "createdAt": "2026-01-02T15:04:05Z",
+//	  "source": { "storageEngine": "postgres", "tool": "bluelink" },
+//	  "instances": [
+//	    { "sha256": "<checksum of the instance JSON>", "instance": { ... } }
+//	  ],
+//	  "instanceCount": 1,
+//	  "digest": "<checksum of all the instance checksums>"
+//	}
+//
+// Each instance is verified against its checksum before it is imported,
+// the instance count and digest come after the instances so exports
+// can be written without knowing how many instances there will be up front.
+type ExportEnvelope struct {
+	FormatVersion string       `json:"formatVersion"`
+	CreatedAt     time.Time    `json:"createdAt"`
+	Source        ExportSource `json:"source"`
+	InstanceCount int          `json:"instanceCount"`
+	Digest        string       `json:"digest"`
+}
+
+// ExportSource describes where an export was produced.
+type ExportSource struct {
+	// StorageEngine is the storage engine the instances were exported from
+	// (e.g. "memfile" or "postgres").
+	StorageEngine string `json:"storageEngine,omitempty"`
+	// Tool is the name of the CLI that produced the export.
+	Tool string `json:"tool,omitempty"`
+}
+
+type envelopeInstance struct {
+	SHA256   string          `json:"sha256"`
+	Instance json.RawMessage `json:"instance"`
+}
+
+// EnvelopeJSONWriter writes instances to an io.Writer wrapped in an export envelope,
+// one instance at a time.
+type EnvelopeJSONWriter struct {
+	w       io.Writer
+	header  ExportEnvelope
+	started bool
+	count   int
+	digest  hash.Hash
+}
+
+// NewEnvelopeJSONWriter creates a new writer that encodes instances in the
+// envelope format with the creation time and source from the given header.
+// The format version, instance count and digest are filled in by the writer.
+func NewEnvelopeJSONWriter(w io.Writer, header ExportEnvelope) *EnvelopeJSONWriter {
+	header.FormatVersion = ExportFormatVersion
+	return &EnvelopeJSONWriter{
+		w:      w,
+		header: header,
+		digest: sha256.New(),
+	}
+}
+
+// WriteInstance encodes the given instance along with its checksum
+// as the next element of the envelope's instances.
+func (w *EnvelopeJSONWriter) WriteInstance(instance state.InstanceState) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(instance)
+	if err != nil {
+		return createInstanceSerializeError(instance, err)
+	}
+
+	checksum := sha256.Sum256(data)
+	w.digest.Write(checksum[:])
+	entry, err := json.MarshalIndent(
+		envelopeInstance{
+			SHA256:   hex.EncodeToString(checksum[:]),
+			Instance: data,
+		},
+		"    ",
+		"  ",
+	)
+	if err != nil {
+		return createInstanceSerializeError(instance, err)
+	}
+
+	separator := ",\n    "
+	if w.count == 0 {
+		separator = "\n    "
+	}
+	if err := writeChunks(w.w, []byte(separator), entry); err != nil {
+		return err
+	}
+
+	w.count++
+	return nil
+}
+
+// Close ends the instances and writes the instance count and digest,
+// it does not close the underlying writer.
+func (w *EnvelopeJSONWriter) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	end := "]"
+	if w.count > 0 {
+		end = "\n  ]"
+	}
+	return writeChunks(
+		w.w,
+		[]byte(end),
+		[]byte(fmt.Sprintf(",\n  \"instanceCount\": %d", w.count)),
+		[]byte(fmt.Sprintf(",\n  \"digest\": %q\n}", hex.EncodeToString(w.digest.Sum(nil)))),
+	)
+}
+
+// Count returns the number of instances written so far.
+func (w *EnvelopeJSONWriter) Count() int {
+	return w.count
+}
+
+func (w *EnvelopeJSONWriter) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+
+	createdAt, err := json.Marshal(w.header.CreatedAt)
+	if err != nil {
+		return createEnvelopeSerializeError(err)
+	}
+
+	source, err := json.MarshalIndent(w.header.Source, "  ", "  ")
+	if err != nil {
+		return createEnvelopeSerializeError(err)
+	}
+
+	return writeChunks(
+		w.w,
+		[]byte(fmt.Sprintf("{\n  \"formatVersion\": %q", w.header.FormatVersion)),
+		[]byte(",\n  \"createdAt\": "),
+		createdAt,
+		[]byte(",\n  \"source\": "),
+		source,
+		[]byte(",\n  \"instances\": ["),
+	)
+}
+
+func createEnvelopeSerializeError(err error) error {
+	return &ExportError{
+		Code:    ErrCodeExportFailed,
+		Message: "failed to serialize export envelope to JSON",
+		Err:     err,
+	}
+}
+
+func decodeEnvelope(
+	decoder *json.Decoder,
+	reader *inputReader,
+	handle func(state.InstanceState) error,
+) (*DecodedExport, error) {
+	envelope := &ExportEnvelope{}
+	hasVersion := false
+	hasInstanceCount := false
+	count := 0
+	digest := sha256.New()
+
+	for decoder.More() {
+		keyToken, err := decoder.Token()
+		if err != nil {
+			return nil, decodeError(reader, err)
+		}
+
+		key, _ := keyToken.(string)
+		switch key {
+		case "formatVersion":
+			if err := decoder.Decode(&envelope.FormatVersion); err != nil {
+				return nil, decodeError(reader, err)
+			}
+			if err := checkFormatVersion(envelope.FormatVersion); err != nil {
+				return nil, err
+			}
+			hasVersion = true
+		case "instances":
+			if !hasVersion {
+				return nil, &ImportError{
+					Code:    ErrCodeInvalidEnvelope,
+					Message: "export envelope must contain a formatVersion before its instances",
+				}
+			}
+			count, err = decodeEnvelopeInstances(decoder, reader, digest, handle)
+			if err != nil {
+				return nil, err
+			}
+		case "instanceCount":
+			if err := decoder.Decode(&envelope.InstanceCount); err != nil {
+				return nil, decodeError(reader, err)
+			}
+			hasInstanceCount = true
+		default:
+			if err := decodeEnvelopeField(decoder, key, envelope); err != nil {
+				return nil, decodeError(reader, err)
+			}
+		}
+	}
+
+	if err := expectDelim(decoder, '}'); err != nil {
+		return nil, decodeError(reader, err)
+	}
+
+	// Objects without a format version are not export envelopes.
+	if !hasVersion {
+		return nil, &ImportError{
+			Code:    ErrCodeInvalidJSON,
+			Message: "expected a JSON array of instances or an export envelope with a formatVersion",
+		}
+	}
+
+	if err := verifyEnvelopeTotals(envelope, hasInstanceCount, count, digest); err != nil {
+		return nil, err
+	}
+
+	return &DecodedExport{
+		InstancesCount: count,
+		Envelope:       envelope,
+	}, nil
+}
+
+// verifyEnvelope reads an export in full to verify the checksums, instance count
+// and digest of exports in the envelope format without handling any instances.
+// Other input is not read past its first token and is not verified,
+// errors in other input are reported when it is decoded.
+func verifyEnvelope(r io.Reader) error {
+	input, err := NewDecompressReader(r)
+	if err != nil {
+		return inputError(err)
+	}
+	defer input.Close()
+
+	reader := &inputReader{r: input}
+	decoder := json.NewDecoder(reader)
+	token, err := decoder.Token()
+	if err != nil {
+		return decodeError(reader, err)
+	}
+	if token != json.Delim('{') {
+		return nil
+	}
+
+	_, err = decodeEnvelope(decoder, reader, func(state.InstanceState) error {
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return expectEndOfInput(decoder, reader)
+}
+
+// decodeEnvelopeField decodes the metadata fields of the envelope that
+// do not need to be checked as they are read, unknown fields are skipped
+// so newer minor versions of the format can be imported.
+func decodeEnvelopeField(decoder *json.Decoder, key string, envelope *ExportEnvelope) error {
+	switch key {
+	case "createdAt":
+		return decoder.Decode(&envelope.CreatedAt)
+	case "source":
+		return decoder.Decode(&envelope.Source)
+	case "digest":
+		return decoder.Decode(&envelope.Digest)
+	default:
+		var skipped json.RawMessage
+		return decoder.Decode(&skipped)
+	}
+}
+
+func decodeEnvelopeInstances(
+	decoder *json.Decoder,
+	reader *inputReader,
+	digest hash.Hash,
+	handle func(state.InstanceState) error,
+) (int, error) {
+	if err := expectDelim(decoder, '['); err != nil {
+		return 0, decodeError(reader, err)
+	}
+
+	count := 0
+	for decoder.More() {
+		var entry envelopeInstance
+		if err := decoder.Decode(&entry); err != nil {
+			return count, decodeError(reader, err)
+		}
+
+		instanceData := &bytes.Buffer{}
+		if err := json.Compact(instanceData, entry.Instance); err != nil {
+			return count, decodeError(reader, err)
+		}
+
+		checksum := sha256.Sum256(instanceData.Bytes())
+		if hex.EncodeToString(checksum[:]) != entry.SHA256 {
+			return count, &ImportError{
+				Code:    ErrCodeChecksumMismatch,
+				Message: fmt.Sprintf("instance %d in the export does not match its checksum", count+1),
+			}
+		}
+		digest.Write(checksum[:])
+
+		var instance state.InstanceState
+		if err := json.Unmarshal(instanceData.Bytes(), &instance); err != nil {
+			return count, decodeError(reader, err)
+		}
+
+		if err := handle(instance); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	if err := expectDelim(decoder, ']'); err != nil {
+		return count, decodeError(reader, err)
+	}
+
+	return count, nil
+}
+
+func verifyEnvelopeTotals(
+	envelope *ExportEnvelope,
+	hasInstanceCount bool,
+	count int,
+	digest hash.Hash,
+) error {
+	if !hasInstanceCount || envelope.InstanceCount != count {
+		return &ImportError{
+			Code: ErrCodeChecksumMismatch,
+			Message: fmt.Sprintf(
+				"export contains %d instances but its envelope records %d, the file may have been truncated or modified",
+				count,
+				envelope.InstanceCount,
+			),
+		}
+	}
+
+	if envelope.Digest != hex.EncodeToString(digest.Sum(nil)) {
+		return &ImportError{
+			Code:    ErrCodeChecksumMismatch,
+			Message: "export does not match its digest, the file may have been truncated or modified",
+		}
+	}
+
+	return nil
+}
+
+func checkFormatVersion(formatVersion string) error {
+	major, err := formatMajorVersion(formatVersion)
+	if err != nil {
+		return &ImportError{
+			Code:    ErrCodeInvalidEnvelope,
+			Message: fmt.Sprintf("invalid export format version %q", formatVersion),
+			Err:     err,
+		}
+	}
+
+	supportedMajor, _ := formatMajorVersion(ExportFormatVersion)
+	if major != supportedMajor {
+		return &ImportError{
+			Code: ErrCodeIncompatibleFormatVersion,
+			Message: fmt.Sprintf(
+				"export format version %s is not supported, only %d.x exports can be imported",
+				formatVersion,
+				supportedMajor,
+			),
+		}
+	}
+
+	return nil
+}
+
+func formatMajorVersion(formatVersion string) (int, error) {
+	majorPart, _, _ := strings.Cut(formatVersion, ".")
+	return strconv.Atoi(majorPart)
+}
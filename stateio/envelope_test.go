@@ -0,0 +1,172 @@
+package stateio
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/stretchr/testify/suite"
+)
+
+type EnvelopeTestSuite struct {
+	suite.Suite
+	createdAt time.Time
+}
+
+func (s *EnvelopeTestSuite) SetupTest() {
+	s.createdAt = time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+}
+
+func (s *EnvelopeTestSuite) Test_decodes_instances_and_metadata_written_by_the_envelope_writer() {
+	data := s.writeEnvelope(
+		state.InstanceState{InstanceID: "inst-001", InstanceName: "Instance <One>", Status: core.InstanceStatusDeployed},
+		state.InstanceState{InstanceID: "inst-002", InstanceName: "Instance Two", Status: core.InstanceStatusDeployed},
+	)
+
+	ids := []string{}
+	decoded, err := DecodeExportJSON(bytes.NewReader(data), func(instance state.InstanceState) error {
+		ids = append(ids, instance.InstanceID)
+		return nil
+	})
+
+	s.Require().NoError(err)
+	s.Equal([]string{"inst-001", "inst-002"}, ids)
+	s.Equal(2, decoded.InstancesCount)
+	s.Require().NotNil(decoded.Envelope)
+	s.Equal(ExportFormatVersion, decoded.Envelope.FormatVersion)
+	s.Equal(s.createdAt, decoded.Envelope.CreatedAt)
+	s.Equal(ExportSource{StorageEngine: StorageEnginePostgres, Tool: "bluelink"}, decoded.Envelope.Source)
+	s.Equal(2, decoded.Envelope.InstanceCount)
+	s.NotEmpty(decoded.Envelope.Digest)
+}
+
+func (s *EnvelopeTestSuite) Test_writes_valid_json_for_an_empty_export() {
+	data := s.writeEnvelope()
+
+	var envelope map[string]any
+	s.Require().NoError(json.Unmarshal(data, &envelope))
+	s.Equal([]any{}, envelope["instances"])
+
+	decoded, err := DecodeExportJSON(bytes.NewReader(data), noopInstanceHandler)
+	s.Require().NoError(err)
+	s.Equal(0, decoded.InstancesCount)
+}
+
+func (s *EnvelopeTestSuite) Test_rejects_instances_that_do_not_match_their_checksum() {
+	data := s.writeEnvelope(
+		state.InstanceState{InstanceID: "inst-001", InstanceName: "Instance One"},
+		state.InstanceState{InstanceID: "inst-002", InstanceName: "Instance Two"},
+	)
+	tampered := strings.Replace(string(data), "Instance Two", "Instance 2", 1)
+
+	ids := []string{}
+	_, err := DecodeExportJSON(strings.NewReader(tampered), func(instance state.InstanceState) error {
+		ids = append(ids, instance.InstanceID)
+		return nil
+	})
+
+	s.assertImportErrorCode(err, ErrCodeChecksumMismatch)
+	s.Contains(err.Error(), "instance 2")
+	s.Equal([]string{"inst-001"}, ids)
+}
+
+func (s *EnvelopeTestSuite) Test_rejects_exports_with_missing_instances() {
+	data := s.writeEnvelope(
+		state.InstanceState{InstanceID: "inst-001"},
+		state.InstanceState{InstanceID: "inst-002"},
+	)
+	envelope := map[string]json.RawMessage{}
+	s.Require().NoError(json.Unmarshal(data, &envelope))
+	instances := []json.RawMessage{}
+	s.Require().NoError(json.Unmarshal(envelope["instances"], &instances))
+	envelope["instances"] = s.marshal(instances[:1])
+
+	_, err := DecodeExportJSON(bytes.NewReader(s.marshal(envelope)), noopInstanceHandler)
+	s.assertImportErrorCode(err, ErrCodeChecksumMismatch)
+
+	// Fixing up the count is not enough to get past the digest.
+	envelope["instanceCount"] = s.marshal(1)
+	_, err = DecodeExportJSON(bytes.NewReader(s.marshal(envelope)), noopInstanceHandler)
+	s.assertImportErrorCode(err, ErrCodeChecksumMismatch)
+	s.Contains(err.Error(), "digest")
+}
+
+func (s *EnvelopeTestSuite) Test_rejects_incompatible_major_versions() {
+	called := false
+	_, err := DecodeExportJSON(
+		strings.NewReader(`{"formatVersion":"2.0","instances":[{"sha256":"","instance":{}}]}`),
+		func(instance state.InstanceState) error {
+			called = true
+			return nil
+		},
+	)
+
+	s.assertImportErrorCode(err, ErrCodeIncompatibleFormatVersion)
+	s.False(called)
+}
+
+func (s *EnvelopeTestSuite) Test_rejects_invalid_format_versions() {
+	_, err := DecodeExportJSON(strings.NewReader(`{"formatVersion":"latest"}`), noopInstanceHandler)
+	s.assertImportErrorCode(err, ErrCodeInvalidEnvelope)
+
+	_, err = DecodeExportJSON(strings.NewReader(`{"instances":[],"formatVersion":"1.0"}`), noopInstanceHandler)
+	s.assertImportErrorCode(err, ErrCodeInvalidEnvelope)
+}
+
+func (s *EnvelopeTestSuite) Test_accepts_newer_minor_versions_with_unknown_fields() {
+	data := s.writeEnvelope(state.InstanceState{InstanceID: "inst-001"})
+	envelope := map[string]json.RawMessage{}
+	s.Require().NoError(json.Unmarshal(data, &envelope))
+	envelope["formatVersion"] = s.marshal("1.7")
+	envelope["compression"] = s.marshal(map[string]string{"algorithm": "none"})
+
+	decoded, err := DecodeExportJSON(bytes.NewReader(s.marshal(envelope)), noopInstanceHandler)
+
+	s.Require().NoError(err)
+	s.Equal(1, decoded.InstancesCount)
+	s.Equal("1.7", decoded.Envelope.FormatVersion)
+}
+
+func (s *EnvelopeTestSuite) Test_decodes_legacy_arrays_without_an_envelope() {
+	decoded, err := DecodeExportJSON(strings.NewReader(`[{"id":"inst-001"}]`), noopInstanceHandler)
+
+	s.Require().NoError(err)
+	s.Equal(1, decoded.InstancesCount)
+	s.Nil(decoded.Envelope)
+}
+
+func (s *EnvelopeTestSuite) writeEnvelope(instances ...state.InstanceState) []byte {
+	output := &bytes.Buffer{}
+	writer := NewEnvelopeJSONWriter(output, ExportEnvelope{
+		CreatedAt: s.createdAt,
+		Source: ExportSource{
+			StorageEngine: StorageEnginePostgres,
+			Tool:          "bluelink",
+		},
+	})
+	for _, instance := range instances {
+		s.Require().NoError(writer.WriteInstance(instance))
+	}
+	s.Require().NoError(writer.Close())
+	return output.Bytes()
+}
+
+func (s *EnvelopeTestSuite) marshal(value any) []byte {
+	data, err := json.Marshal(value)
+	s.Require().NoError(err)
+	return data
+}
+
+func (s *EnvelopeTestSuite) assertImportErrorCode(err error, code ImportErrorCode) {
+	var importErr *ImportError
+	s.Require().ErrorAs(err, &importErr)
+	s.Equal(code, importErr.Code)
+}
+
+func TestEnvelopeTestSuite(t *testing.T) {
+	suite.Run(t, new(EnvelopeTestSuite))
+}
@@ -12,6 +12,27 @@ const (
 	ErrCodeFileNotFound ImportErrorCode = "file_not_found"
 	// ErrCodeRemoteAccessFail indicates a remote file could not be accessed.
 	ErrCodeRemoteAccessFail ImportErrorCode = "remote_access_failed"
+	// ErrCodeInvalidEnvelope indicates an export envelope has an invalid
+	// format version or its metadata is out of order.
+	ErrCodeInvalidEnvelope ImportErrorCode = "invalid_envelope"
+	// ErrCodeIncompatibleFormatVersion indicates an export was written with
+	// a major version of the envelope format that can not be imported.
+	ErrCodeIncompatibleFormatVersion ImportErrorCode = "incompatible_format_version"
+	// ErrCodeChecksumMismatch indicates the instances in an export do not match
+	// the checksums, instance count or digest recorded in its envelope.
+	ErrCodeChecksumMismatch ImportErrorCode = "checksum_mismatch"
+	// ErrCodeDecryptionRequired indicates an export is encrypted and
+	// no passphrase or identity was provided to decrypt it.
+	ErrCodeDecryptionRequired ImportErrorCode = "decryption_required"
+	// ErrCodeDecryptionFailed indicates an encrypted export could not be decrypted
+	// with the provided passphrase or identities, or has been corrupted.
+	ErrCodeDecryptionFailed ImportErrorCode = "decryption_failed"
+	// ErrCodeDecompressionFailed indicates a compressed export is corrupt
+	// or was truncated.
+	ErrCodeDecompressionFailed ImportErrorCode = "decompression_failed"
+	// ErrCodeInstanceConflict indicates instances in an import already exist
+	// with a different state and the import is set to fail on conflicts.
+	ErrCodeInstanceConflict ImportErrorCode = "instance_conflict"
 )
 
 // ImportError represents an error that occurred during import.
@@ -42,6 +63,8 @@ const (
 	ErrCodeInstanceNotFound ExportErrorCode = "not_found"
 	// ErrCodeRemoteUploadFailed indicates a remote upload failed.
 	ErrCodeRemoteUploadFailed ExportErrorCode = "remote_upload_failed"
+	// ErrCodeEncryptionFailed indicates an export could not be encrypted.
+	ErrCodeEncryptionFailed ExportErrorCode = "encryption_failed"
 )
 
 // ExportError represents an error that occurred during export.
@@ -3,6 +3,9 @@ package stateio
 import (
 	"context"
 	"fmt"
+	"io"
+	"path/filepath"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/newstack-cloud/bluelink/libs/blueprint-state/memfile"
@@ -30,6 +33,23 @@ type ExportParams struct {
 	// Exporter is an optional StateExporter for export.
 	// If not provided, a default exporter will be created based on EngineConfig.
 	Exporter StateExporter
+	// PageSize is the number of instances to load from the state container at a time.
+	// If not set, DefaultExportPageSize is used.
+	PageSize int
+	// Envelope writes the instances wrapped in an envelope with the format version,
+	// creation time, source, instance count and checksums of the export
+	// instead of as a bare JSON array.
+	Envelope bool
+	// Tool is the name of the CLI producing the export,
+	// recorded as part of the source in the envelope.
+	Tool string
+	// Encryption encrypts the export with a passphrase or for a set of recipients
+	// when provided, the export is written unencrypted otherwise.
+	Encryption *EncryptionOptions
+	// Compression is the format the export is compressed with before it is
+	// encrypted and written, when not set the format is determined
+	// from the extension of FilePath (see CompressionFromPath).
+	Compression Compression
 }
 
 // ExportResult contains the result of an export operation.
@@ -41,7 +61,12 @@ type ExportResult struct {
 }
 
 // Export performs a state export operation based on the provided parameters.
-// The output is a JSON array of blueprint instances.
+// The output is a JSON array of blueprint instances, or an ExportEnvelope when
+// params.Envelope is set, that is written as instances are loaded
+// so very large states never have to be held in memory.
+// The serialized export is compressed and then encrypted, when
+// compression and encryption are enabled, before it is written to the output.
+// The output file is only created or replaced once the export has succeeded.
 func Export(params ExportParams) (*ExportResult, error) {
 	if params.FileSystem == nil {
 		params.FileSystem = afero.NewOsFs()
@@ -57,30 +82,181 @@ func Export(params ExportParams) (*ExportResult, error) {
 	}
 
 	ctx := context.Background()
-	result, err := ExecuteInstancesExport(ctx, exporter, params.InstanceFilters)
+	output, err := createOutputWriter(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 
-	err = writeOutputData(params, result.Data)
+	payload, err := createPayloadWriter(params, output)
+	if err != nil {
+		output.Abort()
+		return nil, err
+	}
+
+	instancesCount, err := ExecuteInstancesExportStream(
+		ctx,
+		exporter,
+		params.InstanceFilters,
+		params.PageSize,
+		createInstancesWriter(params, payload),
+	)
+	if err == nil {
+		err = payload.Close()
+	}
+	if err != nil {
+		output.Abort()
+		return nil, err
+	}
+
+	err = output.Close()
 	if err != nil {
 		return nil, err
 	}
 
 	return &ExportResult{
 		Success:        true,
-		InstancesCount: result.InstancesCount,
+		InstancesCount: instancesCount,
 		FilePath:       params.FilePath,
-		Message:        fmt.Sprintf("Successfully exported %d instances to %s", result.InstancesCount, params.FilePath),
+		Message:        fmt.Sprintf("Successfully exported %d instances to %s", instancesCount, params.FilePath),
 	}, nil
 }
 
-func writeOutputData(params ExportParams, data []byte) error {
+func createInstancesWriter(params ExportParams, output io.Writer) InstancesWriter {
+	if !params.Envelope {
+		return NewInstancesJSONWriter(output)
+	}
+
+	source := ExportSource{Tool: params.Tool}
+	if params.EngineConfig != nil {
+		source.StorageEngine = params.EngineConfig.State.StorageEngine
+		if source.StorageEngine == "" {
+			source.StorageEngine = StorageEngineMemfile
+		}
+	}
+
+	return NewEnvelopeJSONWriter(output, ExportEnvelope{
+		CreatedAt: time.Now().UTC(),
+		Source:    source,
+	})
+}
+
+// createPayloadWriter creates the writer that serialized instances are
+// written to, compressing and then encrypting them before they reach
+// the output when requested.
+// Closing the payload writer does not close the output.
+func createPayloadWriter(params ExportParams, output io.Writer) (io.WriteCloser, error) {
+	var encrypted io.WriteCloser = nopWriteCloser{output}
+	if params.Encryption != nil {
+		var err error
+		encrypted, err = NewEncryptWriter(output, params.Encryption)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	compression := params.Compression
+	if compression == "" {
+		compression = CompressionFromPath(params.FilePath)
+	}
+
+	compressed, err := NewCompressWriter(encrypted, compression)
+	if err != nil {
+		return nil, err
+	}
+
+	return &layeredWriter{WriteCloser: compressed, next: encrypted}, nil
+}
+
+// layeredWriter closes the layer that is written to before
+// the layer it writes to, so each layer is flushed in order.
+type layeredWriter struct {
+	io.WriteCloser
+	next io.Closer
+}
+
+func (w *layeredWriter) Close() error {
+	if err := w.WriteCloser.Close(); err != nil {
+		return err
+	}
+	return w.next.Close()
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// outputWriter is the destination an export is streamed to,
+// the output is only kept when Close succeeds.
+type outputWriter interface {
+	io.WriteCloser
+	// Abort discards everything written so far.
+	Abort()
+}
+
+func createOutputWriter(ctx context.Context, params ExportParams) (outputWriter, error) {
 	if IsRemoteFile(params.FilePath) {
-		return UploadRemoteFile(context.Background(), params.FilePath, data, params.RemoteOptions)
+		return NewRemoteFileWriter(ctx, params.FilePath, params.RemoteOptions), nil
+	}
+
+	return newLocalFileWriter(params.FileSystem, params.FilePath)
+}
+
+// localFileWriter writes to a temporary file next to the output file
+// that replaces the output file when closed, so a failed export
+// never leaves a partially written file behind.
+type localFileWriter struct {
+	fileSystem afero.Fs
+	file       afero.File
+	filePath   string
+}
+
+func newLocalFileWriter(fileSystem afero.Fs, filePath string) (*localFileWriter, error) {
+	file, err := afero.TempFile(
+		fileSystem,
+		filepath.Dir(filePath),
+		fmt.Sprintf(".%s-*.tmp", filepath.Base(filePath)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &localFileWriter{
+		fileSystem: fileSystem,
+		file:       file,
+		filePath:   filePath,
+	}, nil
+}
+
+func (w *localFileWriter) Write(p []byte) (int, error) {
+	return w.file.Write(p)
+}
+
+func (w *localFileWriter) Close() error {
+	if err := w.file.Close(); err != nil {
+		w.fileSystem.Remove(w.file.Name())
+		return err
+	}
+
+	if err := w.fileSystem.Chmod(w.file.Name(), 0644); err != nil {
+		w.fileSystem.Remove(w.file.Name())
+		return err
 	}
 
-	return afero.WriteFile(params.FileSystem, params.FilePath, data, 0644)
+	if err := w.fileSystem.Rename(w.file.Name(), w.filePath); err != nil {
+		w.fileSystem.Remove(w.file.Name())
+		return err
+	}
+
+	return nil
+}
+
+func (w *localFileWriter) Abort() {
+	w.file.Close()
+	w.fileSystem.Remove(w.file.Name())
 }
 
 func createDefaultExporter(params ExportParams) (StateExporter, error) {
@@ -1,8 +1,10 @@
 package stateio
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
@@ -256,6 +258,38 @@ func (s *StateExportTestSuite) Test_export_fails_for_nonexistent_instance() {
 	var exportErr *ExportError
 	s.ErrorAs(err, &exportErr)
 	s.Equal(ErrCodeInstanceNotFound, exportErr.Code)
+
+	// No partially written output is left behind.
+	files, err := afero.ReadDir(s.fs, "/test")
+	s.Require().NoError(err)
+	for _, file := range files {
+		s.True(file.IsDir(), "unexpected file %s", file.Name())
+	}
+}
+
+func (s *StateExportTestSuite) Test_export_pages_through_instances() {
+	s.seedInstances([]state.InstanceState{
+		{InstanceID: "inst-001", InstanceName: "Instance 1", Status: core.InstanceStatusDeployed},
+		{InstanceID: "inst-002", InstanceName: "Instance 2", Status: core.InstanceStatusDeployed},
+		{InstanceID: "inst-003", InstanceName: "Instance 3", Status: core.InstanceStatusDeployed},
+	})
+
+	result, err := Export(ExportParams{
+		FilePath:     "/test/export.json",
+		EngineConfig: s.engineConfig,
+		FileSystem:   s.fs,
+		Logger:       core.NewNopLogger(),
+		PageSize:     2,
+	})
+
+	s.Require().NoError(err)
+	s.Equal(3, result.InstancesCount)
+
+	data, err := afero.ReadFile(s.fs, "/test/export.json")
+	s.Require().NoError(err)
+	var exported []state.InstanceState
+	s.Require().NoError(json.Unmarshal(data, &exported))
+	s.Len(exported, 3)
 }
 
 func (s *StateExportTestSuite) Test_export_fails_for_multiple_nonexistent_instances() {
@@ -421,6 +455,148 @@ func (s *StateExportTestSuite) Test_exported_data_can_be_reimported() {
 	s.Equal("res-001", inst1.ResourceIDs["resource1"])
 }
 
+func (s *StateExportTestSuite) Test_exports_in_envelope_format() {
+	s.seedInstances([]state.InstanceState{
+		{InstanceID: "inst-001", InstanceName: "Instance 1", Status: core.InstanceStatusDeployed},
+	})
+
+	_, err := Export(ExportParams{
+		FilePath:     "/test/export.json",
+		EngineConfig: s.engineConfig,
+		FileSystem:   s.fs,
+		Logger:       core.NewNopLogger(),
+		Envelope:     true,
+		Tool:         "bluelink",
+	})
+	s.Require().NoError(err)
+
+	data, err := afero.ReadFile(s.fs, "/test/export.json")
+	s.Require().NoError(err)
+	result, err := Import(ImportParams{
+		EngineConfig: s.engineConfig,
+		FileSystem:   s.fs,
+		FileData:     data,
+		Logger:       core.NewNopLogger(),
+	})
+
+	s.Require().NoError(err)
+	s.Equal(1, result.InstancesCount)
+	s.Require().NotNil(result.Envelope)
+	s.Equal(ExportSource{StorageEngine: StorageEngineMemfile, Tool: "bluelink"}, result.Envelope.Source)
+	s.Equal(1, result.Envelope.InstanceCount)
+}
+
+func (s *StateExportTestSuite) Test_exports_encrypted_with_a_passphrase() {
+	originalWorkFactor := scryptWorkFactor
+	scryptWorkFactor = 10
+	defer func() { scryptWorkFactor = originalWorkFactor }()
+
+	s.seedInstances([]state.InstanceState{
+		{InstanceID: "inst-001", InstanceName: "Instance 1", Status: core.InstanceStatusDeployed},
+	})
+
+	_, err := Export(ExportParams{
+		FilePath:     "/test/export.json.enc",
+		EngineConfig: s.engineConfig,
+		FileSystem:   s.fs,
+		Logger:       core.NewNopLogger(),
+		Envelope:     true,
+		Encryption:   &EncryptionOptions{Passphrase: []byte("passphrase")},
+	})
+	s.Require().NoError(err)
+
+	data, err := afero.ReadFile(s.fs, "/test/export.json.enc")
+	s.Require().NoError(err)
+	s.NotContains(string(data), "Instance 1")
+
+	_, err = Import(ImportParams{
+		EngineConfig: s.engineConfig,
+		FileSystem:   s.fs,
+		FileData:     data,
+		Logger:       core.NewNopLogger(),
+	})
+	var importErr *ImportError
+	s.Require().ErrorAs(err, &importErr)
+	s.Equal(ErrCodeDecryptionRequired, importErr.Code)
+
+	result, err := Import(ImportParams{
+		EngineConfig: s.engineConfig,
+		FileSystem:   s.fs,
+		FileData:     data,
+		Logger:       core.NewNopLogger(),
+		Decryption:   &DecryptionOptions{Passphrase: []byte("passphrase")},
+	})
+	s.Require().NoError(err)
+	s.Equal(1, result.InstancesCount)
+	s.NotNil(result.Envelope)
+}
+
+func (s *StateExportTestSuite) Test_exports_compressed_by_file_extension() {
+	s.seedInstances([]state.InstanceState{
+		{InstanceID: "inst-001", InstanceName: "Instance 1", Status: core.InstanceStatusDeployed},
+		{InstanceID: "inst-002", InstanceName: "Instance 2", Status: core.InstanceStatusDeployed},
+	})
+
+	_, err := Export(ExportParams{
+		FilePath:     "/test/export.json.gz",
+		EngineConfig: s.engineConfig,
+		FileSystem:   s.fs,
+		Logger:       core.NewNopLogger(),
+	})
+	s.Require().NoError(err)
+
+	data, err := afero.ReadFile(s.fs, "/test/export.json.gz")
+	s.Require().NoError(err)
+	s.Equal(gzipMagic, data[:2])
+
+	result, err := Import(ImportParams{
+		EngineConfig: s.engineConfig,
+		FileSystem:   s.fs,
+		FileData:     data,
+		Logger:       core.NewNopLogger(),
+	})
+	s.Require().NoError(err)
+	s.Equal(2, result.InstancesCount)
+}
+
+func (s *StateExportTestSuite) Test_compresses_before_encrypting() {
+	originalWorkFactor := scryptWorkFactor
+	scryptWorkFactor = 10
+	defer func() { scryptWorkFactor = originalWorkFactor }()
+
+	s.seedInstances([]state.InstanceState{
+		{InstanceID: "inst-001", InstanceName: "Instance 1", Status: core.InstanceStatusDeployed},
+	})
+
+	_, err := Export(ExportParams{
+		FilePath:     "/test/export.json.enc",
+		EngineConfig: s.engineConfig,
+		FileSystem:   s.fs,
+		Logger:       core.NewNopLogger(),
+		Compression:  CompressionZstd,
+		Encryption:   &EncryptionOptions{Passphrase: []byte("passphrase")},
+	})
+	s.Require().NoError(err)
+
+	data, err := afero.ReadFile(s.fs, "/test/export.json.enc")
+	s.Require().NoError(err)
+	decrypted, err := NewDecryptReader(bytes.NewReader(data), &DecryptionOptions{Passphrase: []byte("passphrase")})
+	s.Require().NoError(err)
+	payload, err := io.ReadAll(decrypted)
+	s.Require().NoError(err)
+	s.Equal(zstdMagic, payload[:4])
+
+	result, err := Import(ImportParams{
+		EngineConfig: s.engineConfig,
+		FileSystem:   s.fs,
+		FileData:     data,
+		Logger:       core.NewNopLogger(),
+		Decryption:   &DecryptionOptions{Passphrase: []byte("passphrase")},
+	})
+	s.Require().NoError(err)
+	s.Equal(1, result.InstancesCount)
+}
+
 func TestStateExportTestSuite(t *testing.T) {
 	suite.Run(t, new(StateExportTestSuite))
 }
@@ -20,6 +20,26 @@ type StateExporter interface {
 	ExportInstances(ctx context.Context, instanceFilters []string) ([]state.InstanceState, error)
 }
 
+// DefaultExportPageSize is the number of instances loaded from the state container
+// at a time when streaming an export.
+const DefaultExportPageSize = 100
+
+// StreamingStateExporter is implemented by exporters that can load instances
+// a page at a time so that an export never needs to hold
+// every instance in memory.
+type StreamingStateExporter interface {
+	StateExporter
+	// StreamInstances calls emit for each instance matching the given IDs or names,
+	// or for every instance if instanceFilters is empty,
+	// loading at most pageSize instances at a time.
+	StreamInstances(
+		ctx context.Context,
+		instanceFilters []string,
+		pageSize int,
+		emit func(state.InstanceState) error,
+	) error
+}
+
 // ContainerStateExporter implements StateExporter using a state.Container.
 // This works with any backend that implements the state.Container interface.
 type ContainerStateExporter struct {
@@ -37,15 +57,52 @@ func (e *ContainerStateExporter) ExportInstances(
 	ctx context.Context,
 	instanceFilters []string,
 ) ([]state.InstanceState, error) {
+	instances := []state.InstanceState{}
+	err := e.StreamInstances(
+		ctx,
+		instanceFilters,
+		DefaultExportPageSize,
+		func(instance state.InstanceState) error {
+			instances = append(instances, instance)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return instances, nil
+}
+
+// StreamInstances pages through the container's instances, retrieving each page
+// with the container's batch get operation before passing the instances to emit.
+// If instanceFilters is empty, all instances are streamed.
+func (e *ContainerStateExporter) StreamInstances(
+	ctx context.Context,
+	instanceFilters []string,
+	pageSize int,
+	emit func(state.InstanceState) error,
+) error {
+	if pageSize <= 0 {
+		pageSize = DefaultExportPageSize
+	}
+
 	if len(instanceFilters) == 0 {
-		return e.exportAllInstances(ctx)
+		return e.streamAllInstances(ctx, pageSize, emit)
 	}
 
-	instances, err := e.container.Instances().GetBatch(ctx, instanceFilters)
-	if err != nil {
-		return nil, createInstanceNotFoundError(err)
+	for start := 0; start < len(instanceFilters); start += pageSize {
+		end := min(start+pageSize, len(instanceFilters))
+		instances, err := e.container.Instances().GetBatch(ctx, instanceFilters[start:end])
+		if err != nil {
+			return createInstanceNotFoundError(err)
+		}
+
+		if err := emitInstances(instances, emit); err != nil {
+			return err
+		}
 	}
-	return instances, nil
+
+	return nil
 }
 
 func createInstanceNotFoundError(err error) *ExportError {
@@ -77,35 +134,61 @@ func formatMissingInstancesMessage(missing []string) string {
 	return fmt.Sprintf("instances not found: %s", strings.Join(quoted, ", "))
 }
 
-func (e *ContainerStateExporter) exportAllInstances(ctx context.Context) ([]state.InstanceState, error) {
-	result, err := e.container.Instances().List(ctx, state.ListInstancesParams{Limit: 0})
-	if err != nil {
-		return nil, &ExportError{
-			Code:    ErrCodeExportFailed,
-			Message: "failed to list instances",
-			Err:     err,
+func (e *ContainerStateExporter) streamAllInstances(
+	ctx context.Context,
+	pageSize int,
+	emit func(state.InstanceState) error,
+) error {
+	offset := 0
+	for {
+		page, err := e.container.Instances().List(ctx, state.ListInstancesParams{
+			Offset: offset,
+			Limit:  pageSize,
+		})
+		if err != nil {
+			return &ExportError{
+				Code:    ErrCodeExportFailed,
+				Message: "failed to list instances",
+				Err:     err,
+			}
 		}
-	}
 
-	if len(result.Instances) == 0 {
-		return []state.InstanceState{}, nil
-	}
+		if len(page.Instances) == 0 {
+			return nil
+		}
 
-	ids := make([]string, len(result.Instances))
-	for i, summary := range result.Instances {
-		ids[i] = summary.InstanceID
-	}
+		ids := make([]string, len(page.Instances))
+		for i, summary := range page.Instances {
+			ids[i] = summary.InstanceID
+		}
 
-	instances, err := e.container.Instances().GetBatch(ctx, ids)
-	if err != nil {
-		return nil, &ExportError{
-			Code:    ErrCodeExportFailed,
-			Message: "failed to retrieve instances",
-			Err:     err,
+		instances, err := e.container.Instances().GetBatch(ctx, ids)
+		if err != nil {
+			return &ExportError{
+				Code:    ErrCodeExportFailed,
+				Message: "failed to retrieve instances",
+				Err:     err,
+			}
+		}
+
+		if err := emitInstances(instances, emit); err != nil {
+			return err
+		}
+
+		offset += len(page.Instances)
+		if offset >= page.TotalCount {
+			return nil
 		}
 	}
+}
 
-	return instances, nil
+func emitInstances(instances []state.InstanceState, emit func(state.InstanceState) error) error {
+	for _, instance := range instances {
+		if err := emit(instance); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // SerializeInstancesJSON serializes instances to a JSON byte array.
@@ -148,3 +231,38 @@ func ExecuteInstancesExport(
 		Data:           data,
 	}, nil
 }
+
+// ExecuteInstancesExportStream performs the instances export using the provided exporter,
+// passing instances to jsonWriter as they are loaded.
+// Exporters that implement StreamingStateExporter are paged through with the given
+// page size, others are loaded in full before being written.
+// Returns the number of instances written.
+func ExecuteInstancesExportStream(
+	ctx context.Context,
+	exporter StateExporter,
+	instanceFilters []string,
+	pageSize int,
+	jsonWriter InstancesWriter,
+) (int, error) {
+	streamingExporter, isStreaming := exporter.(StreamingStateExporter)
+	if isStreaming {
+		err := streamingExporter.StreamInstances(ctx, instanceFilters, pageSize, jsonWriter.WriteInstance)
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		instances, err := exporter.ExportInstances(ctx, instanceFilters)
+		if err != nil {
+			return 0, err
+		}
+		if err := emitInstances(instances, jsonWriter.WriteInstance); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := jsonWriter.Close(); err != nil {
+		return 0, err
+	}
+
+	return jsonWriter.Count(), nil
+}
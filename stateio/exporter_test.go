@@ -1,6 +1,7 @@
 package stateio
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"testing"
@@ -269,6 +270,51 @@ func (s *ExporterTestSuite) Test_ExecuteInstancesExport_with_filter() {
 	s.Equal("inst-002", parsed[0].InstanceID)
 }
 
+func (s *ExporterTestSuite) Test_ContainerStateExporter_streams_instances_a_page_at_a_time() {
+	for _, id := range []string{"inst-001", "inst-002", "inst-003"} {
+		s.seedInstance(state.InstanceState{
+			InstanceID:   id,
+			InstanceName: "Instance " + id,
+			Status:       core.InstanceStatusDeployed,
+		})
+	}
+
+	exporter := NewContainerStateExporter(s.container)
+	ids := []string{}
+	err := exporter.StreamInstances(context.Background(), nil, 2, func(instance state.InstanceState) error {
+		ids = append(ids, instance.InstanceID)
+		return nil
+	})
+
+	s.Require().NoError(err)
+	s.Equal([]string{"inst-001", "inst-002", "inst-003"}, ids)
+}
+
+func (s *ExporterTestSuite) Test_ExecuteInstancesExportStream_matches_serialized_output() {
+	s.seedInstance(state.InstanceState{
+		InstanceID:   "inst-001",
+		InstanceName: "Instance One",
+		Status:       core.InstanceStatusDeployed,
+	})
+	s.seedInstance(state.InstanceState{
+		InstanceID:   "inst-002",
+		InstanceName: "Instance Two",
+		Status:       core.InstanceStatusDeployed,
+	})
+
+	exporter := NewContainerStateExporter(s.container)
+	ctx := context.Background()
+	output := &bytes.Buffer{}
+
+	count, err := ExecuteInstancesExportStream(ctx, exporter, nil, 1, NewInstancesJSONWriter(output))
+
+	s.Require().NoError(err)
+	s.Equal(2, count)
+	result, err := ExecuteInstancesExport(ctx, exporter, nil)
+	s.Require().NoError(err)
+	s.Equal(string(result.Data), output.String())
+}
+
 func TestExporterTestSuite(t *testing.T) {
 	suite.Run(t, new(ExporterTestSuite))
 }
@@ -1,8 +1,10 @@
 package stateio
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -31,37 +33,84 @@ type ImportParams struct {
 	// Importer is an optional StateImporter for import.
 	// If not provided, a default importer will be created based on EngineConfig.
 	Importer StateImporter
+	// BatchSize is the number of instances to save at a time.
+	// If not set, DefaultImportBatchSize is used.
+	BatchSize int
+	// Decryption holds the passphrase or identities used to decrypt
+	// encrypted exports, unencrypted exports are imported as is.
+	Decryption *DecryptionOptions
+	// OnConflict determines how instances that already exist in the state
+	// with a different state are handled.
+	// When not set, instances are saved without checking the state,
+	// replacing any existing instances and no per instance results are reported.
+	OnConflict ConflictStrategy
+	// DryRun compares the instances in the import with the state
+	// without saving anything, reporting what the import would do.
+	DryRun bool
 }
 
 // ImportResult contains the result of an import operation.
 type ImportResult struct {
-	Success        bool   `json:"success"`
+	Success bool `json:"success"`
+	// InstancesCount is the number of instances saved,
+	// or for dry runs, the number of instances in the import.
 	InstancesCount int    `json:"instancesCount,omitempty"`
 	Message        string `json:"message"`
+	// DryRun is true when nothing was saved.
+	DryRun bool `json:"dryRun,omitempty"`
+	// Summary holds the number of instances for each action taken,
+	// only set when the import checks for conflicts or is a dry run.
+	Summary *ImportSummary `json:"summary,omitempty"`
+	// Instances holds the outcome for each instance,
+	// only set when the import checks for conflicts or is a dry run.
+	Instances []InstanceImportResult `json:"instances,omitempty"`
+	// Envelope holds the metadata of the imported export
+	// when it was written in the envelope format.
+	Envelope *ExportEnvelope `json:"envelope,omitempty"`
 }
 
 // Import performs a state import operation based on the provided parameters.
-// The input file must be a JSON array of blueprint instances.
+// The input file must be a JSON array of blueprint instances or an export envelope,
+// instances are read from the file and saved in batches as the file is streamed.
+// Envelopes with an incompatible major format version are rejected and envelopes
+// are verified against their checksums, instance count and digest before any
+// instances are saved.
+// Encrypted exports are detected and decrypted with params.Decryption and
+// exports compressed with gzip or zstd are detected and decompressed.
+// When params.OnConflict is set or params.DryRun is true, each batch of instances
+// is compared with the state before it is saved, see ConflictStrategy.
+// With the ConflictFail strategy, the whole input is compared with the state
+// before any instances are saved.
+// Verifying the input before saving reads the input file twice, the input
+// is decrypted each time it is read and is never written to disk.
 func Import(params ImportParams) (*ImportResult, error) {
 	if params.FileSystem == nil {
 		params.FileSystem = afero.NewOsFs()
 	}
 
-	data, err := getInputData(params)
+	open := func() (io.ReadCloser, error) {
+		return openDecryptedInput(params)
+	}
+	input, err := open()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read input file: %w", err)
+		return nil, err
 	}
 
 	importer := params.Importer
 	if importer == nil {
 		importer, err = createDefaultImporter(params)
 		if err != nil {
+			input.Close()
 			return nil, err
 		}
 	}
 
+	if params.OnConflict != "" || params.DryRun {
+		return importCheckingConflicts(params, importer, input, open)
+	}
+
 	ctx := context.Background()
-	result, err := ExecuteInstancesImport(ctx, importer, data)
+	result, err := importFromSource(ctx, importer, input, open, params.BatchSize)
 	if err != nil {
 		return nil, err
 	}
@@ -70,19 +119,168 @@ func Import(params ImportParams) (*ImportResult, error) {
 		Success:        true,
 		InstancesCount: result.InstancesCount,
 		Message:        fmt.Sprintf("Successfully imported %d instances", result.InstancesCount),
+		Envelope:       result.Envelope,
 	}, nil
 }
 
-func getInputData(params ImportParams) ([]byte, error) {
+func importCheckingConflicts(
+	params ImportParams,
+	importer StateImporter,
+	input io.ReadCloser,
+	open func() (io.ReadCloser, error),
+) (*ImportResult, error) {
+	checker, err := newConflictCheckingImporter(importer, params.OnConflict, params.DryRun)
+	if err != nil {
+		input.Close()
+		return nil, err
+	}
+
+	ctx := context.Background()
+	var result *ImportInstancesResult
+	switch {
+	case params.DryRun:
+		// Nothing is saved in a dry run, so the input is only read once.
+		defer input.Close()
+		result, err = importInstancesStream(ctx, checker, input, params.BatchSize)
+	case params.OnConflict == ConflictFail:
+		result, err = importAfterConflictCheck(params, importer, checker, input, open)
+	default:
+		result, err = importFromSource(ctx, checker, input, open, params.BatchSize)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	summary := checker.summary
+	importResult := &ImportResult{
+		Success:        true,
+		InstancesCount: summary.Created + summary.Overwritten,
+		DryRun:         params.DryRun,
+		Summary:        &summary,
+		Instances:      checker.results,
+		Envelope:       result.Envelope,
+	}
+
+	if params.DryRun {
+		importResult.InstancesCount = result.InstancesCount
+		importResult.Message = fmt.Sprintf(
+			"Dry run of %d instances, nothing was saved: %s",
+			result.InstancesCount,
+			formatImportSummary(summary),
+		)
+		return importResult, nil
+	}
+
+	importResult.Message = fmt.Sprintf(
+		"Successfully imported %d instances: %s",
+		importResult.InstancesCount,
+		formatImportSummary(summary),
+	)
+	return importResult, nil
+}
+
+// importAfterConflictCheck compares all of the instances in the already opened input
+// with the state without saving anything before opening the input again to import
+// the instances, so imports with the ConflictFail strategy fail before any instances
+// are saved. Envelopes are verified in full as the instances are compared.
+func importAfterConflictCheck(
+	params ImportParams,
+	importer StateImporter,
+	checker *conflictCheckingImporter,
+	checkInput io.ReadCloser,
+	open func() (io.ReadCloser, error),
+) (*ImportInstancesResult, error) {
+	err := checkForConflicts(params, importer, checkInput)
+	checkInput.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := open()
+	if err != nil {
+		return nil, err
+	}
+	defer input.Close()
+
+	return importInstancesStream(context.Background(), checker, input, params.BatchSize)
+}
+
+func checkForConflicts(params ImportParams, importer StateImporter, input io.Reader) error {
+	checker, err := newConflictCheckingImporter(importer, ConflictFail, true)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if _, err := importInstancesStream(ctx, checker, input, params.BatchSize); err != nil {
+		return err
+	}
+
+	conflicts := []string{}
+	for _, result := range checker.results {
+		if result.Action == InstanceImportConflict {
+			conflicts = append(conflicts, result.InstanceID)
+		}
+	}
+	if len(conflicts) > 0 {
+		return createConflictError(conflicts)
+	}
+	return nil
+}
+
+func formatImportSummary(summary ImportSummary) string {
+	message := fmt.Sprintf(
+		"%d created, %d overwritten, %d skipped, %d unchanged",
+		summary.Created,
+		summary.Overwritten,
+		summary.Skipped,
+		summary.Unchanged,
+	)
+	if summary.Conflicts > 0 {
+		message += fmt.Sprintf(", %d conflicts", summary.Conflicts)
+	}
+	return message
+}
+
+func openInputData(params ImportParams) (io.ReadCloser, error) {
 	if params.FileData != nil {
-		return params.FileData, nil
+		return io.NopCloser(bytes.NewReader(params.FileData)), nil
 	}
 
 	if IsRemoteFile(params.FilePath) {
-		return DownloadRemoteFile(context.Background(), params.FilePath, params.RemoteOptions)
+		return OpenRemoteFile(context.Background(), params.FilePath, params.RemoteOptions)
+	}
+
+	return os.Open(params.FilePath)
+}
+
+// openDecryptedInput opens the input file and decrypts it as it is read,
+// closing the returned reader closes the input file.
+func openDecryptedInput(params ImportParams) (io.ReadCloser, error) {
+	input, err := openInputData(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input file: %w", err)
 	}
 
-	return os.ReadFile(params.FilePath)
+	decrypted, err := NewDecryptReader(input, params.Decryption)
+	if err != nil {
+		input.Close()
+		return nil, inputError(err)
+	}
+
+	return &decryptedInput{Reader: decrypted, Closer: input}, nil
+}
+
+type decryptedInput struct {
+	io.Reader
+	io.Closer
+}
+
+func inputError(err error) error {
+	if _, isImportErr := err.(*ImportError); isImportErr {
+		return err
+	}
+	return fmt.Errorf("failed to read input file: %w", err)
 }
 
 func createDefaultImporter(params ImportParams) (StateImporter, error) {
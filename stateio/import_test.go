@@ -1,9 +1,11 @@
 package stateio
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"os"
 	"testing"
 
 	"github.com/newstack-cloud/bluelink/libs/blueprint-state/memfile"
@@ -277,6 +279,131 @@ func (s *StateImportTestSuite) Test_import_can_lookup_instance_by_name() {
 	s.Equal("inst-001", instanceID)
 }
 
+func (s *StateImportTestSuite) Test_imports_instances_in_batches() {
+	instances := []state.InstanceState{
+		{InstanceID: "inst-001", InstanceName: "Instance 1", Status: core.InstanceStatusDeployed},
+		{InstanceID: "inst-002", InstanceName: "Instance 2", Status: core.InstanceStatusDeployed},
+		{InstanceID: "inst-003", InstanceName: "Instance 3", Status: core.InstanceStatusDeployed},
+	}
+	jsonData, err := json.Marshal(instances)
+	s.Require().NoError(err)
+	importer := &batchRecordingImporter{}
+
+	result, err := Import(ImportParams{
+		FileData:  jsonData,
+		Importer:  importer,
+		BatchSize: 2,
+	})
+
+	s.Require().NoError(err)
+	s.Equal(3, result.InstancesCount)
+	s.Equal([][]string{{"inst-001", "inst-002"}, {"inst-003"}}, importer.batches)
+}
+
+func (s *StateImportTestSuite) Test_imports_verified_envelopes_in_batches() {
+	importer := &batchRecordingImporter{}
+
+	result, err := Import(ImportParams{
+		FileData:  s.writeEnvelope("inst-001", "inst-002", "inst-003"),
+		Importer:  importer,
+		BatchSize: 2,
+	})
+
+	s.Require().NoError(err)
+	s.Equal(3, result.InstancesCount)
+	s.Require().NotNil(result.Envelope)
+	s.Equal([][]string{{"inst-001", "inst-002"}, {"inst-003"}}, importer.batches)
+}
+
+func (s *StateImportTestSuite) Test_saves_nothing_from_truncated_envelopes() {
+	data := s.writeEnvelope("inst-001", "inst-002", "inst-003")
+	envelope := map[string]json.RawMessage{}
+	s.Require().NoError(json.Unmarshal(data, &envelope))
+	instances := []json.RawMessage{}
+	s.Require().NoError(json.Unmarshal(envelope["instances"], &instances))
+	truncatedInstances, err := json.Marshal(instances[:2])
+	s.Require().NoError(err)
+	envelope["instances"] = truncatedInstances
+	truncated, err := json.Marshal(envelope)
+	s.Require().NoError(err)
+
+	// Imports open the input again once it has been verified.
+	importer := &batchRecordingImporter{}
+	_, err = Import(ImportParams{
+		FileData:  truncated,
+		Importer:  importer,
+		BatchSize: 1,
+	})
+	importErr := &ImportError{}
+	s.Require().ErrorAs(err, &importErr)
+	s.Equal(ErrCodeChecksumMismatch, importErr.Code)
+	s.Empty(importer.batches)
+
+	_, err = ExecuteInstancesImportStream(context.Background(), importer, bytes.NewReader(truncated), 1)
+	s.Require().ErrorAs(err, &importErr)
+	s.Equal(ErrCodeChecksumMismatch, importErr.Code)
+	s.Empty(importer.batches)
+}
+
+func (s *StateImportTestSuite) Test_never_writes_decrypted_exports_to_disk() {
+	tempDir := s.T().TempDir()
+	s.T().Setenv("TMPDIR", tempDir)
+
+	encrypted := &bytes.Buffer{}
+	writer, err := NewEncryptWriter(encrypted, &EncryptionOptions{Passphrase: []byte("passphrase")})
+	s.Require().NoError(err)
+	_, err = writer.Write(s.writeEnvelope("inst-001", "inst-002"))
+	s.Require().NoError(err)
+	s.Require().NoError(writer.Close())
+
+	for _, onConflict := range []ConflictStrategy{ConflictFail, ""} {
+		result, err := Import(ImportParams{
+			FileData:     encrypted.Bytes(),
+			EngineConfig: s.engineConfig,
+			FileSystem:   s.fs,
+			Logger:       core.NewNopLogger(),
+			Decryption:   &DecryptionOptions{Passphrase: []byte("passphrase")},
+			OnConflict:   onConflict,
+			BatchSize:    1,
+		})
+		s.Require().NoError(err)
+		s.Equal(2, result.InstancesCount)
+
+		tempFiles, err := os.ReadDir(tempDir)
+		s.Require().NoError(err)
+		s.Empty(tempFiles)
+	}
+}
+
+func (s *StateImportTestSuite) writeEnvelope(instanceIDs ...string) []byte {
+	output := &bytes.Buffer{}
+	writer := NewEnvelopeJSONWriter(output, ExportEnvelope{
+		Source: ExportSource{StorageEngine: StorageEngineMemfile},
+	})
+	for _, instanceID := range instanceIDs {
+		s.Require().NoError(writer.WriteInstance(state.InstanceState{
+			InstanceID: instanceID,
+			Status:     core.InstanceStatusDeployed,
+		}))
+	}
+	s.Require().NoError(writer.Close())
+	return output.Bytes()
+}
+
+// batchRecordingImporter records the IDs of the instances in each batch it imports.
+type batchRecordingImporter struct {
+	batches [][]string
+}
+
+func (i *batchRecordingImporter) ImportInstances(ctx context.Context, instances []state.InstanceState) error {
+	ids := []string{}
+	for _, instance := range instances {
+		ids = append(ids, instance.InstanceID)
+	}
+	i.batches = append(i.batches, ids)
+	return nil
+}
+
 func TestStateImportTestSuite(t *testing.T) {
 	suite.Run(t, new(StateImportTestSuite))
 }
@@ -3,6 +3,7 @@ package stateio
 import (
 	"context"
 	"encoding/json"
+	"io"
 
 	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
 )
@@ -47,17 +48,29 @@ func ParseInstancesJSON(data []byte) ([]state.InstanceState, error) {
 	return instances, nil
 }
 
+// DefaultImportBatchSize is the number of instances saved at a time
+// when streaming an import.
+const DefaultImportBatchSize = 100
+
 // ImportInstancesResult contains the result of an instances import.
 type ImportInstancesResult struct {
 	InstancesCount int
+	// Envelope holds the verified metadata of exports in the envelope format.
+	Envelope *ExportEnvelope
 }
 
 // ExecuteInstancesImport performs the instances import using the provided importer.
+// Data compressed with gzip or zstd is decompressed before it is parsed.
 func ExecuteInstancesImport(
 	ctx context.Context,
 	importer StateImporter,
 	data []byte,
 ) (*ImportInstancesResult, error) {
+	data, err := decompressData(data)
+	if err != nil {
+		return nil, err
+	}
+
 	instances, err := ParseInstancesJSON(data)
 	if err != nil {
 		return nil, err
@@ -71,3 +84,128 @@ func ExecuteInstancesImport(
 		InstancesCount: len(instances),
 	}, nil
 }
+
+// ExecuteInstancesImportStream performs the instances import using the provided importer,
+// decoding instances from r one at a time and importing them in batches of batchSize
+// so the full set of instances never has to be held in memory.
+// The input can be a bare JSON array of instances or an export envelope,
+// see DecodeExportJSON for how envelopes are verified.
+// When r can be seeked, envelopes are verified in full before any instances are
+// imported, other input is only read once and never written to disk so envelopes
+// are verified as they are imported, use ExecuteInstancesImportFromSource to
+// verify envelopes from such input before any instances are imported.
+// Input compressed with gzip or zstd is detected and decompressed.
+// If batchSize is not set, DefaultImportBatchSize is used.
+// Batches imported before an error is encountered later in the input
+// or by the importer are not rolled back.
+func ExecuteInstancesImportStream(
+	ctx context.Context,
+	importer StateImporter,
+	r io.Reader,
+	batchSize int,
+) (*ImportInstancesResult, error) {
+	seeker, isSeeker := r.(io.ReadSeeker)
+	if !isSeeker {
+		return importInstancesStream(ctx, importer, r, batchSize)
+	}
+
+	start, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, inputError(err)
+	}
+	if err := verifyEnvelope(seeker); err != nil {
+		return nil, err
+	}
+	if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+		return nil, inputError(err)
+	}
+
+	return importInstancesStream(ctx, importer, seeker, batchSize)
+}
+
+// ExecuteInstancesImportFromSource performs the instances import in the same way
+// as ExecuteInstancesImportStream for input that can be opened more than once,
+// such as a local or remote file.
+// The input is opened and read once to verify envelopes in full without holding
+// on to any of it and opened again to import the instances, so envelopes are
+// verified before any instances are imported without the input being written to disk.
+func ExecuteInstancesImportFromSource(
+	ctx context.Context,
+	importer StateImporter,
+	open func() (io.ReadCloser, error),
+	batchSize int,
+) (*ImportInstancesResult, error) {
+	verifyInput, err := open()
+	if err != nil {
+		return nil, err
+	}
+	return importFromSource(ctx, importer, verifyInput, open, batchSize)
+}
+
+// importFromSource verifies envelopes in the already opened input before
+// opening the input again to import the instances, the opened input is closed.
+func importFromSource(
+	ctx context.Context,
+	importer StateImporter,
+	verifyInput io.ReadCloser,
+	open func() (io.ReadCloser, error),
+	batchSize int,
+) (*ImportInstancesResult, error) {
+	err := verifyEnvelope(verifyInput)
+	verifyInput.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := open()
+	if err != nil {
+		return nil, err
+	}
+	defer input.Close()
+
+	return importInstancesStream(ctx, importer, input, batchSize)
+}
+
+func importInstancesStream(
+	ctx context.Context,
+	importer StateImporter,
+	r io.Reader,
+	batchSize int,
+) (*ImportInstancesResult, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultImportBatchSize
+	}
+
+	input, err := NewDecompressReader(r)
+	if err != nil {
+		return nil, inputError(err)
+	}
+	defer input.Close()
+
+	batch := make([]state.InstanceState, 0, batchSize)
+	decoded, err := DecodeExportJSON(input, func(instance state.InstanceState) error {
+		batch = append(batch, instance)
+		if len(batch) < batchSize {
+			return nil
+		}
+
+		err := importer.ImportInstances(ctx, batch)
+		// Importers may hold on to the batch, so each batch gets its own slice.
+		batch = make([]state.InstanceState, 0, batchSize)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(batch) > 0 {
+		if err := importer.ImportInstances(ctx, batch); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ImportInstancesResult{
+		InstancesCount: decoded.InstancesCount,
+		Envelope:       decoded.Envelope,
+	}, nil
+}
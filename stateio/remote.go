@@ -38,6 +38,29 @@ type RemoteDownloadOptions struct {
 // DownloadRemoteFile downloads a file from a remote storage location.
 // Supports s3://, gcs://, and azureblob:// URL schemes.
 func DownloadRemoteFile(ctx context.Context, filePath string, opts *RemoteDownloadOptions) ([]byte, error) {
+	reader, err := OpenRemoteFile(ctx, filePath, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, &ImportError{
+			Code:    ErrCodeRemoteAccessFail,
+			Message: fmt.Sprintf("failed to read remote file: %s", filePath),
+			Err:     err,
+		}
+	}
+
+	return data, nil
+}
+
+// OpenRemoteFile opens a file in a remote storage location for reading,
+// allowing large files to be processed without downloading them into memory first.
+// The caller is responsible for closing the returned reader.
+// Supports s3://, gcs://, and azureblob:// URL schemes.
+func OpenRemoteFile(ctx context.Context, filePath string, opts *RemoteDownloadOptions) (io.ReadCloser, error) {
 	if opts == nil {
 		opts = &RemoteDownloadOptions{}
 	}
@@ -45,11 +68,11 @@ func DownloadRemoteFile(ctx context.Context, filePath string, opts *RemoteDownlo
 	source := shared.BlueprintSourceFromPath(filePath)
 	switch source {
 	case consts.BlueprintSourceS3:
-		return downloadFromS3(ctx, filePath, opts)
+		return openFromS3(ctx, filePath, opts)
 	case consts.BlueprintSourceGCS:
-		return downloadFromGCS(ctx, filePath, opts)
+		return openFromGCS(ctx, filePath, opts)
 	case consts.BlueprintSourceAzureBlob:
-		return downloadFromAzureBlob(ctx, filePath, opts)
+		return openFromAzureBlob(ctx, filePath, opts)
 	default:
 		return nil, &ImportError{
 			Code:    ErrCodeFileNotFound,
@@ -66,20 +89,14 @@ func IsRemoteFile(filePath string) bool {
 		source == consts.BlueprintSourceAzureBlob
 }
 
-func downloadFromS3(ctx context.Context, filePath string, opts *RemoteDownloadOptions) ([]byte, error) {
+func openFromS3(ctx context.Context, filePath string, opts *RemoteDownloadOptions) (io.ReadCloser, error) {
 	pathWithoutScheme := shared.StripObjectStorageScheme(filePath, "s3")
 	bucket, key, err := parseS3Path(pathWithoutScheme)
 	if err != nil {
 		return nil, err
 	}
 
-	configOpts := []func(*awsconfig.LoadOptions) error{}
-	if opts.S3Endpoint != "" {
-		// When using a custom endpoint (e.g., LocalStack), set a default region
-		configOpts = append(configOpts, awsconfig.WithRegion("us-east-1"))
-	}
-
-	conf, err := awsconfig.LoadDefaultConfig(ctx, configOpts...)
+	client, err := loadS3Client(ctx, opts.S3Endpoint, opts.S3UsePathStyle)
 	if err != nil {
 		return nil, &ImportError{
 			Code:    ErrCodeRemoteAccessFail,
@@ -88,7 +105,6 @@ func downloadFromS3(ctx context.Context, filePath string, opts *RemoteDownloadOp
 		}
 	}
 
-	client := createS3Client(conf, opts.S3Endpoint, opts.S3UsePathStyle)
 	output, err := client.GetObject(ctx, &s3.GetObjectInput{
 		Bucket: &bucket,
 		Key:    &key,
@@ -107,9 +123,23 @@ func downloadFromS3(ctx context.Context, filePath string, opts *RemoteDownloadOp
 			Err:     err,
 		}
 	}
-	defer output.Body.Close()
 
-	return io.ReadAll(output.Body)
+	return output.Body, nil
+}
+
+func loadS3Client(ctx context.Context, endpoint string, usePathStyle bool) (*s3.Client, error) {
+	configOpts := []func(*awsconfig.LoadOptions) error{}
+	if endpoint != "" {
+		// When using a custom endpoint (e.g., LocalStack), set a default region
+		configOpts = append(configOpts, awsconfig.WithRegion("us-east-1"))
+	}
+
+	conf, err := awsconfig.LoadDefaultConfig(ctx, configOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return createS3Client(conf, endpoint, usePathStyle), nil
 }
 
 func createS3Client(conf aws.Config, endpoint string, usePathStyle bool) *s3.Client {
@@ -136,7 +166,7 @@ func parseS3Path(pathWithoutScheme string) (bucket, key string, err error) {
 	return parts[0], parts[1], nil
 }
 
-func downloadFromGCS(ctx context.Context, filePath string, opts *RemoteDownloadOptions) ([]byte, error) {
+func openFromGCS(ctx context.Context, filePath string, opts *RemoteDownloadOptions) (io.ReadCloser, error) {
 	pathWithoutScheme := shared.StripObjectStorageScheme(filePath, "gcs")
 	bucket, object, err := parseGCSPath(pathWithoutScheme)
 	if err != nil {
@@ -151,10 +181,10 @@ func downloadFromGCS(ctx context.Context, filePath string, opts *RemoteDownloadO
 			Err:     err,
 		}
 	}
-	defer client.Close()
 
 	reader, err := client.Bucket(bucket).Object(object).NewReader(ctx)
 	if err != nil {
+		client.Close()
 		if err == storage.ErrObjectNotExist {
 			return nil, &ImportError{
 				Code:    ErrCodeFileNotFound,
@@ -167,9 +197,20 @@ func downloadFromGCS(ctx context.Context, filePath string, opts *RemoteDownloadO
 			Err:     err,
 		}
 	}
-	defer reader.Close()
 
-	return io.ReadAll(reader)
+	return &gcsObjectReader{Reader: reader, client: client}, nil
+}
+
+// gcsObjectReader closes the GCS client along with the object reader.
+type gcsObjectReader struct {
+	*storage.Reader
+	client *storage.Client
+}
+
+func (r *gcsObjectReader) Close() error {
+	readerErr := r.Reader.Close()
+	clientErr := r.client.Close()
+	return errors.Join(readerErr, clientErr)
 }
 
 func createGCSClient(ctx context.Context, endpoint string) (*storage.Client, error) {
@@ -190,7 +231,7 @@ func parseGCSPath(pathWithoutScheme string) (bucket, object string, err error) {
 	return parts[0], parts[1], nil
 }
 
-func downloadFromAzureBlob(ctx context.Context, filePath string, opts *RemoteDownloadOptions) ([]byte, error) {
+func openFromAzureBlob(ctx context.Context, filePath string, opts *RemoteDownloadOptions) (io.ReadCloser, error) {
 	pathWithoutScheme := shared.StripObjectStorageScheme(filePath, "azureblob")
 	container, blob, err := parseAzureBlobPath(pathWithoutScheme)
 	if err != nil {
@@ -222,18 +263,7 @@ func downloadFromAzureBlob(ctx context.Context, filePath string, opts *RemoteDow
 		}
 	}
 
-	downloadedData := bytes.Buffer{}
-	retryReader := stream.NewRetryReader(ctx, &azblob.RetryReaderOptions{})
-	_, err = downloadedData.ReadFrom(retryReader)
-	if err != nil {
-		return nil, &ImportError{
-			Code:    ErrCodeRemoteAccessFail,
-			Message: "failed to read Azure Blob data",
-			Err:     err,
-		}
-	}
-
-	return downloadedData.Bytes(), nil
+	return stream.NewRetryReader(ctx, &azblob.RetryReaderOptions{}), nil
 }
 
 func createAzureBlobClient(connectionString string) (*azblob.Client, error) {
@@ -285,6 +315,71 @@ type RemoteUploadOptions struct {
 // UploadRemoteFile uploads a file to a remote storage location.
 // Supports s3://, gcs://, and azureblob:// URL schemes.
 func UploadRemoteFile(ctx context.Context, filePath string, data []byte, opts *RemoteUploadOptions) error {
+	return uploadRemoteStream(ctx, filePath, bytes.NewReader(data), opts)
+}
+
+// errRemoteUploadAborted is the error that the body of a remote upload
+// fails with when RemoteFileWriter.Abort is called.
+var errRemoteUploadAborted = errors.New("remote upload aborted")
+
+// RemoteFileWriter streams data written to it to a file in a remote storage location,
+// using a multipart upload for S3 and streaming uploads for GCS and Azure Blob Storage,
+// so large files never have to be held in memory.
+// The file is only created in remote storage once Close succeeds.
+type RemoteFileWriter struct {
+	pipe   *io.PipeWriter
+	cancel context.CancelFunc
+	done   chan error
+}
+
+// NewRemoteFileWriter starts an upload to the given remote storage location
+// that is fed by writes to the returned writer.
+// Close must be called to complete the upload or Abort to discard it.
+// Supports s3://, gcs://, and azureblob:// URL schemes.
+func NewRemoteFileWriter(ctx context.Context, filePath string, opts *RemoteUploadOptions) *RemoteFileWriter {
+	uploadCtx, cancel := context.WithCancel(ctx)
+	pipeReader, pipeWriter := io.Pipe()
+	done := make(chan error, 1)
+
+	go func() {
+		err := uploadRemoteStream(uploadCtx, filePath, pipeReader, opts)
+		// Unblock any pending writes when the upload ends early.
+		if err != nil {
+			pipeReader.CloseWithError(err)
+		} else {
+			pipeReader.Close()
+		}
+		done <- err
+	}()
+
+	return &RemoteFileWriter{
+		pipe:   pipeWriter,
+		cancel: cancel,
+		done:   done,
+	}
+}
+
+// Write passes data on to the upload, blocking until it has been consumed.
+func (w *RemoteFileWriter) Write(p []byte) (int, error) {
+	return w.pipe.Write(p)
+}
+
+// Close completes the upload and waits for it to finish.
+func (w *RemoteFileWriter) Close() error {
+	w.pipe.Close()
+	err := <-w.done
+	w.cancel()
+	return err
+}
+
+// Abort discards the upload without creating the file in remote storage.
+func (w *RemoteFileWriter) Abort() {
+	w.cancel()
+	w.pipe.CloseWithError(errRemoteUploadAborted)
+	<-w.done
+}
+
+func uploadRemoteStream(ctx context.Context, filePath string, body io.Reader, opts *RemoteUploadOptions) error {
 	if opts == nil {
 		opts = &RemoteUploadOptions{}
 	}
@@ -292,11 +387,11 @@ func UploadRemoteFile(ctx context.Context, filePath string, data []byte, opts *R
 	source := shared.BlueprintSourceFromPath(filePath)
 	switch source {
 	case consts.BlueprintSourceS3:
-		return uploadToS3(ctx, filePath, data, opts)
+		return uploadToS3(ctx, filePath, body, opts)
 	case consts.BlueprintSourceGCS:
-		return uploadToGCS(ctx, filePath, data, opts)
+		return uploadToGCS(ctx, filePath, body, opts)
 	case consts.BlueprintSourceAzureBlob:
-		return uploadToAzureBlob(ctx, filePath, data, opts)
+		return uploadToAzureBlob(ctx, filePath, body, opts)
 	default:
 		return &ExportError{
 			Code:    ErrCodeRemoteUploadFailed,
@@ -305,19 +400,18 @@ func UploadRemoteFile(ctx context.Context, filePath string, data []byte, opts *R
 	}
 }
 
-func uploadToS3(ctx context.Context, filePath string, data []byte, opts *RemoteUploadOptions) error {
+// s3UploadPartSize is the size of each part of a multipart upload to S3,
+// files smaller than a single part are uploaded with a single request.
+const s3UploadPartSize = 8 * 1024 * 1024
+
+func uploadToS3(ctx context.Context, filePath string, body io.Reader, opts *RemoteUploadOptions) error {
 	pathWithoutScheme := shared.StripObjectStorageScheme(filePath, "s3")
 	bucket, key, err := parseS3Path(pathWithoutScheme)
 	if err != nil {
 		return err
 	}
 
-	configOpts := []func(*awsconfig.LoadOptions) error{}
-	if opts.S3Endpoint != "" {
-		configOpts = append(configOpts, awsconfig.WithRegion("us-east-1"))
-	}
-
-	conf, err := awsconfig.LoadDefaultConfig(ctx, configOpts...)
+	client, err := loadS3Client(ctx, opts.S3Endpoint, opts.S3UsePathStyle)
 	if err != nil {
 		return &ExportError{
 			Code:    ErrCodeRemoteUploadFailed,
@@ -326,26 +420,134 @@ func uploadToS3(ctx context.Context, filePath string, data []byte, opts *RemoteU
 		}
 	}
 
-	client := createS3Client(conf, opts.S3Endpoint, opts.S3UsePathStyle)
+	part := make([]byte, s3UploadPartSize)
+	n, err := io.ReadFull(body, part)
+	if isEndOfUploadBody(err) {
+		return putS3Object(ctx, client, bucket, key, part[:n])
+	}
+	if err != nil {
+		return createS3UploadError(err)
+	}
+
+	return uploadS3MultipartObject(ctx, client, bucket, key, body, part)
+}
+
+func putS3Object(ctx context.Context, client *s3.Client, bucket, key string, data []byte) error {
 	contentType := "application/json"
-	_, err = client.PutObject(ctx, &s3.PutObjectInput{
+	_, err := client.PutObject(ctx, &s3.PutObjectInput{
 		Bucket:      &bucket,
 		Key:         &key,
 		Body:        bytes.NewReader(data),
 		ContentType: &contentType,
 	})
 	if err != nil {
-		return &ExportError{
-			Code:    ErrCodeRemoteUploadFailed,
-			Message: "failed to upload to S3",
-			Err:     err,
-		}
+		return createS3UploadError(err)
 	}
 
 	return nil
 }
 
-func uploadToGCS(ctx context.Context, filePath string, data []byte, opts *RemoteUploadOptions) error {
+// uploadS3MultipartObject uploads the first part that has already been read
+// followed by the rest of the body, aborting the upload if any part fails
+// so no incomplete object is left behind.
+func uploadS3MultipartObject(
+	ctx context.Context,
+	client *s3.Client,
+	bucket, key string,
+	body io.Reader,
+	firstPart []byte,
+) error {
+	contentType := "application/json"
+	upload, err := client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      &bucket,
+		Key:         &key,
+		ContentType: &contentType,
+	})
+	if err != nil {
+		return createS3UploadError(err)
+	}
+
+	completedParts, err := uploadS3Parts(ctx, client, bucket, key, upload.UploadId, body, firstPart)
+	if err == nil {
+		_, err = client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+			Bucket:   &bucket,
+			Key:      &key,
+			UploadId: upload.UploadId,
+			MultipartUpload: &s3types.CompletedMultipartUpload{
+				Parts: completedParts,
+			},
+		})
+	}
+	if err != nil {
+		// Use a fresh context as the upload context may have been cancelled.
+		client.AbortMultipartUpload(context.Background(), &s3.AbortMultipartUploadInput{
+			Bucket:   &bucket,
+			Key:      &key,
+			UploadId: upload.UploadId,
+		})
+		return createS3UploadError(err)
+	}
+
+	return nil
+}
+
+func uploadS3Parts(
+	ctx context.Context,
+	client *s3.Client,
+	bucket, key string,
+	uploadID *string,
+	body io.Reader,
+	part []byte,
+) ([]s3types.CompletedPart, error) {
+	completedParts := []s3types.CompletedPart{}
+	n := len(part)
+	var readErr error
+	for partNumber := int32(1); n > 0; partNumber++ {
+		output, err := client.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:     &bucket,
+			Key:        &key,
+			UploadId:   uploadID,
+			PartNumber: aws.Int32(partNumber),
+			Body:       bytes.NewReader(part[:n]),
+		})
+		if err != nil {
+			return nil, err
+		}
+		completedParts = append(completedParts, s3types.CompletedPart{
+			ETag:       output.ETag,
+			PartNumber: aws.Int32(partNumber),
+		})
+
+		if readErr != nil {
+			break
+		}
+		n, readErr = io.ReadFull(body, part)
+		if readErr != nil && !isEndOfUploadBody(readErr) {
+			return nil, readErr
+		}
+	}
+
+	return completedParts, nil
+}
+
+func isEndOfUploadBody(err error) bool {
+	return err == io.EOF || err == io.ErrUnexpectedEOF
+}
+
+func createS3UploadError(err error) error {
+	var exportErr *ExportError
+	if errors.As(err, &exportErr) {
+		return exportErr
+	}
+
+	return &ExportError{
+		Code:    ErrCodeRemoteUploadFailed,
+		Message: "failed to upload to S3",
+		Err:     err,
+	}
+}
+
+func uploadToGCS(ctx context.Context, filePath string, body io.Reader, opts *RemoteUploadOptions) error {
 	pathWithoutScheme := shared.StripObjectStorageScheme(filePath, "gcs")
 	bucket, object, err := parseGCSPath(pathWithoutScheme)
 	if err != nil {
@@ -362,10 +564,15 @@ func uploadToGCS(ctx context.Context, filePath string, data []byte, opts *Remote
 	}
 	defer client.Close()
 
-	writer := client.Bucket(bucket).Object(object).NewWriter(ctx)
+	// The object is only finalised when the writer is closed,
+	// cancelling the context before then discards the upload.
+	writeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	writer := client.Bucket(bucket).Object(object).NewWriter(writeCtx)
 	writer.ContentType = "application/json"
 
-	if _, err := writer.Write(data); err != nil {
+	if _, err := io.Copy(writer, body); err != nil {
+		cancel()
 		writer.Close()
 		return &ExportError{
 			Code:    ErrCodeRemoteUploadFailed,
@@ -385,7 +592,7 @@ func uploadToGCS(ctx context.Context, filePath string, data []byte, opts *Remote
 	return nil
 }
 
-func uploadToAzureBlob(ctx context.Context, filePath string, data []byte, opts *RemoteUploadOptions) error {
+func uploadToAzureBlob(ctx context.Context, filePath string, body io.Reader, opts *RemoteUploadOptions) error {
 	pathWithoutScheme := shared.StripObjectStorageScheme(filePath, "azureblob")
 	container, blobPath, err := parseAzureBlobPath(pathWithoutScheme)
 	if err != nil {
@@ -401,7 +608,9 @@ func uploadToAzureBlob(ctx context.Context, filePath string, data []byte, opts *
 		}
 	}
 
-	_, err = client.UploadBuffer(ctx, container, blobPath, data, nil)
+	// Blocks are only committed once the whole body has been read,
+	// so a failed upload does not leave a partial blob behind.
+	_, err = client.UploadStream(ctx, container, blobPath, body, nil)
 	if err != nil {
 		return &ExportError{
 			Code:    ErrCodeRemoteUploadFailed,
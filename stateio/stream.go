@@ -0,0 +1,242 @@
+package stateio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+)
+
+// InstancesWriter writes exported instances to an output one at a time.
+type InstancesWriter interface {
+	// WriteInstance encodes the given instance to the output.
+	WriteInstance(instance state.InstanceState) error
+	// Close finishes the output, it does not close the underlying writer.
+	Close() error
+	// Count returns the number of instances written so far.
+	Count() int
+}
+
+// InstancesJSONWriter writes instances to an io.Writer as an indented JSON array,
+// one instance at a time.
+// The output is identical to that of SerializeInstancesJSON, so exports
+// written with either can be imported in the same way.
+type InstancesJSONWriter struct {
+	w     io.Writer
+	count int
+}
+
+// NewInstancesJSONWriter creates a new writer that encodes instances
+// as a JSON array to the given writer.
+func NewInstancesJSONWriter(w io.Writer) *InstancesJSONWriter {
+	return &InstancesJSONWriter{w: w}
+}
+
+// WriteInstance encodes the given instance as the next element of the array.
+func (w *InstancesJSONWriter) WriteInstance(instance state.InstanceState) error {
+	data, err := json.MarshalIndent(instance, "  ", "  ")
+	if err != nil {
+		return createInstanceSerializeError(instance, err)
+	}
+
+	separator := ",\n  "
+	if w.count == 0 {
+		separator = "[\n  "
+	}
+	if err := writeChunks(w.w, []byte(separator), data); err != nil {
+		return err
+	}
+
+	w.count++
+	return nil
+}
+
+// Close ends the JSON array, it does not close the underlying writer.
+func (w *InstancesJSONWriter) Close() error {
+	if w.count == 0 {
+		return writeChunks(w.w, []byte("[]"))
+	}
+	return writeChunks(w.w, []byte("\n]"))
+}
+
+// Count returns the number of instances written so far.
+func (w *InstancesJSONWriter) Count() int {
+	return w.count
+}
+
+func createInstanceSerializeError(instance state.InstanceState, err error) error {
+	return &ExportError{
+		Code:    ErrCodeExportFailed,
+		Message: fmt.Sprintf("failed to serialize instance %q to JSON", instance.InstanceID),
+		Err:     err,
+	}
+}
+
+func writeChunks(w io.Writer, chunks ...[]byte) error {
+	for _, chunk := range chunks {
+		if _, err := w.Write(chunk); err != nil {
+			// Remote uploads that fail part way through surface
+			// their own errors to the writer.
+			var exportErr *ExportError
+			if errors.As(err, &exportErr) {
+				return exportErr
+			}
+			return &ExportError{
+				Code:    ErrCodeExportFailed,
+				Message: "failed to write instances",
+				Err:     err,
+			}
+		}
+	}
+	return nil
+}
+
+// DecodedExport contains the result of decoding an export.
+type DecodedExport struct {
+	// InstancesCount is the number of instances that were decoded.
+	InstancesCount int
+	// Envelope holds the metadata of exports written in the envelope format,
+	// it is nil for exports that are a bare JSON array of instances.
+	Envelope *ExportEnvelope
+}
+
+// DecodeExportJSON reads an export from r one instance at a time, calling handle
+// with each instance as soon as it has been decoded so the full export
+// never has to be held in memory.
+// Both exports in the envelope format and bare JSON arrays of instances are supported,
+// instances in an envelope are verified against their checksums before being handled
+// and the instance count and digest are verified once all instances have been read.
+// Errors returned by handle are returned as is.
+func DecodeExportJSON(r io.Reader, handle func(state.InstanceState) error) (*DecodedExport, error) {
+	reader := &inputReader{r: r}
+	decoder := json.NewDecoder(reader)
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, decodeError(reader, err)
+	}
+
+	var decoded *DecodedExport
+	switch token {
+	case json.Delim('{'):
+		decoded, err = decodeEnvelope(decoder, reader, handle)
+	case json.Delim('['):
+		var count int
+		count, err = decodeInstancesArray(decoder, reader, handle)
+		decoded = &DecodedExport{InstancesCount: count}
+	default:
+		err = decodeError(
+			reader,
+			fmt.Errorf("expected a JSON array of instances or an export envelope but found %v", token),
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := expectEndOfInput(decoder, reader); err != nil {
+		return nil, err
+	}
+
+	return decoded, nil
+}
+
+// DecodeInstancesJSON reads a JSON array of instances (or an export envelope)
+// from r one instance at a time, calling handle with each instance as soon as
+// it has been decoded so the full array never has to be held in memory.
+// Errors returned by handle are returned as is, along with
+// the number of instances that were handled successfully.
+func DecodeInstancesJSON(r io.Reader, handle func(state.InstanceState) error) (int, error) {
+	count := 0
+	_, err := DecodeExportJSON(r, func(instance state.InstanceState) error {
+		if err := handle(instance); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
+
+func decodeInstancesArray(
+	decoder *json.Decoder,
+	reader *inputReader,
+	handle func(state.InstanceState) error,
+) (int, error) {
+	count := 0
+	for decoder.More() {
+		var instance state.InstanceState
+		if err := decoder.Decode(&instance); err != nil {
+			return count, decodeError(reader, err)
+		}
+
+		if err := handle(instance); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	if err := expectDelim(decoder, ']'); err != nil {
+		return count, decodeError(reader, err)
+	}
+
+	return count, nil
+}
+
+func expectEndOfInput(decoder *json.Decoder, reader *inputReader) error {
+	if _, err := decoder.Token(); err != io.EOF {
+		return decodeError(
+			reader,
+			errors.New("unexpected data after the end of the export"),
+		)
+	}
+
+	return nil
+}
+
+func expectDelim(decoder *json.Decoder, expected json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	if delim, isDelim := token.(json.Delim); !isDelim || delim != expected {
+		return fmt.Errorf("expected %q but found %v", expected, token)
+	}
+	return nil
+}
+
+func decodeError(reader *inputReader, err error) error {
+	// Encrypted exports that fail to decrypt part way through
+	// report their own errors to the reader.
+	if importErr, isImportErr := reader.err.(*ImportError); isImportErr {
+		return importErr
+	}
+
+	if reader.err != nil {
+		return fmt.Errorf("failed to read input file: %w", reader.err)
+	}
+
+	return &ImportError{
+		Code:    ErrCodeInvalidJSON,
+		Message: "failed to parse instances JSON",
+		Err:     err,
+	}
+}
+
+// inputReader keeps track of errors reading the input so they can be
+// told apart from malformed JSON.
+type inputReader struct {
+	r   io.Reader
+	err error
+}
+
+func (r *inputReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
+}
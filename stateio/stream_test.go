@@ -0,0 +1,122 @@
+package stateio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/stretchr/testify/suite"
+)
+
+type StreamTestSuite struct {
+	suite.Suite
+}
+
+func (s *StreamTestSuite) Test_InstancesJSONWriter_matches_SerializeInstancesJSON() {
+	instances := []state.InstanceState{
+		{
+			InstanceID:   "inst-001",
+			InstanceName: "Instance One",
+			Status:       core.InstanceStatusDeployed,
+			ChildBlueprints: map[string]*state.InstanceState{
+				"child": {InstanceID: "child-001"},
+			},
+		},
+		{
+			InstanceID:   "inst-002",
+			InstanceName: "Instance Two",
+			Status:       core.InstanceStatusDeployed,
+		},
+	}
+
+	for _, toWrite := range [][]state.InstanceState{{}, instances[:1], instances} {
+		output := &bytes.Buffer{}
+		writer := NewInstancesJSONWriter(output)
+		for _, instance := range toWrite {
+			s.Require().NoError(writer.WriteInstance(instance))
+		}
+		s.Require().NoError(writer.Close())
+
+		expected, err := SerializeInstancesJSON(toWrite)
+		s.Require().NoError(err)
+		s.Equal(string(expected), output.String())
+		s.Equal(len(toWrite), writer.Count())
+	}
+}
+
+func (s *StreamTestSuite) Test_DecodeInstancesJSON_handles_each_instance() {
+	ids := []string{}
+	count, err := DecodeInstancesJSON(
+		strings.NewReader(`[{"id":"inst-001"}, {"id":"inst-002"}]`),
+		func(instance state.InstanceState) error {
+			ids = append(ids, instance.InstanceID)
+			return nil
+		},
+	)
+
+	s.Require().NoError(err)
+	s.Equal(2, count)
+	s.Equal([]string{"inst-001", "inst-002"}, ids)
+}
+
+func (s *StreamTestSuite) Test_DecodeInstancesJSON_returns_handler_errors() {
+	handlerErr := errors.New("save failed")
+	count, err := DecodeInstancesJSON(
+		strings.NewReader(`[{"id":"inst-001"}, {"id":"inst-002"}]`),
+		func(instance state.InstanceState) error {
+			if instance.InstanceID == "inst-002" {
+				return handlerErr
+			}
+			return nil
+		},
+	)
+
+	s.ErrorIs(err, handlerErr)
+	s.Equal(1, count)
+}
+
+func (s *StreamTestSuite) Test_DecodeInstancesJSON_rejects_invalid_json() {
+	for _, input := range []string{
+		`{"id":"inst-001"}`,
+		`[{"id":"inst-001"}`,
+		`[{"id":"inst-001"}] []`,
+		`[1]`,
+	} {
+		_, err := DecodeInstancesJSON(strings.NewReader(input), noopInstanceHandler)
+
+		var importErr *ImportError
+		s.Require().ErrorAs(err, &importErr, input)
+		s.Equal(ErrCodeInvalidJSON, importErr.Code, input)
+	}
+}
+
+func (s *StreamTestSuite) Test_DecodeInstancesJSON_reports_read_errors() {
+	readErr := errors.New("connection reset")
+	input := io.MultiReader(strings.NewReader(`[{"id":"inst-001"},`), &failingReader{err: readErr})
+
+	_, err := DecodeInstancesJSON(input, noopInstanceHandler)
+
+	s.ErrorIs(err, readErr)
+	var importErr *ImportError
+	s.False(errors.As(err, &importErr))
+}
+
+func noopInstanceHandler(state.InstanceState) error {
+	return nil
+}
+
+type failingReader struct {
+	err error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
+
+func TestStreamTestSuite(t *testing.T) {
+	suite.Run(t, new(StreamTestSuite))
+}
@@ -0,0 +1,385 @@
+package testutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint-state/manage"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/changes"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/container"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario scripts the behaviour of a ScenarioDeployEngine for tests that
+// cover more than one path through the deploy engine, such as drift being
+// detected, reconciled and a deployment that then fails and rolls back.
+//
+// Scenarios can be built in Go with NewScenarioBuilder or loaded from YAML
+// (or JSON) with LoadScenario, where fields use the JSON field names of the
+// deploy engine types:
+//
+//	instances:
+//	  - name: orders
+//	    id: orders-id
+//	    state: { id: orders-id, name: orders, status: 2 }
+//	    stages:
+//	      - changesetId: changeset-1
+//	        events:
+//	          - id: "1"
+//	            completeChanges: { changes: {} }
+//	        expectPayload: { instanceName: orders }
+//	    deployments:
+//	      - error:
+//	          type: client
+//	          statusCode: 409
+//	          message: drift detected
+//	          driftBlockedResponse: { changesetId: changeset-1 }
+//	      - events:
+//	          - id: "2"
+//	            type: finish
+//	            message: { instanceId: orders-id, status: 2 }
+//	        expectPayload: { changeSetId: changeset-1, autoRollback: true }
+//	    reconciliations:
+//	      - result: { instanceId: orders-id }
+//	        expectApplyPayload:
+//	          resourceActions: [{ resourceId: resource-1, action: accept_external }]
+//
+// Errors use the format of errors recorded in sessions, see engine.SessionError,
+// so that deploy engine client errors such as 409 drift responses are
+// reproduced with their original type.
+type Scenario struct {
+	Validation *ValidationScenario `json:"validation,omitempty"`
+	Instances  []*InstanceScenario `json:"instances,omitempty"`
+	// ListPages are returned from ListBlueprintInstances in the order they are
+	// defined, the last page is returned for any further calls.
+	// When no pages are defined, pages are derived from the instances
+	// of the scenario.
+	ListPages []*state.ListInstancesResult `json:"listPages,omitempty"`
+	Cleanups  []*CleanupScenario           `json:"cleanups,omitempty"`
+}
+
+// ValidationScenario scripts blueprint validations.
+type ValidationScenario struct {
+	Events []*types.BlueprintValidationEvent `json:"events,omitempty"`
+	// Error is returned from CreateBlueprintValidation when set.
+	Error *engine.SessionError `json:"error,omitempty"`
+	// ExpectPayload holds fields that the CreateBlueprintValidation
+	// payload is expected to have, see ScenarioDeployEngine.AssertExpectations.
+	ExpectPayload json.RawMessage `json:"expectPayload,omitempty"`
+}
+
+// InstanceScenario scripts the calls made for a blueprint instance.
+// Stages, deployments and reconciliations are used in the order they are
+// defined for each call that starts one, the last one is used for any
+// further calls.
+type InstanceScenario struct {
+	Name string `json:"name"`
+	// ID is the instance ID, the name is used as the ID when not set.
+	ID string `json:"id,omitempty"`
+	// State is returned for the instance, a state with only the instance ID
+	// and name is returned when not set.
+	State           *state.InstanceState          `json:"state,omitempty"`
+	Exports         map[string]*state.ExportState `json:"exports,omitempty"`
+	Stages          []*StageScenario              `json:"stages,omitempty"`
+	Deployments     []*DeploymentScenario         `json:"deployments,omitempty"`
+	Reconciliations []*ReconciliationScenario     `json:"reconciliations,omitempty"`
+}
+
+func (i *InstanceScenario) instanceID() string {
+	if i.ID != "" {
+		return i.ID
+	}
+	return i.Name
+}
+
+func (i *InstanceScenario) instanceState() *state.InstanceState {
+	if i.State != nil {
+		return i.State
+	}
+	return &state.InstanceState{
+		InstanceID:   i.instanceID(),
+		InstanceName: i.Name,
+	}
+}
+
+// StageScenario scripts a change staging process started with CreateChangeset.
+type StageScenario struct {
+	// ChangesetID defaults to "<instance name>-changeset-<stage number>".
+	ChangesetID string                      `json:"changesetId,omitempty"`
+	Events      []*types.ChangeStagingEvent `json:"events,omitempty"`
+	// Changes are returned from GetChangeset for the change set.
+	Changes *changes.BlueprintChanges `json:"changes,omitempty"`
+	// Status is the status of the change set returned from GetChangeset,
+	// defaults to manage.ChangesetStatusChangesStaged.
+	Status manage.ChangesetStatus `json:"status,omitempty"`
+	// BlueprintLocation is the blueprint location of the change set
+	// returned from GetChangeset.
+	BlueprintLocation string `json:"blueprintLocation,omitempty"`
+	// Error is returned from CreateChangeset when set.
+	Error *engine.SessionError `json:"error,omitempty"`
+	// ExpectPayload holds fields that the CreateChangeset payload is
+	// expected to have, see ScenarioDeployEngine.AssertExpectations.
+	ExpectPayload json.RawMessage `json:"expectPayload,omitempty"`
+}
+
+// DeploymentScenario scripts a deployment started with CreateBlueprintInstance,
+// UpdateBlueprintInstance or DestroyBlueprintInstance.
+type DeploymentScenario struct {
+	Events []*types.BlueprintInstanceEvent `json:"-"`
+	// Error is returned from the call that starts the deployment when set,
+	// see DriftBlockedError for 409 drift responses.
+	Error *engine.SessionError `json:"error,omitempty"`
+	// ExpectPayload holds fields that the payload of the call that starts the
+	// deployment is expected to have, see ScenarioDeployEngine.AssertExpectations.
+	ExpectPayload json.RawMessage `json:"expectPayload,omitempty"`
+}
+
+// scenarioInstanceEvent is the serialised form of a blueprint instance event
+// in a scenario, made up of the ID along with the type and message fields of
+// a serialised deploy event.
+type scenarioInstanceEvent struct {
+	ID string `json:"id"`
+}
+
+func (d *DeploymentScenario) UnmarshalJSON(data []byte) error {
+	type deploymentScenario DeploymentScenario
+	serialised := struct {
+		*deploymentScenario
+		Events []json.RawMessage `json:"events,omitempty"`
+	}{
+		deploymentScenario: (*deploymentScenario)(d),
+	}
+	if err := json.Unmarshal(data, &serialised); err != nil {
+		return err
+	}
+
+	d.Events = make([]*types.BlueprintInstanceEvent, 0, len(serialised.Events))
+	for _, eventData := range serialised.Events {
+		eventID := scenarioInstanceEvent{}
+		if err := json.Unmarshal(eventData, &eventID); err != nil {
+			return err
+		}
+		event := &types.BlueprintInstanceEvent{ID: eventID.ID}
+		if err := json.Unmarshal(eventData, &event.DeployEvent); err != nil {
+			return err
+		}
+		d.Events = append(d.Events, event)
+	}
+	return nil
+}
+
+// ReconciliationScenario scripts a reconciliation check and the
+// reconciliation that is applied after it.
+type ReconciliationScenario struct {
+	// Result is returned from CheckReconciliation, a result without
+	// drift is returned when not set.
+	Result     *container.ReconciliationCheckResult `json:"result,omitempty"`
+	CheckError *engine.SessionError                 `json:"checkError,omitempty"`
+	// ApplyResult is returned from ApplyReconciliation, a result with
+	// nothing applied is returned when not set.
+	ApplyResult *container.ApplyReconciliationResult `json:"applyResult,omitempty"`
+	ApplyError  *engine.SessionError                 `json:"applyError,omitempty"`
+	// ExpectCheckPayload and ExpectApplyPayload hold fields that the payloads
+	// of CheckReconciliation and ApplyReconciliation are expected to have,
+	// see ScenarioDeployEngine.AssertExpectations.
+	ExpectCheckPayload json.RawMessage `json:"expectCheckPayload,omitempty"`
+	ExpectApplyPayload json.RawMessage `json:"expectApplyPayload,omitempty"`
+}
+
+// CleanupScenario scripts a cleanup operation.
+type CleanupScenario struct {
+	Type manage.CleanupType `json:"type"`
+	// Operation is returned once the cleanup has completed from
+	// GetCleanupOperation and WaitForCleanupCompletion,
+	// a completed operation is returned when not set.
+	Operation *manage.CleanupOperation `json:"operation,omitempty"`
+	// Error is returned from the call that starts the cleanup when set.
+	Error *engine.SessionError `json:"error,omitempty"`
+}
+
+// DriftBlockedError creates a 409 error for a deployment that is blocked
+// because drift was detected for the instance.
+func DriftBlockedError(
+	instanceID string,
+	changesetID string,
+	result *container.ReconciliationCheckResult,
+) *engine.SessionError {
+	message := "drift detected for the blueprint instance"
+	return &engine.SessionError{
+		Type:       engine.SessionErrorClient,
+		StatusCode: 409,
+		Message:    message,
+		DriftBlockedResponse: &types.DriftBlockedResponse{
+			Message:              message,
+			InstanceID:           instanceID,
+			ChangesetID:          changesetID,
+			ReconciliationResult: result,
+		},
+	}
+}
+
+// LoadScenario loads a scenario from YAML or JSON.
+func LoadScenario(r io.Reader) (*Scenario, error) {
+	// The YAML document is converted to JSON so that the JSON field names
+	// and custom unmarshalling of the deploy engine types are used.
+	var document any
+	if err := yaml.NewDecoder(r).Decode(&document); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("invalid scenario: %w", err)
+	}
+	documentJSON, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scenario: %w", err)
+	}
+
+	scenario := &Scenario{}
+	if err := json.Unmarshal(documentJSON, scenario); err != nil {
+		return nil, fmt.Errorf("invalid scenario: %w", err)
+	}
+	return scenario, nil
+}
+
+// LoadScenarioFile loads a scenario from a YAML or JSON file.
+func LoadScenarioFile(path string) (*Scenario, error) {
+	scenarioFile, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer scenarioFile.Close()
+
+	return LoadScenario(scenarioFile)
+}
+
+// NewTestDeployEngineFromScenarioFile creates a test deploy engine for
+// the scenario in the provided YAML or JSON file.
+func NewTestDeployEngineFromScenarioFile(path string) (*ScenarioDeployEngine, error) {
+	scenario, err := LoadScenarioFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewScenarioDeployEngine(scenario), nil
+}
+
+// ScenarioBuilder builds a scenario in Go.
+type ScenarioBuilder struct {
+	scenario *Scenario
+}
+
+// NewScenarioBuilder creates a builder for an empty scenario.
+func NewScenarioBuilder() *ScenarioBuilder {
+	return &ScenarioBuilder{scenario: &Scenario{}}
+}
+
+// Validation sets the script for blueprint validations.
+func (b *ScenarioBuilder) Validation(validation *ValidationScenario) *ScenarioBuilder {
+	b.scenario.Validation = validation
+	return b
+}
+
+// Instance returns a builder for the instance with the provided name,
+// the instance is added to the scenario if it has not been added yet.
+func (b *ScenarioBuilder) Instance(name string) *InstanceScenarioBuilder {
+	for _, instance := range b.scenario.Instances {
+		if instance.Name == name {
+			return &InstanceScenarioBuilder{instance: instance}
+		}
+	}
+
+	instance := &InstanceScenario{Name: name}
+	b.scenario.Instances = append(b.scenario.Instances, instance)
+	return &InstanceScenarioBuilder{instance: instance}
+}
+
+// ListPage adds a page of instances to be returned from ListBlueprintInstances.
+func (b *ScenarioBuilder) ListPage(totalCount int, instances ...state.InstanceSummary) *ScenarioBuilder {
+	b.scenario.ListPages = append(b.scenario.ListPages, &state.ListInstancesResult{
+		Instances:  instances,
+		TotalCount: totalCount,
+	})
+	return b
+}
+
+// Cleanup adds the script for a cleanup operation.
+func (b *ScenarioBuilder) Cleanup(cleanup *CleanupScenario) *ScenarioBuilder {
+	b.scenario.Cleanups = append(b.scenario.Cleanups, cleanup)
+	return b
+}
+
+// Scenario returns the built scenario.
+func (b *ScenarioBuilder) Scenario() *Scenario {
+	return b.scenario
+}
+
+// Build creates a test deploy engine for the built scenario.
+func (b *ScenarioBuilder) Build() *ScenarioDeployEngine {
+	return NewScenarioDeployEngine(b.scenario)
+}
+
+// InstanceScenarioBuilder builds the script for a blueprint instance.
+type InstanceScenarioBuilder struct {
+	instance *InstanceScenario
+}
+
+// WithID sets the instance ID.
+func (b *InstanceScenarioBuilder) WithID(instanceID string) *InstanceScenarioBuilder {
+	b.instance.ID = instanceID
+	return b
+}
+
+// WithState sets the state returned for the instance.
+func (b *InstanceScenarioBuilder) WithState(instanceState *state.InstanceState) *InstanceScenarioBuilder {
+	b.instance.State = instanceState
+	return b
+}
+
+// WithExports sets the exports returned for the instance.
+func (b *InstanceScenarioBuilder) WithExports(exports map[string]*state.ExportState) *InstanceScenarioBuilder {
+	b.instance.Exports = exports
+	return b
+}
+
+// Stage adds a change staging process for the instance.
+func (b *InstanceScenarioBuilder) Stage(stage *StageScenario) *InstanceScenarioBuilder {
+	b.instance.Stages = append(b.instance.Stages, stage)
+	return b
+}
+
+// StagesChanges adds a change staging process that streams the provided events.
+func (b *InstanceScenarioBuilder) StagesChanges(
+	changesetID string,
+	events ...*types.ChangeStagingEvent,
+) *InstanceScenarioBuilder {
+	return b.Stage(&StageScenario{ChangesetID: changesetID, Events: events})
+}
+
+// Deployment adds a deployment for the instance.
+func (b *InstanceScenarioBuilder) Deployment(deployment *DeploymentScenario) *InstanceScenarioBuilder {
+	b.instance.Deployments = append(b.instance.Deployments, deployment)
+	return b
+}
+
+// Deploys adds a deployment that streams the provided events.
+func (b *InstanceScenarioBuilder) Deploys(events ...*types.BlueprintInstanceEvent) *InstanceScenarioBuilder {
+	return b.Deployment(&DeploymentScenario{Events: events})
+}
+
+// DriftBlocksDeployment adds a deployment that is blocked by a 409 response
+// because drift was detected for the instance, see DriftBlockedError.
+func (b *InstanceScenarioBuilder) DriftBlocksDeployment(
+	changesetID string,
+	result *container.ReconciliationCheckResult,
+) *InstanceScenarioBuilder {
+	return b.Deployment(&DeploymentScenario{
+		Error: DriftBlockedError(b.instance.instanceID(), changesetID, result),
+	})
+}
+
+// Reconciliation adds a reconciliation check and apply for the instance.
+func (b *InstanceScenarioBuilder) Reconciliation(reconciliation *ReconciliationScenario) *InstanceScenarioBuilder {
+	b.instance.Reconciliations = append(b.instance.Reconciliations, reconciliation)
+	return b
+}
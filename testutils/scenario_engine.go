@@ -0,0 +1,779 @@
+package testutils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint-state/manage"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/container"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	deerrors "github.com/newstack-cloud/bluelink/libs/deploy-engine-client/errors"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+)
+
+// ScenarioCall is a call received by a ScenarioDeployEngine.
+type ScenarioCall struct {
+	Method string
+	// InstanceID is the instance ID or name the call was made for,
+	// this is empty for calls that are not made for an instance.
+	InstanceID string
+	// Payload is the payload of the call, this is nil for
+	// calls without a payload.
+	Payload any
+}
+
+// ScenarioDeployEngine is a test deploy engine that follows a Scenario and
+// records the calls it receives so that tests can make assertions on the
+// payloads sent to the deploy engine.
+//
+// Like the other test deploy engines, streams are not closed after the
+// scripted events have been sent.
+type ScenarioDeployEngine struct {
+	scenario *Scenario
+	mu       sync.Mutex
+	calls    []ScenarioCall
+	// next holds the position of the next stage, deployment, reconciliation
+	// or list page to use keyed by the kind and instance name.
+	next map[string]int
+	// changesets holds the stages for the change sets created so far.
+	changesets map[string]*StageScenario
+	// activeDeployments holds the last deployment started for each instance.
+	activeDeployments map[string]*DeploymentScenario
+	// activeReconciliations holds the last reconciliation checked for each instance.
+	activeReconciliations map[string]*ReconciliationScenario
+	expectations          []*payloadExpectation
+}
+
+type payloadExpectation struct {
+	description string
+	expected    json.RawMessage
+	received    bool
+	mismatches  []string
+}
+
+// NewScenarioDeployEngine creates a test deploy engine for the provided scenario.
+func NewScenarioDeployEngine(scenario *Scenario) *ScenarioDeployEngine {
+	return &ScenarioDeployEngine{
+		scenario:              scenario,
+		next:                  map[string]int{},
+		changesets:            map[string]*StageScenario{},
+		activeDeployments:     map[string]*DeploymentScenario{},
+		activeReconciliations: map[string]*ReconciliationScenario{},
+	}
+}
+
+// Calls returns the calls received so far in the order they were received.
+func (d *ScenarioDeployEngine) Calls() []ScenarioCall {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]ScenarioCall{}, d.calls...)
+}
+
+// Payloads returns the payloads received so far for the provided method.
+func (d *ScenarioDeployEngine) Payloads(method string) []any {
+	payloads := []any{}
+	for _, call := range d.Calls() {
+		if call.Method == method {
+			payloads = append(payloads, call.Payload)
+		}
+	}
+	return payloads
+}
+
+// AssertExpectations fails the test for each error reported by
+// ExpectationErrors.
+func (d *ScenarioDeployEngine) AssertExpectations(t *testing.T) {
+	t.Helper()
+	for _, expectationErr := range d.ExpectationErrors() {
+		t.Error(expectationErr)
+	}
+}
+
+// ExpectationErrors describes each payload that did not have the fields
+// expected by the scenario and each expected payload that was never received.
+// Expected fields are compared by their JSON representation, objects in the
+// received payload may have fields that are not expected and lists
+// are compared item by item.
+func (d *ScenarioDeployEngine) ExpectationErrors() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	expectationErrs := []string{}
+	for _, expectation := range d.expectations {
+		for _, mismatch := range expectation.mismatches {
+			expectationErrs = append(expectationErrs, fmt.Sprintf("%s: %s", expectation.description, mismatch))
+		}
+	}
+
+	scenarioExpectations := d.scenarioExpectations()
+	descriptions := make([]string, 0, len(scenarioExpectations))
+	for description := range scenarioExpectations {
+		descriptions = append(descriptions, description)
+	}
+	slices.Sort(descriptions)
+	for _, description := range descriptions {
+		if !d.expectationReceived(description) {
+			expectationErrs = append(expectationErrs, fmt.Sprintf(
+				"%s: expected a payload with %s but none was received",
+				description,
+				scenarioExpectations[description],
+			))
+		}
+	}
+	return expectationErrs
+}
+
+func (d *ScenarioDeployEngine) scenarioExpectations() map[string]json.RawMessage {
+	expectations := map[string]json.RawMessage{}
+	add := func(description string, expected json.RawMessage) {
+		if len(expected) > 0 {
+			expectations[description] = expected
+		}
+	}
+
+	if d.scenario.Validation != nil {
+		add("validation", d.scenario.Validation.ExpectPayload)
+	}
+	for _, instance := range d.scenario.Instances {
+		for i, stage := range instance.Stages {
+			add(stepDescription(instance, "stage", i), stage.ExpectPayload)
+		}
+		for i, deployment := range instance.Deployments {
+			add(stepDescription(instance, "deployment", i), deployment.ExpectPayload)
+		}
+		for i, reconciliation := range instance.Reconciliations {
+			add(stepDescription(instance, "reconciliation check", i), reconciliation.ExpectCheckPayload)
+			add(stepDescription(instance, "reconciliation apply", i), reconciliation.ExpectApplyPayload)
+		}
+	}
+	return expectations
+}
+
+func (d *ScenarioDeployEngine) expectationReceived(description string) bool {
+	for _, expectation := range d.expectations {
+		if expectation.description == description && expectation.received {
+			return true
+		}
+	}
+	return false
+}
+
+func stepDescription(instance *InstanceScenario, kind string, index int) string {
+	return fmt.Sprintf("instance %q %s %d", instance.Name, kind, index+1)
+}
+
+func (d *ScenarioDeployEngine) CreateBlueprintValidation(
+	ctx context.Context,
+	payload *types.CreateBlueprintValidationPayload,
+	query *types.CreateBlueprintValidationQuery,
+) (*types.BlueprintValidationResponse, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.recordCall("CreateBlueprintValidation", "", payload)
+
+	validation := d.scenario.Validation
+	if validation == nil {
+		validation = &ValidationScenario{}
+	}
+	d.checkPayload("validation", validation.ExpectPayload, payload)
+	if validation.Error != nil {
+		return nil, validation.Error.Err()
+	}
+
+	return &types.BlueprintValidationResponse{
+		Data: &manage.BlueprintValidation{
+			ID:                "scenario-validation",
+			Status:            manage.BlueprintValidationStatusStarting,
+			BlueprintLocation: payload.BlueprintFile,
+			Created:           time.Now().Unix(),
+		},
+	}, nil
+}
+
+func (d *ScenarioDeployEngine) GetBlueprintValidation(
+	ctx context.Context,
+	validationID string,
+) (*manage.BlueprintValidation, error) {
+	return &manage.BlueprintValidation{
+		ID:      validationID,
+		Status:  manage.BlueprintValidationStatusValidated,
+		Created: time.Now().Unix(),
+	}, nil
+}
+
+func (d *ScenarioDeployEngine) StreamBlueprintValidationEvents(
+	ctx context.Context,
+	validationID string,
+	lastEventID string,
+	streamTo chan<- types.BlueprintValidationEvent,
+	errChan chan<- error,
+) error {
+	var events []*types.BlueprintValidationEvent
+	if d.scenario.Validation != nil {
+		events = d.scenario.Validation.Events
+	}
+	streamScenarioEvents(ctx, events, streamTo)
+	return nil
+}
+
+func (d *ScenarioDeployEngine) CleanupBlueprintValidations(
+	ctx context.Context,
+) (*manage.CleanupOperation, error) {
+	return d.startCleanup(manage.CleanupTypeValidations)
+}
+
+func (d *ScenarioDeployEngine) CreateChangeset(
+	ctx context.Context,
+	payload *types.CreateChangesetPayload,
+) (*types.ChangesetResponse, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	instanceRef := payload.InstanceID
+	if instanceRef == "" {
+		instanceRef = payload.InstanceName
+	}
+	d.recordCall("CreateChangeset", instanceRef, payload)
+
+	instance, err := d.findInstance(instanceRef)
+	if err != nil {
+		return nil, err
+	}
+	index, ok := d.nextStep("stage", instance, len(instance.Stages))
+	if !ok {
+		return nil, noStepError(instance, "stage")
+	}
+	stage := instance.Stages[index]
+	d.checkPayload(stepDescription(instance, "stage", index), stage.ExpectPayload, payload)
+	if stage.Error != nil {
+		return nil, stage.Error.Err()
+	}
+
+	changesetID := stage.ChangesetID
+	if changesetID == "" {
+		changesetID = fmt.Sprintf("%s-changeset-%d", instance.Name, index+1)
+	}
+	d.changesets[changesetID] = stage
+
+	return &types.ChangesetResponse{
+		Data: &manage.Changeset{
+			ID:                changesetID,
+			InstanceID:        instance.instanceID(),
+			Destroy:           payload.Destroy,
+			Status:            manage.ChangesetStatusStagingChanges,
+			BlueprintLocation: payload.BlueprintFile,
+			Created:           time.Now().Unix(),
+		},
+	}, nil
+}
+
+func (d *ScenarioDeployEngine) GetChangeset(
+	ctx context.Context,
+	changesetID string,
+) (*manage.Changeset, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	stage := d.findStage(changesetID)
+	if stage == nil {
+		return nil, &deerrors.ClientError{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("change set %q not found", changesetID),
+		}
+	}
+
+	status := stage.Status
+	if status == "" {
+		status = manage.ChangesetStatusChangesStaged
+	}
+	instanceID := ""
+	if instance := d.findStageInstance(stage); instance != nil {
+		instanceID = instance.instanceID()
+	}
+	return &manage.Changeset{
+		ID:                changesetID,
+		InstanceID:        instanceID,
+		Status:            status,
+		BlueprintLocation: stage.BlueprintLocation,
+		Changes:           stage.Changes,
+		Created:           time.Now().Unix(),
+	}, nil
+}
+
+func (d *ScenarioDeployEngine) StreamChangeStagingEvents(
+	ctx context.Context,
+	changesetID string,
+	lastEventID string,
+	streamTo chan<- types.ChangeStagingEvent,
+	errChan chan<- error,
+) error {
+	d.mu.Lock()
+	stage := d.findStage(changesetID)
+	d.mu.Unlock()
+
+	if stage != nil {
+		streamScenarioEvents(ctx, stage.Events, streamTo)
+	}
+	return nil
+}
+
+func (d *ScenarioDeployEngine) CleanupChangesets(
+	ctx context.Context,
+) (*manage.CleanupOperation, error) {
+	return d.startCleanup(manage.CleanupTypeChangesets)
+}
+
+func (d *ScenarioDeployEngine) CreateBlueprintInstance(
+	ctx context.Context,
+	payload *types.BlueprintInstancePayload,
+) (*types.BlueprintInstanceResponse, error) {
+	return d.startDeployment("CreateBlueprintInstance", payload.InstanceName, payload)
+}
+
+func (d *ScenarioDeployEngine) UpdateBlueprintInstance(
+	ctx context.Context,
+	instanceID string,
+	payload *types.BlueprintInstancePayload,
+) (*types.BlueprintInstanceResponse, error) {
+	return d.startDeployment("UpdateBlueprintInstance", instanceID, payload)
+}
+
+func (d *ScenarioDeployEngine) GetBlueprintInstance(
+	ctx context.Context,
+	instanceID string,
+) (*state.InstanceState, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	instance, err := d.findInstance(instanceID)
+	if err != nil {
+		return nil, err
+	}
+	return instance.instanceState(), nil
+}
+
+func (d *ScenarioDeployEngine) ListBlueprintInstances(
+	ctx context.Context,
+	params state.ListInstancesParams,
+) (state.ListInstancesResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.recordCall("ListBlueprintInstances", "", params)
+
+	if len(d.scenario.ListPages) > 0 {
+		index, _ := d.nextStep("list", nil, len(d.scenario.ListPages))
+		return *d.scenario.ListPages[index], nil
+	}
+
+	matching := []state.InstanceSummary{}
+	for _, instance := range d.scenario.Instances {
+		if !strings.Contains(strings.ToLower(instance.Name), strings.ToLower(params.Search)) {
+			continue
+		}
+		instanceState := instance.instanceState()
+		matching = append(matching, state.InstanceSummary{
+			InstanceID:            instance.instanceID(),
+			InstanceName:          instance.Name,
+			Status:                instanceState.Status,
+			LastDeployedTimestamp: int64(instanceState.LastDeployedTimestamp),
+		})
+	}
+
+	page := matching[min(params.Offset, len(matching)):]
+	if params.Limit > 0 && params.Limit < len(page) {
+		page = page[:params.Limit]
+	}
+	return state.ListInstancesResult{
+		Instances:  page,
+		TotalCount: len(matching),
+	}, nil
+}
+
+func (d *ScenarioDeployEngine) GetBlueprintInstanceExports(
+	ctx context.Context,
+	instanceID string,
+) (map[string]*state.ExportState, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	instance, err := d.findInstance(instanceID)
+	if err != nil {
+		return nil, err
+	}
+	return instance.Exports, nil
+}
+
+func (d *ScenarioDeployEngine) DestroyBlueprintInstance(
+	ctx context.Context,
+	instanceID string,
+	payload *types.DestroyBlueprintInstancePayload,
+) (*types.BlueprintInstanceResponse, error) {
+	return d.startDeployment("DestroyBlueprintInstance", instanceID, payload)
+}
+
+func (d *ScenarioDeployEngine) StreamBlueprintInstanceEvents(
+	ctx context.Context,
+	instanceID string,
+	lastEventID string,
+	streamTo chan<- types.BlueprintInstanceEvent,
+	errChan chan<- error,
+) error {
+	d.mu.Lock()
+	instance, err := d.findInstance(instanceID)
+	if err != nil {
+		d.mu.Unlock()
+		return err
+	}
+	// Streams for instances that have not been deployed by the test,
+	// such as when inspecting an instance, use the first deployment.
+	deployment := d.activeDeployments[instance.Name]
+	if deployment == nil && len(instance.Deployments) > 0 {
+		deployment = instance.Deployments[0]
+	}
+	d.mu.Unlock()
+
+	if deployment != nil {
+		streamScenarioEvents(ctx, deployment.Events, streamTo)
+	}
+	return nil
+}
+
+func (d *ScenarioDeployEngine) CleanupEvents(
+	ctx context.Context,
+) (*manage.CleanupOperation, error) {
+	return d.startCleanup(manage.CleanupTypeEvents)
+}
+
+func (d *ScenarioDeployEngine) CleanupReconciliationResults(
+	ctx context.Context,
+) (*manage.CleanupOperation, error) {
+	return d.startCleanup(manage.CleanupTypeReconciliationResults)
+}
+
+func (d *ScenarioDeployEngine) GetCleanupOperation(
+	ctx context.Context,
+	cleanupType manage.CleanupType,
+	operationID string,
+) (*manage.CleanupOperation, error) {
+	return d.completedCleanup(cleanupType, operationID), nil
+}
+
+func (d *ScenarioDeployEngine) WaitForCleanupCompletion(
+	ctx context.Context,
+	cleanupType manage.CleanupType,
+	operationID string,
+	pollInterval time.Duration,
+) (*manage.CleanupOperation, error) {
+	return d.completedCleanup(cleanupType, operationID), nil
+}
+
+func (d *ScenarioDeployEngine) CheckReconciliation(
+	ctx context.Context,
+	instanceID string,
+	payload *types.CheckReconciliationPayload,
+) (*container.ReconciliationCheckResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.recordCall("CheckReconciliation", instanceID, payload)
+
+	instance, err := d.findInstance(instanceID)
+	if err != nil {
+		return nil, err
+	}
+	index, ok := d.nextStep("reconciliation", instance, len(instance.Reconciliations))
+	if !ok {
+		return &container.ReconciliationCheckResult{InstanceID: instance.instanceID()}, nil
+	}
+	reconciliation := instance.Reconciliations[index]
+	d.activeReconciliations[instance.Name] = reconciliation
+	d.checkPayload(
+		stepDescription(instance, "reconciliation check", index),
+		reconciliation.ExpectCheckPayload,
+		payload,
+	)
+
+	if reconciliation.CheckError != nil {
+		return nil, reconciliation.CheckError.Err()
+	}
+	if reconciliation.Result == nil {
+		return &container.ReconciliationCheckResult{InstanceID: instance.instanceID()}, nil
+	}
+	return reconciliation.Result, nil
+}
+
+func (d *ScenarioDeployEngine) ApplyReconciliation(
+	ctx context.Context,
+	instanceID string,
+	payload *types.ApplyReconciliationPayload,
+) (*container.ApplyReconciliationResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.recordCall("ApplyReconciliation", instanceID, payload)
+
+	instance, err := d.findInstance(instanceID)
+	if err != nil {
+		return nil, err
+	}
+	reconciliation := d.activeReconciliations[instance.Name]
+	if reconciliation == nil {
+		return &container.ApplyReconciliationResult{InstanceID: instance.instanceID()}, nil
+	}
+	index := d.next["reconciliation:"+instance.Name] - 1
+	d.checkPayload(
+		stepDescription(instance, "reconciliation apply", index),
+		reconciliation.ExpectApplyPayload,
+		payload,
+	)
+
+	if reconciliation.ApplyError != nil {
+		return nil, reconciliation.ApplyError.Err()
+	}
+	if reconciliation.ApplyResult == nil {
+		return &container.ApplyReconciliationResult{InstanceID: instance.instanceID()}, nil
+	}
+	return reconciliation.ApplyResult, nil
+}
+
+func (d *ScenarioDeployEngine) startDeployment(
+	method string,
+	instanceRef string,
+	payload any,
+) (*types.BlueprintInstanceResponse, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.recordCall(method, instanceRef, payload)
+
+	instance, err := d.findInstance(instanceRef)
+	if err != nil {
+		return nil, err
+	}
+	index, ok := d.nextStep("deployment", instance, len(instance.Deployments))
+	if !ok {
+		return nil, noStepError(instance, "deployment")
+	}
+	deployment := instance.Deployments[index]
+	d.activeDeployments[instance.Name] = deployment
+	d.checkPayload(stepDescription(instance, "deployment", index), deployment.ExpectPayload, payload)
+
+	if deployment.Error != nil {
+		return nil, deployment.Error.Err()
+	}
+	return &types.BlueprintInstanceResponse{
+		Data: *instance.instanceState(),
+	}, nil
+}
+
+func (d *ScenarioDeployEngine) startCleanup(cleanupType manage.CleanupType) (*manage.CleanupOperation, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.recordCall("Cleanup", "", cleanupType)
+
+	cleanup := d.findCleanup(cleanupType)
+	if cleanup.Error != nil {
+		return nil, cleanup.Error.Err()
+	}
+	operation := *d.cleanupOperation(cleanup, "")
+	operation.Status = manage.CleanupOperationStatusRunning
+	operation.EndedAt = 0
+	return &operation, nil
+}
+
+func (d *ScenarioDeployEngine) completedCleanup(
+	cleanupType manage.CleanupType,
+	operationID string,
+) *manage.CleanupOperation {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.cleanupOperation(d.findCleanup(cleanupType), operationID)
+}
+
+func (d *ScenarioDeployEngine) findCleanup(cleanupType manage.CleanupType) *CleanupScenario {
+	for _, cleanup := range d.scenario.Cleanups {
+		if cleanup.Type == cleanupType {
+			return cleanup
+		}
+	}
+	return &CleanupScenario{Type: cleanupType}
+}
+
+func (d *ScenarioDeployEngine) cleanupOperation(
+	cleanup *CleanupScenario,
+	operationID string,
+) *manage.CleanupOperation {
+	if cleanup.Operation != nil {
+		return cleanup.Operation
+	}
+	if operationID == "" {
+		operationID = fmt.Sprintf("scenario-cleanup-%s", cleanup.Type)
+	}
+	return &manage.CleanupOperation{
+		ID:          operationID,
+		CleanupType: cleanup.Type,
+		Status:      manage.CleanupOperationStatusCompleted,
+		StartedAt:   time.Now().Unix() - 1,
+		EndedAt:     time.Now().Unix(),
+	}
+}
+
+func (d *ScenarioDeployEngine) findInstance(instanceRef string) (*InstanceScenario, error) {
+	for _, instance := range d.scenario.Instances {
+		if instance.Name == instanceRef || instance.instanceID() == instanceRef {
+			return instance, nil
+		}
+	}
+	return nil, &deerrors.ClientError{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("blueprint instance %q not found", instanceRef),
+	}
+}
+
+func (d *ScenarioDeployEngine) findStage(changesetID string) *StageScenario {
+	if stage, ok := d.changesets[changesetID]; ok {
+		return stage
+	}
+	for _, instance := range d.scenario.Instances {
+		for _, stage := range instance.Stages {
+			if stage.ChangesetID == changesetID {
+				return stage
+			}
+		}
+	}
+	return nil
+}
+
+func (d *ScenarioDeployEngine) findStageInstance(stage *StageScenario) *InstanceScenario {
+	for _, instance := range d.scenario.Instances {
+		if slices.Contains(instance.Stages, stage) {
+			return instance
+		}
+	}
+	return nil
+}
+
+// nextStep returns the index of the next step of the provided kind for
+// the instance, the last step is reused once all steps have been used.
+func (d *ScenarioDeployEngine) nextStep(kind string, instance *InstanceScenario, count int) (int, bool) {
+	if count == 0 {
+		return 0, false
+	}
+	key := kind
+	if instance != nil {
+		key = kind + ":" + instance.Name
+	}
+	index := min(d.next[key], count-1)
+	d.next[key] = index + 1
+	return index, true
+}
+
+func noStepError(instance *InstanceScenario, kind string) error {
+	return fmt.Errorf("scenario has no %s for instance %q", kind, instance.Name)
+}
+
+func (d *ScenarioDeployEngine) recordCall(method string, instanceID string, payload any) {
+	d.calls = append(d.calls, ScenarioCall{
+		Method:     method,
+		InstanceID: instanceID,
+		Payload:    payload,
+	})
+}
+
+func (d *ScenarioDeployEngine) checkPayload(description string, expected json.RawMessage, payload any) {
+	if len(expected) == 0 {
+		return
+	}
+
+	expectation := &payloadExpectation{
+		description: description,
+		expected:    expected,
+		received:    true,
+	}
+	d.expectations = append(d.expectations, expectation)
+
+	mismatches, err := payloadMismatches(expected, payload)
+	if err != nil {
+		expectation.mismatches = []string{err.Error()}
+		return
+	}
+	expectation.mismatches = mismatches
+}
+
+// payloadMismatches compares the expected fields with the JSON representation
+// of the received payload, returning a description of each field that does not
+// match.
+func payloadMismatches(expected json.RawMessage, payload any) ([]string, error) {
+	var expectedValue any
+	if err := json.Unmarshal(expected, &expectedValue); err != nil {
+		return nil, fmt.Errorf("invalid expected payload: %w", err)
+	}
+
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	var payloadValue any
+	if err := json.Unmarshal(payloadJSON, &payloadValue); err != nil {
+		return nil, err
+	}
+
+	return collectMismatches("payload", expectedValue, payloadValue), nil
+}
+
+func collectMismatches(path string, expected any, received any) []string {
+	if expectedList, isList := expected.([]any); isList {
+		receivedList, isReceivedList := received.([]any)
+		if !isReceivedList || len(receivedList) != len(expectedList) {
+			return []string{fmt.Sprintf("expected %s to have %d items but was %v", path, len(expectedList), received)}
+		}
+		mismatches := []string{}
+		for i, expectedItem := range expectedList {
+			mismatches = append(
+				mismatches,
+				collectMismatches(fmt.Sprintf("%s[%d]", path, i), expectedItem, receivedList[i])...,
+			)
+		}
+		return mismatches
+	}
+
+	expectedMap, isMap := expected.(map[string]any)
+	if !isMap {
+		if !reflect.DeepEqual(expected, received) {
+			return []string{fmt.Sprintf("expected %s to be %v but was %v", path, expected, received)}
+		}
+		return nil
+	}
+
+	receivedMap, isReceivedMap := received.(map[string]any)
+	if !isReceivedMap {
+		return []string{fmt.Sprintf("expected %s to be an object but was %v", path, received)}
+	}
+
+	mismatches := []string{}
+	for _, key := range slices.Sorted(maps.Keys(expectedMap)) {
+		mismatches = append(
+			mismatches,
+			collectMismatches(path+"."+key, expectedMap[key], receivedMap[key])...,
+		)
+	}
+	return mismatches
+}
+
+func streamScenarioEvents[Event any](ctx context.Context, events []*Event, streamTo chan<- Event) {
+	go func() {
+		for _, event := range events {
+			select {
+			case <-ctx.Done():
+				return
+			case streamTo <- *event:
+			}
+		}
+	}()
+}
@@ -0,0 +1,166 @@
+package testutils
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint-state/manage"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/container"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	deerrors "github.com/newstack-cloud/bluelink/libs/deploy-engine-client/errors"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/stretchr/testify/suite"
+)
+
+const driftThenDeployScenario = `
+instances:
+  - name: orders
+    id: orders-id
+    state: { id: orders-id, name: orders, status: 2 }
+    stages:
+      - changesetId: changeset-1
+        events:
+          - id: "1"
+            completeChanges: { changes: {} }
+        expectPayload: { instanceName: orders }
+    deployments:
+      - error:
+          type: client
+          statusCode: 409
+          message: drift detected
+          driftBlockedResponse: { changesetId: changeset-1 }
+      - events:
+          - id: "2"
+            type: finish
+            message: { instanceId: orders-id, status: 2 }
+        expectPayload: { changeSetId: changeset-1, autoRollback: true }
+    reconciliations:
+      - result: { instanceId: orders-id }
+        expectApplyPayload: { resourceActions: [{ resourceId: resource-1, action: accept_external }] }
+`
+
+type ScenarioSuite struct {
+	suite.Suite
+}
+
+func (s *ScenarioSuite) Test_follows_a_scenario_loaded_from_yaml() {
+	scenario, err := LoadScenario(strings.NewReader(driftThenDeployScenario))
+	s.Require().NoError(err)
+	deployEngine := NewScenarioDeployEngine(scenario)
+	ctx := context.Background()
+
+	changeset, err := deployEngine.CreateChangeset(ctx, &types.CreateChangesetPayload{InstanceName: "orders"})
+	s.Require().NoError(err)
+	s.Equal("changeset-1", changeset.Data.ID)
+
+	stagingEvents := make(chan types.ChangeStagingEvent)
+	s.Require().NoError(deployEngine.StreamChangeStagingEvents(ctx, "changeset-1", "", stagingEvents, nil))
+	stagingEvent := <-stagingEvents
+	s.NotNil(stagingEvent.CompleteChanges)
+
+	_, err = deployEngine.UpdateBlueprintInstance(ctx, "orders-id", &types.BlueprintInstancePayload{
+		ChangeSetID: "changeset-1",
+	})
+	clientErr, isDriftBlocked := deerrors.IsDriftBlockedError(err)
+	s.Require().True(isDriftBlocked)
+	s.Equal("changeset-1", clientErr.DriftBlockedResponse.ChangesetID)
+
+	result, err := deployEngine.CheckReconciliation(ctx, "orders-id", &types.CheckReconciliationPayload{})
+	s.Require().NoError(err)
+	s.Equal("orders-id", result.InstanceID)
+	_, err = deployEngine.ApplyReconciliation(ctx, "orders-id", &types.ApplyReconciliationPayload{
+		ResourceActions: []types.ResourceReconcileActionPayload{
+			{ResourceID: "resource-1", Action: "accept_external", NewStatus: "created"},
+		},
+	})
+	s.Require().NoError(err)
+
+	response, err := deployEngine.UpdateBlueprintInstance(ctx, "orders-id", &types.BlueprintInstancePayload{
+		ChangeSetID:  "changeset-1",
+		AutoRollback: true,
+	})
+	s.Require().NoError(err)
+	s.Equal(core.InstanceStatusDeployed, response.Data.Status)
+
+	deployEvents := make(chan types.BlueprintInstanceEvent)
+	s.Require().NoError(deployEngine.StreamBlueprintInstanceEvents(ctx, "orders-id", "", deployEvents, nil))
+	deployEvent := <-deployEvents
+	s.Equal("2", deployEvent.ID)
+	s.Require().NotNil(deployEvent.FinishEvent)
+	s.Equal(core.InstanceStatusDeployed, deployEvent.FinishEvent.Status)
+
+	s.Empty(deployEngine.ExpectationErrors())
+	s.Len(deployEngine.Payloads("UpdateBlueprintInstance"), 2)
+}
+
+func (s *ScenarioSuite) Test_reports_unexpected_and_missing_payloads() {
+	builder := NewScenarioBuilder()
+	builder.Instance("orders").
+		Stage(&StageScenario{ExpectPayload: []byte(`{"instanceName":"orders","force":true}`)}).
+		Deployment(&DeploymentScenario{ExpectPayload: []byte(`{"autoRollback":true}`)})
+	scenarioEngine := builder.Build()
+
+	_, err := scenarioEngine.CreateChangeset(context.Background(), &types.CreateChangesetPayload{InstanceName: "orders"})
+	s.Require().NoError(err)
+
+	s.Equal([]string{
+		`instance "orders" stage 1: expected payload.force to be true but was <nil>`,
+		`instance "orders" deployment 1: expected a payload with {"autoRollback":true} but none was received`,
+	}, scenarioEngine.ExpectationErrors())
+}
+
+func (s *ScenarioSuite) Test_builds_scenarios_in_go() {
+	builder := NewScenarioBuilder()
+	builder.Instance("orders").
+		WithID("orders-id").
+		DriftBlocksDeployment("changeset-1", &container.ReconciliationCheckResult{InstanceID: "orders-id"}).
+		Deploys(&types.BlueprintInstanceEvent{ID: "1"})
+	builder.Instance("payments")
+	deployEngine := builder.Build()
+	ctx := context.Background()
+
+	_, err := deployEngine.DestroyBlueprintInstance(ctx, "orders", &types.DestroyBlueprintInstancePayload{})
+	_, isDriftBlocked := deerrors.IsDriftBlockedError(err)
+	s.True(isDriftBlocked)
+
+	_, err = deployEngine.DestroyBlueprintInstance(ctx, "orders", &types.DestroyBlueprintInstancePayload{})
+	s.NoError(err)
+
+	_, err = deployEngine.GetBlueprintInstance(ctx, "unknown")
+	_, isNotFound := deerrors.IsNotFoundError(err)
+	s.True(isNotFound)
+
+	page, err := deployEngine.ListBlueprintInstances(ctx, state.ListInstancesParams{Offset: 1, Limit: 1})
+	s.Require().NoError(err)
+	s.Equal(2, page.TotalCount)
+	s.Require().Len(page.Instances, 1)
+	s.Equal("payments", page.Instances[0].InstanceName)
+}
+
+func (s *ScenarioSuite) Test_returns_change_sets_of_instance_stages() {
+	builder := NewScenarioBuilder()
+	builder.Instance("orders").
+		WithID("orders-id").
+		Stage(&StageScenario{
+			ChangesetID:       "changeset-1",
+			Status:            manage.ChangesetStatusFailed,
+			BlueprintLocation: "orders.blueprint.yaml",
+		})
+	deployEngine := builder.Build()
+
+	changeset, err := deployEngine.GetChangeset(context.Background(), "changeset-1")
+	s.Require().NoError(err)
+	s.Equal("orders-id", changeset.InstanceID)
+	s.Equal(manage.ChangesetStatusFailed, changeset.Status)
+	s.Equal("orders.blueprint.yaml", changeset.BlueprintLocation)
+
+	_, err = deployEngine.GetChangeset(context.Background(), "changeset-2")
+	_, isNotFound := deerrors.IsNotFoundError(err)
+	s.True(isNotFound)
+}
+
+func TestScenarioSuite(t *testing.T) {
+	suite.Run(t, new(ScenarioSuite))
+}
@@ -0,0 +1,14 @@
+package testutils
+
+import (
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+)
+
+// NewTestDeployEngineFromSession creates a test deploy engine that plays back
+// a session recorded with engine.Recorder (e.g. with the --record-session flag)
+// without waiting between calls and events.
+// This allows sessions attached to bug reports to be used as test fixtures
+// instead of building a fake engine for each scenario.
+func NewTestDeployEngineFromSession(sessionFile string) (engine.DeployEngine, error) {
+	return engine.NewReplayerFromFile(sessionFile, 0)
+}
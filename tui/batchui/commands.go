@@ -0,0 +1,29 @@
+package batchui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// instanceMsg wraps a message produced by the deploy app of a single
+// manifest instance so it can be routed back to the app that produced it.
+type instanceMsg struct {
+	index int
+	msg   tea.Msg
+}
+
+// wrapInstanceCmd wraps a command of the deploy app for the instance
+// at the given index so that the message it produces is tagged with the index.
+// Batches are unwrapped when they are routed so that each command in
+// the batch is wrapped and run concurrently.
+func wrapInstanceCmd(index int, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		msg := cmd()
+		if msg == nil {
+			return nil
+		}
+		return instanceMsg{index: index, msg: msg}
+	}
+}
@@ -0,0 +1,156 @@
+package batchui
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
+	sdkstrings "github.com/newstack-cloud/deploy-cli-sdk/strings"
+)
+
+func (m *MainModel) printRunStarted(run *instanceRun) {
+	if m.printer == nil {
+		return
+	}
+	if m.engineContext != "" {
+		m.printer.Writer().Printf(
+			"Deploying %s (%s) to context %s\n",
+			run.entry.InstanceName,
+			run.entry.BlueprintFile,
+			m.engineContext,
+		)
+		return
+	}
+	m.printer.Writer().Printf("Deploying %s (%s)\n", run.entry.InstanceName, run.entry.BlueprintFile)
+}
+
+func (m *MainModel) printRunSucceeded(run *instanceRun) {
+	if m.printer == nil {
+		return
+	}
+	m.printer.Writer().Printf(
+		"✓ %s deployed in %s\n",
+		run.entry.InstanceName,
+		run.duration().Round(time.Second),
+	)
+}
+
+func (m *MainModel) printRunFailed(run *instanceRun) {
+	if m.printer == nil {
+		return
+	}
+	m.printer.Writer().Printf("ERR %s failed: %s\n", run.entry.InstanceName, run.err.Error())
+}
+
+func (m *MainModel) printRunSkipped(run *instanceRun) {
+	if m.printer == nil {
+		return
+	}
+	m.printer.Writer().Printf("- %s skipped: %s\n", run.entry.InstanceName, run.err.Error())
+}
+
+func (m *MainModel) dispatchHeadlessSummary() {
+	if m.jsonMode {
+		m.outputJSON()
+		return
+	}
+	m.printHeadlessSummary()
+}
+
+func (m *MainModel) outputJSON() {
+	if m.headlessWriter == nil {
+		return
+	}
+	jsonout.WriteJSON(m.headlessWriter, m.buildJSONOutput())
+}
+
+func (m *MainModel) buildJSONOutput() jsonout.BatchDeployOutput {
+	output := jsonout.BatchDeployOutput{
+		Success:   m.Error == nil,
+		Context:   m.engineContext,
+		Variables: m.variables,
+		Summary: jsonout.BatchDeploySummary{
+			Total:     len(m.runs),
+			Succeeded: m.countByStatus(InstanceStatusSucceeded),
+			Failed:    m.countByStatus(InstanceStatusFailed),
+			Skipped:   m.countByStatus(InstanceStatusSkipped),
+		},
+		Instances: make([]jsonout.BatchInstanceOutput, 0, len(m.runs)),
+	}
+
+	for _, run := range m.runs {
+		instanceOutput := jsonout.BatchInstanceOutput{
+			InstanceName:  run.entry.InstanceName,
+			BlueprintFile: run.entry.BlueprintFile,
+			DependsOn:     run.entry.DependsOn,
+			Status:        string(run.status),
+			Result:        run.jsonResult(),
+		}
+		if run.err != nil {
+			instanceOutput.Error = run.err.Error()
+		}
+		output.Instances = append(output.Instances, instanceOutput)
+	}
+
+	return output
+}
+
+func (m *MainModel) printHeadlessSummary() {
+	if m.printer == nil {
+		return
+	}
+	w := m.printer.Writer()
+	w.PrintlnEmpty()
+	w.DoubleSeparator(72)
+
+	total := len(m.runs)
+	succeeded := m.countByStatus(InstanceStatusSucceeded)
+	if m.Error == nil {
+		w.Printf(
+			"✓ Deployed %d %s\n",
+			total,
+			sdkstrings.Pluralize(total, "instance", "instances"),
+		)
+	} else {
+		w.Println("ERR Batch deployment failed")
+	}
+	w.Printf(
+		"  %d succeeded, %d failed, %d skipped\n",
+		succeeded,
+		m.countByStatus(InstanceStatusFailed),
+		m.countByStatus(InstanceStatusSkipped),
+	)
+	w.PrintlnEmpty()
+
+	for _, run := range m.runs {
+		if run.err != nil {
+			w.Printf("  %s %s: %s\n", run.status, run.entry.InstanceName, run.err.Error())
+		}
+	}
+}
+
+func (r *instanceRun) duration() time.Duration {
+	if r.startedAt.IsZero() {
+		return 0
+	}
+	end := r.finishedAt
+	if end.IsZero() {
+		end = time.Now()
+	}
+	return end.Sub(r.startedAt)
+}
+
+// jsonResult returns the JSON output captured from the deploy app of the
+// instance, or nil if the instance was never started or the output
+// is not valid JSON.
+func (r *instanceRun) jsonResult() json.RawMessage {
+	if r.output == nil {
+		return nil
+	}
+	trimmed := bytes.TrimSpace(r.output.Bytes())
+	if len(trimmed) == 0 || !json.Valid(trimmed) {
+		return nil
+	}
+	return json.RawMessage(trimmed)
+}
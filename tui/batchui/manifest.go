@@ -0,0 +1,209 @@
+package batchui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/deploy-cli-sdk/config"
+	"github.com/newstack-cloud/deploy-cli-sdk/consts"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/shared"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultParallelism is the number of instances deployed at the same time
+// when neither the manifest nor the command sets a parallelism.
+const DefaultParallelism = 4
+
+// Manifest describes a set of blueprint instances to be staged and deployed
+// together by the deploy command.
+//
+// Example (YAML):
+//
+//	parallelism: 4
+//	instances:
+//	  - instanceName: networking
+//	    blueprintFile: networking/app.blueprint.yaml
+//	    deployConfigFile: networking/deploy-config.json
+//	  - instanceName: orders-api
+//	    blueprintFile: orders/app.blueprint.yaml
+//	    dependsOn: [networking]
+type Manifest struct {
+	Parallelism int             `yaml:"parallelism" json:"parallelism"`
+	Instances   []ManifestEntry `yaml:"instances" json:"instances"`
+}
+
+// ManifestEntry is a single blueprint instance in a deployment manifest.
+type ManifestEntry struct {
+	InstanceName  string `yaml:"instanceName" json:"instanceName"`
+	BlueprintFile string `yaml:"blueprintFile" json:"blueprintFile"`
+	// DeployConfigFile is an optional path to a deploy config file holding the
+	// provider, transformer and context variable values for the instance.
+	DeployConfigFile string `yaml:"deployConfigFile,omitempty" json:"deployConfigFile,omitempty"`
+	// DependsOn holds the names of instances in the same manifest that must be
+	// deployed successfully before this instance is deployed.
+	DependsOn []string `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+}
+
+// LoadManifest loads and validates a deployment manifest from a YAML or JSON file.
+// Relative local file paths in the manifest are resolved against
+// the directory containing the manifest.
+func LoadManifest(manifestFile string) (*Manifest, error) {
+	data, err := os.ReadFile(manifestFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading deployment manifest %s: %w", manifestFile, err)
+	}
+
+	manifest := &Manifest{}
+	switch strings.ToLower(filepath.Ext(manifestFile)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, manifest)
+	case ".json":
+		err = json.Unmarshal(data, manifest)
+	default:
+		return nil, fmt.Errorf(
+			"unsupported deployment manifest format %q, expected .yaml, .yml or .json",
+			filepath.Ext(manifestFile),
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing deployment manifest %s: %w", manifestFile, err)
+	}
+
+	if err := manifest.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid deployment manifest %s: %w", manifestFile, err)
+	}
+
+	manifest.resolvePaths(filepath.Dir(manifestFile))
+	return manifest, nil
+}
+
+// Validate makes sure every instance in the manifest has a unique name and
+// a blueprint file, and that the dependencies between instances
+// exist and do not form a cycle.
+func (m *Manifest) Validate() error {
+	if len(m.Instances) == 0 {
+		return errors.New("at least one instance must be provided")
+	}
+	if m.Parallelism < 0 {
+		return fmt.Errorf("parallelism must be a positive number, got %d", m.Parallelism)
+	}
+
+	seen := map[string]bool{}
+	for i, entry := range m.Instances {
+		if entry.InstanceName == "" {
+			return fmt.Errorf("instance %d must have an instanceName", i)
+		}
+		if seen[entry.InstanceName] {
+			return fmt.Errorf("instance %q is defined more than once", entry.InstanceName)
+		}
+		seen[entry.InstanceName] = true
+		if entry.BlueprintFile == "" {
+			return fmt.Errorf("instance %q must have a blueprintFile", entry.InstanceName)
+		}
+	}
+
+	for _, entry := range m.Instances {
+		for _, dependency := range entry.DependsOn {
+			if dependency == entry.InstanceName {
+				return fmt.Errorf("instance %q can not depend on itself", entry.InstanceName)
+			}
+			if !seen[dependency] {
+				return fmt.Errorf(
+					"instance %q depends on %q which is not defined in the manifest",
+					entry.InstanceName,
+					dependency,
+				)
+			}
+		}
+	}
+
+	return m.checkForCycles()
+}
+
+func (m *Manifest) checkForCycles() error {
+	dependencies := map[string][]string{}
+	for _, entry := range m.Instances {
+		dependencies[entry.InstanceName] = entry.DependsOn
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	marks := map[string]int{}
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch marks[name] {
+		case visiting:
+			return fmt.Errorf(
+				"dependency cycle detected: %s",
+				strings.Join(append(path, name), " -> "),
+			)
+		case visited:
+			return nil
+		}
+
+		marks[name] = visiting
+		for _, dependency := range dependencies[name] {
+			if err := visit(dependency, append(path, name)); err != nil {
+				return err
+			}
+		}
+		marks[name] = visited
+		return nil
+	}
+
+	for _, entry := range m.Instances {
+		if err := visit(entry.InstanceName, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Manifest) resolvePaths(baseDir string) {
+	for i := range m.Instances {
+		entry := &m.Instances[i]
+		entry.BlueprintFile = resolveLocalPath(baseDir, entry.BlueprintFile)
+		if entry.DeployConfigFile != "" {
+			entry.DeployConfigFile = resolveLocalPath(baseDir, entry.DeployConfigFile)
+		}
+	}
+}
+
+func resolveLocalPath(baseDir, path string) string {
+	if shared.BlueprintSourceFromPath(path) != consts.BlueprintSourceFile || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(baseDir, path)
+}
+
+// LoadOperationConfigs loads the deploy config file of each instance in
+// the manifest, falling back to the provided default operation config for
+// instances that do not set a deploy config file.
+// The returned slice is in the same order as the manifest instances.
+func (m *Manifest) LoadOperationConfigs(
+	defaultConfig *types.BlueprintOperationConfig,
+) ([]*types.BlueprintOperationConfig, error) {
+	configs := make([]*types.BlueprintOperationConfig, len(m.Instances))
+	for i, entry := range m.Instances {
+		if entry.DeployConfigFile == "" {
+			configs[i] = defaultConfig
+			continue
+		}
+
+		opConfig, err := config.LoadOperationConfigFile(entry.DeployConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("instance %q: %w", entry.InstanceName, err)
+		}
+		configs[i] = opConfig
+	}
+	return configs, nil
+}
@@ -0,0 +1,138 @@
+package batchui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type ManifestSuite struct {
+	suite.Suite
+}
+
+func TestManifestSuite(t *testing.T) {
+	suite.Run(t, new(ManifestSuite))
+}
+
+func (s *ManifestSuite) Test_LoadManifest_loads_yaml_manifest_and_resolves_paths() {
+	dir := s.T().TempDir()
+	manifestFile := s.writeFile(dir, "deploy-set.yaml", `
+parallelism: 2
+instances:
+  - instanceName: networking
+    blueprintFile: networking/app.blueprint.yaml
+    deployConfigFile: networking/deploy-config.json
+  - instanceName: orders-api
+    blueprintFile: s3://blueprints/orders.blueprint.yaml
+    dependsOn: [networking]
+`)
+
+	manifest, err := LoadManifest(manifestFile)
+	s.Require().NoError(err)
+	s.Equal(2, manifest.Parallelism)
+	s.Require().Len(manifest.Instances, 2)
+	s.Equal(filepath.Join(dir, "networking/app.blueprint.yaml"), manifest.Instances[0].BlueprintFile)
+	s.Equal(filepath.Join(dir, "networking/deploy-config.json"), manifest.Instances[0].DeployConfigFile)
+	// Remote blueprint sources are left as they are.
+	s.Equal("s3://blueprints/orders.blueprint.yaml", manifest.Instances[1].BlueprintFile)
+	s.Equal([]string{"networking"}, manifest.Instances[1].DependsOn)
+}
+
+func (s *ManifestSuite) Test_LoadManifest_loads_json_manifest() {
+	dir := s.T().TempDir()
+	manifestFile := s.writeFile(
+		dir,
+		"deploy-set.json",
+		`{"instances": [{"instanceName": "networking", "blueprintFile": "/abs/app.blueprint.yaml"}]}`,
+	)
+
+	manifest, err := LoadManifest(manifestFile)
+	s.Require().NoError(err)
+	s.Equal("/abs/app.blueprint.yaml", manifest.Instances[0].BlueprintFile)
+}
+
+func (s *ManifestSuite) Test_LoadManifest_rejects_unsupported_format() {
+	manifestFile := s.writeFile(s.T().TempDir(), "deploy-set.toml", `parallelism = 2`)
+
+	_, err := LoadManifest(manifestFile)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "unsupported deployment manifest format")
+}
+
+func (s *ManifestSuite) Test_Validate_rejects_empty_manifest() {
+	err := (&Manifest{}).Validate()
+	s.Require().Error(err)
+	s.Contains(err.Error(), "at least one instance")
+}
+
+func (s *ManifestSuite) Test_Validate_rejects_duplicate_instances() {
+	manifest := &Manifest{
+		Instances: []ManifestEntry{
+			{InstanceName: "networking", BlueprintFile: "a.yaml"},
+			{InstanceName: "networking", BlueprintFile: "b.yaml"},
+		},
+	}
+	err := manifest.Validate()
+	s.Require().Error(err)
+	s.Contains(err.Error(), "defined more than once")
+}
+
+func (s *ManifestSuite) Test_Validate_rejects_missing_blueprint_file() {
+	manifest := &Manifest{
+		Instances: []ManifestEntry{{InstanceName: "networking"}},
+	}
+	err := manifest.Validate()
+	s.Require().Error(err)
+	s.Contains(err.Error(), "must have a blueprintFile")
+}
+
+func (s *ManifestSuite) Test_Validate_rejects_unknown_dependency() {
+	manifest := &Manifest{
+		Instances: []ManifestEntry{
+			{InstanceName: "orders-api", BlueprintFile: "a.yaml", DependsOn: []string{"networking"}},
+		},
+	}
+	err := manifest.Validate()
+	s.Require().Error(err)
+	s.Contains(err.Error(), "not defined in the manifest")
+}
+
+func (s *ManifestSuite) Test_Validate_rejects_dependency_cycle() {
+	manifest := &Manifest{
+		Instances: []ManifestEntry{
+			{InstanceName: "a", BlueprintFile: "a.yaml", DependsOn: []string{"c"}},
+			{InstanceName: "b", BlueprintFile: "b.yaml", DependsOn: []string{"a"}},
+			{InstanceName: "c", BlueprintFile: "c.yaml", DependsOn: []string{"b"}},
+		},
+	}
+	err := manifest.Validate()
+	s.Require().Error(err)
+	s.Contains(err.Error(), "dependency cycle detected: a -> c -> b -> a")
+}
+
+func (s *ManifestSuite) Test_LoadOperationConfigs_falls_back_to_default_config() {
+	dir := s.T().TempDir()
+	configFile := s.writeFile(dir, "deploy-config.json", `{"contextVariables": {"environment": "staging"}}`)
+	defaultConfig := &types.BlueprintOperationConfig{}
+	manifest := &Manifest{
+		Instances: []ManifestEntry{
+			{InstanceName: "networking", BlueprintFile: "a.yaml", DeployConfigFile: configFile},
+			{InstanceName: "orders-api", BlueprintFile: "b.yaml"},
+		},
+	}
+
+	configs, err := manifest.LoadOperationConfigs(defaultConfig)
+	s.Require().NoError(err)
+	s.Require().Len(configs, 2)
+	s.Equal("staging", configs[0].ContextVariables["environment"].ToString())
+	s.Same(defaultConfig, configs[1])
+}
+
+func (s *ManifestSuite) writeFile(dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
@@ -0,0 +1,429 @@
+package batchui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/newstack-cloud/deploy-cli-sdk/headless"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
+	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/deployui"
+	"go.uber.org/zap"
+)
+
+// InstanceStatus is the status of an instance in a batch deployment.
+type InstanceStatus string
+
+const (
+	// InstanceStatusPending indicates the instance is waiting for its
+	// dependencies or for a free deployment slot.
+	InstanceStatusPending InstanceStatus = "pending"
+	// InstanceStatusRunning indicates the instance is being staged and deployed.
+	InstanceStatusRunning InstanceStatus = "running"
+	// InstanceStatusSucceeded indicates the instance was deployed successfully.
+	InstanceStatusSucceeded InstanceStatus = "succeeded"
+	// InstanceStatusFailed indicates that staging or deploying the instance failed.
+	InstanceStatusFailed InstanceStatus = "failed"
+	// InstanceStatusSkipped indicates the instance was not deployed
+	// because one of its dependencies was not deployed successfully.
+	InstanceStatusSkipped InstanceStatus = "skipped"
+)
+
+var errBatchFailed = errors.New("one or more instances in the deployment manifest were not deployed")
+
+var errBatchRequiresAutoApprove = errors.New(
+	"the instances in a deployment manifest can only be deployed with auto-approve enabled",
+)
+
+// instanceRun tracks the deployment of a single manifest instance.
+// The deploy app for the instance is only created once the instance
+// is ready to be deployed.
+type instanceRun struct {
+	entry           ManifestEntry
+	operationConfig *types.BlueprintOperationConfig
+	status          InstanceStatus
+	app             tea.Model
+	err             error
+	// output captures the JSON output of the deploy app for the instance
+	// when the output is not streamed to the headless writer.
+	output     *bytes.Buffer
+	startedAt  time.Time
+	finishedAt time.Time
+}
+
+// MainModel is the top-level model for deploying the instances of a
+// deployment manifest. It runs a deployui app for each instance, routing
+// messages to the app they belong to, and renders aggregated progress.
+type MainModel struct {
+	ctx          context.Context
+	engine       engine.DeployEngine
+	logger       *zap.Logger
+	runs         []*instanceRun
+	parallelism  int
+	autoApprove  bool
+	autoRollback bool
+	force        bool
+
+	headlessMode   bool
+	headlessWriter io.Writer
+	printer        *headless.Printer
+	jsonMode       bool
+	// engineContext is the name of the selected engine context, if any.
+	engineContext string
+	// variables are the variable values provided on the command line.
+	variables []jsonout.VariableValue
+
+	spinner  spinner.Model
+	styles   *stylespkg.Styles
+	finished bool
+	quitting bool
+	Error    error
+}
+
+// startBatchMsg starts deploying the instances that have no dependencies.
+type startBatchMsg struct{}
+
+func (m MainModel) Init() tea.Cmd {
+	return tea.Batch(
+		m.spinner.Tick,
+		func() tea.Msg { return startBatchMsg{} },
+	)
+}
+
+func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case startBatchMsg:
+		return m, m.schedule()
+	case instanceMsg:
+		return m.handleInstanceMsg(msg)
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			m.quitting = true
+			return m, tea.Quit
+		case "q":
+			if m.finished {
+				m.quitting = true
+				return m, tea.Quit
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m MainModel) handleInstanceMsg(msg instanceMsg) (tea.Model, tea.Cmd) {
+	run := m.runs[msg.index]
+	if run.status != InstanceStatusRunning {
+		return m, nil
+	}
+
+	switch inner := msg.msg.(type) {
+	case tea.BatchMsg:
+		cmds := make([]tea.Cmd, 0, len(inner))
+		for _, cmd := range inner {
+			cmds = append(cmds, wrapInstanceCmd(msg.index, cmd))
+		}
+		return m, tea.Batch(cmds...)
+	case tea.QuitMsg:
+		// The deploy app quits once the deployment of the instance
+		// has completed or failed.
+		m.completeRun(run)
+		return m, m.schedule()
+	}
+
+	updated, cmd := run.app.Update(msg.msg)
+	run.app = updated
+	return m, wrapInstanceCmd(msg.index, cmd)
+}
+
+// schedule skips instances with dependencies that were not deployed and
+// starts as many ready instances as the parallelism allows.
+// Once there is nothing left to deploy, the batch is finished.
+func (m *MainModel) schedule() tea.Cmd {
+	m.skipBlockedRuns()
+
+	cmds := []tea.Cmd{}
+	running := m.countByStatus(InstanceStatusRunning)
+	for i, run := range m.runs {
+		if running >= m.parallelism {
+			break
+		}
+		if run.status != InstanceStatusPending || !m.dependenciesSucceeded(run) {
+			continue
+		}
+
+		cmd, err := m.startRun(i, run)
+		if err != nil {
+			m.failRun(run, err)
+			continue
+		}
+		running += 1
+		cmds = append(cmds, cmd)
+	}
+
+	if len(cmds) == 0 && running == 0 {
+		// Starting an instance may fail immediately, which can
+		// block instances that depend on it.
+		if m.countByStatus(InstanceStatusPending) > 0 {
+			return m.schedule()
+		}
+		return m.finish()
+	}
+
+	return tea.Batch(cmds...)
+}
+
+func (m *MainModel) skipBlockedRuns() {
+	// Skipping an instance can block instances that depend on it,
+	// so keep going until no more instances are skipped.
+	skipped := true
+	for skipped {
+		skipped = false
+		for _, run := range m.runs {
+			if run.status != InstanceStatusPending {
+				continue
+			}
+			if blocker := m.blockingDependency(run); blocker != "" {
+				run.status = InstanceStatusSkipped
+				run.err = fmt.Errorf("dependency %q was not deployed", blocker)
+				m.printRunSkipped(run)
+				skipped = true
+			}
+		}
+	}
+}
+
+func (m *MainModel) startRun(index int, run *instanceRun) (tea.Cmd, error) {
+	run.output = &bytes.Buffer{}
+	headlessWriter := io.Writer(run.output)
+	jsonMode := true
+	if m.headlessMode && !m.jsonMode {
+		// Stream the text output of each instance, prefixed with the instance name
+		// so the output of instances deployed at the same time can be told apart.
+		headlessWriter = newLinePrefixWriter(m.headlessWriter, "["+run.entry.InstanceName+"] ")
+		jsonMode = false
+	}
+
+	app, err := deployui.NewDeployApp(deployui.DeployAppConfig{
+		Context:         m.ctx,
+		DeployEngine:    m.engine,
+		Logger:          m.logger.Named(run.entry.InstanceName),
+		InstanceName:    run.entry.InstanceName,
+		BlueprintFile:   run.entry.BlueprintFile,
+		AutoRollback:    m.autoRollback,
+		Force:           m.force,
+		StageFirst:      true,
+		AutoApprove:     m.autoApprove,
+		SkipPrompts:     true,
+		Styles:          m.styles,
+		Headless:        true,
+		HeadlessWriter:  headlessWriter,
+		JSONMode:        jsonMode,
+		OperationConfig: run.operationConfig,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	run.app = *app
+	run.status = InstanceStatusRunning
+	run.startedAt = time.Now()
+	m.printRunStarted(run)
+	return wrapInstanceCmd(index, app.Init()), nil
+}
+
+func (m *MainModel) completeRun(run *instanceRun) {
+	run.finishedAt = time.Now()
+	app, ok := run.app.(deployui.MainModel)
+	if !ok {
+		m.failRun(run, errors.New("internal error: unexpected deploy model type"))
+		return
+	}
+
+	if app.Error != nil {
+		m.failRun(run, app.Error)
+		return
+	}
+
+	if _, completed := app.DeploymentResult(); !completed {
+		m.failRun(run, errors.New(
+			"deployment stopped before completing, drift may have been detected during change staging",
+		))
+		return
+	}
+
+	run.status = InstanceStatusSucceeded
+	m.printRunSucceeded(run)
+}
+
+func (m *MainModel) failRun(run *instanceRun, err error) {
+	if run.finishedAt.IsZero() {
+		run.finishedAt = time.Now()
+	}
+	run.status = InstanceStatusFailed
+	run.err = err
+	m.printRunFailed(run)
+}
+
+func (m *MainModel) finish() tea.Cmd {
+	m.finished = true
+	if m.countByStatus(InstanceStatusSucceeded) != len(m.runs) {
+		m.Error = errBatchFailed
+	}
+
+	if m.headlessMode {
+		m.dispatchHeadlessSummary()
+		return tea.Quit
+	}
+	return nil
+}
+
+func (m *MainModel) dependenciesSucceeded(run *instanceRun) bool {
+	for _, dependency := range run.entry.DependsOn {
+		if m.findRun(dependency).status != InstanceStatusSucceeded {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *MainModel) blockingDependency(run *instanceRun) string {
+	for _, dependency := range run.entry.DependsOn {
+		status := m.findRun(dependency).status
+		if status == InstanceStatusFailed || status == InstanceStatusSkipped {
+			return dependency
+		}
+	}
+	return ""
+}
+
+func (m *MainModel) findRun(instanceName string) *instanceRun {
+	for _, run := range m.runs {
+		if run.entry.InstanceName == instanceName {
+			return run
+		}
+	}
+	return nil
+}
+
+func (m *MainModel) countByStatus(status InstanceStatus) int {
+	count := 0
+	for _, run := range m.runs {
+		if run.status == status {
+			count += 1
+		}
+	}
+	return count
+}
+
+// InstanceStatuses returns the status of each instance in manifest order.
+func (m *MainModel) InstanceStatuses() []InstanceStatus {
+	statuses := make([]InstanceStatus, len(m.runs))
+	for i, run := range m.runs {
+		statuses[i] = run.status
+	}
+	return statuses
+}
+
+// BatchAppConfig holds the configuration for creating a new batch deploy application.
+type BatchAppConfig struct {
+	// Context is bound to the engine calls made for each instance so they are
+	// cancelled when the command context is cancelled (e.g. on Ctrl+C).
+	Context      context.Context
+	DeployEngine engine.DeployEngine
+	Logger       *zap.Logger
+	Manifest     *Manifest
+	// OperationConfigs holds the operation config for each manifest instance,
+	// in the same order as the manifest instances.
+	OperationConfigs []*types.BlueprintOperationConfig
+	// Parallelism overrides the parallelism set in the manifest when greater than 0.
+	Parallelism int
+	// AutoApprove must be set as the changes staged for each instance are
+	// deployed without a prompt for approval, the batch app can not be
+	// created without it.
+	AutoApprove    bool
+	AutoRollback   bool
+	Force          bool
+	Styles         *stylespkg.Styles
+	Headless       bool
+	HeadlessWriter io.Writer
+	JSONMode       bool
+	// EngineContext is the name of the engine context the instances are
+	// deployed to, shown in the header and included in JSON output.
+	EngineContext string
+	// Variables are the blueprint and context variable values provided
+	// on the command line, included in JSON output.
+	Variables []jsonout.VariableValue
+}
+
+// NewBatchApp creates a new batch deploy application with the given configuration.
+func NewBatchApp(cfg BatchAppConfig) (*MainModel, error) {
+	if len(cfg.OperationConfigs) != len(cfg.Manifest.Instances) {
+		return nil, errors.New("an operation config must be provided for each manifest instance")
+	}
+	if !cfg.AutoApprove {
+		return nil, errBatchRequiresAutoApprove
+	}
+
+	runs := make([]*instanceRun, len(cfg.Manifest.Instances))
+	for i, entry := range cfg.Manifest.Instances {
+		runs[i] = &instanceRun{
+			entry:           entry,
+			operationConfig: cfg.OperationConfigs[i],
+			status:          InstanceStatusPending,
+		}
+	}
+
+	parallelism := cfg.Parallelism
+	if parallelism <= 0 {
+		parallelism = cfg.Manifest.Parallelism
+	}
+	if parallelism <= 0 {
+		parallelism = DefaultParallelism
+	}
+
+	logger := cfg.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = cfg.Styles.Spinner
+
+	var printer *headless.Printer
+	if cfg.Headless && !cfg.JSONMode && cfg.HeadlessWriter != nil {
+		printer = headless.NewPrinter(headless.NewPrefixedWriter(cfg.HeadlessWriter, "[batch] "), 80)
+	}
+
+	return &MainModel{
+		ctx:            cfg.Context,
+		engine:         cfg.DeployEngine,
+		logger:         logger,
+		runs:           runs,
+		parallelism:    parallelism,
+		autoApprove:    cfg.AutoApprove,
+		autoRollback:   cfg.AutoRollback,
+		force:          cfg.Force,
+		headlessMode:   cfg.Headless,
+		headlessWriter: cfg.HeadlessWriter,
+		printer:        printer,
+		jsonMode:       cfg.JSONMode,
+		engineContext:  cfg.EngineContext,
+		variables:      cfg.Variables,
+		spinner:        s,
+		styles:         cfg.Styles,
+	}, nil
+}
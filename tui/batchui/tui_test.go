@@ -0,0 +1,214 @@
+package batchui
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/changes"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/container"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/provider"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
+	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
+	"github.com/newstack-cloud/deploy-cli-sdk/testutils"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+type BatchTUISuite struct {
+	suite.Suite
+	styles *stylespkg.Styles
+}
+
+func TestBatchTUISuite(t *testing.T) {
+	suite.Run(t, new(BatchTUISuite))
+}
+
+func (s *BatchTUISuite) SetupTest() {
+	s.styles = stylespkg.NewStyles(lipgloss.NewRenderer(os.Stdout), stylespkg.NewBluelinkPalette())
+}
+
+func (s *BatchTUISuite) Test_headless_json_deploys_all_instances() {
+	manifest := testManifest()
+	headlessOutput := &bytes.Buffer{}
+	model := s.newBatchApp(manifest, testDeployEngine(nil), headlessOutput, true, 2)
+
+	testModel := teatest.NewTestModel(s.T(), *model, teatest.WithInitialTermSize(300, 100))
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(10*time.Second))
+
+	finalModel := testModel.FinalModel(s.T()).(MainModel)
+	s.Nil(finalModel.Error)
+	s.Equal(
+		[]InstanceStatus{InstanceStatusSucceeded, InstanceStatusSucceeded, InstanceStatusSucceeded},
+		finalModel.InstanceStatuses(),
+	)
+
+	output := jsonout.BatchDeployOutput{}
+	s.Require().NoError(json.Unmarshal(headlessOutput.Bytes(), &output))
+	s.True(output.Success)
+	s.Equal(jsonout.BatchDeploySummary{Total: 3, Succeeded: 3}, output.Summary)
+	s.Require().Len(output.Instances, 3)
+	s.Equal("orders-api", output.Instances[1].InstanceName)
+	s.Equal([]string{"networking"}, output.Instances[1].DependsOn)
+	s.NotEmpty(output.Instances[1].Result)
+}
+
+func (s *BatchTUISuite) Test_headless_json_skips_dependents_of_failed_instance() {
+	manifest := testManifest()
+	headlessOutput := &bytes.Buffer{}
+	deployEngine := testDeployEngine(map[string]string{
+		"networking": "blueprint validation failed",
+	})
+	model := s.newBatchApp(manifest, deployEngine, headlessOutput, true, 2)
+
+	testModel := teatest.NewTestModel(s.T(), *model, teatest.WithInitialTermSize(300, 100))
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(10*time.Second))
+
+	finalModel := testModel.FinalModel(s.T()).(MainModel)
+	s.ErrorIs(finalModel.Error, errBatchFailed)
+	s.Equal(
+		[]InstanceStatus{InstanceStatusFailed, InstanceStatusSkipped, InstanceStatusSucceeded},
+		finalModel.InstanceStatuses(),
+	)
+
+	output := jsonout.BatchDeployOutput{}
+	s.Require().NoError(json.Unmarshal(headlessOutput.Bytes(), &output))
+	s.False(output.Success)
+	s.Equal(jsonout.BatchDeploySummary{Total: 3, Succeeded: 1, Failed: 1, Skipped: 1}, output.Summary)
+	s.Contains(output.Instances[0].Error, "blueprint validation failed")
+	s.Contains(output.Instances[1].Error, "dependency \"networking\" was not deployed")
+}
+
+func (s *BatchTUISuite) Test_headless_text_prefixes_output_with_instance_name() {
+	manifest := testManifest()
+	headlessOutput := &bytes.Buffer{}
+	model := s.newBatchApp(manifest, testDeployEngine(nil), headlessOutput, false, 1)
+
+	testModel := teatest.NewTestModel(s.T(), *model, teatest.WithInitialTermSize(300, 100))
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(10*time.Second))
+
+	finalModel := testModel.FinalModel(s.T()).(MainModel)
+	s.Nil(finalModel.Error)
+
+	output := headlessOutput.String()
+	s.Contains(output, "[batch] Deploying networking")
+	s.Contains(output, "[networking] ")
+	s.Contains(output, "[orders-api] ")
+	s.Contains(output, "[batch] ✓ Deployed 3 instances")
+}
+
+func (s *BatchTUISuite) Test_NewBatchApp_uses_manifest_parallelism_when_not_overridden() {
+	manifest := testManifest()
+	manifest.Parallelism = 3
+	model := s.newBatchApp(manifest, testDeployEngine(nil), &bytes.Buffer{}, true, 0)
+	s.Equal(3, model.parallelism)
+
+	manifest.Parallelism = 0
+	model = s.newBatchApp(manifest, testDeployEngine(nil), &bytes.Buffer{}, true, 0)
+	s.Equal(DefaultParallelism, model.parallelism)
+}
+
+func (s *BatchTUISuite) Test_NewBatchApp_requires_operation_config_per_instance() {
+	_, err := NewBatchApp(BatchAppConfig{
+		Manifest:         testManifest(),
+		OperationConfigs: []*types.BlueprintOperationConfig{{}},
+		AutoApprove:      true,
+		Styles:           s.styles,
+	})
+	s.Require().Error(err)
+}
+
+func (s *BatchTUISuite) Test_NewBatchApp_requires_auto_approve() {
+	manifest := testManifest()
+	_, err := NewBatchApp(BatchAppConfig{
+		Manifest:         manifest,
+		OperationConfigs: make([]*types.BlueprintOperationConfig, len(manifest.Instances)),
+		Styles:           s.styles,
+	})
+	s.Require().ErrorIs(err, errBatchRequiresAutoApprove)
+}
+
+func (s *BatchTUISuite) newBatchApp(
+	manifest *Manifest,
+	deployEngine engine.DeployEngine,
+	headlessOutput *bytes.Buffer,
+	jsonMode bool,
+	parallelism int,
+) *MainModel {
+	opConfigs := make([]*types.BlueprintOperationConfig, len(manifest.Instances))
+	for i := range opConfigs {
+		opConfigs[i] = &types.BlueprintOperationConfig{}
+	}
+
+	model, err := NewBatchApp(BatchAppConfig{
+		DeployEngine:     deployEngine,
+		Logger:           zap.NewNop(),
+		Manifest:         manifest,
+		OperationConfigs: opConfigs,
+		Parallelism:      parallelism,
+		AutoApprove:      true,
+		Styles:           s.styles,
+		Headless:         true,
+		HeadlessWriter:   headlessOutput,
+		JSONMode:         jsonMode,
+	})
+	s.Require().NoError(err)
+	return model
+}
+
+func testManifest() *Manifest {
+	return &Manifest{
+		Instances: []ManifestEntry{
+			{InstanceName: "networking", BlueprintFile: "networking.blueprint.yaml"},
+			{
+				InstanceName:  "orders-api",
+				BlueprintFile: "orders.blueprint.yaml",
+				DependsOn:     []string{"networking"},
+			},
+			{InstanceName: "reporting", BlueprintFile: "reporting.blueprint.yaml"},
+		},
+	}
+}
+
+// testDeployEngine creates a deploy engine that stages and deploys each
+// instance of the test manifest, apart from the instances in stagingErrs
+// for which staging fails with the mapped error message.
+func testDeployEngine(stagingErrs map[string]string) engine.DeployEngine {
+	builder := testutils.NewScenarioBuilder()
+	for _, entry := range testManifest().Instances {
+		instance := builder.Instance(entry.InstanceName)
+		if message, failsStaging := stagingErrs[entry.InstanceName]; failsStaging {
+			instance.Stage(&testutils.StageScenario{
+				Error: &engine.SessionError{Type: engine.SessionErrorOther, Message: message},
+			})
+			continue
+		}
+		instance.
+			StagesChanges("", &types.ChangeStagingEvent{
+				CompleteChanges: &types.CompleteChangesEventData{
+					Changes: &changes.BlueprintChanges{
+						NewResources: map[string]provider.Changes{
+							"test-resource": {},
+						},
+					},
+				},
+			}).
+			Deploys(&types.BlueprintInstanceEvent{
+				DeployEvent: container.DeployEvent{
+					FinishEvent: &container.DeploymentFinishedMessage{
+						InstanceID:  entry.InstanceName,
+						Status:      core.InstanceStatusDeployed,
+						EndOfStream: true,
+					},
+				},
+			})
+	}
+	return builder.Build()
+}
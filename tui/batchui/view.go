@@ -0,0 +1,124 @@
+package batchui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	sdkstrings "github.com/newstack-cloud/deploy-cli-sdk/strings"
+)
+
+func (m MainModel) View() string {
+	if m.headlessMode {
+		return ""
+	}
+	if m.quitting && !m.finished {
+		return m.styles.Muted.Margin(1, 0, 2, 4).Render("See you next time.")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n")
+	total := len(m.runs)
+	sb.WriteString(m.styles.Title.MarginLeft(2).Render(fmt.Sprintf(
+		"Deploying %d %s from manifest",
+		total,
+		sdkstrings.Pluralize(total, "instance", "instances"),
+	)))
+	sb.WriteString(m.styles.Muted.Render(fmt.Sprintf(" (parallelism %d)", m.parallelism)))
+	sb.WriteString("\n")
+	if m.engineContext != "" {
+		sb.WriteString(m.styles.Muted.MarginLeft(2).Render("Context: " + m.engineContext))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	nameWidth := m.longestInstanceName()
+	for _, run := range m.runs {
+		sb.WriteString("  ")
+		sb.WriteString(m.renderStatusIcon(run))
+		sb.WriteString(" ")
+		sb.WriteString(fmt.Sprintf("%-*s", nameWidth, run.entry.InstanceName))
+		sb.WriteString("  ")
+		sb.WriteString(m.renderRunDetails(run))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(m.styles.Muted.MarginLeft(2).Render(fmt.Sprintf(
+		"%d succeeded, %d running, %d pending, %d failed, %d skipped",
+		m.countByStatus(InstanceStatusSucceeded),
+		m.countByStatus(InstanceStatusRunning),
+		m.countByStatus(InstanceStatusPending),
+		m.countByStatus(InstanceStatusFailed),
+		m.countByStatus(InstanceStatusSkipped),
+	)))
+	sb.WriteString("\n\n")
+
+	if m.finished {
+		if m.Error != nil {
+			sb.WriteString(m.styles.Error.MarginLeft(2).Render("✗ " + m.Error.Error()))
+		} else {
+			sb.WriteString(m.styles.Success.MarginLeft(2).Render("✓ All instances deployed"))
+		}
+		sb.WriteString("\n\n  ")
+		sb.WriteString(m.styles.Key.Render("q"))
+		sb.WriteString(m.styles.Muted.Render(" quit"))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+func (m MainModel) renderStatusIcon(run *instanceRun) string {
+	switch run.status {
+	case InstanceStatusRunning:
+		return m.spinner.View()
+	case InstanceStatusSucceeded:
+		return m.styles.Success.Render("✓")
+	case InstanceStatusFailed:
+		return m.styles.Error.Render("✗")
+	case InstanceStatusSkipped:
+		return m.styles.Warning.Render("-")
+	default:
+		return m.styles.Muted.Render("○")
+	}
+}
+
+func (m MainModel) renderRunDetails(run *instanceRun) string {
+	switch run.status {
+	case InstanceStatusRunning:
+		return m.styles.Info.Render("deploying") +
+			m.styles.Muted.Render(" "+run.duration().Round(time.Second).String())
+	case InstanceStatusSucceeded:
+		return m.styles.Success.Render("deployed") +
+			m.styles.Muted.Render(" in "+run.duration().Round(time.Second).String())
+	case InstanceStatusFailed:
+		return m.styles.Error.Render("failed: " + run.err.Error())
+	case InstanceStatusSkipped:
+		return m.styles.Warning.Render("skipped: " + run.err.Error())
+	default:
+		waitingFor := m.pendingDependencies(run)
+		if len(waitingFor) > 0 {
+			return m.styles.Muted.Render("waiting for " + strings.Join(waitingFor, ", "))
+		}
+		return m.styles.Muted.Render("queued")
+	}
+}
+
+func (m MainModel) pendingDependencies(run *instanceRun) []string {
+	waitingFor := []string{}
+	for _, dependency := range run.entry.DependsOn {
+		if m.findRun(dependency).status != InstanceStatusSucceeded {
+			waitingFor = append(waitingFor, dependency)
+		}
+	}
+	return waitingFor
+}
+
+func (m MainModel) longestInstanceName() int {
+	longest := 0
+	for _, run := range m.runs {
+		longest = max(longest, len(run.entry.InstanceName))
+	}
+	return longest
+}
@@ -0,0 +1,42 @@
+package batchui
+
+import (
+	"bytes"
+	"io"
+)
+
+// linePrefixWriter is an io.Writer that prefixes every line written to the
+// underlying writer, used to attribute the headless output of each instance
+// when multiple instances are deployed at the same time.
+// Partial lines are held back until the rest of the line is written.
+type linePrefixWriter struct {
+	w       io.Writer
+	prefix  []byte
+	pending []byte
+}
+
+func newLinePrefixWriter(w io.Writer, prefix string) *linePrefixWriter {
+	return &linePrefixWriter{w: w, prefix: []byte(prefix)}
+}
+
+func (lw *linePrefixWriter) Write(p []byte) (int, error) {
+	lw.pending = append(lw.pending, p...)
+	for {
+		newline := bytes.IndexByte(lw.pending, '\n')
+		if newline < 0 {
+			return len(p), nil
+		}
+
+		line := lw.pending[:newline+1]
+		var err error
+		if len(bytes.TrimSpace(line)) == 0 {
+			_, err = lw.w.Write(line)
+		} else {
+			_, err = lw.w.Write(append(append([]byte{}, lw.prefix...), line...))
+		}
+		lw.pending = lw.pending[newline+1:]
+		if err != nil {
+			return len(p), err
+		}
+	}
+}
@@ -19,7 +19,19 @@ type DeployEventMsg types.BlueprintInstanceEvent
 
 // DeployStreamClosedMsg is sent when the deploy event stream is closed.
 // This typically happens due to a stream timeout or the connection being dropped.
-type DeployStreamClosedMsg struct{}
+type DeployStreamClosedMsg struct {
+	// stream is the event channel that was closed, used to ignore
+	// channels that have been replaced by resuming the stream.
+	stream chan types.BlueprintInstanceEvent
+}
+
+// DeployStreamReconnectMsg is sent when it is time to resume the deploy
+// event stream after the connection to the deploy engine was lost.
+type DeployStreamReconnectMsg struct{}
+
+// DeployStreamResumedMsg is sent when the deploy event stream has been
+// resumed from the last received event.
+type DeployStreamResumedMsg struct{}
 
 // DeployErrorMsg is a message containing an error from the deployment process.
 type DeployErrorMsg struct {
@@ -32,7 +44,8 @@ type DestroyChangesetErrorMsg struct{}
 
 // DeployStartedMsg is a message indicating that deployment has started.
 type DeployStartedMsg struct {
-	InstanceID string
+	InstanceID  string
+	LastEventID string
 }
 
 // StartDeployMsg is a message to initiate deployment.
@@ -74,7 +87,10 @@ func startDeploymentCmd(model DeployModel) tea.Cmd {
 			return DeployErrorMsg{Err: err}
 		}
 
-		return DeployStartedMsg{InstanceID: response.Data.InstanceID}
+		return DeployStartedMsg{
+			InstanceID:  response.Data.InstanceID,
+			LastEventID: response.LastEventID,
+		}
 	}
 }
 
@@ -140,12 +156,38 @@ func waitForNextDeployEventCmd(model DeployModel) tea.Cmd {
 	return func() tea.Msg {
 		event, ok := <-model.eventStream
 		if !ok {
-			return DeployStreamClosedMsg{}
+			return DeployStreamClosedMsg{stream: model.eventStream}
 		}
 		return DeployEventMsg(event)
 	}
 }
 
+// reconnectDeployStreamCmd waits before resuming the deploy event stream.
+func reconnectDeployStreamCmd(wait time.Duration) tea.Cmd {
+	return tea.Tick(wait, func(time.Time) tea.Msg {
+		return DeployStreamReconnectMsg{}
+	})
+}
+
+// resumeDeployStreamCmd resumes the deploy event stream from the last
+// received event.
+func resumeDeployStreamCmd(model DeployModel) tea.Cmd {
+	return func() tea.Msg {
+		err := model.engine.StreamBlueprintInstanceEvents(
+			model.reqCtx(),
+			model.streamResumer.StreamID(),
+			model.streamResumer.LastEventID(),
+			model.eventStream,
+			model.errStream,
+		)
+		if err != nil {
+			return DeployErrorMsg{Err: err}
+		}
+
+		return DeployStreamResumedMsg{}
+	}
+}
+
 func checkForErrCmd(model DeployModel) tea.Cmd {
 	return func() tea.Msg {
 		var err error
@@ -242,7 +284,10 @@ func continueDeploymentCmd(model DeployModel) tea.Cmd {
 			return DeployErrorMsg{Err: err}
 		}
 
-		return DeployStartedMsg{InstanceID: response.Data.InstanceID}
+		return DeployStartedMsg{
+			InstanceID:  response.Data.InstanceID,
+			LastEventID: response.LastEventID,
+		}
 	}
 }
 
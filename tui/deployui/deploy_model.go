@@ -3,7 +3,6 @@ package deployui
 import (
 	"context"
 	"errors"
-	"fmt"
 	"io"
 
 	"github.com/charmbracelet/bubbles/spinner"
@@ -17,6 +16,7 @@ import (
 	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
 	"github.com/newstack-cloud/deploy-cli-sdk/engine"
 	"github.com/newstack-cloud/deploy-cli-sdk/headless"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
 	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
 	"github.com/newstack-cloud/deploy-cli-sdk/tui/driftui"
 	"github.com/newstack-cloud/deploy-cli-sdk/tui/shared"
@@ -222,6 +222,9 @@ type DeployModel struct {
 	engine      engine.DeployEngine
 	eventStream chan types.BlueprintInstanceEvent
 	errStream   chan error
+	// streamResumer tracks the last received event so the stream
+	// can be resumed when the connection to the engine drops.
+	streamResumer shared.StreamResumer
 
 	// Config
 	blueprintFile   string
@@ -240,6 +243,13 @@ type DeployModel struct {
 	headlessWriter io.Writer
 	printer        *headless.Printer
 	jsonMode       bool
+	// ndjsonStream is set when streaming events as NDJSON,
+	// each deployment event is written as it is received.
+	ndjsonStream *jsonout.EventStream
+	// engineContext is the name of the selected engine context, if any.
+	engineContext string
+	// variables are the variable values provided on the command line.
+	variables []jsonout.VariableValue
 
 	styles  *stylespkg.Styles
 	logger  *zap.Logger
@@ -269,7 +279,11 @@ func (m DeployModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case DestroyChangesetErrorMsg:
 		return m.handleDestroyChangesetError()
 	case DeployStreamClosedMsg:
-		return m.handleDeployStreamClosed()
+		return m.handleDeployStreamClosed(msg)
+	case DeployStreamReconnectMsg:
+		return m.handleDeployStreamReconnect()
+	case DeployStreamResumedMsg:
+		return m.handleDeployStreamResumed()
 	case PreDeployInstanceStateFetchedMsg:
 		return m.handlePreDeployInstanceStateFetched(msg)
 	case PostDeployInstanceStateFetchedMsg:
@@ -395,7 +409,9 @@ func (m DeployModel) handlePreDeployInstanceStateFetched(msg PreDeployInstanceSt
 func (m DeployModel) handleDeployStarted(msg DeployStartedMsg) (tea.Model, tea.Cmd) {
 	m.instanceID = msg.InstanceID
 	m.streaming = true
+	m.streamResumer.Start(msg.InstanceID, msg.LastEventID)
 	m.footerRenderer.InstanceID = msg.InstanceID
+	m.footerRenderer.Reconnecting = nil
 
 	if m.headlessMode && !m.jsonMode {
 		m.printHeadlessHeader()
@@ -411,6 +427,8 @@ func (m DeployModel) handleDeployStarted(msg DeployStartedMsg) (tea.Model, tea.C
 
 func (m DeployModel) handleDeployEvent(msg DeployEventMsg) (tea.Model, tea.Cmd) {
 	event := types.BlueprintInstanceEvent(msg)
+	m.streamResumer.RecordEvent(event.ID)
+	m.ndjsonStream.WriteBlueprintInstanceEvent(jsonout.OperationDeploy, &event)
 	m.processEvent(&event)
 	m.splitPane.UpdateItems(ToSplitPaneItems(m.items))
 	// Explicitly refresh viewports to ensure details view is updated
@@ -434,6 +452,7 @@ func (m DeployModel) handleDeployEvent(msg DeployEventMsg) (tea.Model, tea.Cmd)
 
 	// Inline finish handling to ensure state is preserved in the returned model
 	m.finished = true
+	m.streamResumer.Stop()
 	m.finalStatus = finishData.Status
 	m.failureReasons = finishData.FailureReasons
 	m.skippedRollbackItems = finishData.SkippedRollbackItems
@@ -465,6 +484,13 @@ func (m DeployModel) handleDeployError(msg DeployErrorMsg) (tea.Model, tea.Cmd)
 		return m, nil
 	}
 
+	if m.streamResumer.CanResume() && shared.IsStreamConnectionError(msg.Err) {
+		if cmd, resuming := m.resumeStream(); resuming {
+			return m, cmd
+		}
+		msg.Err = m.streamResumer.GaveUpError(msg.Err)
+	}
+
 	m.err = msg.Err
 	if m.headlessMode {
 		if m.jsonMode {
@@ -490,13 +516,27 @@ func (m DeployModel) handleDestroyChangesetError() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m DeployModel) handleDeployStreamClosed() (tea.Model, tea.Cmd) {
+func (m DeployModel) handleDeployStreamClosed(msg DeployStreamClosedMsg) (tea.Model, tea.Cmd) {
+	if msg.stream != nil && msg.stream != m.eventStream {
+		// The closed stream was replaced when the stream was resumed.
+		return m, nil
+	}
+
 	// The deploy event stream was closed (typically due to timeout).
-	// If deployment hasn't finished, mark it as interrupted.
+	// Resume the stream from the last received event if possible,
+	// otherwise if deployment hasn't finished, mark it as interrupted.
 	if !m.finished {
+		err := errors.New("deployment event stream closed unexpectedly (connection timeout or dropped)")
+		if m.streamResumer.CanResume() {
+			if cmd, resuming := m.resumeStream(); resuming {
+				return m, cmd
+			}
+			err = m.streamResumer.GaveUpError(err)
+		}
+
 		m.finished = true
 		m.streaming = false
-		m.err = fmt.Errorf("deployment event stream closed unexpectedly (connection timeout or dropped)")
+		m.err = err
 		if m.headlessMode {
 			if m.jsonMode {
 				m.outputJSONError(m.err)
@@ -509,6 +549,48 @@ func (m DeployModel) handleDeployStreamClosed() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// resumeStream schedules an attempt to resume the deploy event stream after
+// the connection to the engine was lost, false is returned when the
+// reconnection budget has been used up.
+func (m *DeployModel) resumeStream() (tea.Cmd, bool) {
+	if m.streamResumer.Reconnecting() {
+		// An attempt to resume the lost stream has already been scheduled.
+		return nil, true
+	}
+
+	attempt, ok := m.streamResumer.NextAttempt()
+	if !ok {
+		return nil, false
+	}
+
+	m.footerRenderer.Reconnecting = &attempt
+	if m.headlessMode && !m.jsonMode {
+		m.printHeadlessReconnectNotice(attempt)
+	}
+	return reconnectDeployStreamCmd(attempt.Wait), true
+}
+
+func (m DeployModel) handleDeployStreamReconnect() (tea.Model, tea.Cmd) {
+	if m.finished || !m.streamResumer.CanResume() {
+		return m, nil
+	}
+
+	// Events and errors are received on new channels so that
+	// the lost stream does not interfere with the resumed stream.
+	m.eventStream = make(chan types.BlueprintInstanceEvent)
+	m.errStream = make(chan error)
+	return m, resumeDeployStreamCmd(m)
+}
+
+func (m DeployModel) handleDeployStreamResumed() (tea.Model, tea.Cmd) {
+	m.streamResumer.Resumed()
+	m.footerRenderer.Reconnecting = nil
+	return m, tea.Batch(
+		waitForNextDeployEventCmd(m),
+		checkForErrCmd(m),
+	)
+}
+
 func (m DeployModel) handlePostDeployInstanceStateFetched(msg PostDeployInstanceStateFetchedMsg) (tea.Model, tea.Cmd) {
 	// Store the fetched instance state for use in rendering outputs
 	m.postDeployInstanceState = msg.InstanceState
@@ -1003,9 +1085,23 @@ type DeployModelConfig struct {
 	HeadlessWriter   io.Writer
 	ChangesetChanges *changes.BlueprintChanges
 	JSONMode         bool
+	// NDJSONStream is an optional stream that deployment events are written to
+	// as NDJSON, when set the final result is written to the stream
+	// instead of as a pretty-printed JSON object.
+	// This is only used in JSON mode.
+	NDJSONStream *jsonout.EventStream
+	// EngineContext is the name of the engine context the operation runs
+	// against, shown in the header and included in JSON output.
+	EngineContext string
+	// Variables are the blueprint and context variable values provided
+	// on the command line, included in JSON output.
+	Variables []jsonout.VariableValue
 	// OperationConfig carries provider/transformer/context-variable values
 	// (including the deploy target) sent to the engine in the deploy payload.
 	OperationConfig *types.BlueprintOperationConfig
+	// StreamReconnect determines how the deploy event stream is resumed
+	// when the connection to the deploy engine drops.
+	StreamReconnect shared.StreamReconnectPolicy
 }
 
 // reqCtx returns the model's bound context, defaulting to context.Background()
@@ -1021,9 +1117,11 @@ func (m DeployModel) reqCtx() context.Context {
 func NewDeployModel(cfg DeployModelConfig) DeployModel {
 	detailsRenderer, sectionGrouper, footerRenderer := createDeployRenderers(cfg.InstanceID, cfg.InstanceName, cfg.ChangesetID)
 	splitPaneConfig := createDeploySplitPaneConfig(cfg.Styles, detailsRenderer, sectionGrouper, footerRenderer)
+	splitPaneConfig.Subtitle = shared.EngineContextSubtitle(cfg.EngineContext)
 
 	driftDetailsRenderer, driftSectionGrouper, driftFooterRenderer := createDriftRenderers()
 	driftSplitPaneConfig := createDriftSplitPaneConfig(cfg.Styles, driftDetailsRenderer, driftSectionGrouper, driftFooterRenderer)
+	driftSplitPaneConfig.Subtitle = shared.EngineContextSubtitle(cfg.EngineContext)
 
 	printer := createHeadlessPrinter(cfg.IsHeadless, cfg.HeadlessWriter)
 
@@ -1058,9 +1156,13 @@ func NewDeployModel(cfg DeployModelConfig) DeployModel {
 		headlessWriter:          cfg.HeadlessWriter,
 		printer:                 printer,
 		jsonMode:                cfg.JSONMode,
+		ndjsonStream:            cfg.NDJSONStream,
+		engineContext:           cfg.EngineContext,
+		variables:               cfg.Variables,
 		spinner:                 createDeploySpinner(cfg.Styles),
 		eventStream:             make(chan types.BlueprintInstanceEvent),
 		errStream:               make(chan error),
+		streamResumer:           shared.NewStreamResumer(cfg.StreamReconnect),
 		resourcesByName:         resourcesByName,
 		childrenByName:          childrenByName,
 		linksByName:             linksByName,
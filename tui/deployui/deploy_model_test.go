@@ -3,6 +3,7 @@ package deployui
 import (
 	"bytes"
 	"errors"
+	"net/http"
 	"os"
 	"testing"
 	"time"
@@ -12,9 +13,11 @@ import (
 	"github.com/newstack-cloud/bluelink/libs/blueprint/container"
 	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
 	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	engineerrors "github.com/newstack-cloud/bluelink/libs/deploy-engine-client/errors"
 	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
 	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
 	"github.com/newstack-cloud/deploy-cli-sdk/testutils"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/shared"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/zap"
 )
@@ -236,3 +239,85 @@ func (s *DeployModelBehaviourSuite) Test_handleDeployError_non_headless_stores_e
 
 	s.Equal(testErr, dm.Err())
 }
+
+func (s *DeployModelBehaviourSuite) Test_resumes_the_stream_when_it_closes_before_the_deployment_finishes() {
+	headlessOutput := &bytes.Buffer{}
+	model := NewDeployModel(DeployModelConfig{
+		DeployEngine:   testutils.NewTestDeployEngineWithDeployment(nil, "inst", nil),
+		Logger:         zap.NewNop(),
+		Styles:         s.styles,
+		IsHeadless:     true,
+		HeadlessWriter: headlessOutput,
+	})
+
+	updatedModel, _ := model.Update(DeployStartedMsg{InstanceID: "inst", LastEventID: "event-1"})
+	updatedModel, _ = updatedModel.Update(DeployEventMsg(*resourceEvent(
+		"r1", core.ResourceStatusCreating, core.PreciseResourceStatusCreating,
+	)))
+	dm := updatedModel.(DeployModel)
+	lostStream := dm.eventStream
+
+	updatedModel, cmd := dm.Update(DeployStreamClosedMsg{stream: lostStream})
+	dm = updatedModel.(DeployModel)
+	s.Nil(dm.Err())
+	s.NotNil(cmd)
+	s.Require().NotNil(dm.footerRenderer.Reconnecting)
+	s.Equal(1, dm.footerRenderer.Reconnecting.Attempt)
+	s.Contains(headlessOutput.String(), "reconnecting in 1s (attempt 1 of 5)")
+
+	updatedModel, cmd = dm.Update(DeployStreamReconnectMsg{})
+	dm = updatedModel.(DeployModel)
+	s.NotEqual(lostStream, dm.eventStream)
+	s.Require().NotNil(cmd)
+	s.IsType(DeployStreamResumedMsg{}, cmd())
+
+	updatedModel, _ = dm.Update(DeployStreamResumedMsg{})
+	dm = updatedModel.(DeployModel)
+	s.Nil(dm.footerRenderer.Reconnecting)
+	s.False(dm.streamResumer.Reconnecting())
+
+	// The lost stream being closed is ignored once it has been replaced.
+	updatedModel, _ = dm.Update(DeployStreamClosedMsg{stream: lostStream})
+	dm = updatedModel.(DeployModel)
+	s.Nil(dm.Err())
+	s.False(dm.streamResumer.Reconnecting())
+}
+
+func (s *DeployModelBehaviourSuite) Test_resumes_the_stream_for_transient_stream_errors() {
+	model := NewDeployModel(DeployModelConfig{
+		DeployEngine:   testutils.NewTestDeployEngineWithDeployment(nil, "inst", nil),
+		Logger:         zap.NewNop(),
+		Styles:         s.styles,
+		HeadlessWriter: &bytes.Buffer{},
+	})
+
+	updatedModel, _ := model.Update(DeployStartedMsg{InstanceID: "inst"})
+	updatedModel, cmd := updatedModel.Update(DeployErrorMsg{
+		Err: &engineerrors.ClientError{StatusCode: http.StatusBadGateway},
+	})
+	dm := updatedModel.(DeployModel)
+
+	s.Nil(dm.Err())
+	s.NotNil(cmd)
+	s.True(dm.streamResumer.Reconnecting())
+}
+
+func (s *DeployModelBehaviourSuite) Test_fails_once_the_stream_reconnection_budget_is_used_up() {
+	model := NewDeployModel(DeployModelConfig{
+		DeployEngine:    testutils.NewTestDeployEngineWithDeployment(nil, "inst", nil),
+		Logger:          zap.NewNop(),
+		Styles:          s.styles,
+		HeadlessWriter:  &bytes.Buffer{},
+		StreamReconnect: shared.StreamReconnectPolicy{MaxAttempts: 1},
+	})
+
+	updatedModel, _ := model.Update(DeployStartedMsg{InstanceID: "inst"})
+	updatedModel, _ = updatedModel.Update(DeployStreamClosedMsg{})
+	updatedModel, _ = updatedModel.Update(DeployStreamResumedMsg{})
+	updatedModel, _ = updatedModel.Update(DeployStreamClosedMsg{})
+	dm := updatedModel.(DeployModel)
+
+	s.Require().NotNil(dm.Err())
+	s.Contains(dm.Err().Error(), "gave up reconnecting to the event stream after 1 attempt(s)")
+	s.Contains(dm.Err().Error(), "stream closed")
+}
@@ -29,6 +29,9 @@ func (m *DeployModel) printHeadlessHeader() {
 	w := m.printer.Writer()
 	w.PrintlnEmpty()
 	w.Println("Starting deployment...")
+	if m.engineContext != "" {
+		w.Printf("Context: %s\n", m.engineContext)
+	}
 	w.Printf(fmtInstanceID, m.instanceID)
 	if m.instanceName != "" {
 		w.Printf(fmtInstanceName, m.instanceName)
@@ -38,6 +41,10 @@ func (m *DeployModel) printHeadlessHeader() {
 	w.PrintlnEmpty()
 }
 
+func (m *DeployModel) printHeadlessReconnectNotice(attempt shared.StreamReconnectAttempt) {
+	m.printer.Writer().Printf("⚠ %s\n", attempt.Notice())
+}
+
 func (m *DeployModel) printHeadlessResourceEvent(data *container.ResourceDeployUpdateMessage) {
 	statusIcon := shared.ResourceStatusHeadlessIcon(data.Status)
 	statusText := shared.ResourceStatusHeadlessText(data.Status)
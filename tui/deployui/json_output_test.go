@@ -84,6 +84,100 @@ func (s *DeployJSONOutputTestSuite) Test_outputJSON_includes_instance_info() {
 	s.Equal("test-changeset-123", output.ChangesetID)
 }
 
+func (s *DeployJSONOutputTestSuite) Test_outputJSON_includes_engine_context() {
+	jsonOutput := &bytes.Buffer{}
+
+	events := []*types.BlueprintInstanceEvent{
+		resourceDeployEvent("resource-1", core.ResourceStatusCreated),
+		deployFinishEvent(core.InstanceStatusDeployed),
+	}
+
+	model := NewDeployModel(DeployModelConfig{
+		DeployEngine: testutils.NewTestDeployEngineWithDeployment(
+			events,
+			"test-instance-id",
+			&state.InstanceState{InstanceID: "test-instance-id", Status: core.InstanceStatusDeployed},
+		),
+		Logger:         zap.NewNop(),
+		ChangesetID:    "test-changeset-context",
+		InstanceName:   "test-instance",
+		BlueprintFile:  "test.blueprint.yaml",
+		Styles:         s.styles,
+		IsHeadless:     true,
+		HeadlessWriter: jsonOutput,
+		JSONMode:       true,
+		EngineContext:  "prod",
+	})
+
+	testModel := teatest.NewTestModel(
+		s.T(),
+		model,
+		teatest.WithInitialTermSize(300, 100),
+	)
+
+	testModel.Send(StartDeployMsg{})
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+
+	var output jsonout.DeployOutput
+	err := json.Unmarshal(jsonOutput.Bytes(), &output)
+	s.Require().NoError(err)
+	s.Equal("prod", output.Context)
+}
+
+func (s *DeployJSONOutputTestSuite) Test_ndjson_stream_writes_events_followed_by_result() {
+	ndjsonOutput := &bytes.Buffer{}
+
+	instanceState := &state.InstanceState{
+		InstanceID: "test-instance-id",
+		Status:     core.InstanceStatusDeployed,
+	}
+
+	events := []*types.BlueprintInstanceEvent{
+		resourceDeployEvent("resource-1", core.ResourceStatusCreated),
+		deployFinishEvent(core.InstanceStatusDeployed),
+	}
+
+	model := NewDeployModel(DeployModelConfig{
+		DeployEngine:   testutils.NewTestDeployEngineWithDeployment(events, "test-instance-id", instanceState),
+		Logger:         zap.NewNop(),
+		ChangesetID:    "test-changeset-123",
+		InstanceName:   "test-instance",
+		BlueprintFile:  "test.blueprint.yaml",
+		Styles:         s.styles,
+		IsHeadless:     true,
+		HeadlessWriter: ndjsonOutput,
+		JSONMode:       true,
+		NDJSONStream:   jsonout.NewEventStream(ndjsonOutput),
+	})
+
+	testModel := teatest.NewTestModel(
+		s.T(),
+		model,
+		teatest.WithInitialTermSize(300, 100),
+	)
+
+	testModel.Send(StartDeployMsg{})
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+
+	lines := bytes.Split(bytes.TrimSpace(ndjsonOutput.Bytes()), []byte("\n"))
+	s.Require().Len(lines, 3)
+
+	var resourceEvent map[string]any
+	s.Require().NoError(json.Unmarshal(lines[0], &resourceEvent))
+	s.Equal("resource", resourceEvent["event"])
+	s.Equal("deploy", resourceEvent["operation"])
+	s.Equal("resource-1", resourceEvent["data"].(map[string]any)["resourceName"])
+
+	var finishEvent jsonout.StreamEvent
+	s.Require().NoError(json.Unmarshal(lines[1], &finishEvent))
+	s.Equal("finish", finishEvent.Event)
+
+	var output jsonout.DeployOutput
+	s.Require().NoError(json.Unmarshal(lines[2], &output))
+	s.True(output.Success)
+	s.Equal("test-instance-id", output.InstanceID)
+}
+
 func (s *DeployJSONOutputTestSuite) Test_outputJSON_includes_deployment_summary() {
 	jsonOutput := &bytes.Buffer{}
 
@@ -477,6 +477,8 @@ type DeployFooterRenderer struct {
 	SpinnerView         string // Current spinner frame for animated "Deploying" state
 	HasInstanceState    bool   // Whether instance state is available (enables exports view)
 	HasPreRollbackState bool   // Whether pre-rollback state is available (enables pre-rollback view)
+	// Reconnecting is set while the event stream is being resumed.
+	Reconnecting *shared.StreamReconnectAttempt
 }
 
 // Ensure DeployFooterRenderer implements splitpane.FooterRenderer.
@@ -531,6 +533,7 @@ func (r *DeployFooterRenderer) RenderFooter(model *splitpane.Model, s *styles.St
 			HasInstanceState: r.HasInstanceState,
 			StateHintKey:     "e",
 			StateHintLabel:   "exports",
+			Reconnecting:     r.Reconnecting,
 		}, s)
 	}
 
@@ -7,8 +7,10 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/newstack-cloud/bluelink/libs/blueprint/changes"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
 	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
 	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
 	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
 	sharedui "github.com/newstack-cloud/deploy-cli-sdk/ui"
 	"go.uber.org/zap"
@@ -338,11 +340,23 @@ type DeployAppConfig struct {
 	Headless               bool
 	HeadlessWriter         io.Writer
 	JSONMode               bool
-	Preflight              tea.Model
+	// NDJSONStream is an optional stream that staging and deployment events
+	// and the final result are written to as NDJSON in JSON mode.
+	NDJSONStream *jsonout.EventStream
+	// EngineContext is the name of the engine context the operation runs
+	// against, shown in the header and included in JSON output.
+	EngineContext string
+	// Variables are the blueprint and context variable values provided
+	// on the command line, included in JSON output.
+	Variables []jsonout.VariableValue
+	Preflight tea.Model
 	// OperationConfig carries provider/transformer/context-variable values
 	// (including the deploy target) sent to the engine during staging and
 	// deployment.
 	OperationConfig *types.BlueprintOperationConfig
+	// StreamReconnect determines how event streams are resumed
+	// when the connection to the deploy engine drops.
+	StreamReconnect shared.StreamReconnectPolicy
 }
 
 // NewDeployApp creates a new deploy application with the given configuration.
@@ -414,7 +428,11 @@ func NewDeployApp(cfg DeployAppConfig) (*MainModel, error) {
 		IsHeadless:      cfg.Headless,
 		HeadlessWriter:  cfg.HeadlessWriter,
 		JSONMode:        cfg.JSONMode,
+		NDJSONStream:    cfg.NDJSONStream,
+		EngineContext:   cfg.EngineContext,
+		Variables:       cfg.Variables,
 		OperationConfig: cfg.OperationConfig,
+		StreamReconnect: cfg.StreamReconnect,
 	})
 	staging := &stagingModel
 	// Pre-populate blueprint info if available
@@ -439,7 +457,11 @@ func NewDeployApp(cfg DeployAppConfig) (*MainModel, error) {
 		HeadlessWriter:   cfg.HeadlessWriter,
 		ChangesetChanges: nil, // will be set when staging completes
 		JSONMode:         cfg.JSONMode,
+		NDJSONStream:     cfg.NDJSONStream,
+		EngineContext:    cfg.EngineContext,
+		Variables:        cfg.Variables,
 		OperationConfig:  cfg.OperationConfig,
+		StreamReconnect:  cfg.StreamReconnect,
 	})
 
 	postPreflightState := sessionState
@@ -491,3 +513,15 @@ func (m *MainModel) ChangesetID() string {
 func (m *MainModel) InstanceName() string {
 	return m.instanceName
 }
+
+// DeploymentResult returns the final status of the instance and whether
+// the deployment ran to completion.
+// This is used when the deploy app is run as part of a larger flow,
+// such as deploying the instances in a deployment manifest.
+func (m *MainModel) DeploymentResult() (core.InstanceStatus, bool) {
+	deployModel, ok := m.deploy.(DeployModel)
+	if !ok || !deployModel.finished {
+		return core.InstanceStatusPreparing, false
+	}
+	return deployModel.finalStatus, true
+}
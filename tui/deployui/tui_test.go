@@ -874,6 +874,37 @@ func (s *DeployTUISuite) Test_headless_mode_outputs_header_info() {
 	s.Contains(output, "Changeset: test-changeset-header")
 }
 
+func (s *DeployTUISuite) Test_headless_mode_outputs_engine_context() {
+	headlessOutput := &bytes.Buffer{}
+
+	model := NewDeployModel(DeployModelConfig{
+		DeployEngine: testutils.NewTestDeployEngineWithDeployment(
+			testDeployEvents(deploySuccessCreate),
+			"test-instance-id",
+			testInstanceState(core.InstanceStatusDeployed),
+		),
+		Logger:         zap.NewNop(),
+		ChangesetID:    "test-changeset-context",
+		InstanceName:   "my-test-instance",
+		BlueprintFile:  "test.blueprint.yaml",
+		Styles:         s.styles,
+		IsHeadless:     true,
+		HeadlessWriter: headlessOutput,
+		EngineContext:  "prod",
+	})
+
+	testModel := teatest.NewTestModel(
+		s.T(),
+		model,
+		teatest.WithInitialTermSize(300, 100),
+	)
+
+	testModel.Send(StartDeployMsg{})
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+
+	s.Contains(headlessOutput.String(), "Context: prod")
+}
+
 func (s *DeployTUISuite) Test_headless_mode_update_rollback_complete() {
 	headlessOutput := &bytes.Buffer{}
 
@@ -11,6 +11,8 @@ func (m *DeployModel) outputJSON() {
 		Success:          true,
 		InstanceID:       m.instanceID,
 		InstanceName:     m.instanceName,
+		Context:          m.engineContext,
+		Variables:        m.variables,
 		ChangesetID:      m.changesetID,
 		Status:           m.finalStatus.String(),
 		InstanceState:    m.postDeployInstanceState,
@@ -18,7 +20,7 @@ func (m *DeployModel) outputJSON() {
 		Summary:          summary,
 	}
 
-	jsonout.WriteJSON(m.headlessWriter, output)
+	m.writeJSON(output)
 }
 
 func (m *DeployModel) buildDeploySummary() jsonout.DeploySummary {
@@ -74,15 +76,27 @@ func (m *DeployModel) outputJSONDrift() {
 		DriftDetected:  true,
 		InstanceID:     m.instanceID,
 		InstanceName:   m.instanceName,
+		Context:        m.engineContext,
 		ChangesetID:    m.driftBlockedChangesetID,
 		Message:        m.driftMessage,
 		Reconciliation: m.driftResult,
 	}
 
-	jsonout.WriteJSON(m.headlessWriter, output)
+	m.writeJSON(output)
 }
 
 func (m *DeployModel) outputJSONError(err error) {
 	output := jsonout.NewErrorOutput(err)
+	output.Context = m.engineContext
+	m.writeJSON(output)
+}
+
+// writeJSON writes the final result of the deployment as a pretty-printed
+// JSON object, or as the last line of the NDJSON stream when streaming events.
+func (m *DeployModel) writeJSON(output any) {
+	if m.ndjsonStream != nil {
+		m.ndjsonStream.WriteLine(output)
+		return
+	}
 	jsonout.WriteJSON(m.headlessWriter, output)
 }
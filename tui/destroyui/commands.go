@@ -19,7 +19,19 @@ import (
 type DestroyEventMsg types.BlueprintInstanceEvent
 
 // DestroyStreamClosedMsg is sent when the destroy event stream is closed.
-type DestroyStreamClosedMsg struct{}
+type DestroyStreamClosedMsg struct {
+	// stream is the event channel that was closed, used to ignore
+	// channels that have been replaced by resuming the stream.
+	stream chan types.BlueprintInstanceEvent
+}
+
+// DestroyStreamReconnectMsg is sent when it is time to resume the destroy
+// event stream after the connection to the deploy engine was lost.
+type DestroyStreamReconnectMsg struct{}
+
+// DestroyStreamResumedMsg is sent when the destroy event stream has been
+// resumed from the last received event.
+type DestroyStreamResumedMsg struct{}
 
 // DestroyErrorMsg is a message containing an error from the destroy process.
 type DestroyErrorMsg struct {
@@ -28,7 +40,8 @@ type DestroyErrorMsg struct {
 
 // DestroyStartedMsg is a message indicating that destroy has started.
 type DestroyStartedMsg struct {
-	InstanceID string
+	InstanceID  string
+	LastEventID string
 }
 
 // StartDestroyMsg is a message to initiate destroy.
@@ -79,7 +92,10 @@ func startDestroyCmd(model DestroyModel) tea.Cmd {
 			return DestroyErrorMsg{Err: err}
 		}
 
-		return DestroyStartedMsg{InstanceID: response.Data.InstanceID}
+		return DestroyStartedMsg{
+			InstanceID:  response.Data.InstanceID,
+			LastEventID: response.LastEventID,
+		}
 	}
 }
 
@@ -128,12 +144,38 @@ func waitForNextDestroyEventCmd(model DestroyModel) tea.Cmd {
 	return func() tea.Msg {
 		event, ok := <-model.eventStream
 		if !ok {
-			return DestroyStreamClosedMsg{}
+			return DestroyStreamClosedMsg{stream: model.eventStream}
 		}
 		return DestroyEventMsg(event)
 	}
 }
 
+// reconnectDestroyStreamCmd waits before resuming the destroy event stream.
+func reconnectDestroyStreamCmd(wait time.Duration) tea.Cmd {
+	return tea.Tick(wait, func(time.Time) tea.Msg {
+		return DestroyStreamReconnectMsg{}
+	})
+}
+
+// resumeDestroyStreamCmd resumes the destroy event stream from the last
+// received event.
+func resumeDestroyStreamCmd(model DestroyModel) tea.Cmd {
+	return func() tea.Msg {
+		err := model.engine.StreamBlueprintInstanceEvents(
+			model.reqCtx(),
+			model.streamResumer.StreamID(),
+			model.streamResumer.LastEventID(),
+			model.eventStream,
+			model.errStream,
+		)
+		if err != nil {
+			return DestroyErrorMsg{Err: err}
+		}
+
+		return DestroyStreamResumedMsg{}
+	}
+}
+
 func checkForDestroyErrCmd(model DestroyModel) tea.Cmd {
 	return func() tea.Msg {
 		var err error
@@ -208,7 +250,10 @@ func continueDestroyCmd(model DestroyModel) tea.Cmd {
 			return DestroyErrorMsg{Err: err}
 		}
 
-		return DestroyStartedMsg{InstanceID: response.Data.InstanceID}
+		return DestroyStartedMsg{
+			InstanceID:  response.Data.InstanceID,
+			LastEventID: response.LastEventID,
+		}
 	}
 }
 
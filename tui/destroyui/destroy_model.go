@@ -3,7 +3,6 @@ package destroyui
 import (
 	"context"
 	"errors"
-	"fmt"
 	"io"
 
 	"github.com/charmbracelet/bubbles/spinner"
@@ -17,6 +16,7 @@ import (
 	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
 	"github.com/newstack-cloud/deploy-cli-sdk/engine"
 	"github.com/newstack-cloud/deploy-cli-sdk/headless"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
 	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
 	"github.com/newstack-cloud/deploy-cli-sdk/tui/driftui"
 	"github.com/newstack-cloud/deploy-cli-sdk/tui/shared"
@@ -201,6 +201,9 @@ type DestroyModel struct {
 	engine      engine.DeployEngine
 	eventStream chan types.BlueprintInstanceEvent
 	errStream   chan error
+	// streamResumer tracks the last received event so the stream
+	// can be resumed when the connection to the engine drops.
+	streamResumer shared.StreamResumer
 
 	force bool
 
@@ -214,6 +217,13 @@ type DestroyModel struct {
 	headlessWriter io.Writer
 	printer        *headless.Printer
 	jsonMode       bool
+	// ndjsonStream is set when streaming events as NDJSON,
+	// each destroy event is written as it is received.
+	ndjsonStream *jsonout.EventStream
+	// engineContext is the name of the selected engine context, if any.
+	engineContext string
+	// variables are the variable values provided on the command line.
+	variables []jsonout.VariableValue
 
 	styles  *stylespkg.Styles
 	logger  *zap.Logger
@@ -243,7 +253,11 @@ func (m DestroyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case DeployChangesetErrorMsg:
 		return m.handleDeployChangesetError()
 	case DestroyStreamClosedMsg:
-		return m.handleDestroyStreamClosed()
+		return m.handleDestroyStreamClosed(msg)
+	case DestroyStreamReconnectMsg:
+		return m.handleDestroyStreamReconnect()
+	case DestroyStreamResumedMsg:
+		return m.handleDestroyStreamResumed()
 	case PreDestroyInstanceStateFetchedMsg:
 		return m.handlePreDestroyInstanceStateFetched(msg)
 	case ChangesetFetchedMsg:
@@ -389,7 +403,9 @@ func (m DestroyModel) startDestroyAfterFetches() (tea.Model, tea.Cmd) {
 func (m DestroyModel) handleDestroyStarted(msg DestroyStartedMsg) (tea.Model, tea.Cmd) {
 	m.instanceID = msg.InstanceID
 	m.streaming = true
+	m.streamResumer.Start(msg.InstanceID, msg.LastEventID)
 	m.footerRenderer.InstanceID = msg.InstanceID
+	m.footerRenderer.Reconnecting = nil
 
 	if m.headlessMode && !m.jsonMode {
 		m.printHeadlessHeader()
@@ -401,6 +417,8 @@ func (m DestroyModel) handleDestroyStarted(msg DestroyStartedMsg) (tea.Model, te
 
 func (m DestroyModel) handleDestroyEvent(msg DestroyEventMsg) (tea.Model, tea.Cmd) {
 	event := types.BlueprintInstanceEvent(msg)
+	m.streamResumer.RecordEvent(event.ID)
+	m.ndjsonStream.WriteBlueprintInstanceEvent(jsonout.OperationDestroy, &event)
 	m.processEvent(&event)
 	m.splitPane.UpdateItems(ToSplitPaneItems(m.items))
 	// Explicitly refresh viewports to ensure details view is updated
@@ -422,6 +440,7 @@ func (m DestroyModel) handleDestroyEvent(msg DestroyEventMsg) (tea.Model, tea.Cm
 	}
 
 	m.finished = true
+	m.streamResumer.Stop()
 	m.finalStatus = finishData.Status
 	m.failureReasons = finishData.FailureReasons
 	m.footerRenderer.FinalStatus = finishData.Status
@@ -451,6 +470,13 @@ func (m DestroyModel) handleDestroyError(msg DestroyErrorMsg) (tea.Model, tea.Cm
 		return m, nil
 	}
 
+	if m.streamResumer.CanResume() && shared.IsStreamConnectionError(msg.Err) {
+		if cmd, resuming := m.resumeStream(); resuming {
+			return m, cmd
+		}
+		msg.Err = m.streamResumer.GaveUpError(msg.Err)
+	}
+
 	m.err = msg.Err
 	if m.headlessMode {
 		if m.jsonMode {
@@ -476,11 +502,24 @@ func (m DestroyModel) handleDeployChangesetError() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m DestroyModel) handleDestroyStreamClosed() (tea.Model, tea.Cmd) {
+func (m DestroyModel) handleDestroyStreamClosed(msg DestroyStreamClosedMsg) (tea.Model, tea.Cmd) {
+	if msg.stream != nil && msg.stream != m.eventStream {
+		// The closed stream was replaced when the stream was resumed.
+		return m, nil
+	}
+
 	if !m.finished {
+		err := errors.New("destroy event stream closed unexpectedly (connection timeout or dropped)")
+		if m.streamResumer.CanResume() {
+			if cmd, resuming := m.resumeStream(); resuming {
+				return m, cmd
+			}
+			err = m.streamResumer.GaveUpError(err)
+		}
+
 		m.finished = true
 		m.streaming = false
-		m.err = fmt.Errorf("destroy event stream closed unexpectedly (connection timeout or dropped)")
+		m.err = err
 		if m.headlessMode {
 			if m.jsonMode {
 				m.outputJSONError(m.err)
@@ -493,6 +532,48 @@ func (m DestroyModel) handleDestroyStreamClosed() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// resumeStream schedules an attempt to resume the destroy event stream after
+// the connection to the engine was lost, false is returned when the
+// reconnection budget has been used up.
+func (m *DestroyModel) resumeStream() (tea.Cmd, bool) {
+	if m.streamResumer.Reconnecting() {
+		// An attempt to resume the lost stream has already been scheduled.
+		return nil, true
+	}
+
+	attempt, ok := m.streamResumer.NextAttempt()
+	if !ok {
+		return nil, false
+	}
+
+	m.footerRenderer.Reconnecting = &attempt
+	if m.headlessMode && !m.jsonMode {
+		m.printHeadlessReconnectNotice(attempt)
+	}
+	return reconnectDestroyStreamCmd(attempt.Wait), true
+}
+
+func (m DestroyModel) handleDestroyStreamReconnect() (tea.Model, tea.Cmd) {
+	if m.finished || !m.streamResumer.CanResume() {
+		return m, nil
+	}
+
+	// Events and errors are received on new channels so that
+	// the lost stream does not interfere with the resumed stream.
+	m.eventStream = make(chan types.BlueprintInstanceEvent)
+	m.errStream = make(chan error)
+	return m, resumeDestroyStreamCmd(m)
+}
+
+func (m DestroyModel) handleDestroyStreamResumed() (tea.Model, tea.Cmd) {
+	m.streamResumer.Resumed()
+	m.footerRenderer.Reconnecting = nil
+	return m, tea.Batch(
+		waitForNextDestroyEventCmd(m),
+		checkForDestroyErrCmd(m),
+	)
+}
+
 func (m DestroyModel) handlePostDestroyInstanceStateFetched(msg PostDestroyInstanceStateFetchedMsg) (tea.Model, tea.Cmd) {
 	m.postDestroyInstanceState = msg.InstanceState
 	m.detailsRenderer.PostDestroyInstanceState = msg.InstanceState
@@ -729,10 +810,24 @@ type DestroyModelConfig struct {
 	HeadlessWriter   io.Writer
 	ChangesetChanges *changes.BlueprintChanges
 	JSONMode         bool
+	// NDJSONStream is an optional stream that destroy events are written to
+	// as NDJSON, when set the final result is written to the stream
+	// instead of as a pretty-printed JSON object.
+	// This is only used in JSON mode.
+	NDJSONStream *jsonout.EventStream
+	// EngineContext is the name of the engine context the operation runs
+	// against, shown in the header and included in JSON output.
+	EngineContext string
+	// Variables are the blueprint and context variable values provided
+	// on the command line, included in JSON output.
+	Variables []jsonout.VariableValue
 	// OperationConfig carries provider/transformer/context-variable values
 	// (including the deploy target) sent to the engine when destroying an
 	// instance, so provider plugins run against the correct deploy target.
 	OperationConfig *types.BlueprintOperationConfig
+	// StreamReconnect determines how the destroy event stream is resumed
+	// when the connection to the deploy engine drops.
+	StreamReconnect shared.StreamReconnectPolicy
 }
 
 // Returns the model's bound context, defaulting to context.Background()
@@ -748,9 +843,11 @@ func (m DestroyModel) reqCtx() context.Context {
 func NewDestroyModel(cfg DestroyModelConfig) DestroyModel {
 	detailsRenderer, sectionGrouper, footerRenderer := createDestroyRenderers(cfg.InstanceID, cfg.InstanceName, cfg.ChangesetID)
 	splitPaneConfig := createDestroySplitPaneConfig(cfg.Styles, detailsRenderer, sectionGrouper, footerRenderer)
+	splitPaneConfig.Subtitle = shared.EngineContextSubtitle(cfg.EngineContext)
 
 	driftDetailsRenderer, driftSectionGrouper, driftFooterRenderer := createDestroyDriftRenderers()
 	driftSplitPaneConfig := createDestroyDriftSplitPaneConfig(cfg.Styles, driftDetailsRenderer, driftSectionGrouper, driftFooterRenderer)
+	driftSplitPaneConfig.Subtitle = shared.EngineContextSubtitle(cfg.EngineContext)
 
 	printer := createDestroyHeadlessPrinter(cfg.IsHeadless, cfg.HeadlessWriter)
 
@@ -782,9 +879,13 @@ func NewDestroyModel(cfg DestroyModelConfig) DestroyModel {
 		headlessWriter:          cfg.HeadlessWriter,
 		printer:                 printer,
 		jsonMode:                cfg.JSONMode,
+		ndjsonStream:            cfg.NDJSONStream,
+		engineContext:           cfg.EngineContext,
+		variables:               cfg.Variables,
 		spinner:                 createDestroySpinner(cfg.Styles),
 		eventStream:             make(chan types.BlueprintInstanceEvent),
 		errStream:               make(chan error),
+		streamResumer:           shared.NewStreamResumer(cfg.StreamReconnect),
 		resourcesByName:         resourcesByName,
 		childrenByName:          childrenByName,
 		linksByName:             linksByName,
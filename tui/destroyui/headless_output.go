@@ -25,6 +25,9 @@ func (m *DestroyModel) printHeadlessHeader() {
 	w := m.printer.Writer()
 	w.PrintlnEmpty()
 	w.Println("Starting destroy...")
+	if m.engineContext != "" {
+		w.Printf("Context: %s\n", m.engineContext)
+	}
 	w.Printf("Instance ID: %s\n", m.instanceID)
 	if m.instanceName != "" {
 		w.Printf("Instance Name: %s\n", m.instanceName)
@@ -34,6 +37,10 @@ func (m *DestroyModel) printHeadlessHeader() {
 	w.PrintlnEmpty()
 }
 
+func (m *DestroyModel) printHeadlessReconnectNotice(attempt shared.StreamReconnectAttempt) {
+	m.printer.Writer().Printf("⚠ %s\n", attempt.Notice())
+}
+
 func (m *DestroyModel) printHeadlessResourceEvent(data *container.ResourceDeployUpdateMessage) {
 	statusIcon := shared.ResourceStatusHeadlessIcon(data.Status)
 	statusText := shared.ResourceStatusHeadlessText(data.Status)
@@ -11,6 +11,8 @@ func (m *DestroyModel) outputJSON() {
 		Success:         true,
 		InstanceID:      m.instanceID,
 		InstanceName:    m.instanceName,
+		Context:         m.engineContext,
+		Variables:       m.variables,
 		ChangesetID:     m.changesetID,
 		Status:          m.finalStatus.String(),
 		InstanceState:   m.postDestroyInstanceState,
@@ -18,7 +20,7 @@ func (m *DestroyModel) outputJSON() {
 		Summary:         summary,
 	}
 
-	jsonout.WriteJSON(m.headlessWriter, output)
+	m.writeJSON(output)
 }
 
 func (m *DestroyModel) buildDestroySummary() jsonout.DestroySummary {
@@ -82,14 +84,26 @@ func (m *DestroyModel) outputJSONDrift() {
 		DriftDetected:  true,
 		InstanceID:     m.instanceID,
 		InstanceName:   m.instanceName,
+		Context:        m.engineContext,
 		Message:        m.driftMessage,
 		Reconciliation: m.driftResult,
 	}
 
-	jsonout.WriteJSON(m.headlessWriter, output)
+	m.writeJSON(output)
 }
 
 func (m *DestroyModel) outputJSONError(err error) {
 	output := jsonout.NewErrorOutput(err)
+	output.Context = m.engineContext
+	m.writeJSON(output)
+}
+
+// writeJSON writes the final result of the destroy operation as a pretty-printed
+// JSON object, or as the last line of the NDJSON stream when streaming events.
+func (m *DestroyModel) writeJSON(output any) {
+	if m.ndjsonStream != nil {
+		m.ndjsonStream.WriteLine(output)
+		return
+	}
 	jsonout.WriteJSON(m.headlessWriter, output)
 }
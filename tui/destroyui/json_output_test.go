@@ -89,6 +89,58 @@ func (s *JSONOutputSuite) Test_json_output_success() {
 	s.Equal(0, output.Summary.Failed)
 }
 
+func (s *JSONOutputSuite) Test_ndjson_stream_writes_events_followed_by_result() {
+	ndjsonOutput := &bytes.Buffer{}
+
+	events := []*types.BlueprintInstanceEvent{
+		jsonResourceEvent("resource-1", core.ResourceStatusDestroyed, core.PreciseResourceStatusDestroyed),
+		jsonFinishEvent(core.InstanceStatusDestroyed),
+	}
+
+	model := NewDestroyModel(DestroyModelConfig{
+		DestroyEngine: testutils.NewTestDeployEngineWithDeployment(
+			events,
+			"test-instance-id",
+			&state.InstanceState{InstanceID: "test-instance-id", Status: core.InstanceStatusDestroyed},
+		),
+		Logger:         zap.NewNop(),
+		ChangesetID:    "test-changeset-123",
+		InstanceID:     "test-instance-id",
+		InstanceName:   "test-instance",
+		Styles:         s.styles,
+		IsHeadless:     true,
+		HeadlessWriter: ndjsonOutput,
+		JSONMode:       true,
+		NDJSONStream:   jsonout.NewEventStream(ndjsonOutput),
+	})
+
+	testModel := teatest.NewTestModel(
+		s.T(),
+		model,
+		teatest.WithInitialTermSize(300, 100),
+	)
+
+	testModel.Send(StartDestroyMsg{})
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+
+	lines := bytes.Split(bytes.TrimSpace(ndjsonOutput.Bytes()), []byte("\n"))
+	s.Require().Len(lines, 3)
+
+	var resourceEvent jsonout.StreamEvent
+	s.Require().NoError(json.Unmarshal(lines[0], &resourceEvent))
+	s.Equal("resource", resourceEvent.Event)
+	s.Equal("destroy", resourceEvent.Operation)
+
+	var finishEvent jsonout.StreamEvent
+	s.Require().NoError(json.Unmarshal(lines[1], &finishEvent))
+	s.Equal("finish", finishEvent.Event)
+
+	var output jsonout.DestroyOutput
+	s.Require().NoError(json.Unmarshal(lines[2], &output))
+	s.True(output.Success)
+	s.Equal("DESTROYED", output.Status)
+}
+
 func (s *JSONOutputSuite) Test_json_output_includes_retained_elements_and_count() {
 	jsonOutput := &bytes.Buffer{}
 
@@ -275,6 +275,8 @@ type DestroyFooterRenderer struct {
 	ElementFailures     []ElementFailure
 	InterruptedElements []InterruptedElement
 	RetainedElements    []RetainedElement
+	// Reconnecting is set while the event stream is being resumed.
+	Reconnecting *shared.StreamReconnectAttempt
 }
 
 var _ splitpane.FooterRenderer = (*DestroyFooterRenderer)(nil)
@@ -320,6 +322,7 @@ func (r *DestroyFooterRenderer) renderStreamingFooter(s *styles.Styles) string {
 		HasInstanceState: r.HasInstanceState,
 		StateHintKey:     "s",
 		StateHintLabel:   "pre-destroy state",
+		Reconnecting:     r.Reconnecting,
 	}, s)
 
 	if IsRollingBackStatus(r.CurrentStatus) {
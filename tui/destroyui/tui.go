@@ -10,6 +10,7 @@ import (
 	"github.com/newstack-cloud/bluelink/libs/blueprint/changes"
 	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
 	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
 	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
 	sharedui "github.com/newstack-cloud/deploy-cli-sdk/ui"
 	"go.uber.org/zap"
@@ -600,11 +601,23 @@ type DestroyAppConfig struct {
 	Headless               bool
 	HeadlessWriter         io.Writer
 	JSONMode               bool
-	Preflight              tea.Model
+	// NDJSONStream is an optional stream that staging and destroy events
+	// and the final result are written to as NDJSON in JSON mode.
+	NDJSONStream *jsonout.EventStream
+	// EngineContext is the name of the engine context the operation runs
+	// against, shown in the header and included in JSON output.
+	EngineContext string
+	// Variables are the blueprint and context variable values provided
+	// on the command line, included in JSON output.
+	Variables []jsonout.VariableValue
+	Preflight tea.Model
 	// OperationConfig carries provider/transformer/context-variable values
 	// (including the deploy target) sent to the engine when staging destroy
 	// changes and destroying an instance.
 	OperationConfig *types.BlueprintOperationConfig
+	// StreamReconnect determines how event streams are resumed
+	// when the connection to the deploy engine drops.
+	StreamReconnect shared.StreamReconnectPolicy
 }
 
 // NewDestroyApp creates a new destroy application with the given configuration.
@@ -654,7 +667,11 @@ func NewDestroyApp(cfg DestroyAppConfig) (*MainModel, error) {
 		IsHeadless:      cfg.Headless,
 		HeadlessWriter:  cfg.HeadlessWriter,
 		JSONMode:        cfg.JSONMode,
+		NDJSONStream:    cfg.NDJSONStream,
+		EngineContext:   cfg.EngineContext,
+		Variables:       cfg.Variables,
 		OperationConfig: cfg.OperationConfig,
+		StreamReconnect: cfg.StreamReconnect,
 	})
 	staging := &stagingModel
 	staging.SetBlueprintFile(cfg.BlueprintFile)
@@ -675,7 +692,11 @@ func NewDestroyApp(cfg DestroyAppConfig) (*MainModel, error) {
 		HeadlessWriter:   cfg.HeadlessWriter,
 		ChangesetChanges: nil,
 		JSONMode:         cfg.JSONMode,
+		NDJSONStream:     cfg.NDJSONStream,
+		EngineContext:    cfg.EngineContext,
+		Variables:        cfg.Variables,
 		OperationConfig:  cfg.OperationConfig,
+		StreamReconnect:  cfg.StreamReconnect,
 	})
 
 	postPreflightState := sessionState
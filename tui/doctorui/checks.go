@@ -0,0 +1,437 @@
+package doctorui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	deployengine "github.com/newstack-cloud/bluelink/libs/deploy-engine-client"
+	deerrors "github.com/newstack-cloud/bluelink/libs/deploy-engine-client/errors"
+	"github.com/newstack-cloud/deploy-cli-sdk/config"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/newstack-cloud/deploy-cli-sdk/stateio"
+)
+
+// CheckStatus is the outcome of a single doctor check.
+type CheckStatus string
+
+const (
+	// CheckStatusPass indicates the check found no problems.
+	CheckStatusPass CheckStatus = "pass"
+	// CheckStatusWarn indicates the check found a problem that does not
+	// prevent the CLI from working but should be looked at.
+	CheckStatusWarn CheckStatus = "warn"
+	// CheckStatusFail indicates the check found a problem that will prevent
+	// the CLI from working.
+	CheckStatusFail CheckStatus = "fail"
+	// CheckStatusSkip indicates the check was not carried out, either because
+	// it does not apply to the current configuration or because a check it
+	// depends on failed.
+	CheckStatusSkip CheckStatus = "skip"
+)
+
+const (
+	checkIDConfigFile       = "config-file"
+	checkIDEngineAuth       = "engine-auth"
+	checkIDEngineConnection = "engine-connection"
+	checkIDEngineAPI        = "engine-api"
+	checkIDEngineConfig     = "engine-config"
+	checkIDStateStorage     = "state-storage"
+	checkIDPreflight        = "preflight"
+)
+
+// defaultCheckTimeout is the time allowed for checks that make network calls
+// to the deploy engine or the state storage backend.
+const defaultCheckTimeout = 10 * time.Second
+
+// CheckResult holds the outcome of a single doctor check.
+type CheckResult struct {
+	ID      string
+	Name    string
+	Status  CheckStatus
+	Message string
+	// Hint is an optional suggestion for resolving a problem found by the check.
+	Hint string
+}
+
+// check is a single diagnostic step carried out by the doctor.
+type check struct {
+	id   string
+	name string
+	run  func(ctx context.Context, env *checkEnv) CheckResult
+}
+
+// checkEnv holds the inputs for the doctor checks along with
+// state that is shared between checks.
+type checkEnv struct {
+	confProvider              *config.Provider
+	configFile                string
+	configFileIsDefault       bool
+	engineConfigFile          string
+	engineConfigFileIsDefault bool
+	unixSocket                string
+	createEngine              func() (engine.DeployEngine, error)
+	timeout                   time.Duration
+
+	results      map[string]CheckResult
+	engineConfig *stateio.EngineConfig
+}
+
+func (e *checkEnv) failed(checkID string) bool {
+	result, ok := e.results[checkID]
+	return ok && result.Status == CheckStatusFail
+}
+
+func newChecks() []check {
+	return []check{
+		{id: checkIDConfigFile, name: "CLI config file", run: checkConfigFile},
+		{id: checkIDEngineAuth, name: "Engine auth config", run: checkEngineAuth},
+		{id: checkIDEngineConnection, name: "Engine connection", run: checkEngineConnection},
+		{id: checkIDEngineAPI, name: "Authenticated engine call", run: checkEngineAPI},
+		{id: checkIDEngineConfig, name: "Deploy engine config", run: checkEngineConfig},
+		{id: checkIDStateStorage, name: "State storage", run: checkStateStorage},
+	}
+}
+
+func checkConfigFile(_ context.Context, env *checkEnv) CheckResult {
+	if env.configFile == "" {
+		return CheckResult{
+			Status:  CheckStatusSkip,
+			Message: "no config file is configured, using flags, environment variables and defaults",
+		}
+	}
+
+	if _, err := os.Stat(env.configFile); err != nil {
+		if errors.Is(err, os.ErrNotExist) && env.configFileIsDefault {
+			return CheckResult{
+				Status: CheckStatusSkip,
+				Message: fmt.Sprintf(
+					"no config file found at %s, using flags, environment variables and defaults",
+					env.configFile,
+				),
+			}
+		}
+		return CheckResult{
+			Status:  CheckStatusFail,
+			Message: fmt.Sprintf("config file %s could not be read: %s", env.configFile, err),
+			Hint:    "make sure the config file path is correct and the file is readable",
+		}
+	}
+
+	// The config file is parsed with a separate provider so that running
+	// the check does not change the configuration used by the other checks.
+	if err := config.NewProvider().LoadConfigFile(env.configFile); err != nil {
+		result := CheckResult{
+			Status:  CheckStatusFail,
+			Message: fmt.Sprintf("config file %s could not be parsed: %s", env.configFile, err),
+			Hint:    "config files must be valid YAML, JSON or TOML documents that map config keys to values, lists or nested tables",
+		}
+		if errors.Is(err, config.ErrUnsupportedConfigFileFormat) {
+			result.Hint = "config files must have a .yaml, .yml, .json or .toml extension"
+		}
+		return result
+	}
+
+	return CheckResult{
+		Status:  CheckStatusPass,
+		Message: fmt.Sprintf("parsed %s", env.configFile),
+	}
+}
+
+func checkEngineAuth(_ context.Context, env *checkEnv) CheckResult {
+	authConfigFile := config.EngineAuthConfigFile(env.confProvider)
+	if authConfigFile == "" {
+		return CheckResult{
+			Status:  CheckStatusFail,
+			Message: "no engine auth config file is configured",
+			Hint:    "set the engineAuthConfigFile config value to the path of a JSON engine auth config file",
+		}
+	}
+
+	info, err := os.Stat(authConfigFile)
+	if err != nil {
+		return CheckResult{
+			Status:  CheckStatusFail,
+			Message: fmt.Sprintf("engine auth config file %s could not be read: %s", authConfigFile, err),
+			Hint:    "make sure the engineAuthConfigFile config value points to an existing file",
+		}
+	}
+
+	authConfig, err := config.LoadEngineAuthConfig(env.confProvider)
+	if err != nil {
+		return CheckResult{
+			Status:  CheckStatusFail,
+			Message: fmt.Sprintf("engine auth config file %s could not be parsed: %s", authConfigFile, err),
+			Hint:    "the engine auth config file must be a valid JSON document",
+		}
+	}
+
+	if err := authConfig.Validate(); err != nil {
+		return CheckResult{
+			Status:  CheckStatusFail,
+			Message: fmt.Sprintf("engine auth config file %s is invalid: %s", authConfigFile, err),
+		}
+	}
+
+	if _, err := authConfig.ResolveSecrets(); err != nil {
+		return CheckResult{
+			Status:  CheckStatusFail,
+			Message: fmt.Sprintf("engine auth config file %s is invalid: %s", authConfigFile, err),
+			Hint:    "make sure the environment variables, files and credential helpers referenced by secret fields are available",
+		}
+	}
+
+	// Auth config files that only hold secret references can be shared.
+	if authConfig.HasPlaintextSecrets() && config.IsAccessibleByOthers(info) {
+		return CheckResult{
+			Status: CheckStatusWarn,
+			Message: fmt.Sprintf(
+				"engine auth config file %s holds credentials but can be accessed by other users (mode %s)",
+				authConfigFile,
+				info.Mode().Perm(),
+			),
+			Hint: fmt.Sprintf("restrict access to the file with: chmod 600 %s", authConfigFile),
+		}
+	}
+
+	return CheckResult{
+		Status:  CheckStatusPass,
+		Message: fmt.Sprintf("%s uses the %s auth method", authConfigFile, authConfig.Method),
+	}
+}
+
+func checkEngineConnection(_ context.Context, env *checkEnv) CheckResult {
+	connectProtocol, _ := env.confProvider.GetString("connectProtocol")
+	switch connectProtocol {
+	case "unix":
+		return checkUnixSocket(env.unixSocket)
+	case "tcp":
+		return checkEngineEndpoint(env.confProvider)
+	default:
+		return CheckResult{
+			Status:  CheckStatusFail,
+			Message: fmt.Sprintf("invalid connect protocol: %q", connectProtocol),
+			Hint:    "set the connectProtocol config value to either 'tcp' or 'unix'",
+		}
+	}
+}
+
+func checkUnixSocket(socketPath string) CheckResult {
+	info, err := os.Stat(socketPath)
+	if err != nil {
+		return CheckResult{
+			Status:  CheckStatusFail,
+			Message: fmt.Sprintf("unix socket %s could not be found: %s", socketPath, err),
+			Hint:    "make sure the deploy engine is running and listening on the unix socket",
+		}
+	}
+
+	if info.Mode()&os.ModeSocket == 0 {
+		return CheckResult{
+			Status:  CheckStatusFail,
+			Message: fmt.Sprintf("%s exists but is not a unix socket", socketPath),
+			Hint:    "remove the file and restart the deploy engine",
+		}
+	}
+
+	return CheckResult{
+		Status:  CheckStatusPass,
+		Message: fmt.Sprintf("connecting over unix socket %s", socketPath),
+	}
+}
+
+func checkEngineEndpoint(confProvider *config.Provider) CheckResult {
+	endpoint, _ := confProvider.GetString("engineEndpoint")
+	if endpoint == "" {
+		return CheckResult{
+			Status:  CheckStatusFail,
+			Message: "no engine endpoint is configured",
+			Hint: fmt.Sprintf(
+				"set the engineEndpoint config value, a locally running engine listens on %s",
+				deployengine.DefaultEndpoint,
+			),
+		}
+	}
+
+	parsed, err := url.Parse(endpoint)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return CheckResult{
+			Status:  CheckStatusFail,
+			Message: fmt.Sprintf("engine endpoint %q is not a valid http or https URL", endpoint),
+		}
+	}
+
+	return CheckResult{
+		Status:  CheckStatusPass,
+		Message: fmt.Sprintf("connecting over tcp to %s", endpoint),
+	}
+}
+
+func checkEngineAPI(ctx context.Context, env *checkEnv) CheckResult {
+	if env.failed(checkIDEngineAuth) || env.failed(checkIDEngineConnection) {
+		return CheckResult{
+			Status:  CheckStatusSkip,
+			Message: "skipped as the engine auth config or connection settings are invalid",
+		}
+	}
+
+	deployEngine, err := env.createEngine()
+	if err != nil {
+		return CheckResult{
+			Status:  CheckStatusFail,
+			Message: fmt.Sprintf("failed to create the deploy engine client: %s", err),
+		}
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, env.timeout)
+	defer cancel()
+
+	// Listing a single instance is a cheap call that requires
+	// the request to be authenticated.
+	_, err = deployEngine.ListBlueprintInstances(callCtx, state.ListInstancesParams{Limit: 1})
+	if err != nil {
+		return engineAPIErrorResult(err)
+	}
+
+	return CheckResult{
+		Status:  CheckStatusPass,
+		Message: "the deploy engine accepted an authenticated request",
+	}
+}
+
+func engineAPIErrorResult(err error) CheckResult {
+	authPrepErr := &deerrors.AuthPrepError{}
+	authInitErr := &deerrors.AuthInitError{}
+	if errors.As(err, &authPrepErr) || errors.As(err, &authInitErr) {
+		return CheckResult{
+			Status:  CheckStatusFail,
+			Message: fmt.Sprintf("failed to prepare authentication for the deploy engine: %s", err),
+			Hint:    "check the credentials and OAuth2 provider settings in the engine auth config file",
+		}
+	}
+
+	clientErr := &deerrors.ClientError{}
+	if errors.As(err, &clientErr) &&
+		(clientErr.StatusCode == http.StatusUnauthorized || clientErr.StatusCode == http.StatusForbidden) {
+		return CheckResult{
+			Status:  CheckStatusFail,
+			Message: fmt.Sprintf("the deploy engine rejected the credentials (%d): %s", clientErr.StatusCode, clientErr.Message),
+			Hint:    "make sure the credentials in the engine auth config file match those configured for the deploy engine",
+		}
+	}
+
+	requestErr := &deerrors.RequestError{}
+	if errors.As(err, &requestErr) || errors.Is(err, context.DeadlineExceeded) {
+		return CheckResult{
+			Status:  CheckStatusFail,
+			Message: fmt.Sprintf("the deploy engine could not be reached: %s", err),
+			Hint:    "make sure the deploy engine is running and reachable from this machine",
+		}
+	}
+
+	return CheckResult{
+		Status:  CheckStatusFail,
+		Message: fmt.Sprintf("the deploy engine returned an error: %s", err),
+	}
+}
+
+func checkEngineConfig(_ context.Context, env *checkEnv) CheckResult {
+	path := env.engineConfigFile
+	if path == "" {
+		path = stateio.GetDefaultEngineConfigPath()
+	}
+
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) && env.engineConfigFileIsDefault {
+		return CheckResult{
+			Status: CheckStatusSkip,
+			Message: fmt.Sprintf(
+				"no deploy engine config file found at %s, this is only needed for state import and export",
+				path,
+			),
+		}
+	}
+
+	engineConfig, err := stateio.LoadEngineConfig(path)
+	if err != nil {
+		return CheckResult{
+			Status:  CheckStatusFail,
+			Message: err.Error(),
+			Hint:    "the deploy engine config file must be a valid JSON document",
+		}
+	}
+
+	storageEngine := engineConfig.State.StorageEngine
+	if storageEngine != stateio.StorageEngineMemfile && storageEngine != stateio.StorageEnginePostgres {
+		return CheckResult{
+			Status:  CheckStatusFail,
+			Message: fmt.Sprintf("unsupported state storage engine %q in %s", storageEngine, path),
+			Hint: fmt.Sprintf(
+				"state.storage_engine must be either '%s' or '%s'",
+				stateio.StorageEngineMemfile,
+				stateio.StorageEnginePostgres,
+			),
+		}
+	}
+
+	env.engineConfig = engineConfig
+	return CheckResult{
+		Status:  CheckStatusPass,
+		Message: fmt.Sprintf("%s uses the %s state storage engine", path, storageEngine),
+	}
+}
+
+func checkStateStorage(ctx context.Context, env *checkEnv) CheckResult {
+	if env.engineConfig == nil {
+		return CheckResult{
+			Status:  CheckStatusSkip,
+			Message: "skipped as no valid deploy engine config was loaded",
+		}
+	}
+
+	stateConfig := &env.engineConfig.State
+	if stateConfig.StorageEngine == stateio.StorageEngineMemfile {
+		info, err := os.Stat(stateConfig.MemFileStateDir)
+		if err != nil {
+			return CheckResult{
+				Status:  CheckStatusWarn,
+				Message: fmt.Sprintf("state directory %s could not be found: %s", stateConfig.MemFileStateDir, err),
+				Hint:    "the directory is created when the deploy engine first persists state",
+			}
+		}
+		if !info.IsDir() {
+			return CheckResult{
+				Status:  CheckStatusFail,
+				Message: fmt.Sprintf("state directory %s is not a directory", stateConfig.MemFileStateDir),
+			}
+		}
+		return CheckResult{
+			Status:  CheckStatusPass,
+			Message: fmt.Sprintf("state directory %s exists", stateConfig.MemFileStateDir),
+		}
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, env.timeout)
+	defer cancel()
+	if err := stateio.PingPostgres(pingCtx, stateConfig); err != nil {
+		return CheckResult{
+			Status:  CheckStatusFail,
+			Message: err.Error(),
+			Hint:    "make sure the postgres database is running and the credentials in the deploy engine config are correct",
+		}
+	}
+
+	return CheckResult{
+		Status: CheckStatusPass,
+		Message: fmt.Sprintf(
+			"connected to postgres database %s at %s:%d",
+			stateConfig.PostgresDatabase,
+			stateConfig.PostgresHost,
+			stateConfig.PostgresPort,
+		),
+	}
+}
@@ -0,0 +1,276 @@
+package doctorui
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	deerrors "github.com/newstack-cloud/bluelink/libs/deploy-engine-client/errors"
+	"github.com/newstack-cloud/deploy-cli-sdk/config"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/newstack-cloud/deploy-cli-sdk/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+type ChecksSuite struct {
+	suite.Suite
+	dir          string
+	confProvider *config.Provider
+}
+
+func TestChecksSuite(t *testing.T) {
+	suite.Run(t, new(ChecksSuite))
+}
+
+func (s *ChecksSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+	s.confProvider = config.NewProvider()
+}
+
+func (s *ChecksSuite) Test_config_file_check_skips_missing_default_config_file() {
+	env := s.newEnv()
+	env.configFile = filepath.Join(s.dir, "bluelink.config.toml")
+	env.configFileIsDefault = true
+
+	result := checkConfigFile(context.Background(), env)
+	s.Equal(CheckStatusSkip, result.Status)
+}
+
+func (s *ChecksSuite) Test_config_file_check_fails_for_missing_config_file_set_by_user() {
+	env := s.newEnv()
+	env.configFile = filepath.Join(s.dir, "custom.config.toml")
+
+	result := checkConfigFile(context.Background(), env)
+	s.Equal(CheckStatusFail, result.Status)
+	s.Contains(result.Message, "could not be read")
+}
+
+func (s *ChecksSuite) Test_config_file_check_fails_for_unsupported_format() {
+	env := s.newEnv()
+	env.configFile = s.writeFile("bluelink.config.ini", "engineEndpoint=http://localhost:8325", 0o600)
+
+	result := checkConfigFile(context.Background(), env)
+	s.Equal(CheckStatusFail, result.Status)
+	s.Contains(result.Hint, ".yaml, .yml, .json or .toml")
+}
+
+func (s *ChecksSuite) Test_config_file_check_passes_for_valid_config_file() {
+	env := s.newEnv()
+	env.configFile = s.writeFile("bluelink.config.toml", `engineEndpoint = "http://localhost:8325"`, 0o600)
+
+	result := checkConfigFile(context.Background(), env)
+	s.Equal(CheckStatusPass, result.Status)
+}
+
+func (s *ChecksSuite) Test_engine_auth_check_fails_when_not_configured() {
+	result := checkEngineAuth(context.Background(), s.newEnv())
+	s.Equal(CheckStatusFail, result.Status)
+	s.Contains(result.Message, "no engine auth config file is configured")
+}
+
+func (s *ChecksSuite) Test_engine_auth_check_fails_for_invalid_shape() {
+	s.confProvider.SetDefault(
+		"engineAuthConfigFile",
+		s.writeFile("auth.json", `{"method": "apiKey"}`, 0o600),
+	)
+
+	result := checkEngineAuth(context.Background(), s.newEnv())
+	s.Equal(CheckStatusFail, result.Status)
+	s.Contains(result.Message, "requires an apiKey")
+}
+
+func (s *ChecksSuite) Test_engine_auth_check_warns_for_permissive_file_mode() {
+	authFile := s.writeFile("auth.json", `{"method": "apiKey", "apiKey": "test-key"}`, 0o600)
+	s.Require().NoError(os.Chmod(authFile, 0o644))
+	s.confProvider.SetDefault("engineAuthConfigFile", authFile)
+
+	result := checkEngineAuth(context.Background(), s.newEnv())
+	s.Equal(CheckStatusWarn, result.Status)
+	s.Contains(result.Hint, "chmod 600")
+}
+
+func (s *ChecksSuite) Test_engine_auth_check_passes_for_shared_file_with_secret_references() {
+	s.T().Setenv("TEST_DOCTOR_API_KEY", "test-key")
+	authFile := s.writeFile("auth.json", `{"method": "apiKey", "apiKey": "env:TEST_DOCTOR_API_KEY"}`, 0o600)
+	s.Require().NoError(os.Chmod(authFile, 0o644))
+	s.confProvider.SetDefault("engineAuthConfigFile", authFile)
+
+	result := checkEngineAuth(context.Background(), s.newEnv())
+	s.Equal(CheckStatusPass, result.Status)
+}
+
+func (s *ChecksSuite) Test_engine_auth_check_fails_for_unresolvable_secret_reference() {
+	s.confProvider.SetDefault(
+		"engineAuthConfigFile",
+		s.writeFile("auth.json", `{"method": "apiKey", "apiKey": "env:TEST_DOCTOR_MISSING_API_KEY"}`, 0o600),
+	)
+
+	result := checkEngineAuth(context.Background(), s.newEnv())
+	s.Equal(CheckStatusFail, result.Status)
+	s.Contains(result.Message, "TEST_DOCTOR_MISSING_API_KEY environment variable is not set")
+}
+
+func (s *ChecksSuite) Test_engine_auth_check_passes_for_valid_config() {
+	s.confProvider.SetDefault(
+		"engineAuthConfigFile",
+		s.writeFile("auth.json", `{"method": "apiKey", "apiKey": "test-key"}`, 0o600),
+	)
+
+	result := checkEngineAuth(context.Background(), s.newEnv())
+	s.Equal(CheckStatusPass, result.Status)
+	s.Contains(result.Message, "apiKey auth method")
+}
+
+func (s *ChecksSuite) Test_engine_connection_check_fails_for_invalid_protocol() {
+	s.confProvider.SetDefault("connectProtocol", "udp")
+
+	result := checkEngineConnection(context.Background(), s.newEnv())
+	s.Equal(CheckStatusFail, result.Status)
+}
+
+func (s *ChecksSuite) Test_engine_connection_check_fails_for_invalid_endpoint() {
+	s.confProvider.SetDefault("connectProtocol", "tcp")
+	s.confProvider.SetDefault("engineEndpoint", "localhost:8325")
+
+	result := checkEngineConnection(context.Background(), s.newEnv())
+	s.Equal(CheckStatusFail, result.Status)
+	s.Contains(result.Message, "not a valid http or https URL")
+}
+
+func (s *ChecksSuite) Test_engine_connection_check_fails_for_missing_unix_socket() {
+	s.confProvider.SetDefault("connectProtocol", "unix")
+	env := s.newEnv()
+	env.unixSocket = filepath.Join(s.dir, "missing.sock")
+
+	result := checkEngineConnection(context.Background(), env)
+	s.Equal(CheckStatusFail, result.Status)
+	s.Contains(result.Message, "could not be found")
+}
+
+func (s *ChecksSuite) Test_engine_connection_check_passes_for_existing_unix_socket() {
+	// Unix socket paths are limited in length, so a short temp dir is used
+	// instead of the test temp dir.
+	socketDir, err := os.MkdirTemp("", "doctor")
+	s.Require().NoError(err)
+	defer os.RemoveAll(socketDir)
+	socketPath := filepath.Join(socketDir, "engine.sock")
+	listener, err := net.Listen("unix", socketPath)
+	s.Require().NoError(err)
+	defer listener.Close()
+
+	s.confProvider.SetDefault("connectProtocol", "unix")
+	env := s.newEnv()
+	env.unixSocket = socketPath
+
+	result := checkEngineConnection(context.Background(), env)
+	s.Equal(CheckStatusPass, result.Status)
+}
+
+func (s *ChecksSuite) Test_engine_api_check_skips_when_auth_config_failed() {
+	env := s.newEnv()
+	env.results[checkIDEngineAuth] = CheckResult{Status: CheckStatusFail}
+
+	result := checkEngineAPI(context.Background(), env)
+	s.Equal(CheckStatusSkip, result.Status)
+}
+
+func (s *ChecksSuite) Test_engine_api_check_passes_when_engine_accepts_request() {
+	env := s.newEnv()
+	env.createEngine = func() (engine.DeployEngine, error) {
+		return testutils.NewTestDeployEngineForList([]state.InstanceSummary{}), nil
+	}
+
+	result := checkEngineAPI(context.Background(), env)
+	s.Equal(CheckStatusPass, result.Status)
+}
+
+func (s *ChecksSuite) Test_engine_api_check_reports_rejected_credentials() {
+	env := s.newEnv()
+	env.createEngine = func() (engine.DeployEngine, error) {
+		return &listErrorEngine{err: &deerrors.ClientError{StatusCode: 401, Message: "Unauthorized"}}, nil
+	}
+
+	result := checkEngineAPI(context.Background(), env)
+	s.Equal(CheckStatusFail, result.Status)
+	s.Contains(result.Message, "rejected the credentials (401)")
+}
+
+func (s *ChecksSuite) Test_engine_api_check_reports_unreachable_engine() {
+	env := s.newEnv()
+	env.createEngine = func() (engine.DeployEngine, error) {
+		return &listErrorEngine{err: &deerrors.RequestError{Err: errors.New("connection refused")}}, nil
+	}
+
+	result := checkEngineAPI(context.Background(), env)
+	s.Equal(CheckStatusFail, result.Status)
+	s.Contains(result.Message, "could not be reached")
+}
+
+func (s *ChecksSuite) Test_engine_config_check_skips_missing_default_engine_config() {
+	env := s.newEnv()
+	env.engineConfigFile = filepath.Join(s.dir, "config.json")
+	env.engineConfigFileIsDefault = true
+
+	result := checkEngineConfig(context.Background(), env)
+	s.Equal(CheckStatusSkip, result.Status)
+}
+
+func (s *ChecksSuite) Test_engine_config_check_fails_for_unsupported_storage_engine() {
+	env := s.newEnv()
+	env.engineConfigFile = s.writeFile("config.json", `{"state": {"storage_engine": "sqlite"}}`, 0o600)
+
+	result := checkEngineConfig(context.Background(), env)
+	s.Equal(CheckStatusFail, result.Status)
+	s.Contains(result.Message, "unsupported state storage engine")
+}
+
+func (s *ChecksSuite) Test_state_storage_check_passes_for_existing_memfile_dir() {
+	env := s.newEnv()
+	env.engineConfigFile = s.writeFile(
+		"config.json",
+		`{"state": {"storage_engine": "memfile", "memfile_state_dir": "`+s.dir+`"}}`,
+		0o600,
+	)
+
+	s.Equal(CheckStatusPass, checkEngineConfig(context.Background(), env).Status)
+	result := checkStateStorage(context.Background(), env)
+	s.Equal(CheckStatusPass, result.Status)
+}
+
+func (s *ChecksSuite) Test_state_storage_check_skips_without_engine_config() {
+	result := checkStateStorage(context.Background(), s.newEnv())
+	s.Equal(CheckStatusSkip, result.Status)
+}
+
+func (s *ChecksSuite) newEnv() *checkEnv {
+	return &checkEnv{
+		confProvider: s.confProvider,
+		timeout:      time.Second,
+		results:      map[string]CheckResult{},
+	}
+}
+
+func (s *ChecksSuite) writeFile(name, content string, mode os.FileMode) string {
+	path := filepath.Join(s.dir, name)
+	s.Require().NoError(os.WriteFile(path, []byte(content), mode))
+	return path
+}
+
+// listErrorEngine is a deploy engine that fails to list instances
+// with the provided error.
+type listErrorEngine struct {
+	engine.DeployEngine
+	err error
+}
+
+func (e *listErrorEngine) ListBlueprintInstances(
+	ctx context.Context,
+	params state.ListInstancesParams,
+) (state.ListInstancesResult, error) {
+	return state.ListInstancesResult{}, e.err
+}
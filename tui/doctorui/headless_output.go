@@ -0,0 +1,96 @@
+package doctorui
+
+import (
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
+	sdkstrings "github.com/newstack-cloud/deploy-cli-sdk/strings"
+)
+
+func (m *MainModel) printCheckResult(result CheckResult) {
+	if m.printer == nil {
+		return
+	}
+	w := m.printer.Writer()
+	w.Printf("%s %s: %s\n", headlessStatusIcon(result.Status), result.Name, result.Message)
+	if result.Hint != "" && (result.Status == CheckStatusWarn || result.Status == CheckStatusFail) {
+		w.Printf("  hint: %s\n", result.Hint)
+	}
+}
+
+func headlessStatusIcon(status CheckStatus) string {
+	switch status {
+	case CheckStatusPass:
+		return "✓"
+	case CheckStatusWarn:
+		return "⚠"
+	case CheckStatusFail:
+		return "✗"
+	default:
+		return "-"
+	}
+}
+
+func (m *MainModel) dispatchHeadlessSummary() {
+	if m.jsonMode {
+		m.outputJSON()
+		return
+	}
+	m.printHeadlessSummary()
+}
+
+func (m *MainModel) outputJSON() {
+	if m.headlessWriter == nil {
+		return
+	}
+	jsonout.WriteJSON(m.headlessWriter, m.buildJSONOutput())
+}
+
+func (m *MainModel) buildJSONOutput() jsonout.DoctorOutput {
+	output := jsonout.DoctorOutput{
+		Success:  m.Error == nil,
+		CLIName:  m.cliName,
+		Platform: platform(),
+		Summary:  m.summary(),
+		Checks:   make([]jsonout.DoctorCheck, 0, len(m.results)),
+	}
+	for _, result := range m.results {
+		output.Checks = append(output.Checks, jsonout.DoctorCheck{
+			ID:      result.ID,
+			Name:    result.Name,
+			Status:  string(result.Status),
+			Message: result.Message,
+			Hint:    result.Hint,
+		})
+	}
+	return output
+}
+
+func (m *MainModel) summary() jsonout.DoctorSummary {
+	return jsonout.DoctorSummary{
+		Passed:   m.countByStatus(CheckStatusPass),
+		Warnings: m.countByStatus(CheckStatusWarn),
+		Failed:   m.countByStatus(CheckStatusFail),
+		Skipped:  m.countByStatus(CheckStatusSkip),
+	}
+}
+
+func (m *MainModel) printHeadlessSummary() {
+	if m.printer == nil {
+		return
+	}
+	w := m.printer.Writer()
+	summary := m.summary()
+	w.Println("")
+	if summary.Failed > 0 {
+		w.Printf(
+			"✗ %d %s failed\n",
+			summary.Failed,
+			sdkstrings.Pluralize(summary.Failed, "check", "checks"),
+		)
+		return
+	}
+	w.Printf(
+		"✓ All checks passed (%d %s)\n",
+		summary.Warnings,
+		sdkstrings.Pluralize(summary.Warnings, "warning", "warnings"),
+	)
+}
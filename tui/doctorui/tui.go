@@ -0,0 +1,304 @@
+package doctorui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	deployengine "github.com/newstack-cloud/bluelink/libs/deploy-engine-client"
+	"github.com/newstack-cloud/deploy-cli-sdk/config"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/newstack-cloud/deploy-cli-sdk/headless"
+	sdkstrings "github.com/newstack-cloud/deploy-cli-sdk/strings"
+	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/preflight"
+)
+
+// ErrChecksFailed is set as the MainModel error when one or more
+// doctor checks fail, allowing callers to exit with a non-zero code.
+var ErrChecksFailed = errors.New("one or more doctor checks failed")
+
+type doctorSessionState uint32
+
+const (
+	doctorRunningChecks doctorSessionState = iota
+	doctorPreflight
+	doctorComplete
+)
+
+// checkResultMsg is sent when a doctor check has completed.
+type checkResultMsg struct {
+	result CheckResult
+}
+
+// MainModel is the top-level model for the doctor TUI.
+// Checks are carried out one at a time so that later checks
+// can be skipped when a check they depend on fails.
+type MainModel struct {
+	sessionState doctorSessionState
+	ctx          context.Context
+	checks       []check
+	currentCheck int
+	env          *checkEnv
+	results      []CheckResult
+	preflight    tea.Model
+	// preflightSkipReason is reported when there is no preflight model to run.
+	preflightSkipReason string
+	cliName             string
+
+	headless       bool
+	headlessWriter io.Writer
+	printer        *headless.Printer
+	jsonMode       bool
+
+	spinner  spinner.Model
+	styles   *stylespkg.Styles
+	quitting bool
+	Error    error
+}
+
+func (m MainModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, m.runCurrentCheck())
+}
+
+func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case checkResultMsg:
+		return m.handleCheckResult(msg.result)
+	case preflight.SatisfiedMsg:
+		return m.handleCheckResult(CheckResult{
+			Status:  CheckStatusPass,
+			Message: "all preflight checks passed",
+		})
+	case preflight.InstalledMsg:
+		return m.handleCheckResult(CheckResult{
+			Status: CheckStatusWarn,
+			Message: fmt.Sprintf(
+				"%d missing %s installed: %s",
+				msg.InstalledCount,
+				sdkstrings.Pluralize(msg.InstalledCount, "plugin was", "plugins were"),
+				strings.Join(msg.InstalledPlugins, ", "),
+			),
+			Hint: msg.RestartInstructions,
+		})
+	case preflight.MissingMsg:
+		return m.handleCheckResult(CheckResult{
+			Status: CheckStatusFail,
+			Message: fmt.Sprintf(
+				"%d %s missing: %s",
+				len(msg.MissingPlugins),
+				sdkstrings.Pluralize(len(msg.MissingPlugins), "plugin is", "plugins are"),
+				strings.Join(msg.MissingPlugins, ", "),
+			),
+			Hint: fmt.Sprintf("Run `%s doctor --fix` to install the missing plugins.", m.cliName),
+		})
+	case preflight.ErrorMsg:
+		return m.handleCheckResult(CheckResult{
+			Status:  CheckStatusFail,
+			Message: msg.Err.Error(),
+		})
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			m.quitting = true
+			return m, tea.Quit
+		case "q":
+			if m.sessionState == doctorComplete {
+				m.quitting = true
+				return m, tea.Quit
+			}
+		}
+	}
+
+	if m.sessionState == doctorPreflight && m.preflight != nil {
+		updated, cmd := m.preflight.Update(msg)
+		m.preflight = updated
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m MainModel) handleCheckResult(result CheckResult) (tea.Model, tea.Cmd) {
+	if m.sessionState == doctorPreflight {
+		result.ID = checkIDPreflight
+		result.Name = preflightCheckName
+	}
+	m.results = append(m.results, result)
+	m.env.results[result.ID] = result
+	m.printCheckResult(result)
+
+	if m.sessionState == doctorRunningChecks {
+		m.currentCheck += 1
+		if m.currentCheck < len(m.checks) {
+			return m, m.runCurrentCheck()
+		}
+		return m.startPreflight()
+	}
+
+	return m, m.finish()
+}
+
+func (m MainModel) startPreflight() (tea.Model, tea.Cmd) {
+	if m.preflight == nil {
+		m.sessionState = doctorPreflight
+		return m.handleCheckResult(CheckResult{
+			Status:  CheckStatusSkip,
+			Message: m.preflightSkipReason,
+		})
+	}
+
+	m.sessionState = doctorPreflight
+	return m, m.preflight.Init()
+}
+
+func (m *MainModel) finish() tea.Cmd {
+	m.sessionState = doctorComplete
+	if m.countByStatus(CheckStatusFail) > 0 {
+		m.Error = ErrChecksFailed
+	}
+
+	if m.headless {
+		m.dispatchHeadlessSummary()
+		return tea.Quit
+	}
+	return nil
+}
+
+func (m MainModel) runCurrentCheck() tea.Cmd {
+	current := m.checks[m.currentCheck]
+	ctx := m.ctx
+	env := m.env
+	return func() tea.Msg {
+		result := current.run(ctx, env)
+		result.ID = current.id
+		result.Name = current.name
+		return checkResultMsg{result: result}
+	}
+}
+
+func (m *MainModel) countByStatus(status CheckStatus) int {
+	count := 0
+	for _, result := range m.results {
+		if result.Status == status {
+			count += 1
+		}
+	}
+	return count
+}
+
+// Results returns the results of the checks that have completed, in the order
+// they were carried out.
+func (m *MainModel) Results() []CheckResult {
+	return m.results
+}
+
+const preflightCheckName = "Preflight checks"
+
+// DoctorAppConfig holds configuration for creating a new doctor application.
+type DoctorAppConfig struct {
+	// Context is bound to the network calls made by the checks so they are
+	// cancelled when the command context is cancelled (e.g. on Ctrl+C).
+	Context      context.Context
+	ConfProvider *config.Provider
+	// CreateEngine creates the deploy engine client used for the
+	// authenticated engine call check.
+	CreateEngine func() (engine.DeployEngine, error)
+	CLIName      string
+	// ConfigFile is the path of the CLI config file to check.
+	ConfigFile          string
+	ConfigFileIsDefault bool
+	// EngineConfigFile is the path of the deploy engine config file used to determine
+	// the state storage backend, the default engine config path is used when empty.
+	EngineConfigFile          string
+	EngineConfigFileIsDefault bool
+	// UnixSocket is the unix socket the deploy engine client connects to when the
+	// connect protocol is "unix", defaults to the deploy engine client default.
+	UnixSocket string
+	// CheckTimeout is the time allowed for each check that makes a network call,
+	// defaults to 10 seconds.
+	CheckTimeout time.Duration
+	// Preflight must only check for problems, doctor must not change the system
+	// (e.g. by installing missing plugins) unless the user asked it to.
+	Preflight tea.Model
+	// PreflightSkipReason is reported as the result of the preflight check when
+	// Preflight is nil, defaults to a message saying that no preflight checks are
+	// configured or they were skipped.
+	PreflightSkipReason string
+	Styles              *stylespkg.Styles
+	Headless            bool
+	HeadlessWriter      io.Writer
+	JSONMode            bool
+}
+
+// NewDoctorApp creates a new doctor TUI application.
+func NewDoctorApp(cfg DoctorAppConfig) (*MainModel, error) {
+	ctx := cfg.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	unixSocket := cfg.UnixSocket
+	if unixSocket == "" {
+		unixSocket = deployengine.DefaultUnixDomainSocket
+	}
+
+	checkTimeout := cfg.CheckTimeout
+	if checkTimeout <= 0 {
+		checkTimeout = defaultCheckTimeout
+	}
+
+	preflightSkipReason := cfg.PreflightSkipReason
+	if preflightSkipReason == "" {
+		preflightSkipReason = "no preflight checks are configured or they were skipped with --skip-plugin-check"
+	}
+
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = cfg.Styles.Spinner
+
+	var printer *headless.Printer
+	if cfg.Headless && !cfg.JSONMode && cfg.HeadlessWriter != nil {
+		printer = headless.NewPrinter(headless.NewPrefixedWriter(cfg.HeadlessWriter, "[doctor] "), 80)
+	}
+
+	return &MainModel{
+		sessionState: doctorRunningChecks,
+		ctx:          ctx,
+		checks:       newChecks(),
+		env: &checkEnv{
+			confProvider:              cfg.ConfProvider,
+			configFile:                cfg.ConfigFile,
+			configFileIsDefault:       cfg.ConfigFileIsDefault,
+			engineConfigFile:          cfg.EngineConfigFile,
+			engineConfigFileIsDefault: cfg.EngineConfigFileIsDefault,
+			unixSocket:                unixSocket,
+			createEngine:              cfg.CreateEngine,
+			timeout:                   checkTimeout,
+			results:                   map[string]CheckResult{},
+		},
+		preflight:           cfg.Preflight,
+		preflightSkipReason: preflightSkipReason,
+		cliName:             cfg.CLIName,
+		headless:            cfg.Headless,
+		headlessWriter:      cfg.HeadlessWriter,
+		printer:             printer,
+		jsonMode:            cfg.JSONMode,
+		spinner:             s,
+		styles:              cfg.Styles,
+	}, nil
+}
+
+func platform() string {
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
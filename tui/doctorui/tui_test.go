@@ -0,0 +1,222 @@
+package doctorui
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/deploy-cli-sdk/config"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
+	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
+	"github.com/newstack-cloud/deploy-cli-sdk/testutils"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/preflight"
+	"github.com/stretchr/testify/suite"
+)
+
+type DoctorTUISuite struct {
+	suite.Suite
+	styles       *stylespkg.Styles
+	dir          string
+	confProvider *config.Provider
+}
+
+func TestDoctorTUISuite(t *testing.T) {
+	suite.Run(t, new(DoctorTUISuite))
+}
+
+func (s *DoctorTUISuite) SetupTest() {
+	s.styles = stylespkg.NewStyles(lipgloss.NewRenderer(os.Stdout), stylespkg.NewBluelinkPalette())
+	s.dir = s.T().TempDir()
+
+	authFile := filepath.Join(s.dir, "auth.json")
+	s.Require().NoError(os.WriteFile(authFile, []byte(`{"method": "apiKey", "apiKey": "test-key"}`), 0o600))
+	s.confProvider = config.NewProvider()
+	s.confProvider.SetDefault("engineAuthConfigFile", authFile)
+	s.confProvider.SetDefault("connectProtocol", "tcp")
+	s.confProvider.SetDefault("engineEndpoint", "http://localhost:8325")
+}
+
+func (s *DoctorTUISuite) Test_headless_json_reports_all_checks() {
+	headlessOutput := &bytes.Buffer{}
+	model := s.newDoctorApp(headlessOutput, true, &stubPreflight{msg: preflight.SatisfiedMsg{}})
+
+	testModel := teatest.NewTestModel(s.T(), *model, teatest.WithInitialTermSize(300, 100))
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+
+	finalModel := testModel.FinalModel(s.T()).(MainModel)
+	s.Nil(finalModel.Error)
+
+	output := jsonout.DoctorOutput{}
+	s.Require().NoError(json.Unmarshal(headlessOutput.Bytes(), &output))
+	s.True(output.Success)
+	s.Equal("bluelink", output.CLIName)
+	s.NotEmpty(output.Platform)
+	s.Equal(jsonout.DoctorSummary{Passed: 4, Skipped: 3}, output.Summary)
+
+	checkIDs := []string{}
+	for _, check := range output.Checks {
+		checkIDs = append(checkIDs, check.ID)
+	}
+	s.Equal(
+		[]string{
+			checkIDConfigFile,
+			checkIDEngineAuth,
+			checkIDEngineConnection,
+			checkIDEngineAPI,
+			checkIDEngineConfig,
+			checkIDStateStorage,
+			checkIDPreflight,
+		},
+		checkIDs,
+	)
+}
+
+func (s *DoctorTUISuite) Test_headless_text_reports_failed_preflight() {
+	headlessOutput := &bytes.Buffer{}
+	model := s.newDoctorApp(
+		headlessOutput,
+		false,
+		&stubPreflight{msg: preflight.ErrorMsg{Err: errors.New("plugin registry unavailable")}},
+	)
+
+	testModel := teatest.NewTestModel(s.T(), *model, teatest.WithInitialTermSize(300, 100))
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+
+	finalModel := testModel.FinalModel(s.T()).(MainModel)
+	s.ErrorIs(finalModel.Error, ErrChecksFailed)
+
+	output := headlessOutput.String()
+	s.Contains(output, "[doctor] ✓ Engine auth config:")
+	s.Contains(output, "[doctor] ✗ Preflight checks: plugin registry unavailable")
+	s.Contains(output, "[doctor] ✗ 1 check failed")
+}
+
+func (s *DoctorTUISuite) Test_headless_text_reports_missing_plugins_as_failed_preflight() {
+	headlessOutput := &bytes.Buffer{}
+	model := s.newDoctorApp(
+		headlessOutput,
+		false,
+		&stubPreflight{msg: preflight.MissingMsg{MissingPlugins: []string{"aws", "gcp"}}},
+	)
+
+	testModel := teatest.NewTestModel(s.T(), *model, teatest.WithInitialTermSize(300, 100))
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+
+	finalModel := testModel.FinalModel(s.T()).(MainModel)
+	s.ErrorIs(finalModel.Error, ErrChecksFailed)
+
+	output := headlessOutput.String()
+	s.Contains(output, "[doctor] ✗ Preflight checks: 2 plugins are missing: aws, gcp")
+	s.Contains(output, "hint: Run `bluelink doctor --fix` to install the missing plugins.")
+}
+
+func (s *DoctorTUISuite) Test_headless_json_reports_preflight_skip_reason() {
+	headlessOutput := &bytes.Buffer{}
+	model := s.newDoctorApp(headlessOutput, true, nil)
+	model.preflightSkipReason = "the preflight checks install missing plugins, re-run with --fix to run them"
+
+	testModel := teatest.NewTestModel(s.T(), *model, teatest.WithInitialTermSize(300, 100))
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+
+	output := jsonout.DoctorOutput{}
+	s.Require().NoError(json.Unmarshal(headlessOutput.Bytes(), &output))
+	preflightCheck := output.Checks[len(output.Checks)-1]
+	s.Equal(checkIDPreflight, preflightCheck.ID)
+	s.Equal(string(CheckStatusSkip), preflightCheck.Status)
+	s.Equal(
+		"the preflight checks install missing plugins, re-run with --fix to run them",
+		preflightCheck.Message,
+	)
+}
+
+func (s *DoctorTUISuite) Test_engine_call_failure_fails_doctor() {
+	headlessOutput := &bytes.Buffer{}
+	model := s.newDoctorApp(headlessOutput, true, nil)
+	model.env.createEngine = func() (engine.DeployEngine, error) {
+		return testutils.NewTestDeployEngineForListError(), nil
+	}
+
+	testModel := teatest.NewTestModel(s.T(), *model, teatest.WithInitialTermSize(300, 100))
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+
+	finalModel := testModel.FinalModel(s.T()).(MainModel)
+	s.ErrorIs(finalModel.Error, ErrChecksFailed)
+
+	output := jsonout.DoctorOutput{}
+	s.Require().NoError(json.Unmarshal(headlessOutput.Bytes(), &output))
+	s.False(output.Success)
+	s.Equal(1, output.Summary.Failed)
+	s.Equal(string(CheckStatusFail), output.Checks[3].Status)
+	s.Equal(string(CheckStatusSkip), output.Checks[6].Status)
+}
+
+func (s *DoctorTUISuite) Test_interactive_mode_renders_checklist() {
+	model := s.newDoctorApp(nil, false, nil)
+
+	testModel := teatest.NewTestModel(s.T(), *model, teatest.WithInitialTermSize(300, 100))
+	testutils.WaitForContainsAll(
+		s.T(),
+		testModel.Output(),
+		"Engine auth config",
+		"Authenticated engine call",
+		"All checks passed",
+		"Press q to quit",
+	)
+
+	testModel.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+}
+
+// newDoctorApp creates a doctor app that runs in headless mode
+// when a headless output writer is provided.
+func (s *DoctorTUISuite) newDoctorApp(
+	headlessOutput io.Writer,
+	jsonMode bool,
+	preflightModel tea.Model,
+) *MainModel {
+	model, err := NewDoctorApp(DoctorAppConfig{
+		ConfProvider: s.confProvider,
+		CreateEngine: func() (engine.DeployEngine, error) {
+			return testutils.NewTestDeployEngineForList([]state.InstanceSummary{}), nil
+		},
+		CLIName:                   "bluelink",
+		ConfigFile:                filepath.Join(s.dir, "bluelink.config.toml"),
+		ConfigFileIsDefault:       true,
+		EngineConfigFile:          filepath.Join(s.dir, "engine-config.json"),
+		EngineConfigFileIsDefault: true,
+		Preflight:                 preflightModel,
+		Styles:                    s.styles,
+		Headless:                  headlessOutput != nil,
+		HeadlessWriter:            headlessOutput,
+		JSONMode:                  jsonMode,
+	})
+	s.Require().NoError(err)
+	return model
+}
+
+// stubPreflight is a preflight model that completes with the provided message.
+type stubPreflight struct {
+	msg tea.Msg
+}
+
+func (p *stubPreflight) Init() tea.Cmd {
+	return func() tea.Msg { return p.msg }
+}
+
+func (p *stubPreflight) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	return p, nil
+}
+
+func (p *stubPreflight) View() string {
+	return ""
+}
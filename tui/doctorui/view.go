@@ -0,0 +1,95 @@
+package doctorui
+
+import (
+	"fmt"
+	"strings"
+
+	sdkstrings "github.com/newstack-cloud/deploy-cli-sdk/strings"
+)
+
+func (m MainModel) View() string {
+	if m.headless {
+		return ""
+	}
+
+	if m.quitting {
+		return m.styles.Muted.Margin(1, 0, 2, 4).Render("Had enough? See you next time.")
+	}
+
+	if m.sessionState == doctorPreflight && m.preflight != nil {
+		return m.renderChecklist() + m.preflight.View()
+	}
+
+	view := m.renderChecklist()
+	if m.sessionState == doctorComplete {
+		view += m.renderSummary()
+	}
+	return view
+}
+
+func (m MainModel) renderChecklist() string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+
+	for _, result := range m.results {
+		fmt.Fprintf(&sb, "  %s %s\n", m.statusIcon(result.Status), result.Name)
+		fmt.Fprintf(&sb, "    %s\n", m.styles.Muted.Render(result.Message))
+		if result.Hint != "" && (result.Status == CheckStatusWarn || result.Status == CheckStatusFail) {
+			fmt.Fprintf(&sb, "    %s\n", m.styles.Hint.Render("hint: "+result.Hint))
+		}
+	}
+
+	switch m.sessionState {
+	case doctorRunningChecks:
+		fmt.Fprintf(&sb, "  %s Checking %s...\n", m.spinner.View(), m.checks[m.currentCheck].name)
+		for _, pending := range m.checks[m.currentCheck+1:] {
+			fmt.Fprintf(&sb, "  %s %s\n", m.styles.Muted.Render("○"), m.styles.Muted.Render(pending.name))
+		}
+		fmt.Fprintf(&sb, "  %s %s\n", m.styles.Muted.Render("○"), m.styles.Muted.Render(preflightCheckName))
+	case doctorPreflight:
+		fmt.Fprintf(&sb, "  %s Running %s...\n", m.spinner.View(), strings.ToLower(preflightCheckName))
+	}
+
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+func (m MainModel) statusIcon(status CheckStatus) string {
+	switch status {
+	case CheckStatusPass:
+		return m.styles.Success.Render("✓")
+	case CheckStatusWarn:
+		return m.styles.Warning.Render("⚠")
+	case CheckStatusFail:
+		return m.styles.Error.Render("✗")
+	default:
+		return m.styles.Muted.Render("-")
+	}
+}
+
+func (m MainModel) renderSummary() string {
+	var sb strings.Builder
+	summary := m.summary()
+
+	sb.WriteString("  ")
+	if summary.Failed > 0 {
+		sb.WriteString(m.styles.Error.Render(fmt.Sprintf(
+			"%d %s failed",
+			summary.Failed,
+			sdkstrings.Pluralize(summary.Failed, "check", "checks"),
+		)))
+	} else {
+		sb.WriteString(m.styles.Success.Render("All checks passed"))
+	}
+	sb.WriteString(m.styles.Muted.Render(fmt.Sprintf(
+		" (%d passed, %d %s, %d skipped)",
+		summary.Passed,
+		summary.Warnings,
+		sdkstrings.Pluralize(summary.Warnings, "warning", "warnings"),
+		summary.Skipped,
+	)))
+	sb.WriteString("\n\n  ")
+	sb.WriteString(m.styles.Muted.Render("Run with --json to produce a report for support tickets. Press q to quit"))
+	sb.WriteString("\n")
+	return sb.String()
+}
@@ -157,10 +157,15 @@ func (p *HeadlessDriftPrinter) printChildNode(node *headlessChildNode, indent in
 }
 
 func (p *HeadlessDriftPrinter) printHint() {
+	hint := HintForContext(p.context)
+	if hint == "" {
+		return
+	}
+
 	w := p.printer.Writer()
 	w.Println("To resolve:")
 	w.Println("  1. Review external changes manually and update your blueprint")
-	w.Printf("  2. Or re-run with %s\n", HintForContext(p.context))
+	w.Printf("  2. Or re-run with %s\n", hint)
 	w.PrintlnEmpty()
 }
 
@@ -53,6 +53,12 @@ const (
 	// DriftContextDestroy is used when drift is detected during destroy (409 response).
 	// Hint: "Use --force to override drift check"
 	DriftContextDestroy DriftContext = "destroy"
+	// DriftContextCheck is used when drift is reported by the drift check command.
+	// Hint: "Run drift reconcile to update the instance state"
+	DriftContextCheck DriftContext = "check"
+	// DriftContextReconcile is used when drift is reported by the drift reconcile command.
+	// No hint is shown as the drift is being reconciled.
+	DriftContextReconcile DriftContext = "reconcile"
 )
 
 // The CLI binary name used in user-facing hints. It defaults to
@@ -78,6 +84,8 @@ func HintForContext(ctx DriftContext) string {
 		return "Run " + cliName + " deploy --force to override drift check"
 	case DriftContextDestroy:
 		return "Run " + cliName + " destroy --force to override drift check"
+	case DriftContextCheck:
+		return "Run " + cliName + " drift reconcile to update the instance state"
 	default:
 		return ""
 	}
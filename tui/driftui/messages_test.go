@@ -34,6 +34,16 @@ func (s *MessagesTestSuite) Test_HintForContext_destroy() {
 	s.Equal("Run bluelink destroy --force to override drift check", hint)
 }
 
+func (s *MessagesTestSuite) Test_HintForContext_check() {
+	hint := HintForContext(DriftContextCheck)
+	s.Equal("Run bluelink drift reconcile to update the instance state", hint)
+}
+
+func (s *MessagesTestSuite) Test_HintForContext_reconcile_returns_empty() {
+	hint := HintForContext(DriftContextReconcile)
+	s.Equal("", hint)
+}
+
 func (s *MessagesTestSuite) Test_HintForContext_unknown_returns_empty() {
 	hint := HintForContext("unknown_context")
 	s.Equal("", hint)
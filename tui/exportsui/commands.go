@@ -0,0 +1,29 @@
+package exportsui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+)
+
+// ExportsFetchedMsg is sent when the exports of an instance have been fetched.
+type ExportsFetchedMsg struct {
+	Exports map[string]*state.ExportState
+}
+
+// ExportsErrorMsg is sent when fetching the exports of an instance fails.
+type ExportsErrorMsg struct {
+	Err error
+}
+
+func fetchExportsCmd(model MainModel) tea.Cmd {
+	return func() tea.Msg {
+		exports, err := model.engine.GetBlueprintInstanceExports(
+			model.reqCtx(),
+			model.instanceIdentifier(),
+		)
+		if err != nil {
+			return ExportsErrorMsg{Err: err}
+		}
+		return ExportsFetchedMsg{Exports: exports}
+	}
+}
@@ -0,0 +1,168 @@
+package exportsui
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/deploy-cli-sdk/headless"
+)
+
+// OutputFormat determines how exports are written in headless mode.
+type OutputFormat string
+
+const (
+	// OutputFormatText renders a human-readable summary of the exports.
+	OutputFormatText OutputFormat = "text"
+	// OutputFormatJSON renders the exports using the jsonout schema.
+	OutputFormatJSON OutputFormat = "json"
+	// OutputFormatDotenv renders the exports as NAME="value" lines
+	// that can be loaded as a .env file.
+	OutputFormatDotenv OutputFormat = "dotenv"
+	// OutputFormatShell renders the exports as shell export statements
+	// that can be evaluated in a POSIX shell.
+	OutputFormatShell OutputFormat = "shell"
+)
+
+// ParseOutputFormat parses an output format from a flag value.
+func ParseOutputFormat(value string) (OutputFormat, error) {
+	switch OutputFormat(strings.ToLower(strings.TrimSpace(value))) {
+	case OutputFormatText, "":
+		return OutputFormatText, nil
+	case OutputFormatJSON:
+		return OutputFormatJSON, nil
+	case OutputFormatDotenv:
+		return OutputFormatDotenv, nil
+	case OutputFormatShell:
+		return OutputFormatShell, nil
+	default:
+		return "", fmt.Errorf(
+			"invalid output format %q, expected one of: text, json, dotenv, shell",
+			value,
+		)
+	}
+}
+
+// EnvVarName converts an export name into an environment variable name.
+// For example, "queueURL" becomes "QUEUE_URL" and "bucket-arn" becomes "BUCKET_ARN".
+func EnvVarName(exportName string) string {
+	runes := []rune(exportName)
+	var sb strings.Builder
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			sb.WriteRune('_')
+			continue
+		}
+		if i > 0 && unicode.IsUpper(r) && startsNewWord(runes, i) {
+			sb.WriteRune('_')
+		}
+		sb.WriteRune(unicode.ToUpper(r))
+	}
+
+	name := collapseUnderscores(sb.String())
+	if name != "" && unicode.IsDigit(rune(name[0])) {
+		name = "_" + name
+	}
+	return name
+}
+
+// startsNewWord determines whether the upper case rune at index i
+// marks the start of a new word in a camel case string.
+func startsNewWord(runes []rune, i int) bool {
+	prev := runes[i-1]
+	if unicode.IsLower(prev) || unicode.IsDigit(prev) {
+		return true
+	}
+	// Handles the end of an acronym such as the "A" in "URLAddress".
+	return unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+}
+
+func collapseUnderscores(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_'
+	})
+	return strings.Join(parts, "_")
+}
+
+// RawValue returns the unquoted value of an export for use in
+// environment files, shell scripts and single field output.
+// Strings are returned as-is, other scalars use their display form
+// and arrays or maps are encoded as compact JSON.
+func RawValue(node *core.MappingNode) string {
+	if node == nil {
+		return ""
+	}
+
+	if node.Scalar != nil {
+		if node.Scalar.StringValue != nil {
+			return *node.Scalar.StringValue
+		}
+		if node.Scalar.FloatValue != nil {
+			return fmt.Sprintf("%g", *node.Scalar.FloatValue)
+		}
+		return headless.FormatScalarValue(node.Scalar)
+	}
+
+	if node.Items != nil || node.Fields != nil {
+		jsonBytes, err := json.Marshal(node)
+		if err == nil {
+			return string(jsonBytes)
+		}
+	}
+
+	return headless.FormatMappingNode(node)
+}
+
+// SortedExportNames returns the names of the provided exports in alphabetical order.
+func SortedExportNames(exports map[string]*state.ExportState) []string {
+	names := make([]string, 0, len(exports))
+	for name := range exports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FormatDotenv renders exports as dotenv lines in alphabetical order.
+func FormatDotenv(exports map[string]*state.ExportState) string {
+	var sb strings.Builder
+	for _, name := range SortedExportNames(exports) {
+		value := exportRawValue(exports[name])
+		sb.WriteString(fmt.Sprintf("%s=\"%s\"\n", EnvVarName(name), escapeDotenvValue(value)))
+	}
+	return sb.String()
+}
+
+// FormatShell renders exports as shell export statements in alphabetical order.
+func FormatShell(exports map[string]*state.ExportState) string {
+	var sb strings.Builder
+	for _, name := range SortedExportNames(exports) {
+		value := exportRawValue(exports[name])
+		sb.WriteString(fmt.Sprintf("export %s=%s\n", EnvVarName(name), quoteShellValue(value)))
+	}
+	return sb.String()
+}
+
+func exportRawValue(export *state.ExportState) string {
+	if export == nil {
+		return ""
+	}
+	return RawValue(export.Value)
+}
+
+func escapeDotenvValue(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+	)
+	return replacer.Replace(value)
+}
+
+func quoteShellValue(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,109 @@
+package exportsui
+
+import (
+	"testing"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/schema"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/stretchr/testify/suite"
+)
+
+type FormatsSuite struct {
+	suite.Suite
+}
+
+func TestFormatsSuite(t *testing.T) {
+	suite.Run(t, new(FormatsSuite))
+}
+
+func (s *FormatsSuite) Test_EnvVarName_converts_export_names() {
+	cases := map[string]string{
+		"queueUrl":        "QUEUE_URL",
+		"bucket-arn":      "BUCKET_ARN",
+		"URLAddress":      "URL_ADDRESS",
+		"api.endpoint":    "API_ENDPOINT",
+		"table2Name":      "TABLE2_NAME",
+		"1stOutput":       "_1ST_OUTPUT",
+		"already_UPPER":   "ALREADY_UPPER",
+		"double--dash__x": "DOUBLE_DASH_X",
+	}
+	for input, expected := range cases {
+		s.Equal(expected, EnvVarName(input), "input: %s", input)
+	}
+}
+
+func (s *FormatsSuite) Test_ParseOutputFormat_accepts_known_formats() {
+	for _, value := range []string{"", "text", "JSON", " dotenv ", "shell"} {
+		_, err := ParseOutputFormat(value)
+		s.NoError(err, "value: %q", value)
+	}
+}
+
+func (s *FormatsSuite) Test_ParseOutputFormat_rejects_unknown_format() {
+	_, err := ParseOutputFormat("yaml")
+	s.Error(err)
+	s.Contains(err.Error(), "yaml")
+}
+
+func (s *FormatsSuite) Test_RawValue_formats_scalars_and_complex_values() {
+	s.Equal("", RawValue(nil))
+	s.Equal("hello", RawValue(core.MappingNodeFromString("hello")))
+	s.Equal("42", RawValue(core.MappingNodeFromInt(42)))
+	s.Equal("1.5", RawValue(core.MappingNodeFromFloat(1.5)))
+	s.Equal("true", RawValue(core.MappingNodeFromBool(true)))
+	s.Equal(`["a","b"]`, RawValue(&core.MappingNode{
+		Items: []*core.MappingNode{
+			core.MappingNodeFromString("a"),
+			core.MappingNodeFromString("b"),
+		},
+	}))
+}
+
+func (s *FormatsSuite) Test_FormatDotenv_writes_sorted_escaped_lines() {
+	output := FormatDotenv(testExports())
+	s.Equal(
+		"BUCKET_ARN=\"arn:aws:s3:::my-bucket\"\n"+
+			"GREETING=\"say \\\"hi\\\"\"\n"+
+			"QUEUE_URL=\"https://sqs.example.com/queue\"\n",
+		output,
+	)
+}
+
+func (s *FormatsSuite) Test_FormatShell_writes_sorted_quoted_exports() {
+	output := FormatShell(testExports())
+	s.Equal(
+		"export BUCKET_ARN='arn:aws:s3:::my-bucket'\n"+
+			"export GREETING='say \"hi\"'\n"+
+			"export QUEUE_URL='https://sqs.example.com/queue'\n",
+		output,
+	)
+}
+
+func (s *FormatsSuite) Test_FormatShell_escapes_single_quotes() {
+	output := FormatShell(map[string]*state.ExportState{
+		"message": {Value: core.MappingNodeFromString("it's")},
+	})
+	s.Equal("export MESSAGE='it'\\''s'\n", output)
+}
+
+func testExports() map[string]*state.ExportState {
+	return map[string]*state.ExportState{
+		"queueUrl": {
+			Value:       core.MappingNodeFromString("https://sqs.example.com/queue"),
+			Type:        schema.ExportTypeString,
+			Field:       "resources.queue.spec.url",
+			Description: "The URL of the queue.",
+		},
+		"bucketArn": {
+			Value: core.MappingNodeFromString("arn:aws:s3:::my-bucket"),
+			Type:  schema.ExportTypeString,
+			Field: "resources.bucket.spec.arn",
+		},
+		"greeting": {
+			Value: core.MappingNodeFromString(`say "hi"`),
+			Type:  schema.ExportTypeString,
+			Field: "values.greeting",
+		},
+	}
+}
@@ -0,0 +1,135 @@
+package exportsui
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/newstack-cloud/deploy-cli-sdk/headless"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
+	sdkstrings "github.com/newstack-cloud/deploy-cli-sdk/strings"
+)
+
+// dispatchHeadlessOutput writes the loaded exports in the configured format.
+// When a field is configured, only the raw value of that export is written,
+// regardless of the output format.
+func (m *MainModel) dispatchHeadlessOutput() error {
+	if m.field != "" {
+		return m.outputField()
+	}
+
+	switch m.format {
+	case OutputFormatJSON:
+		return m.outputJSON()
+	case OutputFormatDotenv:
+		return writeString(m.headlessWriter, FormatDotenv(m.exports))
+	case OutputFormatShell:
+		return writeString(m.headlessWriter, FormatShell(m.exports))
+	default:
+		m.printHeadlessExports()
+		return nil
+	}
+}
+
+// dispatchHeadlessError reports an error for the formats that have
+// an error representation.
+// Dotenv, shell and single field output are intended to be consumed by other
+// programs so errors are left to the caller to report outside of the output stream.
+func (m *MainModel) dispatchHeadlessError(err error) {
+	if m.field != "" {
+		return
+	}
+
+	switch m.format {
+	case OutputFormatJSON:
+		m.outputJSONError(err)
+	case OutputFormatText:
+		m.printHeadlessError(err)
+	}
+}
+
+func (m *MainModel) outputField() error {
+	export, ok := m.exports[m.field]
+	if !ok {
+		return fmt.Errorf(
+			"export %q not found for instance %q",
+			m.field,
+			m.instanceDisplayName(),
+		)
+	}
+	return writeString(m.headlessWriter, exportRawValue(export)+"\n")
+}
+
+func (m *MainModel) outputJSON() error {
+	if m.headlessWriter == nil {
+		return nil
+	}
+	output := jsonout.InstanceExportsOutput{
+		Success:      true,
+		InstanceID:   m.instanceID,
+		InstanceName: m.instanceName,
+		Exports:      m.exports,
+	}
+	return jsonout.WriteJSON(m.headlessWriter, output)
+}
+
+func (m *MainModel) outputJSONError(err error) {
+	if m.headlessWriter == nil {
+		return
+	}
+	jsonout.WriteJSON(m.headlessWriter, jsonout.NewErrorOutput(err))
+}
+
+func (m *MainModel) printHeadlessExports() {
+	if m.printer == nil {
+		return
+	}
+
+	w := m.printer.Writer()
+	w.PrintlnEmpty()
+	w.Printf("Instance Exports: %s\n", m.instanceDisplayName())
+	w.DoubleSeparator(72)
+
+	if len(m.exportNames) == 0 {
+		w.Println("No exports found.")
+		return
+	}
+
+	for _, name := range m.exportNames {
+		export := m.exports[name]
+		w.Printf("  %s\n", name)
+		if export == nil {
+			continue
+		}
+		w.Printf("    Type: %s\n", export.Type)
+		w.Printf("    Field: %s\n", export.Field)
+		w.Printf("    Value: %s\n", headless.FormatMappingNode(export.Value))
+		if export.Description != "" {
+			w.Printf("    Description: %s\n", export.Description)
+		}
+	}
+
+	count := len(m.exportNames)
+	w.PrintlnEmpty()
+	w.DoubleSeparator(72)
+	w.Printf("Total: %d %s\n", count, sdkstrings.Pluralize(count, "export", "exports"))
+	w.PrintlnEmpty()
+}
+
+func (m *MainModel) printHeadlessError(err error) {
+	if m.printer == nil {
+		return
+	}
+	w := m.printer.Writer()
+	w.PrintlnEmpty()
+	w.Println("ERR Fetching instance exports failed")
+	w.PrintlnEmpty()
+	w.Printf("  Error: %s\n", err.Error())
+}
+
+func writeString(w io.Writer, value string) error {
+	if w == nil {
+		return nil
+	}
+	_, err := io.WriteString(w, value)
+	return err
+}
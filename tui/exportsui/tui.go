@@ -0,0 +1,247 @@
+package exportsui
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/newstack-cloud/deploy-cli-sdk/headless"
+	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
+	"go.uber.org/zap"
+)
+
+const keyCtrlC = "ctrl+c"
+
+type exportsSessionState uint32
+
+const (
+	exportsLoading exportsSessionState = iota
+	exportsViewing
+)
+
+// MainModel is the top-level model for the instance exports command TUI.
+type MainModel struct {
+	sessionState exportsSessionState
+	quitting     bool
+
+	// Config from flags
+	instanceID   string
+	instanceName string
+	field        string
+	format       OutputFormat
+
+	// Loaded data
+	exports     map[string]*state.ExportState
+	exportNames []string
+
+	// Selection state
+	cursor int
+
+	// Runtime state
+	ctx      context.Context
+	headless bool
+	spinner  spinner.Model
+
+	// Dependencies
+	engine engine.DeployEngine
+	logger *zap.Logger
+	styles *stylespkg.Styles
+
+	// Output
+	headlessWriter io.Writer
+	printer        *headless.Printer
+
+	// Window size
+	width int
+
+	Error error
+}
+
+// Init initializes the main model.
+func (m MainModel) Init() tea.Cmd {
+	cmds := []tea.Cmd{fetchExportsCmd(m)}
+	if !m.headless {
+		cmds = append(cmds, m.spinner.Tick)
+	}
+	return tea.Batch(cmds...)
+}
+
+// Update handles messages for the main model.
+func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		return m, nil
+
+	case ExportsFetchedMsg:
+		return m.handleExportsFetched(msg)
+
+	case ExportsErrorMsg:
+		return m.handleExportsError(msg)
+
+	case spinner.TickMsg:
+		if m.sessionState != exportsLoading {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case tea.KeyMsg:
+		return m.handleKeyPress(msg)
+	}
+
+	return m, nil
+}
+
+func (m MainModel) handleExportsFetched(msg ExportsFetchedMsg) (tea.Model, tea.Cmd) {
+	m.exports = msg.Exports
+	m.exportNames = SortedExportNames(msg.Exports)
+	m.sessionState = exportsViewing
+	m.cursor = 0
+
+	if m.headless {
+		if err := m.dispatchHeadlessOutput(); err != nil {
+			m.Error = err
+		}
+		return m, tea.Quit
+	}
+
+	return m, nil
+}
+
+func (m MainModel) handleExportsError(msg ExportsErrorMsg) (tea.Model, tea.Cmd) {
+	m.Error = msg.Err
+	if m.headless {
+		m.dispatchHeadlessError(msg.Err)
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m MainModel) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case keyCtrlC, "q":
+		m.quitting = true
+		return m, tea.Quit
+
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+
+	case "down", "j":
+		if m.cursor < len(m.exportNames)-1 {
+			m.cursor += 1
+		}
+	}
+
+	return m, nil
+}
+
+// View renders the main model.
+func (m MainModel) View() string {
+	if m.headless {
+		return ""
+	}
+
+	if m.quitting {
+		return m.styles.Muted.Margin(1, 0, 2, 4).Render("See you next time.")
+	}
+
+	if m.Error != nil {
+		return m.renderError(m.Error)
+	}
+
+	if m.sessionState == exportsLoading {
+		return m.renderLoading()
+	}
+
+	return m.renderExports()
+}
+
+func (m *MainModel) reqCtx() context.Context {
+	if m.ctx != nil {
+		return m.ctx
+	}
+	return context.Background()
+}
+
+func (m *MainModel) instanceIdentifier() string {
+	if m.instanceID != "" {
+		return m.instanceID
+	}
+	return m.instanceName
+}
+
+func (m *MainModel) instanceDisplayName() string {
+	if m.instanceName != "" {
+		return m.instanceName
+	}
+	return m.instanceID
+}
+
+// ExportsAppConfig holds configuration for creating a new exports application.
+type ExportsAppConfig struct {
+	// Context is bound to the engine calls the exports model makes so they are
+	// cancelled when the command context is cancelled (e.g. on Ctrl+C).
+	Context      context.Context
+	DeployEngine engine.DeployEngine
+	Logger       *zap.Logger
+	InstanceID   string
+	InstanceName string
+	// Field is the name of a single export to write the raw value of
+	// in headless mode, leave empty to write all exports.
+	Field string
+	// Format is the output format used in headless mode.
+	Format         OutputFormat
+	Styles         *stylespkg.Styles
+	Headless       bool
+	HeadlessWriter io.Writer
+}
+
+// NewExportsApp creates a new exports application with the given configuration.
+func NewExportsApp(cfg ExportsAppConfig) (*MainModel, error) {
+	if cfg.InstanceID == "" && cfg.InstanceName == "" {
+		return nil, fmt.Errorf("an instance ID or name must be provided")
+	}
+
+	format := cfg.Format
+	if format == "" {
+		format = OutputFormatText
+	}
+
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = cfg.Styles.Spinner
+
+	model := &MainModel{
+		sessionState:   exportsLoading,
+		instanceID:     cfg.InstanceID,
+		instanceName:   cfg.InstanceName,
+		field:          cfg.Field,
+		format:         format,
+		ctx:            cfg.Context,
+		headless:       cfg.Headless,
+		spinner:        s,
+		engine:         cfg.DeployEngine,
+		logger:         cfg.Logger,
+		styles:         cfg.Styles,
+		headlessWriter: cfg.HeadlessWriter,
+		printer:        createHeadlessPrinter(cfg.Headless, cfg.HeadlessWriter),
+	}
+
+	return model, nil
+}
+
+func createHeadlessPrinter(isHeadless bool, headlessWriter io.Writer) *headless.Printer {
+	if !isHeadless || headlessWriter == nil {
+		return nil
+	}
+	prefixedWriter := headless.NewPrefixedWriter(headlessWriter, "[exports] ")
+	return headless.NewPrinter(prefixedWriter, 80)
+}
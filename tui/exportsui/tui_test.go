@@ -0,0 +1,256 @@
+package exportsui
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
+	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
+	"github.com/newstack-cloud/deploy-cli-sdk/testutils"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+type ExportsTUISuite struct {
+	suite.Suite
+	styles *stylespkg.Styles
+}
+
+func TestExportsTUISuite(t *testing.T) {
+	suite.Run(t, new(ExportsTUISuite))
+}
+
+func (s *ExportsTUISuite) SetupTest() {
+	s.styles = stylespkg.NewStyles(
+		lipgloss.NewRenderer(os.Stdout),
+		stylespkg.NewBluelinkPalette(),
+	)
+}
+
+func (s *ExportsTUISuite) newTestModel(
+	deployEngine engine.DeployEngine,
+	isHeadless bool,
+	output *bytes.Buffer,
+	format OutputFormat,
+	field string,
+) MainModel {
+	model, err := NewExportsApp(ExportsAppConfig{
+		DeployEngine:   deployEngine,
+		Logger:         zap.NewNop(),
+		InstanceName:   "my-app",
+		Field:          field,
+		Format:         format,
+		Styles:         s.styles,
+		Headless:       isHeadless,
+		HeadlessWriter: output,
+	})
+	s.Require().NoError(err)
+	return *model
+}
+
+func (s *ExportsTUISuite) runHeadless(model MainModel) MainModel {
+	testModel := teatest.NewTestModel(
+		s.T(),
+		model,
+		teatest.WithInitialTermSize(300, 100),
+	)
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+	return testModel.FinalModel(s.T()).(MainModel)
+}
+
+// --- Interactive Mode Tests ---
+
+func (s *ExportsTUISuite) Test_displays_exports_table_after_load() {
+	model := s.newTestModel(
+		testExportsEngine(testExports()),
+		false,
+		nil,
+		OutputFormatText,
+		"",
+	)
+
+	testModel := teatest.NewTestModel(
+		s.T(),
+		model,
+		teatest.WithInitialTermSize(300, 100),
+	)
+
+	testutils.WaitForContainsAll(
+		s.T(),
+		testModel.Output(),
+		"Instance Exports",
+		"bucketArn",
+		"queueUrl",
+		"BUCKET_ARN",
+	)
+
+	testutils.KeyDown(testModel)
+	testutils.KeyDown(testModel)
+	testutils.KeyDown(testModel)
+
+	testutils.KeyQ(testModel)
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+
+	finalModel := testModel.FinalModel(s.T()).(MainModel)
+	s.Nil(finalModel.Error)
+	s.Equal(2, finalModel.cursor)
+}
+
+func (s *ExportsTUISuite) Test_displays_empty_state_when_instance_has_no_exports() {
+	model := s.newTestModel(
+		testExportsEngine(map[string]*state.ExportState{}),
+		false,
+		nil,
+		OutputFormatText,
+		"",
+	)
+
+	testModel := teatest.NewTestModel(
+		s.T(),
+		model,
+		teatest.WithInitialTermSize(300, 100),
+	)
+
+	testutils.WaitForContainsAll(s.T(), testModel.Output(), "No exports found")
+
+	testutils.KeyQ(testModel)
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+}
+
+// --- Headless Mode Tests ---
+
+func (s *ExportsTUISuite) Test_headless_text_output() {
+	output := &bytes.Buffer{}
+	finalModel := s.runHeadless(s.newTestModel(
+		testExportsEngine(testExports()),
+		true,
+		output,
+		OutputFormatText,
+		"",
+	))
+
+	s.Nil(finalModel.Error)
+	s.Contains(output.String(), "Instance Exports: my-app")
+	s.Contains(output.String(), "queueUrl")
+	s.Contains(output.String(), "resources.queue.spec.url")
+	s.Contains(output.String(), "The URL of the queue.")
+	s.Contains(output.String(), "Total: 3 exports")
+}
+
+func (s *ExportsTUISuite) Test_headless_json_output() {
+	output := &bytes.Buffer{}
+	finalModel := s.runHeadless(s.newTestModel(
+		testExportsEngine(testExports()),
+		true,
+		output,
+		OutputFormatJSON,
+		"",
+	))
+	s.Nil(finalModel.Error)
+
+	var result jsonout.InstanceExportsOutput
+	s.Require().NoError(json.Unmarshal(output.Bytes(), &result))
+	s.True(result.Success)
+	s.Equal("my-app", result.InstanceName)
+	s.Len(result.Exports, 3)
+	s.Equal("https://sqs.example.com/queue", *result.Exports["queueUrl"].Value.Scalar.StringValue)
+}
+
+func (s *ExportsTUISuite) Test_headless_dotenv_output() {
+	output := &bytes.Buffer{}
+	finalModel := s.runHeadless(s.newTestModel(
+		testExportsEngine(testExports()),
+		true,
+		output,
+		OutputFormatDotenv,
+		"",
+	))
+
+	s.Nil(finalModel.Error)
+	s.Equal(FormatDotenv(testExports()), output.String())
+}
+
+func (s *ExportsTUISuite) Test_headless_field_output_writes_raw_value() {
+	output := &bytes.Buffer{}
+	finalModel := s.runHeadless(s.newTestModel(
+		testExportsEngine(testExports()),
+		true,
+		output,
+		OutputFormatJSON,
+		"queueUrl",
+	))
+
+	s.Nil(finalModel.Error)
+	s.Equal("https://sqs.example.com/queue\n", output.String())
+}
+
+func (s *ExportsTUISuite) Test_headless_field_output_fails_for_missing_export() {
+	output := &bytes.Buffer{}
+	finalModel := s.runHeadless(s.newTestModel(
+		testExportsEngine(testExports()),
+		true,
+		output,
+		OutputFormatText,
+		"missing",
+	))
+
+	s.Require().Error(finalModel.Error)
+	s.Contains(finalModel.Error.Error(), `export "missing" not found`)
+	s.Empty(output.String())
+}
+
+func (s *ExportsTUISuite) Test_headless_json_error_output() {
+	output := &bytes.Buffer{}
+	finalModel := s.runHeadless(s.newTestModel(
+		testutils.NewScenarioBuilder().Build(),
+		true,
+		output,
+		OutputFormatJSON,
+		"",
+	))
+
+	s.Require().Error(finalModel.Error)
+
+	var result jsonout.ErrorOutput
+	s.Require().NoError(json.Unmarshal(output.Bytes(), &result))
+	s.False(result.Success)
+	s.Contains(result.Error.Message, `blueprint instance "my-app" not found`)
+}
+
+func (s *ExportsTUISuite) Test_headless_shell_error_keeps_output_clean() {
+	output := &bytes.Buffer{}
+	finalModel := s.runHeadless(s.newTestModel(
+		testutils.NewScenarioBuilder().Build(),
+		true,
+		output,
+		OutputFormatShell,
+		"",
+	))
+
+	s.Require().Error(finalModel.Error)
+	s.Empty(output.String())
+}
+
+func (s *ExportsTUISuite) Test_new_exports_app_requires_instance_identifier() {
+	_, err := NewExportsApp(ExportsAppConfig{
+		DeployEngine: testExportsEngine(nil),
+		Logger:       zap.NewNop(),
+		Styles:       s.styles,
+	})
+	s.Error(err)
+}
+
+// testExportsEngine creates a deploy engine that returns the provided
+// exports for the my-app instance.
+func testExportsEngine(exports map[string]*state.ExportState) engine.DeployEngine {
+	builder := testutils.NewScenarioBuilder()
+	builder.Instance("my-app").WithExports(exports)
+	return builder.Build()
+}
@@ -0,0 +1,155 @@
+package exportsui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/newstack-cloud/deploy-cli-sdk/headless"
+)
+
+const maxValueColumnWidth = 60
+
+func (m MainModel) renderLoading() string {
+	return fmt.Sprintf(
+		"\n  %s Loading exports for %s...\n",
+		m.spinner.View(),
+		m.instanceDisplayName(),
+	)
+}
+
+func (m MainModel) renderError(err error) string {
+	return m.styles.Error.Margin(2, 4).Render("Error: " + err.Error())
+}
+
+func (m MainModel) renderExports() string {
+	var sb strings.Builder
+
+	sb.WriteString("\n")
+	sb.WriteString(m.styles.Title.MarginLeft(2).Render("Instance Exports"))
+	sb.WriteString("\n")
+	sb.WriteString(m.styles.Muted.MarginLeft(2).Render(m.instanceDisplayName()))
+	sb.WriteString("\n\n")
+
+	if len(m.exportNames) == 0 {
+		sb.WriteString(m.styles.Muted.MarginLeft(4).Render("No exports found."))
+		sb.WriteString("\n\n")
+		sb.WriteString(m.renderFooter())
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	nameWidth, typeWidth := m.columnWidths()
+	sb.WriteString("  ")
+	sb.WriteString(m.styles.Header.Render(
+		padRight("NAME", nameWidth) + "  " + padRight("TYPE", typeWidth) + "  VALUE",
+	))
+	sb.WriteString("\n")
+
+	for i, name := range m.exportNames {
+		sb.WriteString(m.renderExportRow(i, name, nameWidth, typeWidth))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(m.renderSelectedExport())
+	sb.WriteString("\n")
+	sb.WriteString(m.renderFooter())
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+func (m MainModel) renderExportRow(index int, name string, nameWidth, typeWidth int) string {
+	export := m.exports[name]
+	exportType := ""
+	value := "null"
+	if export != nil {
+		exportType = string(export.Type)
+		value = truncate(headless.FormatMappingNode(export.Value), maxValueColumnWidth)
+	}
+
+	row := padRight(name, nameWidth) + "  " + padRight(exportType, typeWidth) + "  " + value
+	if index == m.cursor {
+		return m.styles.Selected.Render("> " + row)
+	}
+	return "  " + row
+}
+
+func (m MainModel) renderSelectedExport() string {
+	if m.cursor < 0 || m.cursor >= len(m.exportNames) {
+		return ""
+	}
+
+	name := m.exportNames[m.cursor]
+	export := m.exports[name]
+	if export == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(m.styles.Category.MarginLeft(2).Render(name))
+	sb.WriteString("\n")
+	if export.Description != "" {
+		sb.WriteString(m.styles.Muted.MarginLeft(4).Render("Description: "))
+		sb.WriteString(export.Description)
+		sb.WriteString("\n")
+	}
+	sb.WriteString(m.styles.Muted.MarginLeft(4).Render("Field: "))
+	sb.WriteString(export.Field)
+	sb.WriteString("\n")
+	sb.WriteString(m.styles.Muted.MarginLeft(4).Render("Environment variable: "))
+	sb.WriteString(EnvVarName(name))
+	sb.WriteString("\n")
+	sb.WriteString(m.styles.Muted.MarginLeft(4).Render("Value:"))
+	sb.WriteString("\n")
+
+	value := headless.FormatMappingNodeWithOptions(
+		export.Value,
+		headless.FormatMappingNodeOptions{PrettyPrint: true},
+	)
+	for _, line := range strings.Split(value, "\n") {
+		sb.WriteString("      ")
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+func (m MainModel) renderFooter() string {
+	var sb strings.Builder
+	sb.WriteString("  ")
+	sb.WriteString(m.styles.Key.Render("↑/↓"))
+	sb.WriteString(m.styles.Muted.Render(" navigate  "))
+	sb.WriteString(m.styles.Key.Render("q"))
+	sb.WriteString(m.styles.Muted.Render(" quit"))
+	return sb.String()
+}
+
+func (m MainModel) columnWidths() (int, int) {
+	nameWidth := len("NAME")
+	typeWidth := len("TYPE")
+	for _, name := range m.exportNames {
+		nameWidth = max(nameWidth, len(name))
+		if export := m.exports[name]; export != nil {
+			typeWidth = max(typeWidth, len(export.Type))
+		}
+	}
+	return nameWidth, typeWidth
+}
+
+func padRight(s string, length int) string {
+	if len(s) >= length {
+		return s
+	}
+	return s + strings.Repeat(" ", length-len(s))
+}
+
+func truncate(s string, maxLen int) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+	return string(runes[:maxLen-3]) + "..."
+}
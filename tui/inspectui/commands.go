@@ -20,11 +20,23 @@ func (m InspectEventMsg) AsFinish() (*container.DeploymentFinishedMessage, bool)
 }
 
 // InspectStreamClosedMsg is sent when the event stream is closed.
-type InspectStreamClosedMsg struct{}
+type InspectStreamClosedMsg struct {
+	// stream is the event channel that was closed, used to ignore
+	// channels that have been replaced by resuming the stream.
+	stream chan types.BlueprintInstanceEvent
+}
 
 // InspectStreamStartedMsg is sent when the event stream has been started.
 type InspectStreamStartedMsg struct{}
 
+// InspectStreamReconnectMsg is sent when it is time to resume the event
+// stream after the connection to the deploy engine was lost.
+type InspectStreamReconnectMsg struct{}
+
+// InspectStreamResumedMsg is sent when the event stream has been resumed
+// from the last received event.
+type InspectStreamResumedMsg struct{}
+
 // InspectErrorMsg is sent when an error occurs.
 type InspectErrorMsg struct {
 	Err error
@@ -103,7 +115,7 @@ func startStreamingCmd(model InspectModel) tea.Cmd {
 func waitForNextEventMsg(model InspectModel) tea.Msg {
 	event, ok := <-model.eventStream
 	if !ok {
-		return InspectStreamClosedMsg{}
+		return InspectStreamClosedMsg{stream: model.eventStream}
 	}
 	return InspectEventMsg(event)
 }
@@ -114,6 +126,42 @@ func waitForNextEventCmd(model InspectModel) tea.Cmd {
 	}
 }
 
+func checkForErrCmd(model InspectModel) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		select {
+		case <-time.After(1 * time.Second):
+			break
+		case newErr := <-model.errStream:
+			err = newErr
+		}
+		return InspectErrorMsg{Err: err}
+	}
+}
+
+func reconnectStreamCmd(wait time.Duration) tea.Cmd {
+	return tea.Tick(wait, func(time.Time) tea.Msg {
+		return InspectStreamReconnectMsg{}
+	})
+}
+
+func resumeStreamCmd(model InspectModel) tea.Cmd {
+	return func() tea.Msg {
+		err := model.engine.StreamBlueprintInstanceEvents(
+			model.reqCtx(),
+			model.streamResumer.StreamID(),
+			model.streamResumer.LastEventID(),
+			model.eventStream,
+			model.errStream,
+		)
+		if err != nil {
+			return InspectErrorMsg{Err: err}
+		}
+
+		return InspectStreamResumedMsg{}
+	}
+}
+
 func errInstanceNotFound(instanceID, instanceName string) error {
 	identifier := instanceID
 	if identifier == "" {
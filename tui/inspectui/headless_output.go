@@ -13,6 +13,10 @@ import (
 
 const fmtIndentedValue = "%s  %s\n"
 
+func (m *InspectModel) printHeadlessReconnectNotice(attempt shared.StreamReconnectAttempt) {
+	m.printer.Writer().Printf("⚠ %s\n", attempt.Notice())
+}
+
 func (m *InspectModel) printHeadlessInstanceState() {
 	if m.printer == nil {
 		return
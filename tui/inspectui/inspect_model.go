@@ -34,6 +34,9 @@ type InspectModelConfig struct {
 	IsHeadless     bool
 	HeadlessWriter io.Writer
 	JSONMode       bool
+	// StreamReconnect determines how the event stream of an in-progress
+	// deployment is resumed when the connection to the deploy engine drops.
+	StreamReconnect shared.StreamReconnectPolicy
 }
 
 // InspectModel is the model for the inspect view.
@@ -78,6 +81,9 @@ type InspectModel struct {
 	engine      engine.DeployEngine
 	eventStream chan types.BlueprintInstanceEvent
 	errStream   chan error
+	// streamResumer tracks the last received event so the stream
+	// can be resumed when the connection to the engine drops.
+	streamResumer shared.StreamResumer
 
 	// Output modes
 	headlessMode   bool
@@ -118,11 +124,18 @@ func (m InspectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case InstanceNotFoundMsg:
 		return m.handleInstanceNotFound(msg)
 	case InspectStreamStartedMsg:
-		return m, waitForNextEventCmd(m)
+		cmd := m.streamStarted()
+		return m, cmd
 	case InspectEventMsg:
 		return m.handleInspectEvent(msg)
 	case InspectStreamClosedMsg:
-		return m.handleStreamClosed()
+		return m.handleStreamClosed(msg)
+	case InspectStreamReconnectMsg:
+		cmd := m.reconnectStream()
+		return m, cmd
+	case InspectStreamResumedMsg:
+		cmd := m.streamResumed()
+		return m, cmd
 	case InspectErrorMsg:
 		return m.handleInspectError(msg)
 	}
@@ -170,6 +183,7 @@ func (m InspectModel) handleInstanceNotFound(msg InstanceNotFoundMsg) (tea.Model
 }
 
 func (m InspectModel) handleInspectEvent(msg InspectEventMsg) (tea.Model, tea.Cmd) {
+	m.streamResumer.RecordEvent(msg.ID)
 	m.processEvent(&msg)
 	m.splitPane.UpdateItems(ToSplitPaneItems(m.items))
 
@@ -177,6 +191,7 @@ func (m InspectModel) handleInspectEvent(msg InspectEventMsg) (tea.Model, tea.Cm
 	if isFinish && finishData.EndOfStream {
 		m.finished = true
 		m.streaming = false
+		m.streamResumer.Stop()
 		m.footerRenderer.Streaming = false
 		m.footerRenderer.Finished = true
 		m.footerRenderer.CurrentStatus = finishData.Status
@@ -194,34 +209,56 @@ func (m InspectModel) handleInspectEvent(msg InspectEventMsg) (tea.Model, tea.Cm
 		return m, nil
 	}
 
-	return m, waitForNextEventCmd(m)
+	return m, tea.Batch(waitForNextEventCmd(m), checkForErrCmd(m))
 }
 
-func (m InspectModel) handleStreamClosed() (tea.Model, tea.Cmd) {
+func (m InspectModel) handleStreamClosed(msg InspectStreamClosedMsg) (tea.Model, tea.Cmd) {
+	if msg.stream != nil && msg.stream != m.eventStream {
+		// The closed stream was replaced when the stream was resumed.
+		return m, nil
+	}
+
+	if m.finished {
+		m.streaming = false
+		m.footerRenderer.Streaming = false
+		return m, nil
+	}
+
+	cmd, err := m.resumeStream(errStreamClosedUnexpectedly)
+	if err == nil {
+		return m, cmd
+	}
+
 	m.streaming = false
 	m.footerRenderer.Streaming = false
-	if !m.finished {
-		m.err = errStreamClosedUnexpectedly
-		if m.headlessMode {
-			if m.jsonMode {
-				m.outputJSONError(m.err)
-			} else {
-				m.printHeadlessError(m.err)
-			}
-			return m, tea.Quit
+	m.err = err
+	if m.headlessMode {
+		if m.jsonMode {
+			m.outputJSONError(m.err)
+		} else {
+			m.printHeadlessError(m.err)
 		}
+		return m, tea.Quit
 	}
 	return m, nil
 }
 
 func (m InspectModel) handleInspectError(msg InspectErrorMsg) (tea.Model, tea.Cmd) {
 	if msg.Err != nil {
-		m.err = msg.Err
+		err := msg.Err
+		if shared.IsStreamConnectionError(err) {
+			var cmd tea.Cmd
+			if cmd, err = m.resumeStream(err); err == nil {
+				return m, cmd
+			}
+		}
+
+		m.err = err
 		if m.headlessMode {
 			if m.jsonMode {
-				m.outputJSONError(msg.Err)
+				m.outputJSONError(err)
 			} else {
-				m.printHeadlessError(msg.Err)
+				m.printHeadlessError(err)
 			}
 			return m, tea.Quit
 		}
@@ -229,6 +266,60 @@ func (m InspectModel) handleInspectError(msg InspectErrorMsg) (tea.Model, tea.Cm
 	return m, nil
 }
 
+// streamStarted begins tracking the event stream of the in-progress
+// deployment so that it can be resumed if the connection drops.
+// Events are streamed from the beginning so all events for the
+// deployment are shown.
+func (m *InspectModel) streamStarted() tea.Cmd {
+	m.streamResumer.Start(m.instanceID, "")
+	m.footerRenderer.Reconnecting = nil
+	return tea.Batch(waitForNextEventCmd(*m), checkForErrCmd(*m))
+}
+
+// resumeStream schedules an attempt to resume the event stream after the
+// connection to the engine was lost, the provided cause is returned when
+// the stream can not be resumed, wrapped when the reconnection budget
+// has been used up.
+func (m *InspectModel) resumeStream(cause error) (tea.Cmd, error) {
+	if !m.streamResumer.CanResume() {
+		return nil, cause
+	}
+
+	if m.streamResumer.Reconnecting() {
+		// An attempt to resume the lost stream has already been scheduled.
+		return nil, nil
+	}
+
+	attempt, ok := m.streamResumer.NextAttempt()
+	if !ok {
+		return nil, m.streamResumer.GaveUpError(cause)
+	}
+
+	m.footerRenderer.Reconnecting = &attempt
+	if m.headlessMode && !m.jsonMode {
+		m.printHeadlessReconnectNotice(attempt)
+	}
+	return reconnectStreamCmd(attempt.Wait), nil
+}
+
+func (m *InspectModel) reconnectStream() tea.Cmd {
+	if m.finished || !m.streamResumer.CanResume() {
+		return nil
+	}
+
+	// Events and errors are received on new channels so that
+	// the lost stream does not interfere with the resumed stream.
+	m.eventStream = make(chan types.BlueprintInstanceEvent)
+	m.errStream = make(chan error)
+	return resumeStreamCmd(*m)
+}
+
+func (m *InspectModel) streamResumed() tea.Cmd {
+	m.streamResumer.Resumed()
+	m.footerRenderer.Reconnecting = nil
+	return tea.Batch(waitForNextEventCmd(*m), checkForErrCmd(*m))
+}
+
 func (m InspectModel) handleWindowSize(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
 	m.width = msg.Width
 	m.height = msg.Height
@@ -565,6 +656,7 @@ func NewInspectModel(cfg InspectModelConfig) *InspectModel {
 		spinner:                 createInspectSpinner(cfg.Styles),
 		eventStream:             make(chan types.BlueprintInstanceEvent),
 		errStream:               make(chan error),
+		streamResumer:           shared.NewStreamResumer(cfg.StreamReconnect),
 		resourcesByName:         resourcesByName,
 		childrenByName:          childrenByName,
 		linksByName:             linksByName,
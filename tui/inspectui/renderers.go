@@ -248,6 +248,8 @@ type InspectFooterRenderer struct {
 	SpinnerView      string
 	HasInstanceState bool
 	EmbeddedInList   bool // When true, shows "esc back to list" instead of "q quit"
+	// Reconnecting is set while the event stream is being resumed.
+	Reconnecting *shared.StreamReconnectAttempt
 }
 
 var _ splitpane.FooterRenderer = (*InspectFooterRenderer)(nil)
@@ -288,6 +290,8 @@ func (r *InspectFooterRenderer) RenderFooter(model *splitpane.Model, s *styles.S
 		sb.WriteString(s.Key.Render("e"))
 		sb.WriteString(s.Muted.Render(" for exports"))
 		sb.WriteString("\n")
+	} else if r.Streaming && r.Reconnecting != nil {
+		shared.RenderReconnectingNotice(&sb, *r.Reconnecting, s)
 	} else if r.Streaming {
 		sb.WriteString(s.Muted.Render("  streaming deployment events..."))
 		sb.WriteString("\n")
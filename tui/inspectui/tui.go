@@ -11,6 +11,7 @@ import (
 	"github.com/charmbracelet/huh"
 	"github.com/newstack-cloud/deploy-cli-sdk/engine"
 	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/shared"
 	"go.uber.org/zap"
 )
 
@@ -83,7 +84,11 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case InspectEventMsg:
 		return m.handleInspectEventMsg(msg)
 	case InspectStreamClosedMsg:
-		return m.handleInspectStreamClosedMsg()
+		return m.handleInspectStreamClosedMsg(msg)
+	case InspectStreamReconnectMsg:
+		return m.handleInspectStreamReconnectMsg()
+	case InspectStreamResumedMsg:
+		return m.handleInspectStreamResumedMsg()
 	case InspectErrorMsg:
 		return m.handleInspectErrorMsg(msg)
 	case spinner.TickMsg:
@@ -145,6 +150,9 @@ type InspectAppConfig struct {
 	Headless       bool
 	HeadlessWriter io.Writer
 	JSONMode       bool
+	// StreamReconnect determines how event streams are resumed
+	// when the connection to the deploy engine drops.
+	StreamReconnect shared.StreamReconnectPolicy
 }
 
 // NewInspectApp creates a new inspect application with the given configuration.
@@ -166,15 +174,16 @@ func NewInspectApp(cfg InspectAppConfig) (*MainModel, error) {
 
 	// Create the inspect model
 	inspect := NewInspectModel(InspectModelConfig{
-		Context:        cfg.Context,
-		DeployEngine:   cfg.DeployEngine,
-		Logger:         cfg.Logger,
-		InstanceID:     cfg.InstanceID,
-		InstanceName:   cfg.InstanceName,
-		Styles:         cfg.Styles,
-		IsHeadless:     cfg.Headless,
-		HeadlessWriter: cfg.HeadlessWriter,
-		JSONMode:       cfg.JSONMode,
+		Context:         cfg.Context,
+		DeployEngine:    cfg.DeployEngine,
+		Logger:          cfg.Logger,
+		InstanceID:      cfg.InstanceID,
+		InstanceName:    cfg.InstanceName,
+		Styles:          cfg.Styles,
+		IsHeadless:      cfg.Headless,
+		HeadlessWriter:  cfg.HeadlessWriter,
+		JSONMode:        cfg.JSONMode,
+		StreamReconnect: cfg.StreamReconnect,
 	})
 
 	model := &MainModel{
@@ -1,10 +1,9 @@
 package inspectui
 
 import (
-	"errors"
-
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/shared"
 )
 
 func (m MainModel) handleInstanceInputMsg(msg InstanceInputMsg) (tea.Model, tea.Cmd) {
@@ -69,7 +68,15 @@ func (m MainModel) handleInstanceNotFoundMsg(msg InstanceNotFoundMsg) (tea.Model
 }
 
 func (m MainModel) handleInspectStreamStartedMsg() (tea.Model, tea.Cmd) {
-	return m, waitForNextEventCmd(*m.inspect)
+	return m, m.inspect.streamStarted()
+}
+
+func (m MainModel) handleInspectStreamReconnectMsg() (tea.Model, tea.Cmd) {
+	return m, m.inspect.reconnectStream()
+}
+
+func (m MainModel) handleInspectStreamResumedMsg() (tea.Model, tea.Cmd) {
+	return m, m.inspect.streamResumed()
 }
 
 func (m MainModel) handleStateRefreshTickMsg() (tea.Model, tea.Cmd) {
@@ -94,6 +101,7 @@ func (m MainModel) handleInstanceStateRefreshedMsg(msg InstanceStateRefreshedMsg
 
 func (m MainModel) handleInspectEventMsg(msg InspectEventMsg) (tea.Model, tea.Cmd) {
 	cmds := []tea.Cmd{}
+	m.inspect.streamResumer.RecordEvent(msg.ID)
 	m.inspect.processEvent(&msg)
 	m.inspect.splitPane.UpdateItems(ToSplitPaneItems(m.inspect.items))
 
@@ -101,6 +109,7 @@ func (m MainModel) handleInspectEventMsg(msg InspectEventMsg) (tea.Model, tea.Cm
 	if isFinish && finishData.EndOfStream {
 		m.inspect.finished = true
 		m.inspect.streaming = false
+		m.inspect.streamResumer.Stop()
 		m.inspect.footerRenderer.Streaming = false
 		m.inspect.footerRenderer.Finished = true
 		m.inspect.footerRenderer.CurrentStatus = finishData.Status
@@ -121,37 +130,59 @@ func (m MainModel) handleInspectEventMsg(msg InspectEventMsg) (tea.Model, tea.Cm
 		return m, tea.Batch(cmds...)
 	}
 
-	cmds = append(cmds, waitForNextEventCmd(*m.inspect))
+	cmds = append(cmds, waitForNextEventCmd(*m.inspect), checkForErrCmd(*m.inspect))
 	return m, tea.Batch(cmds...)
 }
 
-func (m MainModel) handleInspectStreamClosedMsg() (tea.Model, tea.Cmd) {
+func (m MainModel) handleInspectStreamClosedMsg(msg InspectStreamClosedMsg) (tea.Model, tea.Cmd) {
+	if msg.stream != nil && msg.stream != m.inspect.eventStream {
+		// The closed stream was replaced when the stream was resumed.
+		return m, nil
+	}
+
+	if m.inspect.finished {
+		m.inspect.streaming = false
+		m.inspect.footerRenderer.Streaming = false
+		return m, nil
+	}
+
+	cmd, err := m.inspect.resumeStream(errStreamClosedUnexpectedly)
+	if err == nil {
+		return m, cmd
+	}
+
 	m.inspect.streaming = false
 	m.inspect.footerRenderer.Streaming = false
-	if !m.inspect.finished {
-		m.inspect.err = errors.New("event stream closed unexpectedly")
-		m.Error = m.inspect.err
-		if m.headless {
-			if m.jsonMode {
-				m.inspect.outputJSONError(m.inspect.err)
-			} else {
-				m.inspect.printHeadlessError(m.inspect.err)
-			}
-			return m, tea.Quit
+	m.inspect.err = err
+	m.Error = err
+	if m.headless {
+		if m.jsonMode {
+			m.inspect.outputJSONError(err)
+		} else {
+			m.inspect.printHeadlessError(err)
 		}
+		return m, tea.Quit
 	}
 	return m, nil
 }
 
 func (m MainModel) handleInspectErrorMsg(msg InspectErrorMsg) (tea.Model, tea.Cmd) {
 	if msg.Err != nil {
-		m.inspect.err = msg.Err
-		m.Error = msg.Err
+		err := msg.Err
+		if shared.IsStreamConnectionError(err) {
+			var cmd tea.Cmd
+			if cmd, err = m.inspect.resumeStream(err); err == nil {
+				return m, cmd
+			}
+		}
+
+		m.inspect.err = err
+		m.Error = err
 		if m.headless {
 			if m.jsonMode {
-				m.inspect.outputJSONError(msg.Err)
+				m.inspect.outputJSONError(err)
 			} else {
-				m.inspect.printHeadlessError(msg.Err)
+				m.inspect.printHeadlessError(err)
 			}
 			return m, tea.Quit
 		}
@@ -24,6 +24,13 @@ type InstalledMsg struct {
 	InstalledCount      int
 }
 
+// MissingMsg indicates dependencies are missing and were not installed
+// because the preflight check was created to only check dependencies
+// (e.g. for the doctor command, which must not change the system).
+type MissingMsg struct {
+	MissingPlugins []string
+}
+
 // ErrorMsg indicates the preflight check failed.
 type ErrorMsg struct {
 	Err error
@@ -0,0 +1,105 @@
+package reconcileui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/container"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/shared"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/stateutil"
+)
+
+// CheckCompleteMsg is sent when the reconciliation check for an instance
+// has completed, along with the current state of the instance that is used
+// to display the details of the elements that need reconciliation.
+type CheckCompleteMsg struct {
+	Result        *container.ReconciliationCheckResult
+	InstanceState *state.InstanceState
+}
+
+// CheckErrorMsg is sent when the reconciliation check for an instance fails.
+type CheckErrorMsg struct {
+	Err error
+}
+
+// ApplyCompleteMsg is sent when reconciliation actions have been applied.
+type ApplyCompleteMsg struct {
+	Result *container.ApplyReconciliationResult
+}
+
+// ApplyErrorMsg is sent when applying reconciliation actions fails.
+type ApplyErrorMsg struct {
+	Err error
+}
+
+func checkReconciliationCmd(model MainModel) tea.Cmd {
+	return func() tea.Msg {
+		docInfo, err := model.documentInfo()
+		if err != nil {
+			return CheckErrorMsg{Err: err}
+		}
+
+		result, err := engine.CheckReconciliation(
+			model.reqCtx(),
+			model.engine,
+			model.instanceIdentifier(),
+			&types.CheckReconciliationPayload{
+				BlueprintDocumentInfo: docInfo,
+				Scope:                 string(container.ReconciliationScopeAll),
+				Config:                model.operationConfig,
+			},
+		)
+		if err != nil {
+			return CheckErrorMsg{Err: err}
+		}
+
+		instanceState := stateutil.FetchInstanceState(
+			model.reqCtx(),
+			model.engine,
+			model.instanceID,
+			model.instanceName,
+		)
+
+		return CheckCompleteMsg{
+			Result:        result,
+			InstanceState: instanceState,
+		}
+	}
+}
+
+func applyReconciliationCmd(model MainModel) tea.Cmd {
+	return func() tea.Msg {
+		docInfo, err := model.documentInfo()
+		if err != nil {
+			return ApplyErrorMsg{Err: err}
+		}
+
+		payload := BuildApplyPayload(
+			model.checkResult,
+			model.policy,
+			docInfo,
+			model.operationConfig,
+		)
+		result, err := model.engine.ApplyReconciliation(
+			model.reqCtx(),
+			model.instanceIdentifier(),
+			payload,
+		)
+		if err != nil {
+			return ApplyErrorMsg{Err: err}
+		}
+
+		return ApplyCompleteMsg{Result: result}
+	}
+}
+
+func (m *MainModel) documentInfo() (types.BlueprintDocumentInfo, error) {
+	if m.blueprintFile == "" {
+		return types.BlueprintDocumentInfo{}, nil
+	}
+	return shared.BuildDocumentInfo(
+		shared.BlueprintSourceFromPath(m.blueprintFile),
+		m.blueprintFile,
+	)
+}
@@ -0,0 +1,147 @@
+package reconcileui
+
+import (
+	"github.com/newstack-cloud/bluelink/libs/blueprint/container"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/driftui"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/shared"
+)
+
+// Decision is the choice made by a policy for a single element
+// that needs reconciliation.
+type Decision struct {
+	ElementType  driftui.DriftItemType
+	Name         string
+	ChildPath    string
+	ResourceType string
+	DriftType    container.ReconciliationType
+	Choice       Choice
+	// Action is the reconciliation action that will be applied,
+	// this is empty when the persisted state is kept.
+	Action container.ReconciliationAction
+}
+
+// BuildDecisions determines the choice for each resource and link
+// in the reconciliation check result using the provided policy.
+func BuildDecisions(result *container.ReconciliationCheckResult, policy *Policy) []Decision {
+	if result == nil {
+		return nil
+	}
+
+	decisions := make([]Decision, 0, len(result.Resources)+len(result.Links))
+	for i := range result.Resources {
+		r := &result.Resources[i]
+		choice := policy.ChoiceForResource(r)
+		decisions = append(decisions, Decision{
+			ElementType:  driftui.DriftItemTypeResource,
+			Name:         r.ResourceName,
+			ChildPath:    r.ChildPath,
+			ResourceType: r.ResourceType,
+			DriftType:    r.Type,
+			Choice:       choice,
+			Action:       actionForChoice(choice, r.RecommendedAction),
+		})
+	}
+
+	for i := range result.Links {
+		l := &result.Links[i]
+		choice := policy.ChoiceForLink(l)
+		decisions = append(decisions, Decision{
+			ElementType: driftui.DriftItemTypeLink,
+			Name:        l.LinkName,
+			ChildPath:   l.ChildPath,
+			DriftType:   l.Type,
+			Choice:      choice,
+			Action:      actionForChoice(choice, l.RecommendedAction),
+		})
+	}
+
+	return decisions
+}
+
+// CountChoices returns the number of decisions that accept the external
+// state and the number of decisions that keep the persisted state.
+func CountChoices(decisions []Decision) (accepted, kept int) {
+	for _, decision := range decisions {
+		if decision.Choice == ChoiceAcceptExternal {
+			accepted += 1
+		} else {
+			kept += 1
+		}
+	}
+	return accepted, kept
+}
+
+// BuildApplyPayload creates the payload to apply reconciliation for the elements
+// in the check result that the policy accepts the external state for.
+// Elements for which the persisted state is kept are left out of the payload.
+func BuildApplyPayload(
+	result *container.ReconciliationCheckResult,
+	policy *Policy,
+	docInfo types.BlueprintDocumentInfo,
+	config *types.BlueprintOperationConfig,
+) *types.ApplyReconciliationPayload {
+	resources := []container.ResourceReconcileResult{}
+	for i := range result.Resources {
+		if policy.ChoiceForResource(&result.Resources[i]) == ChoiceAcceptExternal {
+			resources = append(resources, result.Resources[i])
+		}
+	}
+
+	links := []container.LinkReconcileResult{}
+	for i := range result.Links {
+		if policy.ChoiceForLink(&result.Links[i]) == ChoiceAcceptExternal {
+			links = append(links, result.Links[i])
+		}
+	}
+
+	return &types.ApplyReconciliationPayload{
+		BlueprintDocumentInfo: docInfo,
+		ResourceActions:       shared.BuildResourceActions(resources),
+		LinkActions:           shared.BuildLinkActions(links),
+		Config:                config,
+	}
+}
+
+func actionForChoice(
+	choice Choice,
+	recommended container.ReconciliationAction,
+) container.ReconciliationAction {
+	if choice != ChoiceAcceptExternal {
+		return ""
+	}
+	return recommended
+}
+
+func toJSONDecisions(decisions []Decision) []jsonout.ReconcileDecision {
+	jsonDecisions := make([]jsonout.ReconcileDecision, 0, len(decisions))
+	for _, decision := range decisions {
+		jsonDecisions = append(jsonDecisions, jsonout.ReconcileDecision{
+			ElementType:  string(decision.ElementType),
+			Name:         decision.Name,
+			ChildPath:    decision.ChildPath,
+			ResourceType: decision.ResourceType,
+			DriftType:    string(decision.DriftType),
+			Choice:       string(decision.Choice),
+			Action:       string(decision.Action),
+		})
+	}
+	return jsonDecisions
+}
+
+func toJSONErrors(errs []container.ReconciliationError) []jsonout.ReconcileError {
+	if len(errs) == 0 {
+		return nil
+	}
+	jsonErrors := make([]jsonout.ReconcileError, 0, len(errs))
+	for _, err := range errs {
+		jsonErrors = append(jsonErrors, jsonout.ReconcileError{
+			ElementID:   err.ElementID,
+			ElementName: err.ElementName,
+			ElementType: err.ElementType,
+			Error:       err.Error,
+		})
+	}
+	return jsonErrors
+}
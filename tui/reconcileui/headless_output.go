@@ -0,0 +1,178 @@
+package reconcileui
+
+import (
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
+	sdkstrings "github.com/newstack-cloud/deploy-cli-sdk/strings"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/driftui"
+)
+
+// dispatchHeadlessCheckOutput writes the result of a reconciliation check
+// when there is nothing to reconcile or the model is only checking for drift.
+func (m *MainModel) dispatchHeadlessCheckOutput() {
+	if m.jsonMode {
+		if m.mode == ModeReconcile {
+			m.outputJSONReconcile()
+			return
+		}
+		m.outputJSONCheck()
+		return
+	}
+
+	if !m.DriftDetected() {
+		m.printHeadlessNoDrift()
+		return
+	}
+	m.printHeadlessDrift()
+}
+
+func (m *MainModel) dispatchHeadlessReconcileOutput() {
+	if m.jsonMode {
+		m.outputJSONReconcile()
+		return
+	}
+	m.printHeadlessReconcileResult()
+}
+
+func (m *MainModel) dispatchHeadlessError(err error) {
+	if m.jsonMode {
+		m.outputJSONError(err)
+		return
+	}
+	m.printHeadlessError(err)
+}
+
+func (m *MainModel) outputJSONCheck() {
+	if m.headlessWriter == nil {
+		return
+	}
+	output := jsonout.DriftCheckOutput{
+		Success:        true,
+		DriftDetected:  m.DriftDetected(),
+		InstanceID:     m.resultInstanceID(),
+		InstanceName:   m.instanceName,
+		Reconciliation: m.checkResult,
+	}
+	jsonout.WriteJSON(m.headlessWriter, output)
+}
+
+func (m *MainModel) outputJSONReconcile() {
+	if m.headlessWriter == nil {
+		return
+	}
+	output := jsonout.DriftReconcileOutput{
+		Success:        m.Error == nil,
+		DriftDetected:  m.DriftDetected(),
+		InstanceID:     m.resultInstanceID(),
+		InstanceName:   m.instanceName,
+		Reconciliation: m.checkResult,
+		Decisions:      toJSONDecisions(m.decisions),
+	}
+	if m.applyResult != nil {
+		output.ResourcesUpdated = m.applyResult.ResourcesUpdated
+		output.LinksUpdated = m.applyResult.LinksUpdated
+		output.Errors = toJSONErrors(m.applyResult.Errors)
+	}
+	jsonout.WriteJSON(m.headlessWriter, output)
+}
+
+func (m *MainModel) outputJSONError(err error) {
+	if m.headlessWriter == nil {
+		return
+	}
+	jsonout.WriteJSON(m.headlessWriter, jsonout.NewErrorOutput(err))
+}
+
+func (m *MainModel) printHeadlessNoDrift() {
+	if m.printer == nil {
+		return
+	}
+	w := m.printer.Writer()
+	w.PrintlnEmpty()
+	w.Printf("✓ No drift detected for %s\n", m.instanceDisplayName())
+	w.PrintlnEmpty()
+}
+
+func (m *MainModel) printHeadlessDrift() {
+	if m.printer == nil {
+		return
+	}
+	driftContext := driftui.DriftContextCheck
+	if m.mode == ModeReconcile {
+		driftContext = driftui.DriftContextReconcile
+	}
+	driftui.NewHeadlessDriftPrinter(m.printer, driftContext).PrintDriftDetected(m.checkResult)
+}
+
+func (m *MainModel) printHeadlessDecisions() {
+	if m.printer == nil {
+		return
+	}
+	w := m.printer.Writer()
+	w.Println("Reconciliation plan:")
+	for _, decision := range m.decisions {
+		name := decision.Name
+		if decision.ChildPath != "" {
+			name = decision.ChildPath + "." + name
+		}
+		if decision.Choice == ChoiceAcceptExternal {
+			w.Printf("  ✓ %s %s: %s (%s)\n", decision.ElementType, name, decision.Choice, decision.Action)
+		} else {
+			w.Printf("  - %s %s: %s\n", decision.ElementType, name, decision.Choice)
+		}
+	}
+	w.PrintlnEmpty()
+}
+
+func (m *MainModel) printHeadlessReconcileResult() {
+	if m.printer == nil || m.applyResult == nil {
+		return
+	}
+	w := m.printer.Writer()
+	w.DoubleSeparator(72)
+
+	if len(m.applyResult.Errors) > 0 {
+		w.Println("ERR Reconciliation failed")
+		w.PrintlnEmpty()
+		for _, reconcileErr := range m.applyResult.Errors {
+			w.Printf("  %s %s: %s\n", reconcileErr.ElementType, reconcileErr.ElementName, reconcileErr.Error)
+		}
+		w.PrintlnEmpty()
+	} else {
+		w.Println("✓ Reconciliation complete")
+	}
+
+	resources := m.applyResult.ResourcesUpdated
+	links := m.applyResult.LinksUpdated
+	w.Printf(
+		"  %d %s and %d %s updated\n",
+		resources,
+		sdkstrings.Pluralize(resources, "resource", "resources"),
+		links,
+		sdkstrings.Pluralize(links, "link", "links"),
+	)
+
+	_, kept := CountChoices(m.decisions)
+	if kept > 0 {
+		w.Printf(
+			"  %d %s kept the persisted state and will be reported by the next drift check\n",
+			kept,
+			sdkstrings.Pluralize(kept, "element", "elements"),
+		)
+	}
+	w.PrintlnEmpty()
+}
+
+func (m *MainModel) printHeadlessError(err error) {
+	if m.printer == nil {
+		return
+	}
+	w := m.printer.Writer()
+	w.PrintlnEmpty()
+	if m.mode == ModeReconcile {
+		w.Println("ERR Drift reconciliation failed")
+	} else {
+		w.Println("ERR Drift check failed")
+	}
+	w.PrintlnEmpty()
+	w.Printf("  Error: %s\n", err.Error())
+}
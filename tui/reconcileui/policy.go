@@ -0,0 +1,224 @@
+package reconcileui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/container"
+	"gopkg.in/yaml.v3"
+)
+
+// Choice is the decision made for an element that needs reconciliation.
+type Choice string
+
+const (
+	// ChoiceAcceptExternal accepts the external state of the element as the
+	// source of truth, applying the recommended reconciliation action
+	// to update the persisted state.
+	ChoiceAcceptExternal Choice = "accept-external"
+	// ChoiceKeepState leaves the persisted state of the element as it is,
+	// the element will be reported again by the next drift check.
+	ChoiceKeepState Choice = "keep-state"
+)
+
+// ParseChoice parses a reconciliation choice from a flag or policy file value.
+func ParseChoice(value string) (Choice, error) {
+	switch Choice(strings.ToLower(strings.TrimSpace(value))) {
+	case ChoiceAcceptExternal:
+		return ChoiceAcceptExternal, nil
+	case ChoiceKeepState:
+		return ChoiceKeepState, nil
+	default:
+		return "", fmt.Errorf(
+			"invalid reconciliation action %q, expected one of: %s, %s",
+			value,
+			ChoiceAcceptExternal,
+			ChoiceKeepState,
+		)
+	}
+}
+
+// Rule determines the choice for the resources and links it matches.
+// Name and ResourceType are glob patterns (as supported by path.Match),
+// when both are set an element must match both of them.
+//
+// Names are matched against the logical name of a resource or link
+// and against the name qualified by the child blueprint path
+// (e.g. "childA.ordersTable") for elements in child blueprints.
+// Resource types only match resources.
+type Rule struct {
+	Name         string `json:"name,omitempty" yaml:"name,omitempty"`
+	ResourceType string `json:"resourceType,omitempty" yaml:"resourceType,omitempty"`
+	Action       Choice `json:"action" yaml:"action"`
+}
+
+// Policy determines the choices made when reconciling the elements
+// of a reconciliation check result.
+// Rules are evaluated in order and the first matching rule wins,
+// elements that do not match any rule use the default choice.
+type Policy struct {
+	Default Choice `json:"default,omitempty" yaml:"default,omitempty"`
+	Rules   []Rule `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// LoadPolicyFile loads a reconciliation policy from a YAML or JSON file.
+func LoadPolicyFile(policyFile string) (*Policy, error) {
+	data, err := os.ReadFile(policyFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading reconciliation policy file %s: %w", policyFile, err)
+	}
+
+	policy := &Policy{}
+	switch strings.ToLower(filepath.Ext(policyFile)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, policy)
+	case ".json":
+		err = json.Unmarshal(data, policy)
+	default:
+		return nil, fmt.Errorf(
+			"unsupported reconciliation policy file format %q, expected .yaml, .yml or .json",
+			filepath.Ext(policyFile),
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing reconciliation policy file %s: %w", policyFile, err)
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid reconciliation policy file %s: %w", policyFile, err)
+	}
+
+	return policy, nil
+}
+
+// Validate makes sure the default choice and the choices and patterns
+// of all the rules in the policy are valid.
+func (p *Policy) Validate() error {
+	if p.Default != "" {
+		if _, err := ParseChoice(string(p.Default)); err != nil {
+			return err
+		}
+	}
+
+	for i, rule := range p.Rules {
+		if rule.Name == "" && rule.ResourceType == "" {
+			return fmt.Errorf("rule %d must have a name or resourceType", i)
+		}
+		if _, err := ParseChoice(string(rule.Action)); err != nil {
+			return fmt.Errorf("rule %d: %w", i, err)
+		}
+		if err := validatePattern(rule.Name); err != nil {
+			return fmt.Errorf("rule %d: %w", i, err)
+		}
+		if err := validatePattern(rule.ResourceType); err != nil {
+			return fmt.Errorf("rule %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// ParseSelectors parses a comma-separated list of selectors into rules
+// with the given choice.
+// A selector is either a name pattern (e.g. "ordersTable", "childA.*")
+// or a resource type pattern prefixed with "type:" (e.g. "type:aws/dynamodb/*").
+func ParseSelectors(selectors string, choice Choice) ([]Rule, error) {
+	rules := []Rule{}
+	for _, selector := range strings.Split(selectors, ",") {
+		selector = strings.TrimSpace(selector)
+		if selector == "" {
+			continue
+		}
+
+		rule := Rule{Action: choice}
+		if resourceType, isType := strings.CutPrefix(selector, "type:"); isType {
+			rule.ResourceType = resourceType
+		} else {
+			rule.Name = selector
+		}
+
+		if rule.Name == "" && rule.ResourceType == "" {
+			return nil, fmt.Errorf("invalid selector %q, a resource type must follow \"type:\"", selector)
+		}
+		if err := validatePattern(rule.Name + rule.ResourceType); err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// WithOverrides returns a copy of the policy with the provided rules
+// evaluated before the rules of the policy and the default choice replaced
+// when a non-empty default is provided.
+// This is used to give choices expressed with flags precedence over
+// the choices in a policy file.
+func (p *Policy) WithOverrides(rules []Rule, defaultChoice Choice) *Policy {
+	merged := &Policy{Default: p.Default}
+	if defaultChoice != "" {
+		merged.Default = defaultChoice
+	}
+	merged.Rules = append(merged.Rules, rules...)
+	merged.Rules = append(merged.Rules, p.Rules...)
+	return merged
+}
+
+// ChoiceForResource returns the choice for a resource that needs reconciliation.
+func (p *Policy) ChoiceForResource(result *container.ResourceReconcileResult) Choice {
+	for _, rule := range p.Rules {
+		if rule.matches(result.ResourceName, result.ChildPath, result.ResourceType) {
+			return rule.Action
+		}
+	}
+	return p.defaultChoice()
+}
+
+// ChoiceForLink returns the choice for a link that needs reconciliation.
+func (p *Policy) ChoiceForLink(result *container.LinkReconcileResult) Choice {
+	for _, rule := range p.Rules {
+		if rule.ResourceType != "" {
+			continue
+		}
+		if rule.matches(result.LinkName, result.ChildPath, "") {
+			return rule.Action
+		}
+	}
+	return p.defaultChoice()
+}
+
+func (p *Policy) defaultChoice() Choice {
+	if p.Default == "" {
+		return ChoiceAcceptExternal
+	}
+	return p.Default
+}
+
+func (r Rule) matches(name, childPath, resourceType string) bool {
+	if r.ResourceType != "" && !matchPattern(r.ResourceType, resourceType) {
+		return false
+	}
+	if r.Name == "" {
+		return true
+	}
+	if matchPattern(r.Name, name) {
+		return true
+	}
+	return childPath != "" && matchPattern(r.Name, childPath+"."+name)
+}
+
+func matchPattern(pattern, value string) bool {
+	matched, err := path.Match(pattern, value)
+	return err == nil && matched
+}
+
+func validatePattern(pattern string) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package reconcileui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/container"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/driftui"
+	"github.com/stretchr/testify/suite"
+)
+
+type PolicySuite struct {
+	suite.Suite
+}
+
+func TestPolicySuite(t *testing.T) {
+	suite.Run(t, new(PolicySuite))
+}
+
+func (s *PolicySuite) Test_ParseChoice_accepts_valid_choices() {
+	choice, err := ParseChoice("accept-external")
+	s.Require().NoError(err)
+	s.Equal(ChoiceAcceptExternal, choice)
+
+	choice, err = ParseChoice(" Keep-State ")
+	s.Require().NoError(err)
+	s.Equal(ChoiceKeepState, choice)
+}
+
+func (s *PolicySuite) Test_ParseChoice_rejects_unknown_choice() {
+	_, err := ParseChoice("ignore")
+	s.Require().Error(err)
+	s.Contains(err.Error(), "invalid reconciliation action \"ignore\"")
+}
+
+func (s *PolicySuite) Test_ParseSelectors_creates_name_and_type_rules() {
+	rules, err := ParseSelectors("ordersTable, type:aws/s3/*,,", ChoiceKeepState)
+	s.Require().NoError(err)
+	s.Equal([]Rule{
+		{Name: "ordersTable", Action: ChoiceKeepState},
+		{ResourceType: "aws/s3/*", Action: ChoiceKeepState},
+	}, rules)
+}
+
+func (s *PolicySuite) Test_ParseSelectors_rejects_empty_type() {
+	_, err := ParseSelectors("type:", ChoiceKeepState)
+	s.Require().Error(err)
+}
+
+func (s *PolicySuite) Test_ParseSelectors_rejects_invalid_pattern() {
+	_, err := ParseSelectors("orders[", ChoiceKeepState)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "invalid pattern")
+}
+
+func (s *PolicySuite) Test_empty_policy_accepts_external_state() {
+	policy := &Policy{}
+	s.Equal(ChoiceAcceptExternal, policy.ChoiceForResource(&testResourceResults()[0]))
+	s.Equal(ChoiceAcceptExternal, policy.ChoiceForLink(&testLinkResults()[0]))
+}
+
+func (s *PolicySuite) Test_first_matching_rule_wins() {
+	policy := &Policy{
+		Default: ChoiceKeepState,
+		Rules: []Rule{
+			{Name: "ordersTable", Action: ChoiceKeepState},
+			{ResourceType: "aws/dynamodb/*", Action: ChoiceAcceptExternal},
+		},
+	}
+	resources := testResourceResults()
+
+	s.Equal(ChoiceKeepState, policy.ChoiceForResource(&resources[0]))
+	s.Equal(ChoiceAcceptExternal, policy.ChoiceForResource(&resources[1]))
+	s.Equal(ChoiceKeepState, policy.ChoiceForResource(&resources[2]))
+}
+
+func (s *PolicySuite) Test_name_rule_matches_child_qualified_name() {
+	policy := &Policy{
+		Rules: []Rule{
+			{Name: "coreInfra.*", Action: ChoiceKeepState},
+		},
+	}
+	resources := testResourceResults()
+
+	s.Equal(ChoiceAcceptExternal, policy.ChoiceForResource(&resources[0]))
+	s.Equal(ChoiceKeepState, policy.ChoiceForResource(&resources[2]))
+}
+
+func (s *PolicySuite) Test_type_rules_do_not_match_links() {
+	policy := &Policy{
+		Default: ChoiceKeepState,
+		Rules: []Rule{
+			{ResourceType: "*", Action: ChoiceAcceptExternal},
+		},
+	}
+	s.Equal(ChoiceKeepState, policy.ChoiceForLink(&testLinkResults()[0]))
+}
+
+func (s *PolicySuite) Test_WithOverrides_gives_flag_rules_precedence() {
+	policy := &Policy{
+		Default: ChoiceKeepState,
+		Rules: []Rule{
+			{Name: "ordersTable", Action: ChoiceKeepState},
+		},
+	}
+	merged := policy.WithOverrides(
+		[]Rule{{Name: "ordersTable", Action: ChoiceAcceptExternal}},
+		ChoiceAcceptExternal,
+	)
+	resources := testResourceResults()
+
+	s.Equal(ChoiceAcceptExternal, merged.ChoiceForResource(&resources[0]))
+	s.Equal(ChoiceAcceptExternal, merged.Default)
+	// The original policy is left unchanged.
+	s.Len(policy.Rules, 1)
+	s.Equal(ChoiceKeepState, policy.Default)
+}
+
+func (s *PolicySuite) Test_LoadPolicyFile_loads_yaml_policy() {
+	policyFile := s.writePolicyFile("policy.yaml", `
+default: keep-state
+rules:
+  - resourceType: aws/dynamodb/table
+    action: accept-external
+  - name: coreInfra.*
+    action: keep-state
+`)
+
+	policy, err := LoadPolicyFile(policyFile)
+	s.Require().NoError(err)
+	s.Equal(&Policy{
+		Default: ChoiceKeepState,
+		Rules: []Rule{
+			{ResourceType: "aws/dynamodb/table", Action: ChoiceAcceptExternal},
+			{Name: "coreInfra.*", Action: ChoiceKeepState},
+		},
+	}, policy)
+}
+
+func (s *PolicySuite) Test_LoadPolicyFile_loads_json_policy() {
+	policyFile := s.writePolicyFile(
+		"policy.json",
+		`{"rules": [{"name": "ordersTable", "action": "keep-state"}]}`,
+	)
+
+	policy, err := LoadPolicyFile(policyFile)
+	s.Require().NoError(err)
+	s.Equal(&Policy{
+		Rules: []Rule{{Name: "ordersTable", Action: ChoiceKeepState}},
+	}, policy)
+}
+
+func (s *PolicySuite) Test_LoadPolicyFile_rejects_invalid_action() {
+	policyFile := s.writePolicyFile(
+		"policy.json",
+		`{"rules": [{"name": "ordersTable", "action": "delete"}]}`,
+	)
+
+	_, err := LoadPolicyFile(policyFile)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "rule 0")
+}
+
+func (s *PolicySuite) Test_LoadPolicyFile_rejects_rule_without_selector() {
+	policyFile := s.writePolicyFile(
+		"policy.json",
+		`{"rules": [{"action": "keep-state"}]}`,
+	)
+
+	_, err := LoadPolicyFile(policyFile)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "must have a name or resourceType")
+}
+
+func (s *PolicySuite) Test_LoadPolicyFile_rejects_unsupported_format() {
+	policyFile := s.writePolicyFile("policy.toml", `default = "keep-state"`)
+
+	_, err := LoadPolicyFile(policyFile)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "unsupported reconciliation policy file format")
+}
+
+func (s *PolicySuite) Test_BuildDecisions_records_choice_and_action() {
+	policy := &Policy{
+		Rules: []Rule{{Name: "ordersTable", Action: ChoiceKeepState}},
+	}
+
+	decisions := BuildDecisions(testCheckResult(), policy)
+
+	s.Require().Len(decisions, 4)
+	s.Equal(Decision{
+		ElementType:  driftui.DriftItemTypeResource,
+		Name:         "ordersTable",
+		ResourceType: "aws/dynamodb/table",
+		DriftType:    container.ReconciliationTypeDrift,
+		Choice:       ChoiceKeepState,
+	}, decisions[0])
+	s.Equal(ChoiceAcceptExternal, decisions[1].Choice)
+	s.Equal(container.ReconciliationActionAcceptExternal, decisions[1].Action)
+	s.Equal(driftui.DriftItemTypeLink, decisions[3].ElementType)
+
+	accepted, kept := CountChoices(decisions)
+	s.Equal(3, accepted)
+	s.Equal(1, kept)
+}
+
+func (s *PolicySuite) Test_BuildApplyPayload_only_includes_accepted_elements() {
+	policy := &Policy{
+		Rules: []Rule{
+			{Name: "ordersTable", Action: ChoiceKeepState},
+			{Name: "ordersFunction::ordersTable", Action: ChoiceKeepState},
+		},
+	}
+
+	payload := BuildApplyPayload(testCheckResult(), policy, testDocumentInfo(), nil)
+
+	s.Require().Len(payload.ResourceActions, 2)
+	s.Equal("resource-2", payload.ResourceActions[0].ResourceID)
+	s.Equal("resource-3", payload.ResourceActions[1].ResourceID)
+	s.Equal("coreInfra", payload.ResourceActions[1].ChildPath)
+	s.Empty(payload.LinkActions)
+	s.Equal("app.blueprint.yaml", payload.BlueprintFile)
+}
+
+func (s *PolicySuite) writePolicyFile(name, content string) string {
+	policyFile := filepath.Join(s.T().TempDir(), name)
+	s.Require().NoError(os.WriteFile(policyFile, []byte(content), 0o600))
+	return policyFile
+}
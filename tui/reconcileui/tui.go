@@ -0,0 +1,423 @@
+package reconcileui
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/container"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/newstack-cloud/deploy-cli-sdk/headless"
+	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/driftui"
+	"github.com/newstack-cloud/deploy-cli-sdk/ui/splitpane"
+	"go.uber.org/zap"
+)
+
+// MaxExpandDepth is the maximum nesting depth for expanding child blueprints
+// in the drift review view.
+const MaxExpandDepth = 2
+
+const keyCtrlC = "ctrl+c"
+
+// Mode determines whether the model only reports drift
+// or goes on to reconcile the drift that has been detected.
+type Mode string
+
+const (
+	// ModeCheck reports the elements of an instance that need reconciliation.
+	ModeCheck Mode = "check"
+	// ModeReconcile reports the elements of an instance that need reconciliation
+	// and applies reconciliation actions based on a policy.
+	ModeReconcile Mode = "reconcile"
+)
+
+type reconcileSessionState uint32
+
+const (
+	reconcileChecking reconcileSessionState = iota
+	reconcileReviewing
+	reconcileApplying
+	reconcileFinished
+)
+
+// MainModel is the top-level model for the drift check and drift reconcile commands.
+type MainModel struct {
+	sessionState reconcileSessionState
+	quitting     bool
+
+	// Config from flags
+	mode            Mode
+	instanceID      string
+	instanceName    string
+	blueprintFile   string
+	operationConfig *types.BlueprintOperationConfig
+	policy          *Policy
+
+	// Results
+	checkResult   *container.ReconciliationCheckResult
+	instanceState *state.InstanceState
+	decisions     []Decision
+	applyResult   *container.ApplyReconciliationResult
+
+	// Runtime state
+	ctx       context.Context
+	headless  bool
+	jsonMode  bool
+	spinner   spinner.Model
+	splitPane splitpane.Model
+	footer    *footerRenderer
+
+	// Dependencies
+	engine engine.DeployEngine
+	logger *zap.Logger
+	styles *stylespkg.Styles
+
+	// Output
+	headlessWriter io.Writer
+	printer        *headless.Printer
+
+	// Window size
+	width  int
+	height int
+
+	Error error
+}
+
+// Init initializes the main model.
+func (m MainModel) Init() tea.Cmd {
+	cmds := []tea.Cmd{checkReconciliationCmd(m)}
+	if !m.headless {
+		cmds = append(cmds, m.spinner.Tick)
+	}
+	return tea.Batch(cmds...)
+}
+
+// Update handles messages for the main model.
+func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		var cmd tea.Cmd
+		m.splitPane, cmd = m.splitPane.Update(msg)
+		return m, cmd
+
+	case CheckCompleteMsg:
+		return m.handleCheckComplete(msg)
+
+	case CheckErrorMsg:
+		return m.handleError(msg.Err)
+
+	case ApplyCompleteMsg:
+		return m.handleApplyComplete(msg)
+
+	case ApplyErrorMsg:
+		return m.handleError(msg.Err)
+
+	case spinner.TickMsg:
+		if m.sessionState != reconcileChecking && m.sessionState != reconcileApplying {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case splitpane.QuitMsg:
+		m.quitting = true
+		return m, tea.Quit
+
+	case tea.KeyMsg:
+		return m.handleKeyPress(msg)
+	}
+
+	if m.sessionState == reconcileReviewing {
+		var cmd tea.Cmd
+		m.splitPane, cmd = m.splitPane.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m MainModel) handleCheckComplete(msg CheckCompleteMsg) (tea.Model, tea.Cmd) {
+	m.checkResult = msg.Result
+	m.instanceState = msg.InstanceState
+
+	if !m.DriftDetected() {
+		m.sessionState = reconcileFinished
+		if m.headless {
+			m.dispatchHeadlessCheckOutput()
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
+	m.decisions = BuildDecisions(m.checkResult, m.policy)
+	m.footer.accepted, m.footer.kept = CountChoices(m.decisions)
+	m.sessionState = reconcileReviewing
+
+	if m.headless {
+		return m.handleHeadlessDrift()
+	}
+
+	m.splitPane.SetItems(driftui.BuildDriftItems(m.checkResult, m.instanceState))
+	return m, nil
+}
+
+func (m MainModel) handleHeadlessDrift() (tea.Model, tea.Cmd) {
+	if m.mode == ModeCheck {
+		m.sessionState = reconcileFinished
+		m.dispatchHeadlessCheckOutput()
+		return m, tea.Quit
+	}
+
+	if !m.jsonMode {
+		m.printHeadlessDrift()
+		m.printHeadlessDecisions()
+	}
+
+	return m.startApply()
+}
+
+func (m MainModel) startApply() (tea.Model, tea.Cmd) {
+	accepted, _ := CountChoices(m.decisions)
+	if accepted == 0 {
+		// Nothing to apply when the persisted state is kept for every element.
+		return m.handleApplyComplete(ApplyCompleteMsg{
+			Result: &container.ApplyReconciliationResult{InstanceID: m.checkResult.InstanceID},
+		})
+	}
+
+	m.sessionState = reconcileApplying
+	cmds := []tea.Cmd{applyReconciliationCmd(m)}
+	if !m.headless {
+		cmds = append(cmds, m.spinner.Tick)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m MainModel) handleApplyComplete(msg ApplyCompleteMsg) (tea.Model, tea.Cmd) {
+	m.applyResult = msg.Result
+	if m.applyResult == nil {
+		m.applyResult = &container.ApplyReconciliationResult{}
+	}
+	m.sessionState = reconcileFinished
+
+	if len(m.applyResult.Errors) > 0 {
+		m.Error = fmt.Errorf(
+			"reconciliation failed for %d of %d elements",
+			len(m.applyResult.Errors),
+			len(m.decisions),
+		)
+	}
+
+	if m.headless {
+		m.dispatchHeadlessReconcileOutput()
+		return m, tea.Quit
+	}
+
+	return m, nil
+}
+
+func (m MainModel) handleError(err error) (tea.Model, tea.Cmd) {
+	m.Error = err
+	m.sessionState = reconcileFinished
+	if m.headless {
+		m.dispatchHeadlessError(err)
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m MainModel) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case keyCtrlC:
+		m.quitting = true
+		return m, tea.Quit
+	case "q":
+		if m.sessionState == reconcileApplying {
+			return m, nil
+		}
+		m.quitting = true
+		return m, tea.Quit
+	}
+
+	if m.sessionState != reconcileReviewing {
+		return m, nil
+	}
+
+	if m.mode == ModeReconcile && (msg.String() == "a" || msg.String() == "A") {
+		return m.startApply()
+	}
+
+	var cmd tea.Cmd
+	m.splitPane, cmd = m.splitPane.Update(msg)
+	return m, cmd
+}
+
+// View renders the main model.
+func (m MainModel) View() string {
+	if m.headless {
+		return ""
+	}
+
+	if m.quitting {
+		return m.styles.Muted.Margin(1, 0, 2, 4).Render("See you next time.")
+	}
+
+	if m.Error != nil && m.applyResult == nil {
+		return m.renderError(m.Error)
+	}
+
+	switch m.sessionState {
+	case reconcileChecking:
+		return m.renderLoading("Checking %s for drift...")
+	case reconcileApplying:
+		return m.renderLoading("Applying reconciliation for %s...")
+	case reconcileReviewing:
+		return m.splitPane.View()
+	default:
+		return m.renderFinished()
+	}
+}
+
+// DriftDetected returns whether the reconciliation check found any
+// resources or links that need reconciliation.
+func (m MainModel) DriftDetected() bool {
+	return m.checkResult != nil &&
+		(len(m.checkResult.Resources) > 0 || len(m.checkResult.Links) > 0)
+}
+
+// Decisions returns the choices made for the elements that need reconciliation.
+func (m MainModel) Decisions() []Decision {
+	return m.decisions
+}
+
+func (m *MainModel) reqCtx() context.Context {
+	if m.ctx != nil {
+		return m.ctx
+	}
+	return context.Background()
+}
+
+func (m *MainModel) instanceIdentifier() string {
+	if m.instanceID != "" {
+		return m.instanceID
+	}
+	return m.instanceName
+}
+
+func (m *MainModel) instanceDisplayName() string {
+	if m.instanceName != "" {
+		return m.instanceName
+	}
+	return m.instanceID
+}
+
+func (m *MainModel) resultInstanceID() string {
+	if m.checkResult != nil && m.checkResult.InstanceID != "" {
+		return m.checkResult.InstanceID
+	}
+	return m.instanceID
+}
+
+// ReconcileAppConfig holds configuration for creating a new drift check
+// or drift reconcile application.
+type ReconcileAppConfig struct {
+	// Context is bound to the engine calls the model makes so they are
+	// cancelled when the command context is cancelled (e.g. on Ctrl+C).
+	Context      context.Context
+	DeployEngine engine.DeployEngine
+	Logger       *zap.Logger
+	Mode         Mode
+	InstanceID   string
+	InstanceName string
+	// BlueprintFile is an optional blueprint document that is sent
+	// to the deploy engine along with the check and apply requests.
+	BlueprintFile   string
+	OperationConfig *types.BlueprintOperationConfig
+	// Policy determines the choices made for the elements that need reconciliation,
+	// when not set, the external state is accepted for all elements.
+	Policy         *Policy
+	Styles         *stylespkg.Styles
+	Headless       bool
+	HeadlessWriter io.Writer
+	JSONMode       bool
+}
+
+// NewReconcileApp creates a new drift check or drift reconcile application
+// with the given configuration.
+func NewReconcileApp(cfg ReconcileAppConfig) (*MainModel, error) {
+	if cfg.InstanceID == "" && cfg.InstanceName == "" {
+		return nil, fmt.Errorf("an instance ID or name must be provided")
+	}
+
+	mode := cfg.Mode
+	if mode == "" {
+		mode = ModeCheck
+	}
+
+	policy := cfg.Policy
+	if policy == nil {
+		policy = &Policy{}
+	}
+
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = cfg.Styles.Spinner
+
+	footer := &footerRenderer{mode: mode}
+
+	model := &MainModel{
+		sessionState:    reconcileChecking,
+		mode:            mode,
+		instanceID:      cfg.InstanceID,
+		instanceName:    cfg.InstanceName,
+		blueprintFile:   cfg.BlueprintFile,
+		operationConfig: cfg.OperationConfig,
+		policy:          policy,
+		ctx:             cfg.Context,
+		headless:        cfg.Headless,
+		jsonMode:        cfg.JSONMode,
+		spinner:         s,
+		splitPane:       splitpane.New(createSplitPaneConfig(cfg.Styles, footer)),
+		footer:          footer,
+		engine:          cfg.DeployEngine,
+		logger:          cfg.Logger,
+		styles:          cfg.Styles,
+		headlessWriter:  cfg.HeadlessWriter,
+		printer:         createHeadlessPrinter(cfg.Headless, mode, cfg.HeadlessWriter),
+	}
+
+	return model, nil
+}
+
+func createSplitPaneConfig(styles *stylespkg.Styles, footer *footerRenderer) splitpane.Config {
+	return splitpane.Config{
+		Styles: styles,
+		DetailsRenderer: &driftui.DriftDetailsRenderer{
+			MaxExpandDepth:       MaxExpandDepth,
+			NavigationStackDepth: 0,
+		},
+		Title:          "⚠ Drift Detected",
+		LeftPaneRatio:  0.4,
+		MaxExpandDepth: MaxExpandDepth,
+		SectionGrouper: &driftui.DriftSectionGrouper{
+			MaxExpandDepth: MaxExpandDepth,
+		},
+		FooterRenderer: footer,
+	}
+}
+
+func createHeadlessPrinter(isHeadless bool, mode Mode, headlessWriter io.Writer) *headless.Printer {
+	if !isHeadless || headlessWriter == nil {
+		return nil
+	}
+	prefixedWriter := headless.NewPrefixedWriter(headlessWriter, fmt.Sprintf("[drift %s] ", mode))
+	return headless.NewPrinter(prefixedWriter, 80)
+}
@@ -0,0 +1,462 @@
+package reconcileui
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/container"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/provider"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
+	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
+	"github.com/newstack-cloud/deploy-cli-sdk/testutils"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+type ReconcileTUISuite struct {
+	suite.Suite
+	styles *stylespkg.Styles
+}
+
+func TestReconcileTUISuite(t *testing.T) {
+	suite.Run(t, new(ReconcileTUISuite))
+}
+
+func (s *ReconcileTUISuite) SetupTest() {
+	s.styles = stylespkg.NewStyles(
+		lipgloss.NewRenderer(os.Stdout),
+		stylespkg.NewBluelinkPalette(),
+	)
+}
+
+func (s *ReconcileTUISuite) newTestModel(
+	deployEngine engine.DeployEngine,
+	mode Mode,
+	policy *Policy,
+	isHeadless bool,
+	jsonMode bool,
+	output *bytes.Buffer,
+) MainModel {
+	model, err := NewReconcileApp(ReconcileAppConfig{
+		DeployEngine:   deployEngine,
+		Logger:         zap.NewNop(),
+		Mode:           mode,
+		InstanceName:   "my-app",
+		Policy:         policy,
+		Styles:         s.styles,
+		Headless:       isHeadless,
+		HeadlessWriter: output,
+		JSONMode:       jsonMode,
+	})
+	s.Require().NoError(err)
+	return *model
+}
+
+func (s *ReconcileTUISuite) runHeadless(model MainModel) MainModel {
+	testModel := teatest.NewTestModel(
+		s.T(),
+		model,
+		teatest.WithInitialTermSize(300, 100),
+	)
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+	return testModel.FinalModel(s.T()).(MainModel)
+}
+
+func (s *ReconcileTUISuite) Test_NewReconcileApp_requires_instance() {
+	_, err := NewReconcileApp(ReconcileAppConfig{
+		DeployEngine: testutils.NewScenarioBuilder().Build(),
+		Styles:       s.styles,
+	})
+	s.Require().Error(err)
+}
+
+// --- Interactive Mode Tests ---
+
+func (s *ReconcileTUISuite) Test_check_displays_drift_review() {
+	deployEngine := testReconcileEngine(&testutils.ReconciliationScenario{Result: testCheckResult()})
+	model := s.newTestModel(deployEngine, ModeCheck, nil, false, false, nil)
+
+	testModel := teatest.NewTestModel(
+		s.T(),
+		model,
+		teatest.WithInitialTermSize(300, 100),
+	)
+
+	testutils.WaitForContainsAll(
+		s.T(),
+		testModel.Output(),
+		"Drift Detected",
+		"ordersTable",
+		"drift reconcile",
+	)
+
+	testutils.KeyQ(testModel)
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+
+	finalModel := testModel.FinalModel(s.T()).(MainModel)
+	s.Nil(finalModel.Error)
+	s.True(finalModel.DriftDetected())
+	s.Nil(finalModel.applyResult)
+}
+
+func (s *ReconcileTUISuite) Test_check_displays_no_drift() {
+	deployEngine := testReconcileEngine(&testutils.ReconciliationScenario{
+		Result: &container.ReconciliationCheckResult{InstanceID: "instance-1"},
+	})
+	model := s.newTestModel(deployEngine, ModeCheck, nil, false, false, nil)
+
+	testModel := teatest.NewTestModel(
+		s.T(),
+		model,
+		teatest.WithInitialTermSize(300, 100),
+	)
+
+	testutils.WaitForContainsAll(s.T(), testModel.Output(), "No drift detected")
+
+	testutils.KeyQ(testModel)
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+
+	finalModel := testModel.FinalModel(s.T()).(MainModel)
+	s.False(finalModel.DriftDetected())
+}
+
+func (s *ReconcileTUISuite) Test_reconcile_applies_policy_choices_on_confirmation() {
+	deployEngine := testReconcileEngine(&testutils.ReconciliationScenario{
+		Result: testCheckResult(),
+		ApplyResult: &container.ApplyReconciliationResult{
+			InstanceID:       "instance-1",
+			ResourcesUpdated: 2,
+			LinksUpdated:     1,
+		},
+	})
+	policy := &Policy{
+		Rules: []Rule{{Name: "ordersTable", Action: ChoiceKeepState}},
+	}
+	model := s.newTestModel(deployEngine, ModeReconcile, policy, false, false, nil)
+
+	testModel := teatest.NewTestModel(
+		s.T(),
+		model,
+		teatest.WithInitialTermSize(300, 100),
+	)
+
+	testutils.WaitForContainsAll(
+		s.T(),
+		testModel.Output(),
+		"apply reconciliation",
+		"3 accept external state, 1 keep persisted state",
+	)
+
+	testutils.Key(testModel, "a")
+
+	testutils.WaitForContainsAll(
+		s.T(),
+		testModel.Output(),
+		"Reconciliation complete",
+		"2 resources and 1 link updated",
+		"1 element kept the persisted state",
+	)
+
+	payloads := applyPayloads(deployEngine)
+	s.Require().Len(payloads, 1)
+	s.Len(payloads[0].ResourceActions, 2)
+	s.Len(payloads[0].LinkActions, 1)
+
+	testutils.KeyQ(testModel)
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+
+	finalModel := testModel.FinalModel(s.T()).(MainModel)
+	s.Nil(finalModel.Error)
+}
+
+// --- Headless Mode Tests ---
+
+func (s *ReconcileTUISuite) Test_headless_check_prints_drift() {
+	output := &bytes.Buffer{}
+	deployEngine := testReconcileEngine(&testutils.ReconciliationScenario{Result: testCheckResult()})
+	finalModel := s.runHeadless(s.newTestModel(deployEngine, ModeCheck, nil, true, false, output))
+
+	s.Nil(finalModel.Error)
+	s.True(finalModel.DriftDetected())
+	s.Contains(output.String(), "[drift check]")
+	s.Contains(output.String(), "Drift detected")
+	s.Contains(output.String(), "ordersTable (aws/dynamodb/table)")
+	s.Contains(output.String(), "Run bluelink drift reconcile to update the instance state")
+	s.Empty(applyPayloads(deployEngine))
+}
+
+func (s *ReconcileTUISuite) Test_headless_check_prints_no_drift() {
+	output := &bytes.Buffer{}
+	deployEngine := testReconcileEngine(&testutils.ReconciliationScenario{})
+	finalModel := s.runHeadless(s.newTestModel(deployEngine, ModeCheck, nil, true, false, output))
+
+	s.Nil(finalModel.Error)
+	s.False(finalModel.DriftDetected())
+	s.Contains(output.String(), "No drift detected for my-app")
+}
+
+func (s *ReconcileTUISuite) Test_headless_check_json_output() {
+	output := &bytes.Buffer{}
+	deployEngine := testReconcileEngine(&testutils.ReconciliationScenario{Result: testCheckResult()})
+	finalModel := s.runHeadless(s.newTestModel(deployEngine, ModeCheck, nil, true, true, output))
+	s.Nil(finalModel.Error)
+
+	var result jsonout.DriftCheckOutput
+	s.Require().NoError(json.Unmarshal(output.Bytes(), &result))
+	s.True(result.Success)
+	s.True(result.DriftDetected)
+	s.Equal("instance-1", result.InstanceID)
+	s.Equal("my-app", result.InstanceName)
+	s.Len(result.Reconciliation.Resources, 3)
+	s.Len(result.Reconciliation.Links, 1)
+}
+
+func (s *ReconcileTUISuite) Test_headless_check_json_error_output() {
+	output := &bytes.Buffer{}
+	finalModel := s.runHeadless(s.newTestModel(
+		testReconcileEngine(&testutils.ReconciliationScenario{
+			CheckError: &engine.SessionError{Type: engine.SessionErrorOther, Message: "instance not found"},
+		}),
+		ModeCheck,
+		nil,
+		true,
+		true,
+		output,
+	))
+	s.Require().Error(finalModel.Error)
+
+	var result jsonout.ErrorOutput
+	s.Require().NoError(json.Unmarshal(output.Bytes(), &result))
+	s.False(result.Success)
+	s.Contains(result.Error.Message, "instance not found")
+}
+
+func (s *ReconcileTUISuite) Test_headless_reconcile_prints_plan_and_result() {
+	output := &bytes.Buffer{}
+	deployEngine := testReconcileEngine(&testutils.ReconciliationScenario{
+		Result: testCheckResult(),
+		ApplyResult: &container.ApplyReconciliationResult{
+			InstanceID:       "instance-1",
+			ResourcesUpdated: 1,
+		},
+	})
+	policy := &Policy{
+		Default: ChoiceKeepState,
+		Rules:   []Rule{{Name: "usersTable", Action: ChoiceAcceptExternal}},
+	}
+	finalModel := s.runHeadless(s.newTestModel(deployEngine, ModeReconcile, policy, true, false, output))
+
+	s.Nil(finalModel.Error)
+	s.Contains(output.String(), "[drift reconcile]")
+	s.Contains(output.String(), "Reconciliation plan:")
+	s.Contains(output.String(), "✓ resource usersTable: accept-external (accept_external)")
+	s.Contains(output.String(), "- resource ordersTable: keep-state")
+	s.Contains(output.String(), "- resource coreInfra.sharedBucket: keep-state")
+	s.Contains(output.String(), "✓ Reconciliation complete")
+	s.Contains(output.String(), "1 resource and 0 links updated")
+	s.Contains(output.String(), "3 elements kept the persisted state")
+	s.NotContains(output.String(), "To resolve:")
+
+	payloads := applyPayloads(deployEngine)
+	s.Require().Len(payloads, 1)
+	s.Require().Len(payloads[0].ResourceActions, 1)
+	s.Equal("resource-2", payloads[0].ResourceActions[0].ResourceID)
+	s.Empty(payloads[0].LinkActions)
+}
+
+func (s *ReconcileTUISuite) Test_headless_reconcile_json_output() {
+	output := &bytes.Buffer{}
+	deployEngine := testReconcileEngine(&testutils.ReconciliationScenario{
+		Result: testCheckResult(),
+		ApplyResult: &container.ApplyReconciliationResult{
+			InstanceID:       "instance-1",
+			ResourcesUpdated: 3,
+			LinksUpdated:     1,
+		},
+	})
+	finalModel := s.runHeadless(s.newTestModel(deployEngine, ModeReconcile, nil, true, true, output))
+	s.Nil(finalModel.Error)
+
+	var result jsonout.DriftReconcileOutput
+	s.Require().NoError(json.Unmarshal(output.Bytes(), &result))
+	s.True(result.Success)
+	s.True(result.DriftDetected)
+	s.Equal(3, result.ResourcesUpdated)
+	s.Equal(1, result.LinksUpdated)
+	s.Require().Len(result.Decisions, 4)
+	s.Equal(jsonout.ReconcileDecision{
+		ElementType:  "resource",
+		Name:         "ordersTable",
+		ResourceType: "aws/dynamodb/table",
+		DriftType:    "drift",
+		Choice:       "accept-external",
+		Action:       "accept_external",
+	}, result.Decisions[0])
+}
+
+func (s *ReconcileTUISuite) Test_headless_reconcile_skips_apply_when_all_state_is_kept() {
+	output := &bytes.Buffer{}
+	deployEngine := testReconcileEngine(&testutils.ReconciliationScenario{Result: testCheckResult()})
+	policy := &Policy{Default: ChoiceKeepState}
+	finalModel := s.runHeadless(s.newTestModel(deployEngine, ModeReconcile, policy, true, true, output))
+	s.Nil(finalModel.Error)
+	s.Empty(applyPayloads(deployEngine))
+
+	var result jsonout.DriftReconcileOutput
+	s.Require().NoError(json.Unmarshal(output.Bytes(), &result))
+	s.True(result.Success)
+	s.Equal(0, result.ResourcesUpdated)
+	s.Equal("keep-state", result.Decisions[0].Choice)
+	s.Empty(result.Decisions[0].Action)
+}
+
+func (s *ReconcileTUISuite) Test_headless_reconcile_reports_element_errors() {
+	output := &bytes.Buffer{}
+	deployEngine := testReconcileEngine(&testutils.ReconciliationScenario{
+		Result: testCheckResult(),
+		ApplyResult: &container.ApplyReconciliationResult{
+			InstanceID:       "instance-1",
+			ResourcesUpdated: 2,
+			LinksUpdated:     1,
+			Errors: []container.ReconciliationError{
+				{
+					ElementID:   "resource-1",
+					ElementName: "ordersTable",
+					ElementType: "resource",
+					Error:       "external state missing",
+				},
+			},
+		},
+	})
+	finalModel := s.runHeadless(s.newTestModel(deployEngine, ModeReconcile, nil, true, true, output))
+	s.Require().Error(finalModel.Error)
+
+	var result jsonout.DriftReconcileOutput
+	s.Require().NoError(json.Unmarshal(output.Bytes(), &result))
+	s.False(result.Success)
+	s.Equal([]jsonout.ReconcileError{
+		{
+			ElementID:   "resource-1",
+			ElementName: "ordersTable",
+			ElementType: "resource",
+			Error:       "external state missing",
+		},
+	}, result.Errors)
+}
+
+func (s *ReconcileTUISuite) Test_headless_reconcile_apply_error() {
+	output := &bytes.Buffer{}
+	finalModel := s.runHeadless(s.newTestModel(
+		testReconcileEngine(&testutils.ReconciliationScenario{
+			Result:     testCheckResult(),
+			ApplyError: &engine.SessionError{Type: engine.SessionErrorOther, Message: "apply failed"},
+		}),
+		ModeReconcile,
+		nil,
+		true,
+		false,
+		output,
+	))
+
+	s.Require().Error(finalModel.Error)
+	s.Contains(output.String(), "ERR Drift reconciliation failed")
+	s.Contains(output.String(), "apply failed")
+}
+
+// testReconcileEngine creates a deploy engine that follows the provided
+// reconciliation for the my-app instance.
+func testReconcileEngine(reconciliation *testutils.ReconciliationScenario) *testutils.ScenarioDeployEngine {
+	builder := testutils.NewScenarioBuilder()
+	builder.Instance("my-app").
+		WithID("instance-1").
+		Reconciliation(reconciliation)
+	return builder.Build()
+}
+
+// applyPayloads returns the payloads received by the deploy engine
+// for ApplyReconciliation.
+func applyPayloads(deployEngine *testutils.ScenarioDeployEngine) []*types.ApplyReconciliationPayload {
+	payloads := []*types.ApplyReconciliationPayload{}
+	for _, payload := range deployEngine.Payloads("ApplyReconciliation") {
+		payloads = append(payloads, payload.(*types.ApplyReconciliationPayload))
+	}
+	return payloads
+}
+
+func testCheckResult() *container.ReconciliationCheckResult {
+	return &container.ReconciliationCheckResult{
+		InstanceID: "instance-1",
+		Resources:  testResourceResults(),
+		Links:      testLinkResults(),
+		HasDrift:   true,
+	}
+}
+
+func testResourceResults() []container.ResourceReconcileResult {
+	prevValue := "PAY_PER_REQUEST"
+	newValue := "PROVISIONED"
+	return []container.ResourceReconcileResult{
+		{
+			ResourceID:     "resource-1",
+			ResourceName:   "ordersTable",
+			ResourceType:   "aws/dynamodb/table",
+			Type:           container.ReconciliationTypeDrift,
+			ResourceExists: true,
+			ExternalState:  core.MappingNodeFromString(newValue),
+			Changes: &provider.Changes{
+				ModifiedFields: []provider.FieldChange{
+					{
+						FieldPath: "spec.billingMode",
+						PrevValue: core.MappingNodeFromString(prevValue),
+						NewValue:  core.MappingNodeFromString(newValue),
+					},
+				},
+			},
+			RecommendedAction: container.ReconciliationActionAcceptExternal,
+		},
+		{
+			ResourceID:        "resource-2",
+			ResourceName:      "usersTable",
+			ResourceType:      "aws/dynamodb/table",
+			Type:              container.ReconciliationTypeDrift,
+			ResourceExists:    true,
+			RecommendedAction: container.ReconciliationActionAcceptExternal,
+		},
+		{
+			ResourceID:        "resource-3",
+			ResourceName:      "sharedBucket",
+			ResourceType:      "aws/s3/bucket",
+			ChildPath:         "coreInfra",
+			Type:              container.ReconciliationTypeDrift,
+			ResourceExists:    true,
+			RecommendedAction: container.ReconciliationActionAcceptExternal,
+		},
+	}
+}
+
+func testLinkResults() []container.LinkReconcileResult {
+	return []container.LinkReconcileResult{
+		{
+			LinkID:            "link-1",
+			LinkName:          "ordersFunction::ordersTable",
+			Type:              container.ReconciliationTypeDrift,
+			RecommendedAction: container.ReconciliationActionAcceptExternal,
+		},
+	}
+}
+
+func testDocumentInfo() types.BlueprintDocumentInfo {
+	return types.BlueprintDocumentInfo{
+		FileSourceScheme: "file",
+		Directory:        "/projects/app",
+		BlueprintFile:    "app.blueprint.yaml",
+	}
+}
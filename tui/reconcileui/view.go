@@ -0,0 +1,154 @@
+package reconcileui
+
+import (
+	"fmt"
+	"strings"
+
+	sdkstrings "github.com/newstack-cloud/deploy-cli-sdk/strings"
+	"github.com/newstack-cloud/deploy-cli-sdk/styles"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/driftui"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/shared"
+	"github.com/newstack-cloud/deploy-cli-sdk/ui/splitpane"
+)
+
+func (m MainModel) renderLoading(format string) string {
+	return fmt.Sprintf(
+		"\n  %s %s\n",
+		m.spinner.View(),
+		fmt.Sprintf(format, m.styles.Selected.Render(m.instanceDisplayName())),
+	)
+}
+
+func (m MainModel) renderError(err error) string {
+	return m.styles.Error.Margin(2, 4).Render("Error: " + err.Error())
+}
+
+func (m MainModel) renderFinished() string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+
+	if !m.DriftDetected() {
+		sb.WriteString(m.styles.Title.MarginLeft(2).Render("✓ No drift detected"))
+		sb.WriteString("\n")
+		sb.WriteString(m.styles.Muted.MarginLeft(2).Render(
+			"The persisted state of " + m.instanceDisplayName() + " matches the external state.",
+		))
+		sb.WriteString("\n\n")
+		sb.WriteString(m.renderQuitFooter())
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	sb.WriteString(m.renderApplySummary())
+	sb.WriteString("\n")
+	sb.WriteString(m.renderQuitFooter())
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+func (m MainModel) renderApplySummary() string {
+	var sb strings.Builder
+	result := m.applyResult
+
+	if len(result.Errors) > 0 {
+		sb.WriteString(m.styles.Error.MarginLeft(2).Render("✗ " + m.Error.Error()))
+		sb.WriteString("\n\n")
+		for _, reconcileErr := range result.Errors {
+			sb.WriteString(m.styles.Error.MarginLeft(4).Render(
+				fmt.Sprintf("%s %s: %s", reconcileErr.ElementType, reconcileErr.ElementName, reconcileErr.Error),
+			))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	} else {
+		sb.WriteString(m.styles.Title.MarginLeft(2).Render("✓ Reconciliation complete"))
+		sb.WriteString("\n\n")
+	}
+
+	sb.WriteString(m.styles.Muted.MarginLeft(4).Render(
+		fmt.Sprintf(
+			"%d %s and %d %s updated",
+			result.ResourcesUpdated,
+			sdkstrings.Pluralize(result.ResourcesUpdated, "resource", "resources"),
+			result.LinksUpdated,
+			sdkstrings.Pluralize(result.LinksUpdated, "link", "links"),
+		),
+	))
+	sb.WriteString("\n")
+
+	_, kept := CountChoices(m.decisions)
+	if kept > 0 {
+		sb.WriteString(m.styles.Muted.MarginLeft(4).Render(
+			fmt.Sprintf(
+				"%d %s kept the persisted state and will be reported by the next drift check",
+				kept,
+				sdkstrings.Pluralize(kept, "element", "elements"),
+			),
+		))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+func (m MainModel) renderQuitFooter() string {
+	var sb strings.Builder
+	sb.WriteString("  ")
+	sb.WriteString(m.styles.Key.Render("q"))
+	sb.WriteString(m.styles.Muted.Render(" quit"))
+	return sb.String()
+}
+
+// footerRenderer implements splitpane.FooterRenderer for the drift review
+// of the drift check and drift reconcile commands.
+type footerRenderer struct {
+	mode     Mode
+	accepted int
+	kept     int
+}
+
+// Ensure footerRenderer implements splitpane.FooterRenderer
+var _ splitpane.FooterRenderer = (*footerRenderer)(nil)
+
+func (r *footerRenderer) RenderFooter(model *splitpane.Model, s *styles.Styles) string {
+	sb := strings.Builder{}
+	sb.WriteString("\n")
+
+	if model.IsInDrillDown() {
+		shared.RenderBreadcrumb(&sb, model.NavigationPath(), s)
+		shared.RenderFooterNavigation(&sb, s,
+			shared.KeyHint{Key: "esc", Desc: "back"},
+			shared.KeyHint{Key: "enter", Desc: "expand"},
+		)
+		return sb.String()
+	}
+
+	sb.WriteString(s.Muted.Render("  "))
+	if r.mode == ModeReconcile {
+		sb.WriteString(s.Key.Render("a"))
+		sb.WriteString(s.Muted.Render(" apply reconciliation  "))
+	}
+	sb.WriteString(s.Key.Render("q"))
+	sb.WriteString(s.Muted.Render(" quit"))
+	sb.WriteString("\n\n")
+
+	if r.mode == ModeReconcile {
+		sb.WriteString(s.Muted.Render("  Policy: "))
+		sb.WriteString(s.Hint.Render(fmt.Sprintf(
+			"%d accept external state, %d keep persisted state",
+			r.accepted,
+			r.kept,
+		)))
+		sb.WriteString("\n\n")
+	} else {
+		sb.WriteString(s.Muted.Render("  Hint: "))
+		sb.WriteString(s.Hint.Render(driftui.HintForContext(driftui.DriftContextCheck)))
+		sb.WriteString("\n\n")
+	}
+
+	shared.RenderFooterNavigation(&sb, s,
+		shared.KeyHint{Key: "enter", Desc: "expand"},
+	)
+
+	return sb.String()
+}
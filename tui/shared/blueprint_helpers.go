@@ -49,3 +49,13 @@ func BuildObjectStorageDocumentInfo(blueprintFile, scheme string) types.Blueprin
 		BlueprintFile:    path.Base(pathWithoutScheme),
 	}
 }
+
+// EngineContextSubtitle returns the header subtitle that shows the engine
+// context an operation runs against, or an empty string when no context
+// is selected.
+func EngineContextSubtitle(engineContext string) string {
+	if engineContext == "" {
+		return ""
+	}
+	return "Context: " + engineContext
+}
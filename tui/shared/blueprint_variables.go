@@ -0,0 +1,199 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/lang"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/schema"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/deploy-cli-sdk/consts"
+)
+
+// MissingVariable describes a variable declared in a blueprint that has no
+// default value and has not been provided in the blueprint operation config.
+type MissingVariable struct {
+	Name        string
+	Type        schema.VariableType
+	Description string
+	// AllowedValues holds the string form of the values the
+	// variable is restricted to, if any.
+	AllowedValues []string
+	Secret        bool
+}
+
+// MissingVariablesError is returned when a blueprint declares required
+// variables that have not been provided and the user can not be prompted
+// for them.
+type MissingVariablesError struct {
+	Variables []MissingVariable
+}
+
+func (e *MissingVariablesError) Error() string {
+	sb := strings.Builder{}
+	fmt.Fprintf(
+		&sb,
+		"the blueprint requires %d variable(s) that have not been provided:",
+		len(e.Variables),
+	)
+	for _, variable := range e.Variables {
+		fmt.Fprintf(&sb, "\n  - %s (%s)", variable.Name, variable.Type)
+		if len(variable.AllowedValues) > 0 {
+			fmt.Fprintf(&sb, ", one of: %s", strings.Join(variable.AllowedValues, ", "))
+		}
+	}
+	sb.WriteString(
+		"\nset them with --var name=value, --var-file or the blueprintVariables " +
+			"section of the deploy config file",
+	)
+	return sb.String()
+}
+
+// FindMissingVariables loads the variable definitions from a local blueprint
+// file and returns the variables that have no default value and are not set
+// in the blueprint operation config, ordered by name.
+//
+// Nothing is returned for blueprints that are not local files or local files
+// that do not exist, the deploy engine reports missing variables for these
+// when the blueprint is validated.
+func FindMissingVariables(
+	blueprintFile string,
+	opConfig *types.BlueprintOperationConfig,
+) ([]MissingVariable, error) {
+	if BlueprintSourceFromPath(blueprintFile) != consts.BlueprintSourceFile {
+		return nil, nil
+	}
+	if _, err := os.Stat(blueprintFile); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	blueprint, err := loadLocalBlueprint(blueprintFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading variables from blueprint %s: %w", blueprintFile, err)
+	}
+	if blueprint.Variables == nil {
+		return nil, nil
+	}
+
+	provided := map[string]*core.ScalarValue{}
+	if opConfig != nil && opConfig.BlueprintVariables != nil {
+		provided = opConfig.BlueprintVariables
+	}
+
+	missing := []MissingVariable{}
+	for name, variable := range blueprint.Variables.Values {
+		if variable == nil || variable.Default != nil || provided[name] != nil {
+			continue
+		}
+		missing = append(missing, toMissingVariable(name, variable))
+	}
+	slices.SortFunc(missing, func(a, b MissingVariable) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return missing, nil
+}
+
+// FindSecretVariables loads the variable definitions from local blueprint
+// files and returns the names of the variables declared as secret.
+// Blueprints that are not local files, do not exist or can not be loaded
+// are skipped as the deploy engine reports problems with these blueprints.
+func FindSecretVariables(blueprintFiles ...string) map[string]bool {
+	secretVariables := map[string]bool{}
+	for _, blueprintFile := range blueprintFiles {
+		if BlueprintSourceFromPath(blueprintFile) != consts.BlueprintSourceFile {
+			continue
+		}
+
+		blueprint, err := loadLocalBlueprint(blueprintFile)
+		if err != nil || blueprint.Variables == nil {
+			continue
+		}
+
+		for name, variable := range blueprint.Variables.Values {
+			if variable != nil && variable.Secret != nil &&
+				variable.Secret.BoolValue != nil && *variable.Secret.BoolValue {
+				secretVariables[name] = true
+			}
+		}
+	}
+	return secretVariables
+}
+
+func loadLocalBlueprint(blueprintFile string) (*schema.Blueprint, error) {
+	switch strings.ToLower(filepath.Ext(blueprintFile)) {
+	case ".bp", ".blueprint":
+		return lang.ParseFile(blueprintFile)
+	case ".json", ".jsonc":
+		return schema.Load(blueprintFile, schema.JWCCSpecFormat)
+	default:
+		return schema.Load(blueprintFile, schema.YAMLSpecFormat)
+	}
+}
+
+func toMissingVariable(name string, variable *schema.Variable) MissingVariable {
+	missing := MissingVariable{
+		Name: name,
+		Type: schema.VariableTypeString,
+	}
+	if variable.Type != nil && variable.Type.Value != "" {
+		missing.Type = variable.Type.Value
+	}
+	if variable.Description != nil {
+		missing.Description = variable.Description.ToString()
+	}
+	if variable.Secret != nil && variable.Secret.BoolValue != nil {
+		missing.Secret = *variable.Secret.BoolValue
+	}
+	for _, allowedValue := range variable.AllowedValues {
+		if allowedValue != nil {
+			missing.AllowedValues = append(missing.AllowedValues, allowedValue.ToString())
+		}
+	}
+	return missing
+}
+
+// ParseVariableValue parses the value entered for a variable into a scalar
+// of the variable's type, custom variable types defined by providers
+// are treated as strings.
+func ParseVariableValue(variable MissingVariable, input string) (*core.ScalarValue, error) {
+	trimmed := strings.TrimSpace(input)
+	if trimmed == "" {
+		return nil, fmt.Errorf("a value for %s is required", variable.Name)
+	}
+
+	switch variable.Type {
+	case schema.VariableTypeInteger:
+		value, err := strconv.Atoi(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be an integer", variable.Name)
+		}
+		return core.ScalarFromInt(value), nil
+	case schema.VariableTypeFloat:
+		value, err := strconv.ParseFloat(trimmed, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number", variable.Name)
+		}
+		return core.ScalarFromFloat(value), nil
+	case schema.VariableTypeBoolean:
+		value, err := strconv.ParseBool(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false", variable.Name)
+		}
+		return core.ScalarFromBool(value), nil
+	}
+
+	if len(variable.AllowedValues) > 0 && !slices.Contains(variable.AllowedValues, input) {
+		return nil, fmt.Errorf(
+			"%s must be one of: %s",
+			variable.Name,
+			strings.Join(variable.AllowedValues, ", "),
+		)
+	}
+	return core.ScalarFromString(input), nil
+}
@@ -0,0 +1,163 @@
+package shared
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/schema"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
+	"github.com/stretchr/testify/suite"
+)
+
+const testVariablesBlueprint = `version: 2025-11-02
+variables:
+  region:
+    type: string
+    description: The region to deploy to.
+    allowedValues:
+      - eu-west-1
+      - us-east-1
+  replicas:
+    type: integer
+  databasePassword:
+    type: string
+    secret: true
+  logLevel:
+    type: string
+    default: info
+resources:
+  bucket:
+    type: aws/s3/bucket
+    spec:
+      bucketName: example
+`
+
+type BlueprintVariablesSuite struct {
+	suite.Suite
+	blueprintFile string
+}
+
+func (s *BlueprintVariablesSuite) SetupTest() {
+	s.blueprintFile = filepath.Join(s.T().TempDir(), "app.blueprint.yaml")
+	s.Require().NoError(os.WriteFile(s.blueprintFile, []byte(testVariablesBlueprint), 0o600))
+}
+
+func (s *BlueprintVariablesSuite) Test_finds_variables_without_defaults_or_values() {
+	missing, err := FindMissingVariables(s.blueprintFile, &types.BlueprintOperationConfig{
+		BlueprintVariables: map[string]*core.ScalarValue{
+			"replicas": core.ScalarFromInt(2),
+		},
+	})
+	s.Require().NoError(err)
+	s.Equal([]MissingVariable{
+		{Name: "databasePassword", Type: schema.VariableTypeString, Secret: true},
+		{
+			Name:          "region",
+			Type:          schema.VariableTypeString,
+			Description:   "The region to deploy to.",
+			AllowedValues: []string{"eu-west-1", "us-east-1"},
+		},
+	}, missing)
+}
+
+func (s *BlueprintVariablesSuite) Test_skips_remote_and_missing_blueprints() {
+	missing, err := FindMissingVariables("s3://bucket/app.blueprint.yaml", nil)
+	s.Require().NoError(err)
+	s.Empty(missing)
+
+	missing, err = FindMissingVariables(filepath.Join(s.T().TempDir(), "missing.yaml"), nil)
+	s.Require().NoError(err)
+	s.Empty(missing)
+}
+
+func (s *BlueprintVariablesSuite) Test_finds_secret_variables_of_local_blueprints() {
+	secretVariables := FindSecretVariables(
+		s.blueprintFile,
+		"s3://bucket/app.blueprint.yaml",
+		filepath.Join(s.T().TempDir(), "missing.yaml"),
+	)
+	s.Equal(map[string]bool{"databasePassword": true}, secretVariables)
+}
+
+func (s *BlueprintVariablesSuite) Test_missing_variables_error_lists_all_variables() {
+	missing, err := FindMissingVariables(s.blueprintFile, nil)
+	s.Require().NoError(err)
+
+	message := (&MissingVariablesError{Variables: missing}).Error()
+	s.Contains(message, "the blueprint requires 3 variable(s) that have not been provided")
+	s.Contains(message, "  - databasePassword (string)")
+	s.Contains(message, "  - region (string), one of: eu-west-1, us-east-1")
+	s.Contains(message, "  - replicas (integer)")
+	s.Contains(message, "--var name=value")
+}
+
+func (s *BlueprintVariablesSuite) Test_parses_values_to_the_type_of_the_variable() {
+	value, err := ParseVariableValue(MissingVariable{Name: "replicas", Type: schema.VariableTypeInteger}, " 3 ")
+	s.Require().NoError(err)
+	s.Equal(core.ScalarFromInt(3), value)
+
+	value, err = ParseVariableValue(MissingVariable{Name: "ratio", Type: schema.VariableTypeFloat}, "0.5")
+	s.Require().NoError(err)
+	s.Equal(core.ScalarFromFloat(0.5), value)
+
+	value, err = ParseVariableValue(MissingVariable{Name: "enabled", Type: schema.VariableTypeBoolean}, "true")
+	s.Require().NoError(err)
+	s.Equal(core.ScalarFromBool(true), value)
+
+	value, err = ParseVariableValue(MissingVariable{Name: "region", Type: "aws/region"}, "eu-west-2")
+	s.Require().NoError(err)
+	s.Equal(core.ScalarFromString("eu-west-2"), value)
+}
+
+func (s *BlueprintVariablesSuite) Test_rejects_values_that_do_not_match_the_variable() {
+	_, err := ParseVariableValue(MissingVariable{Name: "replicas", Type: schema.VariableTypeInteger}, "three")
+	s.EqualError(err, "replicas must be an integer")
+
+	_, err = ParseVariableValue(MissingVariable{Name: "enabled", Type: schema.VariableTypeBoolean}, "maybe")
+	s.EqualError(err, "enabled must be true or false")
+
+	_, err = ParseVariableValue(MissingVariable{Name: "region", Type: schema.VariableTypeString}, "  ")
+	s.EqualError(err, "a value for region is required")
+
+	_, err = ParseVariableValue(MissingVariable{
+		Name:          "region",
+		Type:          schema.VariableTypeString,
+		AllowedValues: []string{"eu-west-1"},
+	}, "us-east-1")
+	s.EqualError(err, "region must be one of: eu-west-1")
+}
+
+func (s *BlueprintVariablesSuite) Test_variables_form_excludes_secrets_from_saved_values() {
+	missing, err := FindMissingVariables(s.blueprintFile, nil)
+	s.Require().NoError(err)
+
+	form := NewVariablesForm(stylespkg.NewDefaultStyles(stylespkg.NewBluelinkPalette()), missing, "deploy.json")
+	form.answers = []string{"hunter2", "eu-west-1", "2"}
+
+	values, err := form.Values()
+	s.Require().NoError(err)
+	s.Equal(map[string]*core.ScalarValue{
+		"databasePassword": core.ScalarFromString("hunter2"),
+		"region":           core.ScalarFromString("eu-west-1"),
+		"replicas":         core.ScalarFromInt(2),
+	}, values)
+
+	savable, err := form.SavableValues()
+	s.Require().NoError(err)
+	s.Nil(savable)
+
+	form.save = true
+	savable, err = form.SavableValues()
+	s.Require().NoError(err)
+	s.Equal(map[string]*core.ScalarValue{
+		"region":   core.ScalarFromString("eu-west-1"),
+		"replicas": core.ScalarFromInt(2),
+	}, savable)
+}
+
+func TestBlueprintVariablesSuite(t *testing.T) {
+	suite.Run(t, new(BlueprintVariablesSuite))
+}
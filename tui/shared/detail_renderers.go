@@ -366,6 +366,9 @@ type StreamingFooterParams struct {
 	HasInstanceState bool
 	StateHintKey     string // e.g., "e" for exports or "s" for pre-destroy state
 	StateHintLabel   string // e.g., "exports" or "pre-destroy state"
+	// Reconnecting is set while the event stream is being resumed
+	// after the connection to the deploy engine was lost.
+	Reconnecting *StreamReconnectAttempt
 }
 
 // RenderStreamingFooter renders a footer for an in-progress streaming operation.
@@ -382,6 +385,10 @@ func RenderStreamingFooter(sb *strings.Builder, params StreamingFooterParams, s
 	}
 	sb.WriteString("\n")
 
+	if params.Reconnecting != nil {
+		RenderReconnectingNotice(sb, *params.Reconnecting, s)
+	}
+
 	if params.ChangesetID != "" {
 		sb.WriteString(s.Muted.Render("  Changeset: "))
 		sb.WriteString(s.Selected.Render(params.ChangesetID))
@@ -0,0 +1,193 @@
+package shared
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/newstack-cloud/deploy-cli-sdk/styles"
+)
+
+const (
+	// DefaultStreamReconnectAttempts is the number of consecutive attempts
+	// made to reconnect to an event stream when a stream reconnect policy
+	// does not set the number of attempts.
+	DefaultStreamReconnectAttempts = 5
+	// DefaultStreamReconnectInitialWait is the wait before the first attempt
+	// to reconnect to an event stream.
+	DefaultStreamReconnectInitialWait = time.Second
+	// DefaultStreamReconnectMaxWait is the upper bound of the wait between
+	// attempts to reconnect to an event stream when a stream reconnect policy
+	// does not set a max wait.
+	DefaultStreamReconnectMaxWait = 30 * time.Second
+)
+
+// StreamReconnectPolicy determines how event streams are resumed when the
+// connection to the deploy engine drops while an operation is in progress.
+type StreamReconnectPolicy struct {
+	// MaxAttempts is the number of consecutive attempts made to reconnect
+	// before the operation is reported as failed, the count is reset
+	// whenever an event is received.
+	// Defaults to DefaultStreamReconnectAttempts,
+	// a negative value disables reconnection.
+	MaxAttempts int
+	// MaxWait is the upper bound of the wait between attempts,
+	// the wait doubles for each attempt starting from
+	// DefaultStreamReconnectInitialWait.
+	// Defaults to DefaultStreamReconnectMaxWait.
+	MaxWait time.Duration
+}
+
+// StreamReconnectAttempt describes a scheduled attempt to reconnect
+// to an event stream.
+type StreamReconnectAttempt struct {
+	Attempt     int
+	MaxAttempts int
+	Wait        time.Duration
+}
+
+// Notice returns a message for the user explaining that the
+// connection was lost and when the stream will be resumed.
+func (a StreamReconnectAttempt) Notice() string {
+	return fmt.Sprintf(
+		"connection to the deploy engine lost, reconnecting in %s (attempt %d of %d)",
+		a.Wait.Round(time.Second),
+		a.Attempt,
+		a.MaxAttempts,
+	)
+}
+
+// StreamResumer tracks the position in an event stream so that the stream
+// can be resumed from the last received event with SSE Last-Event-ID
+// semantics when the connection to the deploy engine drops.
+type StreamResumer struct {
+	policy       StreamReconnectPolicy
+	streamID     string
+	lastEventID  string
+	attempt      StreamReconnectAttempt
+	reconnecting bool
+}
+
+// NewStreamResumer creates a stream resumer for the provided policy.
+func NewStreamResumer(policy StreamReconnectPolicy) StreamResumer {
+	if policy.MaxAttempts == 0 {
+		policy.MaxAttempts = DefaultStreamReconnectAttempts
+	}
+	if policy.MaxWait <= 0 {
+		policy.MaxWait = DefaultStreamReconnectMaxWait
+	}
+	return StreamResumer{policy: policy}
+}
+
+// Start begins tracking the stream for the provided ID (e.g. an instance
+// or change set ID) that starts after the provided event ID,
+// usually the LastEventID of the response that started the operation.
+func (r *StreamResumer) Start(streamID string, lastEventID string) {
+	r.streamID = streamID
+	r.lastEventID = lastEventID
+	r.attempt = StreamReconnectAttempt{}
+	r.reconnecting = false
+}
+
+// Stop ends tracking of the stream, once stopped the stream can not be resumed.
+func (r *StreamResumer) Stop() {
+	r.streamID = ""
+	r.reconnecting = false
+}
+
+// CanResume determines whether a stream is being tracked that can be resumed.
+func (r StreamResumer) CanResume() bool {
+	return r.streamID != ""
+}
+
+// StreamID returns the ID of the stream being tracked.
+func (r StreamResumer) StreamID() string {
+	return r.streamID
+}
+
+// RecordEvent records the ID of an event received from the stream,
+// this resets the number of consecutive attempts to reconnect.
+func (r *StreamResumer) RecordEvent(eventID string) {
+	if eventID != "" {
+		r.lastEventID = eventID
+	}
+	r.attempt = StreamReconnectAttempt{}
+}
+
+// LastEventID returns the ID of the last event received from the stream
+// that the stream should be resumed after.
+func (r StreamResumer) LastEventID() string {
+	return r.lastEventID
+}
+
+// Reconnecting determines whether an attempt to reconnect has been
+// scheduled and the stream has not been resumed yet.
+// Further connection errors for the lost stream should be ignored
+// while reconnecting.
+func (r StreamResumer) Reconnecting() bool {
+	return r.reconnecting
+}
+
+// Resumed marks the stream as resumed once a new connection has been made.
+func (r *StreamResumer) Resumed() {
+	r.reconnecting = false
+}
+
+// Attempt returns the latest attempt to reconnect to the stream.
+func (r StreamResumer) Attempt() StreamReconnectAttempt {
+	return r.attempt
+}
+
+// NextAttempt schedules the next attempt to reconnect to the stream,
+// false is returned when the reconnection budget has been used up
+// or reconnection is disabled.
+func (r *StreamResumer) NextAttempt() (StreamReconnectAttempt, bool) {
+	if r.policy.MaxAttempts < 0 || r.attempt.Attempt >= r.policy.MaxAttempts {
+		return r.attempt, false
+	}
+
+	wait := DefaultStreamReconnectInitialWait
+	for i := 0; i < r.attempt.Attempt && wait < r.policy.MaxWait; i += 1 {
+		wait *= 2
+	}
+
+	r.attempt = StreamReconnectAttempt{
+		Attempt:     r.attempt.Attempt + 1,
+		MaxAttempts: r.policy.MaxAttempts,
+		Wait:        min(wait, r.policy.MaxWait),
+	}
+	r.reconnecting = true
+	return r.attempt, true
+}
+
+// GaveUpError wraps the error that caused the stream to be lost
+// once the reconnection budget has been used up.
+func (r StreamResumer) GaveUpError(err error) error {
+	if r.attempt.Attempt == 0 {
+		return err
+	}
+	return fmt.Errorf(
+		"gave up reconnecting to the event stream after %d attempt(s): %w",
+		r.attempt.Attempt,
+		err,
+	)
+}
+
+// IsStreamConnectionError determines whether an error received from an
+// event stream was caused by the connection to the deploy engine rather
+// than a failure of the operation, in which case the stream can be resumed.
+func IsStreamConnectionError(err error) bool {
+	return engine.IsTransientError(err)
+}
+
+// RenderReconnectingNotice renders a notice that the connection to the deploy
+// engine was lost and the event stream is being resumed.
+func RenderReconnectingNotice(sb *strings.Builder, attempt StreamReconnectAttempt, s *styles.Styles) {
+	sb.WriteString(s.Warning.Render(fmt.Sprintf(
+		"  Reconnecting… (attempt %d of %d)",
+		attempt.Attempt,
+		attempt.MaxAttempts,
+	)))
+	sb.WriteString("\n")
+}
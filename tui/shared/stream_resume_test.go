@@ -0,0 +1,115 @@
+package shared
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	deerrors "github.com/newstack-cloud/bluelink/libs/deploy-engine-client/errors"
+	"github.com/stretchr/testify/suite"
+)
+
+type StreamResumeTestSuite struct {
+	suite.Suite
+}
+
+func TestStreamResumeTestSuite(t *testing.T) {
+	suite.Run(t, new(StreamResumeTestSuite))
+}
+
+func (s *StreamResumeTestSuite) Test_can_not_resume_before_the_stream_is_started() {
+	resumer := NewStreamResumer(StreamReconnectPolicy{})
+	s.False(resumer.CanResume())
+
+	resumer.Start("instance-1", "event-1")
+	s.True(resumer.CanResume())
+	s.Equal("instance-1", resumer.StreamID())
+	s.Equal("event-1", resumer.LastEventID())
+
+	resumer.Stop()
+	s.False(resumer.CanResume())
+}
+
+func (s *StreamResumeTestSuite) Test_backs_off_exponentially_up_to_the_max_wait() {
+	resumer := NewStreamResumer(StreamReconnectPolicy{MaxAttempts: 5, MaxWait: 5 * time.Second})
+	resumer.Start("instance-1", "")
+
+	waits := []time.Duration{}
+	for {
+		attempt, ok := resumer.NextAttempt()
+		if !ok {
+			break
+		}
+		s.True(resumer.Reconnecting())
+		s.Equal(5, attempt.MaxAttempts)
+		waits = append(waits, attempt.Wait)
+		resumer.Resumed()
+	}
+
+	s.Equal([]time.Duration{
+		time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		5 * time.Second,
+		5 * time.Second,
+	}, waits)
+}
+
+func (s *StreamResumeTestSuite) Test_receiving_an_event_resets_the_attempts() {
+	resumer := NewStreamResumer(StreamReconnectPolicy{MaxAttempts: 2})
+	resumer.Start("instance-1", "event-1")
+
+	_, ok := resumer.NextAttempt()
+	s.True(ok)
+	_, ok = resumer.NextAttempt()
+	s.True(ok)
+
+	resumer.Resumed()
+	resumer.RecordEvent("event-2")
+	s.Equal("event-2", resumer.LastEventID())
+	s.Equal(0, resumer.Attempt().Attempt)
+
+	attempt, ok := resumer.NextAttempt()
+	s.True(ok)
+	s.Equal(1, attempt.Attempt)
+}
+
+func (s *StreamResumeTestSuite) Test_events_without_an_id_keep_the_last_event_id() {
+	resumer := NewStreamResumer(StreamReconnectPolicy{})
+	resumer.Start("instance-1", "event-1")
+	resumer.RecordEvent("")
+	s.Equal("event-1", resumer.LastEventID())
+}
+
+func (s *StreamResumeTestSuite) Test_reconnection_can_be_disabled() {
+	resumer := NewStreamResumer(StreamReconnectPolicy{MaxAttempts: -1})
+	resumer.Start("instance-1", "")
+
+	_, ok := resumer.NextAttempt()
+	s.False(ok)
+
+	err := errors.New("stream closed")
+	s.Equal(err, resumer.GaveUpError(err))
+}
+
+func (s *StreamResumeTestSuite) Test_gave_up_error_wraps_the_cause() {
+	resumer := NewStreamResumer(StreamReconnectPolicy{MaxAttempts: 1})
+	resumer.Start("instance-1", "")
+	_, ok := resumer.NextAttempt()
+	s.True(ok)
+	_, ok = resumer.NextAttempt()
+	s.False(ok)
+
+	err := errors.New("stream closed")
+	gaveUpErr := resumer.GaveUpError(err)
+	s.ErrorIs(gaveUpErr, err)
+	s.Contains(gaveUpErr.Error(), "after 1 attempt(s)")
+}
+
+func (s *StreamResumeTestSuite) Test_detects_stream_connection_errors() {
+	s.True(IsStreamConnectionError(&deerrors.ClientError{StatusCode: http.StatusBadGateway}))
+	s.True(IsStreamConnectionError(&deerrors.RequestError{Err: errors.New("connection reset by peer")}))
+	s.False(IsStreamConnectionError(&deerrors.ClientError{StatusCode: http.StatusUnauthorized}))
+	s.False(IsStreamConnectionError(errors.New("deployment failed")))
+}
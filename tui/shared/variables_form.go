@@ -0,0 +1,145 @@
+package shared
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/schema"
+	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
+)
+
+// VariablesForm prompts for values of blueprint variables that have
+// not been provided, with the option to save the answers to the
+// deploy config file.
+type VariablesForm struct {
+	// Form is the themed huh form to run.
+	Form      *huh.Form
+	variables []MissingVariable
+	answers   []string
+	save      bool
+}
+
+// NewVariablesForm creates a form with a field for each missing variable.
+// Variables with allowed values and boolean variables are presented as a
+// selection, all other variables as inputs validated against the type
+// of the variable. Secret variables are masked as they are entered.
+//
+// When saveFile is not empty, the form ends with a confirmation to save the
+// answers to that file, values for secret variables are never saved.
+func NewVariablesForm(
+	styles *stylespkg.Styles,
+	variables []MissingVariable,
+	saveFile string,
+) *VariablesForm {
+	form := &VariablesForm{
+		variables: variables,
+		answers:   make([]string, len(variables)),
+	}
+
+	fields := make([]huh.Field, 0, len(variables))
+	for i, variable := range variables {
+		fields = append(fields, newVariableField(variable, &form.answers[i]))
+	}
+	groups := []*huh.Group{
+		huh.NewGroup(fields...).
+			Title("Missing blueprint variables").
+			Description("The blueprint requires values for the following variables."),
+	}
+
+	if saveFile != "" && form.hasSavableVariables() {
+		groups = append(groups, huh.NewGroup(
+			huh.NewConfirm().
+				Key("saveVariables").
+				Title("Save answers to the deploy config file?").
+				Description(fmt.Sprintf(
+					"Writes the values to %s, values for secret variables are not saved.",
+					saveFile,
+				)).
+				Affirmative("Yes, save").
+				Negative("No").
+				WithButtonAlignment(lipgloss.Left).
+				Value(&form.save),
+		))
+	}
+
+	form.Form = NewThemedForm(styles, groups...)
+	return form
+}
+
+func newVariableField(variable MissingVariable, valuePtr *string) huh.Field {
+	description := fmt.Sprintf("(%s)", variable.Type)
+	if variable.Description != "" {
+		description = fmt.Sprintf("%s %s", variable.Description, description)
+	}
+
+	options := variable.AllowedValues
+	if len(options) == 0 && variable.Type == schema.VariableTypeBoolean {
+		options = []string{"true", "false"}
+	}
+	if len(options) > 0 {
+		return huh.NewSelect[string]().
+			Key(variable.Name).
+			Title(variable.Name).
+			Description(description).
+			Options(huh.NewOptions(options...)...).
+			Value(valuePtr)
+	}
+
+	input := huh.NewInput().
+		Key(variable.Name).
+		Title(variable.Name).
+		Description(description).
+		Value(valuePtr).
+		Validate(func(value string) error {
+			_, err := ParseVariableValue(variable, value)
+			return err
+		})
+	if variable.Secret {
+		input = input.EchoMode(huh.EchoModePassword)
+	}
+	return input
+}
+
+func (f *VariablesForm) hasSavableVariables() bool {
+	for _, variable := range f.variables {
+		if !variable.Secret {
+			return true
+		}
+	}
+	return false
+}
+
+// Values returns the values entered for the missing variables,
+// parsed into scalars of the type of each variable.
+func (f *VariablesForm) Values() (map[string]*core.ScalarValue, error) {
+	values := make(map[string]*core.ScalarValue, len(f.variables))
+	for i, variable := range f.variables {
+		value, err := ParseVariableValue(variable, f.answers[i])
+		if err != nil {
+			return nil, err
+		}
+		values[variable.Name] = value
+	}
+	return values, nil
+}
+
+// SavableValues returns the values entered for the variables that are not
+// secret when the user chose to save the answers, otherwise nil.
+func (f *VariablesForm) SavableValues() (map[string]*core.ScalarValue, error) {
+	if !f.save {
+		return nil, nil
+	}
+
+	values, err := f.Values()
+	if err != nil {
+		return nil, err
+	}
+	for _, variable := range f.variables {
+		if variable.Secret {
+			delete(values, variable.Name)
+		}
+	}
+	return values, nil
+}
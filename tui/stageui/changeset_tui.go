@@ -0,0 +1,125 @@
+package stageui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
+	"go.uber.org/zap"
+)
+
+// ShowChangesetModel is the top-level model for the command that shows
+// a previously staged change set.
+// It loads the change set from the deploy engine and delegates rendering
+// to the stage model so the change set is presented in the same way
+// as the result of a stage command.
+type ShowChangesetModel struct {
+	changesetID string
+	stage       StageModel
+	styles      *stylespkg.Styles
+	quitting    bool
+	Error       error
+}
+
+// Init loads the change set and starts the loading spinner.
+func (m ShowChangesetModel) Init() tea.Cmd {
+	return tea.Batch(m.stage.Init(), m.stage.LoadChangeset(m.changesetID))
+}
+
+// Update handles messages for the show change set model.
+func (m ShowChangesetModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			m.quitting = true
+			return m, tea.Quit
+		case "q":
+			if m.stage.finished && !m.stage.showingExportsView && !m.stage.showingOverview {
+				m.quitting = true
+				return m, tea.Quit
+			}
+		}
+	}
+
+	newStage, cmd := m.stage.Update(msg)
+	stageModel, ok := newStage.(StageModel)
+	if !ok {
+		m.Error = errors.New("internal error: unexpected stage model type")
+		return m, tea.Quit
+	}
+	m.stage = stageModel
+	if stageModel.err != nil {
+		m.Error = stageModel.err
+	}
+
+	return m, cmd
+}
+
+// View renders the show change set model.
+func (m ShowChangesetModel) View() string {
+	if m.quitting {
+		return m.styles.Muted.Margin(1, 0, 2, 4).Render("See you next time.")
+	}
+
+	if !m.stage.headlessMode && !m.stage.finished && m.stage.err == nil {
+		return fmt.Sprintf(
+			"\n  %s Loading change set %s...\n",
+			m.stage.spinner.View(),
+			m.styles.Selected.Render(m.changesetID),
+		)
+	}
+
+	return m.stage.View()
+}
+
+// Stage returns the stage model used to render the change set.
+func (m *ShowChangesetModel) Stage() StageModel {
+	return m.stage
+}
+
+// ShowChangesetAppConfig holds the configuration for creating
+// a new show change set application.
+type ShowChangesetAppConfig struct {
+	// Context is bound to the engine calls the model makes so they are
+	// cancelled when the command context is cancelled (e.g. on Ctrl+C).
+	Context      context.Context
+	DeployEngine engine.DeployEngine
+	Logger       *zap.Logger
+	ChangesetID  string
+	// InstanceName is an optional instance name used in place of the
+	// instance ID in output and suggested commands.
+	InstanceName   string
+	Styles         *stylespkg.Styles
+	Headless       bool
+	HeadlessWriter io.Writer
+	JSONMode       bool
+}
+
+// NewShowChangesetApp creates a new application that renders a previously
+// staged change set with the given configuration.
+func NewShowChangesetApp(cfg ShowChangesetAppConfig) (*ShowChangesetModel, error) {
+	if cfg.ChangesetID == "" {
+		return nil, errors.New("a change set ID must be provided")
+	}
+
+	stage := NewStageModel(StageModelConfig{
+		Context:        cfg.Context,
+		DeployEngine:   cfg.DeployEngine,
+		Logger:         cfg.Logger,
+		InstanceName:   cfg.InstanceName,
+		Styles:         cfg.Styles,
+		IsHeadless:     cfg.Headless,
+		HeadlessWriter: cfg.HeadlessWriter,
+		JSONMode:       cfg.JSONMode,
+	})
+
+	return &ShowChangesetModel{
+		changesetID: cfg.ChangesetID,
+		stage:       stage,
+		styles:      cfg.Styles,
+	}, nil
+}
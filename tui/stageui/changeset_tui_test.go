@@ -0,0 +1,255 @@
+package stageui
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/newstack-cloud/bluelink/libs/blueprint-state/manage"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/changes"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/provider"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
+	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
+	"github.com/newstack-cloud/deploy-cli-sdk/testutils"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+type ShowChangesetTestSuite struct {
+	suite.Suite
+	styles *stylespkg.Styles
+}
+
+func TestShowChangesetTestSuite(t *testing.T) {
+	suite.Run(t, new(ShowChangesetTestSuite))
+}
+
+func (s *ShowChangesetTestSuite) SetupTest() {
+	s.styles = stylespkg.NewStyles(
+		lipgloss.NewRenderer(os.Stdout),
+		stylespkg.NewBluelinkPalette(),
+	)
+}
+
+func (s *ShowChangesetTestSuite) newApp(
+	deployEngine engine.DeployEngine,
+	isHeadless bool,
+	output *bytes.Buffer,
+	jsonMode bool,
+) ShowChangesetModel {
+	app, err := NewShowChangesetApp(ShowChangesetAppConfig{
+		DeployEngine:   deployEngine,
+		Logger:         zap.NewNop(),
+		ChangesetID:    "changeset-123",
+		Styles:         s.styles,
+		Headless:       isHeadless,
+		HeadlessWriter: output,
+		JSONMode:       jsonMode,
+	})
+	s.Require().NoError(err)
+	return *app
+}
+
+func (s *ShowChangesetTestSuite) runHeadless(app ShowChangesetModel) ShowChangesetModel {
+	testModel := teatest.NewTestModel(
+		s.T(),
+		app,
+		teatest.WithInitialTermSize(300, 100),
+	)
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+	return testModel.FinalModel(s.T()).(ShowChangesetModel)
+}
+
+func (s *ShowChangesetTestSuite) Test_headless_prints_changeset_summary() {
+	output := &bytes.Buffer{}
+	finalApp := s.runHeadless(s.newApp(
+		testChangesetEngine(manage.ChangesetStatusChangesStaged),
+		true,
+		output,
+		false,
+	))
+
+	s.Nil(finalApp.Error)
+	text := output.String()
+	s.Contains(text, "Changeset: changeset-123")
+	s.Contains(text, "Blueprint: app.blueprint.yaml")
+	s.Contains(text, "newQueue")
+	s.Contains(text, "existingTable")
+	s.Contains(text, "oldBucket")
+	s.Contains(text, "newQueue::existingTable")
+	s.Contains(text, "Actions: 2 create, 1 update, 1 delete, 0 recreate, 0 retain")
+	s.Contains(text, "deploy --changeset-id changeset-123 --instance-id instance-123")
+}
+
+func (s *ShowChangesetTestSuite) Test_json_output_uses_stage_output_shape() {
+	output := &bytes.Buffer{}
+	finalApp := s.runHeadless(s.newApp(
+		testChangesetEngine(manage.ChangesetStatusChangesStaged),
+		true,
+		output,
+		true,
+	))
+	s.Nil(finalApp.Error)
+
+	var result jsonout.StageOutput
+	s.Require().NoError(json.Unmarshal(output.Bytes(), &result))
+	s.True(result.Success)
+	s.Equal("changeset-123", result.ChangesetID)
+	s.Equal("instance-123", result.InstanceID)
+	s.Require().NotNil(result.Changes)
+	s.Contains(result.Changes.NewResources, "newQueue")
+	s.Equal(2, result.Summary.Resources.Total)
+	s.Equal(1, result.Summary.Resources.Create)
+	s.Equal(1, result.Summary.Resources.Update)
+	s.Equal(1, result.Summary.Links.Create)
+	s.Equal(1, result.Summary.Links.Delete)
+}
+
+func (s *ShowChangesetTestSuite) Test_json_error_when_changeset_still_staging() {
+	output := &bytes.Buffer{}
+	finalApp := s.runHeadless(s.newApp(
+		testChangesetEngine(manage.ChangesetStatusStagingChanges),
+		true,
+		output,
+		true,
+	))
+	s.Require().Error(finalApp.Error)
+
+	var result jsonout.ErrorOutput
+	s.Require().NoError(json.Unmarshal(output.Bytes(), &result))
+	s.False(result.Success)
+	s.Contains(result.Error.Message, "still staging changes")
+}
+
+func (s *ShowChangesetTestSuite) Test_headless_error_when_changeset_cannot_be_fetched() {
+	output := &bytes.Buffer{}
+	finalApp := s.runHeadless(s.newApp(
+		testutils.NewScenarioBuilder().Build(),
+		true,
+		output,
+		false,
+	))
+
+	s.Require().Error(finalApp.Error)
+	s.Contains(output.String(), `change set "changeset-123" not found`)
+}
+
+func (s *ShowChangesetTestSuite) Test_interactive_renders_changes_in_split_pane() {
+	app := s.newApp(
+		testChangesetEngine(manage.ChangesetStatusChangesStaged),
+		false,
+		nil,
+		false,
+	)
+
+	testModel := teatest.NewTestModel(
+		s.T(),
+		app,
+		teatest.WithInitialTermSize(300, 100),
+	)
+
+	testutils.WaitForContainsAll(
+		s.T(),
+		testModel.Output(),
+		"Change Staging",
+		"newQueue",
+		"existingTable",
+	)
+
+	testutils.KeyQ(testModel)
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+
+	finalApp := testModel.FinalModel(s.T()).(ShowChangesetModel)
+	s.Nil(finalApp.Error)
+	stage := finalApp.Stage()
+	s.True(stage.Finished())
+	s.Equal("changeset-123", stage.ChangesetID())
+}
+
+func (s *ShowChangesetTestSuite) Test_new_show_changeset_app_requires_changeset_id() {
+	_, err := NewShowChangesetApp(ShowChangesetAppConfig{
+		DeployEngine: testutils.NewScenarioBuilder().Build(),
+		Logger:       zap.NewNop(),
+		Styles:       s.styles,
+	})
+	s.Error(err)
+}
+
+func (s *ShowChangesetTestSuite) Test_buildItemsFromChanges_orders_items_by_type_and_name() {
+	items := buildItemsFromChanges(testStagedChanges(), testChangesetInstanceState())
+
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, string(item.Type)+":"+item.Name)
+	}
+	s.Equal([]string{
+		"resource:existingTable",
+		"resource:newQueue",
+		"link:existingTable::oldBucket",
+		"link:newQueue::existingTable",
+	}, names)
+}
+
+// testChangesetEngine creates a deploy engine with the changeset-123
+// change set for the my-app instance in the provided status.
+func testChangesetEngine(status manage.ChangesetStatus) engine.DeployEngine {
+	builder := testutils.NewScenarioBuilder()
+	builder.Instance("my-app").
+		WithID("instance-123").
+		WithState(testChangesetInstanceState()).
+		Stage(&testutils.StageScenario{
+			ChangesetID:       "changeset-123",
+			Changes:           testStagedChanges(),
+			Status:            status,
+			BlueprintLocation: "app.blueprint.yaml",
+		})
+	return builder.Build()
+}
+
+func testStagedChanges() *changes.BlueprintChanges {
+	return &changes.BlueprintChanges{
+		NewResources: map[string]provider.Changes{
+			"newQueue": {
+				NewOutboundLinks: map[string]provider.LinkChanges{
+					"existingTable": {},
+				},
+			},
+		},
+		ResourceChanges: map[string]provider.Changes{
+			"existingTable": {
+				ModifiedFields: []provider.FieldChange{
+					{
+						FieldPath: "spec.billingMode",
+						PrevValue: core.MappingNodeFromString("PROVISIONED"),
+						NewValue:  core.MappingNodeFromString("PAY_PER_REQUEST"),
+					},
+				},
+			},
+		},
+		RemovedLinks: []string{"existingTable::oldBucket"},
+	}
+}
+
+func testChangesetInstanceState() *state.InstanceState {
+	return &state.InstanceState{
+		InstanceID:   "instance-123",
+		InstanceName: "my-app",
+		ResourceIDs: map[string]string{
+			"existingTable": "resource-table-1",
+		},
+		Resources: map[string]*state.ResourceState{
+			"resource-table-1": {
+				ResourceID: "resource-table-1",
+				Name:       "existingTable",
+				Type:       "aws/dynamodb/table",
+			},
+		},
+	}
+}
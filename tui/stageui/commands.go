@@ -2,12 +2,14 @@ package stageui
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/newstack-cloud/bluelink/libs/blueprint/changes"
 	"github.com/newstack-cloud/bluelink/libs/blueprint/container"
 	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/bluelink/libs/blueprint-state/manage"
 	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
 	"github.com/newstack-cloud/deploy-cli-sdk/tui/driftui"
 	"github.com/newstack-cloud/deploy-cli-sdk/tui/shared"
@@ -19,7 +21,19 @@ type StageEventMsg types.ChangeStagingEvent
 
 // StageStreamClosedMsg is sent when the staging event stream is closed.
 // This typically happens due to a stream timeout or the connection being dropped.
-type StageStreamClosedMsg struct{}
+type StageStreamClosedMsg struct {
+	// stream is the event channel that was closed, used to ignore
+	// channels that have been replaced by resuming the stream.
+	stream chan types.ChangeStagingEvent
+}
+
+// StageStreamReconnectMsg is sent when it is time to resume the staging
+// event stream after the connection to the deploy engine was lost.
+type StageStreamReconnectMsg struct{}
+
+// StageStreamResumedMsg is sent when the staging event stream has been
+// resumed from the last received event.
+type StageStreamResumedMsg struct{}
 
 // StageErrorMsg is a message containing an error from the staging process.
 type StageErrorMsg struct {
@@ -29,6 +43,7 @@ type StageErrorMsg struct {
 // StageStartedMsg is a message indicating that staging has started.
 type StageStartedMsg struct {
 	ChangesetID string
+	LastEventID string
 }
 
 // StageCompleteMsg is a message indicating that staging has completed.
@@ -40,11 +55,70 @@ type StageCompleteMsg struct {
 	InstanceState *state.InstanceState // Pre-deployment instance state for unchanged items
 }
 
+// ChangesetLoadedMsg is sent when a previously staged change set has been
+// fetched from the deploy engine along with the current state of the instance
+// the change set is for (if any).
+type ChangesetLoadedMsg struct {
+	Changeset     *manage.Changeset
+	InstanceState *state.InstanceState
+}
+
 // InstanceStateFetchedMsg is sent when instance state has been successfully fetched.
 type InstanceStateFetchedMsg struct {
 	InstanceState *state.InstanceState
 }
 
+func loadChangesetCmd(model StageModel, changesetID string) tea.Cmd {
+	return func() tea.Msg {
+		changeset, err := model.engine.GetChangeset(model.reqCtx(), changesetID)
+		if err != nil {
+			return StageErrorMsg{Err: err}
+		}
+
+		if err := checkChangesetStaged(changeset); err != nil {
+			return StageErrorMsg{Err: err}
+		}
+
+		instanceState := stateutil.FetchInstanceState(
+			model.reqCtx(),
+			model.engine,
+			changeset.InstanceID,
+			model.instanceName,
+		)
+
+		return ChangesetLoadedMsg{
+			Changeset:     changeset,
+			InstanceState: instanceState,
+		}
+	}
+}
+
+// checkChangesetStaged makes sure that change staging has completed
+// successfully for a change set before it is rendered.
+func checkChangesetStaged(changeset *manage.Changeset) error {
+	switch changeset.Status {
+	case manage.ChangesetStatusChangesStaged:
+		return nil
+	case manage.ChangesetStatusStarting, manage.ChangesetStatusStagingChanges:
+		return fmt.Errorf(
+			"change set %q is still staging changes, try again once change staging has completed",
+			changeset.ID,
+		)
+	case manage.ChangesetStatusDriftDetected:
+		return fmt.Errorf(
+			"change staging for change set %q was blocked by drift, "+
+				"reconcile the instance and stage the changes again",
+			changeset.ID,
+		)
+	default:
+		return fmt.Errorf(
+			"change set %q has no staged changes to show (status: %s)",
+			changeset.ID,
+			changeset.Status,
+		)
+	}
+}
+
 func startStagingCmd(model StageModel) tea.Cmd {
 	return func() tea.Msg {
 		// Fetch instance state if we have an instance ID or name
@@ -81,6 +155,7 @@ func startStagingCmd(model StageModel) tea.Cmd {
 		// Return both the changeset ID and instance state
 		return StageStartedWithStateMsg{
 			ChangesetID:   response.Data.ID,
+			LastEventID:   response.LastEventID,
 			InstanceState: instanceState,
 		}
 	}
@@ -90,6 +165,7 @@ func startStagingCmd(model StageModel) tea.Cmd {
 // and includes the fetched instance state (if available).
 type StageStartedWithStateMsg struct {
 	ChangesetID   string
+	LastEventID   string
 	InstanceState *state.InstanceState
 }
 
@@ -113,12 +189,38 @@ func waitForNextEventCmd(model StageModel) tea.Cmd {
 	return func() tea.Msg {
 		event, ok := <-model.eventStream
 		if !ok {
-			return StageStreamClosedMsg{}
+			return StageStreamClosedMsg{stream: model.eventStream}
 		}
 		return StageEventMsg(event)
 	}
 }
 
+// reconnectStagingStreamCmd waits before resuming the staging event stream.
+func reconnectStagingStreamCmd(wait time.Duration) tea.Cmd {
+	return tea.Tick(wait, func(time.Time) tea.Msg {
+		return StageStreamReconnectMsg{}
+	})
+}
+
+// resumeStagingStreamCmd resumes the staging event stream from the last
+// received event.
+func resumeStagingStreamCmd(model StageModel) tea.Cmd {
+	return func() tea.Msg {
+		err := model.engine.StreamChangeStagingEvents(
+			model.reqCtx(),
+			model.streamResumer.StreamID(),
+			model.streamResumer.LastEventID(),
+			model.eventStream,
+			model.errStream,
+		)
+		if err != nil {
+			return StageErrorMsg{Err: err}
+		}
+
+		return StageStreamResumedMsg{}
+	}
+}
+
 func checkForErrCmd(model StageModel) tea.Cmd {
 	return func() tea.Msg {
 		var err error
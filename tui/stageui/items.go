@@ -1,6 +1,8 @@
 package stageui
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -382,6 +384,116 @@ func appendNoChangeItemsFromState(
 	return items
 }
 
+// buildItemsFromChanges builds the top-level stage items from the complete
+// changes of a change set.
+// This is used when rendering a previously staged change set where the
+// individual resource, child and link change events are not available.
+func buildItemsFromChanges(
+	bc *changes.BlueprintChanges,
+	instanceState *state.InstanceState,
+) []StageItem {
+	if bc == nil {
+		return []StageItem{}
+	}
+
+	ctx := childItemContext{instanceState: instanceState}
+	addedResources := make(map[string]bool)
+	addedChildren := make(map[string]bool)
+
+	var splitPaneItems []splitpane.Item
+	splitPaneItems = appendResourceItems(splitPaneItems, bc, ctx, addedResources)
+	splitPaneItems = appendChildItems(splitPaneItems, bc, ctx, addedChildren)
+	splitPaneItems = appendNoChangeItemsFromState(splitPaneItems, ctx, addedResources, addedChildren)
+
+	items := make([]StageItem, 0, len(splitPaneItems))
+	for _, item := range splitPaneItems {
+		if stageItem, ok := item.(*StageItem); ok {
+			items = append(items, *stageItem)
+		}
+	}
+	items = appendLinkItemsFromChanges(items, bc, instanceState)
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Type != items[j].Type {
+			return itemTypeOrder(items[i].Type) < itemTypeOrder(items[j].Type)
+		}
+		return items[i].Name < items[j].Name
+	})
+
+	return items
+}
+
+// appendLinkItemsFromChanges derives link items from the outbound link changes
+// of new and changed resources along with the links that will be removed.
+func appendLinkItemsFromChanges(
+	items []StageItem,
+	bc *changes.BlueprintChanges,
+	instanceState *state.InstanceState,
+) []StageItem {
+	for resourceAName, rc := range bc.NewResources {
+		for resourceBName, linkChanges := range rc.NewOutboundLinks {
+			items = append(items, newLinkItem(resourceAName, resourceBName, linkChanges, ActionCreate, instanceState))
+		}
+	}
+
+	for resourceAName, rc := range bc.ResourceChanges {
+		for resourceBName, linkChanges := range rc.NewOutboundLinks {
+			items = append(items, newLinkItem(resourceAName, resourceBName, linkChanges, ActionCreate, instanceState))
+		}
+		for resourceBName, linkChanges := range rc.OutboundLinkChanges {
+			items = append(items, newLinkItem(resourceAName, resourceBName, linkChanges, ActionUpdate, instanceState))
+		}
+	}
+
+	for _, linkName := range bc.RemovedLinks {
+		items = append(items, StageItem{
+			Type:      ItemTypeLink,
+			Name:      linkName,
+			Action:    ActionDelete,
+			Removed:   true,
+			LinkState: findLinkState(instanceState, linkName),
+		})
+	}
+
+	return items
+}
+
+func newLinkItem(
+	resourceAName string,
+	resourceBName string,
+	linkChanges provider.LinkChanges,
+	action ActionType,
+	instanceState *state.InstanceState,
+) StageItem {
+	linkName := fmt.Sprintf("%s::%s", resourceAName, resourceBName)
+	return StageItem{
+		Type:      ItemTypeLink,
+		Name:      linkName,
+		Action:    action,
+		Changes:   &linkChanges,
+		New:       action == ActionCreate,
+		LinkState: findLinkState(instanceState, linkName),
+	}
+}
+
+func findLinkState(instanceState *state.InstanceState, linkName string) *state.LinkState {
+	if instanceState == nil || instanceState.Links == nil {
+		return nil
+	}
+	return instanceState.Links[linkName]
+}
+
+func itemTypeOrder(itemType ItemType) int {
+	switch itemType {
+	case ItemTypeResource:
+		return 0
+	case ItemTypeChild:
+		return 1
+	default:
+		return 2
+	}
+}
+
 // ToSplitPaneItems converts a slice of StageItems to splitpane.Items.
 func ToSplitPaneItems(items []StageItem) []splitpane.Item {
 	result := make([]splitpane.Item, len(items))
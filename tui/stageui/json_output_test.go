@@ -75,6 +75,172 @@ func (s *JSONOutputTestSuite) Test_outputJSON_includes_changeset_id() {
 	s.Equal("test-instance", output.InstanceName)
 }
 
+func (s *JSONOutputTestSuite) Test_outputJSON_includes_engine_context() {
+	jsonOutput := &bytes.Buffer{}
+	model := NewStageModel(StageModelConfig{
+		DeployEngine: testutils.NewTestDeployEngineWithStaging(
+			[]*types.ChangeStagingEvent{
+				resourceCreateEvent("test-resource"),
+				completeChangesEvent(),
+			},
+			"test-changeset-context",
+		),
+		Logger:         zap.NewNop(),
+		InstanceName:   "test-instance",
+		Styles:         stylespkg.NewStyles(lipgloss.NewRenderer(os.Stdout), stylespkg.NewBluelinkPalette()),
+		IsHeadless:     true,
+		HeadlessWriter: jsonOutput,
+		JSONMode:       true,
+		EngineContext:  "prod",
+	})
+
+	testModel := teatest.NewTestModel(
+		s.T(),
+		model,
+		teatest.WithInitialTermSize(300, 100),
+	)
+
+	testModel.Send(sharedui.SelectBlueprintMsg{
+		BlueprintFile: "test.blueprint.yaml",
+		Source:        consts.BlueprintSourceFile,
+	})
+
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+
+	var output jsonout.StageOutput
+	err := json.Unmarshal(jsonOutput.Bytes(), &output)
+	s.Require().NoError(err)
+	s.Equal("prod", output.Context)
+}
+
+func (s *JSONOutputTestSuite) Test_outputJSON_includes_variables() {
+	jsonOutput := &bytes.Buffer{}
+	variables := []jsonout.VariableValue{
+		{Name: "region", Kind: "blueprint", Value: "eu-west-2", Source: "flag"},
+		{Name: "apiSecret", Kind: "blueprint", Value: "********", Source: "var file", SourceName: "vars.yaml", Redacted: true},
+	}
+	model := NewStageModel(StageModelConfig{
+		DeployEngine: testutils.NewTestDeployEngineWithStaging(
+			[]*types.ChangeStagingEvent{
+				resourceCreateEvent("test-resource"),
+				completeChangesEvent(),
+			},
+			"test-changeset-variables",
+		),
+		Logger:         zap.NewNop(),
+		InstanceName:   "test-instance",
+		Styles:         stylespkg.NewStyles(lipgloss.NewRenderer(os.Stdout), stylespkg.NewBluelinkPalette()),
+		IsHeadless:     true,
+		HeadlessWriter: jsonOutput,
+		JSONMode:       true,
+		Variables:      variables,
+	})
+
+	testModel := teatest.NewTestModel(
+		s.T(),
+		model,
+		teatest.WithInitialTermSize(300, 100),
+	)
+
+	testModel.Send(sharedui.SelectBlueprintMsg{
+		BlueprintFile: "test.blueprint.yaml",
+		Source:        consts.BlueprintSourceFile,
+	})
+
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+
+	var output jsonout.StageOutput
+	err := json.Unmarshal(jsonOutput.Bytes(), &output)
+	s.Require().NoError(err)
+	s.Equal(variables, output.Variables)
+}
+
+func (s *JSONOutputTestSuite) Test_outputJSONError_includes_engine_context() {
+	jsonOutput := &bytes.Buffer{}
+	model := NewStageModel(StageModelConfig{
+		DeployEngine: testutils.NewTestDeployEngineWithStagingError(
+			&engineerrors.ClientError{Message: "Unauthorized", StatusCode: 401},
+		),
+		Logger:         zap.NewNop(),
+		InstanceName:   "test-instance",
+		Styles:         stylespkg.NewStyles(lipgloss.NewRenderer(os.Stdout), stylespkg.NewBluelinkPalette()),
+		IsHeadless:     true,
+		HeadlessWriter: jsonOutput,
+		JSONMode:       true,
+		EngineContext:  "prod",
+	})
+
+	testModel := teatest.NewTestModel(
+		s.T(),
+		model,
+		teatest.WithInitialTermSize(300, 100),
+	)
+
+	testModel.Send(sharedui.SelectBlueprintMsg{
+		BlueprintFile: "test.blueprint.yaml",
+		Source:        consts.BlueprintSourceFile,
+	})
+
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+
+	var output jsonout.ErrorOutput
+	err := json.Unmarshal(jsonOutput.Bytes(), &output)
+	s.Require().NoError(err)
+	s.False(output.Success)
+	s.Equal("prod", output.Context)
+}
+
+func (s *JSONOutputTestSuite) Test_ndjson_stream_writes_events_followed_by_result() {
+	ndjsonOutput := &bytes.Buffer{}
+	model := NewStageModel(StageModelConfig{
+		DeployEngine: testutils.NewTestDeployEngineWithStaging(
+			[]*types.ChangeStagingEvent{
+				resourceCreateEvent("test-resource"),
+				completeChangesEvent(),
+			},
+			"test-changeset-ndjson",
+		),
+		Logger:         zap.NewNop(),
+		InstanceName:   "test-instance",
+		Styles:         stylespkg.NewStyles(lipgloss.NewRenderer(os.Stdout), stylespkg.NewBluelinkPalette()),
+		IsHeadless:     true,
+		HeadlessWriter: ndjsonOutput,
+		JSONMode:       true,
+		NDJSONStream:   jsonout.NewEventStream(ndjsonOutput),
+	})
+
+	testModel := teatest.NewTestModel(
+		s.T(),
+		model,
+		teatest.WithInitialTermSize(300, 100),
+	)
+
+	testModel.Send(sharedui.SelectBlueprintMsg{
+		BlueprintFile: "test.blueprint.yaml",
+		Source:        consts.BlueprintSourceFile,
+	})
+
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+
+	lines := bytes.Split(bytes.TrimSpace(ndjsonOutput.Bytes()), []byte("\n"))
+	s.Require().Len(lines, 3)
+
+	var resourceEvent map[string]any
+	s.Require().NoError(json.Unmarshal(lines[0], &resourceEvent))
+	s.Equal("resourceChanges", resourceEvent["event"])
+	s.Equal("stage", resourceEvent["operation"])
+	s.Equal("test-resource", resourceEvent["data"].(map[string]any)["resourceName"])
+
+	var completeEvent jsonout.StreamEvent
+	s.Require().NoError(json.Unmarshal(lines[1], &completeEvent))
+	s.Equal("changeStagingComplete", completeEvent.Event)
+
+	var output jsonout.StageOutput
+	s.Require().NoError(json.Unmarshal(lines[2], &output))
+	s.True(output.Success)
+	s.Equal("test-changeset-ndjson", output.ChangesetID)
+}
+
 func (s *JSONOutputTestSuite) Test_outputJSON_includes_resource_summary() {
 	jsonOutput := &bytes.Buffer{}
 	events := []*types.ChangeStagingEvent{
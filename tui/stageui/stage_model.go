@@ -20,6 +20,7 @@ import (
 	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
 	"github.com/newstack-cloud/deploy-cli-sdk/engine"
 	"github.com/newstack-cloud/deploy-cli-sdk/headless"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
 	sdkstrings "github.com/newstack-cloud/deploy-cli-sdk/strings"
 	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
 	"github.com/newstack-cloud/deploy-cli-sdk/tui/driftui"
@@ -119,6 +120,9 @@ type StageModel struct {
 	engine      engine.DeployEngine
 	eventStream chan types.ChangeStagingEvent
 	errStream   chan error
+	// streamResumer tracks the last received event so the stream
+	// can be resumed when the connection to the engine drops.
+	streamResumer shared.StreamResumer
 
 	// Config
 	blueprintFile   string
@@ -142,6 +146,13 @@ type StageModel struct {
 
 	// JSON output mode
 	jsonMode bool
+	// ndjsonStream is set when streaming events as NDJSON,
+	// each staging event is written as it is received.
+	ndjsonStream *jsonout.EventStream
+	// engineContext is the name of the selected engine context, if any.
+	engineContext string
+	// variables are the variable values provided on the command line.
+	variables []jsonout.VariableValue
 
 	// Drift review state
 	driftReviewMode bool
@@ -223,6 +234,11 @@ func (m StageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m, startedCmds = m.handleStageStartedWithStateMsg(msg)
 		cmds = append(cmds, startedCmds...)
 
+	case ChangesetLoadedMsg:
+		var loadedCmds []tea.Cmd
+		m, loadedCmds = m.handleChangesetLoadedMsg(msg)
+		cmds = append(cmds, loadedCmds...)
+
 	case StageEventMsg:
 		var eventCmds []tea.Cmd
 		m, eventCmds = m.handleStageEventMsg(msg)
@@ -237,11 +253,23 @@ func (m StageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case StageStreamClosedMsg:
 		var cmd tea.Cmd
-		m, cmd = m.handleStageStreamClosedMsg()
+		m, cmd = m.handleStageStreamClosedMsg(msg)
+		if cmd != nil {
+			return m, cmd
+		}
+
+	case StageStreamReconnectMsg:
+		var cmd tea.Cmd
+		m, cmd = m.handleStageStreamReconnectMsg()
 		if cmd != nil {
 			return m, cmd
 		}
 
+	case StageStreamResumedMsg:
+		var resumedCmds []tea.Cmd
+		m, resumedCmds = m.handleStageStreamResumedMsg()
+		cmds = append(cmds, resumedCmds...)
+
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
@@ -835,6 +863,11 @@ func (m StageModel) renderStreamingView() string {
 	sb.WriteString("\n")
 	fmt.Fprintf(&sb, "  %s Staging changes...\n\n", m.spinner.View())
 
+	if m.streamResumer.Reconnecting() {
+		shared.RenderReconnectingNotice(&sb, m.streamResumer.Attempt(), m.styles)
+		sb.WriteString("\n")
+	}
+
 	if m.changesetID != "" {
 		fmt.Fprintf(&sb, "  Changeset: %s\n\n", m.styles.Selected.Render(m.changesetID))
 	}
@@ -1071,9 +1104,23 @@ type StageModelConfig struct {
 	IsHeadless     bool
 	HeadlessWriter io.Writer
 	JSONMode       bool
+	// NDJSONStream is an optional stream that staging events are written to
+	// as NDJSON, when set the final result is written to the stream
+	// instead of as a pretty-printed JSON object.
+	// This is only used in JSON mode.
+	NDJSONStream *jsonout.EventStream
+	// EngineContext is the name of the engine context the operation runs
+	// against, shown in the header and included in JSON output.
+	EngineContext string
+	// Variables are the blueprint and context variable values provided
+	// on the command line, included in JSON output.
+	Variables []jsonout.VariableValue
 	// OperationConfig carries provider/transformer/context-variable values
 	// (including the deploy target) sent to the engine in the changeset payload.
 	OperationConfig *types.BlueprintOperationConfig
+	// StreamReconnect determines how the staging event stream is resumed
+	// when the connection to the deploy engine drops.
+	StreamReconnect shared.StreamReconnectPolicy
 }
 
 // Returns the model's bound context, defaulting to context.Background()
@@ -1113,6 +1160,7 @@ func NewStageModel(cfg StageModelConfig) StageModel {
 		Styles:          cfg.Styles,
 		DetailsRenderer: detailsRenderer,
 		Title:           "Change Staging",
+		Subtitle:        shared.EngineContextSubtitle(cfg.EngineContext),
 		LeftPaneRatio:   0.4,
 		MaxExpandDepth:  MaxExpandDepth,
 		SectionGrouper:  sectionGrouper,
@@ -1138,6 +1186,7 @@ func NewStageModel(cfg StageModelConfig) StageModel {
 		Styles:          cfg.Styles,
 		DetailsRenderer: driftDetailsRenderer,
 		Title:           "⚠ Drift Detected",
+		Subtitle:        shared.EngineContextSubtitle(cfg.EngineContext),
 		LeftPaneRatio:   0.4,
 		MaxExpandDepth:  MaxExpandDepth,
 		SectionGrouper:  driftSectionGrouper,
@@ -1173,9 +1222,13 @@ func NewStageModel(cfg StageModelConfig) StageModel {
 		headlessWriter:       cfg.HeadlessWriter,
 		printer:              printer,
 		jsonMode:             cfg.JSONMode,
+		ndjsonStream:         cfg.NDJSONStream,
+		engineContext:        cfg.EngineContext,
+		variables:            cfg.Variables,
 		spinner:              s,
 		eventStream:          make(chan types.ChangeStagingEvent),
 		errStream:            make(chan error),
+		streamResumer:        shared.NewStreamResumer(cfg.StreamReconnect),
 		resourceChanges:      make(map[string]*ResourceChangeState),
 		childChanges:         make(map[string]*ChildChangeState),
 		linkChanges:          make(map[string]*LinkChangeState),
@@ -1325,6 +1378,17 @@ func (m *StageModel) StartStaging() tea.Cmd {
 	return startStagingCmd(*m)
 }
 
+// LoadChangeset fetches a previously staged change set so it can be rendered
+// in the same way as the result of a staging run.
+// Returns a tea.Cmd that loads the change set.
+func (m *StageModel) LoadChangeset(changesetID string) tea.Cmd {
+	if m.streaming {
+		return nil
+	}
+	m.streaming = true
+	return loadChangesetCmd(*m, changesetID)
+}
+
 // Test accessor methods - these provide read-only access for testing purposes.
 
 // Err returns the error stored in the model.
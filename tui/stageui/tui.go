@@ -7,11 +7,14 @@ import (
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
 	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
 	"github.com/newstack-cloud/deploy-cli-sdk/consts"
 	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
 	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
 	"github.com/newstack-cloud/deploy-cli-sdk/tui/preflight"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/shared"
 	sharedui "github.com/newstack-cloud/deploy-cli-sdk/ui"
 	"go.uber.org/zap"
 )
@@ -243,10 +246,22 @@ type StageAppConfig struct {
 	Headless               bool
 	HeadlessWriter         io.Writer
 	JSONMode               bool
-	Preflight              tea.Model
+	// NDJSONStream is an optional stream that staging events and the final
+	// result are written to as NDJSON in JSON mode.
+	NDJSONStream *jsonout.EventStream
+	// EngineContext is the name of the engine context the operation runs
+	// against, shown in the header and included in JSON output.
+	EngineContext string
+	// Variables are the blueprint and context variable values provided
+	// on the command line, included in JSON output.
+	Variables []jsonout.VariableValue
+	Preflight tea.Model
 	// OperationConfig carries provider/transformer/context-variable values
 	// (including the deploy target) sent to the engine during change staging.
 	OperationConfig *types.BlueprintOperationConfig
+	// StreamReconnect determines how event streams are resumed
+	// when the connection to the deploy engine drops.
+	StreamReconnect shared.StreamReconnectPolicy
 }
 
 // NewStageApp creates a new stage application with the given configuration.
@@ -294,7 +309,11 @@ func NewStageApp(cfg StageAppConfig) (*MainModel, error) {
 		IsHeadless:      cfg.Headless,
 		HeadlessWriter:  cfg.HeadlessWriter,
 		JSONMode:        cfg.JSONMode,
+		NDJSONStream:    cfg.NDJSONStream,
+		EngineContext:   cfg.EngineContext,
+		Variables:       cfg.Variables,
 		OperationConfig: cfg.OperationConfig,
+		StreamReconnect: cfg.StreamReconnect,
 	})
 
 	// Determine if we need to prompt for stage options
@@ -327,6 +346,31 @@ func NewStageApp(cfg StageAppConfig) (*MainModel, error) {
 	}, nil
 }
 
+// StageResult holds the outcome of a completed staging run.
+type StageResult struct {
+	Output        jsonout.StageOutput
+	BlueprintFile string
+	// InstanceState is the state of the instance when staging started,
+	// this is nil for new deployments.
+	InstanceState *state.InstanceState
+	Destroy       bool
+}
+
+// Result returns the outcome of the staging run.
+// This is nil if staging did not complete successfully.
+func (m *MainModel) Result() *StageResult {
+	stageModel, ok := m.stage.(StageModel)
+	if !ok || m.Error != nil || !stageModel.finished || stageModel.err != nil {
+		return nil
+	}
+	return &StageResult{
+		Output:        stageModel.StageOutput(),
+		BlueprintFile: m.blueprintFile,
+		InstanceState: stageModel.instanceState,
+		Destroy:       stageModel.destroy,
+	}
+}
+
 // Test accessor methods - these provide read-only access for testing purposes.
 
 // Stage returns the stage model (as tea.Model interface).
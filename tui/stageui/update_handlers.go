@@ -1,11 +1,13 @@
 package stageui
 
 import (
-	"fmt"
+	"errors"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
 	"github.com/newstack-cloud/deploy-cli-sdk/tui/driftui"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/shared"
 	sharedui "github.com/newstack-cloud/deploy-cli-sdk/ui"
 	"github.com/newstack-cloud/deploy-cli-sdk/ui/splitpane"
 )
@@ -58,6 +60,7 @@ func (m StageModel) handleStageStartedMsg(msg StageStartedMsg) (StageModel, []te
 	}
 
 	m.changesetID = msg.ChangesetID
+	m.streamResumer.Start(msg.ChangesetID, msg.LastEventID)
 	m.footerRenderer.ChangesetID = msg.ChangesetID
 	if m.headlessMode && !m.jsonMode {
 		m.printHeadlessHeader()
@@ -71,6 +74,7 @@ func (m StageModel) handleStageStartedWithStateMsg(msg StageStartedWithStateMsg)
 	}
 
 	m.changesetID = msg.ChangesetID
+	m.streamResumer.Start(msg.ChangesetID, msg.LastEventID)
 	m.instanceState = msg.InstanceState
 	m.footerRenderer.ChangesetID = msg.ChangesetID
 	if m.headlessMode && !m.jsonMode {
@@ -86,6 +90,8 @@ func (m StageModel) handleStageEventMsg(msg StageEventMsg) (StageModel, []tea.Cm
 
 	var cmds []tea.Cmd
 	event := types.ChangeStagingEvent(msg)
+	m.streamResumer.RecordEvent(event.ID)
+	m.ndjsonStream.WriteChangeStagingEvent(jsonout.OperationStage, &event)
 	m.processEvent(&event)
 	cmds = append(cmds, checkForErrCmd(m))
 
@@ -106,6 +112,7 @@ func (m StageModel) handleCompleteChangesEvent(
 	cmds []tea.Cmd,
 ) (StageModel, []tea.Cmd) {
 	m.finished = true
+	m.streamResumer.Stop()
 	m.completeChanges = eventData.Changes
 
 	if len(m.items) == 0 && m.completeChanges != nil {
@@ -142,9 +149,42 @@ func (m StageModel) handleCompleteChangesEvent(
 	return m, cmds
 }
 
+func (m StageModel) handleChangesetLoadedMsg(msg ChangesetLoadedMsg) (StageModel, []tea.Cmd) {
+	if m.err != nil || msg.Changeset == nil {
+		return m, nil
+	}
+
+	changeset := msg.Changeset
+	m.changesetID = changeset.ID
+	m.footerRenderer.ChangesetID = changeset.ID
+	if changeset.InstanceID != "" {
+		m.SetInstanceID(changeset.InstanceID)
+	}
+	m.SetDestroy(changeset.Destroy)
+	m.blueprintFile = changeset.BlueprintLocation
+	m.instanceState = msg.InstanceState
+	m.streaming = false
+
+	// Destroy change sets are populated from the complete changes
+	// in the same way as they are at the end of a destroy staging run.
+	if !changeset.Destroy {
+		m.items = buildItemsFromChanges(changeset.Changes, msg.InstanceState)
+	}
+
+	if m.headlessMode && !m.jsonMode {
+		m.printHeadlessChangesetHeader(changeset)
+	}
+
+	return m.handleCompleteChangesEvent(
+		&types.CompleteChangesEventData{Changes: changeset.Changes},
+		nil,
+	)
+}
+
 func (m StageModel) handleDriftDetectedEvent(cmds []tea.Cmd) (StageModel, []tea.Cmd) {
 	m.driftReviewMode = true
 	m.streaming = false
+	m.streamResumer.Stop()
 
 	if m.driftResult != nil {
 		driftItems := BuildDriftItems(m.driftResult, m.instanceState)
@@ -181,6 +221,13 @@ func (m StageModel) handleStageErrorMsg(msg StageErrorMsg) (StageModel, tea.Cmd)
 		return m, nil
 	}
 
+	if m.streamResumer.CanResume() && shared.IsStreamConnectionError(msg.Err) {
+		if cmd, resuming := m.resumeStream(); resuming {
+			return m, cmd
+		}
+		msg.Err = m.streamResumer.GaveUpError(msg.Err)
+	}
+
 	m.err = msg.Err
 	m.streaming = false
 
@@ -196,14 +243,24 @@ func (m StageModel) handleStageErrorMsg(msg StageErrorMsg) (StageModel, tea.Cmd)
 	return m, nil
 }
 
-func (m StageModel) handleStageStreamClosedMsg() (StageModel, tea.Cmd) {
-	if m.finished {
+func (m StageModel) handleStageStreamClosedMsg(msg StageStreamClosedMsg) (StageModel, tea.Cmd) {
+	if m.finished || (msg.stream != nil && msg.stream != m.eventStream) {
+		// Staging has finished or the closed stream was replaced
+		// when the stream was resumed.
 		return m, nil
 	}
 
+	err := errors.New("staging event stream closed unexpectedly (connection timeout or dropped)")
+	if m.streamResumer.CanResume() {
+		if cmd, resuming := m.resumeStream(); resuming {
+			return m, cmd
+		}
+		err = m.streamResumer.GaveUpError(err)
+	}
+
 	m.finished = true
 	m.streaming = false
-	m.err = fmt.Errorf("staging event stream closed unexpectedly (connection timeout or dropped)")
+	m.err = err
 
 	if m.headlessMode {
 		if m.jsonMode {
@@ -217,6 +274,43 @@ func (m StageModel) handleStageStreamClosedMsg() (StageModel, tea.Cmd) {
 	return m, nil
 }
 
+// resumeStream schedules an attempt to resume the staging event stream after
+// the connection to the engine was lost, false is returned when the
+// reconnection budget has been used up.
+func (m *StageModel) resumeStream() (tea.Cmd, bool) {
+	if m.streamResumer.Reconnecting() {
+		// An attempt to resume the lost stream has already been scheduled.
+		return nil, true
+	}
+
+	attempt, ok := m.streamResumer.NextAttempt()
+	if !ok {
+		return nil, false
+	}
+
+	if m.headlessMode && !m.jsonMode {
+		m.printHeadlessReconnectNotice(attempt)
+	}
+	return reconnectStagingStreamCmd(attempt.Wait), true
+}
+
+func (m StageModel) handleStageStreamReconnectMsg() (StageModel, tea.Cmd) {
+	if m.finished || !m.streamResumer.CanResume() {
+		return m, nil
+	}
+
+	// Events and errors are received on new channels so that
+	// the lost stream does not interfere with the resumed stream.
+	m.eventStream = make(chan types.ChangeStagingEvent)
+	m.errStream = make(chan error)
+	return m, resumeStagingStreamCmd(m)
+}
+
+func (m StageModel) handleStageStreamResumedMsg() (StageModel, []tea.Cmd) {
+	m.streamResumer.Resumed()
+	return m, []tea.Cmd{waitForNextEventCmd(m), checkForErrCmd(m)}
+}
+
 func (m StageModel) handleReconciliationCompleteMsg() (StageModel, []tea.Cmd) {
 	m.driftReviewMode = false
 	m.driftResult = nil
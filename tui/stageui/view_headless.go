@@ -3,11 +3,13 @@ package stageui
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/newstack-cloud/bluelink/libs/blueprint/changes"
 	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
 	"github.com/newstack-cloud/bluelink/libs/blueprint/provider"
 	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/bluelink/libs/blueprint-state/manage"
 	engineerrors "github.com/newstack-cloud/bluelink/libs/deploy-engine-client/errors"
 	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
 	"github.com/newstack-cloud/deploy-cli-sdk/headless"
@@ -20,11 +22,36 @@ import (
 func (m *StageModel) printHeadlessHeader() {
 	w := m.printer.Writer()
 	w.Println("Starting change staging...")
+	if m.engineContext != "" {
+		w.Printf("Context: %s\n", m.engineContext)
+	}
 	w.Printf("Changeset: %s\n", m.changesetID)
 	w.DoubleSeparator(72)
 	w.PrintlnEmpty()
 }
 
+func (m *StageModel) printHeadlessReconnectNotice(attempt shared.StreamReconnectAttempt) {
+	m.printer.Writer().Printf("⚠ %s\n", attempt.Notice())
+}
+
+func (m *StageModel) printHeadlessChangesetHeader(changeset *manage.Changeset) {
+	w := m.printer.Writer()
+	w.Printf("Changeset: %s\n", changeset.ID)
+	if changeset.InstanceID != "" {
+		w.Printf("Instance ID: %s\n", changeset.InstanceID)
+	}
+	if changeset.BlueprintLocation != "" {
+		w.Printf("Blueprint: %s\n", changeset.BlueprintLocation)
+	}
+	if changeset.Created > 0 {
+		w.Printf("Created: %s\n", time.Unix(changeset.Created, 0).UTC().Format(time.RFC3339))
+	}
+	if changeset.Destroy {
+		w.Println("Destroy: true")
+	}
+	w.DoubleSeparator(72)
+}
+
 func (m *StageModel) printHeadlessResourceEvent(data *types.ResourceChangesEventData) {
 	action := m.determineResourceAction(data)
 	suffix := ""
@@ -5,18 +5,22 @@ import (
 )
 
 func (m *StageModel) outputJSON() {
-	summary := m.buildChangeSummary()
+	m.writeJSON(m.StageOutput())
+}
 
-	output := jsonout.StageOutput{
+// StageOutput returns the result of staging in the form used
+// for the JSON output of the stage command.
+func (m *StageModel) StageOutput() jsonout.StageOutput {
+	return jsonout.StageOutput{
 		Success:      true,
 		ChangesetID:  m.changesetID,
 		InstanceID:   m.instanceID,
 		InstanceName: m.instanceName,
+		Context:      m.engineContext,
+		Variables:    m.variables,
 		Changes:      m.completeChanges,
-		Summary:      summary,
+		Summary:      m.buildChangeSummary(),
 	}
-
-	jsonout.WriteJSON(m.headlessWriter, output)
 }
 
 func (m *StageModel) buildChangeSummary() jsonout.ChangeSummary {
@@ -95,14 +99,26 @@ func (m *StageModel) outputJSONDrift() {
 		DriftDetected:  true,
 		InstanceID:     m.instanceID,
 		InstanceName:   m.instanceName,
+		Context:        m.engineContext,
 		Message:        m.driftMessage,
 		Reconciliation: m.driftResult,
 	}
 
-	jsonout.WriteJSON(m.headlessWriter, output)
+	m.writeJSON(output)
 }
 
 func (m *StageModel) outputJSONError(err error) {
 	output := jsonout.NewErrorOutput(err)
+	output.Context = m.engineContext
+	m.writeJSON(output)
+}
+
+// writeJSON writes the final result of staging as a pretty-printed JSON
+// object, or as the last line of the NDJSON stream when streaming events.
+func (m *StageModel) writeJSON(output any) {
+	if m.ndjsonStream != nil {
+		m.ndjsonStream.WriteLine(output)
+		return
+	}
 	jsonout.WriteJSON(m.headlessWriter, output)
 }
@@ -15,10 +15,20 @@ type ExportCompleteMsg struct {
 	Err    error
 }
 
+// exportOptions holds the options from the export command
+// that control the format of the export.
+type exportOptions struct {
+	envelope    bool
+	tool        string
+	encryption  *stateio.EncryptionOptions
+	compression stateio.Compression
+}
+
 func startExportCmd(
 	engineConfig *stateio.EngineConfig,
 	filePath string,
 	instanceFilters []string,
+	options exportOptions,
 ) tea.Cmd {
 	return func() tea.Msg {
 		result, err := stateio.Export(stateio.ExportParams{
@@ -26,6 +36,10 @@ func startExportCmd(
 			InstanceFilters: instanceFilters,
 			EngineConfig:    engineConfig,
 			FileSystem:      afero.NewOsFs(),
+			Envelope:        options.envelope,
+			Tool:            options.tool,
+			Encryption:      options.encryption,
+			Compression:     options.compression,
 		})
 		return ExportCompleteMsg{Result: result, Err: err}
 	}
@@ -21,6 +21,10 @@ type ExportModelConfig struct {
 	Headless        bool
 	HeadlessWriter  io.Writer
 	JSONMode        bool
+	Envelope        bool
+	Tool            string
+	Encryption      *stateio.EncryptionOptions
+	Compression     stateio.Compression
 }
 
 // ExportModel handles the export progress display.
@@ -36,6 +40,7 @@ type ExportModel struct {
 	headless        bool
 	headlessWriter  io.Writer
 	jsonMode        bool
+	options         exportOptions
 	styles          *stylespkg.Styles
 	width           int
 }
@@ -56,6 +61,12 @@ func NewExportModel(config ExportModelConfig) *ExportModel {
 		jsonMode:        config.JSONMode,
 		styles:          config.Styles,
 		width:           80,
+		options: exportOptions{
+			envelope:    config.Envelope,
+			tool:        config.Tool,
+			encryption:  config.Encryption,
+			compression: config.Compression,
+		},
 	}
 }
 
@@ -184,5 +195,5 @@ func (m *ExportModel) IsFinished() bool {
 
 // StartExport returns a command to start the export process.
 func (m *ExportModel) StartExport() tea.Cmd {
-	return startExportCmd(m.engineConfig, m.filePath, m.instanceFilters)
+	return startExportCmd(m.engineConfig, m.filePath, m.instanceFilters, m.options)
 }
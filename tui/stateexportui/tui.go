@@ -231,6 +231,15 @@ type StateExportAppConfig struct {
 	Headless        bool
 	HeadlessWriter  io.Writer
 	JSONMode        bool
+	// Envelope writes the export in the envelope format with metadata and checksums.
+	Envelope bool
+	// Tool is the name of the CLI recorded in the envelope of the export.
+	Tool string
+	// Encryption encrypts the export with a passphrase or for recipients when set.
+	Encryption *stateio.EncryptionOptions
+	// Compression is the format the export is compressed with,
+	// determined by the file extension when not set.
+	Compression stateio.Compression
 }
 
 // NewStateExportApp creates a new state export application.
@@ -263,6 +272,10 @@ func NewStateExportApp(config StateExportAppConfig) (*MainModel, error) {
 		Headless:        config.Headless,
 		HeadlessWriter:  config.HeadlessWriter,
 		JSONMode:        config.JSONMode,
+		Envelope:        config.Envelope,
+		Tool:            config.Tool,
+		Encryption:      config.Encryption,
+		Compression:     config.Compression,
 	})
 
 	return &MainModel{
@@ -33,15 +33,29 @@ func startDownloadCmd(filePath string) tea.Cmd {
 	}
 }
 
+// importOptions holds the options from the import command
+// that control how an export is read and saved.
+type importOptions struct {
+	batchSize  int
+	decryption *stateio.DecryptionOptions
+	onConflict stateio.ConflictStrategy
+	dryRun     bool
+}
+
 func startImportCmd(
 	engineConfig *stateio.EngineConfig,
 	filePath string,
+	options importOptions,
 ) tea.Cmd {
 	return func() tea.Msg {
 		result, err := stateio.Import(stateio.ImportParams{
 			FilePath:     filePath,
 			EngineConfig: engineConfig,
 			FileSystem:   afero.NewOsFs(),
+			BatchSize:    options.batchSize,
+			Decryption:   options.decryption,
+			OnConflict:   options.onConflict,
+			DryRun:       options.dryRun,
 		})
 		return ImportCompleteMsg{Result: result, Err: err}
 	}
@@ -50,12 +64,17 @@ func startImportCmd(
 func startImportWithDataCmd(
 	engineConfig *stateio.EngineConfig,
 	data []byte,
+	options importOptions,
 ) tea.Cmd {
 	return func() tea.Msg {
 		result, err := stateio.Import(stateio.ImportParams{
 			EngineConfig: engineConfig,
 			FileSystem:   afero.NewOsFs(),
 			FileData:     data,
+			BatchSize:    options.batchSize,
+			Decryption:   options.decryption,
+			OnConflict:   options.onConflict,
+			DryRun:       options.dryRun,
 		})
 		return ImportCompleteMsg{Result: result, Err: err}
 	}
@@ -3,6 +3,7 @@ package stateimportui
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
@@ -20,6 +21,10 @@ type ImportModelConfig struct {
 	Headless       bool
 	HeadlessWriter io.Writer
 	JSONMode       bool
+	BatchSize      int
+	Decryption     *stateio.DecryptionOptions
+	OnConflict     stateio.ConflictStrategy
+	DryRun         bool
 }
 
 // ImportModel handles the import progress display.
@@ -35,6 +40,7 @@ type ImportModel struct {
 	headless       bool
 	headlessWriter io.Writer
 	jsonMode       bool
+	options        importOptions
 	styles         *stylespkg.Styles
 	width          int
 }
@@ -54,6 +60,12 @@ func NewImportModel(config ImportModelConfig) *ImportModel {
 		jsonMode:       config.JSONMode,
 		styles:         config.Styles,
 		width:          80, // Default width, will be updated on first WindowSizeMsg
+		options: importOptions{
+			batchSize:  config.BatchSize,
+			decryption: config.Decryption,
+			onConflict: config.OnConflict,
+			dryRun:     config.DryRun,
+		},
 	}
 }
 
@@ -78,7 +90,7 @@ func (m *ImportModel) Update(msg tea.Msg) (*ImportModel, tea.Cmd) {
 			return m, nil
 		}
 		m.importing = true
-		return m, startImportWithDataCmd(m.engineConfig, msg.Data)
+		return m, startImportWithDataCmd(m.engineConfig, msg.Data, m.options)
 	case ImportStartedMsg:
 		m.importing = true
 		return m, nil
@@ -105,6 +117,10 @@ func (m *ImportModel) View() string {
 		return fmt.Sprintf("\n  %s Downloading from %s...\n", m.spinner.View(), m.filePath)
 	}
 
+	if m.importing && m.options.dryRun {
+		return fmt.Sprintf("\n  %s Comparing import with state...\n", m.spinner.View())
+	}
+
 	if m.importing {
 		return fmt.Sprintf("\n  %s Importing state...\n", m.spinner.View())
 	}
@@ -139,12 +155,125 @@ func (m *ImportModel) renderResult() string {
 		return "\n  Import completed with no result.\n\n  Press q to quit\n"
 	}
 
-	return fmt.Sprintf("\n  %s Import complete\n\n    Instances imported: %d\n\n  Press q to quit\n",
+	if m.result.DryRun {
+		return fmt.Sprintf("\n  %s Dry run complete, nothing was saved\n\n    Instances in import: %d\n%s\n  Press q to quit\n",
+			m.styles.Success.Render("✓"),
+			m.result.InstancesCount,
+			m.renderInstanceResults(),
+		)
+	}
+
+	return fmt.Sprintf("\n  %s Import complete\n\n    Instances imported: %d\n%s\n  Press q to quit\n",
 		m.styles.Success.Render("✓"),
 		m.result.InstancesCount,
+		m.renderInstanceResults(),
 	)
 }
 
+// maxRenderedInstanceResults is the maximum number of instance results
+// shown in the result view, headless output includes every instance.
+const maxRenderedInstanceResults = 20
+
+func (m *ImportModel) renderInstanceResults() string {
+	if m.result.Summary == nil {
+		return ""
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("    %s\n\n", formatImportSummary(m.result.Summary)))
+	for i, result := range m.result.Instances {
+		if i == maxRenderedInstanceResults {
+			sb.WriteString(m.styles.Muted.Render(fmt.Sprintf(
+				"    ... and %d more instances\n",
+				len(m.result.Instances)-maxRenderedInstanceResults,
+			)))
+			break
+		}
+
+		lines := formatInstanceResult(result)
+		sb.WriteString(fmt.Sprintf("    %s %s\n", m.renderStatusIcon(result.Status), lines[0]))
+		for _, line := range lines[1:] {
+			sb.WriteString(m.styles.Muted.Render("        "+line) + "\n")
+		}
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+func (m *ImportModel) renderStatusIcon(status stateio.InstanceImportStatus) string {
+	switch status {
+	case stateio.InstanceImportNew:
+		return m.styles.Success.Render("+")
+	case stateio.InstanceImportDiffers:
+		return m.styles.Warning.Render("~")
+	default:
+		return m.styles.Muted.Render("=")
+	}
+}
+
+func formatImportSummary(summary *stateio.ImportSummary) string {
+	formatted := fmt.Sprintf(
+		"Created: %d  Overwritten: %d  Skipped: %d  Unchanged: %d",
+		summary.Created,
+		summary.Overwritten,
+		summary.Skipped,
+		summary.Unchanged,
+	)
+	if summary.Conflicts > 0 {
+		formatted += fmt.Sprintf("  Conflicts: %d", summary.Conflicts)
+	}
+	return formatted
+}
+
+// formatInstanceResult formats the result for an instance as a line
+// with the status and action followed by a line for each kind of difference.
+func formatInstanceResult(result stateio.InstanceImportResult) []string {
+	lines := []string{fmt.Sprintf(
+		"%s (%s): %s, %s",
+		result.InstanceName,
+		result.InstanceID,
+		result.Status,
+		result.Action,
+	)}
+
+	differences := result.Differences
+	if differences == nil {
+		return lines
+	}
+
+	for _, element := range []struct {
+		name        string
+		differences stateio.ElementDifferences
+	}{
+		{name: "resources", differences: differences.Resources},
+		{name: "links", differences: differences.Links},
+		{name: "children", differences: differences.Children},
+	} {
+		if !element.differences.IsEmpty() {
+			lines = append(lines, fmt.Sprintf("%s: %s", element.name, formatElementDifferences(element.differences)))
+		}
+	}
+
+	if len(differences.Fields) > 0 {
+		lines = append(lines, fmt.Sprintf("fields: %s", strings.Join(differences.Fields, ", ")))
+	}
+	return lines
+}
+
+func formatElementDifferences(differences stateio.ElementDifferences) string {
+	names := []string{}
+	for _, name := range differences.Added {
+		names = append(names, "+"+name)
+	}
+	for _, name := range differences.Removed {
+		names = append(names, "-"+name)
+	}
+	for _, name := range differences.Changed {
+		names = append(names, "~"+name)
+	}
+	return strings.Join(names, " ")
+}
+
 func (m *ImportModel) writeHeadlessOutput() {
 	if m.headlessWriter == nil {
 		return
@@ -170,6 +299,9 @@ func (m *ImportModel) writeJSONOutput() {
 			Mode:           "import",
 			InstancesCount: m.result.InstancesCount,
 			Message:        m.result.Message,
+			DryRun:         m.result.DryRun,
+			Summary:        m.result.Summary,
+			Instances:      m.result.Instances,
 		}
 		jsonout.WriteJSON(m.headlessWriter, output)
 	}
@@ -183,6 +315,13 @@ func (m *ImportModel) writeTextOutput() {
 
 	if m.result != nil {
 		fmt.Fprintf(m.headlessWriter, "%s\n", m.result.Message)
+		for _, result := range m.result.Instances {
+			lines := formatInstanceResult(result)
+			fmt.Fprintf(m.headlessWriter, "  %s\n", lines[0])
+			for _, line := range lines[1:] {
+				fmt.Fprintf(m.headlessWriter, "    %s\n", line)
+			}
+		}
 	}
 }
 
@@ -206,5 +345,5 @@ func (m *ImportModel) StartImport() tea.Cmd {
 	if stateio.IsRemoteFile(m.filePath) {
 		return startDownloadCmd(m.filePath)
 	}
-	return startImportCmd(m.engineConfig, m.filePath)
+	return startImportCmd(m.engineConfig, m.filePath, m.options)
 }
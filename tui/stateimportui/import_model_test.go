@@ -144,6 +144,106 @@ func (s *ImportModelSuite) Test_renderResult_with_result_shows_instance_count()
 	s.Contains(view, "5")
 }
 
+func (s *ImportModelSuite) Test_renderResult_with_dry_run_shows_instance_differences() {
+	m := s.newImportModel(s.testFile)
+	m.finished = true
+	m.result = dryRunImportResult()
+
+	view := m.renderResult()
+	s.Contains(view, "Dry run complete, nothing was saved")
+	s.Contains(view, "Created: 1")
+	s.Contains(view, "orders (inst-1): differs, overwrite")
+	s.Contains(view, "resources: +queue -bucket ~table")
+	s.Contains(view, "search (inst-2): new, create")
+}
+
+func (s *ImportModelSuite) Test_headless_dry_run_json_output_includes_instance_results() {
+	var buf bytes.Buffer
+	m := NewImportModel(ImportModelConfig{
+		EngineConfig:   s.engineConfig,
+		FilePath:       s.testFile,
+		Styles:         s.styles,
+		Headless:       true,
+		HeadlessWriter: &buf,
+		JSONMode:       true,
+		DryRun:         true,
+	})
+
+	m.Update(ImportCompleteMsg{Result: dryRunImportResult()})
+
+	output := buf.String()
+	s.Contains(output, `"dryRun": true`)
+	s.Contains(output, `"overwritten": 1`)
+	s.Contains(output, `"status": "differs"`)
+	s.Contains(output, `"queue"`)
+}
+
+func (s *ImportModelSuite) Test_headless_dry_run_text_output_includes_instance_results() {
+	var buf bytes.Buffer
+	m := NewImportModel(ImportModelConfig{
+		EngineConfig:   s.engineConfig,
+		FilePath:       s.testFile,
+		Styles:         s.styles,
+		Headless:       true,
+		HeadlessWriter: &buf,
+		DryRun:         true,
+	})
+
+	m.Update(ImportCompleteMsg{Result: dryRunImportResult()})
+
+	output := buf.String()
+	s.Contains(output, "Dry run")
+	s.Contains(output, "orders (inst-1): differs, overwrite")
+	s.Contains(output, "fields: status")
+}
+
+func (s *ImportModelSuite) Test_startImportCmd_dry_run_does_not_save_instances() {
+	cmd := startImportCmd(s.engineConfig, s.testFile, importOptions{dryRun: true})
+	msg := cmd()
+
+	completeMsg, ok := msg.(ImportCompleteMsg)
+	s.Require().True(ok)
+	s.Require().NoError(completeMsg.Err)
+	s.True(completeMsg.Result.DryRun)
+	s.Equal(&stateio.ImportSummary{Created: 1}, completeMsg.Result.Summary)
+
+	entries, err := os.ReadDir(s.stateDir)
+	s.Require().NoError(err)
+	s.Empty(entries)
+}
+
+func dryRunImportResult() *stateio.ImportResult {
+	return &stateio.ImportResult{
+		Success:        true,
+		InstancesCount: 2,
+		Message:        "Dry run: 2 instances would be imported",
+		DryRun:         true,
+		Summary:        &stateio.ImportSummary{Created: 1, Overwritten: 1},
+		Instances: []stateio.InstanceImportResult{
+			{
+				InstanceID:   "inst-1",
+				InstanceName: "orders",
+				Status:       stateio.InstanceImportDiffers,
+				Action:       stateio.InstanceImportOverwrite,
+				Differences: &stateio.InstanceDifferences{
+					Resources: stateio.ElementDifferences{
+						Added:   []string{"queue"},
+						Removed: []string{"bucket"},
+						Changed: []string{"table"},
+					},
+					Fields: []string{"status"},
+				},
+			},
+			{
+				InstanceID:   "inst-2",
+				InstanceName: "search",
+				Status:       stateio.InstanceImportNew,
+				Action:       stateio.InstanceImportCreate,
+			},
+		},
+	}
+}
+
 func (s *ImportModelSuite) Test_View_returns_empty_in_headless_mode() {
 	m := NewImportModel(ImportModelConfig{
 		EngineConfig: s.engineConfig,
@@ -237,7 +337,7 @@ func (s *ImportModelSuite) Test_StartImport_with_gcs_path_returns_download_cmd()
 
 func (s *ImportModelSuite) Test_startImportWithDataCmd_imports_from_memory() {
 	data := []byte(`[{"id":"inst-1","name":"Test","status":2}]`)
-	cmd := startImportWithDataCmd(s.engineConfig, data)
+	cmd := startImportWithDataCmd(s.engineConfig, data, importOptions{})
 
 	msg := cmd()
 	completeMsg, ok := msg.(ImportCompleteMsg)
@@ -248,7 +348,7 @@ func (s *ImportModelSuite) Test_startImportWithDataCmd_imports_from_memory() {
 }
 
 func (s *ImportModelSuite) Test_startImportWithDataCmd_with_invalid_data_returns_error() {
-	cmd := startImportWithDataCmd(s.engineConfig, []byte("not-valid-json"))
+	cmd := startImportWithDataCmd(s.engineConfig, []byte("not-valid-json"), importOptions{})
 
 	msg := cmd()
 	completeMsg, ok := msg.(ImportCompleteMsg)
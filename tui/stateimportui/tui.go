@@ -232,6 +232,18 @@ type StateImportAppConfig struct {
 	Headless       bool
 	HeadlessWriter io.Writer
 	JSONMode       bool
+	// BatchSize is the number of instances to save at a time,
+	// stateio.DefaultImportBatchSize is used when not set.
+	BatchSize int
+	// Decryption holds the passphrase or identities used to decrypt
+	// encrypted exports.
+	Decryption *stateio.DecryptionOptions
+	// OnConflict determines how instances that already exist with
+	// a different state are handled, instances are saved without
+	// being compared with the state when not set.
+	OnConflict stateio.ConflictStrategy
+	// DryRun reports what the import would do without saving anything.
+	DryRun bool
 }
 
 // NewStateImportApp creates a new state import application.
@@ -263,6 +275,10 @@ func NewStateImportApp(config StateImportAppConfig) (*MainModel, error) {
 		Headless:       config.Headless,
 		HeadlessWriter: config.HeadlessWriter,
 		JSONMode:       config.JSONMode,
+		BatchSize:      config.BatchSize,
+		Decryption:     config.Decryption,
+		OnConflict:     config.OnConflict,
+		DryRun:         config.DryRun,
 	})
 
 	return &MainModel{
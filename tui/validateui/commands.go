@@ -39,7 +39,32 @@ func startValidateStreamCmd(model ValidateModel, logger *zap.Logger) tea.Cmd {
 		if err != nil {
 			return ValidateErrMsg{err}
 		}
-		return nil
+		return ValidateStartedMsg{
+			validationID: response.Data.ID,
+			lastEventID:  response.LastEventID,
+		}
+	}
+}
+
+func reconnectValidateStreamCmd(wait time.Duration) tea.Cmd {
+	return tea.Tick(wait, func(time.Time) tea.Msg {
+		return ValidateStreamReconnectMsg{}
+	})
+}
+
+func resumeValidateStreamCmd(model ValidateModel) tea.Cmd {
+	return func() tea.Msg {
+		err := model.engine.StreamBlueprintValidationEvents(
+			model.reqCtx(),
+			model.streamResumer.StreamID(),
+			model.streamResumer.LastEventID(),
+			model.resultStream,
+			model.errStream,
+		)
+		if err != nil {
+			return ValidateErrMsg{err}
+		}
+		return ValidateStreamResumedMsg{}
 	}
 }
 
@@ -147,7 +172,10 @@ func buildLoaderConfig(model ValidateModel) *types.ValidationLoaderConfig {
 
 func waitForNextResultCmd(model ValidateModel) tea.Cmd {
 	return func() tea.Msg {
-		event := <-model.resultStream
+		event, ok := <-model.resultStream
+		if !ok {
+			return ValidateStreamClosedMsg{stream: model.resultStream}
+		}
 		return ValidateResultMsg(&event)
 	}
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
 	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/shared"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -40,3 +41,35 @@ func TestCreateValidationPayload_includesOperationConfig(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Same(t, opConfig, httpsPayload.Config)
 }
+
+// A closed result stream must be reported instead of being read as an
+// endless series of empty validation events.
+func TestWaitForNextResultCmd_reportsClosedStream(t *testing.T) {
+	model := ValidateModel{
+		resultStream: make(chan types.BlueprintValidationEvent),
+	}
+	close(model.resultStream)
+
+	msg := waitForNextResultCmd(model)()
+	assert.Equal(t, ValidateStreamClosedMsg{stream: model.resultStream}, msg)
+}
+
+func TestValidateModel_resumesClosedStreamAfterStart(t *testing.T) {
+	model := ValidateModel{
+		resultStream:  make(chan types.BlueprintValidationEvent),
+		errStream:     make(chan error),
+		streamResumer: shared.NewStreamResumer(shared.StreamReconnectPolicy{MaxAttempts: 1}),
+	}
+
+	updated, _ := model.Update(ValidateStartedMsg{validationID: "validation-1", lastEventID: "event-1"})
+	updated, cmd := updated.Update(ValidateStreamClosedMsg{stream: updated.(ValidateModel).resultStream})
+	validateModel := updated.(ValidateModel)
+	assert.NoError(t, validateModel.err)
+	assert.NotNil(t, cmd)
+	assert.True(t, validateModel.streamResumer.Reconnecting())
+
+	updated, _ = validateModel.Update(ValidateStreamResumedMsg{})
+	updated, _ = updated.Update(ValidateStreamClosedMsg{stream: updated.(ValidateModel).resultStream})
+	validateModel = updated.(ValidateModel)
+	assert.ErrorContains(t, validateModel.err, "gave up reconnecting to the event stream after 1 attempt(s)")
+}
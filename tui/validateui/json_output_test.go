@@ -0,0 +1,93 @@
+package validateui
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
+	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
+	"github.com/newstack-cloud/deploy-cli-sdk/testutils"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+type JSONOutputTestSuite struct {
+	suite.Suite
+}
+
+func TestJSONOutputTestSuite(t *testing.T) {
+	suite.Run(t, new(JSONOutputTestSuite))
+}
+
+func (s *JSONOutputTestSuite) Test_outputJSON_for_successful_validation() {
+	jsonOutput := &bytes.Buffer{}
+	mainModel, err := NewValidateApp(ValidateAppConfig{
+		Engine:         testutils.NewTestDeployEngine(testValidationEvents(validationSuccess)),
+		Logger:         zap.NewNop(),
+		BlueprintFile:  "test.blueprint.yaml",
+		Styles:         stylespkg.NewStyles(lipgloss.NewRenderer(os.Stdout), stylespkg.NewBluelinkPalette()),
+		Headless:       true,
+		HeadlessWriter: jsonOutput,
+		JSONMode:       true,
+	})
+	s.Require().NoError(err)
+
+	testModel := teatest.NewTestModel(
+		s.T(),
+		mainModel,
+		teatest.WithInitialTermSize(300, 100),
+	)
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+
+	finalModel := testModel.FinalModel(s.T()).(MainModel)
+	s.Nil(finalModel.Error)
+
+	var output jsonout.ValidateOutput
+	s.Require().NoError(json.Unmarshal(jsonOutput.Bytes(), &output))
+	s.True(output.Success)
+	s.Contains(output.BlueprintFile, "test.blueprint.yaml")
+	s.Len(output.Diagnostics, 2)
+	s.Equal(jsonout.ValidateSummary{Errors: 0, Warnings: 1, Info: 1}, output.Summary)
+}
+
+func (s *JSONOutputTestSuite) Test_outputJSON_for_failed_validation_includes_suggested_actions() {
+	jsonOutput := &bytes.Buffer{}
+	mainModel, err := NewValidateApp(ValidateAppConfig{
+		Engine:         testutils.NewTestDeployEngine(testValidationEvents(validationFailed)),
+		Logger:         zap.NewNop(),
+		BlueprintFile:  "test.blueprint.yaml",
+		Styles:         stylespkg.NewStyles(lipgloss.NewRenderer(os.Stdout), stylespkg.NewBluelinkPalette()),
+		Headless:       true,
+		HeadlessWriter: jsonOutput,
+		JSONMode:       true,
+	})
+	s.Require().NoError(err)
+
+	testModel := teatest.NewTestModel(
+		s.T(),
+		mainModel,
+		teatest.WithInitialTermSize(300, 100),
+	)
+	testModel.WaitFinished(s.T(), teatest.WithFinalTimeout(5*time.Second))
+
+	finalModel := testModel.FinalModel(s.T()).(MainModel)
+	s.ErrorIs(finalModel.Error, ErrValidationFailed)
+
+	var output jsonout.ValidateOutput
+	s.Require().NoError(json.Unmarshal(jsonOutput.Bytes(), &output))
+	s.False(output.Success)
+	s.Equal(1, output.Summary.Errors)
+	s.Require().Len(output.Diagnostics, 3)
+
+	errorDiagnostic := output.Diagnostics[2]
+	s.Equal("ERROR", errorDiagnostic.Level)
+	s.Require().Len(errorDiagnostic.SuggestedActions, 1)
+	s.Equal("Install provider", errorDiagnostic.SuggestedActions[0].Title)
+	s.Require().NotEmpty(errorDiagnostic.SuggestedActions[0].Links)
+	s.Equal("https://registry.bluelink.dev/providers", errorDiagnostic.SuggestedActions[0].Links[0].URL)
+}
@@ -9,8 +9,10 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
 	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
 	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
 	"github.com/newstack-cloud/deploy-cli-sdk/tui/preflight"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/shared"
 	sharedui "github.com/newstack-cloud/deploy-cli-sdk/ui"
 	"go.uber.org/zap"
 )
@@ -30,6 +32,11 @@ const (
 	ValidateStageSourceCode
 )
 
+// ErrValidationFailed is set as the MainModel error when validation completes
+// with one or more error diagnostics, allowing callers to distinguish
+// a failed validation from a failure to carry out the validation.
+var ErrValidationFailed = errors.New("validation failed")
+
 type validateSessionState uint32
 
 const (
@@ -149,7 +156,7 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.Error = validateModel.err
 		}
 		if validateModel.validationFailed {
-			m.Error = errors.New("validation failed")
+			m.Error = ErrValidationFailed
 		}
 	}
 	return m, tea.Batch(cmds...)
@@ -234,7 +241,11 @@ type ValidateAppConfig struct {
 	Styles                 *stylespkg.Styles
 	Headless               bool
 	HeadlessWriter         io.Writer
-	Preflight              tea.Model
+	// JSONMode, when true, writes the validation result as a single
+	// jsonout.ValidateOutput object to HeadlessWriter instead of streaming
+	// rendered diagnostics. Implies Headless.
+	JSONMode  bool
+	Preflight tea.Model
 	// TransformSpec, when non-nil, sets the validation loader's transform-spec
 	// option directly and skips the interactive options form. When nil, the
 	// SDK default of true is used and (in interactive mode) the form is shown.
@@ -244,10 +255,16 @@ type ValidateAppConfig struct {
 	// options form. When nil, the SDK default of false is used and (in
 	// interactive mode) the form is shown.
 	ValidateAfterTransform *bool
+	// Variables are the blueprint and context variable values provided
+	// on the command line, included in JSON output.
+	Variables []jsonout.VariableValue
 	// OperationConfig carries provider/transformer/context-variable values
 	// (including the deploy target) sent to the engine in the validation
 	// payload, so transformer plugins run correctly during validation.
 	OperationConfig *types.BlueprintOperationConfig
+	// StreamReconnect determines how event streams are resumed
+	// when the connection to the deploy engine drops.
+	StreamReconnect shared.StreamReconnectPolicy
 }
 
 func NewValidateApp(cfg ValidateAppConfig) (*MainModel, error) {
@@ -292,10 +309,13 @@ func NewValidateApp(cfg ValidateAppConfig) (*MainModel, error) {
 		Logger:                 cfg.Logger,
 		Headless:               cfg.Headless,
 		HeadlessWriter:         cfg.HeadlessWriter,
+		JSONMode:               cfg.JSONMode,
 		Styles:                 cfg.Styles,
 		TransformSpec:          transformSpec,
 		ValidateAfterTransform: validateAfterTransform,
+		Variables:              cfg.Variables,
 		OperationConfig:        cfg.OperationConfig,
+		StreamReconnect:        cfg.StreamReconnect,
 	})
 
 	var optionsForm *ValidateOptionsFormModel
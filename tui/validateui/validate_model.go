@@ -16,7 +16,9 @@ import (
 	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
 	"github.com/newstack-cloud/deploy-cli-sdk/diagutils"
 	"github.com/newstack-cloud/deploy-cli-sdk/engine"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
 	stylespkg "github.com/newstack-cloud/deploy-cli-sdk/styles"
+	"github.com/newstack-cloud/deploy-cli-sdk/tui/shared"
 	sharedui "github.com/newstack-cloud/deploy-cli-sdk/ui"
 	"go.uber.org/zap"
 )
@@ -34,6 +36,27 @@ type ValidateErrMsg struct {
 
 type ValidateStreamMsg struct{}
 
+// ValidateStartedMsg is sent when the validation has been created
+// and the event stream has been started.
+type ValidateStartedMsg struct {
+	validationID string
+	lastEventID  string
+}
+
+// ValidateStreamClosedMsg is sent when the validation event stream is closed
+// before the end of the validation was received.
+type ValidateStreamClosedMsg struct {
+	stream chan types.BlueprintValidationEvent
+}
+
+// ValidateStreamReconnectMsg is sent when it is time to resume the validation
+// event stream after the connection to the deploy engine was lost.
+type ValidateStreamReconnectMsg struct{}
+
+// ValidateStreamResumedMsg is sent when the validation event stream has been
+// resumed from the last received event.
+type ValidateStreamResumedMsg struct{}
+
 type ValidateModel struct {
 	spinner         spinner.Model
 	viewport        viewport.Model
@@ -45,6 +68,7 @@ type ValidateModel struct {
 	resultStream    chan types.BlueprintValidationEvent
 	collected       []*types.BlueprintValidationEvent
 	errStream       chan error
+	streamResumer   shared.StreamResumer
 	streaming       bool
 	err             error
 	width           int
@@ -59,9 +83,12 @@ type ValidateModel struct {
 	renderer               *glamour.TermRenderer
 	headless               bool
 	headlessWriter         io.Writer
+	jsonMode               bool
 	styles                 *stylespkg.Styles
 	transformSpec          bool
 	validateAfterTransform bool
+	// variables are the variable values provided on the command line.
+	variables []jsonout.VariableValue
 	// operationConfig carries provider/transformer/context-variable values
 	// (including the deploy target) sent to the engine in the validation payload.
 	operationConfig *types.BlueprintOperationConfig
@@ -104,7 +131,17 @@ func (m ValidateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, startValidateStreamCmd(m, m.logger), waitForNextResultCmd(m), checkForErrCmd(m))
 		}
 		m.streaming = true
+	case ValidateStartedMsg:
+		m.streamResumer.Start(msg.validationID, msg.lastEventID)
+	case ValidateStreamClosedMsg:
+		return m.handleStreamClosed(msg)
+	case ValidateStreamReconnectMsg:
+		return m.handleStreamReconnect()
+	case ValidateStreamResumedMsg:
+		m.streamResumer.Resumed()
+		cmds = append(cmds, waitForNextResultCmd(m), checkForErrCmd(m))
 	case ValidateResultMsg:
+		m.streamResumer.RecordEvent(msg.ID)
 		// The engine may send a terminal marker (End set, no diagnostic content)
 		// purely to close the stream. For example, when a validation produced no
 		// diagnostics. Collect only real diagnostics so the marker is not
@@ -117,8 +154,12 @@ func (m ValidateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, waitForNextResultCmd(m))
 		} else {
 			m.finished = true
+			m.streamResumer.Stop()
 			m.validationFailed = checkForValidationFailure(m.collected)
 			m.viewport.SetContent(m.resultContents())
+			if m.jsonMode {
+				m.outputJSON()
+			}
 			if m.headless {
 				// Make sure we exit after validation completes in headless mode.
 				cmds = append(cmds, tea.Quit)
@@ -130,8 +171,7 @@ func (m ValidateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	case ValidateErrMsg:
 		if msg.err != nil {
-			m.err = msg.err
-			return m, tea.Quit
+			return m.handleErr(msg.err)
 		}
 	}
 
@@ -142,6 +182,70 @@ func (m ValidateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+func (m ValidateModel) handleErr(err error) (tea.Model, tea.Cmd) {
+	if m.streamResumer.CanResume() && shared.IsStreamConnectionError(err) {
+		if cmd, resuming := m.resumeStream(); resuming {
+			return m, cmd
+		}
+		err = m.streamResumer.GaveUpError(err)
+	}
+
+	m.err = err
+	if m.jsonMode {
+		m.outputJSONError(err)
+	}
+	return m, tea.Quit
+}
+
+func (m ValidateModel) handleStreamClosed(msg ValidateStreamClosedMsg) (tea.Model, tea.Cmd) {
+	if m.finished || msg.stream != m.resultStream {
+		// Validation has finished or the closed stream was replaced
+		// when the stream was resumed.
+		return m, nil
+	}
+
+	err := fmt.Errorf("validation event stream closed unexpectedly (connection timeout or dropped)")
+	if m.streamResumer.CanResume() {
+		if cmd, resuming := m.resumeStream(); resuming {
+			return m, cmd
+		}
+		err = m.streamResumer.GaveUpError(err)
+	}
+	return m.handleErr(err)
+}
+
+// resumeStream schedules an attempt to resume the validation event stream
+// after the connection to the engine was lost, false is returned when the
+// reconnection budget has been used up.
+func (m *ValidateModel) resumeStream() (tea.Cmd, bool) {
+	if m.streamResumer.Reconnecting() {
+		// An attempt to resume the lost stream has already been scheduled.
+		return nil, true
+	}
+
+	attempt, ok := m.streamResumer.NextAttempt()
+	if !ok {
+		return nil, false
+	}
+
+	if m.headless && !m.jsonMode {
+		fmt.Fprintf(m.headlessWriter, "⚠ %s\n", attempt.Notice())
+	}
+	return reconnectValidateStreamCmd(attempt.Wait), true
+}
+
+func (m ValidateModel) handleStreamReconnect() (tea.Model, tea.Cmd) {
+	if m.finished || !m.streamResumer.CanResume() {
+		return m, nil
+	}
+
+	// Events and errors are received on new channels so that
+	// the lost stream does not interfere with the resumed stream.
+	m.resultStream = make(chan types.BlueprintValidationEvent)
+	m.errStream = make(chan error)
+	return m, resumeValidateStreamCmd(m)
+}
+
 func (m ValidateModel) footerView() string {
 	hints := m.styles.Muted.Render(" ↑/↓ scroll • q quit ")
 	b := lipgloss.RoundedBorder()
@@ -153,6 +257,12 @@ func (m ValidateModel) footerView() string {
 }
 
 func (m ValidateModel) View() string {
+	if m.jsonMode {
+		// In JSON mode, the result is written to the headless writer as a single
+		// JSON object once validation completes, see outputJSON.
+		return ""
+	}
+
 	if m.headless {
 		// In headless mode, print directly to configured writer and return empty string
 		m.renderHeadless()
@@ -164,6 +274,13 @@ func (m ValidateModel) View() string {
 	}
 
 	if !m.finished {
+		if m.streamResumer.Reconnecting() {
+			sb := strings.Builder{}
+			fmt.Fprintf(&sb, "\n\n %s Validating project...\n", m.spinner.View())
+			shared.RenderReconnectingNotice(&sb, m.streamResumer.Attempt(), m.styles)
+			sb.WriteString("\n")
+			return sb.String()
+		}
 		return fmt.Sprintf("\n\n %s Validating project...\n\n", m.spinner.View())
 	}
 
@@ -466,12 +583,19 @@ type ValidateModelConfig struct {
 	Logger                 *zap.Logger
 	Headless               bool
 	HeadlessWriter         io.Writer
+	JSONMode               bool
 	Styles                 *stylespkg.Styles
 	TransformSpec          bool
 	ValidateAfterTransform bool
+	// Variables are the blueprint and context variable values provided
+	// on the command line, included in JSON output.
+	Variables []jsonout.VariableValue
 	// OperationConfig carries provider/transformer/context-variable values
 	// (including the deploy target) sent to the engine in the validation payload.
 	OperationConfig *types.BlueprintOperationConfig
+	// StreamReconnect determines how the validation event stream is resumed
+	// when the connection to the deploy engine drops.
+	StreamReconnect shared.StreamReconnectPolicy
 }
 
 // Returns the model's bound context, defaulting to context.Background()
@@ -498,12 +622,15 @@ func NewValidateModel(cfg ValidateModelConfig) ValidateModel {
 		logger:                 cfg.Logger,
 		resultStream:           make(chan types.BlueprintValidationEvent),
 		errStream:              make(chan error),
+		streamResumer:          shared.NewStreamResumer(cfg.StreamReconnect),
 		renderer:               renderer,
 		headless:               cfg.Headless,
 		headlessWriter:         cfg.HeadlessWriter,
+		jsonMode:               cfg.JSONMode,
 		styles:                 cfg.Styles,
 		transformSpec:          cfg.TransformSpec,
 		validateAfterTransform: cfg.ValidateAfterTransform,
+		variables:              cfg.Variables,
 		operationConfig:        cfg.OperationConfig,
 	}
 }
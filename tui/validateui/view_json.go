@@ -0,0 +1,22 @@
+package validateui
+
+import (
+	bpcore "github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/deploy-cli-sdk/jsonout"
+)
+
+func (m *ValidateModel) outputJSON() {
+	diagnostics := make([]*bpcore.Diagnostic, 0, len(m.collected))
+	for _, result := range m.collected {
+		diagnostics = append(diagnostics, &result.Diagnostic)
+	}
+
+	output := jsonout.NewValidateOutput(m.blueprintFile, diagnostics)
+	output.Variables = m.variables
+	jsonout.WriteJSON(m.headlessWriter, output)
+}
+
+func (m *ValidateModel) outputJSONError(err error) {
+	output := jsonout.NewErrorOutput(err)
+	jsonout.WriteJSON(m.headlessWriter, output)
+}
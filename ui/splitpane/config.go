@@ -11,6 +11,10 @@ type Config struct {
 	// Optional: title shown in the left pane header (default: none)
 	Title string
 
+	// Optional: muted line shown below the title (default: none),
+	// e.g. the engine context an operation is running against
+	Subtitle string
+
 	// Optional with defaults
 	LeftPaneRatio  float64        // Default: 0.4
 	MaxExpandDepth int            // Default: 2
@@ -915,6 +915,28 @@ func (s *SplitPaneSuite) Test_public_getter_methods() {
 	s.Empty(model.NavigationStack())
 }
 
+func (s *SplitPaneSuite) Test_renders_subtitle_below_title() {
+	config := Config{
+		Styles:          s.styles,
+		DetailsRenderer: &mockDetailsRenderer{},
+		Title:           "Deployment",
+		Subtitle:        "Context: prod",
+	}
+	model := New(config)
+	model.SetItems(createTestItems())
+
+	testModel := teatest.NewTestModel(
+		s.T(),
+		testableModel{Model: model},
+		teatest.WithInitialTermSize(120, 40),
+	)
+
+	testutils.WaitForContainsAll(s.T(), testModel.Output(), "Deployment", "Context: prod")
+
+	err := testModel.Quit()
+	s.NoError(err)
+}
+
 func (s *SplitPaneSuite) Test_enter_on_non_expandable_non_drillable_item() {
 	items := []Item{
 		&mockItem{id: "1", name: "Plain Item", icon: "●", expandable: false, canDrill: false},
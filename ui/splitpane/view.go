@@ -112,6 +112,10 @@ func (m Model) renderDefaultHeader() string {
 	} else if m.config.Title != "" {
 		sb.WriteString(headerStyle.Render(m.config.Title))
 		sb.WriteString("\n")
+		if m.config.Subtitle != "" {
+			sb.WriteString(m.config.Styles.Muted.Render(m.config.Subtitle))
+			sb.WriteString("\n")
+		}
 	}
 	sb.WriteString("\n")
 